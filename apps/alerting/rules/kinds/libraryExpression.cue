@@ -0,0 +1,25 @@
+package kinds
+
+import (
+	"github.com/grafana/grafana/apps/alerting/rules/kinds/v0alpha1"
+)
+
+libraryExpressionKind: {
+	kind:       "LibraryExpression"
+	pluralName: "LibraryExpressions"
+}
+
+libraryExpressionv0alpha1: libraryExpressionKind & {
+	schema: {
+		spec: v0alpha1.LibraryExpressionSpec
+	}
+	// The graph is validated when it is expanded by the library expression
+	// command, so only the references between its parts are checked here.
+	validation: {
+		operations: [
+			"CREATE",
+			"UPDATE",
+		]
+	}
+	selectableFields: []
+}
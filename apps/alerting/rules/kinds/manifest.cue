@@ -13,6 +13,7 @@ manifest: {
 				alertRulev0alpha1,
 				recordingRulev0alpha1,
 				ruleSequencev0alpha1,
+				libraryExpressionv0alpha1,
 			]
 			routes: searchRoutes
 		}
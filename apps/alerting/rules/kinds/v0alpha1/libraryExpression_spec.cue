@@ -0,0 +1,29 @@
+package v0alpha1
+
+LibraryExpressionSpec: {
+	title:        string
+	description?: string
+	// parameters are the inputs of the graph. Each parameter is bound to a query or
+	// expression of the caller and is referenced by name in the expressions.
+	parameters: [...#Parameter]
+	// expressions is the graph of server side expressions, keyed by refId.
+	expressions: #GraphNodeMap
+	// output is the refId of the expression whose result is returned to the caller.
+	output: string
+}
+
+#Parameter: {
+	name:         #ParameterName
+	description?: string
+}
+
+#ParameterName: string & =~"^[a-zA-Z_][a-zA-Z0-9_]*$"
+
+#GraphNodeMap: {
+	[string]: #GraphNode
+}
+
+#GraphNode: {
+	// The model of the server side expression, as used in the queries of a rule
+	model: _
+}
@@ -0,0 +1,99 @@
+package v0alpha1
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-app-sdk/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type LibraryExpressionClient struct {
+	client *resource.TypedClient[*LibraryExpression, *LibraryExpressionList]
+}
+
+func NewLibraryExpressionClient(client resource.Client) *LibraryExpressionClient {
+	return &LibraryExpressionClient{
+		client: resource.NewTypedClient[*LibraryExpression, *LibraryExpressionList](client, LibraryExpressionKind()),
+	}
+}
+
+func NewLibraryExpressionClientFromGenerator(generator resource.ClientGenerator) (*LibraryExpressionClient, error) {
+	c, err := generator.ClientFor(LibraryExpressionKind())
+	if err != nil {
+		return nil, err
+	}
+	return NewLibraryExpressionClient(c), nil
+}
+
+func (c *LibraryExpressionClient) Get(ctx context.Context, identifier resource.Identifier) (*LibraryExpression, error) {
+	return c.client.Get(ctx, identifier)
+}
+
+func (c *LibraryExpressionClient) List(ctx context.Context, namespace string, opts resource.ListOptions) (*LibraryExpressionList, error) {
+	return c.client.List(ctx, namespace, opts)
+}
+
+func (c *LibraryExpressionClient) ListAll(ctx context.Context, namespace string, opts resource.ListOptions) (*LibraryExpressionList, error) {
+	resp, err := c.client.List(ctx, namespace, resource.ListOptions{
+		ResourceVersion: opts.ResourceVersion,
+		Limit:           opts.Limit,
+		LabelFilters:    opts.LabelFilters,
+		FieldSelectors:  opts.FieldSelectors,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for resp.GetContinue() != "" {
+		page, err := c.client.List(ctx, namespace, resource.ListOptions{
+			Continue:        resp.GetContinue(),
+			ResourceVersion: opts.ResourceVersion,
+			Limit:           opts.Limit,
+			LabelFilters:    opts.LabelFilters,
+			FieldSelectors:  opts.FieldSelectors,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.SetContinue(page.GetContinue())
+		resp.SetResourceVersion(page.GetResourceVersion())
+		resp.SetItems(append(resp.GetItems(), page.GetItems()...))
+	}
+	return resp, nil
+}
+
+func (c *LibraryExpressionClient) Create(ctx context.Context, obj *LibraryExpression, opts resource.CreateOptions) (*LibraryExpression, error) {
+	// Make sure apiVersion and kind are set
+	obj.APIVersion = GroupVersion.Identifier()
+	obj.Kind = LibraryExpressionKind().Kind()
+	return c.client.Create(ctx, obj, opts)
+}
+
+func (c *LibraryExpressionClient) Update(ctx context.Context, obj *LibraryExpression, opts resource.UpdateOptions) (*LibraryExpression, error) {
+	return c.client.Update(ctx, obj, opts)
+}
+
+func (c *LibraryExpressionClient) Patch(ctx context.Context, identifier resource.Identifier, req resource.PatchRequest, opts resource.PatchOptions) (*LibraryExpression, error) {
+	return c.client.Patch(ctx, identifier, req, opts)
+}
+
+func (c *LibraryExpressionClient) UpdateStatus(ctx context.Context, identifier resource.Identifier, newStatus LibraryExpressionStatus, opts resource.UpdateOptions) (*LibraryExpression, error) {
+	return c.client.Update(ctx, &LibraryExpression{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       LibraryExpressionKind().Kind(),
+			APIVersion: GroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			ResourceVersion: opts.ResourceVersion,
+			Namespace:       identifier.Namespace,
+			Name:            identifier.Name,
+		},
+		Status: newStatus,
+	}, resource.UpdateOptions{
+		Subresource:     "status",
+		ResourceVersion: opts.ResourceVersion,
+	})
+}
+
+func (c *LibraryExpressionClient) Delete(ctx context.Context, identifier resource.Identifier, opts resource.DeleteOptions) error {
+	return c.client.Delete(ctx, identifier, opts)
+}
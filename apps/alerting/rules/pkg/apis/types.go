@@ -13,6 +13,7 @@ func GetKinds() map[schema.GroupVersion][]sdkResource.Kind {
 			v0alpha1.AlertRuleKind(),
 			v0alpha1.RecordingRuleKind(),
 			v0alpha1.RuleSequenceKind(),
+			v0alpha1.LibraryExpressionKind(),
 		},
 	}
 	return result
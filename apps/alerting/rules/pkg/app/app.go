@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/alertrule"
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/config"
+	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/libraryexpression"
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/recordingrule"
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/rulesequence"
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/validation"
@@ -38,8 +39,9 @@ func New(cfg app.Config) (app.App, error) {
 				Mutator:   buildKindMutator(kind, runtimeCfg),
 				Watcher:   buildKindWatcher(kind, runtimeCfg),
 			}
-			// Only kinds with a watcher run an informer (RuleSequence), so this
-			// scopes that watch to WatchNamespace; empty means all namespaces.
+			// Only kinds with a watcher run an informer (RuleSequence and
+			// LibraryExpression), so this scopes those watches to WatchNamespace;
+			// empty means all namespaces.
 			if managedKind.Watcher != nil && runtimeCfg.WatchNamespace != "" {
 				managedKind.ReconcileOptions.Namespace = runtimeCfg.WatchNamespace
 			}
@@ -109,6 +111,11 @@ func buildKindValidator(kind resource.Kind, cfg config.RuntimeConfig, md app.Man
 			WithOpenAPIValidation(md, gk).
 			OnWrite(rulesequence.ValidateWrite(cfg)).
 			Build()
+	case "LibraryExpression":
+		return validation.NewBuilder[*v0alpha1.LibraryExpression]().
+			WithOpenAPIValidation(md, gk).
+			OnWrite(libraryexpression.ValidateWrite(cfg)).
+			Build()
 	}
 	return nil, nil
 }
@@ -131,6 +138,10 @@ func buildKindWatcher(kind resource.Kind, cfg config.RuntimeConfig) operator.Res
 		if idx, ok := cfg.MembershipResolver.(*rulesequence.MembershipIndex); ok {
 			return idx
 		}
+	case "LibraryExpression":
+		if cfg.LibraryExpressionWatcher != nil {
+			return cfg.LibraryExpressionWatcher
+		}
 	}
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/grafana/grafana-app-sdk/operator"
 	"github.com/grafana/grafana-app-sdk/simple"
 
 	"github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
@@ -44,9 +45,13 @@ type RuntimeConfig struct {
 	// RuleSequence (if any) owns a rule UID. The default implementation is a
 	// watch-backed in-memory index that provides O(1) lookups.
 	MembershipResolver RuleSequenceMembershipResolver
-	// WatchNamespace scopes the RuleSequence informer to one namespace. Empty
-	// watches all namespaces (on-prem default); in cloud it must be the stack
-	// namespace, else the all-namespace watch is rejected as a mismatch.
+	// LibraryExpressionWatcher is kept in sync with the LibraryExpressions by an
+	// informer. The registry uses it to look up library expressions when the
+	// expression engine expands them. When nil, no informer is run for the kind.
+	LibraryExpressionWatcher operator.ResourceWatcher
+	// WatchNamespace scopes the informers to one namespace. Empty watches all
+	// namespaces (on-prem default); in cloud it must be the stack namespace,
+	// else the all-namespace watch is rejected as a mismatch.
 	WatchNamespace string
 	// SearchRulesHandler is built by the registry with access to the alerting
 	// services. It backs the single namespaced POST /search custom route, which
@@ -0,0 +1,89 @@
+package libraryexpression
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/grafana-app-sdk/resource"
+
+	model "github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
+)
+
+// Index maintains a watch-backed, in-memory copy of the LibraryExpressions of
+// every namespace. It implements operator.ResourceWatcher so the app-sdk
+// informer keeps it in sync, and is read by the expression engine every time it
+// expands a library expression, so changes are used by the next evaluation of
+// every rule that references the library expression.
+//
+// Before the informer's initial list has been processed the index is empty and
+// Get reports every library expression as missing.
+type Index struct {
+	mu    sync.RWMutex
+	items map[resource.Identifier]*model.LibraryExpression
+}
+
+// NewIndex creates an empty index. The caller must register it as a Watcher on
+// the LibraryExpression AppManagedKind so the app-sdk informer populates it.
+func NewIndex() *Index {
+	return &Index{
+		items: make(map[resource.Identifier]*model.LibraryExpression),
+	}
+}
+
+// Get returns a copy of the LibraryExpression with the given name in the namespace.
+func (i *Index) Get(namespace, name string) (*model.LibraryExpression, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	le, ok := i.items[resource.Identifier{Namespace: namespace, Name: name}]
+	if !ok {
+		return nil, false
+	}
+	return le.DeepCopy(), true
+}
+
+// Add is called by the informer when a LibraryExpression is created or observed
+// during the initial list.
+func (i *Index) Add(_ context.Context, obj resource.Object) error {
+	le, ok := obj.(*model.LibraryExpression)
+	if !ok {
+		return fmt.Errorf("library expression index received non-LibraryExpression object: %T", obj)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.items[identifier(le)] = le.DeepCopy()
+	return nil
+}
+
+// Update is called by the informer when a LibraryExpression is modified.
+func (i *Index) Update(_ context.Context, old, new resource.Object) error {
+	oldLE, ok := old.(*model.LibraryExpression)
+	if !ok {
+		return fmt.Errorf("library expression index received non-LibraryExpression old object: %T", old)
+	}
+	newLE, ok := new.(*model.LibraryExpression)
+	if !ok {
+		return fmt.Errorf("library expression index received non-LibraryExpression new object: %T", new)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.items, identifier(oldLE))
+	i.items[identifier(newLE)] = newLE.DeepCopy()
+	return nil
+}
+
+// Delete is called by the informer when a LibraryExpression is removed.
+func (i *Index) Delete(_ context.Context, obj resource.Object) error {
+	le, ok := obj.(*model.LibraryExpression)
+	if !ok {
+		return fmt.Errorf("library expression index received non-LibraryExpression object: %T", obj)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.items, identifier(le))
+	return nil
+}
+
+func identifier(le *model.LibraryExpression) resource.Identifier {
+	return resource.Identifier{Namespace: le.Namespace, Name: le.Name}
+}
@@ -0,0 +1,139 @@
+package libraryexpression
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
+)
+
+func makeLibraryExpression(namespace, name, output string) *model.LibraryExpression {
+	le := model.NewLibraryExpression()
+	le.Namespace = namespace
+	le.Name = name
+	le.Spec.Title = name
+	le.Spec.Parameters = []model.LibraryExpressionParameter{{Name: "input"}}
+	le.Spec.Expressions = model.LibraryExpressionGraphNodeMap{
+		output: {Model: map[string]any{"type": "math", "expression": "$input * 2"}},
+	}
+	le.Spec.Output = output
+	return le
+}
+
+func TestIndex_Get_empty_index_returns_not_found(t *testing.T) {
+	idx := NewIndex()
+	_, ok := idx.Get("default", "double")
+	assert.False(t, ok)
+}
+
+func TestIndex_Add_is_namespaced(t *testing.T) {
+	idx := NewIndex()
+	require.NoError(t, idx.Add(context.Background(), makeLibraryExpression("org-1", "double", "A")))
+
+	le, ok := idx.Get("org-1", "double")
+	require.True(t, ok)
+	assert.Equal(t, "A", le.Spec.Output)
+
+	_, ok = idx.Get("org-2", "double")
+	assert.False(t, ok)
+}
+
+func TestIndex_Get_returns_a_copy(t *testing.T) {
+	idx := NewIndex()
+	require.NoError(t, idx.Add(context.Background(), makeLibraryExpression("org-1", "double", "A")))
+
+	le, ok := idx.Get("org-1", "double")
+	require.True(t, ok)
+	le.Spec.Output = "B"
+
+	le, ok = idx.Get("org-1", "double")
+	require.True(t, ok)
+	assert.Equal(t, "A", le.Spec.Output)
+}
+
+func TestIndex_Update_replaces_the_definition(t *testing.T) {
+	idx := NewIndex()
+	old := makeLibraryExpression("org-1", "double", "A")
+	require.NoError(t, idx.Add(context.Background(), old))
+	require.NoError(t, idx.Update(context.Background(), old, makeLibraryExpression("org-1", "double", "B")))
+
+	le, ok := idx.Get("org-1", "double")
+	require.True(t, ok)
+	assert.Equal(t, "B", le.Spec.Output)
+}
+
+func TestIndex_Delete_removes_the_definition(t *testing.T) {
+	idx := NewIndex()
+	le := makeLibraryExpression("org-1", "double", "A")
+	require.NoError(t, idx.Add(context.Background(), le))
+	require.NoError(t, idx.Delete(context.Background(), le))
+
+	_, ok := idx.Get("org-1", "double")
+	assert.False(t, ok)
+}
+
+func TestIndex_rejects_other_kinds(t *testing.T) {
+	idx := NewIndex()
+	require.Error(t, idx.Add(context.Background(), model.NewRuleSequence()))
+}
+
+func TestValidateSpec(t *testing.T) {
+	valid := func() model.LibraryExpressionSpec {
+		return makeLibraryExpression("org-1", "double", "A").Spec
+	}
+
+	require.NoError(t, ValidateSpec(valid()))
+
+	tests := []struct {
+		name   string
+		mutate func(*model.LibraryExpressionSpec)
+		errMsg string
+	}{
+		{
+			name:   "empty title",
+			mutate: func(s *model.LibraryExpressionSpec) { s.Title = "" },
+			errMsg: "title must not be empty",
+		},
+		{
+			name:   "no expressions",
+			mutate: func(s *model.LibraryExpressionSpec) { s.Expressions = nil },
+			errMsg: "at least one expression",
+		},
+		{
+			name: "duplicate parameter",
+			mutate: func(s *model.LibraryExpressionSpec) {
+				s.Parameters = append(s.Parameters, model.LibraryExpressionParameter{Name: "input"})
+			},
+			errMsg: `parameter "input" is defined multiple times`,
+		},
+		{
+			name: "parameter named like an expression",
+			mutate: func(s *model.LibraryExpressionSpec) {
+				s.Parameters = append(s.Parameters, model.LibraryExpressionParameter{Name: "A"})
+			},
+			errMsg: `parameter "A" has the same name as an expression`,
+		},
+		{
+			name: "model without type",
+			mutate: func(s *model.LibraryExpressionSpec) {
+				s.Expressions["B"] = model.LibraryExpressionGraphNode{Model: map[string]any{"expression": "$A"}}
+			},
+			errMsg: `model of expression "B" must have an expression type`,
+		},
+		{
+			name:   "unknown output",
+			mutate: func(s *model.LibraryExpressionSpec) { s.Output = "C" },
+			errMsg: `output "C" is not one of the expressions`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := valid()
+			tc.mutate(&spec)
+			require.ErrorContains(t, ValidateSpec(spec), tc.errMsg)
+		})
+	}
+}
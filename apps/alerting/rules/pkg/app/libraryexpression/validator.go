@@ -0,0 +1,62 @@
+package libraryexpression
+
+import (
+	"context"
+	"fmt"
+
+	model "github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
+	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/config"
+	"github.com/grafana/grafana/apps/alerting/rules/pkg/app/validation"
+)
+
+// ValidateWrite checks that the graph of a LibraryExpression is well formed. The
+// expressions themselves are parsed when the graph is expanded by the expression
+// engine, so only the references between the parts of the graph are checked here.
+func ValidateWrite(_ config.RuntimeConfig) validation.ValidateFunc[*model.LibraryExpression] {
+	return func(_ context.Context, req validation.Request[*model.LibraryExpression]) error {
+		return ValidateSpec(req.Object.Spec)
+	}
+}
+
+// ValidateSpec returns an error if the spec does not describe a usable graph.
+func ValidateSpec(spec model.LibraryExpressionSpec) error {
+	if spec.Title == "" {
+		return fmt.Errorf("library expression title must not be empty")
+	}
+	if len(spec.Expressions) == 0 {
+		return fmt.Errorf("library expression requires at least one expression")
+	}
+
+	params := make(map[string]struct{}, len(spec.Parameters))
+	for _, p := range spec.Parameters {
+		name := string(p.Name)
+		if name == "" {
+			return fmt.Errorf("parameter name must not be empty")
+		}
+		if _, exists := params[name]; exists {
+			return fmt.Errorf("parameter %q is defined multiple times", name)
+		}
+		if _, exists := spec.Expressions[name]; exists {
+			return fmt.Errorf("parameter %q has the same name as an expression", name)
+		}
+		params[name] = struct{}{}
+	}
+
+	for refID, expr := range spec.Expressions {
+		if refID == "" {
+			return fmt.Errorf("expression refId must not be empty")
+		}
+		m, ok := expr.Model.(map[string]any)
+		if !ok {
+			return fmt.Errorf("model of expression %q must be an object", refID)
+		}
+		if t, ok := m["type"].(string); !ok || t == "" {
+			return fmt.Errorf("model of expression %q must have an expression type", refID)
+		}
+	}
+
+	if _, ok := spec.Expressions[spec.Output]; !ok {
+		return fmt.Errorf("output %q is not one of the expressions", spec.Output)
+	}
+	return nil
+}
@@ -75,6 +75,10 @@ type MetricRequest struct {
 	Queries []*simplejson.Json `json:"queries"`
 	// required: false
 	Debug bool `json:"debug"`
+	// Explain adds the explanation of the execution of each expression and query to the results, under the refId `__explain__`.
+	// It applies only to requests with expressions.
+	// required: false
+	Explain bool `json:"explain"`
 }
 
 func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
@@ -102,6 +106,7 @@ func (mr *MetricRequest) CloneWithQueries(queries []*simplejson.Json) MetricRequ
 		To:      mr.To,
 		Queries: queries,
 		Debug:   mr.Debug,
+		Explain: mr.Explain,
 	}
 }
 
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// newAlertingStateArchiver creates the service that exports and imports the alert state directly in the database.
+// The in-memory state of a running Grafana is not updated, so the commands should run while Grafana is stopped.
+func newAlertingStateArchiver(cfg *setting.Cfg, sqlStore db.DB) *statearchive.Service {
+	l := log.New("cli.alerting-state")
+	ruleStore := &store.DBstore{Cfg: cfg.UnifiedAlerting, SQLStore: sqlStore, Logger: l}
+	return statearchive.NewService(
+		ruleStore,
+		store.ProtoInstanceDBStore{SQLStore: sqlStore, Logger: l},
+		ruleStore,
+		statearchive.NewKVAlertmanagerState(kvstore.ProvideService(sqlStore)),
+		nil,
+		l,
+	)
+}
+
+func exportAlertingState(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	path := c.String("file")
+
+	archive, err := newAlertingStateArchiver(cfg, sqlStore).Export(context.Background(), int64(c.Int("org-id")))
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(path), b, 0600); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	logger.Infof("Exported the state of %d alert instances of %d alert rules to %s.", len(archive.Instances), len(archive.Rules), path)
+	return nil
+}
+
+func importAlertingState(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	path := c.String("file")
+	ruleUIDs, err := parseUIDMap(c.StringSlice("rule-uid-map"))
+	if err != nil {
+		return err
+	}
+	folderUIDs, err := parseUIDMap(c.StringSlice("folder-uid-map"))
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read the archive: %w", err)
+	}
+	var archive definitions.AlertStateArchive
+	if err := json.Unmarshal(b, &archive); err != nil {
+		return fmt.Errorf("failed to parse the archive: %w", err)
+	}
+
+	result, err := newAlertingStateArchiver(cfg, sqlStore).Import(context.Background(), int64(c.Int("org-id")), archive, statearchive.ImportOptions{
+		RuleUIDs:   ruleUIDs,
+		FolderUIDs: folderUIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Imported the state of %d alert instances of %d alert rules.", result.Instances, result.Rules)
+	if len(result.SkippedRules) > 0 {
+		logger.Infof("Skipped %d alert rules that were not found: %s", len(result.SkippedRules), strings.Join(result.SkippedRules, ", "))
+	}
+	if result.NotificationLogSkipped {
+		logger.Infof("Skipped the notification log because the labels of alert rules changed. Notifications of the imported alerts will be sent again.")
+	}
+	return nil
+}
+
+// parseUIDMap parses a list of mappings in the form <archive UID>=<UID>.
+func parseUIDMap(values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	for _, v := range values {
+		from, to, ok := strings.Cut(v, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid UID mapping %q, expected <archive UID>=<UID>", v)
+		}
+		result[from] = to
+	}
+	return result, nil
+}
@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUIDMap(t *testing.T) {
+	result, err := parseUIDMap([]string{"rule-a=rule-b", "folder=other-folder"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"rule-a": "rule-b", "folder": "other-folder"}, result)
+
+	result, err = parseUIDMap(nil)
+	require.NoError(t, err)
+	require.Empty(t, result)
+
+	for _, invalid := range []string{"rule-a", "=rule-b", "rule-a="} {
+		_, err := parseUIDMap([]string{invalid})
+		require.Error(t, err, invalid)
+	}
+}
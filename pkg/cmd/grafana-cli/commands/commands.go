@@ -228,6 +228,54 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "alerting-state",
+		Usage: "Exports and imports the state of the alerts of an organization. Run the commands while Grafana is stopped.",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Exports the alert instances, silences and notification log of an organization to a file.",
+				Action: runDbCommand(exportAlertingState),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The ID of the organization",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:     "file",
+						Usage:    "Path of the file to write the archive to",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "import",
+				Usage:  "Imports an archive of alert instances, silences and notification log into an organization. The state of the matching alert rules is replaced.",
+				Action: runDbCommand(importAlertingState),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The ID of the organization",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:     "file",
+						Usage:    "Path of the file to read the archive from",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "rule-uid-map",
+						Usage: "Maps the UID of an alert rule in the archive to the UID of an alert rule in the organization, as <archive UID>=<UID>. Can be repeated.",
+					},
+					&cli.StringSliceFlag{
+						Name:  "folder-uid-map",
+						Usage: "Maps the UID of a folder in the archive to the UID of a folder in the organization, as <archive UID>=<UID>. Can be repeated.",
+					},
+				},
+			},
+		},
+	},
 	{
 		Name:   "flush-rbac-seed-assignment",
 		Usage:  "Clears RBAC seeding to force re-seeding on next startup. Use after running an Enterprise build, then an OSS build, then an Enterprise build again.",
@@ -0,0 +1,68 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AggregateCommand groups the numbers or series of the referenced query by
+// a list of labels and aggregates the values of each group.
+type AggregateCommand struct {
+	ReferenceVar string
+	RefID        string
+	Operation    mathexp.AggregateOperation
+	By           []string
+	K            int
+}
+
+// NewAggregateCommand creates a new AggregateCommand.
+func NewAggregateCommand(refID, referenceVar string, operation mathexp.AggregateOperation, by []string, k int) (*AggregateCommand, error) {
+	if err := mathexp.ValidateAggregate(operation, k); err != nil {
+		return nil, err
+	}
+	return &AggregateCommand{
+		ReferenceVar: referenceVar,
+		RefID:        refID,
+		Operation:    operation,
+		By:           by,
+		K:            k,
+	}, nil
+}
+
+// UnmarshalAggregateCommand creates an AggregateCommand from Grafana's frontend query.
+func UnmarshalAggregateCommand(rn *rawNode) (*AggregateCommand, error) {
+	q := AggregateQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the aggregate command: %w", err)
+	}
+	referenceVar := strings.TrimPrefix(q.Expression, "$")
+	if referenceVar == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+	return NewAggregateCommand(rn.RefID, referenceVar, q.Operation, q.By, q.K)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AggregateCommand) NeedsVars() []string {
+	return []string{ac.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AggregateCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAggregate")
+	defer span.End()
+	return mathexp.Aggregate(ac.RefID, vars[ac.ReferenceVar], ac.Operation, ac.By, ac.K)
+}
+
+func (ac *AggregateCommand) Type() string {
+	return TypeAggregate.String()
+}
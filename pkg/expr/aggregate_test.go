@@ -0,0 +1,64 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestUnmarshalAggregateCommand(t *testing.T) {
+	t.Run("parses the query", func(t *testing.T) {
+		cmd, err := UnmarshalAggregateCommand(&rawNode{
+			RefID:    "B",
+			QueryRaw: []byte(`{"expression": "$A", "operation": "topk", "by": ["cluster"], "k": 2}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &AggregateCommand{
+			ReferenceVar: "A",
+			RefID:        "B",
+			Operation:    mathexp.AggregateTopK,
+			By:           []string{"cluster"},
+			K:            2,
+		}, cmd)
+		require.Equal(t, []string{"A"}, cmd.NeedsVars())
+	})
+
+	t.Run("missing expression should error", func(t *testing.T) {
+		_, err := UnmarshalAggregateCommand(&rawNode{
+			RefID:    "B",
+			QueryRaw: []byte(`{"operation": "sum"}`),
+		})
+		require.ErrorContains(t, err, "no variable specified")
+	})
+
+	t.Run("unknown operation should error", func(t *testing.T) {
+		_, err := UnmarshalAggregateCommand(&rawNode{
+			RefID:    "B",
+			QueryRaw: []byte(`{"expression": "$A", "operation": "median"}`),
+		})
+		require.ErrorContains(t, err, "not supported")
+	})
+}
+
+func TestAggregateCommand_Execute(t *testing.T) {
+	cmd, err := NewAggregateCommand("B", "A", mathexp.AggregateSum, []string{"cluster"}, 0)
+	require.NoError(t, err)
+
+	a1 := mathexp.NewNumber("A", data.Labels{"cluster": "a", "host": "1"})
+	a1.SetValue(new(1.0))
+	a2 := mathexp.NewNumber("A", data.Labels{"cluster": "a", "host": "2"})
+	a2.SetValue(new(2.0))
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{a1, a2}}}
+
+	res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+	require.Equal(t, data.Labels{"cluster": "a"}, res.Values[0].GetLabels())
+	require.Equal(t, new(3.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+}
@@ -95,7 +95,7 @@ type ReduceCommand struct {
 
 // NewReduceCommand creates a new ReduceCMD.
 func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+	_, err := mathexp.GetSeriesReduceFunc(reducer)
 	if err != nil {
 		return nil, err
 	}
@@ -222,6 +222,8 @@ type ResampleCommand struct {
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
 	TimeRange     TimeRange
+	Align         mathexp.ResampleAlignment
+	Location      *time.Location
 	refID         string
 }
 
@@ -238,10 +240,32 @@ func NewResampleCommand(refID, rawWindow, varToResample string, downsampler math
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
 		TimeRange:     tr,
+		Location:      time.UTC,
 		refID:         refID,
 	}, nil
 }
 
+// WithAlignment aligns the resampled points to the start of the calendar unit in the
+// given timezone instead of the start of the time range. An empty timezone means UTC.
+func (gr *ResampleCommand) WithAlignment(align mathexp.ResampleAlignment, timezone string) error {
+	switch align {
+	case mathexp.ResampleAlignmentNone, mathexp.ResampleAlignmentMinute, mathexp.ResampleAlignmentHour, mathexp.ResampleAlignmentDay:
+	default:
+		return fmt.Errorf("resample alignment '%s' is not supported. Supported only: [minute,hour,day]", align)
+	}
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return fmt.Errorf("failed to load resample timezone %q: %w", timezone, err)
+		}
+	}
+	gr.Align = align
+	gr.Location = loc
+	return nil
+}
+
 // UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
 func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 	if rn.TimeRange == nil {
@@ -285,11 +309,30 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
-	return NewResampleCommand(rn.RefID, window,
+	cmd, err := NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
 		rn.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var align, timezone string
+	if rawAlign, ok := rn.Query["align"]; ok {
+		if align, ok = rawAlign.(string); !ok {
+			return nil, fmt.Errorf("expected resample align to be a string, got type %T", rawAlign)
+		}
+	}
+	if rawTimezone, ok := rn.Query["timezone"]; ok {
+		if timezone, ok = rawTimezone.(string); !ok {
+			return nil, fmt.Errorf("expected resample timezone to be a string, got type %T", rawTimezone)
+		}
+	}
+	if err := cmd.WithAlignment(mathexp.ResampleAlignment(align), timezone); err != nil {
+		return nil, err
+	}
+	return cmd, nil
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -311,7 +354,7 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 		}
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To)
+			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To, mathexp.WithAlignment(gr.Align, gr.Location))
 			if err != nil {
 				return newRes, err
 			}
@@ -348,6 +391,16 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeForecast is the CMDType for forecasting series and detecting anomalies
+	TypeForecast
+	// TypeAggregate is the CMDType for aggregating results grouped by labels
+	TypeAggregate
+	// TypeJoin is the CMDType for joining two results by their labels
+	TypeJoin
+	// TypeLibrary is the CMDType for expanding a library expression of the organization
+	TypeLibrary
+	// TypeRuleState is the CMDType for reading the state of another alert rule of the organization
+	TypeRuleState
 )
 
 func (gt CommandType) String() string {
@@ -364,6 +417,16 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeForecast:
+		return "forecast"
+	case TypeAggregate:
+		return "aggregate"
+	case TypeJoin:
+		return "join"
+	case TypeLibrary:
+		return "library"
+	case TypeRuleState:
+		return "rule_state"
 	default:
 		return "unknown"
 	}
@@ -384,6 +447,16 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "forecast":
+		return TypeForecast, nil
+	case "aggregate":
+		return TypeAggregate, nil
+	case "join":
+		return TypeJoin, nil
+	case "library":
+		return TypeLibrary, nil
+	case "rule_state":
+		return TypeRuleState, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math"
 	"math/rand"
 	"testing"
@@ -273,3 +274,52 @@ func TestResampleCommand_Execute(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestUnmarshalResampleCommand_Alignment(t *testing.T) {
+	newNode := func(query map[string]any) *rawNode {
+		q := map[string]any{
+			"expression":  "$A",
+			"window":      "1h",
+			"downsampler": "mean",
+			"upsampler":   "linear",
+		}
+		maps.Copy(q, query)
+		return &rawNode{
+			RefID:     "B",
+			Query:     q,
+			TimeRange: RelativeTimeRange{From: -24 * time.Hour},
+		}
+	}
+
+	t.Run("defaults to no alignment in UTC", func(t *testing.T) {
+		cmd, err := UnmarshalResampleCommand(newNode(nil))
+		require.NoError(t, err)
+		require.Equal(t, mathexp.ResampleAlignment(mathexp.ResampleAlignmentNone), cmd.Align)
+		require.Equal(t, time.UTC, cmd.Location)
+	})
+
+	t.Run("aligns to calendar unit in timezone", func(t *testing.T) {
+		cmd, err := UnmarshalResampleCommand(newNode(map[string]any{
+			"align":    "day",
+			"timezone": "Europe/Berlin",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, mathexp.ResampleAlignmentDay, cmd.Align)
+		require.Equal(t, "Europe/Berlin", cmd.Location.String())
+	})
+
+	t.Run("fails on unknown alignment", func(t *testing.T) {
+		_, err := UnmarshalResampleCommand(newNode(map[string]any{
+			"align": "week",
+		}))
+		require.Error(t, err)
+	})
+
+	t.Run("fails on unknown timezone", func(t *testing.T) {
+		_, err := UnmarshalResampleCommand(newNode(map[string]any{
+			"align":    "hour",
+			"timezone": "Mars/Olympus_Mons",
+		}))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,247 @@
+package expr
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ExplainRefID is the refId of the response that holds the Explanation of a pipeline
+// when the request asks for it.
+const ExplainRefID = "__explain__"
+
+// The explanation is returned along with the results, so its size is bounded regardless
+// of the size of the data the pipeline processes.
+const (
+	// maxExplainFrames is the maximum number of input and output frames kept for each node.
+	maxExplainFrames = 10
+	// maxExplainRows is the maximum number of rows kept for each frame.
+	maxExplainRows = 10
+	// maxExplainLabelSets is the maximum number of label sets and dropped series kept for each node.
+	maxExplainLabelSets = 100
+	// maxExplainLabelComparisons is the maximum number of label set comparisons done to find
+	// the dropped series of a node.
+	maxExplainLabelComparisons = 100_000
+)
+
+// Explanation describes how each node of a pipeline was executed, in the order of execution.
+type Explanation struct {
+	Nodes []*NodeExplanation `json:"nodes"`
+
+	// conversions holds the conversions of the datasource responses until their node is added.
+	conversions map[string]conversionExplanation
+}
+
+// NodeExplanation describes the execution of a single node. Frames and label sets are truncated.
+type NodeExplanation struct {
+	RefID  string   `json:"refId"`
+	Type   string   `json:"type"`
+	Inputs []string `json:"inputs,omitempty"`
+	// DurationMs is the time the node took to execute. Datasource nodes that are queried
+	// together share the duration of the query.
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+	// Conversion is the type of conversion done to the response of a datasource.
+	Conversion   string        `json:"conversion,omitempty"`
+	InputFrames  data.Frames   `json:"inputFrames,omitempty"`
+	OutputFrames data.Frames   `json:"outputFrames,omitempty"`
+	Labels       []data.Labels `json:"labels,omitempty"`
+	// DroppedSeries are the label sets of the inputs that have no matching label set in the output.
+	// Label sets match when one is a subset of the other.
+	DroppedSeries []data.Labels `json:"droppedSeries,omitempty"`
+	// Truncated is true if frames, rows or label sets were left out of the explanation.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type conversionExplanation struct {
+	conversion string
+	frames     data.Frames
+}
+
+type explanationKey struct{}
+
+// ContextWithExplanation returns a context that makes the pipelines executed with it
+// record their execution into the returned Explanation.
+func ContextWithExplanation(ctx context.Context) (context.Context, *Explanation) {
+	e := &Explanation{conversions: map[string]conversionExplanation{}}
+	return context.WithValue(ctx, explanationKey{}, e), e
+}
+
+// explanationFromContext returns the Explanation of the context or nil if the execution is not explained.
+// All methods of Explanation can be called on nil.
+func explanationFromContext(ctx context.Context) *Explanation {
+	e, _ := ctx.Value(explanationKey{}).(*Explanation)
+	return e
+}
+
+// DataResponse returns the explanation as the custom metadata of a frame without fields.
+func (e *Explanation) DataResponse() backend.DataResponse {
+	frame := data.NewFrame("explain")
+	frame.RefID = ExplainRefID
+	frame.SetMeta(&data.FrameMeta{
+		Type:        "explain",
+		TypeVersion: data.FrameTypeVersion{0, 1},
+		Custom:      e,
+	})
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// addConversion records the frames a datasource returned for the node and how they were converted.
+func (e *Explanation) addConversion(refID, conversion string, frames data.Frames) {
+	if e == nil {
+		return
+	}
+	e.conversions[refID] = conversionExplanation{conversion: conversion, frames: frames}
+}
+
+// addNode records the result of the node. The inputs of the node must be in vars.
+func (e *Explanation) addNode(node Node, vars mathexp.Vars, res mathexp.Results, duration time.Duration) {
+	if e == nil {
+		return
+	}
+	ne := &NodeExplanation{
+		RefID:      node.RefID(),
+		Type:       explainNodeType(node),
+		Inputs:     node.NeedsVars(),
+		DurationMs: float64(duration.Nanoseconds()) / float64(time.Millisecond),
+	}
+	if res.Error != nil {
+		ne.Error = res.Error.Error()
+	}
+
+	var inputFrames data.Frames
+	if c, ok := e.conversions[node.RefID()]; ok {
+		ne.Conversion = c.conversion
+		inputFrames = c.frames
+		delete(e.conversions, node.RefID())
+	} else {
+		for _, refID := range ne.Inputs {
+			for _, v := range vars[refID].Values {
+				inputFrames = append(inputFrames, v.AsDataFrame())
+			}
+		}
+	}
+	outputFrames := make(data.Frames, 0, len(res.Values))
+	for _, v := range res.Values {
+		outputFrames = append(outputFrames, v.AsDataFrame())
+	}
+	ne.InputFrames = ne.truncateFrames(inputFrames)
+	ne.OutputFrames = ne.truncateFrames(outputFrames)
+
+	outputLabels := make([]data.Labels, 0, len(res.Values))
+	for _, v := range res.Values {
+		if _, ok := v.(mathexp.NoData); ok {
+			continue
+		}
+		outputLabels = append(outputLabels, v.GetLabels())
+	}
+	ne.Labels = ne.truncateLabels(outputLabels)
+	if res.Error == nil {
+		ne.DroppedSeries = ne.truncateLabels(droppedSeries(frameLabels(inputFrames), outputLabels))
+	}
+
+	e.Nodes = append(e.Nodes, ne)
+}
+
+func (ne *NodeExplanation) truncateFrames(frames data.Frames) data.Frames {
+	if len(frames) > maxExplainFrames {
+		frames = frames[:maxExplainFrames]
+		ne.Truncated = true
+	}
+	result := make(data.Frames, 0, len(frames))
+	for _, f := range frames {
+		if f == nil {
+			continue
+		}
+		if f.Rows() > maxExplainRows {
+			ne.Truncated = true
+		}
+		result = append(result, truncateFrame(f, maxExplainRows))
+	}
+	return result
+}
+
+func (ne *NodeExplanation) truncateLabels(labels []data.Labels) []data.Labels {
+	if len(labels) > maxExplainLabelSets {
+		ne.Truncated = true
+		return labels[:maxExplainLabelSets]
+	}
+	return labels
+}
+
+// truncateFrame returns a copy of the frame with at most the given number of rows.
+func truncateFrame(f *data.Frame, rows int) *data.Frame {
+	result := f.EmptyCopy()
+	for i, field := range f.Fields {
+		for j := 0; j < min(rows, field.Len()); j++ {
+			result.Fields[i].Append(field.CopyAt(j))
+		}
+	}
+	return result
+}
+
+// frameLabels returns the label sets of the numeric fields of the frames.
+func frameLabels(frames data.Frames) []data.Labels {
+	var result []data.Labels
+	for _, f := range frames {
+		if f == nil {
+			continue
+		}
+		for _, field := range f.Fields {
+			if field.Type().Numeric() {
+				result = append(result, field.Labels)
+			}
+		}
+	}
+	return result
+}
+
+// droppedSeries returns the input label sets that have no matching label set in the output.
+// Label sets match when one is a subset of the other, so series that were reduced, grouped
+// or joined are not considered dropped. It returns nil if the comparison is too expensive.
+func droppedSeries(input, output []data.Labels) []data.Labels {
+	if len(input)*len(output) > maxExplainLabelComparisons {
+		return nil
+	}
+	var dropped []data.Labels
+	for _, in := range input {
+		matched := false
+		for _, out := range output {
+			if isLabelSubset(in, out) || isLabelSubset(out, in) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			dropped = append(dropped, in)
+		}
+	}
+	return dropped
+}
+
+// isLabelSubset returns true if every label of a is in b.
+func isLabelSubset(a, b data.Labels) bool {
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func explainNodeType(node Node) string {
+	switch n := node.(type) {
+	case *CMDNode:
+		return n.CMDType.String()
+	case *DSNode:
+		return n.datasource.Type
+	case *MLNode:
+		return "ml"
+	default:
+		return node.NodeType().String()
+	}
+}
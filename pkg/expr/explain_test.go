@@ -0,0 +1,140 @@
+package expr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestTransformDataExplain(t *testing.T) {
+	times := make([]time.Time, 0, 12)
+	values := make([]*float64, 0, 12)
+	for i := range 12 {
+		times = append(times, time.Unix(int64(i), 0))
+		values = append(values, new(float64(i)))
+	}
+	dsDF := data.NewFrame("test",
+		data.NewField("time", nil, times),
+		data.NewField("value", data.Labels{"host": "a"}, values),
+	)
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{
+				From: time.Time{},
+				To:   time.Time{},
+			},
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "reducer": "last", "expression": "$A", "hide": true }`),
+		},
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$B * 2" }`),
+		},
+	}
+
+	s, req := newMockQueryService(map[string]backend.DataResponse{"A": {Frames: data.Frames{dsDF}}}, queries)
+	s.cfg.ExpressionsEnabled = true
+
+	t.Run("does not explain by default", func(t *testing.T) {
+		res, err := s.TransformData(t.Context(), time.Now(), req)
+		require.NoError(t, err)
+		require.NotContains(t, res.Responses, ExplainRefID)
+	})
+
+	t.Run("explains every node", func(t *testing.T) {
+		req.Explain = true
+		res, err := s.TransformData(t.Context(), time.Now(), req)
+		require.NoError(t, err)
+		require.NotContains(t, res.Responses, "B")
+		require.Contains(t, res.Responses, ExplainRefID)
+
+		frames := res.Responses[ExplainRefID].Frames
+		require.Len(t, frames, 1)
+		explanation, ok := frames[0].Meta.Custom.(*Explanation)
+		require.True(t, ok)
+		require.Len(t, explanation.Nodes, 3)
+
+		a, b, c := explanation.Nodes[0], explanation.Nodes[1], explanation.Nodes[2]
+		require.Equal(t, "A", a.RefID)
+		require.Equal(t, "test", a.Type)
+		require.Equal(t, "single frame series", a.Conversion)
+		require.Len(t, a.InputFrames, 1)
+		require.Equal(t, maxExplainRows, a.InputFrames[0].Rows())
+		require.True(t, a.Truncated)
+
+		require.Equal(t, "B", b.RefID, "hidden nodes should be explained")
+		require.Equal(t, "reduce", b.Type)
+		require.Equal(t, []string{"A"}, b.Inputs)
+		require.Equal(t, []data.Labels{{"host": "a"}}, b.Labels)
+		require.Empty(t, b.DroppedSeries)
+
+		require.Equal(t, "C", c.RefID)
+		require.Equal(t, "math", c.Type)
+		require.Len(t, c.OutputFrames, 1)
+		v, err := c.OutputFrames[0].FloatAt(0, 0)
+		require.NoError(t, err)
+		require.Equal(t, 22.0, v)
+
+		_, err = json.Marshal(res)
+		require.NoError(t, err)
+	})
+}
+
+func TestDroppedSeries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []data.Labels
+		output   []data.Labels
+		expected []data.Labels
+	}{
+		{
+			name:   "series with the same labels are kept",
+			input:  []data.Labels{{"host": "a"}, {"host": "b"}},
+			output: []data.Labels{{"host": "a"}, {"host": "b"}},
+		},
+		{
+			name:     "series without output are dropped",
+			input:    []data.Labels{{"host": "a"}, {"host": "b"}},
+			output:   []data.Labels{{"host": "a"}},
+			expected: []data.Labels{{"host": "b"}},
+		},
+		{
+			name:   "grouped series are kept",
+			input:  []data.Labels{{"host": "a", "dc": "x"}, {"host": "b", "dc": "x"}},
+			output: []data.Labels{{"dc": "x"}},
+		},
+		{
+			name:   "joined series are kept",
+			input:  []data.Labels{{"host": "a"}},
+			output: []data.Labels{{"host": "a", "dc": "x"}},
+		},
+		{
+			name:     "every series is dropped without output",
+			input:    []data.Labels{{"host": "a"}},
+			expected: []data.Labels{{"host": "a"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, droppedSeries(tc.input, tc.output))
+		})
+	}
+}
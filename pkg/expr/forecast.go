@@ -0,0 +1,156 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// The label added to the series returned by the forecast command to tell them apart
+const forecastLabel = "forecast"
+
+// ForecastCommand fits a seasonal model to each series of the referenced query
+// and returns the predicted values with an anomaly band, or whether the last point
+// of each series is anomalous.
+type ForecastCommand struct {
+	ReferenceVar string
+	RefID        string
+	Output       ForecastOutput
+	Options      mathexp.ForecastOptions
+}
+
+// NewForecastCommand creates a new ForecastCommand.
+func NewForecastCommand(refID, referenceVar string, output ForecastOutput, opts mathexp.ForecastOptions) (*ForecastCommand, error) {
+	switch output {
+	case "":
+		output = ForecastOutputBands
+	case ForecastOutputBands, ForecastOutputAnomaly:
+	default:
+		return nil, fmt.Errorf("forecast output '%s' is not supported, expected one of [%s, %s]", output, ForecastOutputBands, ForecastOutputAnomaly)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &ForecastCommand{
+		ReferenceVar: referenceVar,
+		RefID:        refID,
+		Output:       output,
+		Options:      opts,
+	}, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	q := ForecastQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	referenceVar := strings.TrimPrefix(q.Expression, "$")
+	if referenceVar == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+
+	opts := mathexp.DefaultForecastOptions()
+	if q.Alpha != nil {
+		opts.Alpha = *q.Alpha
+	}
+	if q.Beta != nil {
+		opts.Beta = *q.Beta
+	}
+	if q.Gamma != nil {
+		opts.Gamma = *q.Gamma
+	}
+	if q.Deviations != nil {
+		opts.Deviations = *q.Deviations
+	}
+	var err error
+	if q.Seasonality != "" {
+		opts.Season, err = gtime.ParseDuration(q.Seasonality)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse seasonality '%v' in '%v': %w", q.Seasonality, rn.RefID, err)
+		}
+	}
+	if q.Horizon != "" {
+		opts.Horizon, err = gtime.ParseDuration(q.Horizon)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse horizon '%v' in '%v': %w", q.Horizon, rn.RefID, err)
+		}
+	}
+
+	return NewForecastCommand(rn.RefID, referenceVar, q.Output, opts)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (fc *ForecastCommand) NeedsVars() []string {
+	return []string{fc.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (fc *ForecastCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[fc.ReferenceVar].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			forecast, err := v.Forecast(fc.RefID, fc.Options)
+			if err != nil {
+				return newRes, err
+			}
+			if fc.Output == ForecastOutputAnomaly {
+				num := mathexp.NewNumber(fc.RefID, v.GetLabels())
+				num.SetValue(forecast.IsAnomalous)
+				if forecast.Notice != "" {
+					num.AddNotice(data.Notice{Severity: data.NoticeSeverityInfo, Text: forecast.Notice})
+				}
+				newRes.Values = append(newRes.Values, num)
+				continue
+			}
+			for _, band := range []struct {
+				kind   string
+				series mathexp.Series
+			}{
+				{"predicted", forecast.Predicted},
+				{"upper", forecast.Upper},
+				{"lower", forecast.Lower},
+			} {
+				s := band.series
+				s.SetLabels(withForecastLabel(v.GetLabels(), band.kind))
+				if forecast.Notice != "" {
+					s.AddNotice(data.Notice{Severity: data.NoticeSeverityInfo, Text: forecast.Notice})
+				}
+				newRes.Values = append(newRes.Values, s)
+			}
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (fc *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
+
+func withForecastLabel(labels data.Labels, kind string) data.Labels {
+	l := labels.Copy()
+	if l == nil {
+		l = data.Labels{}
+	}
+	l[forecastLabel] = kind
+	return l
+}
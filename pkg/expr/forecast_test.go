@@ -0,0 +1,130 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestUnmarshalForecastCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected *ForecastCommand
+		errorIs  string
+	}{
+		{
+			name:  "defaults",
+			query: `{"expression": "$A"}`,
+			expected: &ForecastCommand{
+				ReferenceVar: "A",
+				RefID:        "B",
+				Output:       ForecastOutputBands,
+				Options:      mathexp.DefaultForecastOptions(),
+			},
+		},
+		{
+			name:  "all options",
+			query: `{"expression": "A", "seasonality": "1d", "horizon": "1h", "alpha": 0.5, "beta": 0, "gamma": 0.2, "deviations": 2, "output": "anomaly"}`,
+			expected: &ForecastCommand{
+				ReferenceVar: "A",
+				RefID:        "B",
+				Output:       ForecastOutputAnomaly,
+				Options: mathexp.ForecastOptions{
+					Alpha:      0.5,
+					Beta:       0,
+					Gamma:      0.2,
+					Season:     24 * time.Hour,
+					Horizon:    time.Hour,
+					Deviations: 2,
+				},
+			},
+		},
+		{
+			name:    "missing expression",
+			query:   `{"seasonality": "1d"}`,
+			errorIs: "no variable specified",
+		},
+		{
+			name:    "invalid seasonality",
+			query:   `{"expression": "$A", "seasonality": "daily"}`,
+			errorIs: "failed to parse seasonality",
+		},
+		{
+			name:    "invalid output",
+			query:   `{"expression": "$A", "output": "both"}`,
+			errorIs: "forecast output 'both' is not supported",
+		},
+		{
+			name:    "alpha out of range",
+			query:   `{"expression": "$A", "alpha": 1.5}`,
+			errorIs: "alpha must be between 0 and 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := UnmarshalForecastCommand(&rawNode{
+				RefID:    "B",
+				QueryRaw: []byte(tc.query),
+			})
+			if tc.errorIs != "" {
+				require.ErrorContains(t, err, tc.errorIs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestForecastCommand_Execute(t *testing.T) {
+	input := mathexp.NewSeries("A", data.Labels{"host": "a"}, 7)
+	for i, v := range []float64{5, 6, 5, 6, 5, 6, 50} {
+		input.SetPoint(i, time.Unix(int64(i*10), 0), new(v))
+	}
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{input, mathexp.NewNoData()}},
+	}
+
+	t.Run("bands", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", ForecastOutputBands, mathexp.DefaultForecastOptions())
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 4)
+		for i, kind := range []string{"predicted", "upper", "lower"} {
+			require.Equal(t, data.Labels{"host": "a", "forecast": kind}, res.Values[i].GetLabels())
+			require.Equal(t, 7, res.Values[i].(mathexp.Series).Len())
+		}
+		require.IsType(t, mathexp.NoData{}, res.Values[3])
+		require.Equal(t, data.Labels{"host": "a"}, input.GetLabels())
+	})
+
+	t.Run("anomaly", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", ForecastOutputAnomaly, mathexp.DefaultForecastOptions())
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		num, ok := res.Values[0].(mathexp.Number)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"host": "a"}, num.GetLabels())
+		require.Equal(t, new(1.0), num.GetFloat64Value())
+	})
+
+	t.Run("number input should error", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", ForecastOutputAnomaly, mathexp.DefaultForecastOptions())
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}},
+		}, tracing.InitializeTracerForTest(), nil)
+		require.Error(t, err)
+	})
+}
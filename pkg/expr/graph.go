@@ -71,6 +71,7 @@ type DataPipeline []Node
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	explanation := explanationFromContext(c)
 	//nolint:staticcheck // not yet migrated to OpenFeature
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
 	// Execute datasource nodes first, and grouped by datasource.
@@ -83,7 +84,11 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			dsNodes = append(dsNodes, node.(*DSNode))
 		}
 
+		start := time.Now()
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		for _, node := range dsNodes {
+			explanation.addNode(node, vars, vars[node.RefID()], time.Since(start))
+		}
 	}
 
 	for _, node := range *dp {
@@ -102,6 +107,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 				}
 			}
 			vars[node.RefID()] = mathexp.Results{Error: disabledErr}
+			explanation.addNode(node, vars, vars[node.RefID()], 0)
 			continue
 		}
 
@@ -138,6 +144,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			}
 		}
 		if hasDepError {
+			explanation.addNode(node, vars, vars[node.RefID()], 0)
 			continue
 		}
 
@@ -154,12 +161,14 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
 
 		vars[node.RefID()] = res
+		explanation.addNode(node, vars, res, time.Since(start))
 		span.End()
 	}
 	return vars, nil
@@ -350,7 +359,13 @@ func (s *Service) buildGraph(ctx context.Context, req *Request) (*simple.Directe
 		case TypeDatasourceNode:
 			node, err = s.buildDSNode(dp, rn, req)
 		case TypeCMDNode:
-			node, err = buildCMDNode(ctx, rn, s.features, s.cfg)
+			if isLibraryNode(rn) {
+				node, err = s.buildLibraryNode(ctx, rn, req.OrgId, nil)
+			} else if isRuleStateNode(rn) {
+				node, err = s.buildRuleStateNode(rn, req.OrgId)
+			} else {
+				node, err = buildCMDNode(ctx, rn, s.features, s.cfg)
+			}
 		case TypeMLNode:
 			//nolint:staticcheck // not yet migrated to OpenFeature
 			if s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
@@ -0,0 +1,76 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// JoinCommand matches the results of two queries by their labels and applies
+// a binary operator to each matched pair.
+type JoinCommand struct {
+	Left    string
+	Right   string
+	RefID   string
+	Options mathexp.JoinOptions
+}
+
+// NewJoinCommand creates a new JoinCommand.
+func NewJoinCommand(refID, left, right string, opts mathexp.JoinOptions) (*JoinCommand, error) {
+	if opts.Type == "" {
+		opts.Type = mathexp.JoinInner
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &JoinCommand{
+		Left:    left,
+		Right:   right,
+		RefID:   refID,
+		Options: opts,
+	}, nil
+}
+
+// UnmarshalJoinCommand creates a JoinCommand from Grafana's frontend query.
+func UnmarshalJoinCommand(rn *rawNode) (*JoinCommand, error) {
+	q := JoinQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the join command: %w", err)
+	}
+	left := strings.TrimPrefix(q.Left, "$")
+	right := strings.TrimPrefix(q.Right, "$")
+	if left == "" || right == "" {
+		return nil, fmt.Errorf("join in refId %v requires both a left and a right variable", rn.RefID)
+	}
+	return NewJoinCommand(rn.RefID, left, right, mathexp.JoinOptions{
+		Type:     q.JoinType,
+		Operator: q.Operator,
+		On:       q.On,
+		Ignoring: q.Ignoring,
+		Fill:     q.Fill,
+	})
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (jc *JoinCommand) NeedsVars() []string {
+	return []string{jc.Left, jc.Right}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (jc *JoinCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteJoin")
+	defer span.End()
+	return mathexp.Join(jc.RefID, vars[jc.Left], vars[jc.Right], jc.Options)
+}
+
+func (jc *JoinCommand) Type() string {
+	return TypeJoin.String()
+}
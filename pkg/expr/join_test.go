@@ -0,0 +1,81 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestUnmarshalJoinCommand(t *testing.T) {
+	t.Run("defaults to an inner join", func(t *testing.T) {
+		cmd, err := UnmarshalJoinCommand(&rawNode{
+			RefID:    "C",
+			QueryRaw: []byte(`{"left": "$A", "right": "B", "operator": "/", "on": ["host"]}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &JoinCommand{
+			Left:  "A",
+			Right: "B",
+			RefID: "C",
+			Options: mathexp.JoinOptions{
+				Type:     mathexp.JoinInner,
+				Operator: "/",
+				On:       []string{"host"},
+			},
+		}, cmd)
+		require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+	})
+
+	t.Run("parses join type and fill", func(t *testing.T) {
+		cmd, err := UnmarshalJoinCommand(&rawNode{
+			RefID:    "C",
+			QueryRaw: []byte(`{"left": "A", "right": "B", "operator": "-", "joinType": "outer", "ignoring": ["job"], "fill": 0}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, mathexp.JoinOuter, cmd.Options.Type)
+		require.Equal(t, []string{"job"}, cmd.Options.Ignoring)
+		require.Equal(t, new(0.0), cmd.Options.Fill)
+	})
+
+	t.Run("missing right should error", func(t *testing.T) {
+		_, err := UnmarshalJoinCommand(&rawNode{
+			RefID:    "C",
+			QueryRaw: []byte(`{"left": "A", "operator": "+"}`),
+		})
+		require.ErrorContains(t, err, "requires both a left and a right variable")
+	})
+
+	t.Run("missing operator should error", func(t *testing.T) {
+		_, err := UnmarshalJoinCommand(&rawNode{
+			RefID:    "C",
+			QueryRaw: []byte(`{"left": "A", "right": "B"}`),
+		})
+		require.ErrorContains(t, err, "invalid join operator")
+	})
+}
+
+func TestJoinCommand_Execute(t *testing.T) {
+	cmd, err := NewJoinCommand("C", "A", "B", mathexp.JoinOptions{Operator: "+", On: []string{"host"}})
+	require.NoError(t, err)
+
+	a := mathexp.NewNumber("A", data.Labels{"host": "1", "source": "prometheus"})
+	a.SetValue(new(1.0))
+	b := mathexp.NewNumber("B", data.Labels{"host": "1", "source": "loki"})
+	b.SetValue(new(2.0))
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{a}},
+		"B": mathexp.Results{Values: mathexp.Values{b}},
+	}
+
+	res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+	require.Equal(t, data.Labels{"host": "1"}, res.Values[0].GetLabels())
+	require.Equal(t, new(3.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+}
@@ -0,0 +1,281 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// maxLibraryDepth is the maximum number of library expressions that can be nested
+// in each other.
+const maxLibraryDepth = 10
+
+// ErrLibraryExpressionNotFound is returned by an ExpressionLibrary when the
+// library expression does not exist in the organization.
+var ErrLibraryExpressionNotFound = errors.New("library expression not found")
+
+// ExpressionLibrary looks up the library expressions of an organization.
+type ExpressionLibrary interface {
+	// GetLibraryExpression returns the library expression with the given UID, or
+	// ErrLibraryExpressionNotFound if there is none.
+	GetLibraryExpression(ctx context.Context, orgID int64, uid string) (*LibraryExpression, error)
+}
+
+// LibraryExpression is a named, parameterized graph of expressions that is stored
+// once per organization and used by the library command.
+type LibraryExpression struct {
+	UID string
+	// Parameters are the names the expressions use to reference the inputs bound
+	// by the library command.
+	Parameters []string
+	// Expressions are the models of the expressions of the graph, keyed by refId.
+	Expressions map[string]json.RawMessage
+	// Output is the refId of the expression whose result is returned.
+	Output string
+}
+
+// LibraryCommand evaluates a library expression with its parameters bound to
+// queries or expressions of the request.
+//
+// The library expression is read and its graph is built when the pipeline is
+// built, so a pipeline that is built for every evaluation, like the one of an
+// alert rule, always uses the latest definition of the library expression.
+type LibraryCommand struct {
+	UID    string
+	RefID  string
+	Inputs map[string]string
+	Output string
+
+	// nodes are the expressions the output depends on, in execution order.
+	nodes []*CMDNode
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (lc *LibraryCommand) NeedsVars() []string {
+	return slices.Sorted(maps.Values(lc.Inputs))
+}
+
+// Execute runs the expressions of the library expression with the bound inputs
+// and returns the result of its output expression.
+func (lc *LibraryCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer, metrics *metrics.ExprMetrics) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteLibrary")
+	span.SetAttributes(attribute.String("library.uid", lc.UID))
+	defer span.End()
+
+	inner := make(mathexp.Vars, len(lc.Inputs)+len(lc.nodes))
+	for param, refID := range lc.Inputs {
+		inner[param] = vars[refID]
+	}
+
+	for _, node := range lc.nodes {
+		var depErr error
+		for _, neededVar := range node.NeedsVars() {
+			if inner[neededVar].Error != nil {
+				depErr = MakeDependencyError(node.RefID(), neededVar)
+				break
+			}
+		}
+		if depErr != nil {
+			inner[node.RefID()] = mathexp.Results{Error: depErr}
+			continue
+		}
+		res, err := node.Command.Execute(ctx, now, inner, tracer, metrics)
+		if err != nil {
+			res.Error = err
+		}
+		inner[node.RefID()] = res
+	}
+
+	res := inner[lc.Output]
+	if res.Error != nil {
+		return mathexp.Results{}, fmt.Errorf("library expression '%s' failed: %w", lc.UID, res.Error)
+	}
+	return res, nil
+}
+
+func (lc *LibraryCommand) Type() string {
+	return TypeLibrary.String()
+}
+
+// isLibraryNode returns true if the raw node is a library command.
+func isLibraryNode(rn *rawNode) bool {
+	commandType, err := GetExpressionCommandType(rn.Query)
+	return err == nil && commandType == TypeLibrary
+}
+
+// buildLibraryNode reads the library expression referenced by the raw node and
+// expands it into a LibraryCommand. path holds the UIDs of the library expressions
+// that are being expanded and is used to detect library expressions that reference
+// themselves, directly or through other library expressions.
+func (s *Service) buildLibraryNode(ctx context.Context, rn *rawNode, orgID int64, path []string) (*CMDNode, error) {
+	cmd, err := s.buildLibraryCommand(ctx, rn, orgID, path)
+	if err != nil {
+		return nil, MakeParseError(rn.RefID, err)
+	}
+	return &CMDNode{
+		baseNode: baseNode{
+			id:    rn.idx,
+			refID: rn.RefID,
+		},
+		CMDType: TypeLibrary,
+		Command: cmd,
+	}, nil
+}
+
+func (s *Service) buildLibraryCommand(ctx context.Context, rn *rawNode, orgID int64, path []string) (*LibraryCommand, error) {
+	q := LibraryQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the library command: %w", err)
+	}
+	if q.UID == "" {
+		return nil, fmt.Errorf("no library expression specified for refId %v", rn.RefID)
+	}
+	if s.library == nil {
+		return nil, fmt.Errorf("library expressions are not available")
+	}
+	chain := append(slices.Clone(path), q.UID)
+	if slices.Contains(path, q.UID) {
+		return nil, fmt.Errorf("library expression '%s' references itself: %s", q.UID, strings.Join(chain, " -> "))
+	}
+	if len(path) >= maxLibraryDepth {
+		return nil, fmt.Errorf("library expressions can not be nested more than %d levels deep: %s", maxLibraryDepth, strings.Join(chain, " -> "))
+	}
+
+	le, err := s.library.GetLibraryExpression(ctx, orgID, q.UID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library expression '%s': %w", q.UID, err)
+	}
+
+	inputs := make(map[string]string, len(q.Inputs))
+	for param, refID := range q.Inputs {
+		if !slices.Contains(le.Parameters, param) {
+			return nil, fmt.Errorf("library expression '%s' has no parameter '%s'", q.UID, param)
+		}
+		refID = strings.TrimPrefix(refID, "$")
+		if refID == "" {
+			return nil, fmt.Errorf("no variable specified for parameter '%s' of library expression '%s'", param, q.UID)
+		}
+		inputs[param] = refID
+	}
+	for _, param := range le.Parameters {
+		if _, ok := inputs[param]; !ok {
+			return nil, fmt.Errorf("parameter '%s' of library expression '%s' is not bound", param, q.UID)
+		}
+	}
+
+	nodes, err := s.buildLibraryGraph(ctx, rn, orgID, chain, le)
+	if err != nil {
+		return nil, fmt.Errorf("invalid library expression '%s': %w", q.UID, err)
+	}
+
+	return &LibraryCommand{
+		UID:    q.UID,
+		RefID:  rn.RefID,
+		Inputs: inputs,
+		Output: le.Output,
+		nodes:  nodes,
+	}, nil
+}
+
+// buildLibraryGraph builds the expressions of the library expression and returns
+// the ones the output depends on in execution order.
+func (s *Service) buildLibraryGraph(ctx context.Context, rn *rawNode, orgID int64, path []string, le *LibraryExpression) ([]*CMDNode, error) {
+	if _, ok := le.Expressions[le.Output]; !ok {
+		return nil, fmt.Errorf("output '%s' is not one of the expressions", le.Output)
+	}
+
+	refIDs := slices.Sorted(maps.Keys(le.Expressions))
+	dp := simple.NewDirectedGraph()
+	registry := make(map[string]*CMDNode, len(le.Expressions))
+	for i, refID := range refIDs {
+		if slices.Contains(le.Parameters, refID) {
+			return nil, fmt.Errorf("expression '%s' has the same name as a parameter", refID)
+		}
+		query := make(map[string]any)
+		if err := json.Unmarshal(le.Expressions[refID], &query); err != nil {
+			return nil, fmt.Errorf("failed to parse expression '%s': %w", refID, err)
+		}
+		inner := &rawNode{
+			Query:      query,
+			QueryRaw:   le.Expressions[refID],
+			RefID:      refID,
+			TimeRange:  rn.TimeRange,
+			DataSource: DataSourceModel(),
+			idx:        int64(i),
+		}
+
+		var node *CMDNode
+		var err error
+		if isLibraryNode(inner) {
+			node, err = s.buildLibraryNode(ctx, inner, orgID, path)
+		} else if isRuleStateNode(inner) {
+			node, err = s.buildRuleStateNode(inner, orgID)
+		} else {
+			node, err = buildCMDNode(ctx, inner, s.features, s.cfg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch node.CMDType {
+		case TypeSQL, TypeClassicConditions:
+			return nil, fmt.Errorf("%s expressions can not be used in a library expression, but '%s' is one", node.CMDType, refID)
+		}
+		registry[refID] = node
+		dp.AddNode(node)
+	}
+
+	for _, refID := range refIDs {
+		node := registry[refID]
+		for _, neededVar := range node.NeedsVars() {
+			if slices.Contains(le.Parameters, neededVar) {
+				continue
+			}
+			neededNode, ok := registry[neededVar]
+			if !ok {
+				return nil, MakeMissingDependentNodeError(node.RefID(), neededVar)
+			}
+			if neededNode.ID() == node.ID() {
+				return nil, fmt.Errorf("expression '%v' cannot reference itself", neededVar)
+			}
+			dp.SetEdge(dp.NewEdge(neededNode, node))
+		}
+	}
+
+	sorted, err := topo.SortStabilized(dp, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the expressions the output depends on are executed.
+	used := map[string]bool{le.Output: true}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		node := sorted[i].(*CMDNode)
+		if !used[node.RefID()] {
+			continue
+		}
+		for _, neededVar := range node.NeedsVars() {
+			used[neededVar] = true
+		}
+	}
+	nodes := make([]*CMDNode, 0, len(sorted))
+	for _, n := range sorted {
+		if node := n.(*CMDNode); used[node.RefID()] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
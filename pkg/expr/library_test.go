@@ -0,0 +1,220 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type fakeExpressionLibrary map[string]*LibraryExpression
+
+func (f fakeExpressionLibrary) GetLibraryExpression(_ context.Context, _ int64, uid string) (*LibraryExpression, error) {
+	le, ok := f[uid]
+	if !ok {
+		return nil, ErrLibraryExpressionNotFound
+	}
+	return le, nil
+}
+
+func libraryNode(refID, query string) *rawNode {
+	q := map[string]any{}
+	if err := json.Unmarshal([]byte(query), &q); err != nil {
+		panic(err)
+	}
+	return &rawNode{
+		RefID:      refID,
+		Query:      q,
+		QueryRaw:   []byte(query),
+		DataSource: DataSourceModel(),
+	}
+}
+
+func TestBuildLibraryNode(t *testing.T) {
+	library := fakeExpressionLibrary{
+		"double": {
+			UID:        "double",
+			Parameters: []string{"x"},
+			Expressions: map[string]json.RawMessage{
+				"out":    json.RawMessage(`{"type": "math", "expression": "$x * 2"}`),
+				"unused": json.RawMessage(`{"type": "math", "expression": "$x + 1"}`),
+			},
+			Output: "out",
+		},
+		"quadruple": {
+			UID:        "quadruple",
+			Parameters: []string{"y"},
+			Expressions: map[string]json.RawMessage{
+				"twice": json.RawMessage(`{"type": "library", "uid": "double", "inputs": {"x": "$y"}}`),
+				"out":   json.RawMessage(`{"type": "library", "uid": "double", "inputs": {"x": "$twice"}}`),
+			},
+			Output: "out",
+		},
+		"a": {
+			UID:         "a",
+			Expressions: map[string]json.RawMessage{"out": json.RawMessage(`{"type": "library", "uid": "b"}`)},
+			Output:      "out",
+		},
+		"b": {
+			UID:         "b",
+			Expressions: map[string]json.RawMessage{"out": json.RawMessage(`{"type": "library", "uid": "a"}`)},
+			Output:      "out",
+		},
+		"sql": {
+			UID:         "sql",
+			Expressions: map[string]json.RawMessage{"out": json.RawMessage(`{"type": "sql", "expression": "SELECT 1"}`)},
+			Output:      "out",
+		},
+		"missing-output": {
+			UID:         "missing-output",
+			Expressions: map[string]json.RawMessage{"out": json.RawMessage(`{"type": "math", "expression": "1"}`)},
+			Output:      "result",
+		},
+	}
+	s, _ := newMockQueryService(nil, nil)
+	s.library = library
+
+	execute := func(t *testing.T, node *CMDNode, vars mathexp.Vars) mathexp.Results {
+		t.Helper()
+		res, err := node.Command.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		return res
+	}
+	number := func(f float64) mathexp.Results {
+		n := mathexp.NewNumber("A", data.Labels{"host": "a"})
+		n.SetValue(new(f))
+		return mathexp.Results{Values: mathexp.Values{n}}
+	}
+
+	t.Run("binds the inputs and returns the output", func(t *testing.T) {
+		node, err := s.buildLibraryNode(t.Context(), libraryNode("B", `{"type": "library", "uid": "double", "inputs": {"x": "$A"}}`), 1, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"A"}, node.NeedsVars())
+
+		cmd := node.Command.(*LibraryCommand)
+		require.Len(t, cmd.nodes, 1, "expressions the output does not depend on should be pruned")
+
+		res := execute(t, node, mathexp.Vars{"A": number(3)})
+		require.Len(t, res.Values, 1)
+		require.Equal(t, new(6.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Equal(t, data.Labels{"host": "a"}, res.Values[0].GetLabels())
+	})
+
+	t.Run("expands nested library expressions", func(t *testing.T) {
+		node, err := s.buildLibraryNode(t.Context(), libraryNode("B", `{"type": "library", "uid": "quadruple", "inputs": {"y": "A"}}`), 1, nil)
+		require.NoError(t, err)
+
+		res := execute(t, node, mathexp.Vars{"A": number(3)})
+		require.Equal(t, new(12.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("fails when an input failed", func(t *testing.T) {
+		node, err := s.buildLibraryNode(t.Context(), libraryNode("B", `{"type": "library", "uid": "double", "inputs": {"x": "$A"}}`), 1, nil)
+		require.NoError(t, err)
+
+		_, err = node.Command.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Error: ErrSeriesMustBeWide}}, tracing.InitializeTracerForTest(), nil)
+		require.ErrorContains(t, err, "library expression 'double' failed")
+	})
+
+	testCases := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{
+			name:  "unknown library expression",
+			query: `{"type": "library", "uid": "triple", "inputs": {"x": "$A"}}`,
+			err:   "library expression not found",
+		},
+		{
+			name:  "missing uid",
+			query: `{"type": "library", "inputs": {"x": "$A"}}`,
+			err:   "no library expression specified",
+		},
+		{
+			name:  "unbound parameter",
+			query: `{"type": "library", "uid": "double"}`,
+			err:   "parameter 'x' of library expression 'double' is not bound",
+		},
+		{
+			name:  "unknown parameter",
+			query: `{"type": "library", "uid": "double", "inputs": {"x": "$A", "z": "$A"}}`,
+			err:   "library expression 'double' has no parameter 'z'",
+		},
+		{
+			name:  "empty input",
+			query: `{"type": "library", "uid": "double", "inputs": {"x": "$"}}`,
+			err:   "no variable specified for parameter 'x'",
+		},
+		{
+			name:  "library expressions that reference each other",
+			query: `{"type": "library", "uid": "a"}`,
+			err:   "references itself: a -> b -> a",
+		},
+		{
+			name:  "sql expression in the library expression",
+			query: `{"type": "library", "uid": "sql"}`,
+			err:   "sql expressions can not be used in a library expression",
+		},
+		{
+			name:  "output is not an expression",
+			query: `{"type": "library", "uid": "missing-output"}`,
+			err:   "output 'result' is not one of the expressions",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.buildLibraryNode(t.Context(), libraryNode("B", tc.query), 1, nil)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	t.Run("fails without a library", func(t *testing.T) {
+		s, _ := newMockQueryService(nil, nil)
+		_, err := s.buildLibraryNode(t.Context(), libraryNode("B", `{"type": "library", "uid": "double", "inputs": {"x": "$A"}}`), 1, nil)
+		require.ErrorContains(t, err, "library expressions are not available")
+	})
+}
+
+func TestLibraryExpressionPipeline(t *testing.T) {
+	queries := []Query{
+		{
+			RefID:      "A",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "21" }`),
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "library", "uid": "double", "inputs": {"x": "$A"} }`),
+		},
+	}
+	s, req := newMockQueryService(nil, queries)
+	s.library = fakeExpressionLibrary{
+		"double": {
+			UID:         "double",
+			Parameters:  []string{"x"},
+			Expressions: map[string]json.RawMessage{"out": json.RawMessage(`{"type": "math", "expression": "$x * 2"}`)},
+			Output:      "out",
+		},
+	}
+
+	pl, err := s.BuildPipeline(t.Context(), req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, getRefIDOrder(pl))
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["B"].Error)
+	require.Len(t, res.Responses["B"].Frames, 1)
+	require.Equal(t, "B", res.Responses["B"].Frames[0].RefID)
+	v, err := res.Responses["B"].Frames[0].FloatAt(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 42.0, v)
+}
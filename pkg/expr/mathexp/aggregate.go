@@ -0,0 +1,274 @@
+package mathexp
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The aggregation operation
+// +enum
+type AggregateOperation string
+
+const (
+	// Sum of the values in each group
+	AggregateSum AggregateOperation = "sum"
+
+	// Average of the values in each group
+	AggregateAvg AggregateOperation = "avg"
+
+	// Smallest value in each group
+	AggregateMin AggregateOperation = "min"
+
+	// Largest value in each group
+	AggregateMax AggregateOperation = "max"
+
+	// Number of non-null values in each group
+	AggregateCount AggregateOperation = "count"
+
+	// The k largest values in each group, keeping their labels
+	AggregateTopK AggregateOperation = "topk"
+
+	// The k smallest values in each group, keeping their labels
+	AggregateBottomK AggregateOperation = "bottomk"
+)
+
+// IsSelector returns true if the operation selects values from the group
+// rather than combining them into a single value.
+func (op AggregateOperation) IsSelector() bool {
+	return op == AggregateTopK || op == AggregateBottomK
+}
+
+// ValidateAggregate returns an error if the operation is unknown or k is invalid for it.
+func ValidateAggregate(op AggregateOperation, k int) error {
+	switch op {
+	case AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount:
+		return nil
+	case AggregateTopK, AggregateBottomK:
+		if k <= 0 {
+			return fmt.Errorf("%s requires k to be greater than 0, got %d", op, k)
+		}
+		return nil
+	default:
+		return fmt.Errorf("aggregate operation '%s' is not supported", op)
+	}
+}
+
+// Aggregate groups the values of res by the given labels and combines the values of
+// each group with op. The result of sum, avg, min, max and count has a value per group
+// labelled with the grouping labels. The result of topk and bottomk keeps the k
+// largest or smallest values of each group with their original labels.
+//
+// Numbers are aggregated by value. Series are aggregated point by point over the union
+// of their timestamps, so topk and bottomk select the series per timestamp and series
+// that are never selected are dropped. Null and NaN values are ignored. NoData values
+// are ignored, and if there is nothing else to aggregate NoData is returned. The input
+// must not mix Series and Numbers.
+func Aggregate(refID string, res Results, op AggregateOperation, by []string, k int) (Results, error) {
+	if err := ValidateAggregate(op, k); err != nil {
+		return Results{}, err
+	}
+
+	var numbers []Number
+	var series []Series
+	for _, val := range res.Values {
+		switch v := val.(type) {
+		case Number:
+			numbers = append(numbers, v)
+		case Series:
+			series = append(series, v)
+		case NoData:
+		default:
+			return Results{}, fmt.Errorf("can only aggregate numbers or series, got type %v", val.Type())
+		}
+	}
+	if len(numbers) > 0 && len(series) > 0 {
+		return Results{}, fmt.Errorf("can not aggregate a mix of numbers and series")
+	}
+
+	newRes := Results{}
+	switch {
+	case len(numbers) > 0:
+		for _, group := range groupBy(numbers, by) {
+			newRes.Values = append(newRes.Values, aggregateNumbers(refID, group, op, k)...)
+		}
+	case len(series) > 0:
+		for _, group := range groupBy(series, by) {
+			newRes.Values = append(newRes.Values, aggregateSeries(refID, group, op, k)...)
+		}
+	default:
+		newRes.Values = append(newRes.Values, NewNoData())
+	}
+	return newRes, nil
+}
+
+type valueGroup[T Value] struct {
+	labels data.Labels
+	values []T
+}
+
+// groupBy groups the values by the values of the given labels, in the order in
+// which the groups are first seen.
+func groupBy[T Value](values []T, by []string) []*valueGroup[T] {
+	var groups []*valueGroup[T]
+	index := map[string]*valueGroup[T]{}
+	for _, v := range values {
+		labels := selectLabels(v.GetLabels(), by)
+		key := labels.String()
+		g, ok := index[key]
+		if !ok {
+			g = &valueGroup[T]{labels: labels}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.values = append(g.values, v)
+	}
+	return groups
+}
+
+// selectLabels returns the labels with one of the given names.
+func selectLabels(labels data.Labels, names []string) data.Labels {
+	selected := data.Labels{}
+	for _, name := range names {
+		if v, ok := labels[name]; ok {
+			selected[name] = v
+		}
+	}
+	return selected
+}
+
+func aggregateNumbers(refID string, group *valueGroup[Number], op AggregateOperation, k int) []Value {
+	if op.IsSelector() {
+		type ranked struct {
+			n Number
+			f float64
+		}
+		var candidates []ranked
+		for _, n := range group.values {
+			if f := n.GetFloat64Value(); f != nil && !math.IsNaN(*f) {
+				candidates = append(candidates, ranked{n: n, f: *f})
+			}
+		}
+		slices.SortStableFunc(candidates, func(a, b ranked) int {
+			if op == AggregateTopK {
+				return cmp.Compare(b.f, a.f)
+			}
+			return cmp.Compare(a.f, b.f)
+		})
+		selected := make([]Value, 0, min(k, len(candidates)))
+		for _, c := range candidates[:min(k, len(candidates))] {
+			n := NewNumber(refID, c.n.GetLabels().Copy())
+			n.SetValue(new(c.f))
+			selected = append(selected, n)
+		}
+		return selected
+	}
+
+	values := make([]float64, 0, len(group.values))
+	for _, n := range group.values {
+		if f := n.GetFloat64Value(); f != nil && !math.IsNaN(*f) {
+			values = append(values, *f)
+		}
+	}
+	n := NewNumber(refID, group.labels)
+	n.SetValue(aggregateValues(op, values))
+	return []Value{n}
+}
+
+func aggregateSeries(refID string, group *valueGroup[Series], op AggregateOperation, k int) []Value {
+	// Collect the valid values of each series at every timestamp.
+	type point struct {
+		t      time.Time
+		values []float64
+		series []int
+	}
+	var points []*point
+	byTime := map[int64]*point{}
+	for i, s := range group.values {
+		for j := range s.Len() {
+			t, f := s.GetPoint(j)
+			p, ok := byTime[t.UnixNano()]
+			if !ok {
+				p = &point{t: t}
+				byTime[t.UnixNano()] = p
+				points = append(points, p)
+			}
+			if f != nil && !math.IsNaN(*f) {
+				p.values = append(p.values, *f)
+				p.series = append(p.series, i)
+			}
+		}
+	}
+	slices.SortFunc(points, func(a, b *point) int { return a.t.Compare(b.t) })
+
+	if !op.IsSelector() {
+		newSeries := NewSeries(refID, group.labels, len(points))
+		for i, p := range points {
+			newSeries.SetPoint(i, p.t, aggregateValues(op, p.values))
+		}
+		return []Value{newSeries}
+	}
+
+	selected := make([]Series, len(group.values))
+	used := make([]bool, len(group.values))
+	for i, s := range group.values {
+		selected[i] = NewSeries(refID, s.GetLabels().Copy(), len(points))
+	}
+	for pi, p := range points {
+		order := make([]int, len(p.values))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			if op == AggregateTopK {
+				return cmp.Compare(p.values[b], p.values[a])
+			}
+			return cmp.Compare(p.values[a], p.values[b])
+		})
+		for i := range selected {
+			selected[i].SetPoint(pi, p.t, nil)
+		}
+		for _, idx := range order[:min(k, len(order))] {
+			si := p.series[idx]
+			selected[si].SetPoint(pi, p.t, new(p.values[idx]))
+			used[si] = true
+		}
+	}
+	newValues := make([]Value, 0, len(selected))
+	for i, s := range selected {
+		if used[i] {
+			newValues = append(newValues, s)
+		}
+	}
+	return newValues
+}
+
+// aggregateValues combines the values with one of the non-selector operations.
+// It returns nil if there are no values, except for count.
+func aggregateValues(op AggregateOperation, values []float64) *float64 {
+	if op == AggregateCount {
+		return new(float64(len(values)))
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	var f float64
+	switch op {
+	case AggregateSum, AggregateAvg:
+		for _, v := range values {
+			f += v
+		}
+		if op == AggregateAvg {
+			f /= float64(len(values))
+		}
+	case AggregateMin:
+		f = slices.Min(values)
+	case AggregateMax:
+		f = slices.Max(values)
+	}
+	return &f
+}
@@ -0,0 +1,164 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	numbers := resultValuesNoErr(
+		makeNumber("", data.Labels{"cluster": "a", "host": "1"}, new(1.0)),
+		makeNumber("", data.Labels{"cluster": "a", "host": "2"}, new(4.0)),
+		makeNumber("", data.Labels{"cluster": "b", "host": "3"}, new(2.0)),
+		makeNumber("", data.Labels{"cluster": "a", "host": "4"}, nil),
+		makeNumber("", data.Labels{"cluster": "b", "host": "5"}, new(3.0)),
+	)
+	series := resultValuesNoErr(
+		makeSeries("", data.Labels{"cluster": "a", "host": "1"},
+			tp{time.Unix(0, 0), new(1.0)},
+			tp{time.Unix(10, 0), new(5.0)},
+		),
+		makeSeries("", data.Labels{"cluster": "a", "host": "2"},
+			tp{time.Unix(10, 0), new(2.0)},
+			tp{time.Unix(20, 0), nil},
+		),
+		makeSeries("", data.Labels{"cluster": "b", "host": "3"},
+			tp{time.Unix(0, 0), new(7.0)},
+		),
+	)
+
+	tests := []struct {
+		name     string
+		input    Results
+		op       AggregateOperation
+		by       []string
+		k        int
+		expected Results
+		errorIs  string
+	}{
+		{
+			name:  "sum by cluster",
+			input: numbers,
+			op:    AggregateSum,
+			by:    []string{"cluster"},
+			expected: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "a"}, new(5.0)),
+				makeNumber("B", data.Labels{"cluster": "b"}, new(5.0)),
+			),
+		},
+		{
+			name:  "avg of everything",
+			input: numbers,
+			op:    AggregateAvg,
+			expected: resultValuesNoErr(
+				makeNumber("B", data.Labels{}, new(2.5)),
+			),
+		},
+		{
+			name:  "count by cluster ignores nulls",
+			input: numbers,
+			op:    AggregateCount,
+			by:    []string{"cluster"},
+			expected: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "a"}, new(2.0)),
+				makeNumber("B", data.Labels{"cluster": "b"}, new(2.0)),
+			),
+		},
+		{
+			name:  "topk keeps the labels",
+			input: numbers,
+			op:    AggregateTopK,
+			by:    []string{"cluster"},
+			k:     1,
+			expected: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "a", "host": "2"}, new(4.0)),
+				makeNumber("B", data.Labels{"cluster": "b", "host": "5"}, new(3.0)),
+			),
+		},
+		{
+			name:  "bottomk",
+			input: numbers,
+			op:    AggregateBottomK,
+			k:     2,
+			expected: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "a", "host": "1"}, new(1.0)),
+				makeNumber("B", data.Labels{"cluster": "b", "host": "3"}, new(2.0)),
+			),
+		},
+		{
+			name:  "max of series by cluster over the union of timestamps",
+			input: series,
+			op:    AggregateMax,
+			by:    []string{"cluster"},
+			expected: resultValuesNoErr(
+				makeSeries("B", data.Labels{"cluster": "a"},
+					tp{time.Unix(0, 0), new(1.0)},
+					tp{time.Unix(10, 0), new(5.0)},
+					tp{time.Unix(20, 0), nil},
+				),
+				makeSeries("B", data.Labels{"cluster": "b"},
+					tp{time.Unix(0, 0), new(7.0)},
+				),
+			),
+		},
+		{
+			name:  "bottomk of series per timestamp",
+			input: series,
+			op:    AggregateBottomK,
+			k:     1,
+			expected: resultValuesNoErr(
+				makeSeries("B", data.Labels{"cluster": "a", "host": "1"},
+					tp{time.Unix(0, 0), new(1.0)},
+					tp{time.Unix(10, 0), nil},
+					tp{time.Unix(20, 0), nil},
+				),
+				makeSeries("B", data.Labels{"cluster": "a", "host": "2"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), new(2.0)},
+					tp{time.Unix(20, 0), nil},
+				),
+			),
+		},
+		{
+			name:     "no data",
+			input:    resultValuesNoErr(NewNoData()),
+			op:       AggregateSum,
+			expected: resultValuesNoErr(NewNoData()),
+		},
+		{
+			name: "mix of numbers and series should error",
+			input: resultValuesNoErr(
+				makeNumber("", nil, new(1.0)),
+				makeSeries("", nil, tp{time.Unix(0, 0), new(1.0)}),
+			),
+			op:      AggregateSum,
+			errorIs: "mix of numbers and series",
+		},
+		{
+			name:    "topk without k should error",
+			input:   numbers,
+			op:      AggregateTopK,
+			errorIs: "requires k",
+		},
+		{
+			name:    "unknown operation should error",
+			input:   numbers,
+			op:      "median",
+			errorIs: "not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Aggregate("B", tt.input, tt.op, tt.by, tt.k)
+			if tt.errorIs != "" {
+				require.ErrorContains(t, err, tt.errorIs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, res)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultForecastAlpha is the default smoothing factor of the level.
+	DefaultForecastAlpha = 0.3
+	// DefaultForecastBeta is the default smoothing factor of the trend.
+	DefaultForecastBeta = 0.1
+	// DefaultForecastGamma is the default smoothing factor of the seasonal component.
+	DefaultForecastGamma = 0.1
+	// DefaultForecastDeviations is the default width of the band in standard deviations.
+	DefaultForecastDeviations = 3.0
+)
+
+// ForecastOptions configures Series.Forecast.
+type ForecastOptions struct {
+	// Alpha, Beta and Gamma are the smoothing factors of the level, trend and
+	// seasonal component of the model. They must be in the range [0, 1].
+	Alpha float64
+	Beta  float64
+	Gamma float64
+
+	// Season is the length of a season, for example 24h for a daily pattern.
+	// When zero, only the level and trend are modelled.
+	Season time.Duration
+
+	// Horizon is how far past the last point of the series to forecast.
+	Horizon time.Duration
+
+	// Deviations is the width of the band in standard deviations of the
+	// one step prediction errors.
+	Deviations float64
+}
+
+// DefaultForecastOptions returns the options used when none are configured.
+func DefaultForecastOptions() ForecastOptions {
+	return ForecastOptions{
+		Alpha:      DefaultForecastAlpha,
+		Beta:       DefaultForecastBeta,
+		Gamma:      DefaultForecastGamma,
+		Deviations: DefaultForecastDeviations,
+	}
+}
+
+// Validate returns an error if the options cannot be used to fit a model.
+func (o ForecastOptions) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value float64
+	}{{"alpha", o.Alpha}, {"beta", o.Beta}, {"gamma", o.Gamma}} {
+		if math.IsNaN(f.value) || f.value < 0 || f.value > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", f.name, f.value)
+		}
+	}
+	if math.IsNaN(o.Deviations) || o.Deviations <= 0 {
+		return fmt.Errorf("deviations must be greater than 0, got %v", o.Deviations)
+	}
+	if o.Season < 0 {
+		return fmt.Errorf("seasonality must not be negative, got %v", o.Season)
+	}
+	if o.Horizon < 0 {
+		return fmt.Errorf("horizon must not be negative, got %v", o.Horizon)
+	}
+	return nil
+}
+
+// Forecast is the result of Series.Forecast.
+type Forecast struct {
+	// Predicted holds the one step ahead prediction for every point of the series,
+	// followed by the predictions for the horizon.
+	Predicted Series
+	// Upper and Lower are the bounds of the band around Predicted.
+	Upper Series
+	Lower Series
+	// IsAnomalous is 1 when the last point of the series is outside the band and 0 when
+	// it is inside. It is nil when the last point is null or there is not enough data.
+	IsAnomalous *float64
+	// Notice is set when the model had to fall back to a simpler one.
+	Notice string
+}
+
+// Forecast fits an additive Holt-Winters model to the series and returns the one step
+// ahead predictions with a band of opts.Deviations standard deviations of the prediction
+// errors around them. The season length in points is derived from opts.Season and the
+// median interval between the points. If the series holds fewer than two seasons, the
+// seasonal component is dropped.
+//
+// The standard deviation of the errors does not include the last point so that an
+// outlier at the end of the series does not widen the band it is checked against.
+// Null and NaN points do not update the model but still get a prediction. Series with
+// fewer than three points get null predictions.
+func (s Series) Forecast(refID string, opts ForecastOptions) (Forecast, error) {
+	if err := opts.Validate(); err != nil {
+		return Forecast{}, err
+	}
+	s = sortedSeriesCopy(refID, s)
+	n := s.Len()
+
+	step := medianStep(s)
+	horizon := 0
+	if step > 0 {
+		horizon = int(opts.Horizon / step)
+	}
+	if horizon > MaxNewSeriesLength {
+		return Forecast{}, ErrNewSeriesLengthTooLong{newSeriesLength: horizon}
+	}
+
+	fc := Forecast{
+		Predicted: NewSeries(refID, s.GetLabels(), 0),
+		Upper:     NewSeries(refID, s.GetLabels(), 0),
+		Lower:     NewSeries(refID, s.GetLabels(), 0),
+	}
+	if n < 3 || step <= 0 {
+		for i := range n {
+			fc.Predicted.AppendPoint(s.GetTime(i), nil)
+			fc.Upper.AppendPoint(s.GetTime(i), nil)
+			fc.Lower.AppendPoint(s.GetTime(i), nil)
+		}
+		return fc, nil
+	}
+
+	m := 0
+	if opts.Season > 0 {
+		m = int(math.Round(float64(opts.Season) / float64(step)))
+		if m < 2 || n < 2*m {
+			fc.Notice = fmt.Sprintf("not enough data for a seasonality of %v, at least two seasons are required; only the level and trend are modelled", opts.Season)
+			m = 0
+		}
+	}
+
+	value := func(i int) (float64, bool) {
+		f := s.GetValue(i)
+		if f == nil || math.IsNaN(*f) {
+			return 0, false
+		}
+		return *f, true
+	}
+
+	var level, trend float64
+	seasonal := make([]float64, max(m, 1))
+	start := 0
+	if m > 0 {
+		// Initialise from the first two seasons and start predicting with the second.
+		first, firstOK := meanOf(value, 0, m)
+		second, secondOK := meanOf(value, m, 2*m)
+		if !firstOK {
+			first = 0
+		}
+		level = first
+		if firstOK && secondOK {
+			trend = (second - first) / float64(m)
+		}
+		for i := range m {
+			if v, ok := value(i); ok {
+				seasonal[i] = v - first
+			}
+		}
+		start = m
+	} else {
+		// Initialise the level with the first valid point and start predicting after it.
+		for start < n {
+			v, ok := value(start)
+			start++
+			if ok {
+				level = v
+				break
+			}
+		}
+	}
+
+	predictions := make([]*float64, n)
+	residuals := make([]float64, 0, n)
+	for i := start; i < n; i++ {
+		si := 0
+		if m > 0 {
+			si = i % m
+		}
+		pred := level + trend + seasonal[si]
+		predictions[i] = &pred
+
+		v, ok := value(i)
+		if !ok {
+			level += trend
+			continue
+		}
+		if i < n-1 {
+			residuals = append(residuals, v-pred)
+		}
+		prevLevel := level
+		level = opts.Alpha*(v-seasonal[si]) + (1-opts.Alpha)*(prevLevel+trend)
+		trend = opts.Beta*(level-prevLevel) + (1-opts.Beta)*trend
+		if m > 0 {
+			seasonal[si] = opts.Gamma*(v-level) + (1-opts.Gamma)*seasonal[si]
+		}
+	}
+
+	var band *float64
+	if len(residuals) > 0 {
+		sumSq := float64(0)
+		for _, r := range residuals {
+			sumSq += r * r
+		}
+		b := opts.Deviations * math.Sqrt(sumSq/float64(len(residuals)))
+		band = &b
+	}
+
+	appendPoint := func(t time.Time, pred *float64) {
+		fc.Predicted.AppendPoint(t, pred)
+		if pred == nil || band == nil {
+			fc.Upper.AppendPoint(t, nil)
+			fc.Lower.AppendPoint(t, nil)
+			return
+		}
+		fc.Upper.AppendPoint(t, new(*pred+*band))
+		fc.Lower.AppendPoint(t, new(*pred-*band))
+	}
+	for i := range n {
+		appendPoint(s.GetTime(i), predictions[i])
+	}
+	last := s.GetTime(n - 1)
+	for k := 1; k <= horizon; k++ {
+		si := 0
+		if m > 0 {
+			si = (n - 1 + k) % m
+		}
+		appendPoint(last.Add(time.Duration(k)*step), new(level+float64(k)*trend+seasonal[si]))
+	}
+
+	if v, ok := value(n - 1); ok && predictions[n-1] != nil && band != nil {
+		if math.Abs(v-*predictions[n-1]) > *band {
+			fc.IsAnomalous = new(1.0)
+		} else {
+			fc.IsAnomalous = new(0.0)
+		}
+	}
+	return fc, nil
+}
+
+// medianStep returns the median of the intervals between the points of a time sorted series.
+func medianStep(s Series) time.Duration {
+	steps := make([]time.Duration, 0, s.Len())
+	for i := 1; i < s.Len(); i++ {
+		if d := s.GetTime(i).Sub(s.GetTime(i - 1)); d > 0 {
+			steps = append(steps, d)
+		}
+	}
+	if len(steps) == 0 {
+		return 0
+	}
+	slices.Sort(steps)
+	return steps[len(steps)/2]
+}
+
+// meanOf returns the mean of the valid values in [from, to).
+func meanOf(value func(int) (float64, bool), from, to int) (float64, bool) {
+	sum := float64(0)
+	count := 0
+	for i := from; i < to; i++ {
+		if v, ok := value(i); ok {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
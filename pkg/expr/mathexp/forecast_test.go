@@ -0,0 +1,114 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+// seriesOf returns a series with one point every 10 seconds.
+func seriesOf(values ...*float64) Series {
+	s := NewSeries("", data.Labels{"host": "a"}, len(values))
+	for i, v := range values {
+		s.SetPoint(i, time.Unix(int64(i*10), 0), v)
+	}
+	return s
+}
+
+func requireSeriesValues(t *testing.T, expected []*float64, s Series) {
+	t.Helper()
+	require.Equal(t, len(expected), s.Len())
+	for i, e := range expected {
+		v := s.GetValue(i)
+		if e == nil {
+			require.Nil(t, v, "point %d", i)
+			continue
+		}
+		require.NotNil(t, v, "point %d", i)
+		require.InDelta(t, *e, *v, 1e-9, "point %d", i)
+	}
+}
+
+func TestSeriesForecast(t *testing.T) {
+	t.Run("seasonal series is predicted exactly", func(t *testing.T) {
+		s := seriesOf(new(1.0), new(5.0), new(1.0), new(5.0), new(1.0), new(5.0), new(1.0), new(5.0))
+		opts := DefaultForecastOptions()
+		opts.Season = 20 * time.Second
+		opts.Horizon = 20 * time.Second
+
+		fc, err := s.Forecast("B", opts)
+		require.NoError(t, err)
+		require.Empty(t, fc.Notice)
+
+		expected := []*float64{nil, nil, new(1.0), new(5.0), new(1.0), new(5.0), new(1.0), new(5.0), new(1.0), new(5.0)}
+		requireSeriesValues(t, expected, fc.Predicted)
+		requireSeriesValues(t, expected, fc.Upper)
+		requireSeriesValues(t, expected, fc.Lower)
+		require.Equal(t, time.Unix(90, 0), fc.Predicted.GetTime(9))
+		require.Equal(t, data.Labels{"host": "a"}, fc.Predicted.GetLabels())
+		require.Equal(t, new(0.0), fc.IsAnomalous)
+	})
+
+	t.Run("last point outside of the band is anomalous", func(t *testing.T) {
+		s := seriesOf(new(5.0), new(6.0), new(5.0), new(6.0), new(5.0), new(6.0), new(50.0))
+		fc, err := s.Forecast("B", DefaultForecastOptions())
+		require.NoError(t, err)
+		require.Equal(t, new(1.0), fc.IsAnomalous)
+		require.Nil(t, fc.Predicted.GetValue(0))
+		require.InDelta(t, 5.0, *fc.Predicted.GetValue(1), 1e-9)
+		require.Greater(t, *fc.Upper.GetValue(3), *fc.Predicted.GetValue(3))
+		require.Less(t, *fc.Lower.GetValue(3), *fc.Predicted.GetValue(3))
+	})
+
+	t.Run("last point inside of the band is not anomalous", func(t *testing.T) {
+		s := seriesOf(new(5.0), new(6.0), new(5.0), new(6.0), new(5.0), new(6.0), new(5.5))
+		fc, err := s.Forecast("B", DefaultForecastOptions())
+		require.NoError(t, err)
+		require.Equal(t, new(0.0), fc.IsAnomalous)
+	})
+
+	t.Run("null points are predicted but do not update the model", func(t *testing.T) {
+		s := seriesOf(new(2.0), new(2.0), nil, new(2.0), nil)
+		fc, err := s.Forecast("B", DefaultForecastOptions())
+		require.NoError(t, err)
+		requireSeriesValues(t, []*float64{nil, new(2.0), new(2.0), new(2.0), new(2.0)}, fc.Predicted)
+		require.Nil(t, fc.IsAnomalous)
+	})
+
+	t.Run("falls back to the trend model without two seasons of data", func(t *testing.T) {
+		s := seriesOf(new(1.0), new(2.0), new(3.0), new(4.0))
+		opts := DefaultForecastOptions()
+		opts.Season = time.Hour
+		fc, err := s.Forecast("B", opts)
+		require.NoError(t, err)
+		require.NotEmpty(t, fc.Notice)
+		require.Equal(t, 4, fc.Predicted.Len())
+		require.NotNil(t, fc.Predicted.GetValue(1))
+	})
+
+	t.Run("too few points give null predictions", func(t *testing.T) {
+		s := seriesOf(new(1.0), new(2.0))
+		fc, err := s.Forecast("B", DefaultForecastOptions())
+		require.NoError(t, err)
+		requireSeriesValues(t, []*float64{nil, nil}, fc.Predicted)
+		requireSeriesValues(t, []*float64{nil, nil}, fc.Upper)
+		require.Nil(t, fc.IsAnomalous)
+	})
+
+	t.Run("invalid options should error", func(t *testing.T) {
+		s := seriesOf(new(1.0), new(2.0), new(3.0))
+		for name, opts := range map[string]ForecastOptions{
+			"alpha":      {Alpha: 2, Deviations: 3},
+			"gamma":      {Gamma: -1, Deviations: 3},
+			"deviations": {},
+			"horizon":    {Deviations: 3, Horizon: -time.Second},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := s.Forecast("B", opts)
+				require.ErrorContains(t, err, name)
+			})
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// The functions in this file operate on a whole Series rather than on each point
+// independently, so they require the points to be ordered by time. Every function
+// works on a time sorted copy of its input and keeps the labels of the input series,
+// so the results can be combined with other results using the usual union rules.
+//
+// Unless stated otherwise, points with a null or NaN value are not used when computing
+// the value of other points.
+
+// delta returns the difference between each point and the previous non-null point of each series.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) (Series, error) {
+		return seriesDiff(e.RefID, s, false, false), nil
+	})
+}
+
+// increase is like delta, but treats decreasing values as counter resets, in which case
+// the value of the point after the reset is used as the increase.
+func increase(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "increase", varSet, func(s Series) (Series, error) {
+		return seriesDiff(e.RefID, s, true, false), nil
+	})
+}
+
+// rate returns the per-second increase between each point and the previous non-null point
+// of each series. Like increase, decreasing values are treated as counter resets.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) (Series, error) {
+		return seriesDiff(e.RefID, s, true, true), nil
+	})
+}
+
+// cumsum returns the running total of each series. Null and NaN points are kept as they are
+// and are not added to the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		total := float64(0)
+		for i := range s.Len() {
+			t, f := s.GetPoint(i)
+			if f == nil || math.IsNaN(*f) {
+				newSeries.SetPoint(i, t, copyFloat(f))
+				continue
+			}
+			total += *f
+			nF := total
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries, nil
+	})
+}
+
+// movingAvg returns the average of the points within the trailing window of each point.
+func movingAvg(e *State, varSet Results, window string) (Results, error) {
+	w, err := parseWindow(window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) (Series, error) {
+		return movingWindow(e.RefID, s, w, func(sum float64, count int) float64 {
+			return sum / float64(count)
+		}), nil
+	})
+}
+
+// movingSum returns the sum of the points within the trailing window of each point.
+func movingSum(e *State, varSet Results, window string) (Results, error) {
+	w, err := parseWindow(window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_sum", varSet, func(s Series) (Series, error) {
+		return movingWindow(e.RefID, s, w, func(sum float64, _ int) float64 {
+			return sum
+		}), nil
+	})
+}
+
+// ewma returns the exponentially weighted moving average of each series using the smoothing
+// factor alpha, which must be in the range (0, 1]. Null and NaN points are kept as they are
+// and do not change the average.
+func ewma(e *State, varSet Results, alphaRes Results) (Results, error) {
+	alpha, err := scalarArg("ewma", alphaRes)
+	if err != nil {
+		return Results{}, err
+	}
+	if err := validateAlpha(alpha); err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "ewma", varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var avg *float64
+		for i := range s.Len() {
+			t, f := s.GetPoint(i)
+			if f == nil || math.IsNaN(*f) {
+				newSeries.SetPoint(i, t, copyFloat(f))
+				continue
+			}
+			nF := *f
+			if avg != nil {
+				nF = alpha*(*f) + (1-alpha)*(*avg)
+			}
+			avg = &nF
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries, nil
+	})
+}
+
+// timeShift moves every point of each series by the given duration. A positive duration moves
+// the points into the future, so that for example `$A - time_shift($A, "1d")` compares
+// each point with the value from the day before.
+func timeShift(e *State, varSet Results, duration string) (Results, error) {
+	d, err := gtime.ParseDuration(duration)
+	if err != nil {
+		return Results{}, fmt.Errorf("time_shift: failed to parse duration %q: %w", duration, err)
+	}
+	return perSeries(e, "time_shift", varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := range s.Len() {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), copyFloat(f))
+		}
+		return newSeries, nil
+	})
+}
+
+// perSeries calls seriesF with a time sorted copy of each Series in varSet.
+// NoData values are passed through unchanged. Other value types return an error
+// since they have no points to operate over.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) (Series, error)) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newSeries, err := seriesF(sortedSeriesCopy(e.RefID, v))
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, newSeries)
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series but got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// seriesDiff returns, for each point, the difference to the last non-null point before it.
+// When counter is true, a decrease is considered a counter reset and the value of the point is
+// used as the difference. When perSecond is true, the difference is divided by the number of
+// seconds between the two points.
+// The first non-null point has a null value in the result, null and NaN points are kept as they are.
+func seriesDiff(refID string, s Series, counter, perSecond bool) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	var prevTime time.Time
+	var prev *float64
+	for i := range s.Len() {
+		t, f := s.GetPoint(i)
+		if f == nil || math.IsNaN(*f) {
+			newSeries.SetPoint(i, t, copyFloat(f))
+			continue
+		}
+		if prev == nil {
+			newSeries.SetPoint(i, t, nil)
+			prev, prevTime = f, t
+			continue
+		}
+		nF := *f - *prev
+		if counter && nF < 0 {
+			nF = *f
+		}
+		if perSecond {
+			secs := t.Sub(prevTime).Seconds()
+			if secs <= 0 {
+				// duplicate timestamp, the rate is not defined
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			nF /= secs
+		}
+		newSeries.SetPoint(i, t, &nF)
+		prev, prevTime = f, t
+	}
+	return newSeries
+}
+
+// movingWindow calls aggF with the sum and count of the non-null and non-NaN points in
+// the window (t-window, t] of each point t. If there are no such points, the result is null.
+func movingWindow(refID string, s Series, window time.Duration, aggF func(sum float64, count int) float64) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	start := 0
+	for i := range s.Len() {
+		t := s.GetTime(i)
+		for !s.GetTime(start).After(t.Add(-window)) {
+			start++
+		}
+		sum := float64(0)
+		count := 0
+		for j := start; j <= i; j++ {
+			f := s.GetValue(j)
+			if f == nil || math.IsNaN(*f) {
+				continue
+			}
+			sum += *f
+			count++
+		}
+		if count == 0 {
+			newSeries.SetPoint(i, t, nil)
+			continue
+		}
+		nF := aggF(sum, count)
+		newSeries.SetPoint(i, t, &nF)
+	}
+	return newSeries
+}
+
+// sortedSeriesCopy returns a copy of the series with the points sorted by time
+// so that the input series is not mutated.
+func sortedSeriesCopy(refID string, s Series) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	for i := range s.Len() {
+		t, f := s.GetPoint(i)
+		newSeries.SetPoint(i, t, f)
+	}
+	newSeries.SortByTime(false)
+	return newSeries
+}
+
+func copyFloat(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	nF := *f
+	return &nF
+}
+
+// scalarArg returns the value of a Results that holds a single non-null Scalar.
+func scalarArg(name string, res Results) (float64, error) {
+	if len(res.Values) != 1 {
+		return 0, fmt.Errorf("%s: expected a single scalar argument but got %d values", name, len(res.Values))
+	}
+	s, ok := res.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("%s: expected a scalar argument but got %v", name, res.Values[0].Type())
+	}
+	f := s.GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("%s: scalar argument must not be null", name)
+	}
+	return *f, nil
+}
+
+func parseWindow(window string) (time.Duration, error) {
+	w, err := gtime.ParseDuration(window)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse window %q: %w", window, err)
+	}
+	if w <= 0 {
+		return 0, fmt.Errorf("window must be a positive duration, got %q", window)
+	}
+	return w, nil
+}
+
+func validateAlpha(alpha float64) error {
+	if math.IsNaN(alpha) || alpha <= 0 || alpha > 1 {
+		return fmt.Errorf("ewma: alpha must be greater than 0 and less than or equal to 1, got %v", alpha)
+	}
+	return nil
+}
+
+// checkWindowArg validates the window argument of moving_* functions at parse time.
+func checkWindowArg(_ *parse.Tree, f *parse.FuncNode) error {
+	if s, ok := f.Args[1].(*parse.StringNode); ok {
+		if _, err := parseWindow(s.Text); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkDurationArg validates the duration argument of time_shift at parse time.
+func checkDurationArg(_ *parse.Tree, f *parse.FuncNode) error {
+	if s, ok := f.Args[1].(*parse.StringNode); ok {
+		if _, err := gtime.ParseDuration(s.Text); err != nil {
+			return fmt.Errorf("%s: failed to parse duration %q: %w", f.Name, s.Text, err)
+		}
+	}
+	return nil
+}
+
+// checkAlphaArg validates a constant alpha argument of ewma at parse time.
+func checkAlphaArg(_ *parse.Tree, f *parse.FuncNode) error {
+	if s, ok := f.Args[1].(*parse.ScalarNode); ok {
+		return validateAlpha(s.Float64)
+	}
+	return nil
+}
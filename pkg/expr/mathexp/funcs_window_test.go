@@ -0,0 +1,302 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestWindowFuncs(t *testing.T) {
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name: "delta on series keeps labels and skips nulls",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(5, 0), new(1.0)},
+						tp{time.Unix(10, 0), new(4.0)},
+						tp{time.Unix(15, 0), nil},
+						tp{time.Unix(20, 0), new(2.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(5, 0), nil},
+					tp{time.Unix(10, 0), new(3.0)},
+					tp{time.Unix(15, 0), nil},
+					tp{time.Unix(20, 0), new(-2.0)},
+				),
+			),
+		},
+		{
+			name: "delta sorts the series by time",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(10, 0), new(4.0)},
+						tp{time.Unix(5, 0), new(1.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(5, 0), nil},
+					tp{time.Unix(10, 0), new(3.0)},
+				),
+			),
+		},
+		{
+			name: "increase handles counter resets",
+			expr: "increase($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), new(5.0)},
+						tp{time.Unix(10, 0), new(8.0)},
+						tp{time.Unix(20, 0), new(2.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), new(3.0)},
+					tp{time.Unix(20, 0), new(2.0)},
+				),
+			),
+		},
+		{
+			name: "rate is the increase per second",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), new(5.0)},
+						tp{time.Unix(10, 0), new(25.0)},
+						tp{time.Unix(20, 0), new(5.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), new(2.0)},
+					tp{time.Unix(20, 0), new(0.5)},
+				),
+			),
+		},
+		{
+			name: "cumsum keeps null points",
+			expr: "cumsum($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), new(1.0)},
+						tp{time.Unix(10, 0), nil},
+						tp{time.Unix(30, 0), new(2.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), new(1.0)},
+					tp{time.Unix(10, 0), nil},
+					tp{time.Unix(30, 0), new(3.0)},
+				),
+			),
+		},
+		{
+			name: "moving_avg over a trailing window",
+			expr: `moving_avg($A, "20s")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), new(1.0)},
+						tp{time.Unix(10, 0), new(3.0)},
+						tp{time.Unix(20, 0), new(5.0)},
+						tp{time.Unix(30, 0), nil},
+						tp{time.Unix(60, 0), nil},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), new(1.0)},
+					tp{time.Unix(10, 0), new(2.0)},
+					tp{time.Unix(20, 0), new(4.0)},
+					tp{time.Unix(30, 0), new(5.0)},
+					tp{time.Unix(60, 0), nil},
+				),
+			),
+		},
+		{
+			name: "moving_sum over a trailing window",
+			expr: `moving_sum($A, "20s")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), new(1.0)},
+						tp{time.Unix(10, 0), new(3.0)},
+						tp{time.Unix(20, 0), new(5.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), new(1.0)},
+					tp{time.Unix(10, 0), new(4.0)},
+					tp{time.Unix(20, 0), new(8.0)},
+				),
+			),
+		},
+		{
+			name:     "moving_avg with invalid window should error",
+			expr:     `moving_avg($A, "abc")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_sum with negative window should error",
+			expr:     `moving_sum($A, "-1m")`,
+			newErrIs: require.Error,
+		},
+		{
+			name: "ewma",
+			expr: "ewma($A, 0.5)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), new(2.0)},
+						tp{time.Unix(10, 0), nil},
+						tp{time.Unix(20, 0), new(4.0)},
+						tp{time.Unix(30, 0), new(7.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), new(2.0)},
+					tp{time.Unix(10, 0), nil},
+					tp{time.Unix(20, 0), new(3.0)},
+					tp{time.Unix(30, 0), new(5.0)},
+				),
+			),
+		},
+		{
+			name:     "ewma with alpha out of range should error",
+			expr:     "ewma($A, 2)",
+			newErrIs: require.Error,
+		},
+		{
+			name: "time_shift moves points",
+			expr: `time_shift($A, "1m")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(0, 0), new(1.0)},
+						tp{time.Unix(10, 0), new(2.0)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(60, 0), new(1.0)},
+					tp{time.Unix(70, 0), new(2.0)},
+				),
+			),
+		},
+		{
+			name: "series function on number should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, new(1.0))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "series function on scalar should error",
+			expr:     "cumsum(1)",
+			newErrIs: require.Error,
+		},
+		{
+			name: "series function on no data",
+			expr: "cumsum($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(NewNoData()),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewNoData()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e == nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tt.results, res)
+		})
+	}
+}
+
+func TestWindowFuncsUnion(t *testing.T) {
+	e, err := New(`$A - time_shift($A, "10s")`)
+	require.NoError(t, err)
+
+	vars := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), new(1.0)},
+				tp{time.Unix(10, 0), new(3.0)},
+				tp{time.Unix(20, 0), new(6.0)},
+			),
+		),
+	}
+	res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.Equal(t, resultValuesNoErr(
+		makeSeries("", data.Labels{"host": "a"},
+			tp{time.Unix(10, 0), new(2.0)},
+			tp{time.Unix(20, 0), new(3.0)},
+		),
+	), res)
+}
@@ -0,0 +1,197 @@
+package mathexp
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The join type
+// +enum
+type JoinType string
+
+const (
+	// Only values with a match on the other side
+	JoinInner JoinType = "inner"
+
+	// All values of the left side, and the values of the right side with a match
+	JoinLeft JoinType = "left"
+
+	// All values of both sides
+	JoinOuter JoinType = "outer"
+)
+
+// JoinOptions configures Join.
+type JoinOptions struct {
+	// Type decides which unmatched values are kept.
+	Type JoinType
+
+	// Operator is the binary operator, for example "+" or ">", applied to each matched pair.
+	Operator string
+
+	// On is the list of labels used to match values. When empty, all labels except
+	// the ones in Ignoring are used.
+	On []string
+
+	// Ignoring is the list of labels that are not used to match values.
+	Ignoring []string
+
+	// Fill is used as the value of the missing side of an unmatched value kept by a
+	// left or outer join. When nil, the result of the operation is null.
+	Fill *float64
+}
+
+// Validate returns an error if the options cannot be used to join values.
+func (o JoinOptions) Validate() error {
+	switch o.Type {
+	case JoinInner, JoinLeft, JoinOuter:
+	default:
+		return fmt.Errorf("join type '%s' is not supported", o.Type)
+	}
+	if len(o.On) > 0 && len(o.Ignoring) > 0 {
+		return fmt.Errorf("on and ignoring can not be used together")
+	}
+	if _, err := binaryOp(o.Operator, 1, 1); err != nil {
+		return fmt.Errorf("invalid join operator: %w", err)
+	}
+	return nil
+}
+
+// Join matches the values of left and right by their labels, like vector matching in
+// PromQL, and applies the operator to each matched pair. Two values match when they have
+// the same values for the labels in opts.On, or for all their labels except the ones in
+// opts.Ignoring. The result is labelled with the labels used for matching.
+//
+// Each side must have at most one value for each set of matching labels. Numbers and
+// Series can be joined with each other; when two Series are joined, only the points with
+// equal timestamps are kept. NoData on either side is treated as an empty side.
+func Join(refID string, left, right Results, opts JoinOptions) (Results, error) {
+	if err := opts.Validate(); err != nil {
+		return Results{}, err
+	}
+
+	leftSide, err := joinSide("left", left, opts)
+	if err != nil {
+		return Results{}, err
+	}
+	rightSide, err := joinSide("right", right, opts)
+	if err != nil {
+		return Results{}, err
+	}
+
+	e := &State{RefID: refID}
+	newRes := Results{}
+	matched := make(map[string]bool, len(rightSide.keys))
+	for _, key := range leftSide.keys {
+		l := leftSide.values[key]
+		r, ok := rightSide.values[key]
+		if !ok && opts.Type == JoinInner {
+			continue
+		}
+		matched[key] = ok
+		v, err := e.joinPair(leftSide.labels[key], opts, l, r)
+		if err != nil {
+			return Results{}, err
+		}
+		newRes.Values = append(newRes.Values, v)
+	}
+	if opts.Type == JoinOuter {
+		for _, key := range rightSide.keys {
+			if matched[key] {
+				continue
+			}
+			v, err := e.joinPair(rightSide.labels[key], opts, nil, rightSide.values[key])
+			if err != nil {
+				return Results{}, err
+			}
+			newRes.Values = append(newRes.Values, v)
+		}
+	}
+	if len(newRes.Values) == 0 {
+		newRes.Values = append(newRes.Values, NewNoData())
+	}
+	return newRes, nil
+}
+
+type joinValues struct {
+	keys   []string
+	values map[string]Value
+	labels map[string]data.Labels
+}
+
+// joinSide indexes the values of one side of a join by their matching labels.
+func joinSide(name string, res Results, opts JoinOptions) (joinValues, error) {
+	side := joinValues{values: map[string]Value{}, labels: map[string]data.Labels{}}
+	for _, val := range res.Values {
+		switch val.(type) {
+		case Number, Series:
+		case NoData:
+			continue
+		default:
+			return side, fmt.Errorf("can only join numbers or series, got type %v on the %s side", val.Type(), name)
+		}
+		labels := matchingLabels(val.GetLabels(), opts)
+		key := labels.String()
+		if _, ok := side.values[key]; ok {
+			return side, fmt.Errorf("found more than one value on the %s side for the matching labels %s", name, key)
+		}
+		side.keys = append(side.keys, key)
+		side.values[key] = val
+		side.labels[key] = labels
+	}
+	return side, nil
+}
+
+// matchingLabels returns the labels used to match a value with the given labels.
+func matchingLabels(labels data.Labels, opts JoinOptions) data.Labels {
+	if len(opts.On) > 0 {
+		return selectLabels(labels, opts.On)
+	}
+	matching := data.Labels{}
+	for name, v := range labels {
+		if !slices.Contains(opts.Ignoring, name) {
+			matching[name] = v
+		}
+	}
+	return matching
+}
+
+// joinPair applies the operator of the join to a pair of values. A nil value is a
+// missing side and is replaced by the fill value of the join.
+func (e *State) joinPair(labels data.Labels, opts JoinOptions, l, r Value) (Value, error) {
+	switch {
+	case l == nil:
+		return e.joinFill(labels, opts.Operator, r, opts.Fill, false)
+	case r == nil:
+		return e.joinFill(labels, opts.Operator, l, opts.Fill, true)
+	}
+	switch lt := l.(type) {
+	case Number:
+		switch rt := r.(type) {
+		case Number:
+			return e.biScalarNumber(labels, opts.Operator, lt, rt.GetFloat64Value(), true)
+		case Series:
+			return e.biSeriesNumber(labels, opts.Operator, rt, lt.GetFloat64Value(), false)
+		}
+	case Series:
+		switch rt := r.(type) {
+		case Number:
+			return e.biSeriesNumber(labels, opts.Operator, lt, rt.GetFloat64Value(), true)
+		case Series:
+			return e.biSeriesSeries(labels, opts.Operator, lt, rt)
+		}
+	}
+	return nil, fmt.Errorf("can not join %v and %v", l.Type(), r.Type())
+}
+
+// joinFill applies the operator to a value without a match and the fill value.
+func (e *State) joinFill(labels data.Labels, op string, v Value, fill *float64, valueFirst bool) (Value, error) {
+	switch vt := v.(type) {
+	case Number:
+		return e.biScalarNumber(labels, op, vt, fill, valueFirst)
+	case Series:
+		return e.biSeriesNumber(labels, op, vt, fill, valueFirst)
+	}
+	return nil, fmt.Errorf("can not join %v", v.Type())
+}
@@ -0,0 +1,138 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	left := resultValuesNoErr(
+		makeNumber("", data.Labels{"host": "a", "job": "api"}, new(10.0)),
+		makeNumber("", data.Labels{"host": "b", "job": "api"}, new(20.0)),
+	)
+	right := resultValuesNoErr(
+		makeNumber("", data.Labels{"host": "a", "job": "db"}, new(2.0)),
+		makeNumber("", data.Labels{"host": "c", "job": "db"}, new(4.0)),
+	)
+
+	tests := []struct {
+		name     string
+		left     Results
+		right    Results
+		opts     JoinOptions
+		expected Results
+		errorIs  string
+	}{
+		{
+			name:  "inner join on host",
+			left:  left,
+			right: right,
+			opts:  JoinOptions{Type: JoinInner, Operator: "/", On: []string{"host"}},
+			expected: resultValuesNoErr(
+				makeNumber("C", data.Labels{"host": "a"}, new(5.0)),
+			),
+		},
+		{
+			name:  "inner join ignoring job",
+			left:  left,
+			right: right,
+			opts:  JoinOptions{Type: JoinInner, Operator: "-", Ignoring: []string{"job"}},
+			expected: resultValuesNoErr(
+				makeNumber("C", data.Labels{"host": "a"}, new(8.0)),
+			),
+		},
+		{
+			name:  "left join without fill",
+			left:  left,
+			right: right,
+			opts:  JoinOptions{Type: JoinLeft, Operator: "/", On: []string{"host"}},
+			expected: resultValuesNoErr(
+				makeNumber("C", data.Labels{"host": "a"}, new(5.0)),
+				makeNumber("C", data.Labels{"host": "b"}, nil),
+			),
+		},
+		{
+			name:  "outer join with fill",
+			left:  left,
+			right: right,
+			opts:  JoinOptions{Type: JoinOuter, Operator: "-", On: []string{"host"}, Fill: new(0.0)},
+			expected: resultValuesNoErr(
+				makeNumber("C", data.Labels{"host": "a"}, new(8.0)),
+				makeNumber("C", data.Labels{"host": "b"}, new(20.0)),
+				makeNumber("C", data.Labels{"host": "c"}, new(-4.0)),
+			),
+		},
+		{
+			name:  "no match returns no data",
+			left:  left,
+			right: right,
+			opts:  JoinOptions{Type: JoinInner, Operator: "+"},
+			expected: resultValuesNoErr(
+				NewNoData(),
+			),
+		},
+		{
+			name: "series with number",
+			left: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), new(1.0)},
+					tp{time.Unix(10, 0), new(3.0)},
+				),
+			),
+			right: right,
+			opts:  JoinOptions{Type: JoinInner, Operator: ">", On: []string{"host"}},
+			expected: resultValuesNoErr(
+				makeSeries("C", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), new(0.0)},
+					tp{time.Unix(10, 0), new(1.0)},
+				),
+			),
+		},
+		{
+			name:  "no data on the right side",
+			left:  left,
+			right: resultValuesNoErr(NewNoData()),
+			opts:  JoinOptions{Type: JoinLeft, Operator: "+", On: []string{"host"}, Fill: new(1.0)},
+			expected: resultValuesNoErr(
+				makeNumber("C", data.Labels{"host": "a"}, new(11.0)),
+				makeNumber("C", data.Labels{"host": "b"}, new(21.0)),
+			),
+		},
+		{
+			name:    "duplicate matching labels should error",
+			left:    left,
+			right:   right,
+			opts:    JoinOptions{Type: JoinInner, Operator: "+", On: []string{"job"}},
+			errorIs: "more than one value on the left side",
+		},
+		{
+			name:    "on and ignoring should error",
+			opts:    JoinOptions{Type: JoinInner, Operator: "+", On: []string{"host"}, Ignoring: []string{"job"}},
+			errorIs: "can not be used together",
+		},
+		{
+			name:    "unknown operator should error",
+			opts:    JoinOptions{Type: JoinInner, Operator: "<>"},
+			errorIs: "invalid join operator",
+		},
+		{
+			name:    "unknown join type should error",
+			opts:    JoinOptions{Type: "cross", Operator: "+"},
+			errorIs: "not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Join("C", tt.left, tt.right, tt.opts)
+			if tt.errorIs != "" {
+				require.ErrorContains(t, err, tt.errorIs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, res)
+		})
+	}
+}
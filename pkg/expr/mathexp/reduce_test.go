@@ -22,6 +22,17 @@ var seriesWithNil = Vars{
 	),
 }
 
+var fourPointSeries = Vars{
+	"A": resultValuesNoErr(
+		makeSeries("temp", nil,
+			tp{time.Unix(0, 0), new(3.0)},
+			tp{time.Unix(5, 0), new(1.0)},
+			tp{time.Unix(10, 0), new(3.0)},
+			tp{time.Unix(15, 0), new(7.0)},
+		),
+	),
+}
+
 var seriesEmpty = Vars{
 	"A": resultValuesNoErr(
 		makeSeries("temp", nil),
@@ -274,6 +285,186 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(2.0))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "variance series",
+			red:         "variance",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(4.75))),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(math.Sqrt(4.75)))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(6.0))),
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(4.0))),
+		},
+		{
+			name:        "diff series with a nil value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(1.0))),
+		},
+		{
+			name:        "rate series",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(4.0/15))),
+		},
+		{
+			name:        "rate series with a single point",
+			red:         "rate",
+			varToReduce: "A",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("temp", nil, tp{time.Unix(5, 0), new(2.0)}),
+				),
+			},
+			errIs:     require.NoError,
+			resultsIs: require.Equal,
+			results:   resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "p25 series interpolates between values",
+			red:         "p25",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(2.5))),
+		},
+		{
+			name:        "p50 series is the median",
+			red:         "p50",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(3.0))),
+		},
+		{
+			name:        "p0 series is the min",
+			red:         "p0",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(1.0))),
+		},
+		{
+			name:        "p100 series is the max",
+			red:         "p100",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, new(7.0))),
+		},
+		{
+			name:        "p50 empty series",
+			red:         "p50",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "percentile above 100 will error",
+			red:         "p101",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "negative percentile will error",
+			red:         "p-1",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "percentile without a number will error",
+			red:         "p",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "percentile with an invalid number will error",
+			red:         "pfoo",
+			varToReduce: "A",
+			vars:        fourPointSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -394,6 +585,34 @@ func TestSeriesReduceDropNN(t *testing.T) {
 			vars:        seriesWithNil,
 			results:     resultValuesNoErr(makeNumber("", nil, new(1.0))),
 		},
+		{
+			name:        "DropNN: stddev series that becomes empty after filtering non-number",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
+		{
+			name:        "DropNN: p95 series with a nil value and real value",
+			red:         "p95",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, new(2.0))),
+		},
+		{
+			name:        "DropNN: diff series with a nil value and real value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, new(0.0))),
+		},
+		{
+			name:        "DropNN: rate series with a nil value and real value",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
 	}
 
 	for _, tt := range tests {
@@ -516,6 +735,20 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 			vars:        seriesWithNil,
 			results:     resultValuesNoErr(makeNumber("", nil, new(2.0))),
 		},
+		{
+			name:        "replaceNN: range series with a nil value and real value",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, new(math.Abs(2-replaceWith)))),
+		},
+		{
+			name:        "replaceNN: first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			results:     resultValuesNoErr(makeNumber("", nil, new(replaceWith))),
+		},
 	}
 
 	for _, tt := range tests {
@@ -538,6 +771,26 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 	}
 }
 
+func TestPercentile(t *testing.T) {
+	values := []*float64{new(3.0), new(1.0), new(3.0), new(7.0)}
+	fv := Float64Field(*data.NewField("", nil, values))
+
+	require.Equal(t, 1.0, *Percentile(0)(&fv))
+	require.Equal(t, 2.5, *Percentile(25)(&fv))
+	require.Equal(t, 3.0, *Percentile(50)(&fv))
+	require.InDelta(t, 6.88, *Percentile(99)(&fv), 1e-9)
+	require.Equal(t, 7.0, *Percentile(100)(&fv))
+
+	reduce, err := GetReduceFunc("p99.9")
+	require.NoError(t, err)
+	require.InDelta(t, 6.988, *reduce(&fv), 1e-9)
+
+	for _, rFunc := range []ReducerID{"p", "p-0.1", "p100.1", "pNaN", "p1e3", "P50"} {
+		_, err := GetReduceFunc(rFunc)
+		require.Errorf(t, err, "reducer %s should not be supported", rFunc)
+	}
+}
+
 func sortedFloat64(f []float64) []float64 {
 	f = append([]float64(nil), f...)
 	sort.Float64s(f)
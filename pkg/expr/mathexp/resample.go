@@ -3,8 +3,6 @@ package mathexp
 import (
 	"fmt"
 	"time"
-
-	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 // The upsample function
@@ -21,10 +19,159 @@ const (
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
 
+	// Linear interpolation between the surrounding values
+	UpsamplerLinear Upsampler = "linear"
+
+	// Time weighted average of the linear interpolation over the window, also used instead of the downsampler
+	UpsamplerTimeWeighted Upsampler = "time_weighted"
+
 	// Maximum size of new series length.
 	MaxNewSeriesLength int = 1_000_000
 )
 
+// The calendar unit the resampled points are aligned to
+// +enum
+type ResampleAlignment string
+
+const (
+	// Align to the start of the time range
+	ResampleAlignmentNone ResampleAlignment = ""
+
+	// Align to the start of a minute
+	ResampleAlignmentMinute ResampleAlignment = "minute"
+
+	// Align to the start of an hour
+	ResampleAlignmentHour ResampleAlignment = "hour"
+
+	// Align to the start of a day
+	ResampleAlignmentDay ResampleAlignment = "day"
+)
+
+// ResampleOption is a functional option for configuring Series.Resample.
+type ResampleOption func(*resampleOptions)
+
+type resampleOptions struct {
+	alignment ResampleAlignment
+	location  *time.Location
+}
+
+// WithAlignment aligns the resampled points to the start of the given calendar unit
+// in loc instead of the start of the time range. A nil loc is treated as UTC.
+func WithAlignment(alignment ResampleAlignment, loc *time.Location) ResampleOption {
+	return func(o *resampleOptions) {
+		o.alignment = alignment
+		o.location = loc
+	}
+}
+
+// alignTime returns the first start of the calendar unit in loc that is not before t.
+func alignTime(t time.Time, alignment ResampleAlignment, loc *time.Location) (time.Time, error) {
+	lt := t.In(loc)
+	var aligned time.Time
+	switch alignment {
+	case ResampleAlignmentNone:
+		return t, nil
+	case ResampleAlignmentMinute:
+		aligned = time.Date(lt.Year(), lt.Month(), lt.Day(), lt.Hour(), lt.Minute(), 0, 0, loc)
+		if aligned.Before(lt) {
+			aligned = time.Date(lt.Year(), lt.Month(), lt.Day(), lt.Hour(), lt.Minute()+1, 0, 0, loc)
+		}
+	case ResampleAlignmentHour:
+		aligned = time.Date(lt.Year(), lt.Month(), lt.Day(), lt.Hour(), 0, 0, 0, loc)
+		if aligned.Before(lt) {
+			aligned = time.Date(lt.Year(), lt.Month(), lt.Day(), lt.Hour()+1, 0, 0, 0, loc)
+		}
+	case ResampleAlignmentDay:
+		aligned = time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, loc)
+		if aligned.Before(lt) {
+			aligned = time.Date(lt.Year(), lt.Month(), lt.Day()+1, 0, 0, 0, 0, loc)
+		}
+	default:
+		return t, fmt.Errorf("alignment %v not implemented", alignment)
+	}
+	return aligned.In(t.Location()), nil
+}
+
+// preservesSingleValue returns true if the reducer returns the value itself when there is
+// only one value, so that windows with a single point do not need to be reduced.
+func preservesSingleValue(rFunc ReducerID) bool {
+	switch rFunc {
+	case ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerLast, ReducerFirst, ReducerMedian:
+		return true
+	default:
+		_, ok := parsePercentile(rFunc)
+		return ok
+	}
+}
+
+// interpolate returns the value at t on the line between the points (t1, v1) and (t2, v2).
+// If either value is null, null is returned.
+func interpolate(t, t1, t2 time.Time, v1, v2 *float64) *float64 {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	f := *v1
+	if d := t2.Sub(t1); d > 0 {
+		f += (*v2 - *v1) * float64(t.Sub(t1)) / float64(d)
+	}
+	return &f
+}
+
+// timeWeightedMean returns the time weighted average of the points of the window between
+// from and to, interpolated linearly. The values at the edges of the window are interpolated
+// from the points before and after the window, if there are any. Otherwise only the part of
+// the window between its first and last point is averaged. If any value is null, null is returned.
+func timeWeightedMean(window Series, from, to time.Time, before, after *samplePoint) *float64 {
+	points := make([]samplePoint, 0, window.Len()+2)
+	for i := 0; i < window.Len(); i++ {
+		t, v := window.GetPoint(i)
+		// the first window also holds the points before the time range
+		if t.Before(from) {
+			before = &samplePoint{time: t, value: v}
+			continue
+		}
+		points = append(points, samplePoint{time: t, value: v})
+	}
+	if len(points) == 0 {
+		if before == nil || after == nil {
+			return nil
+		}
+		return interpolate(from.Add(to.Sub(from)/2), before.time, after.time, before.value, after.value)
+	}
+	if before != nil {
+		first := points[0]
+		points = append([]samplePoint{{time: from, value: interpolate(from, before.time, first.time, before.value, first.value)}}, points...)
+	}
+	if after != nil {
+		last := points[len(points)-1]
+		points = append(points, samplePoint{time: to, value: interpolate(to, last.time, after.time, last.value, after.value)})
+	}
+	for _, p := range points {
+		if p.value == nil {
+			return nil
+		}
+	}
+
+	first, last := points[0], points[len(points)-1]
+	d := last.time.Sub(first.time).Seconds()
+	if d <= 0 {
+		f := *last.value
+		return &f
+	}
+	var area float64
+	for i := 1; i < len(points); i++ {
+		area += (*points[i-1].value + *points[i].value) / 2 * points[i].time.Sub(points[i-1].time).Seconds()
+	}
+	f := area / d
+	return &f
+}
+
+// samplePoint is a point of a series.
+type samplePoint struct {
+	time  time.Time
+	value *float64
+}
+
 type ErrNewSeriesLengthTooLong struct {
 	newSeriesLength int
 }
@@ -34,21 +181,59 @@ func (e ErrNewSeriesLengthTooLong) Error() string {
 }
 
 // Resample turns the Series into a Number based on the given reduction function
-func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
-	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
+func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time, opts ...ResampleOption) (Series, error) {
+	o := resampleOptions{location: time.UTC}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.location == nil {
+		o.location = time.UTC
+	}
+	start, err := alignTime(from, o.alignment, o.location)
+	if err != nil {
+		return s, err
+	}
+	reduce, err := GetSeriesReduceFunc(downsampler)
+	if err != nil {
+		return s, fmt.Errorf("downsampling %v not implemented", downsampler)
+	}
+	// When aligning to days, step in calendar days so that the points stay at the
+	// start of the day when the UTC offset of the location changes.
+	next := func(t time.Time) time.Time { return t.Add(interval) }
+	calendarDays := o.alignment == ResampleAlignmentDay && interval%(24*time.Hour) == 0
+	if calendarDays {
+		days := int(interval / (24 * time.Hour))
+		next = func(t time.Time) time.Time {
+			return t.In(o.location).AddDate(0, 0, days).In(t.Location())
+		}
+	}
+
+	newSeriesLength := int(float64(to.Sub(start).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
 	if newSeriesLength > MaxNewSeriesLength {
 		return s, ErrNewSeriesLengthTooLong{newSeriesLength: newSeriesLength}
 	}
-	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
+	maxIdx := newSeriesLength
+	if calendarDays {
+		// shorter days can fit one more point into the time range
+		maxIdx++
+	}
+	resampled := NewSeries(refID, s.GetLabels(), 0)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
+	seen := false
 	idx := 0
-	t := from
-	for !t.After(to) && idx <= newSeriesLength {
-		vals := make([]*float64, 0)
+	t := start
+	windowStart := t.Add(-interval)
+	for !t.After(to) && idx <= maxIdx {
+		window := NewSeries(refID, s.GetLabels(), 0)
+		var before *samplePoint
+		if seen {
+			before = &samplePoint{time: lastSeenTime, value: lastSeen}
+		}
 		sIdx := bookmark
 		for sIdx != s.Len() {
 			st, v := s.GetPoint(sIdx)
@@ -58,10 +243,12 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
-			vals = append(vals, v)
+			lastSeenTime = st
+			seen = true
+			window.AppendPoint(st, v)
 		}
 		var value *float64
-		if len(vals) == 0 { // upsampling
+		if window.Len() == 0 { // upsampling
 			switch upsampler {
 			case UpsamplerPad:
 				if lastSeen != nil {
@@ -77,33 +264,37 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear, UpsamplerTimeWeighted:
+				if !seen || sIdx == s.Len() { // no value on one of the sides
+					value = nil
+					break
+				}
+				// The window has no points, so the series is a straight line over the window and
+				// its time weighted average is the value in the middle of the window.
+				at := t
+				if upsampler == UpsamplerTimeWeighted {
+					at = t.Add(-interval / 2)
+				}
+				nextTime, nextValue := s.GetPoint(sIdx)
+				value = interpolate(at, lastSeenTime, nextTime, lastSeen, nextValue)
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
-		} else if len(vals) == 1 {
-			value = vals[0]
-		} else { // downsampling
-			fVec := data.NewField("", s.GetLabels(), vals)
-			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
+		} else if upsampler == UpsamplerTimeWeighted {
+			var after *samplePoint
+			if sIdx != s.Len() {
+				nextTime, nextValue := s.GetPoint(sIdx)
+				after = &samplePoint{time: nextTime, value: nextValue}
 			}
-			value = tmp
+			value = timeWeightedMean(window, windowStart, t, before, after)
+		} else if window.Len() == 1 && preservesSingleValue(downsampler) {
+			_, value = window.GetPoint(0)
+		} else { // downsampling
+			value = reduce(window)
 		}
-		resampled.SetPoint(idx, t, value)
-		t = t.Add(interval)
+		resampled.AppendPoint(t, value)
+		windowStart = t
+		t = next(t)
 		idx++
 	}
 	return resampled, nil
@@ -279,6 +279,190 @@ func TestResampleSeries(t *testing.T) {
 				time.Unix(9, 0), new(0.0),
 			}),
 		},
+		{
+			name:        "resample series: downsampling (count / fillna)",
+			interval:    time.Second * 5,
+			downsampler: "count",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(16, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), new(2.0),
+			}, tp{
+				time.Unix(4, 0), new(3.0),
+			}, tp{
+				time.Unix(7, 0), new(1.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), new(2.0),
+			}, tp{
+				time.Unix(10, 0), new(1.0),
+			}, tp{
+				time.Unix(15, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: downsampling (rate / fillna)",
+			interval:    time.Second * 5,
+			downsampler: "rate",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(11, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), new(2.0),
+			}, tp{
+				time.Unix(5, 0), new(10.0),
+			}, tp{
+				time.Unix(6, 0), new(10.0),
+			}, tp{
+				time.Unix(10, 0), new(18.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), new(2.0),
+			}, tp{
+				time.Unix(10, 0), new(2.0),
+			}),
+		},
+		{
+			name:        "resample series: downsampling (p90 / fillna)",
+			interval:    time.Second * 5,
+			downsampler: "p90",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(5, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), new(1.0),
+			}, tp{
+				time.Unix(3, 0), new(11.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), new(10.0),
+			}),
+		},
+		{
+			name:        "resample series: unknown downsampler",
+			interval:    time.Second * 5,
+			downsampler: "p50",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(16, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), new(2.0),
+			}),
+			expectedError: "downsampling p50 not implemented",
+		},
+		{
+			name:        "resample series: upsampling (mean / linear)",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(20, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), new(2.0),
+			}, tp{
+				time.Unix(12, 0), new(7.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), new(2.0),
+			}, tp{
+				time.Unix(10, 0), new(6.0),
+			}, tp{
+				time.Unix(15, 0), new(7.0),
+			}, tp{
+				time.Unix(20, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / time_weighted)",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "time_weighted",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(20, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), new(2.0),
+			}, tp{
+				time.Unix(12, 0), new(7.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), new(2.75),
+			}, tp{
+				time.Unix(10, 0), new(4.75),
+			}, tp{
+				time.Unix(15, 0), new(6.5),
+			}, tp{
+				time.Unix(20, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: downsampling (mean / time_weighted)",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "time_weighted",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(10, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), new(0.0),
+			}, tp{
+				time.Unix(4, 0), new(6.0),
+			}, tp{
+				time.Unix(6, 0), new(10.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), new(4.0),
+			}, tp{
+				time.Unix(10, 0), new(9.0),
+			}),
+		},
+		{
+			name:        "resample series: linear upsampling with a null neighbour",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(10, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), nil,
+			}, tp{
+				time.Unix(12, 0), new(7.0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), nil,
+			}, tp{
+				time.Unix(10, 0), nil,
+			}),
+		},
 		{
 			name:        "resample series: upsampling, result too big",
 			interval:    time.Microsecond,
@@ -312,3 +496,53 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestResampleSeriesAlignment(t *testing.T) {
+	t.Run("align to minutes", func(t *testing.T) {
+		s := makeSeries("", nil, tp{
+			time.Date(2024, 1, 1, 0, 0, 45, 0, time.UTC), new(1.0),
+		}, tp{
+			time.Date(2024, 1, 1, 0, 1, 30, 0, time.UTC), new(2.0),
+		}, tp{
+			time.Date(2024, 1, 1, 0, 2, 10, 0, time.UTC), new(3.0),
+		})
+		from := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+		to := time.Date(2024, 1, 1, 0, 3, 0, 0, time.UTC)
+
+		series, err := s.Resample("", time.Minute, "mean", "fillna", from, to, WithAlignment(ResampleAlignmentMinute, nil))
+		require.NoError(t, err)
+		assert.Equal(t, makeSeries("", nil, tp{
+			time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC), new(1.0),
+		}, tp{
+			time.Date(2024, 1, 1, 0, 2, 0, 0, time.UTC), new(2.0),
+		}, tp{
+			time.Date(2024, 1, 1, 0, 3, 0, 0, time.UTC), new(3.0),
+		}), series)
+	})
+
+	t.Run("align to days in a timezone with daylight saving time", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		from := time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+
+		series, err := makeSeries("", nil).Resample("", 24*time.Hour, "mean", "fillna", from, to, WithAlignment(ResampleAlignmentDay, loc))
+		require.NoError(t, err)
+		assert.Equal(t, makeSeries("", nil, tp{
+			time.Date(2024, 3, 29, 23, 0, 0, 0, time.UTC), nil,
+		}, tp{
+			time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC), nil,
+		}, tp{
+			time.Date(2024, 3, 31, 22, 0, 0, 0, time.UTC), nil,
+		}, tp{
+			time.Date(2024, 4, 1, 22, 0, 0, 0, time.UTC), nil,
+		}), series)
+	})
+
+	t.Run("unknown alignment should error", func(t *testing.T) {
+		from := time.Unix(0, 0)
+		_, err := makeSeries("", nil).Resample("", time.Minute, "mean", "fillna", from, from.Add(time.Hour), WithAlignment("week", nil))
+		require.Error(t, err)
+	})
+}
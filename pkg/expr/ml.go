@@ -131,6 +131,7 @@ func (m *MLNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s *
 
 	// process the response the same way DSNode does. Use plugin ID as data source type. Semantically, they are the same.
 	responseType, result, err = s.converter.Convert(ctx, mlPluginID, dataFrames)
+	explanationFromContext(ctx).addConversion(m.refID, responseType, dataFrames)
 	return result, err
 }
 
@@ -161,6 +161,12 @@ func buildCMDNode(ctx context.Context, rn *rawNode, toggles featuremgmt.FeatureT
 		node.Command, err = UnmarshalThresholdCommand(rn)
 	case TypeSQL:
 		node.Command, err = UnmarshalSQLCommand(ctx, rn, cfg)
+	case TypeForecast:
+		node.Command, err = UnmarshalForecastCommand(rn)
+	case TypeAggregate:
+		node.Command, err = UnmarshalAggregateCommand(rn)
+	case TypeJoin:
+		node.Command, err = UnmarshalJoinCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -367,6 +373,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 				if err != nil {
 					result.Error = makeConversionError(dn.RefID(), err)
 				}
+				explanationFromContext(ctx).addConversion(dn.refID, responseType, dataFrames)
 				instrument(err, responseType)
 				vars[dn.refID] = result
 			}
@@ -458,6 +465,11 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		dataType := categorizeFrameInputType(dataFrames)
 
 		result, converted = handleSqlInput(ctx, s.tracer, dn.RefID(), dn.IsInputTo(), dn.datasource.Type, dataFrames)
+		if converted {
+			explanationFromContext(ctx).addConversion(dn.refID, dataType+" to table", dataFrames)
+		} else {
+			explanationFromContext(ctx).addConversion(dn.refID, "", dataFrames)
+		}
 		status := "ok"
 		if result.Error != nil {
 			status = "error"
@@ -468,6 +480,7 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		if err != nil {
 			err = makeConversionError(dn.refID, err)
 		}
+		explanationFromContext(ctx).addConversion(dn.refID, responseType, dataFrames)
 	}
 
 	return result, err
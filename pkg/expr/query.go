@@ -3,6 +3,8 @@ package expr
 import (
 	"embed"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
@@ -29,6 +31,21 @@ const (
 
 	// SQL query
 	QueryTypeSQL QueryType = "sql"
+
+	// Forecast query results and detect anomalies
+	QueryTypeForecast QueryType = "forecast"
+
+	// Aggregate query results grouped by labels
+	QueryTypeAggregate QueryType = "aggregate"
+
+	// Join two query results by their labels
+	QueryTypeJoin QueryType = "join"
+
+	// Evaluate a library expression of the organization
+	QueryTypeLibrary QueryType = "library"
+
+	// Read the state of another alert rule of the organization
+	QueryTypeRuleState QueryType = "rule_state"
 )
 
 type MathQuery struct {
@@ -40,7 +57,7 @@ type ReduceQuery struct {
 	// Reference to single query result
 	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
 
-	// The reducer
+	// The reducer. Any percentile can also be used with pN, where N is a number between 0 and 100, for example p99.9
 	Reducer mathexp.ReducerID `json:"reducer"`
 
 	// Reducer Options
@@ -55,11 +72,17 @@ type ResampleQuery struct {
 	// The time duration
 	Window string `json:"window" jsonschema:"minLength=1,example=1d,example=10m"`
 
-	// The downsample function
+	// The downsample function. Any percentile can also be used with pN, where N is a number between 0 and 100, for example p99.9
 	Downsampler mathexp.ReducerID `json:"downsampler"`
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// Align the resampled points to the start of a calendar unit instead of the start of the time range
+	Align mathexp.ResampleAlignment `json:"align,omitempty"`
+
+	// The timezone used to align the resampled points, defaults to UTC
+	Timezone string `json:"timezone,omitempty" jsonschema:"example=Europe/Berlin,example=America/New_York"`
 }
 
 type ThresholdQuery struct {
@@ -68,6 +91,102 @@ type ThresholdQuery struct {
 
 	// Threshold Conditions
 	Conditions []ThresholdConditionJSON `json:"conditions"`
+
+	// The label that holds the severity level of the alert instance, defaults to "severity"
+	SeverityLabel string `json:"severityLabel,omitempty"`
+
+	// The number of consecutive evaluations the recovery threshold of a severity level must be met before the result leaves the level
+	RecoveryEvaluations int `json:"recoveryEvaluations,omitempty" jsonschema:"minimum=0"`
+
+	// The severity levels of the results of the previous evaluation, set by alerting
+	LoadedSeverities *data.Frame `json:"loadedSeverities,omitempty"`
+}
+
+// QueryType = forecast
+type ForecastQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The length of a season. When empty, only the level and trend are modelled
+	Seasonality string `json:"seasonality,omitempty" jsonschema:"example=1d,example=1h"`
+
+	// How far to forecast past the last point of the input
+	Horizon string `json:"horizon,omitempty" jsonschema:"example=1h"`
+
+	// Smoothing factor of the level, defaults to 0.3
+	Alpha *float64 `json:"alpha,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Smoothing factor of the trend, defaults to 0.1
+	Beta *float64 `json:"beta,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Smoothing factor of the season, defaults to 0.1
+	Gamma *float64 `json:"gamma,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Width of the band in standard deviations of the prediction errors, defaults to 3
+	Deviations *float64 `json:"deviations,omitempty"`
+
+	// The result of the expression
+	Output ForecastOutput `json:"output,omitempty"`
+}
+
+// QueryType = aggregate
+type AggregateQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The aggregation operation
+	Operation mathexp.AggregateOperation `json:"operation"`
+
+	// The labels to group by. When empty, all values are aggregated into one
+	By []string `json:"by,omitempty"`
+
+	// The number of values to keep for topk and bottomk
+	K int `json:"k,omitempty" jsonschema:"minimum=1"`
+}
+
+// QueryType = join
+type JoinQuery struct {
+	// Reference to the left query result
+	Left string `json:"left" jsonschema:"minLength=1,example=$A"`
+
+	// Reference to the right query result
+	Right string `json:"right" jsonschema:"minLength=1,example=$B"`
+
+	// The binary operator applied to each matched pair
+	Operator string `json:"operator" jsonschema:"minLength=1,example=+,example=>"`
+
+	// The join type, defaults to inner
+	JoinType mathexp.JoinType `json:"joinType,omitempty"`
+
+	// Only match on these labels
+	On []string `json:"on,omitempty"`
+
+	// Match on all labels except these
+	Ignoring []string `json:"ignoring,omitempty"`
+
+	// The value used for the missing side of an unmatched value in left and outer joins
+	Fill *float64 `json:"fill,omitempty"`
+}
+
+// QueryType = library
+type LibraryQuery struct {
+	// The UID of the library expression
+	UID string `json:"uid" jsonschema:"minLength=1"`
+
+	// The query or expression bound to each parameter of the library expression
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// QueryType = rule_state
+type RuleStateQuery struct {
+	// The UID of the alert rule
+	RuleUID string `json:"ruleUid" jsonschema:"minLength=1"`
+
+	// The states that are matched, defaults to Alerting
+	States []string `json:"states,omitempty"`
+
+	// The result of the expression, defaults to count
+	Output RuleStateOutput `json:"output,omitempty"`
 }
 
 type ClassicQuery struct {
@@ -107,6 +226,18 @@ const (
 	ReduceModeReplace ReduceMode = "replaceNN"
 )
 
+// The result of a forecast expression
+// +enum
+type ForecastOutput string
+
+const (
+	// The predicted value with an upper and lower band for each series
+	ForecastOutputBands ForecastOutput = "bands"
+
+	// 1 if the last value of each series is outside of the band, otherwise 0
+	ForecastOutputAnomaly ForecastOutput = "anomaly"
+)
+
 //go:embed query.types.json
 var f embed.FS
 
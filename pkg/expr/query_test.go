@@ -24,8 +24,13 @@ func TestQueryTypeDefinitions(t *testing.T) {
 			Enums: []reflect.Type{
 				reflect.TypeFor[mathexp.ReducerID](),
 				reflect.TypeFor[mathexp.Upsampler](),
+				reflect.TypeFor[mathexp.ResampleAlignment](),
+				reflect.TypeFor[mathexp.AggregateOperation](),
+				reflect.TypeFor[mathexp.JoinType](),
 				reflect.TypeFor[ReduceMode](),
 				reflect.TypeFor[ThresholdType](),
+				reflect.TypeFor[ForecastOutput](),
+				reflect.TypeFor[RuleStateOutput](),
 				reflect.TypeFor[classic.ConditionOperatorType](),
 			},
 		})
@@ -153,6 +158,126 @@ func TestQueryTypeDefinitions(t *testing.T) {
 						]
 					  }`),
 			},
+			{
+				Name: "Warning and critical severity levels",
+				SaveModel: data.AsUnstructured(ThresholdQuery{
+					Expression: "B",
+					Conditions: []ThresholdConditionJSON{
+						{
+							Severity:        "critical",
+							Evaluator:       ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{90}},
+							UnloadEvaluator: &ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{85}},
+						},
+						{
+							Severity:        "warning",
+							Evaluator:       ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{70}},
+							UnloadEvaluator: &ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{65}},
+						},
+					},
+					RecoveryEvaluations: 3,
+				}),
+			},
+		},
+	}, {
+		Discriminators: data.NewDiscriminators("type", QueryTypeForecast),
+		GoType:         reflect.TypeFor[*ForecastQuery](),
+		Examples: []data.QueryExample{
+			{
+				Name: "daily anomaly bands",
+				SaveModel: data.AsUnstructured(ForecastQuery{
+					Expression:  "$A",
+					Seasonality: "1d",
+					Horizon:     "1h",
+					Output:      ForecastOutputBands,
+				}),
+			},
+			{
+				Name: "is the last value anomalous",
+				SaveModel: data.AsUnstructured(ForecastQuery{
+					Expression:  "$A",
+					Seasonality: "1d",
+					Output:      ForecastOutputAnomaly,
+				}),
+			},
+		},
+	}, {
+		Discriminators: data.NewDiscriminators("type", QueryTypeAggregate),
+		GoType:         reflect.TypeFor[*AggregateQuery](),
+		Examples: []data.QueryExample{
+			{
+				Name: "sum by cluster",
+				SaveModel: data.AsUnstructured(AggregateQuery{
+					Expression: "$A",
+					Operation:  mathexp.AggregateSum,
+					By:         []string{"cluster"},
+				}),
+			},
+			{
+				Name: "top 3 hosts",
+				SaveModel: data.AsUnstructured(AggregateQuery{
+					Expression: "$A",
+					Operation:  mathexp.AggregateTopK,
+					K:          3,
+				}),
+			},
+		},
+	}, {
+		Discriminators: data.NewDiscriminators("type", QueryTypeJoin),
+		GoType:         reflect.TypeFor[*JoinQuery](),
+		Examples: []data.QueryExample{
+			{
+				Name: "divide A by B on host",
+				SaveModel: data.AsUnstructured(JoinQuery{
+					Left:     "$A",
+					Right:    "$B",
+					Operator: "/",
+					On:       []string{"host"},
+				}),
+			},
+			{
+				Name: "A minus B keeping hosts missing from B",
+				SaveModel: data.AsUnstructured(JoinQuery{
+					Left:     "$A",
+					Right:    "$B",
+					Operator: "-",
+					JoinType: mathexp.JoinLeft,
+					Fill:     new(0.0),
+				}),
+			},
+		},
+	}, {
+		Discriminators: data.NewDiscriminators("type", QueryTypeLibrary),
+		GoType:         reflect.TypeFor[*LibraryQuery](),
+		Examples: []data.QueryExample{
+			{
+				Name: "error ratio of A and B",
+				SaveModel: data.AsUnstructured(LibraryQuery{
+					UID: "error-ratio",
+					Inputs: map[string]string{
+						"errors":   "$A",
+						"requests": "$B",
+					},
+				}),
+			},
+		},
+	}, {
+		Discriminators: data.NewDiscriminators("type", QueryTypeRuleState),
+		GoType:         reflect.TypeFor[*RuleStateQuery](),
+		Examples: []data.QueryExample{
+			{
+				Name: "number of firing instances of a rule",
+				SaveModel: data.AsUnstructured(RuleStateQuery{
+					RuleUID: "database-down",
+				}),
+			},
+			{
+				Name: "pending or firing state of each instance of a rule",
+				SaveModel: data.AsUnstructured(RuleStateQuery{
+					RuleUID: "database-down",
+					States:  []string{"Pending", "Alerting"},
+					Output:  RuleStateOutputInstances,
+				}),
+			},
 		},
 	}},
 	)
@@ -0,0 +1,199 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// RuleStateReader reads the current state of the alert instances of the alert
+// rules of an organization.
+type RuleStateReader interface {
+	// GetRuleInstanceStates returns the current state of each alert instance of
+	// the rule with the given UID. It returns no states for a rule that has not
+	// been evaluated yet, and ErrRuleStateRuleNotFound for a rule that does not exist.
+	GetRuleInstanceStates(ctx context.Context, orgID int64, ruleUID string) ([]RuleInstanceState, error)
+}
+
+// ErrRuleStateRuleNotFound is returned by a RuleStateReader when the alert rule
+// does not exist, so the rule state command fails instead of reading no instances.
+var ErrRuleStateRuleNotFound = errors.New("alert rule not found")
+
+// RuleInstanceState is the current state of an alert instance.
+type RuleInstanceState struct {
+	Labels data.Labels
+	// State is the name of the state of the instance, for example "Alerting" or "Normal".
+	State string
+}
+
+// The result of a rule state expression
+// +enum
+type RuleStateOutput string
+
+const (
+	// A single number with the count of alert instances that are in one of the states
+	RuleStateOutputCount RuleStateOutput = "count"
+
+	// A number for each alert instance with its labels, 1 if it is in one of the states, otherwise 0
+	RuleStateOutputInstances RuleStateOutput = "instances"
+)
+
+// ruleStateNames are the states a rule state expression can match, as returned
+// by a RuleStateReader.
+var ruleStateNames = []string{"Normal", "Alerting", "Pending", "NoData", "Error", "Recovering"}
+
+// RuleStateCommand returns the current state of the alert instances of another
+// alert rule of the organization.
+//
+// The states are read when the command is executed, so the result reflects the
+// last evaluation of the other rule, that may happen before or after the
+// evaluation of the rule that uses the command.
+type RuleStateCommand struct {
+	RuleUID string
+	RefID   string
+	States  []string
+	Output  RuleStateOutput
+
+	orgID  int64
+	reader RuleStateReader
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (rc *RuleStateCommand) NeedsVars() []string {
+	return []string{}
+}
+
+// Execute reads the state of the alert instances of the rule and returns the
+// result in the format of the output of the command.
+func (rc *RuleStateCommand) Execute(ctx context.Context, _ time.Time, _ mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteRuleState")
+	span.SetAttributes(attribute.String("rule.uid", rc.RuleUID))
+	defer span.End()
+
+	instances, err := rc.reader.GetRuleInstanceStates(ctx, rc.orgID, rc.RuleUID)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to get the state of alert rule '%s': %w", rc.RuleUID, err)
+	}
+
+	if rc.Output == RuleStateOutputInstances {
+		values := make([]mathexp.Value, 0, len(instances))
+		for _, instance := range instances {
+			n := mathexp.NewNumber(rc.RefID, instance.Labels)
+			n.SetValue(new(rc.value(instance)))
+			values = append(values, n)
+		}
+		return mathexp.Results{Values: values}, nil
+	}
+
+	var count float64
+	for _, instance := range instances {
+		count += rc.value(instance)
+	}
+	n := mathexp.NewNumber(rc.RefID, nil)
+	n.SetValue(&count)
+	return mathexp.Results{Values: mathexp.Values{n}}, nil
+}
+
+func (rc *RuleStateCommand) value(instance RuleInstanceState) float64 {
+	if slices.Contains(rc.States, instance.State) {
+		return 1
+	}
+	return 0
+}
+
+func (rc *RuleStateCommand) Type() string {
+	return TypeRuleState.String()
+}
+
+// isRuleStateNode returns true if the raw node is a rule state command.
+func isRuleStateNode(rn *rawNode) bool {
+	commandType, err := GetExpressionCommandType(rn.Query)
+	return err == nil && commandType == TypeRuleState
+}
+
+// buildRuleStateNode creates a rule state command that reads the state of the
+// alert rules of the organization.
+func (s *Service) buildRuleStateNode(rn *rawNode, orgID int64) (*CMDNode, error) {
+	cmd, err := UnmarshalRuleStateCommand(rn)
+	if err == nil && s.ruleStates == nil {
+		err = fmt.Errorf("alert rule states are not available")
+	}
+	if err != nil {
+		return nil, MakeParseError(rn.RefID, err)
+	}
+	cmd.orgID = orgID
+	cmd.reader = s.ruleStates
+	return &CMDNode{
+		baseNode: baseNode{
+			id:    rn.idx,
+			refID: rn.RefID,
+		},
+		CMDType: TypeRuleState,
+		Command: cmd,
+	}, nil
+}
+
+// UnmarshalRuleStateCommand creates a RuleStateCommand from Grafana's frontend query.
+// The command can not be executed until the reader of the rule states is set.
+func UnmarshalRuleStateCommand(rn *rawNode) (*RuleStateCommand, error) {
+	q := RuleStateQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the rule state command: %w", err)
+	}
+	if q.RuleUID == "" {
+		return nil, fmt.Errorf("no alert rule specified for refId %v", rn.RefID)
+	}
+
+	states := []string{"Alerting"}
+	if len(q.States) > 0 {
+		states = make([]string, 0, len(q.States))
+		for _, state := range q.States {
+			idx := slices.IndexFunc(ruleStateNames, func(name string) bool {
+				return strings.EqualFold(name, state)
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("unsupported state '%s', must be one of %s", state, strings.Join(ruleStateNames, ", "))
+			}
+			states = append(states, ruleStateNames[idx])
+		}
+	}
+
+	output := q.Output
+	switch output {
+	case "":
+		output = RuleStateOutputCount
+	case RuleStateOutputCount, RuleStateOutputInstances:
+	default:
+		return nil, fmt.Errorf("unsupported output '%s', must be one of %s, %s", output, RuleStateOutputCount, RuleStateOutputInstances)
+	}
+
+	return &RuleStateCommand{
+		RuleUID: q.RuleUID,
+		RefID:   rn.RefID,
+		States:  states,
+		Output:  output,
+	}, nil
+}
+
+// GetRuleStateDependency returns the UID of the alert rule the raw model reads
+// the state of, if it describes a rule state command.
+func GetRuleStateDependency(query map[string]any) (string, bool) {
+	t, err := GetExpressionCommandType(query)
+	if err != nil || t != TypeRuleState {
+		return "", false
+	}
+	uid, ok := query["ruleUid"].(string)
+	return uid, ok && uid != ""
+}
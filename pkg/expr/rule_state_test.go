@@ -0,0 +1,125 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type fakeRuleStateReader map[string][]RuleInstanceState
+
+func (f fakeRuleStateReader) GetRuleInstanceStates(_ context.Context, orgID int64, ruleUID string) ([]RuleInstanceState, error) {
+	if orgID != 1 {
+		return nil, nil
+	}
+	states, ok := f[ruleUID]
+	if !ok {
+		return nil, ErrRuleStateRuleNotFound
+	}
+	return states, nil
+}
+
+func TestRuleStateCommand(t *testing.T) {
+	s, _ := newMockQueryService(nil, nil)
+	s.ruleStates = fakeRuleStateReader{
+		"database-down": {
+			{Labels: data.Labels{"db": "a"}, State: "Alerting"},
+			{Labels: data.Labels{"db": "b"}, State: "Pending"},
+			{Labels: data.Labels{"db": "c"}, State: "Normal"},
+		},
+	}
+
+	execute := func(t *testing.T, query string, orgID int64) mathexp.Results {
+		t.Helper()
+		node, err := s.buildRuleStateNode(libraryNode("B", query), orgID)
+		require.NoError(t, err)
+		require.Empty(t, node.NeedsVars())
+		res, err := node.Command.Execute(context.Background(), time.Now(), mathexp.Vars{}, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("counts the firing instances by default", func(t *testing.T) {
+		res := execute(t, `{"type": "rule_state", "ruleUid": "database-down"}`, 1)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, new(1.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Empty(t, res.Values[0].GetLabels())
+	})
+
+	t.Run("counts the instances in any of the states", func(t *testing.T) {
+		res := execute(t, `{"type": "rule_state", "ruleUid": "database-down", "states": ["pending", "Alerting"]}`, 1)
+		require.Equal(t, new(2.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("returns zero for a rule without instances", func(t *testing.T) {
+		res := execute(t, `{"type": "rule_state", "ruleUid": "database-down"}`, 2)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, new(0.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("returns a number for each instance", func(t *testing.T) {
+		res := execute(t, `{"type": "rule_state", "ruleUid": "database-down", "output": "instances"}`, 1)
+		require.Len(t, res.Values, 3)
+		for i, expected := range []float64{1, 0, 0} {
+			require.Equal(t, new(expected), res.Values[i].(mathexp.Number).GetFloat64Value())
+		}
+		require.Equal(t, data.Labels{"db": "b"}, res.Values[1].GetLabels())
+	})
+
+	t.Run("fails for a rule that does not exist", func(t *testing.T) {
+		node, err := s.buildRuleStateNode(libraryNode("B", `{"type": "rule_state", "ruleUid": "deleted"}`), 1)
+		require.NoError(t, err)
+		_, err = node.Command.Execute(context.Background(), time.Now(), mathexp.Vars{}, tracing.InitializeTracerForTest(), nil)
+		require.ErrorIs(t, err, ErrRuleStateRuleNotFound)
+		require.ErrorContains(t, err, "deleted")
+	})
+
+	testCases := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{
+			name:  "missing rule uid",
+			query: `{"type": "rule_state"}`,
+			err:   "no alert rule specified",
+		},
+		{
+			name:  "unknown state",
+			query: `{"type": "rule_state", "ruleUid": "database-down", "states": ["Firing"]}`,
+			err:   "unsupported state 'Firing'",
+		},
+		{
+			name:  "unknown output",
+			query: `{"type": "rule_state", "ruleUid": "database-down", "output": "series"}`,
+			err:   "unsupported output 'series'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.buildRuleStateNode(libraryNode("B", tc.query), 1)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	t.Run("fails without a reader", func(t *testing.T) {
+		s, _ := newMockQueryService(nil, nil)
+		_, err := s.buildRuleStateNode(libraryNode("B", `{"type": "rule_state", "ruleUid": "database-down"}`), 1)
+		require.ErrorContains(t, err, "alert rule states are not available")
+	})
+}
+
+func TestGetRuleStateDependency(t *testing.T) {
+	uid, ok := GetRuleStateDependency(map[string]any{"type": "rule_state", "ruleUid": "database-down"})
+	require.True(t, ok)
+	require.Equal(t, "database-down", uid)
+
+	_, ok = GetRuleStateDependency(map[string]any{"type": "math", "ruleUid": "database-down"})
+	require.False(t, ok)
+}
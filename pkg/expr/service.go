@@ -70,6 +70,13 @@ type Service struct {
 	tracer                    tracing.Tracer
 	metrics                   *metrics.ExprMetrics
 	qsDatasourceClientBuilder dsquerierclient.QSDatasourceClientBuilder
+
+	// library is used to expand library commands, they fail to build when it is nil.
+	library ExpressionLibrary
+
+	// ruleStates is used to read the state of alert rules by rule state commands,
+	// they fail to build when it is nil.
+	ruleStates RuleStateReader
 }
 
 type pluginContextProvider interface {
@@ -78,7 +85,8 @@ type pluginContextProvider interface {
 }
 
 func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider *plugincontext.Provider,
-	features featuremgmt.FeatureToggles, registerer prometheus.Registerer, tracer tracing.Tracer, builder dsquerierclient.QSDatasourceClientBuilder) *Service {
+	features featuremgmt.FeatureToggles, registerer prometheus.Registerer, tracer tracing.Tracer, builder dsquerierclient.QSDatasourceClientBuilder,
+	library ExpressionLibrary, ruleStates RuleStateReader) *Service {
 	return &Service{
 		cfg:           cfg,
 		dataService:   pluginClient,
@@ -92,6 +100,8 @@ func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider
 			Tracer:   tracer,
 		},
 		qsDatasourceClientBuilder: builder,
+		library:                   library,
+		ruleStates:                ruleStates,
 	}
 }
 
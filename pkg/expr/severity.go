@@ -0,0 +1,264 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// DefaultSeverityLabel is the label that holds the severity level of an alert
+// instance when the threshold command does not specify one.
+const DefaultSeverityLabel = "severity"
+
+// LoadedSeverity is the severity level of a result after the previous evaluation.
+type LoadedSeverity struct {
+	// Severity is the name of the severity level.
+	Severity string
+	// RecoveryStreak is the number of consecutive evaluations the recovery threshold of
+	// the severity level has been met.
+	RecoveryStreak int
+}
+
+// LoadedSeverities contains the severity level of every result that was at a level after the
+// previous evaluation. Results are identified by the fingerprint of their labels.
+type LoadedSeverities map[data.Fingerprint]LoadedSeverity
+
+// SeverityThresholdResult is set as the custom metadata of the frames returned by the
+// SeverityThresholdCommand, so the state of each result can be loaded by the next evaluation.
+type SeverityThresholdResult struct {
+	// Severity is the name of the severity level of the result, empty if the result is at none.
+	Severity string
+	// RecoveryStreak is the number of consecutive evaluations the recovery threshold of
+	// the severity level has been met without the result leaving the level.
+	RecoveryStreak int
+	// Label is the name of the label that holds the severity level of the alert instance.
+	Label string
+}
+
+// SeverityLevel is a named threshold with an optional recovery threshold.
+type SeverityLevel struct {
+	Name      string
+	Threshold ThresholdCommand
+	// Recovery is met when a dimension at the level can leave it. When nil, a dimension
+	// leaves the level as soon as Threshold is not met.
+	Recovery *ThresholdCommand
+}
+
+// recovered returns true if a dimension with value f can leave the level.
+func (l SeverityLevel) recovered(f float64) bool {
+	if l.Recovery != nil {
+		return l.Recovery.predicate.Eval(f)
+	}
+	return !l.Threshold.predicate.Eval(f)
+}
+
+// SeverityThresholdCommand is a threshold command with several severity levels, each with its
+// own threshold and recovery threshold. It uses the levels the dimensions were at after the
+// previous evaluation, provided by LoadedSeverities:
+// - a dimension moves to a more severe level as soon as the threshold of that level is met.
+// - a dimension leaves its level when the recovery threshold of the level has been met for
+// RecoveryEvaluations consecutive evaluations. It then moves to the most severe lower level
+// it has not recovered from, or to none.
+// The result is 1 for each dimension at a level and 0 for the others. The labels of the result
+// are the labels of the dimension, so that a dimension that changes level stays the same alert
+// instance. The name of the level is returned in the SeverityThresholdResult of the result, and
+// is added as SeverityLabel to the labels of the alerts sent for the alert instance, so they can
+// be routed by it.
+type SeverityThresholdCommand struct {
+	RefID        string
+	ReferenceVar string
+	// Levels are ordered from the most to the least severe.
+	Levels              []SeverityLevel
+	SeverityLabel       string
+	RecoveryEvaluations int
+	LoadedSeverities    LoadedSeverities
+}
+
+func NewSeverityThresholdCommand(refID, referenceVar string, levels []SeverityLevel, severityLabel string, recoveryEvaluations int, loaded LoadedSeverities) (*SeverityThresholdCommand, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("at least one severity level is required")
+	}
+	for i, level := range levels {
+		if level.Name == "" {
+			return nil, fmt.Errorf("severity level %d has no name", i)
+		}
+		if slices.ContainsFunc(levels[:i], func(l SeverityLevel) bool { return l.Name == level.Name }) {
+			return nil, fmt.Errorf("severity level '%s' is defined more than once", level.Name)
+		}
+	}
+	if severityLabel == "" {
+		severityLabel = DefaultSeverityLabel
+	}
+	if recoveryEvaluations < 0 {
+		return nil, fmt.Errorf("recovery evaluations must not be negative, got %d", recoveryEvaluations)
+	}
+	return &SeverityThresholdCommand{
+		RefID:               refID,
+		ReferenceVar:        referenceVar,
+		Levels:              levels,
+		SeverityLabel:       severityLabel,
+		RecoveryEvaluations: max(recoveryEvaluations, 1),
+		LoadedSeverities:    loaded,
+	}, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (sc *SeverityThresholdCommand) NeedsVars() []string {
+	return []string{sc.ReferenceVar}
+}
+
+func (sc *SeverityThresholdCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteSeverityThreshold")
+	span.SetAttributes(attribute.Int("previousLoadedDimensions", len(sc.LoadedSeverities)))
+	span.SetAttributes(attribute.Int("levels", len(sc.Levels)))
+	defer span.End()
+
+	refVarResult := vars[sc.ReferenceVar]
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
+	for _, val := range refVarResult.Values {
+		switch v := val.(type) {
+		case mathexp.Number:
+			newRes.Values = append(newRes.Values, sc.evaluate(v))
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+		default:
+			return newRes, fmt.Errorf("severity thresholds can only be applied to numbers, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (sc *SeverityThresholdCommand) Type() string {
+	return "severity_threshold"
+}
+
+// evaluate returns the result of the dimension of the number.
+func (sc *SeverityThresholdCommand) evaluate(n mathexp.Number) mathexp.Number {
+	labels := n.GetLabels()
+	current, streak := sc.loadedLevel(labels)
+
+	level := current
+	var value *float64
+	if f := n.GetFloat64Value(); f != nil {
+		level, streak = sc.nextLevel(*f, current, streak)
+		value = new(0.0)
+		if level >= 0 {
+			value = new(1.0)
+		}
+	}
+
+	result := SeverityThresholdResult{RecoveryStreak: streak, Label: sc.SeverityLabel}
+	if level >= 0 {
+		result.Severity = sc.Levels[level].Name
+	}
+	res := mathexp.NewNumber(sc.RefID, labels)
+	res.SetValue(value)
+	res.Frame.Meta.Custom = result
+	return res
+}
+
+// loadedLevel returns the index of the level the dimension was at after the previous evaluation
+// and the number of consecutive evaluations the recovery threshold of the level has been met.
+// It returns -1 if the dimension was at no level, or at a level that no longer exists.
+func (sc *SeverityThresholdCommand) loadedLevel(labels data.Labels) (int, int) {
+	loaded, ok := sc.LoadedSeverities[labels.Fingerprint()]
+	if !ok {
+		return -1, 0
+	}
+	level := slices.IndexFunc(sc.Levels, func(l SeverityLevel) bool { return l.Name == loaded.Severity })
+	if level < 0 {
+		return -1, 0
+	}
+	return level, loaded.RecoveryStreak
+}
+
+// nextLevel returns the level of a dimension with the value f that was at the level current, and
+// the number of consecutive evaluations the recovery threshold of the returned level has been met.
+func (sc *SeverityThresholdCommand) nextLevel(f float64, current, streak int) (int, int) {
+	firing := slices.IndexFunc(sc.Levels, func(l SeverityLevel) bool { return l.Threshold.predicate.Eval(f) })
+	if current < 0 || (firing >= 0 && firing <= current) {
+		return firing, 0
+	}
+	if !sc.Levels[current].recovered(f) {
+		return current, 0
+	}
+	streak++
+	if streak < sc.RecoveryEvaluations {
+		return current, streak
+	}
+	for i := current + 1; i < len(sc.Levels); i++ {
+		if !sc.Levels[i].recovered(f) {
+			return i, 0
+		}
+	}
+	return -1, 0
+}
+
+// LoadedSeveritiesFromFrame converts data.Frame to LoadedSeverities.
+// The input data frame must have a uint64 field with the fingerprints, a string field with the
+// severity levels and an int64 field with the recovery streaks. Returns error if the input data
+// frame has invalid format
+func LoadedSeveritiesFromFrame(frame *data.Frame) (LoadedSeverities, error) {
+	frameType, frameVersion := frame.TypeInfo("")
+	if frameType != "severity_fingerprints" {
+		return nil, fmt.Errorf("invalid format of loaded severities frame: expected frame type 'severity_fingerprints'")
+	}
+	if frameVersion.Greater(data.FrameTypeVersion{1, 0}) {
+		return nil, fmt.Errorf("invalid format of loaded severities frame: expected frame type 'severity_fingerprints' of version 1.0 or lower")
+	}
+	if len(frame.Fields) != 3 {
+		return nil, fmt.Errorf("invalid format of loaded severities frame: expected three fields but got %d", len(frame.Fields))
+	}
+	fps, severities, streaks := frame.Fields[0], frame.Fields[1], frame.Fields[2]
+	if fps.Type() != data.FieldTypeUint64 {
+		return nil, fmt.Errorf("invalid format of loaded severities frame: the type of the first field must be uint64 but got %s", fps.Type().String())
+	}
+	if severities.Type() != data.FieldTypeString {
+		return nil, fmt.Errorf("invalid format of loaded severities frame: the type of the second field must be string but got %s", severities.Type().String())
+	}
+	if streaks.Type() != data.FieldTypeInt64 {
+		return nil, fmt.Errorf("invalid format of loaded severities frame: the type of the third field must be int64 but got %s", streaks.Type().String())
+	}
+	result := make(LoadedSeverities, fps.Len())
+	for i := range fps.Len() {
+		result[data.Fingerprint(fps.At(i).(uint64))] = LoadedSeverity{
+			Severity:       severities.At(i).(string),
+			RecoveryStreak: int(streaks.At(i).(int64)),
+		}
+	}
+	return result, nil
+}
+
+// LoadedSeveritiesToFrame converts LoadedSeverities to data.Frame.
+func LoadedSeveritiesToFrame(loaded LoadedSeverities) *data.Frame {
+	fps := make([]uint64, 0, len(loaded))
+	for fp := range loaded {
+		fps = append(fps, uint64(fp))
+	}
+	slices.Sort(fps)
+	severities := make([]string, 0, len(fps))
+	streaks := make([]int64, 0, len(fps))
+	for _, fp := range fps {
+		severities = append(severities, loaded[data.Fingerprint(fp)].Severity)
+		streaks = append(streaks, int64(loaded[data.Fingerprint(fp)].RecoveryStreak))
+	}
+	frame := data.NewFrame("",
+		data.NewField("fingerprints", nil, fps),
+		data.NewField("severities", nil, severities),
+		data.NewField("recovery_streaks", nil, streaks),
+	)
+	frame.SetMeta(&data.FrameMeta{
+		Type:        "severity_fingerprints",
+		TypeVersion: data.FrameTypeVersion{1, 0},
+	})
+	return frame
+}
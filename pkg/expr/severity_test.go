@@ -0,0 +1,244 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestSeverityThresholdExecute(t *testing.T) {
+	number := func(value *float64) mathexp.Number {
+		n := mathexp.NewNumber("A", data.Labels{"host": "a"})
+		n.SetValue(value)
+		return n
+	}
+	loaded := func(severity string, streak int) LoadedSeverities {
+		return LoadedSeverities{data.Labels{"host": "a"}.Fingerprint(): {Severity: severity, RecoveryStreak: streak}}
+	}
+	level := func(name string, threshold, recovery float64) SeverityLevel {
+		return SeverityLevel{
+			Name:      name,
+			Threshold: ThresholdCommand{ThresholdFunc: ThresholdIsAbove, predicate: greaterThanPredicate{threshold}},
+			Recovery:  &ThresholdCommand{ThresholdFunc: ThresholdIsBelow, predicate: lessThanPredicate{recovery}},
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		recoveryEvaluations int
+		loaded              LoadedSeverities
+		value               *float64
+		expectedSeverity    string
+		expectedValue       *float64
+		expectedStreak      int
+	}{
+		{
+			name:          "below every threshold",
+			value:         new(50.0),
+			expectedValue: new(0.0),
+		},
+		{
+			name:             "uses the most severe level that is met",
+			value:            new(95.0),
+			expectedSeverity: "critical",
+			expectedValue:    new(1.0),
+		},
+		{
+			name:             "uses the least severe level",
+			value:            new(75.0),
+			expectedSeverity: "warning",
+			expectedValue:    new(1.0),
+		},
+		{
+			name:          "does not load a level below its threshold",
+			value:         new(68.0),
+			expectedValue: new(0.0),
+		},
+		{
+			name:             "escalates to a more severe level",
+			loaded:           loaded("warning", 0),
+			value:            new(95.0),
+			expectedSeverity: "critical",
+			expectedValue:    new(1.0),
+		},
+		{
+			name:             "stays at a level until the recovery threshold is met",
+			loaded:           loaded("critical", 0),
+			value:            new(87.0),
+			expectedSeverity: "critical",
+			expectedValue:    new(1.0),
+		},
+		{
+			name:             "steps down to the lower level it has not recovered from",
+			loaded:           loaded("critical", 0),
+			value:            new(68.0),
+			expectedSeverity: "warning",
+			expectedValue:    new(1.0),
+		},
+		{
+			name:          "recovers from every level",
+			loaded:        loaded("critical", 0),
+			value:         new(50.0),
+			expectedValue: new(0.0),
+		},
+		{
+			name:                "holds the level until the recovery threshold is met for enough evaluations",
+			recoveryEvaluations: 3,
+			loaded:              loaded("warning", 1),
+			value:               new(50.0),
+			expectedSeverity:    "warning",
+			expectedValue:       new(1.0),
+			expectedStreak:      2,
+		},
+		{
+			name:                "recovers when the recovery threshold is met for enough evaluations",
+			recoveryEvaluations: 3,
+			loaded:              loaded("warning", 2),
+			value:               new(50.0),
+			expectedValue:       new(0.0),
+		},
+		{
+			name:                "resets the streak when the recovery threshold is not met",
+			recoveryEvaluations: 3,
+			loaded:              loaded("warning", 2),
+			value:               new(67.0),
+			expectedSeverity:    "warning",
+			expectedValue:       new(1.0),
+		},
+		{
+			name:          "ignores a loaded level that no longer exists",
+			loaded:        loaded("major", 0),
+			value:         new(68.0),
+			expectedValue: new(0.0),
+		},
+		{
+			name:             "keeps the level of a dimension without value",
+			loaded:           loaded("critical", 0),
+			expectedSeverity: "critical",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := NewSeverityThresholdCommand("B", "A", []SeverityLevel{
+				level("critical", 90, 85),
+				level("warning", 70, 65),
+			}, "", tc.recoveryEvaluations, tc.loaded)
+			require.NoError(t, err)
+
+			res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+				"A": mathexp.Results{Values: mathexp.Values{number(tc.value)}},
+			}, tracing.InitializeTracerForTest(), nil)
+			require.NoError(t, err)
+			require.Len(t, res.Values, 1)
+
+			n := res.Values[0].(mathexp.Number)
+			require.Equal(t, tc.expectedValue, n.GetFloat64Value())
+			// the severity does not change the labels, so a dimension that changes level stays the same alert instance
+			require.Equal(t, data.Labels{"host": "a"}, n.GetLabels())
+			require.Equal(t, SeverityThresholdResult{Severity: tc.expectedSeverity, RecoveryStreak: tc.expectedStreak, Label: "severity"}, n.Frame.Meta.Custom)
+		})
+	}
+
+	t.Run("fails on series", func(t *testing.T) {
+		cmd, err := NewSeverityThresholdCommand("B", "A", []SeverityLevel{level("critical", 90, 85)}, "", 0, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewSeries("A", nil, 0)}},
+		}, tracing.InitializeTracerForTest(), nil)
+		require.ErrorContains(t, err, "can only be applied to numbers")
+	})
+}
+
+func TestUnmarshalSeverityThresholdCommand(t *testing.T) {
+	t.Run("creates a severity threshold when conditions have a severity", func(t *testing.T) {
+		loaded := LoadedSeverities{1: {Severity: "critical", RecoveryStreak: 2}}
+		frame, err := LoadedSeveritiesToFrame(loaded).MarshalJSON()
+		require.NoError(t, err)
+		cmd, err := UnmarshalThresholdCommand(&rawNode{
+			RefID: "B",
+			QueryRaw: []byte(`{
+				"expression": "A",
+				"severityLabel": "level",
+				"recoveryEvaluations": 3,
+				"conditions": [
+					{"severity": "critical", "evaluator": {"type": "gt", "params": [90]}, "unloadEvaluator": {"type": "lt", "params": [85]}},
+					{"severity": "warning", "evaluator": {"type": "gt", "params": [70]}}
+				],
+				"loadedSeverities": ` + string(frame) + `
+			}`),
+		})
+		require.NoError(t, err)
+		require.IsType(t, &SeverityThresholdCommand{}, cmd)
+		sc := cmd.(*SeverityThresholdCommand)
+		require.Equal(t, "level", sc.SeverityLabel)
+		require.Equal(t, 3, sc.RecoveryEvaluations)
+		require.Equal(t, loaded, sc.LoadedSeverities)
+		require.Len(t, sc.Levels, 2)
+		require.NotNil(t, sc.Levels[0].Recovery)
+		require.Nil(t, sc.Levels[1].Recovery)
+	})
+
+	testCases := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{
+			name:  "not every condition has a severity",
+			query: `{"expression": "A", "conditions": [{"severity": "critical", "evaluator": {"type": "gt", "params": [90]}}, {"evaluator": {"type": "gt", "params": [70]}}]}`,
+			err:   "condition 1 has no severity",
+		},
+		{
+			name:  "severity is defined twice",
+			query: `{"expression": "A", "conditions": [{"severity": "critical", "evaluator": {"type": "gt", "params": [90]}}, {"severity": "critical", "evaluator": {"type": "gt", "params": [70]}}]}`,
+			err:   "severity level 'critical' is defined more than once",
+		},
+		{
+			name:  "negative recovery evaluations",
+			query: `{"expression": "A", "recoveryEvaluations": -1, "conditions": [{"severity": "critical", "evaluator": {"type": "gt", "params": [90]}}]}`,
+			err:   "must not be negative",
+		},
+		{
+			name:  "recovery evaluations without severities",
+			query: `{"expression": "A", "recoveryEvaluations": 2, "conditions": [{"evaluator": {"type": "gt", "params": [90]}}]}`,
+			err:   "can only be used with severity levels",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(tc.query)})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestSeverityThresholdExpression(t *testing.T) {
+	query := map[string]any{
+		"type":       "threshold",
+		"expression": "A",
+		"conditions": []any{
+			map[string]any{"severity": "critical", "evaluator": map[string]any{"type": "gt", "params": []any{90}}, "unloadEvaluator": map[string]any{"type": "lt", "params": []any{85}}},
+		},
+	}
+	require.True(t, IsSeverityThresholdExpression(query))
+	require.False(t, IsHysteresisExpression(query), "severity levels must not be treated as hysteresis")
+
+	require.NoError(t, SetLoadedSeveritiesToThresholdCommand(query, LoadedSeverities{1: {Severity: "critical", RecoveryStreak: 1}}))
+	frame, ok := query["loadedSeverities"].(*data.Frame)
+	require.True(t, ok)
+	loaded, err := LoadedSeveritiesFromFrame(frame)
+	require.NoError(t, err)
+	require.Equal(t, LoadedSeverities{1: {Severity: "critical", RecoveryStreak: 1}}, loaded)
+
+	require.False(t, IsSeverityThresholdExpression(map[string]any{
+		"type":       "threshold",
+		"conditions": []any{map[string]any{"evaluator": map[string]any{"type": "gt", "params": []any{90}}}},
+	}))
+	require.Error(t, SetLoadedSeveritiesToThresholdCommand(map[string]any{"type": "math"}, nil))
+}
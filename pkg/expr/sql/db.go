@@ -20,7 +20,8 @@ import (
 type DB struct {
 	// queryGuard is an optional function that validates SQL queries before execution.
 	// It takes a refID and the raw SQL string, returning true if the query is allowed
-	// and false with an error if the query should be blocked. If nil, AllowQuery is used as the default.
+	// and false with an error if the query should be blocked. If nil, queries are checked with AllowQuery
+	// unless they were allowed when prepared, see Prepare.
 	queryGuard func(refID, rawSQL string) (bool, error)
 }
 
@@ -51,8 +52,7 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 	guard := db.queryGuard
 	if guard == nil {
 		// Use the default query guard if none is provided
-		// AllowQuery is parsing twice due to TablesList, but don't care for now. We can save the parsed query and reuse it later if we want.
-		guard = AllowQuery
+		guard = allowPreparedQuery
 	}
 
 	allow, err := guard(name, query)
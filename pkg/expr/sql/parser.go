@@ -76,3 +76,110 @@ func TablesList(ctx context.Context, rawSQL string) ([]string, error) {
 
 	return result, nil
 }
+
+// IsDeterministic returns false if the sql statement calls a function whose result
+// can change between executions with the same input tables, for example RAND() or
+// NOW(). Functions that are not known to be deterministic are treated as
+// non-deterministic.
+func IsDeterministic(rawSQL string) (bool, error) {
+	stmt, err := sqlparser.Parse(rawSQL)
+	if err != nil {
+		return false, fmt.Errorf("error parsing sql: %s", err.Error())
+	}
+
+	deterministic := true
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch f := node.(type) {
+		case *sqlparser.CurTimeFuncExpr:
+			// NOW(), CURRENT_TIMESTAMP, UTC_TIMESTAMP, LOCALTIME, ...
+			deterministic = false
+		case *sqlparser.FuncExpr:
+			deterministic = deterministicFunction(f)
+		}
+		return deterministic, nil
+	}, stmt)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse SQL expression: %w", err)
+	}
+	return deterministic, nil
+}
+
+// deterministicFunction returns true if the function always returns the same result
+// for the same arguments.
+func deterministicFunction(f *sqlparser.FuncExpr) bool {
+	switch strings.ToLower(f.Name.String()) {
+	// Conditional functions
+	case "if", "coalesce", "ifnull", "nullif", "least", "greatest":
+		return true
+
+	// Aggregation and window functions
+	case "sum", "avg", "count", "min", "max":
+		return true
+	case "stddev", "std", "stddev_pop", "stddev_sample", "variance", "var_pop", "var_samp":
+		return true
+	case "group_concat", "json_objectagg", "json_arrayagg":
+		return true
+	case "row_number", "rank", "dense_rank", "percent_rank", "first_value", "last_value", "ntile", "lead", "lag":
+		return true
+
+	// Mathematical functions
+	case "abs", "round", "floor", "ceiling", "ceil", "sqrt", "pow", "power":
+		return true
+	case "mod", "log", "log2", "log10", "exp", "sign", "ln", "truncate":
+		return true
+	case "sin", "cos", "tan", "cot", "asin", "acos", "atan", "atan2":
+		return true
+	case "conv", "degrees", "radians", "pi":
+		return true
+
+	// String functions
+	case "concat", "length", "char_length", "lower", "upper", "lcase", "ucase":
+		return true
+	case "substring", "substring_index", "left", "right", "mid", "ltrim", "rtrim":
+		return true
+	case "replace", "reverse", "repeat", "position", "instr", "locate":
+		return true
+	case "ascii", "ord", "char", "elt", "quote", "from_base64", "format":
+		return true
+	case "regexp_substr", "regexp_replace", "regexp_instr", "regexp_like":
+		return true
+
+	// Date functions that only depend on their arguments
+	case "str_to_date", "date_format", "get_format", "date_add", "adddate", "date_sub", "subdate":
+		return true
+	case "year", "month", "day", "weekday", "last_day", "yearweek", "weekofyear", "datediff":
+		return true
+	case "from_unixtime", "extract", "hour", "minute", "second", "microsecond":
+		return true
+	case "dayname", "monthname", "dayofweek", "dayofmonth", "dayofyear":
+		return true
+	case "week", "quarter", "time_to_sec", "sec_to_time", "timestampdiff", "timestampadd":
+		return true
+	case "from_days", "to_days", "time_format", "time", "timediff":
+		return true
+	case "unix_timestamp":
+		// without an argument it returns the current time
+		return len(f.Exprs) > 0
+
+	// Type conversion
+	case "cast", "convert":
+		return true
+
+	// JSON functions
+	case "json_extract", "json_object", "json_array", "json_valid":
+		return true
+	case "json_merge", "json_merge_patch", "json_merge_preserve":
+		return true
+	case "json_contains", "json_contains_path", "json_length", "json_type", "json_keys", "json_depth":
+		return true
+	case "json_search", "json_quote", "json_unquote", "json_pretty", "json_value", "json_overlaps":
+		return true
+	case "json_set", "json_insert", "json_replace", "json_remove", "json_array_append", "json_array_insert":
+		return true
+
+	default:
+		// rand, uuid, now, sysdate, curdate, curtime, and any function that is
+		// not known to be deterministic
+		return false
+	}
+}
@@ -131,3 +131,33 @@ func TestTablesList(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDeterministic(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected bool
+	}{
+		{sql: "SELECT * FROM A", expected: true},
+		{sql: "SELECT value * RAND() FROM A", expected: false},
+		{sql: "SELECT * FROM A WHERE time > UNIX_TIMESTAMP() - 60", expected: false},
+		{sql: "SELECT UNIX_TIMESTAMP(time) FROM A", expected: true},
+		{sql: "SELECT * FROM (SELECT rand() AS r FROM A) AS t", expected: false},
+		{sql: "SELECT NOW() FROM A", expected: false},
+		{sql: "SELECT * FROM A WHERE time > CURRENT_TIMESTAMP", expected: false},
+		{sql: "SELECT UTC_TIMESTAMP() FROM A", expected: false},
+		{sql: "SELECT LOCALTIME FROM A", expected: false},
+		{sql: "SELECT SYSDATE() FROM A", expected: false},
+		{sql: "SELECT CURDATE() FROM A", expected: false},
+		{sql: "SELECT UUID() FROM A", expected: false},
+		{sql: "SELECT unknown_function(value) FROM A", expected: false},
+		{sql: "SELECT ROUND(AVG(value), 2), DATE_FORMAT(time, '%Y') FROM A GROUP BY 2", expected: true},
+		{sql: "SELECT COALESCE(value, 0) FROM A", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			d, err := IsDeterministic(tt.sql)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, d)
+		})
+	}
+}
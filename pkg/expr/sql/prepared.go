@@ -0,0 +1,67 @@
+package sql
+
+import (
+	"context"
+	"slices"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// preparedQueriesSize is the number of prepared queries that are kept.
+const preparedQueriesSize = 1000
+
+// preparedQueries holds the prepared queries by the text of the query.
+var preparedQueries, _ = lru.New[string, PreparedQuery](preparedQueriesSize)
+
+// PreparedQuery is what is known about a SQL query from its text alone. It does not
+// depend on the input of the query, so it is parsed once and reused by all executions
+// of the same query.
+type PreparedQuery struct {
+	// Tables are the tables that the query reads, see TablesList.
+	Tables []string
+	// Deterministic is false if the query calls a function whose result can change
+	// between executions with the same input, see IsDeterministic.
+	Deterministic bool
+
+	// allowed is true if the query passed AllowQuery.
+	allowed bool
+}
+
+// Prepare parses the query, or returns the prepared query of a previous call with
+// the same query text.
+func Prepare(ctx context.Context, refID, rawSQL string) (PreparedQuery, error) {
+	if q, ok := preparedQueries.Get(rawSQL); ok {
+		q.Tables = slices.Clone(q.Tables)
+		return q, nil
+	}
+
+	tables, err := TablesList(ctx, rawSQL)
+	if err != nil {
+		return PreparedQuery{}, err
+	}
+	deterministic, err := IsDeterministic(rawSQL)
+	if err != nil {
+		return PreparedQuery{}, err
+	}
+	// Queries that are not allowed are prepared too, as the error is returned
+	// when the query is executed.
+	allowed, err := AllowQuery(refID, rawSQL)
+
+	q := PreparedQuery{
+		Tables:        tables,
+		Deterministic: deterministic,
+		allowed:       allowed && err == nil,
+	}
+	preparedQueries.Add(rawSQL, q)
+	q.Tables = slices.Clone(tables)
+	return q, nil
+}
+
+// allowPreparedQuery is the default query guard. It checks the query with AllowQuery
+// unless the query was prepared and allowed.
+func allowPreparedQuery(refID, rawSQL string) (bool, error) {
+	if q, ok := preparedQueries.Get(rawSQL); ok && q.allowed {
+		return true, nil
+	}
+	return AllowQuery(refID, rawSQL)
+}
@@ -0,0 +1,41 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepare(t *testing.T) {
+	query := "SELECT A.value AS prepare_test FROM B JOIN A ON A.time = B.time"
+	q, err := Prepare(t.Context(), "C", query)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, q.Tables)
+	require.True(t, q.Deterministic)
+	require.True(t, q.allowed)
+
+	// the prepared query is reused, and changing it does not change the cached query
+	q.Tables[0] = "changed"
+	q, err = Prepare(t.Context(), "C", query)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, q.Tables)
+
+	q, err = Prepare(t.Context(), "C", "SELECT RAND() AS prepare_test FROM A")
+	require.NoError(t, err)
+	require.False(t, q.Deterministic)
+
+	_, err = Prepare(t.Context(), "C", "SELECT * FROM")
+	require.Error(t, err)
+}
+
+func TestAllowPreparedQuery(t *testing.T) {
+	query := "SELECT LOAD_FILE('/etc/hostname') AS prepare_test FROM A"
+	q, err := Prepare(t.Context(), "C", query)
+	require.NoError(t, err)
+	require.False(t, q.allowed)
+
+	// queries that are not allowed are checked again to return the error
+	allowed, err := allowPreparedQuery("C", query)
+	require.Error(t, err)
+	require.False(t, allowed)
+}
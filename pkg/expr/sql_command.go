@@ -45,11 +45,12 @@ func NewSQLCommand(ctx context.Context, logger log.Logger, refID, format, rawSQL
 	if rawSQL == "" {
 		return nil, sql.MakeErrEmptyQuery(refID)
 	}
-	tables, err := sql.TablesList(ctx, rawSQL)
+	prepared, err := sql.Prepare(ctx, refID, rawSQL)
 	if err != nil {
 		sqlLogger.Warn("invalid sql query", "sql", rawSQL, "error", err)
 		return nil, sql.MakeErrInvalidQuery(refID, err)
 	}
+	tables := prepared.Tables
 	if len(tables) == 0 {
 		sqlLogger.Warn("no tables found in SQL query", "sql", rawSQL)
 	}
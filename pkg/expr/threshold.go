@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -138,7 +139,14 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	}
 	referenceVar := cmdConfig.Expression
 
-	// we only support one condition for now, we might want to turn this in to "OR" expressions later
+	if cmdConfig.hasSeverities() {
+		return unmarshalSeverityThresholdCommand(rn.RefID, referenceVar, cmdConfig)
+	}
+	if cmdConfig.SeverityLabel != "" || cmdConfig.RecoveryEvaluations != 0 {
+		return nil, fmt.Errorf("severity label and recovery evaluations can only be used with severity levels")
+	}
+
+	// we only support one condition without a severity, we might want to turn this in to "OR" expressions later
 	if len(cmdConfig.Conditions) != 1 {
 		return nil, fmt.Errorf("threshold expression requires exactly one condition")
 	}
@@ -166,6 +174,36 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	return threshold, nil
 }
 
+func unmarshalSeverityThresholdCommand(refID, referenceVar string, cmdConfig ThresholdCommandConfig) (Command, error) {
+	levels := make([]SeverityLevel, 0, len(cmdConfig.Conditions))
+	for i, condition := range cmdConfig.Conditions {
+		if condition.Severity == "" {
+			return nil, fmt.Errorf("condition %d has no severity, either all or none of the conditions must have one", i)
+		}
+		threshold, err := NewThresholdCommand(refID, referenceVar, condition.Evaluator.Type, condition.Evaluator.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition of severity '%s': %w", condition.Severity, err)
+		}
+		level := SeverityLevel{Name: condition.Severity, Threshold: *threshold}
+		if condition.UnloadEvaluator != nil {
+			level.Recovery, err = NewThresholdCommand(refID, referenceVar, condition.UnloadEvaluator.Type, condition.UnloadEvaluator.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unloadCondition of severity '%s': %w", condition.Severity, err)
+			}
+		}
+		levels = append(levels, level)
+	}
+	var loaded LoadedSeverities
+	if cmdConfig.LoadedSeverities != nil {
+		var err error
+		loaded, err = LoadedSeveritiesFromFrame(cmdConfig.LoadedSeverities)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse loaded severities: %w", err)
+		}
+	}
+	return NewSeverityThresholdCommand(refID, referenceVar, levels, cmdConfig.SeverityLabel, cmdConfig.RecoveryEvaluations, loaded)
+}
+
 // NeedsVars returns the variable names (refIds) that are dependencies
 // to execute the command and allows the command to fulfill the Command interface.
 func (tc *ThresholdCommand) NeedsVars() []string {
@@ -230,14 +268,26 @@ func IsSupportedThresholdFunc(name string) bool {
 }
 
 type ThresholdCommandConfig struct {
-	Expression string                   `json:"expression"`
-	Conditions []ThresholdConditionJSON `json:"conditions"`
+	Expression          string                   `json:"expression"`
+	Conditions          []ThresholdConditionJSON `json:"conditions"`
+	SeverityLabel       string                   `json:"severityLabel,omitempty"`
+	RecoveryEvaluations int                      `json:"recoveryEvaluations,omitempty"`
+	LoadedSeverities    *data.Frame              `json:"loadedSeverities,omitempty"`
+}
+
+// hasSeverities returns true if any of the conditions has a severity.
+func (c ThresholdCommandConfig) hasSeverities() bool {
+	return slices.ContainsFunc(c.Conditions, func(condition ThresholdConditionJSON) bool {
+		return condition.Severity != ""
+	})
 }
 
 type ThresholdConditionJSON struct {
 	Evaluator        ConditionEvalJSON  `json:"evaluator"`
 	UnloadEvaluator  *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
 	LoadedDimensions *data.Frame        `json:"loadedDimensions,omitempty"`
+	// The name of the severity level of the condition. Conditions with a severity are ordered from the most to the least severe
+	Severity string `json:"severity,omitempty"`
 }
 
 // IsHysteresisExpression returns true if the raw model describes a hysteresis command:
@@ -252,6 +302,36 @@ func IsHysteresisExpression(query map[string]any) bool {
 	return c != nil
 }
 
+// IsSeverityThresholdExpression returns true if the raw model describes a threshold command
+// with severity levels, i.e. a condition of field 'conditions' has field 'severity'.
+func IsSeverityThresholdExpression(query map[string]any) bool {
+	t, err := GetExpressionCommandType(query)
+	if err != nil || t != TypeThreshold {
+		return false
+	}
+	conditions, ok := query["conditions"].([]any)
+	if !ok {
+		return false
+	}
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]any); ok {
+			if severity, ok := condition["severity"].(string); ok && severity != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetLoadedSeveritiesToThresholdCommand mutates the input map and sets field "loadedSeverities" with the data frame created from the provided severities.
+func SetLoadedSeveritiesToThresholdCommand(query map[string]any, loaded LoadedSeverities) error {
+	if !IsSeverityThresholdExpression(query) {
+		return errors.New("not a threshold command with severity levels")
+	}
+	query["loadedSeverities"] = LoadedSeveritiesToFrame(loaded)
+	return nil
+}
+
 // SetLoadedDimensionsToHysteresisCommand mutates the input map and sets field "conditions[0].loadedMetrics" with the data frame created from the provided fingerprints.
 func SetLoadedDimensionsToHysteresisCommand(query map[string]any, fingerprints Fingerprints) error {
 	condition, err := getConditionForHysteresisCommand(query)
@@ -298,6 +378,10 @@ func getConditionForHysteresisCommand(query map[string]any) (map[string]any, err
 	if !ok {
 		return nil, nil
 	}
+	// a condition with a severity is a severity level, see IsSeverityThresholdExpression
+	if severity, ok := condition["severity"].(string); ok && severity != "" {
+		return nil, nil
+	}
 	return condition, nil
 }
 
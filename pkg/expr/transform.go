@@ -16,6 +16,8 @@ import (
 type Request struct {
 	Headers map[string]string
 	Debug   bool
+	// Explain adds an Explanation of the execution of every node to the response.
+	Explain bool
 	OrgId   int64
 	Queries []Query
 	User    identity.Requester
@@ -93,6 +95,11 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		return nil, err
 	}
 
+	var explanation *Explanation
+	if req.Explain {
+		ctx, explanation = ContextWithExplanation(ctx)
+	}
+
 	// Execute the pipeline. Disabled nodes (e.g. those with missing dependencies)
 	// inject their errors into the result vars during execution; downstream nodes
 	// fail via the existing Mode 1 dependency-error path.
@@ -118,6 +125,11 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		responses = filteredRes
 	}
 
+	// The explanation covers hidden queries too, as they are part of the pipeline.
+	if explanation != nil {
+		responses.Responses[ExplainRefID] = explanation.DataResponse()
+	}
+
 	return responses, nil
 }
 
@@ -303,6 +303,8 @@ func prepareQuery(
 		nil,
 		b.tracer,
 		qsDsClientBuilder,
+		nil, // library
+		nil, // ruleStates
 	)
 
 	return &preparedQuery{
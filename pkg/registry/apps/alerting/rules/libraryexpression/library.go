@@ -0,0 +1,64 @@
+package libraryexpression
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	libraryexpression_app "github.com/grafana/grafana/apps/alerting/rules/pkg/app/libraryexpression"
+	"github.com/grafana/grafana/pkg/expr"
+	reqns "github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var _ expr.ExpressionLibrary = (*ExpressionLibrary)(nil)
+
+// ExpressionLibrary serves the library expressions of the watch-backed index to
+// the expression engine.
+type ExpressionLibrary struct {
+	index      *libraryexpression_app.Index
+	namespacer reqns.NamespaceMapper
+}
+
+func NewExpressionLibrary(index *libraryexpression_app.Index, namespacer reqns.NamespaceMapper) *ExpressionLibrary {
+	return &ExpressionLibrary{
+		index:      index,
+		namespacer: namespacer,
+	}
+}
+
+// ProvideExpressionLibrary creates the library with an empty index. The index is
+// filled by the alerting rules app, which watches the library expressions.
+func ProvideExpressionLibrary(cfg *setting.Cfg) *ExpressionLibrary {
+	return NewExpressionLibrary(libraryexpression_app.NewIndex(), reqns.GetNamespaceMapper(cfg))
+}
+
+// Index returns the index that the library reads the library expressions from.
+func (l *ExpressionLibrary) Index() *libraryexpression_app.Index {
+	return l.index
+}
+
+func (l *ExpressionLibrary) GetLibraryExpression(_ context.Context, orgID int64, uid string) (*expr.LibraryExpression, error) {
+	le, ok := l.index.Get(l.namespacer(orgID), uid)
+	if !ok {
+		return nil, expr.ErrLibraryExpressionNotFound
+	}
+
+	result := &expr.LibraryExpression{
+		UID:         le.Name,
+		Parameters:  make([]string, 0, len(le.Spec.Parameters)),
+		Expressions: make(map[string]json.RawMessage, len(le.Spec.Expressions)),
+		Output:      le.Spec.Output,
+	}
+	for _, p := range le.Spec.Parameters {
+		result.Parameters = append(result.Parameters, string(p.Name))
+	}
+	for refID, node := range le.Spec.Expressions {
+		model, err := json.Marshal(node.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the model of expression '%s': %w", refID, err)
+		}
+		result.Expressions[refID] = model
+	}
+	return result, nil
+}
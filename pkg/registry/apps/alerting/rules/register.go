@@ -23,6 +23,7 @@ import (
 	grafanarest "github.com/grafana/grafana/pkg/apiserver/rest"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/alertrule"
+	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/libraryexpression"
 	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/recordingrule"
 	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/rulesequence"
 	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/search"
@@ -56,6 +57,7 @@ func RegisterAppInstaller(
 	unifiedClient unifiedresource.ResourceClient,
 	dual dualwrite.Service,
 	_ resource.ClientGenerator, // retained for Wire compatibility; membership resolution now uses a watch-backed index
+	library *libraryexpression.ExpressionLibrary,
 ) (*AppInstaller, error) {
 	if ng.IsDisabled() {
 		log.New("app-registry").Info("Skipping Kubernetes Alerting Rules apiserver (rules.alerting.grafana.app): Unified Alerting is disabled")
@@ -69,6 +71,11 @@ func RegisterAppInstaller(
 
 	membershipIndex := rulesequence_app.NewMembershipIndex()
 
+	// Library expressions are expanded when a rule's pipeline is built, which
+	// happens on every evaluation, so rules use the latest definitions. The
+	// expression service reads them from the index that the app keeps up to date.
+	libraryIndex := library.Index()
+
 	// Search routes through a dual-writer-aware client per kind: the legacy
 	// backend (provisioning service) serves modes 0-3, the unified client 4+.
 	legacySearch := search.NewLegacyClient(*ng.Api.AlertRules)
@@ -84,6 +91,7 @@ func RegisterAppInstaller(
 		ReservedLabelKeys:             ngmodels.LabelsUserCannotSpecify,
 		ResolveRuleRef:                newRuleRefResolver(ng),
 		MembershipResolver:            membershipIndex,
+		LibraryExpressionWatcher:      libraryIndex,
 		NotificationSettingsValidator: newNotificationSettingsValidator(ng),
 		WatchNamespace:                watchNamespace(cfg),
 		SearchRulesHandler:            search.WithAPIStatusErrorResponse(searchHandler.SearchRules),
@@ -226,6 +234,8 @@ func (a *AppInstaller) GetAuthorizer() authorizer.Authorizer {
 				return alertrule.Authorize(ctx, authz, a)
 			case rulesequence.ResourceInfo.GroupResource().Resource:
 				return rulesequence.Authorize(ctx, authz, a)
+			case libraryexpression.ResourceInfo.GroupResource().Resource:
+				return libraryexpression.Authorize(ctx, authz, a)
 			case search.RouteResource:
 				return search.Authorize(ctx, authz, a)
 			}
@@ -249,6 +259,8 @@ func (a *AppInstaller) GetLegacyStorage(gvr schema.GroupVersionResource) grafana
 		return alertrule.NewStorage(*a.ng.Api.AlertRules, namespacer)
 	case rulesequence.ResourceInfo.GroupVersionResource():
 		return nil
+	case libraryexpression.ResourceInfo.GroupVersionResource():
+		return nil
 	default:
 		panic("unknown legacy storage requested: " + gvr.String())
 	}
@@ -3,6 +3,7 @@ package rules
 import (
 	"testing"
 
+	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/libraryexpression"
 	"github.com/grafana/grafana/pkg/services/ngalert"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -45,7 +46,7 @@ func TestRegisterAppInstaller_UnifiedAlertingEnabled(t *testing.T) {
 			cfg := &setting.Cfg{UnifiedAlerting: setting.UnifiedAlertingSettings{Enabled: &enabled}}
 			ng := &ngalert.AlertNG{Cfg: cfg, Api: &api.API{AlertRules: &provisioning.AlertRuleService{}}}
 
-			inst, err := RegisterAppInstaller(cfg, ng, nil, nil, nil)
+			inst, err := RegisterAppInstaller(cfg, ng, nil, nil, nil, libraryexpression.ProvideExpressionLibrary(cfg))
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
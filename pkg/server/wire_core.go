@@ -57,6 +57,7 @@ import (
 	secretsecurevalueservice "github.com/grafana/grafana/pkg/registry/apis/secret/service"
 	secretvalidator "github.com/grafana/grafana/pkg/registry/apis/secret/validator"
 	appregistry "github.com/grafana/grafana/pkg/registry/apps"
+	"github.com/grafana/grafana/pkg/registry/apps/alerting/rules/libraryexpression"
 	playlistmigrator "github.com/grafana/grafana/pkg/registry/apps/playlist/migrator"
 	querycachingmigrator "github.com/grafana/grafana/pkg/registry/apps/querycaching/migrator"
 	shorturlmigrator "github.com/grafana/grafana/pkg/registry/apps/shorturl/migrator"
@@ -121,6 +122,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert"
 	ngimage "github.com/grafana/grafana/pkg/services/ngalert/image"
 	ngmetrics "github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngstate "github.com/grafana/grafana/pkg/services/ngalert/state"
 	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
@@ -326,6 +328,10 @@ var wireBasicSet = wire.NewSet(
 	wire.Bind(new(serviceaccounts.Service), new(*serviceaccountsproxy.ServiceAccountsProxy)),
 	dsquerierclient.NewNullQSDatasourceClientBuilder,
 	expr.ProvideService,
+	libraryexpression.ProvideExpressionLibrary,
+	wire.Bind(new(expr.ExpressionLibrary), new(*libraryexpression.ExpressionLibrary)),
+	ngstate.ProvideRuleStateReader,
+	wire.Bind(new(expr.RuleStateReader), new(*ngstate.RuleStateReader)),
 	featuremgmt.ProvideManagerService,
 	featuremgmt.ProvideToggles,
 	dashboardservice.ProvideDashboardServiceImpl,
@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/inhibition_rules"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/routes"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/rulepolicy"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -64,9 +65,16 @@ type API struct {
 	RuleStore             RuleStore
 	AlertingStore         store.AlertingStore
 	AdminConfigStore      store.AdminConfigurationStore
+	RulePolicyStore       store.RulePolicyStore
+	RulePolicyEnforcer    *rulepolicy.Enforcer
+	MaintenanceWindows    MaintenanceWindowService
+	RuleShards            RuleShards
+	StateArchiver         AlertStateArchiver
 	DataProxy             *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager  *notifier.MultiOrgAlertmanager
 	StateManager          state.AlertInstanceManager
+	AlertAcknowledger     AlertAcknowledger
+	RecordingBackfiller   RecordingRuleBackfiller
 	RuleMutator           apiprometheus.RuleMutator
 	AccessControl         ac.AccessControl
 	ReceiverService       *notifier.ReceiverService
@@ -128,7 +136,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			ruleAuthzService,
 			api.SilenceLimitsProvider,
 		),
-		receiverAuthz: accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+		receiverAuthz:   accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+		receiverDigests: api.ReceiverService,
 	}), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
@@ -153,6 +162,11 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			amRefresher:        api.MultiOrgAlertmanager,
 			featureManager:     api.FeatureManager,
 			userService:        api.UserService,
+			rulePolicy:         api.RulePolicyEnforcer,
+			datasourceCache:    api.DatasourceCache,
+			alertInstances:     api.StateManager,
+			acknowledger:       api.AlertAcknowledger,
+			backfiller:         api.RecordingBackfiller,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
@@ -163,16 +177,23 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			authz:           ruleAuthzService,
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
-			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer, api.Cfg.UnifiedAlerting, api.FeatureManager),
+			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer, api.Cfg.UnifiedAlerting, api.FeatureManager, api.MultiOrgAlertmanager, api.Historian),
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
 			folderService:   api.RuleStore,
+			ruleStore:       api.RuleStore,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
 			datasourceService:    api.DatasourceService,
 			store:                api.AdminConfigStore,
+			rulePolicyStore:      api.RulePolicyStore,
+			rulePolicy:           api.RulePolicyEnforcer,
+			ruleStore:            api.RuleStore,
+			maintenanceWindows:   api.MaintenanceWindows,
+			ruleShards:           api.RuleShards,
+			stateArchiver:        api.StateArchiver,
 			cfg:                  &api.Cfg.UnifiedAlerting,
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
@@ -186,6 +207,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		namespaces:          api.RuleStore,
+		xactManager:         api.TransactionManager,
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
 	}), m)
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// AlertStateArchiver exports and imports the state of the alerts of an organization.
+type AlertStateArchiver interface {
+	Export(ctx context.Context, orgID int64) (*apimodels.AlertStateArchive, error)
+	Import(ctx context.Context, orgID int64, archive apimodels.AlertStateArchive, opts statearchive.ImportOptions) (apimodels.AlertStateImportResult, error)
+}
+
+func (srv ConfigSrv) RouteGetAlertStateExport(c *contextmodel.ReqContext) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	archive, err := srv.stateArchiver.Export(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to export alert state")
+	}
+	return response.JSON(http.StatusOK, archive)
+}
+
+func (srv ConfigSrv) RoutePostAlertStateImport(c *contextmodel.ReqContext, body apimodels.PostableAlertStateImport) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	result, err := srv.stateArchiver.Import(c.Req.Context(), c.GetOrgID(), body.Archive, statearchive.ImportOptions{
+		RuleUIDs:   body.RuleUIDs,
+		FolderUIDs: body.FolderUIDs,
+	})
+	if err != nil {
+		if errors.Is(err, statearchive.ErrInvalidArchive) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to import alert state")
+	}
+	srv.log.Info("Imported alert state", "user", c.SignedInUser.GetLogin(), "rules", result.Rules, "instances", result.Instances)
+	return response.JSON(http.StatusOK, result)
+}
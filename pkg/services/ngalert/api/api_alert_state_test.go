@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+type fakeStateArchiver struct {
+	exportedOrg int64
+	imported    *definitions.AlertStateArchive
+	opts        statearchive.ImportOptions
+	err         error
+}
+
+func (f *fakeStateArchiver) Export(_ context.Context, orgID int64) (*definitions.AlertStateArchive, error) {
+	f.exportedOrg = orgID
+	return &definitions.AlertStateArchive{Version: statearchive.Version, OrgID: orgID}, f.err
+}
+
+func (f *fakeStateArchiver) Import(_ context.Context, _ int64, archive definitions.AlertStateArchive, opts statearchive.ImportOptions) (definitions.AlertStateImportResult, error) {
+	f.imported = &archive
+	f.opts = opts
+	return definitions.AlertStateImportResult{Rules: 1, Instances: 2, SkippedRules: []string{}}, f.err
+}
+
+func TestRouteAlertState(t *testing.T) {
+	t.Run("exports the state of the organization", func(t *testing.T) {
+		archiver := &fakeStateArchiver{}
+		sut := ConfigSrv{stateArchiver: archiver, log: log.NewNopLogger()}
+		ctx := createRequestCtxInOrg(3)
+		ctx.OrgRole = org.RoleAdmin
+
+		resp := sut.RouteGetAlertStateExport(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var archive definitions.AlertStateArchive
+		require.NoError(t, json.Unmarshal(resp.Body(), &archive))
+		require.EqualValues(t, 3, archive.OrgID)
+		require.EqualValues(t, 3, archiver.exportedOrg)
+	})
+
+	t.Run("imports the archive with the UID mappings", func(t *testing.T) {
+		archiver := &fakeStateArchiver{}
+		sut := ConfigSrv{stateArchiver: archiver, log: log.NewNopLogger()}
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		resp := sut.RoutePostAlertStateImport(ctx, definitions.PostableAlertStateImport{
+			Archive:    definitions.AlertStateArchive{Version: statearchive.Version, OrgID: 3},
+			RuleUIDs:   map[string]string{"a": "b"},
+			FolderUIDs: map[string]string{"c": "d"},
+		})
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.EqualValues(t, 3, archiver.imported.OrgID)
+		require.Equal(t, map[string]string{"a": "b"}, archiver.opts.RuleUIDs)
+		require.Equal(t, map[string]string{"c": "d"}, archiver.opts.FolderUIDs)
+	})
+
+	t.Run("returns bad request for an invalid archive", func(t *testing.T) {
+		archiver := &fakeStateArchiver{err: fmt.Errorf("%w: unsupported version", statearchive.ErrInvalidArchive)}
+		sut := ConfigSrv{stateArchiver: archiver, log: log.NewNopLogger()}
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		resp := sut.RoutePostAlertStateImport(ctx, definitions.PostableAlertStateImport{})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("requires the organization admin role", func(t *testing.T) {
+		sut := ConfigSrv{stateArchiver: &fakeStateArchiver{}, log: log.NewNopLogger()}
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleEditor
+
+		require.Equal(t, http.StatusForbidden, sut.RouteGetAlertStateExport(ctx).Status())
+		require.Equal(t, http.StatusForbidden, sut.RoutePostAlertStateImport(ctx, definitions.PostableAlertStateImport{}).Status())
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
+)
+
+// ReceiverDigestService manages the digest mode of the receivers.
+type ReceiverDigestService interface {
+	GetReceiverDigest(ctx context.Context, uid string, orgID int64, user identity.Requester) (*ngmodels.ReceiverDigest, error)
+	SetReceiverDigest(ctx context.Context, uid string, digest ngmodels.ReceiverDigest, orgID int64, user identity.Requester) (*ngmodels.ReceiverDigest, error)
+	DeleteReceiverDigest(ctx context.Context, uid string, orgID int64, user identity.Requester) error
+}
+
+func (srv AlertmanagerSrv) RouteGetReceiverDigest(c *contextmodel.ReqContext, name string) response.Response {
+	digest, err := srv.receiverDigests.GetReceiverDigest(c.Req.Context(), legacy_storage.NameToUid(name), c.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get receiver digest", err)
+	}
+	return response.JSON(http.StatusOK, receiverDigestToAPI(*digest))
+}
+
+func (srv AlertmanagerSrv) RoutePutReceiverDigest(c *contextmodel.ReqContext, body apimodels.ReceiverDigest, name string) response.Response {
+	digest := ngmodels.ReceiverDigest{
+		Window:         time.Duration(body.Window),
+		BypassMatchers: labels.Matchers(body.BypassMatchers),
+	}
+	saved, err := srv.receiverDigests.SetReceiverDigest(c.Req.Context(), legacy_storage.NameToUid(name), digest, c.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to save receiver digest", err)
+	}
+	return response.JSON(http.StatusOK, receiverDigestToAPI(*saved))
+}
+
+func (srv AlertmanagerSrv) RouteDeleteReceiverDigest(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.receiverDigests.DeleteReceiverDigest(c.Req.Context(), legacy_storage.NameToUid(name), c.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete receiver digest", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func receiverDigestToAPI(d ngmodels.ReceiverDigest) apimodels.ReceiverDigest {
+	return apimodels.ReceiverDigest{
+		Window:         model.Duration(d.Window),
+		BypassMatchers: apimodels.ObjectMatchers(d.BypassMatchers),
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/rulepolicy"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/setting"
@@ -32,6 +33,12 @@ type ConfigSrv struct {
 	datasourceService    datasources.DataSourceService
 	alertmanagerProvider ExternalAlertmanagerProvider
 	store                store.AdminConfigurationStore
+	rulePolicyStore      store.RulePolicyStore
+	rulePolicy           *rulepolicy.Enforcer
+	ruleStore            RuleStore
+	maintenanceWindows   MaintenanceWindowService
+	ruleShards           RuleShards
+	stateArchiver        AlertStateArchiver
 	cfg                  *setting.UnifiedAlertingSettings
 	log                  log.Logger
 }
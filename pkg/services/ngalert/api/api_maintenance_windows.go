@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MaintenanceWindowService manages the maintenance windows of the organizations.
+type MaintenanceWindowService interface {
+	ListMaintenanceWindows(ctx context.Context, orgID int64) ([]*ngmodels.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*ngmodels.MaintenanceWindow, error)
+	CreateMaintenanceWindow(ctx context.Context, w ngmodels.MaintenanceWindow) (*ngmodels.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, w ngmodels.MaintenanceWindow) (*ngmodels.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, user string) error
+}
+
+func (srv ConfigSrv) RouteGetMaintenanceWindows(c *contextmodel.ReqContext) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	windows, err := srv.maintenanceWindows.ListMaintenanceWindows(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return maintenanceWindowErrorResponse(err, "failed to get maintenance windows")
+	}
+	now := time.Now()
+	result := make(apimodels.MaintenanceWindows, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, maintenanceWindowToAPI(*w, now))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv ConfigSrv) RouteGetMaintenanceWindow(c *contextmodel.ReqContext, uid string) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	w, err := srv.maintenanceWindows.GetMaintenanceWindow(c.Req.Context(), c.GetOrgID(), uid)
+	if err != nil {
+		return maintenanceWindowErrorResponse(err, "failed to get maintenance window")
+	}
+	return response.JSON(http.StatusOK, maintenanceWindowToAPI(*w, time.Now()))
+}
+
+func (srv ConfigSrv) RoutePostMaintenanceWindow(c *contextmodel.ReqContext, body apimodels.MaintenanceWindow) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	w, err := maintenanceWindowFromAPI(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	w.OrgID = c.GetOrgID()
+	w.CreatedBy = c.SignedInUser.GetLogin()
+	created, err := srv.maintenanceWindows.CreateMaintenanceWindow(c.Req.Context(), w)
+	if err != nil {
+		return maintenanceWindowErrorResponse(err, "failed to create maintenance window")
+	}
+	return response.JSON(http.StatusCreated, maintenanceWindowToAPI(*created, time.Now()))
+}
+
+func (srv ConfigSrv) RoutePutMaintenanceWindow(c *contextmodel.ReqContext, body apimodels.MaintenanceWindow, uid string) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	w, err := maintenanceWindowFromAPI(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	w.OrgID = c.GetOrgID()
+	w.UID = uid
+	w.UpdatedBy = c.SignedInUser.GetLogin()
+	updated, err := srv.maintenanceWindows.UpdateMaintenanceWindow(c.Req.Context(), w)
+	if err != nil {
+		return maintenanceWindowErrorResponse(err, "failed to update maintenance window")
+	}
+	return response.JSON(http.StatusOK, maintenanceWindowToAPI(*updated, time.Now()))
+}
+
+func (srv ConfigSrv) RouteDeleteMaintenanceWindow(c *contextmodel.ReqContext, uid string) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	if err := srv.maintenanceWindows.DeleteMaintenanceWindow(c.Req.Context(), c.GetOrgID(), uid, c.SignedInUser.GetLogin()); err != nil {
+		return maintenanceWindowErrorResponse(err, "failed to delete maintenance window")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "maintenance window deleted"})
+}
+
+func maintenanceWindowErrorResponse(err error, msg string) response.Response {
+	if errors.Is(err, ngmodels.ErrMaintenanceWindowNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, ngmodels.ErrMaintenanceWindowInvalid) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, msg)
+}
+
+func maintenanceWindowFromAPI(w apimodels.MaintenanceWindow) (ngmodels.MaintenanceWindow, error) {
+	matchers := make(labels.Matchers, 0, len(w.Matchers))
+	for _, m := range w.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			return ngmodels.MaintenanceWindow{}, fmt.Errorf("%w: matchers must have a name and a value", ngmodels.ErrMaintenanceWindowInvalid)
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		t := labels.MatchEqual
+		switch {
+		case isEqual && isRegex:
+			t = labels.MatchRegexp
+		case !isEqual && isRegex:
+			t = labels.MatchNotRegexp
+		case !isEqual:
+			t = labels.MatchNotEqual
+		}
+		matcher, err := labels.NewMatcher(t, *m.Name, *m.Value)
+		if err != nil {
+			return ngmodels.MaintenanceWindow{}, fmt.Errorf("%w: %s", ngmodels.ErrMaintenanceWindowInvalid, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return ngmodels.MaintenanceWindow{
+		UID:                 w.UID,
+		Title:               w.Title,
+		Schedule:            w.Schedule,
+		Timezone:            w.Timezone,
+		Duration:            time.Duration(w.Duration),
+		Matchers:            matchers,
+		PauseRuleEvaluation: w.PauseRuleEvaluation,
+	}, nil
+}
+
+func maintenanceWindowToAPI(w ngmodels.MaintenanceWindow, now time.Time) apimodels.MaintenanceWindow {
+	matchers := make(amv2.Matchers, 0, len(w.Matchers))
+	for _, m := range w.Matchers {
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    new(m.Name),
+			Value:   new(m.Value),
+			IsRegex: new(m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp),
+			IsEqual: new(m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp),
+		})
+	}
+	result := apimodels.MaintenanceWindow{
+		UID:                 w.UID,
+		Title:               w.Title,
+		Schedule:            w.Schedule,
+		Timezone:            w.Timezone,
+		Duration:            model.Duration(w.Duration),
+		Matchers:            matchers,
+		PauseRuleEvaluation: w.PauseRuleEvaluation,
+		CreatedBy:           w.CreatedBy,
+		UpdatedBy:           w.UpdatedBy,
+		Created:             w.Created,
+		Updated:             w.Updated,
+		SilenceID:           w.SilenceID,
+	}
+	if _, end, active, err := w.ActiveOccurrence(now); err == nil && active {
+		result.ActiveUntil = &end
+	}
+	if next, err := w.NextOccurrence(now); err == nil && !next.IsZero() {
+		result.NextOccurrence = &next
+	}
+	return result
+}
@@ -0,0 +1,589 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/alertmanager/config"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
+	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ruleFieldsToIgnoreInImportDiff contains the fields of a rule that are not part of the export formats or are calculated when the group is saved.
+var ruleFieldsToIgnoreInImportDiff = append(store.AlertRuleFieldsToIgnoreInDiff[:], "GUID", "RuleGroupIndex", "Metadata")
+
+type ruleGroupImport struct {
+	group alerting_models.AlertRuleGroup
+	diff  definitions.RuleGroupImportDiff
+}
+
+type contactPointImport struct {
+	create []definitions.EmbeddedContactPoint
+	update []definitions.EmbeddedContactPoint
+	delete []string
+	diff   definitions.ContactPointImportDiff
+}
+
+// RoutePostAlertingFileImport imports the rule groups and contact points of a file produced by any of the export endpoints.
+// Notification policies and mute timings are not imported, they are reported as skipped. Everything is validated before
+// anything is applied, and the changes are applied in a single transaction: the contact points first, so that the rules
+// can use them, then the rule groups. If any change fails, nothing is imported.
+func (srv *ProvisioningSrv) RoutePostAlertingFileImport(c *contextmodel.ReqContext, body []byte) response.Response {
+	file, fromHcl, err := decodeAlertingFileImport(c, body)
+	if err != nil {
+		if errors.Is(err, errorUnsupportedMediaType) {
+			return response.Err(err)
+		}
+		return ErrResp(http.StatusBadRequest, err, "failed to parse the file")
+	}
+
+	groups := make([]ruleGroupImport, 0, len(file.Groups))
+	for _, g := range file.Groups {
+		imp, err := srv.planRuleGroupImport(c, g, fromHcl)
+		if err != nil {
+			if errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+				return ErrResp(http.StatusBadRequest, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, fmt.Sprintf("failed to compare rule group %s", g.Name), err)
+		}
+		groups = append(groups, imp)
+	}
+
+	contactPoints := make([]contactPointImport, 0, len(file.ContactPoints))
+	for _, cp := range file.ContactPoints {
+		imp, err := srv.planContactPointImport(c, cp)
+		if err != nil {
+			if errors.Is(err, provisioning.ErrValidation) {
+				return ErrResp(http.StatusBadRequest, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, fmt.Sprintf("failed to compare contact point %s", cp.Name), err)
+		}
+		contactPoints = append(contactPoints, imp)
+	}
+
+	dryRun := c.QueryBoolWithDefault("dry_run", false)
+	if !dryRun {
+		err := srv.xactManager.InTransaction(c.Req.Context(), func(ctx context.Context) error {
+			for _, imp := range contactPoints {
+				if err := srv.applyContactPointImport(ctx, c, imp); err != nil {
+					return fmt.Errorf("failed to import contact point %s: %w", imp.diff.Name, err)
+				}
+			}
+			for _, imp := range groups {
+				if imp.diff.Action == definitions.ImportActionUnchanged {
+					continue
+				}
+				if err := srv.alertRules.ReplaceRuleGroup(ctx, c.SignedInUser, imp.group, determineManagerProperties(c), ""); err != nil {
+					return fmt.Errorf("failed to import rule group %s: %w", imp.group.Title, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+				return ErrResp(http.StatusBadRequest, err, "")
+			}
+			if errors.Is(err, store.ErrOptimisticLock) {
+				return ErrResp(http.StatusConflict, err, "")
+			}
+			if errors.Is(err, alerting_models.ErrQuotaReached) {
+				return ErrResp(http.StatusForbidden, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to import the file", err)
+		}
+	}
+
+	result := definitions.AlertingFileImportResult{
+		Applied:       !dryRun,
+		RuleGroups:    make([]definitions.RuleGroupImportDiff, 0, len(groups)),
+		ContactPoints: make([]definitions.ContactPointImportDiff, 0, len(contactPoints)),
+		Skipped:       skippedImportResources(file),
+	}
+	for _, imp := range groups {
+		result.RuleGroups = append(result.RuleGroups, imp.diff)
+	}
+	for _, imp := range contactPoints {
+		result.ContactPoints = append(result.ContactPoints, imp.diff)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// skippedImportResources describes the resources of the file that are not imported.
+func skippedImportResources(file definitions.AlertingFileExport) []string {
+	var result []string
+	for range file.Policies {
+		result = append(result, "notification policy tree")
+	}
+	for _, mt := range file.MuteTimings {
+		result = append(result, fmt.Sprintf("mute timing %s", mt.Name))
+	}
+	return result
+}
+
+// decodeAlertingFileImport decodes the file in the format given by the format query parameter or the content type of the request.
+// The second return value is true if the file is HCL.
+func decodeAlertingFileImport(c *contextmodel.ReqContext, body []byte) (definitions.AlertingFileExport, bool, error) {
+	format := c.Query("format")
+	if format == "" {
+		m := "application/yaml"
+		if contentType := c.Req.Header.Get("Content-Type"); contentType != "" {
+			var err error
+			if m, _, err = mime.ParseMediaType(contentType); err != nil {
+				return definitions.AlertingFileExport{}, false, err
+			}
+		}
+		switch m {
+		case "application/yaml", "text/yaml":
+			format = "yaml"
+		case "application/json":
+			format = "json"
+		case "application/terraform+hcl", "text/hcl":
+			format = "hcl"
+		default:
+			return definitions.AlertingFileExport{}, false, errorUnsupportedMediaType.Errorf("unsupported media type: %s, only YAML, JSON and HCL are supported", m)
+		}
+	}
+
+	var file definitions.AlertingFileExport
+	switch format {
+	case "hcl":
+		f, err := decodeHclImport(body)
+		return f, true, err
+	case "json":
+		if err := json.Unmarshal(body, &file); err != nil {
+			return definitions.AlertingFileExport{}, false, err
+		}
+	case "yaml":
+		if err := yaml.Unmarshal(body, &file); err != nil {
+			return definitions.AlertingFileExport{}, false, err
+		}
+	default:
+		return definitions.AlertingFileExport{}, false, fmt.Errorf("unsupported format %q, must be one of yaml, json or hcl", format)
+	}
+	return unescapeAlertingFileExport(file), false, nil
+}
+
+func decodeHclImport(body []byte) (definitions.AlertingFileExport, error) {
+	resources, err := hcl.Decode(body, "import.tf", func(resourceType string) (interface{}, error) {
+		switch resourceType {
+		case "grafana_rule_group":
+			return &definitions.AlertRuleGroupExport{}, nil
+		case "grafana_contact_point":
+			return &definitions.ContactPoint{}, nil
+		case "grafana_notification_policy":
+			return &definitions.RouteExport{}, nil
+		case "grafana_mute_timing":
+			return &definitions.MuteTimeIntervalExportHcl{}, nil
+		default:
+			return nil, fmt.Errorf("resources of type %s cannot be imported", resourceType)
+		}
+	})
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+
+	file := definitions.AlertingFileExport{APIVersion: 1}
+	for _, r := range resources {
+		switch body := r.Body.(type) {
+		case *definitions.AlertRuleGroupExport:
+			file.Groups = append(file.Groups, *body)
+		case *definitions.ContactPoint:
+			receiver, err := ContactPointToContactPointExport(*body)
+			if err != nil {
+				return definitions.AlertingFileExport{}, fmt.Errorf("failed to convert contact point %s: %w", body.Name, err)
+			}
+			cp := definitions.ContactPointExport{Name: receiver.Name}
+			for _, integration := range receiver.Integrations {
+				cp.Receivers = append(cp.Receivers, definitions.ReceiverExport{
+					Type:                  string(integration.Type),
+					Settings:              definitions.RawMessage(integration.Settings),
+					DisableResolveMessage: integration.DisableResolveMessage,
+				})
+			}
+			file.ContactPoints = append(file.ContactPoints, cp)
+		case *definitions.RouteExport:
+			file.Policies = append(file.Policies, definitions.NotificationPolicyExport{RouteExport: body})
+		case *definitions.MuteTimeIntervalExportHcl:
+			// Mute timings are not imported, only the name is kept to report them as skipped.
+			file.MuteTimings = append(file.MuteTimings, definitions.MuteTimeIntervalExport{MuteTimeInterval: config.MuteTimeInterval{Name: body.Name}})
+		}
+	}
+	return file, nil
+}
+
+// planRuleGroupImport converts the imported group to the model and compares it with the group in the database.
+// Imported rules without UID are matched with the existing rules by title because HCL does not contain rule UIDs.
+func (srv *ProvisioningSrv) planRuleGroupImport(c *contextmodel.ReqContext, export definitions.AlertRuleGroupExport, fromHcl bool) (ruleGroupImport, error) {
+	if export.FolderUID == "" {
+		folderUID, err := srv.resolveFolderFullpath(c, export.Folder)
+		if err != nil {
+			return ruleGroupImport{}, err
+		}
+		export.FolderUID = folderUID
+	}
+	export.OrgID = c.GetOrgID()
+	group, err := AlertRuleGroupFromAlertRuleGroupExport(export)
+	if err != nil {
+		return ruleGroupImport{}, fmt.Errorf("%w: rule group %s: %s", alerting_models.ErrAlertRuleFailedValidation, export.Name, err.Error())
+	}
+
+	existing, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.SignedInUser, group.FolderUID, group.Title)
+	if err != nil && !errors.Is(err, alerting_models.ErrAlertRuleGroupNotFound) {
+		return ruleGroupImport{}, err
+	}
+	return ruleGroupImport{
+		group: group,
+		diff:  diffRuleGroupImport(existing.Rules, &group, fromHcl),
+	}, nil
+}
+
+func (srv *ProvisioningSrv) resolveFolderFullpath(c *contextmodel.ReqContext, fullpath string) (string, error) {
+	titles := folderimpl.SplitFullpath(fullpath)
+	if len(titles) == 0 {
+		return "", fmt.Errorf("%w: rule group has no folder set", alerting_models.ErrAlertRuleFailedValidation)
+	}
+	parentUID := ""
+	for _, title := range titles {
+		f, err := srv.namespaces.GetNamespaceByTitle(c.Req.Context(), title, c.GetOrgID(), c.SignedInUser, parentUID)
+		if err != nil {
+			if errors.Is(err, dashboards.ErrFolderNotFound) {
+				return "", fmt.Errorf("%w: %s", dashboards.ErrFolderNotFound, fullpath)
+			}
+			return "", err
+		}
+		parentUID = f.UID
+	}
+	return parentUID, nil
+}
+
+// diffRuleGroupImport matches the imported rules with the existing ones and returns the changes. It updates the imported rules
+// with the identity of the matched rules so that the group replaces them instead of creating new ones.
+func diffRuleGroupImport(existing []alerting_models.AlertRule, group *alerting_models.AlertRuleGroup, fromHcl bool) definitions.RuleGroupImportDiff {
+	diff := definitions.RuleGroupImportDiff{
+		FolderUID: group.FolderUID,
+		Name:      group.Title,
+		Action:    definitions.ImportActionUnchanged,
+		Rules:     make([]definitions.RuleImportDiff, 0, len(group.Rules)),
+	}
+	if len(existing) == 0 {
+		diff.Action = definitions.ImportActionCreate
+	}
+
+	matched := make([]bool, len(existing))
+	findExisting := func(rule alerting_models.AlertRule) int {
+		for i := range existing {
+			if matched[i] {
+				continue
+			}
+			if (rule.UID != "" && existing[i].UID == rule.UID) || (rule.UID == "" && existing[i].Title == rule.Title) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		idx := findExisting(*rule)
+		if idx < 0 {
+			diff.Rules = append(diff.Rules, definitions.RuleImportDiff{UID: rule.UID, Title: rule.Title, Action: definitions.ImportActionCreate})
+			continue
+		}
+		matched[idx] = true
+		current := existing[idx]
+		rule.UID = current.UID
+		rule.Metadata = current.Metadata
+		if fromHcl {
+			// HCL does not contain the dashboard and panel of the rule.
+			rule.DashboardUID = current.DashboardUID
+			rule.PanelID = current.PanelID
+		}
+		for q := range rule.Data {
+			// Keep the stored model if it only differs in formatting so that it is not reported as changed.
+			if q < len(current.Data) && jsonEqual(current.Data[q].Model, rule.Data[q].Model) {
+				rule.Data[q].Model = current.Data[q].Model
+			}
+		}
+
+		ruleDiff := definitions.RuleImportDiff{UID: rule.UID, Title: rule.Title, Action: definitions.ImportActionUnchanged}
+		if d := current.Diff(rule, ruleFieldsToIgnoreInImportDiff...); len(d) > 0 {
+			ruleDiff.Action = definitions.ImportActionUpdate
+			ruleDiff.ChangedFields = d.Paths()
+		}
+		diff.Rules = append(diff.Rules, ruleDiff)
+	}
+
+	for i, m := range matched {
+		if !m {
+			diff.Rules = append(diff.Rules, definitions.RuleImportDiff{UID: existing[i].UID, Title: existing[i].Title, Action: definitions.ImportActionDelete})
+		}
+	}
+
+	if diff.Action == definitions.ImportActionUnchanged && slices.ContainsFunc(diff.Rules, func(r definitions.RuleImportDiff) bool {
+		return r.Action != definitions.ImportActionUnchanged
+	}) {
+		diff.Action = definitions.ImportActionUpdate
+	}
+	return diff
+}
+
+// planContactPointImport compares the integrations of the imported contact point with the existing integrations of the contact point
+// with the same name. Integrations are matched by UID if the file contains it, and by type otherwise.
+// It returns provisioning.ErrValidation if an integration is not valid.
+func (srv *ProvisioningSrv) planContactPointImport(c *contextmodel.ReqContext, cp definitions.ContactPointExport) (contactPointImport, error) {
+	existing, err := srv.contactPointService.GetContactPoints(c.Req.Context(), provisioning.ContactPointQuery{OrgID: c.GetOrgID(), Name: cp.Name}, c.SignedInUser)
+	if err != nil {
+		return contactPointImport{}, err
+	}
+
+	imp := contactPointImport{
+		diff: definitions.ContactPointImportDiff{
+			Name:         cp.Name,
+			Action:       definitions.ImportActionUnchanged,
+			Integrations: make([]definitions.IntegrationImportDiff, 0, len(cp.Receivers)),
+		},
+	}
+	if len(existing) == 0 {
+		imp.diff.Action = definitions.ImportActionCreate
+	}
+
+	matched := make([]bool, len(existing))
+	findExisting := func(r definitions.ReceiverExport) int {
+		for i := range existing {
+			if !matched[i] && r.UID != "" && existing[i].UID == r.UID {
+				return i
+			}
+		}
+		for i := range existing {
+			if !matched[i] && r.UID == "" && existing[i].Type == r.Type {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, r := range cp.Receivers {
+		settings, err := simplejson.NewJson(r.Settings)
+		if err != nil {
+			return contactPointImport{}, fmt.Errorf("invalid settings of %s integration: %w", r.Type, err)
+		}
+		integration := definitions.EmbeddedContactPoint{
+			UID:                   r.UID,
+			Name:                  cp.Name,
+			Type:                  r.Type,
+			Settings:              settings,
+			DisableResolveMessage: r.DisableResolveMessage,
+		}
+
+		idx := findExisting(r)
+		if err := validateImportedIntegration(c.Req.Context(), integration, idx >= 0); err != nil {
+			return contactPointImport{}, fmt.Errorf("%w: %s integration of contact point %s: %s", provisioning.ErrValidation, r.Type, cp.Name, err.Error())
+		}
+		if idx < 0 {
+			imp.create = append(imp.create, integration)
+			imp.diff.Integrations = append(imp.diff.Integrations, definitions.IntegrationImportDiff{UID: r.UID, Type: r.Type, Action: definitions.ImportActionCreate})
+			continue
+		}
+		matched[idx] = true
+		current := existing[idx]
+		integration.UID = current.UID
+
+		integrationDiff := definitions.IntegrationImportDiff{UID: current.UID, Type: r.Type, Action: definitions.ImportActionUnchanged}
+		changed, err := diffIntegrationSettings(current.Settings, settings)
+		if err != nil {
+			return contactPointImport{}, err
+		}
+		if current.DisableResolveMessage != r.DisableResolveMessage {
+			changed = append(changed, "disableResolveMessage")
+		}
+		if len(changed) > 0 {
+			integrationDiff.Action = definitions.ImportActionUpdate
+			integrationDiff.ChangedFields = changed
+			imp.update = append(imp.update, integration)
+		}
+		imp.diff.Integrations = append(imp.diff.Integrations, integrationDiff)
+	}
+
+	for i, m := range matched {
+		if !m {
+			imp.delete = append(imp.delete, existing[i].UID)
+			imp.diff.Integrations = append(imp.diff.Integrations, definitions.IntegrationImportDiff{UID: existing[i].UID, Type: existing[i].Type, Action: definitions.ImportActionDelete})
+		}
+	}
+
+	if imp.diff.Action == definitions.ImportActionUnchanged && len(imp.create)+len(imp.update)+len(imp.delete) > 0 {
+		imp.diff.Action = definitions.ImportActionUpdate
+	}
+	return imp, nil
+}
+
+// validateImportedIntegration validates the settings of an imported integration. The secure settings of an exported
+// integration are redacted, so a redacted value can only keep the stored value of an existing integration. The settings
+// of an existing integration with redacted values are validated after they are merged with the stored ones.
+func validateImportedIntegration(ctx context.Context, integration definitions.EmbeddedContactPoint, exists bool) error {
+	redacted := redactedSettings("", integration.Settings.Interface())
+	if len(redacted) > 0 {
+		if !exists {
+			return fmt.Errorf("settings %s are redacted, set the value of the secure settings of a new integration", strings.Join(redacted, ", "))
+		}
+		return nil
+	}
+	// The imported settings are in plain text, there is nothing to decrypt.
+	return provisioning.ValidateContactPoint(ctx, &integration, func(_ context.Context, _ map[string][]byte, _, fallback string) string {
+		return fallback
+	}, nil)
+}
+
+// redactedSettings returns the sorted paths of the settings that have the redacted value.
+func redactedSettings(path string, v any) []string {
+	var result []string
+	switch v := v.(type) {
+	case string:
+		if v == definitions.RedactedValue {
+			result = append(result, path)
+		}
+	case map[string]any:
+		for k, value := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			result = append(result, redactedSettings(p, value)...)
+		}
+	case []any:
+		for i, value := range v {
+			result = append(result, redactedSettings(fmt.Sprintf("%s[%d]", path, i), value)...)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// applyContactPointImport creates and updates the integrations before deleting the remaining ones,
+// so that a contact point used by notification policies never becomes empty.
+func (srv *ProvisioningSrv) applyContactPointImport(ctx context.Context, c *contextmodel.ReqContext, imp contactPointImport) error {
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	for _, integration := range imp.create {
+		if _, err := srv.contactPointService.CreateContactPoint(ctx, c.GetOrgID(), c.SignedInUser, integration, provenance); err != nil {
+			return err
+		}
+	}
+	for _, integration := range imp.update {
+		if err := srv.contactPointService.UpdateContactPoint(ctx, c.GetOrgID(), c.SignedInUser, integration, provenance); err != nil {
+			return err
+		}
+	}
+	for _, uid := range imp.delete {
+		if err := srv.contactPointService.DeleteContactPoint(ctx, c.GetOrgID(), c.SignedInUser, uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffIntegrationSettings returns the sorted names of the settings that differ. Imported settings that are redacted
+// are considered equal to the stored ones, while secure settings in plain text are always reported as changed.
+func diffIntegrationSettings(current, imported *simplejson.Json) ([]string, error) {
+	toMap := func(s *simplejson.Json) (map[string]any, error) {
+		result := map[string]any{}
+		if s == nil {
+			return result, nil
+		}
+		data, err := s.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return result, json.Unmarshal(data, &result)
+	}
+	a, err := toMap(current)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toMap(imported)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for k, v := range b {
+		if cur, ok := a[k]; !ok || (v != definitions.RedactedValue && !reflect.DeepEqual(cur, v)) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	slices.Sort(changed)
+	return changed, nil
+}
+
+func jsonEqual(a, b []byte) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// unescapeAlertingFileExport reverts escapeAlertingFileExport for the resources that can be imported.
+func unescapeAlertingFileExport(file definitions.AlertingFileExport) definitions.AlertingFileExport {
+	for i := range file.Groups {
+		g := &file.Groups[i]
+		g.Name = removeEscapeCharactersFromString(g.Name)
+		g.Folder = removeEscapeCharactersFromString(g.Folder)
+		for j := range g.Rules {
+			r := &g.Rules[j]
+			r.Title = removeEscapeCharactersFromString(r.Title)
+			if r.Labels != nil {
+				labels := make(map[string]string, len(*r.Labels))
+				for k, v := range *r.Labels {
+					labels[k] = removeEscapeCharactersFromString(v)
+				}
+				r.Labels = &labels
+			}
+			if ns := r.NotificationSettings; ns != nil {
+				ns.Receiver = removeEscapeCharactersFromString(ns.Receiver)
+				for _, values := range []*[]string{ns.GroupBy, ns.MuteTimeIntervals, ns.ActiveTimeIntervals} {
+					if values == nil {
+						continue
+					}
+					for k := range *values {
+						(*values)[k] = removeEscapeCharactersFromString((*values)[k])
+					}
+				}
+			}
+		}
+	}
+	for i := range file.ContactPoints {
+		cp := &file.ContactPoints[i]
+		cp.Name = removeEscapeCharactersFromString(cp.Name)
+		for j := range cp.Receivers {
+			cp.Receivers[j].Settings = definitions.RawMessage(removeEscapeCharactersFromString(string(cp.Receivers[j].Settings)))
+		}
+	}
+	return file
+}
+
+func removeEscapeCharactersFromString(s string) string {
+	return strings.ReplaceAll(s, "$$", "$")
+}
@@ -1968,6 +1968,262 @@ func TestIntegrationProvisioningApiContactPointExport(t *testing.T) {
 	})
 }
 
+func TestIntegrationProvisioningApiImport(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	createSut := func(t *testing.T) ProvisioningSrv {
+		t.Helper()
+		env := createTestEnv(t, testConfig)
+		env.ac = &recordingAccessControlFake{
+			Callback: func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+				return true, nil
+			},
+		}
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		insertRule(t, sut, createTestAlertRule("rule", 1))
+		return sut
+	}
+
+	export := func(t *testing.T, sut ProvisioningSrv, format string) []byte {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Req.Form.Set("format", format)
+		response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+		require.Equal(t, 200, response.Status())
+		return response.Body()
+	}
+
+	importFile := func(t *testing.T, sut ProvisioningSrv, contentType string, body []byte, dryRun bool) definitions.AlertingFileImportResult {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set("Content-Type", contentType)
+		if dryRun {
+			rc.Req.Form.Set("dry_run", "true")
+		}
+		response := sut.RoutePostAlertingFileImport(&rc, body)
+		require.Equal(t, 200, response.Status(), string(response.Body()))
+		var result definitions.AlertingFileImportResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		return result
+	}
+
+	getGroup := func(t *testing.T, sut ProvisioningSrv) definitions.AlertRuleGroup {
+		t.Helper()
+		rc := createTestRequestCtx()
+		response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+		require.Equal(t, 200, response.Status())
+		return deserializeRuleGroup(t, response.Body())
+	}
+
+	t.Run("unchanged export is not changed by import", func(t *testing.T) {
+		testCases := []struct {
+			format      string
+			contentType string
+		}{
+			{format: "yaml", contentType: "application/yaml"},
+			{format: "json", contentType: "application/json"},
+			{format: "hcl", contentType: "text/hcl"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.format, func(t *testing.T) {
+				sut := createSut(t)
+
+				result := importFile(t, sut, tc.contentType, export(t, sut, tc.format), false)
+
+				require.True(t, result.Applied)
+				require.Len(t, result.RuleGroups, 1)
+				require.Equal(t, definitions.ImportActionUnchanged, result.RuleGroups[0].Action)
+				require.Equal(t, []definitions.RuleImportDiff{{UID: "rule", Title: "rule", Action: definitions.ImportActionUnchanged}}, result.RuleGroups[0].Rules)
+			})
+		}
+	})
+
+	t.Run("dry run returns diff without changing the group", func(t *testing.T) {
+		sut := createSut(t)
+		var file definitions.AlertingFileExport
+		require.NoError(t, json.Unmarshal(export(t, sut, "json"), &file))
+		file.Groups[0].Rules[0].Title = "renamed"
+		body, err := json.Marshal(file)
+		require.NoError(t, err)
+
+		result := importFile(t, sut, "application/json", body, true)
+
+		require.False(t, result.Applied)
+		require.Len(t, result.RuleGroups, 1)
+		require.Equal(t, definitions.ImportActionUpdate, result.RuleGroups[0].Action)
+		require.Equal(t, []definitions.RuleImportDiff{{UID: "rule", Title: "renamed", Action: definitions.ImportActionUpdate, ChangedFields: []string{"Title"}}}, result.RuleGroups[0].Rules)
+		require.Equal(t, "rule", getGroup(t, sut).Rules[0].Title)
+
+		t.Run("and applies it otherwise", func(t *testing.T) {
+			result := importFile(t, sut, "application/json", body, false)
+
+			require.True(t, result.Applied)
+			group := getGroup(t, sut)
+			require.Len(t, group.Rules, 1)
+			require.Equal(t, "rule", group.Rules[0].UID)
+			require.Equal(t, "renamed", group.Rules[0].Title)
+		})
+	})
+
+	t.Run("HCL rules are matched by title", func(t *testing.T) {
+		sut := createSut(t)
+		body := strings.Replace(string(export(t, sut, "hcl")), `name      = "rule"`, `name      = "another rule"`, 1)
+		require.Contains(t, body, "another rule")
+
+		result := importFile(t, sut, "text/hcl", []byte(body), false)
+
+		require.Equal(t, []definitions.RuleImportDiff{
+			{Title: "another rule", Action: definitions.ImportActionCreate},
+			{UID: "rule", Title: "rule", Action: definitions.ImportActionDelete},
+		}, result.RuleGroups[0].Rules)
+		group := getGroup(t, sut)
+		require.Len(t, group.Rules, 1)
+		require.Equal(t, "another rule", group.Rules[0].Title)
+		require.NotEqual(t, "rule", group.Rules[0].UID)
+	})
+
+	t.Run("rule group in unknown folder returns 400", func(t *testing.T) {
+		sut := createSut(t)
+		body := strings.Replace(string(export(t, sut, "yaml")), "folder: Folder Title", "folder: Unknown Folder", 1)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingFileImport(&rc, []byte(body))
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("notification policies and mute timings are skipped", func(t *testing.T) {
+		sut := createSut(t)
+		body := []byte(`apiVersion: 1
+policies:
+  - orgId: 1
+    receiver: grafana-default-email
+muteTimes:
+  - orgId: 1
+    name: weekends
+    time_intervals:
+      - weekdays: [saturday, sunday]
+`)
+
+		result := importFile(t, sut, "application/yaml", body, false)
+
+		require.True(t, result.Applied)
+		require.Equal(t, []string{"notification policy tree", "mute timing weekends"}, result.Skipped)
+	})
+
+	t.Run("rule group that cannot be saved returns 400", func(t *testing.T) {
+		sut := createSut(t)
+		body := string(export(t, sut, "yaml"))
+		require.Contains(t, body, "interval: 1m")
+		body = strings.Replace(body, "interval: 1m", "interval: 15s", 1)
+		body = strings.Replace(body, "title: rule", "title: renamed", 1)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingFileImport(&rc, []byte(body))
+
+		require.Equal(t, 400, response.Status())
+		require.Contains(t, string(response.Body()), "failed to import rule group my-cool-group")
+		require.Equal(t, "rule", getGroup(t, sut).Rules[0].Title)
+	})
+
+	t.Run("contact point integrations are updated", func(t *testing.T) {
+		sut := createSut(t)
+		body := []byte(`apiVersion: 1
+contactPoints:
+  - orgId: 1
+    name: grafana-default-email
+    receivers:
+      - uid: email-uid
+        type: email
+        settings:
+          addresses: <other@example.com>
+        disableResolveMessage: false
+      - type: webhook
+        settings:
+          url: http://localhost
+`)
+
+		result := importFile(t, sut, "application/yaml", body, false)
+
+		require.Equal(t, []definitions.ContactPointImportDiff{{
+			Name:   "grafana-default-email",
+			Action: definitions.ImportActionUpdate,
+			Integrations: []definitions.IntegrationImportDiff{
+				{UID: "email-uid", Type: "email", Action: definitions.ImportActionUpdate, ChangedFields: []string{"addresses"}},
+				{Type: "webhook", Action: definitions.ImportActionCreate},
+			},
+		}}, result.ContactPoints)
+
+		rc := createTestRequestCtx()
+		rc.Req.Form.Set("name", "grafana-default-email")
+		response := sut.RouteGetContactPoints(&rc)
+		require.Equal(t, 200, response.Status())
+		var cps []definitions.EmbeddedContactPoint
+		require.NoError(t, json.Unmarshal(response.Body(), &cps))
+		require.Len(t, cps, 2)
+		for _, cp := range cps {
+			if cp.Type == "email" {
+				require.Equal(t, "<other@example.com>", cp.Settings.Get("addresses").MustString())
+			}
+		}
+	})
+
+	getContactPoints := func(t *testing.T, sut ProvisioningSrv, name string) []definitions.EmbeddedContactPoint {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Req.Form.Set("name", name)
+		response := sut.RouteGetContactPoints(&rc)
+		require.Equal(t, 200, response.Status())
+		var cps []definitions.EmbeddedContactPoint
+		require.NoError(t, json.Unmarshal(response.Body(), &cps))
+		return cps
+	}
+
+	t.Run("new integration with redacted secure settings returns 400", func(t *testing.T) {
+		sut := createSut(t)
+		body := []byte(`apiVersion: 1
+contactPoints:
+  - orgId: 1
+    name: slack
+    receivers:
+      - type: slack
+        settings:
+          url: "[REDACTED]"
+`)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingFileImport(&rc, body)
+
+		require.Equal(t, 400, response.Status())
+		require.Contains(t, string(response.Body()), "settings url are redacted")
+		require.Empty(t, getContactPoints(t, sut, "slack"))
+	})
+
+	t.Run("invalid contact point prevents the import of all contact points", func(t *testing.T) {
+		sut := createSut(t)
+		body := []byte(`apiVersion: 1
+contactPoints:
+  - orgId: 1
+    name: valid
+    receivers:
+      - type: webhook
+        settings:
+          url: http://localhost
+  - orgId: 1
+    name: invalid
+    receivers:
+      - type: webhook
+        settings: {}
+`)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingFileImport(&rc, body)
+
+		require.Equal(t, 400, response.Status())
+		require.Empty(t, getContactPoints(t, sut, "valid"))
+	})
+}
+
 func TestApiContactPointExportSnapshot(t *testing.T) {
 	// This test should fail whenever the export of a contact point changes. If the change is expected, update
 	// the corresponding test response file(s) in test-data/receiver-exports/*
@@ -2473,6 +2729,8 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		muteTimings:         provisioning.NewMuteTimingService(configStore, env.prov, env.xact, env.log, env.store, rs, validation.ValidateProvenanceRelaxed),
 		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.folderService, env.quotas, env.xact, 60, 10, 100, env.log, env.nsValidator, env.rulesAuthz, provisioning.NoopRuleMutationValidator{}),
 		folderSvc:           env.folderService,
+		namespaces:          env.store,
+		xactManager:         env.xact,
 		featureManager:      env.features,
 	}
 }
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// RuleShards tells which instance of the cluster evaluates an alert rule when the evaluation is sharded.
+type RuleShards interface {
+	Name() string
+	Members() []string
+	Owner(key ngmodels.AlertRuleKey) string
+}
+
+// RouteGetRuleOwnership returns the instance of the cluster that evaluates each alert rule of the organization.
+func (srv ConfigSrv) RouteGetRuleOwnership(c *contextmodel.ReqContext) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+	if srv.ruleShards == nil {
+		return response.JSON(http.StatusOK, apimodels.RuleOwnership{Members: []string{}, Rules: []apimodels.RuleOwner{}})
+	}
+
+	rules, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{OrgID: c.GetOrgID()})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	result := apimodels.RuleOwnership{
+		Enabled:  true,
+		Instance: srv.ruleShards.Name(),
+		Members:  srv.ruleShards.Members(),
+		Rules:    make([]apimodels.RuleOwner, 0, len(rules)),
+	}
+	if result.Members == nil {
+		result.Members = []string{}
+	}
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, apimodels.RuleOwner{
+			UID:          rule.UID,
+			Title:        rule.Title,
+			NamespaceUID: rule.NamespaceUID,
+			RuleGroup:    rule.RuleGroup,
+			Owner:        srv.ruleShards.Owner(rule.GetKey()),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+type fakeRuleShards struct {
+	owners map[string]string
+}
+
+func (f fakeRuleShards) Name() string {
+	return "instance-a"
+}
+
+func (f fakeRuleShards) Members() []string {
+	return []string{"instance-a", "instance-b"}
+}
+
+func (f fakeRuleShards) Owner(key models.AlertRuleKey) string {
+	return f.owners[key.UID]
+}
+
+func TestRouteGetRuleOwnership(t *testing.T) {
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1))
+	rule1 := gen.GenerateRef()
+	rule2 := gen.GenerateRef()
+	otherOrg := gen.With(gen.WithOrgID(2)).GenerateRef()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), rule1, rule2, otherOrg)
+
+	t.Run("returns the instance that evaluates each rule of the organization", func(t *testing.T) {
+		sut := ConfigSrv{
+			ruleStore:  ruleStore,
+			ruleShards: fakeRuleShards{owners: map[string]string{rule1.UID: "instance-a", rule2.UID: "instance-b"}},
+			log:        log.NewNopLogger(),
+		}
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		resp := sut.RouteGetRuleOwnership(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var result definitions.RuleOwnership
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.True(t, result.Enabled)
+		require.Equal(t, "instance-a", result.Instance)
+		require.Equal(t, []string{"instance-a", "instance-b"}, result.Members)
+		require.ElementsMatch(t, []definitions.RuleOwner{
+			{UID: rule1.UID, Title: rule1.Title, NamespaceUID: rule1.NamespaceUID, RuleGroup: rule1.RuleGroup, Owner: "instance-a"},
+			{UID: rule2.UID, Title: rule2.Title, NamespaceUID: rule2.NamespaceUID, RuleGroup: rule2.RuleGroup, Owner: "instance-b"},
+		}, result.Rules)
+	})
+
+	t.Run("returns no owners if evaluation is not sharded", func(t *testing.T) {
+		sut := ConfigSrv{ruleStore: ruleStore, log: log.NewNopLogger()}
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		resp := sut.RouteGetRuleOwnership(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var result definitions.RuleOwnership
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.False(t, result.Enabled)
+		require.Empty(t, result.Rules)
+	})
+
+	t.Run("requires the organization admin role", func(t *testing.T) {
+		sut := ConfigSrv{ruleStore: ruleStore, ruleShards: fakeRuleShards{}, log: log.NewNopLogger()}
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleEditor
+
+		require.Equal(t, http.StatusForbidden, sut.RouteGetRuleOwnership(ctx).Status())
+	})
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func (srv ConfigSrv) RouteGetRulePolicy(c *contextmodel.ReqContext) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	policy, err := srv.rulePolicyStore.GetRulePolicy(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		if errors.Is(err, store.ErrNoRulePolicy) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		msg := "failed to fetch the rule policy from the database"
+		srv.log.Error(msg, "error", err)
+		return ErrResp(http.StatusInternalServerError, err, msg)
+	}
+	return response.JSON(http.StatusOK, rulePolicyToAPI(*policy))
+}
+
+func (srv ConfigSrv) RoutePostRulePolicy(c *contextmodel.ReqContext, body apimodels.RulePolicy) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	policy := rulePolicyFromAPI(body)
+	if err := policy.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err := srv.rulePolicyStore.SaveRulePolicy(c.Req.Context(), c.GetOrgID(), policy); err != nil {
+		msg := "failed to save the rule policy to the database"
+		srv.log.Error(msg, "error", err)
+		return ErrResp(http.StatusInternalServerError, err, msg)
+	}
+	return response.JSON(http.StatusCreated, util.DynMap{"message": "rule policy updated"})
+}
+
+func (srv ConfigSrv) RouteDeleteRulePolicy(c *contextmodel.ReqContext) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	if err := srv.rulePolicyStore.DeleteRulePolicy(c.Req.Context(), c.GetOrgID()); err != nil {
+		srv.log.Error("Unable to delete rule policy", "error", err)
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "rule policy deleted"})
+}
+
+// RouteTestRulePolicy checks all alert rules of the organization against the policy in the request
+// without saving it, so that the impact of a policy can be seen before it is enforced.
+func (srv ConfigSrv) RouteTestRulePolicy(c *contextmodel.ReqContext, body apimodels.RulePolicy) response.Response {
+	if c.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	policy := rulePolicyFromAPI(body)
+	if err := policy.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	rules, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{OrgID: c.GetOrgID()})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	result := apimodels.RulePolicyTestResult{Rules: []apimodels.RulePolicyRuleViolations{}}
+	for _, v := range srv.rulePolicy.Check(c.Req.Context(), c.GetOrgID(), &policy, rules) {
+		result.Rules = append(result.Rules, apimodels.RulePolicyRuleViolations{
+			UID:          v.Rule.UID,
+			Title:        v.Rule.Title,
+			NamespaceUID: v.Rule.NamespaceUID,
+			RuleGroup:    v.Rule.RuleGroup,
+			Violations:   v.Violations,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func rulePolicyFromAPI(policy apimodels.RulePolicy) ngmodels.RulePolicy {
+	return ngmodels.RulePolicy{
+		Mode:                  ngmodels.RulePolicyMode(policy.Mode),
+		RequiredLabels:        policy.RequiredLabels,
+		RequiredAnnotations:   policy.RequiredAnnotations,
+		LabelValuePatterns:    policy.LabelValuePatterns,
+		MinInterval:           time.Duration(policy.MinInterval),
+		MaxInterval:           time.Duration(policy.MaxInterval),
+		MaxFor:                time.Duration(policy.MaxFor),
+		BannedDatasourceTypes: policy.BannedDatasourceTypes,
+	}
+}
+
+func rulePolicyToAPI(policy ngmodels.RulePolicy) apimodels.RulePolicy {
+	return apimodels.RulePolicy{
+		Mode:                  apimodels.RulePolicyMode(policy.Mode),
+		RequiredLabels:        policy.RequiredLabels,
+		RequiredAnnotations:   policy.RequiredAnnotations,
+		LabelValuePatterns:    policy.LabelValuePatterns,
+		MinInterval:           model.Duration(policy.MinInterval),
+		MaxInterval:           model.Duration(policy.MaxInterval),
+		MaxFor:                model.Duration(policy.MaxFor),
+		BannedDatasourceTypes: policy.BannedDatasourceTypes,
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/rulepolicy"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func createRulePolicySut(t *testing.T, ruleStore *fakes.RuleStore) (ConfigSrv, *store.FakeRulePolicyStore) {
+	policyStore := store.NewFakeRulePolicyStore(t)
+	return ConfigSrv{
+		rulePolicyStore: policyStore,
+		rulePolicy:      rulepolicy.NewEnforcer(policyStore, &fakeDatasources.FakeDataSourceService{}, log.NewNopLogger()),
+		ruleStore:       ruleStore,
+		log:             log.NewNopLogger(),
+	}, policyStore
+}
+
+func TestRulePolicyRoutes(t *testing.T) {
+	policy := definitions.RulePolicy{
+		Mode:                definitions.RulePolicyModeReject,
+		RequiredLabels:      []string{"team"},
+		RequiredAnnotations: []string{"runbook_url"},
+		LabelValuePatterns:  map[string]string{"team": "[a-z]+"},
+		MaxFor:              model.Duration(time.Hour),
+	}
+
+	t.Run("saves, returns and deletes the policy of the organization", func(t *testing.T) {
+		sut, policyStore := createRulePolicySut(t, fakes.NewRuleStore(t))
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		require.Equal(t, http.StatusNotFound, sut.RouteGetRulePolicy(ctx).Status())
+
+		require.Equal(t, http.StatusCreated, sut.RoutePostRulePolicy(ctx, policy).Status())
+		require.Equal(t, models.RulePolicyModeReject, policyStore.Policies[1].Mode)
+		require.Equal(t, time.Hour, policyStore.Policies[1].MaxFor)
+
+		resp := sut.RouteGetRulePolicy(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var got definitions.RulePolicy
+		require.NoError(t, json.Unmarshal(resp.Body(), &got))
+		require.Equal(t, policy, got)
+
+		require.Equal(t, http.StatusOK, sut.RouteDeleteRulePolicy(ctx).Status())
+		require.Empty(t, policyStore.Policies)
+	})
+
+	t.Run("rejects an invalid policy", func(t *testing.T) {
+		sut, policyStore := createRulePolicySut(t, fakes.NewRuleStore(t))
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		invalid := policy
+		invalid.LabelValuePatterns = map[string]string{"team": "("}
+		require.Equal(t, http.StatusBadRequest, sut.RoutePostRulePolicy(ctx, invalid).Status())
+		require.Equal(t, http.StatusBadRequest, sut.RouteTestRulePolicy(ctx, definitions.RulePolicy{Mode: "audit"}).Status())
+		require.Empty(t, policyStore.Policies)
+	})
+
+	t.Run("requires the organization admin role", func(t *testing.T) {
+		sut, _ := createRulePolicySut(t, fakes.NewRuleStore(t))
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleEditor
+
+		require.Equal(t, http.StatusForbidden, sut.RouteGetRulePolicy(ctx).Status())
+		require.Equal(t, http.StatusForbidden, sut.RoutePostRulePolicy(ctx, policy).Status())
+		require.Equal(t, http.StatusForbidden, sut.RouteDeleteRulePolicy(ctx).Status())
+		require.Equal(t, http.StatusForbidden, sut.RouteTestRulePolicy(ctx, policy).Status())
+	})
+
+	t.Run("reports the rules that violate the policy without saving it", func(t *testing.T) {
+		gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithFor(time.Minute))
+		compliant := gen.With(gen.WithLabels(map[string]string{"team": "platform"}), gen.WithAnnotations(map[string]string{"runbook_url": "https://example.com"})).GenerateRef()
+		violating := gen.With(gen.WithLabels(map[string]string{"team": "Platform"}), gen.WithAnnotations(map[string]string{"runbook_url": "https://example.com"})).GenerateRef()
+		otherOrg := gen.With(gen.WithOrgID(2), gen.WithLabels(nil)).GenerateRef()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), compliant, violating, otherOrg)
+
+		sut, policyStore := createRulePolicySut(t, ruleStore)
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+
+		resp := sut.RouteTestRulePolicy(ctx, policy)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var result definitions.RulePolicyTestResult
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, []definitions.RulePolicyRuleViolations{{
+			UID:          violating.UID,
+			Title:        violating.Title,
+			NamespaceUID: violating.NamespaceUID,
+			RuleGroup:    violating.RuleGroup,
+			Violations:   []string{`value "Platform" of label team does not match pattern "[a-z]+"`},
+		}}, result.Rules)
+		require.Empty(t, policyStore.Policies)
+	})
+}
+
+func TestEnforceRulePolicy(t *testing.T) {
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithLabels(nil))
+	policyStore := store.NewFakeRulePolicyStore(t)
+	srv := createService(fakes.NewRuleStore(t), nil)
+	srv.rulePolicy = rulepolicy.NewEnforcer(policyStore, &fakeDatasources.FakeDataSourceService{}, log.NewNopLogger())
+
+	delta := func() *store.GroupDelta {
+		return &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: 1},
+			New:      []*models.AlertRule{gen.With(gen.WithTitle("new")).GenerateRef()},
+		}
+	}
+
+	t.Run("accepts rules if the organization has no policy", func(t *testing.T) {
+		warnings, err := srv.enforceRulePolicy(context.Background(), delta())
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+	})
+
+	t.Run("rejects rules that violate a policy in reject mode", func(t *testing.T) {
+		policyStore.Policies[1] = models.RulePolicy{Mode: models.RulePolicyModeReject, RequiredLabels: []string{"team"}}
+		_, err := srv.enforceRulePolicy(context.Background(), delta())
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "rule 'new' violates the rule policy: missing required label team")
+	})
+
+	t.Run("returns warnings for rules that violate a policy in warn mode", func(t *testing.T) {
+		policyStore.Policies[1] = models.RulePolicy{Mode: models.RulePolicyModeWarn, RequiredLabels: []string{"team"}}
+		warnings, err := srv.enforceRulePolicy(context.Background(), delta())
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule 'new' violates the rule policy: missing required label team"}, warnings)
+	})
+
+	t.Run("does not check updates that only move rules", func(t *testing.T) {
+		policyStore.Policies[1] = models.RulePolicy{Mode: models.RulePolicyModeReject, RequiredLabels: []string{"team"}}
+		existing := gen.GenerateRef()
+		moved := models.CopyRule(existing)
+		moved.RuleGroupIndex++
+		d := &store.GroupDelta{GroupKey: models.AlertRuleGroupKey{OrgID: 1}, Update: []store.RuleDelta{{
+			Existing: existing,
+			New:      moved,
+			Diff:     existing.Diff(moved),
+		}}}
+		warnings, err := srv.enforceRulePolicy(context.Background(), d)
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+	})
+}
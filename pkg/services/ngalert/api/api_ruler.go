@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
@@ -21,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
@@ -30,6 +32,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/rulepolicy"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -64,6 +67,13 @@ type RulerSrv struct {
 	amConfigStore  AMConfigStore
 	amRefresher    AMRefresher
 	featureManager featuremgmt.FeatureToggles
+	rulePolicy     *rulepolicy.Enforcer
+
+	datasourceCache datasources.CacheService
+
+	alertInstances state.AlertInstanceManager
+	acknowledger   AlertAcknowledger
+	backfiller     RecordingRuleBackfiller
 }
 
 var (
@@ -158,7 +168,10 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 			rulesToDelete = append(rulesToDelete, uid...)
 		}
 		if len(rulesToDelete) > 0 {
-			err := srv.store.DeleteAlertRulesByUID(ctx, c.GetOrgID(), ngmodels.NewUserUID(c.SignedInUser), permanently, rulesToDelete...)
+			if err := validateRuleDependencyChanges(ctx, srv.store, c.GetOrgID(), nil, rulesToDelete); err != nil {
+				return err
+			}
+			err = srv.store.DeleteAlertRulesByUID(ctx, c.GetOrgID(), ngmodels.NewUserUID(c.SignedInUser), permanently, rulesToDelete...)
 			if err != nil {
 				return err
 			}
@@ -178,7 +191,7 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 		if errors.As(err, &errutil.Error{}) {
 			return response.Err(err)
 		}
-		if errors.Is(err, errProvisionedResource) {
+		if errors.Is(err, errProvisionedResource) || errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
 			return ErrResp(http.StatusBadRequest, err, "failed to delete rule group")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
@@ -394,11 +407,68 @@ func (srv RulerSrv) RouteGetRuleVersionsByUID(c *contextmodel.ReqContext, ruleUI
 		// do not provide provenance status because we do not have historical changes for it
 		ruleNode := toGettableExtendedRuleNode(rule.AlertRule, map[string]ngmodels.Provenance{}, userUIDmapping)
 		ruleNode.GrafanaManagedAlert.Message = rule.Message
+		ruleNode.GrafanaManagedAlert.RestoredFrom = rule.RestoredFrom
 		result = append(result, ruleNode)
 	}
 	return response.JSON(http.StatusOK, result)
 }
 
+// RouteGetRuleDependencies returns the alert rules the user can read that depend on the state of
+// other alert rules or that other alert rules depend on, with the dependencies between them.
+func (srv RulerSrv) RouteGetRuleDependencies(c *contextmodel.ReqContext) response.Response {
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	result := apimodels.RuleDependencyGraph{Rules: []apimodels.RuleDependencyNode{}}
+	if len(namespaceMap) == 0 {
+		return response.JSON(http.StatusOK, result)
+	}
+
+	configs, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), authorizedRuleGroupQuery{
+		User:          c.SignedInUser,
+		NamespaceUIDs: slices.Collect(maps.Keys(namespaceMap)),
+	})
+	if err != nil {
+		return errorToResponse(err)
+	}
+	rules := make(map[string]*ngmodels.AlertRule)
+	for _, group := range configs {
+		for _, rule := range group {
+			rules[rule.UID] = rule
+		}
+	}
+
+	graph := ngmodels.NewRuleDependencyGraph(slices.Collect(maps.Values(rules)))
+	dependents := graph.Dependents()
+	// Only the dependencies between rules the user can read are returned.
+	visible := func(uids []string) []string {
+		result := make([]string, 0, len(uids))
+		for _, uid := range uids {
+			if _, ok := rules[uid]; ok {
+				result = append(result, uid)
+			}
+		}
+		return result
+	}
+	for _, uid := range slices.Sorted(maps.Keys(rules)) {
+		dependsOn, dependedOnBy := visible(graph[uid]), visible(dependents[uid])
+		if len(dependsOn) == 0 && len(dependedOnBy) == 0 {
+			continue
+		}
+		rule := rules[uid]
+		result.Rules = append(result.Rules, apimodels.RuleDependencyNode{
+			UID:          rule.UID,
+			Title:        rule.Title,
+			NamespaceUID: rule.NamespaceUID,
+			RuleGroup:    rule.RuleGroup,
+			DependsOn:    dependsOn,
+			Dependents:   dependedOnBy,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 func alertRuleVersionsToAlertRules(vs []*ngmodels.AlertRuleVersion) []*ngmodels.AlertRule {
 	result := make([]*ngmodels.AlertRule, len(vs))
 	for i := range vs {
@@ -441,7 +511,7 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 		RuleGroup:    ruleGroupConfig.Name,
 	}
 
-	return srv.updateAlertRulesInGroup(c, groupKey, rules, deletePermanently)
+	return srv.updateAlertRulesInGroup(c, groupKey, rules, deletePermanently, nil)
 }
 
 func (srv RulerSrv) RouteDeleteAlertRuleFromTrashByGUID(ctx *contextmodel.ReqContext, guid string) response.Response {
@@ -471,8 +541,8 @@ func (srv RulerSrv) checkGroupLimits(group apimodels.PostableRuleGroupConfig) er
 // All operations are performed in a single transaction
 //
 //nolint:gocyclo
-func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, deletePermanently bool) response.Response {
-	finalChanges, amConfig, err := srv.performUpdateAlertRules(c.Req.Context(), c, groupKey, rules, deletePermanently)
+func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, deletePermanently bool, restore *ruleRestore) response.Response {
+	finalChanges, amConfig, warnings, err := srv.performUpdateAlertRules(c.Req.Context(), c, groupKey, rules, deletePermanently, restore)
 
 	if err != nil {
 		if errors.As(err, &errutil.Error{}) {
@@ -497,12 +567,16 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		}
 	}
 
-	return changesToResponse(finalChanges)
+	return changesToResponse(finalChanges, warnings)
 }
 
-func (srv RulerSrv) performUpdateAlertRules(ctx context.Context, c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, deletePermanently bool) (*store.GroupDelta, *ngmodels.AlertConfiguration, error) {
+// performUpdateAlertRules applies the changes to the rule group in a transaction. It returns the applied changes,
+// the Alertmanager configuration to refresh if any, and the violations of the rule policy that did not reject the changes.
+// If restore is not nil, the version created by the update of the restored rule records the restored version.
+func (srv RulerSrv) performUpdateAlertRules(ctx context.Context, c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, deletePermanently bool, restore *ruleRestore) (*store.GroupDelta, *ngmodels.AlertConfiguration, []string, error) {
 	var finalChanges *store.GroupDelta
 	var dbConfig *ngmodels.AlertConfiguration
+	var warnings []string
 	err := srv.xactManager.InTransaction(ctx, func(tranCtx context.Context) error {
 		id, _ := c.GetInternalID()
 		userNamespace := c.GetIdentityType()
@@ -529,6 +603,15 @@ func (srv RulerSrv) performUpdateAlertRules(ctx context.Context, c *contextmodel
 			return err
 		}
 
+		if err := validateRuleDependencies(tranCtx, srv.store, groupChanges); err != nil {
+			return err
+		}
+
+		warnings, err = srv.enforceRulePolicy(tranCtx, groupChanges)
+		if err != nil {
+			return err
+		}
+
 		newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
 		if len(newOrUpdatedNotificationSettings) > 0 {
 			amConfig, err := srv.amConfigStore.GetLatestAlertmanagerConfiguration(tranCtx, groupChanges.GroupKey.OrgID)
@@ -581,10 +664,15 @@ func (srv RulerSrv) performUpdateAlertRules(ctx context.Context, c *contextmodel
 				if ngmodels.IsNoGroupRuleGroup(update.Existing.RuleGroup) && !ngmodels.IsNoGroupRuleGroup(update.New.RuleGroup) {
 					return fmt.Errorf("%w: cannot move rule out of this group", ngmodels.ErrAlertRuleFailedValidation)
 				}
-				updates = append(updates, ngmodels.UpdateRule{
+				upd := ngmodels.UpdateRule{
 					Existing: update.Existing,
 					New:      *update.New,
-				})
+				}
+				if restore != nil && update.New.UID == restore.UID {
+					upd.Message = fmt.Sprintf("Restored from version %d", restore.Version)
+					upd.RestoredFrom = restore.Version
+				}
+				updates = append(updates, upd)
 			}
 			err = srv.store.UpdateAlertRules(tranCtx, ngmodels.NewUserUID(c.SignedInUser), updates)
 			if err != nil {
@@ -628,18 +716,19 @@ func (srv RulerSrv) performUpdateAlertRules(ctx context.Context, c *contextmodel
 	})
 
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return finalChanges, dbConfig, nil
+	return finalChanges, dbConfig, warnings, nil
 }
 
-func changesToResponse(finalChanges *store.GroupDelta) response.Response {
+func changesToResponse(finalChanges *store.GroupDelta, warnings []string) response.Response {
 	body := apimodels.UpdateRuleGroupResponse{
-		Message: "rule group updated successfully",
-		Created: make([]string, 0, len(finalChanges.New)),
-		Updated: make([]string, 0, len(finalChanges.Update)),
-		Deleted: make([]string, 0, len(finalChanges.Delete)),
+		Message:  "rule group updated successfully",
+		Created:  make([]string, 0, len(finalChanges.New)),
+		Updated:  make([]string, 0, len(finalChanges.Update)),
+		Deleted:  make([]string, 0, len(finalChanges.Delete)),
+		Warnings: warnings,
 	}
 	if finalChanges.IsEmpty() {
 		body.Message = "no changes detected in the rule group"
@@ -770,6 +859,90 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 	return nil
 }
 
+// validateRuleDependencies checks that the alert rules the new and updated rules read the state of exist,
+// that no remaining rule reads the state of a deleted rule, and that the changes do not make the alert rules
+// of the organization depend on each other in a cycle.
+func validateRuleDependencies(ctx context.Context, ruleStore RuleStore, groupChanges *store.GroupDelta) error {
+	changed := make([]*ngmodels.AlertRule, 0, len(groupChanges.New)+len(groupChanges.Update))
+	changed = append(changed, groupChanges.New...)
+	for _, upd := range groupChanges.Update {
+		changed = append(changed, upd.New)
+	}
+	deleted := make([]string, 0, len(groupChanges.Delete))
+	for _, rule := range groupChanges.Delete {
+		deleted = append(deleted, rule.UID)
+	}
+
+	return validateRuleDependencyChanges(ctx, ruleStore, groupChanges.GroupKey.OrgID, changed, deleted)
+}
+
+// validateRuleDependencyChanges validates the changes with ngmodels.ValidateRuleDependencyChanges. Only the rules
+// the validation can see are read: the deleted rules, the rules that depend on them, and the rules the changed rules
+// depend on, directly or through other rules. A cycle that the changes create goes through a changed rule, so it is
+// made of these rules.
+func validateRuleDependencyChanges(ctx context.Context, ruleStore RuleStore, orgID int64, changed []*ngmodels.AlertRule, deleted []string) error {
+	var rules []*ngmodels.AlertRule
+	if len(deleted) > 0 {
+		dependents, err := ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID, DependsOnRuleUIDs: deleted})
+		if err != nil {
+			return fmt.Errorf("failed to get alert rules: %w", err)
+		}
+		rules = append(rules, dependents...)
+	}
+
+	requested := make(map[string]struct{})
+	pending := slices.Clone(deleted)
+	for _, rule := range changed {
+		pending = append(pending, rule.GetRuleStateDependencies()...)
+	}
+	for len(pending) > 0 {
+		uids := make([]string, 0, len(pending))
+		for _, uid := range pending {
+			if _, ok := requested[uid]; !ok {
+				requested[uid] = struct{}{}
+				uids = append(uids, uid)
+			}
+		}
+		if len(uids) == 0 {
+			break
+		}
+		found, err := ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID, RuleUIDs: uids})
+		if err != nil {
+			return fmt.Errorf("failed to get alert rules: %w", err)
+		}
+		rules = append(rules, found...)
+		pending = nil
+		for _, rule := range found {
+			pending = append(pending, rule.GetRuleStateDependencies()...)
+		}
+	}
+	return ngmodels.ValidateRuleDependencyChanges(rules, changed, deleted)
+}
+
+// enforceRulePolicy checks the new and updated rules against the rule policy of the organization.
+// It returns the violations as warnings if the policy does not reject them.
+func (srv RulerSrv) enforceRulePolicy(ctx context.Context, groupChanges *store.GroupDelta) ([]string, error) {
+	if srv.rulePolicy == nil {
+		return nil, nil
+	}
+	changed := make([]*ngmodels.AlertRule, 0, len(groupChanges.New)+len(groupChanges.Update))
+	changed = append(changed, groupChanges.New...)
+	for _, upd := range groupChanges.Update {
+		if shouldValidate(upd) {
+			changed = append(changed, upd.New)
+		}
+	}
+	violations, err := srv.rulePolicy.Enforce(ctx, groupChanges.GroupKey.OrgID, changed)
+	if err != nil {
+		return nil, err
+	}
+	warnings := make([]string, 0, len(violations))
+	for _, v := range violations {
+		warnings = append(warnings, v.String())
+	}
+	return warnings, nil
+}
+
 // shouldValidate returns true if the rule is not paused and there are changes in the rule that are not ignored
 func shouldValidate(delta store.RuleDelta) bool {
 	for _, diff := range delta.Diff {
@@ -898,7 +1071,7 @@ func (srv RulerSrv) RouteUpdateNamespaceRules(c *contextmodel.ReqContext, body a
 
 				rulesToUpdate = append(rulesToUpdate, &r)
 			}
-			_, _, err := srv.performUpdateAlertRules(ctx, c, groupKey, rulesToUpdate, false)
+			_, _, _, err := srv.performUpdateAlertRules(ctx, c, groupKey, rulesToUpdate, false, nil)
 			if errors.Is(err, errProvisionedResource) {
 				continue
 			}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+var errAlertInstanceNotFound = errors.New("alert instance not found")
+
+// AlertAcknowledger acknowledges the alert instances of the rules.
+type AlertAcknowledger interface {
+	Acknowledge(ctx context.Context, rule *ngmodels.AlertRule, s *state.State, ack ngmodels.AlertAcknowledgement) (*ngmodels.AlertAcknowledgement, error)
+	Unacknowledge(ctx context.Context, rule *ngmodels.AlertRule, s *state.State, user string) error
+}
+
+// RoutePostAcknowledgeAlert acknowledges the firing alert instance of the rule with the given labels, and assigns it
+// to a user. No more notifications are sent for the alert instance while it is acknowledged.
+func (srv RulerSrv) RoutePostAcknowledgeAlert(c *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	s := srv.findAlertInstance(ctx, &rule, body.Labels)
+	if s == nil {
+		return ErrResp(http.StatusNotFound, errAlertInstanceNotFound, "")
+	}
+
+	ack, err := srv.acknowledger.Acknowledge(ctx, &rule, s, ngmodels.AlertAcknowledgement{
+		AcknowledgedBy: c.SignedInUser.GetLogin(),
+		Assignee:       body.Assignee,
+		Note:           body.Note,
+		ExpiresAt:      body.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertAcknowledgementInvalid) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to acknowledge alert")
+	}
+	return response.JSON(http.StatusOK, apimodels.GettableAlertAcknowledgement{
+		Labels:         body.Labels,
+		AcknowledgedBy: ack.AcknowledgedBy,
+		Assignee:       ack.Assignee,
+		Note:           ack.Note,
+		Created:        ack.Created,
+		ExpiresAt:      ack.ExpiresAt,
+	})
+}
+
+// RoutePostUnacknowledgeAlert removes the acknowledgement of the alert instance of the rule with the given labels.
+func (srv RulerSrv) RoutePostUnacknowledgeAlert(c *contextmodel.ReqContext, body apimodels.PostableAlertUnacknowledgement, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	s := srv.findAlertInstance(ctx, &rule, body.Labels)
+	if s == nil {
+		return ErrResp(http.StatusNotFound, errAlertInstanceNotFound, "")
+	}
+
+	if err := srv.acknowledger.Unacknowledge(ctx, &rule, s, c.SignedInUser.GetLogin()); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertAcknowledgementNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to remove alert acknowledgement")
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// findAlertInstance returns the state of the rule with the given labels. The labels may omit the internal labels,
+// as they are omitted by the Prometheus-compatible API.
+func (srv RulerSrv) findAlertInstance(ctx context.Context, rule *ngmodels.AlertRule, labels map[string]string) *state.State {
+	if len(labels) == 0 {
+		return nil
+	}
+	for _, s := range srv.alertInstances.GetStatesForRuleUID(ctx, rule.OrgID, rule.UID) {
+		if maps.Equal(s.Labels, labels) || maps.Equal(s.GetLabels(ngmodels.WithoutInternalLabels()), labels) {
+			return s
+		}
+	}
+	return nil
+}
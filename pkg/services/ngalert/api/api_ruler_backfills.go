@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RecordingRuleBackfiller creates and controls the backfills of recording rules.
+type RecordingRuleBackfiller interface {
+	Create(ctx context.Context, rule *ngmodels.AlertRule, from, to time.Time, createdBy string) (*ngmodels.RecordingRuleBackfill, error)
+	Get(ctx context.Context, orgID int64, uid string) (*ngmodels.RecordingRuleBackfill, error)
+	List(ctx context.Context, orgID int64, ruleUID string) ([]*ngmodels.RecordingRuleBackfill, error)
+	Cancel(ctx context.Context, orgID int64, uid string) (*ngmodels.RecordingRuleBackfill, error)
+	Resume(ctx context.Context, orgID int64, uid string) (*ngmodels.RecordingRuleBackfill, error)
+}
+
+// RouteGetRecordingRuleBackfills returns the backfills of the recording rule, the oldest first.
+func (srv RulerSrv) RouteGetRecordingRuleBackfills(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	backfills, err := srv.backfiller.List(ctx, rule.OrgID, rule.UID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to list recording rule backfills")
+	}
+	result := make(apimodels.GettableRecordingRuleBackfills, 0, len(backfills))
+	for _, b := range backfills {
+		result = append(result, toGettableRecordingRuleBackfill(b))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RoutePostRecordingRuleBackfill creates a backfill of the recording rule over a past time range. The backfill
+// runs in the background.
+func (srv RulerSrv) RoutePostRecordingRuleBackfill(c *contextmodel.ReqContext, body apimodels.PostableRecordingRuleBackfill, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	b, err := srv.backfiller.Create(ctx, &rule, body.From, body.To, c.SignedInUser.GetLogin())
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrRecordingRuleBackfillInvalid) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to create recording rule backfill")
+	}
+	return response.JSON(http.StatusAccepted, toGettableRecordingRuleBackfill(b))
+}
+
+// RoutePostCancelRecordingRuleBackfill stops the pending or running backfill of the recording rule.
+func (srv RulerSrv) RoutePostCancelRecordingRuleBackfill(c *contextmodel.ReqContext, ruleUID string, backfillUID string) response.Response {
+	return srv.changeRecordingRuleBackfill(c, ruleUID, backfillUID, srv.backfiller.Cancel)
+}
+
+// RoutePostResumeRecordingRuleBackfill continues the failed or canceled backfill of the recording rule from
+// the last saved evaluation.
+func (srv RulerSrv) RoutePostResumeRecordingRuleBackfill(c *contextmodel.ReqContext, ruleUID string, backfillUID string) response.Response {
+	return srv.changeRecordingRuleBackfill(c, ruleUID, backfillUID, srv.backfiller.Resume)
+}
+
+func (srv RulerSrv) changeRecordingRuleBackfill(c *contextmodel.ReqContext, ruleUID string, backfillUID string, change func(context.Context, int64, string) (*ngmodels.RecordingRuleBackfill, error)) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	b, err := srv.backfiller.Get(ctx, rule.OrgID, backfillUID)
+	if err == nil && b.RuleUID != rule.UID {
+		err = ngmodels.ErrRecordingRuleBackfillNotFound
+	}
+	if err == nil {
+		b, err = change(ctx, rule.OrgID, backfillUID)
+	}
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrRecordingRuleBackfillNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, ngmodels.ErrRecordingRuleBackfillInvalid) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to change recording rule backfill")
+	}
+	return response.JSON(http.StatusOK, toGettableRecordingRuleBackfill(b))
+}
+
+func toGettableRecordingRuleBackfill(b *ngmodels.RecordingRuleBackfill) apimodels.GettableRecordingRuleBackfill {
+	return apimodels.GettableRecordingRuleBackfill{
+		UID:         b.UID,
+		RuleUID:     b.RuleUID,
+		From:        b.From,
+		To:          b.To,
+		Status:      string(b.Status),
+		Next:        b.Next,
+		Evaluations: b.Evaluations,
+		Completed:   b.Completed,
+		Failed:      b.Failed,
+		LastError:   b.LastError,
+		CreatedBy:   b.CreatedBy,
+		Created:     b.Created,
+		Updated:     b.Updated,
+	}
+}
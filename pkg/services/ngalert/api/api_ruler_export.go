@@ -7,12 +7,14 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
 
 	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	apivalidation "github.com/grafana/grafana/pkg/services/ngalert/api/validation"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
 )
 
 // ExportFromPayload converts the rule groups from the argument `ruleGroupConfig` to export format. All rules are expected to be fully specified. The access to data sources mentioned in the rules is not enforced.
@@ -52,6 +54,63 @@ func (srv RulerSrv) ExportRules(c *contextmodel.ReqContext) response.Response {
 	// The similar method exists in provisioning (see ProvisioningSrv.RouteGetAlertRulesExport).
 	// Modification to parameters and response format should be made in these two methods at the same time.
 
+	groups, errResp := srv.getRuleGroupsForExport(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(groups)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+	return exportResponse(c, e)
+}
+
+// ExportRulesToPrometheus reads alert rules that user has access to from database according to the filters
+// and converts them to Prometheus rule groups. The rules that cannot be converted are listed in the response
+// with the reason, and so are the settings of the converted rules that Prometheus does not support. It responds with YAML unless JSON is requested in the Accept header.
+func (srv RulerSrv) ExportRulesToPrometheus(c *contextmodel.ReqContext) response.Response {
+	groups, errResp := srv.getRuleGroupsForExport(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	datasourceTypes := srv.getDatasourceTypes(c, groups)
+	result := apimodels.PrometheusRulesExport{
+		Namespaces: map[string][]apimodels.PrometheusRuleGroup{},
+	}
+	for _, group := range groups {
+		promGroup, failures, warnings := prom.GrafanaRuleGroupToPrometheus(group.Title, group.Rules, datasourceTypes)
+		for _, f := range failures {
+			result.Skipped = append(result.Skipped, apimodels.PrometheusRuleExportFailure{
+				UID:          f.Rule.UID,
+				Title:        f.Rule.Title,
+				NamespaceUID: f.Rule.NamespaceUID,
+				RuleGroup:    f.Rule.RuleGroup,
+				Reason:       f.Reason,
+			})
+		}
+		for _, w := range warnings {
+			result.Warnings = append(result.Warnings, apimodels.PrometheusRuleExportWarning{
+				UID:          w.Rule.UID,
+				Title:        w.Rule.Title,
+				NamespaceUID: w.Rule.NamespaceUID,
+				RuleGroup:    w.Rule.RuleGroup,
+				Field:        w.Field,
+				Reason:       w.Reason,
+			})
+		}
+		if len(promGroup.Rules) == 0 {
+			continue
+		}
+		result.Namespaces[group.FolderFullpath] = append(result.Namespaces[group.FolderFullpath], prometheusRuleGroupToAPI(promGroup))
+	}
+
+	return convertPrometheusResponse(c, http.StatusOK, result)
+}
+
+// getRuleGroupsForExport reads the rule groups that match the export filters in the request, sorted by folder and title.
+func (srv RulerSrv) getRuleGroupsForExport(c *contextmodel.ReqContext) ([]ngmodels.AlertRuleGroupWithFolderFullpath, response.Response) {
 	folderUIDs := c.QueryStrings("folderUid")
 	group := c.Query("group")
 	uid := c.Query("ruleUid")
@@ -59,16 +118,16 @@ func (srv RulerSrv) ExportRules(c *contextmodel.ReqContext) response.Response {
 	var groups []ngmodels.AlertRuleGroupWithFolderFullpath
 	if uid != "" {
 		if group != "" || len(folderUIDs) > 0 {
-			return ErrResp(http.StatusBadRequest, errors.New("group and folder should not be specified when a single rule is requested"), "")
+			return nil, ErrResp(http.StatusBadRequest, errors.New("group and folder should not be specified when a single rule is requested"), "")
 		}
 		rulesGroup, err := srv.getRuleWithFolderFullpathByRuleUid(c, uid)
 		if err != nil {
-			return errorToResponse(err)
+			return nil, errorToResponse(err)
 		}
 		groups = []ngmodels.AlertRuleGroupWithFolderFullpath{rulesGroup}
 	} else if group != "" {
 		if len(folderUIDs) != 1 || folderUIDs[0] == "" {
-			return ErrResp(http.StatusBadRequest,
+			return nil, ErrResp(http.StatusBadRequest,
 				fmt.Errorf("group name must be specified together with a single folder_uid parameter. Got %d", len(folderUIDs)),
 				"",
 			)
@@ -79,29 +138,24 @@ func (srv RulerSrv) ExportRules(c *contextmodel.ReqContext) response.Response {
 			RuleGroup:    group,
 		})
 		if err != nil {
-			return errorToResponse(err)
+			return nil, errorToResponse(err)
 		}
 		groups = []ngmodels.AlertRuleGroupWithFolderFullpath{rulesGroup}
 	} else {
 		var err error
 		groups, err = srv.getRulesWithFolderFullPathInFolders(c, folderUIDs)
 		if err != nil {
-			return errorToResponse(err)
+			return nil, errorToResponse(err)
 		}
 	}
 
 	if len(groups) == 0 {
-		return response.Empty(http.StatusNotFound)
+		return nil, response.Empty(http.StatusNotFound)
 	}
 
 	// sort result so the response is always stable
 	ngmodels.SortAlertRuleGroupWithFolderTitle(groups)
-
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(groups)
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
-	}
-	return exportResponse(c, e)
+	return groups, nil
 }
 
 // getRuleWithFolderFullpathByRuleUid calls getAuthorizedRuleByUid and combines its result with folder (aka namespace) title.
@@ -192,3 +246,50 @@ func (srv RulerSrv) getRulesWithFolderFullPathInFolders(c *contextmodel.ReqConte
 	}
 	return result, nil
 }
+
+// getDatasourceTypes returns the types of the data sources that the rules query. The data sources that
+// cannot be read are left out, so that the rules that query them are reported as not convertible.
+func (srv RulerSrv) getDatasourceTypes(c *contextmodel.ReqContext, groups []ngmodels.AlertRuleGroupWithFolderFullpath) map[string]string {
+	result := make(map[string]string)
+	for _, group := range groups {
+		for i := range group.Rules {
+			for _, uid := range group.Rules[i].GetQueryDatasourceUIDs() {
+				if _, ok := result[uid]; ok {
+					continue
+				}
+				ds, err := srv.datasourceCache.GetDatasourceByUID(c.Req.Context(), uid, c.SignedInUser, c.SkipDSCache)
+				if err != nil {
+					if !errors.Is(err, datasources.ErrDataSourceNotFound) {
+						srv.log.Warn("Failed to get data source to export rules", "datasource_uid", uid, "error", err)
+					}
+					continue
+				}
+				result[uid] = ds.Type
+			}
+		}
+	}
+	return result
+}
+
+func prometheusRuleGroupToAPI(group prom.PrometheusRuleGroup) apimodels.PrometheusRuleGroup {
+	rules := make([]apimodels.PrometheusRule, len(group.Rules))
+	for i, r := range group.Rules {
+		rules[i] = apimodels.PrometheusRule{
+			Alert:         r.Alert,
+			Expr:          r.Expr,
+			For:           r.For,
+			KeepFiringFor: r.KeepFiringFor,
+			Labels:        r.Labels,
+			Annotations:   r.Annotations,
+			Record:        r.Record,
+		}
+	}
+	return apimodels.PrometheusRuleGroup{
+		Name:        group.Name,
+		Interval:    group.Interval,
+		QueryOffset: group.QueryOffset,
+		Limit:       group.Limit,
+		Rules:       rules,
+		Labels:      group.Labels,
+	}
+}
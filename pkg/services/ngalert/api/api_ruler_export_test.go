@@ -11,13 +11,17 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	folder2 "github.com/grafana/grafana/pkg/services/folder"
 	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -501,3 +505,109 @@ func TestExportRules(t *testing.T) {
 		})
 	}
 }
+
+func TestExportRulesToPrometheus(t *testing.T) {
+	orgID := int64(1)
+	f := &folder2.Folder{UID: "folder-uid", Title: "team", Fullpath: "alerts/team"}
+	ruleStore := fakes.NewRuleStore(t)
+
+	gen := ngmodels.RuleGen
+	groupKey := ngmodels.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: f.UID, RuleGroup: "group"}
+	rule := func(title, datasourceUID string, index int) *ngmodels.AlertRule {
+		r := gen.With(
+			gen.WithGroupKey(groupKey),
+			gen.WithTitle(title),
+			gen.WithGroupIndex(index),
+			gen.WithIntervalSeconds(60),
+			gen.WithIsPaused(false),
+			gen.WithFor(time.Minute),
+			gen.WithKeepFiringFor(0),
+			gen.WithLabels(map[string]string{"severity": "critical"}),
+			gen.WithAnnotations(nil),
+		).GenerateRef()
+		r.Record = nil
+		r.Metadata = ngmodels.AlertRuleMetadata{}
+		r.NoDataState = ngmodels.OK
+		r.ExecErrState = ngmodels.OkErrState
+		r.MissingSeriesEvalsToResolve = new(int64(1))
+		r.NotificationSettings = nil
+		r.Condition = "B"
+		r.Data = []ngmodels.AlertQuery{
+			{RefID: "A", DatasourceUID: datasourceUID, Model: json.RawMessage(`{"expr":"up","instant":true}`)},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"lt","params":[1]}}]}`)},
+		}
+		return r
+	}
+	convertible := rule("convertible", "prometheus-uid", 1)
+	convertible.NotificationSettings = &ngmodels.NotificationSettings{Receiver: "team"}
+	notConvertible := rule("not convertible", "testdata-uid", 2)
+	ruleStore.PutRule(context.Background(), convertible, notConvertible)
+	ruleStore.Folders[orgID] = []*folder2.Folder{f}
+
+	srv := createService(ruleStore, nil)
+	srv.datasourceCache = &fakeDatasources.FakeCacheService{DataSources: []*datasources.DataSource{
+		{UID: "prometheus-uid", Type: datasources.DS_PROMETHEUS},
+		{UID: "testdata-uid", Type: datasources.DS_TESTDATA},
+	}}
+
+	perms := map[int64]map[string][]string{
+		orgID: {
+			folder2.ActionFoldersRead:            []string{folder2.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+			accesscontrol.ActionAlertingRuleRead: []string{folder2.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+			datasources.ActionQuery: []string{
+				datasources.ScopeProvider.GetResourceScopeUID("prometheus-uid"),
+				datasources.ScopeProvider.GetResourceScopeUID("testdata-uid"),
+			},
+		},
+	}
+
+	t.Run("returns rule groups in Prometheus format, rules that cannot be converted and unsupported settings", func(t *testing.T) {
+		rc := createRequestContextWithPerms(orgID, perms, nil)
+		rc.Req.Header.Add("Accept", "application/json")
+
+		resp := srv.ExportRulesToPrometheus(rc)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.PrometheusRulesExport
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, map[string][]apimodels.PrometheusRuleGroup{
+			"alerts/team": {{
+				Name:     "group",
+				Interval: model.Duration(time.Minute),
+				Rules: []apimodels.PrometheusRule{{
+					Alert:  "convertible",
+					Expr:   "(up) < 1",
+					For:    new(model.Duration(time.Minute)),
+					Labels: map[string]string{"severity": "critical"},
+				}},
+			}},
+		}, result.Namespaces)
+		require.Equal(t, []apimodels.PrometheusRuleExportFailure{{
+			UID:          notConvertible.UID,
+			Title:        "not convertible",
+			NamespaceUID: f.UID,
+			RuleGroup:    "group",
+			Reason:       "query A uses data source of type grafana-testdata-datasource, must be prometheus-compatible or loki",
+		}}, result.Skipped)
+		require.Len(t, result.Warnings, 1)
+		require.Equal(t, convertible.UID, result.Warnings[0].UID)
+		require.Equal(t, "notification_settings", result.Warnings[0].Field)
+	})
+
+	t.Run("responds with YAML by default", func(t *testing.T) {
+		rc := createRequestContextWithPerms(orgID, perms, nil)
+
+		resp := srv.ExportRulesToPrometheus(rc)
+		resp.WriteTo(rc)
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, "text/yaml", rc.Resp.Header().Get("Content-Type"))
+	})
+
+	t.Run("rejects a group without a single folder", func(t *testing.T) {
+		rc := createRequestContextWithPerms(orgID, perms, nil)
+		rc.Req.Form.Set("group", "group")
+
+		require.Equal(t, http.StatusBadRequest, srv.ExportRulesToPrometheus(rc).Status())
+	})
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
@@ -100,6 +101,23 @@ func TestRouteDeleteAlertRules(t *testing.T) {
 				require.Equalf(t, 202, response.Status(), "Expected 202 but got %d: %v", response.Status(), string(response.Body()))
 				assertRulesDeleted(t, authorizedRulesInFolder, ruleStore)
 			})
+			t.Run("return BadRequest if other rules depend on the state of the rules", func(t *testing.T) {
+				ruleStore := initFakeRuleStore(t)
+				provisioningStore := fakes.NewFakeProvisioningStore()
+
+				folderGen := gen.With(gen.WithNamespace(folder.ToFolderReference()))
+				rule := folderGen.With(gen.WithGroupPrefix("authz-")).GenerateRef()
+				dependent := gen.With(withRuleStateQueries(rule.UID)).GenerateRef()
+				ruleStore.PutRule(context.Background(), rule, dependent)
+
+				permissions := createPermissionsForRulesWithoutDS([]*models.AlertRule{rule}, orgID)
+				requestCtx := createRequestContextWithPerms(orgID, permissions, nil)
+
+				response := createServiceWithProvenanceStore(ruleStore, provisioningStore).RouteDeleteAlertRules(requestCtx, folder.UID, "")
+
+				require.Equalf(t, 400, response.Status(), "Expected 400 but got %d: %v", response.Status(), string(response.Body()))
+				require.Empty(t, getRecordedCommand(ruleStore))
+			})
 			t.Run("return Forbidden if user is not authorized to access any group in the folder", func(t *testing.T) {
 				ruleStore := initFakeRuleStore(t)
 				ruleStore.PutRule(context.Background(), gen.With(gen.WithNamespace(folder.ToFolderReference())).GenerateManyRef(1, 5)...)
@@ -686,6 +704,54 @@ func TestRouteGetRuleVersionsByUID(t *testing.T) {
 	})
 }
 
+func TestRouteGetRuleDependencies(t *testing.T) {
+	gen := models.RuleGen
+	orgID := rand.Int63()
+	ruleStore := fakes.NewRuleStore(t)
+	folder1 := randFolder()
+	folder2 := randFolder()
+	ruleStore.Folders[orgID] = []*folder.Folder{folder1, folder2}
+
+	group1Key := models.GenerateGroupKey(orgID)
+	group1Key.NamespaceUID = folder1.UID
+	group2Key := models.GenerateGroupKey(orgID)
+	group2Key.NamespaceUID = folder2.UID
+
+	visible := []*models.AlertRule{
+		gen.With(gen.WithGroupKey(group1Key), gen.WithUID("parent")).GenerateRef(),
+		gen.With(gen.WithGroupKey(group1Key), gen.WithUID("child"), withRuleStateQueries("parent", "hidden")).GenerateRef(),
+		gen.With(gen.WithGroupKey(group1Key), gen.WithUID("other"), withRuleStateQueries("hidden")).GenerateRef(),
+		gen.With(gen.WithGroupKey(group1Key), gen.WithUID("independent")).GenerateRef(),
+	}
+	hidden := gen.With(gen.WithGroupKey(group2Key), gen.WithUID("hidden"), withRuleStateQueries("parent")).GenerateRef()
+	ruleStore.PutRule(context.Background(), append(visible, hidden)...)
+
+	request := createRequestContextWithPerms(orgID, createPermissionsForRules(visible, orgID), nil)
+	response := createService(ruleStore, nil).RouteGetRuleDependencies(request)
+	require.Equal(t, http.StatusOK, response.Status())
+
+	result := apimodels.RuleDependencyGraph{}
+	require.NoError(t, json.Unmarshal(response.Body(), &result))
+	require.Equal(t, []apimodels.RuleDependencyNode{
+		{
+			UID:          "child",
+			Title:        visible[1].Title,
+			NamespaceUID: folder1.UID,
+			RuleGroup:    group1Key.RuleGroup,
+			DependsOn:    []string{"parent"},
+			Dependents:   []string{},
+		},
+		{
+			UID:          "parent",
+			Title:        visible[0].Title,
+			NamespaceUID: folder1.UID,
+			RuleGroup:    group1Key.RuleGroup,
+			DependsOn:    []string{},
+			Dependents:   []string{"child"},
+		},
+	}, result.Rules)
+}
+
 func TestRouteGetRulesConfig(t *testing.T) {
 	gen := models.RuleGen
 	t.Run("fine-grained access is enabled", func(t *testing.T) {
@@ -1049,6 +1115,116 @@ func TestValidateQueries(t *testing.T) {
 	})
 }
 
+func TestValidateRuleDependencies(t *testing.T) {
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1))
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(),
+		gen.With(gen.WithUID("parent")).GenerateRef(),
+		gen.With(gen.WithUID("child"), withRuleStateQueries("parent")).GenerateRef(),
+	)
+
+	t.Run("should accept rules without dependencies", func(t *testing.T) {
+		delta := store.GroupDelta{New: []*models.AlertRule{gen.GenerateRef()}}
+		require.NoError(t, validateRuleDependencies(context.Background(), ruleStore, &delta))
+	})
+
+	t.Run("should accept dependencies on existing rules", func(t *testing.T) {
+		delta := store.GroupDelta{New: []*models.AlertRule{gen.With(withRuleStateQueries("parent", "child")).GenerateRef()}}
+		require.NoError(t, validateRuleDependencies(context.Background(), ruleStore, &delta))
+	})
+
+	t.Run("should reject dependencies on rules that do not exist", func(t *testing.T) {
+		delta := store.GroupDelta{New: []*models.AlertRule{gen.With(withRuleStateQueries("missing")).GenerateRef()}}
+		err := validateRuleDependencies(context.Background(), ruleStore, &delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "missing")
+	})
+
+	t.Run("should reject dependencies on deleted rules", func(t *testing.T) {
+		parent, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: "parent"})
+		require.NoError(t, err)
+		delta := store.GroupDelta{
+			New:    []*models.AlertRule{gen.With(withRuleStateQueries("parent")).GenerateRef()},
+			Delete: []*models.AlertRule{parent},
+		}
+		require.ErrorIs(t, validateRuleDependencies(context.Background(), ruleStore, &delta), models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject deleting rules that other rules depend on", func(t *testing.T) {
+		parent, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: "parent"})
+		require.NoError(t, err)
+		delta := store.GroupDelta{GroupKey: parent.GetGroupKey(), Delete: []*models.AlertRule{parent}}
+		err = validateRuleDependencies(context.Background(), ruleStore, &delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "child")
+	})
+
+	t.Run("should validate changes to rules without dependencies", func(t *testing.T) {
+		existing, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: "child"})
+		require.NoError(t, err)
+		parent, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: "parent"})
+		require.NoError(t, err)
+		// The parent is replaced by a rule without dependencies while the child still depends on it.
+		delta := store.GroupDelta{
+			GroupKey: parent.GetGroupKey(),
+			Update:   []store.RuleDelta{{Existing: existing, New: models.CopyRule(existing, models.RuleGen.WithTitle("renamed"), withRuleStateQueries("parent"))}},
+			Delete:   []*models.AlertRule{parent},
+		}
+		require.ErrorIs(t, validateRuleDependencies(context.Background(), ruleStore, &delta), models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject updates that make rules depend on each other", func(t *testing.T) {
+		existing, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: "parent"})
+		require.NoError(t, err)
+		updated := models.CopyRule(existing, withRuleStateQueries("child"))
+		delta := store.GroupDelta{Update: []store.RuleDelta{{Existing: existing, New: updated}}}
+		err = validateRuleDependencies(context.Background(), ruleStore, &delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "child -> parent -> child")
+	})
+
+	t.Run("should not read all rules of the organization", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(),
+			gen.With(gen.WithUID("parent")).GenerateRef(),
+			gen.With(gen.WithUID("child"), withRuleStateQueries("parent")).GenerateRef(),
+			gen.With(gen.WithUID("grandchild"), withRuleStateQueries("child")).GenerateRef(),
+		)
+		parent, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: "parent"})
+		require.NoError(t, err)
+		delta := store.GroupDelta{
+			New:    []*models.AlertRule{gen.With(withRuleStateQueries("grandchild")).GenerateRef()},
+			Delete: []*models.AlertRule{parent},
+		}
+		err = validateRuleDependencies(context.Background(), ruleStore, &delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "child")
+
+		queries := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			q, ok := cmd.(models.ListAlertRulesQuery)
+			return q, ok
+		})
+		require.NotEmpty(t, queries)
+		for _, q := range queries {
+			q := q.(models.ListAlertRulesQuery)
+			require.True(t, len(q.RuleUIDs) > 0 || len(q.DependsOnRuleUIDs) > 0, "unexpected query of all rules: %+v", q)
+		}
+	})
+}
+
+// withRuleStateQueries sets the queries of the rule to a query and a rule state expression for each of the rules.
+func withRuleStateQueries(uids ...string) models.AlertRuleMutator {
+	queries := []models.AlertQuery{models.GenerateAlertQuery()}
+	for i, uid := range uids {
+		queries = append(queries, models.AlertQuery{
+			RefID:         fmt.Sprintf("S%d", i),
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(fmt.Sprintf(`{"type": "rule_state", "ruleUid": %q}`, uid)),
+		})
+	}
+	return models.RuleGen.WithQuery(queries...)
+}
+
 func createServiceWithProvenanceStore(store *fakes.RuleStore, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(store, nil)
 	svc.provenanceStore = provenanceStore
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var errRuleVersionNotFound = errors.New("rule version not found")
+
+// ruleRestore identifies the rule that an update of a rule group restores to one of its previous versions.
+type ruleRestore struct {
+	UID     string
+	Version int64
+}
+
+// RouteGetRuleVersionsDiff returns the changes between two versions of the rule. The version to compare to
+// defaults to the current version of the rule.
+func (srv RulerSrv) RouteGetRuleVersionsDiff(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid version to compare from: %w", err), "")
+	}
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	to := rule.Version
+	if s := c.Query("to"); s != "" {
+		if to, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid version to compare to: %w", err), "")
+		}
+	}
+
+	versions, err := srv.getRuleVersions(ctx, rule, from, to)
+	if err != nil {
+		if errors.Is(err, errRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule history", err)
+	}
+
+	diff, err := ruleVersionDiff(versions[0], versions[1])
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to compare rule versions")
+	}
+	return response.JSON(http.StatusOK, diff)
+}
+
+// RoutePostRestoreRuleVersion updates the rule with the definition of one of its previous versions. The rule keeps
+// its UID, and the folder, group and evaluation interval it currently belongs to. The new version records the user
+// who restored it and the restored version.
+func (srv RulerSrv) RoutePostRestoreRuleVersion(c *contextmodel.ReqContext, ruleUID string, version string) response.Response {
+	ctx := c.Req.Context()
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid version: %w", err), "")
+	}
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+	if v == rule.Version {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("version %d is the current version of the rule", v), "")
+	}
+
+	versions, err := srv.getRuleVersions(ctx, rule, v)
+	if err != nil {
+		if errors.Is(err, errRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule history", err)
+	}
+	restored, err := restoreRuleVersion(rule, versions[0])
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to restore rule version")
+	}
+
+	groupKey := rule.GetGroupKey()
+	group, err := srv.getAuthorizedRuleGroup(ctx, c, groupKey)
+	if err != nil {
+		return errorToResponse(err)
+	}
+	rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group))
+	for _, r := range group {
+		if r.UID == rule.UID {
+			r = &restored
+		}
+		rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true, HasEditorSettings: true})
+	}
+	return srv.updateAlertRulesInGroup(c, groupKey, rules, false, &ruleRestore{UID: rule.UID, Version: v})
+}
+
+// getRuleVersions returns the given versions of the rule in the same order. The current version is returned as is,
+// the others are taken from the version history.
+func (srv RulerSrv) getRuleVersions(ctx context.Context, rule ngmodels.AlertRule, versions ...int64) ([]ngmodels.AlertRule, error) {
+	var history []*ngmodels.AlertRuleVersion
+	if slices.ContainsFunc(versions, func(v int64) bool { return v != rule.Version }) {
+		var err error
+		history, err = srv.store.GetAlertRuleVersions(ctx, rule.OrgID, rule.GUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]ngmodels.AlertRule, 0, len(versions))
+	for _, v := range versions {
+		if v == rule.Version {
+			result = append(result, rule)
+			continue
+		}
+		idx := slices.IndexFunc(history, func(h *ngmodels.AlertRuleVersion) bool { return h.Version == v })
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: rule %s has no version %d", errRuleVersionNotFound, rule.UID, v)
+		}
+		result = append(result, history[idx].AlertRule)
+	}
+	return result, nil
+}
+
+// restoreRuleVersion returns the current rule with the definition of the previous version.
+func restoreRuleVersion(current, previous ngmodels.AlertRule) (ngmodels.AlertRule, error) {
+	restored := *previous.Copy()
+	restored.ID = current.ID
+	restored.GUID = current.GUID
+	restored.UID = current.UID
+	restored.OrgID = current.OrgID
+	restored.Version = current.Version
+	restored.Updated = current.Updated
+	restored.UpdatedBy = current.UpdatedBy
+	restored.NamespaceUID = current.NamespaceUID
+	restored.FolderFullpath = current.FolderFullpath
+	restored.RuleGroup = current.RuleGroup
+	restored.RuleGroupIndex = current.RuleGroupIndex
+	restored.IntervalSeconds = current.IntervalSeconds
+	// Versions do not store the dashboard and panel, they are restored from the annotations.
+	restored.DashboardUID = nil
+	restored.PanelID = nil
+	if err := restored.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+	return restored, nil
+}
+
+// ruleVersionFields are the settings of a rule that are not compared separately in a version diff.
+type ruleVersionFields struct {
+	Title                       string                       `json:"title"`
+	Condition                   string                       `json:"condition"`
+	NoDataState                 ngmodels.NoDataState         `json:"no_data_state"`
+	ExecErrState                ngmodels.ExecutionErrorState `json:"exec_err_state"`
+	For                         model.Duration               `json:"for"`
+	KeepFiringFor               model.Duration               `json:"keep_firing_for"`
+	IsPaused                    bool                         `json:"is_paused"`
+	Record                      *apimodels.Record            `json:"record"`
+	MissingSeriesEvalsToResolve *int64                       `json:"missing_series_evals_to_resolve"`
+	Metadata                    *apimodels.AlertRuleMetadata `json:"metadata"`
+	NamespaceUID                string                       `json:"namespace_uid"`
+	RuleGroup                   string                       `json:"rule_group"`
+	IntervalSeconds             int64                        `json:"intervalSeconds"`
+}
+
+func ruleVersionFieldsFromRule(r ngmodels.AlertRule) ruleVersionFields {
+	return ruleVersionFields{
+		Title:                       r.Title,
+		Condition:                   r.Condition,
+		NoDataState:                 r.NoDataState,
+		ExecErrState:                r.ExecErrState,
+		For:                         model.Duration(r.For),
+		KeepFiringFor:               model.Duration(r.KeepFiringFor),
+		IsPaused:                    r.IsPaused,
+		Record:                      ApiRecordFromModelRecord(r.Record),
+		MissingSeriesEvalsToResolve: r.MissingSeriesEvalsToResolve,
+		Metadata:                    AlertRuleMetadataFromModelMetadata(r.Metadata),
+		NamespaceUID:                r.NamespaceUID,
+		RuleGroup:                   r.RuleGroup,
+		IntervalSeconds:             r.IntervalSeconds,
+	}
+}
+
+// ruleVersionDiff returns the changes from one version of a rule to another.
+func ruleVersionDiff(from, to ngmodels.AlertRule) (apimodels.RuleVersionDiff, error) {
+	fields, err := diffJSONFields("", ruleVersionFieldsFromRule(from), ruleVersionFieldsFromRule(to))
+	if err != nil {
+		return apimodels.RuleVersionDiff{}, err
+	}
+	queries, err := diffRuleQueries(from.Data, to.Data)
+	if err != nil {
+		return apimodels.RuleVersionDiff{}, err
+	}
+	notificationSettings, err := diffJSONFields("",
+		AlertRuleNotificationSettingsFromNotificationSettings(from.NotificationSettings),
+		AlertRuleNotificationSettingsFromNotificationSettings(to.NotificationSettings),
+	)
+	if err != nil {
+		return apimodels.RuleVersionDiff{}, err
+	}
+	return apimodels.RuleVersionDiff{
+		UID:                  to.UID,
+		FromVersion:          from.Version,
+		ToVersion:            to.Version,
+		Fields:               fields,
+		Queries:              queries,
+		Labels:               diffStringMaps(from.Labels, to.Labels),
+		Annotations:          diffStringMaps(from.Annotations, to.Annotations),
+		NotificationSettings: notificationSettings,
+	}, nil
+}
+
+// diffRuleQueries matches the queries and expressions by their reference ID and returns the ones that were added,
+// removed or changed.
+func diffRuleQueries(from, to []ngmodels.AlertQuery) ([]apimodels.RuleQueryDiff, error) {
+	result := make([]apimodels.RuleQueryDiff, 0)
+	previous := make(map[string]ngmodels.AlertQuery, len(from))
+	for _, q := range from {
+		previous[q.RefID] = q
+	}
+	current := make(map[string]struct{}, len(to))
+	for _, q := range to {
+		current[q.RefID] = struct{}{}
+		isExpr, _ := q.IsExpression()
+		p, ok := previous[q.RefID]
+		if !ok {
+			result = append(result, apimodels.RuleQueryDiff{RefID: q.RefID, Action: "added", IsExpression: isExpr})
+			continue
+		}
+
+		// The model is compared separately, field by field.
+		a, b := ApiAlertQueriesFromAlertQueries([]ngmodels.AlertQuery{p})[0], ApiAlertQueriesFromAlertQueries([]ngmodels.AlertQuery{q})[0]
+		a.Model, b.Model = nil, nil
+		changes, err := diffJSONFields("", a, b)
+		if err != nil {
+			return nil, err
+		}
+		modelChanges, err := diffJSONFields("model.", p.Model, q.Model)
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %w", q.RefID, err)
+		}
+		changes = append(changes, modelChanges...)
+		if len(changes) > 0 {
+			result = append(result, apimodels.RuleQueryDiff{RefID: q.RefID, Action: "changed", IsExpression: isExpr, Changes: changes})
+		}
+	}
+	for _, q := range from {
+		if _, ok := current[q.RefID]; !ok {
+			isExpr, _ := q.IsExpression()
+			result = append(result, apimodels.RuleQueryDiff{RefID: q.RefID, Action: "removed", IsExpression: isExpr})
+		}
+	}
+	return result, nil
+}
+
+func diffStringMaps(from, to map[string]string) []apimodels.RuleFieldDiff {
+	result := make([]apimodels.RuleFieldDiff, 0)
+	keys := slices.Collect(maps.Keys(from))
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		a, inFrom := from[k]
+		b, inTo := to[k]
+		if inFrom && inTo && a == b {
+			continue
+		}
+		d := apimodels.RuleFieldDiff{Path: k}
+		if inFrom {
+			d.From = a
+		}
+		if inTo {
+			d.To = b
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// diffJSONFields compares the top-level fields of the JSON representation of two values. A nil value has no fields.
+func diffJSONFields(prefix string, from, to any) ([]apimodels.RuleFieldDiff, error) {
+	toMap := func(v any) (map[string]any, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var result map[string]any
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	a, err := toMap(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toMap(to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]apimodels.RuleFieldDiff, 0)
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if reflect.DeepEqual(a[k], b[k]) {
+			continue
+		}
+		result = append(result, apimodels.RuleFieldDiff{Path: prefix + k, From: a[k], To: b[k]})
+	}
+	return result, nil
+}
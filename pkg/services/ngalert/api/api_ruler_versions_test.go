@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRouteGetRuleVersionsDiff(t *testing.T) {
+	orgID := rand.Int63()
+	f := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = f.UID
+	gen := models.RuleGen.With(models.RuleGen.WithGroupKey(groupKey), models.RuleGen.WithUniqueID())
+
+	rule := gen.With(
+		gen.WithVersion(3),
+		gen.WithTitle("current"),
+		gen.WithLabels(data.Labels{"team": "a", "severity": "critical"}),
+		gen.WithAnnotations(data.Labels{"summary": "current summary"}),
+	).GenerateRef()
+	previous := models.CopyRule(rule)
+	previous.Version = 2
+	previous.Title = "previous"
+	previous.Labels = map[string]string{"team": "b"}
+	previous.Annotations = map[string]string{"summary": "current summary", "runbook_url": "http://runbook"}
+	previous.Data[0].Model = json.RawMessage(`{"expr":"up == 0","intervalMs":1000}`)
+	rule.Data[0].Model = json.RawMessage(`{"expr":"up == 1","intervalMs":1000}`)
+
+	initStore := func(t *testing.T) *fakes.RuleStore {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		ruleStore.PutRule(context.Background(), rule)
+		ruleStore.History[rule.GUID] = []*models.AlertRuleVersion{{AlertRule: *previous}}
+		return ruleStore
+	}
+
+	t.Run("should return changes between the version and the current rule", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+		req.Req.Form.Set("from", "2")
+
+		response := createService(initStore(t), nil).RouteGetRuleVersionsDiff(req, rule.UID)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result apimodels.RuleVersionDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+
+		assert.Equal(t, rule.UID, result.UID)
+		assert.EqualValues(t, 2, result.FromVersion)
+		assert.EqualValues(t, 3, result.ToVersion)
+		assert.Equal(t, []apimodels.RuleFieldDiff{{Path: "title", From: "previous", To: "current"}}, result.Fields)
+		assert.Equal(t, []apimodels.RuleFieldDiff{
+			{Path: "severity", To: "critical"},
+			{Path: "team", From: "b", To: "a"},
+		}, result.Labels)
+		assert.Equal(t, []apimodels.RuleFieldDiff{{Path: "runbook_url", From: "http://runbook"}}, result.Annotations)
+		assert.Equal(t, []apimodels.RuleQueryDiff{{
+			RefID:   rule.Data[0].RefID,
+			Action:  "changed",
+			Changes: []apimodels.RuleFieldDiff{{Path: "model.expr", From: "up == 0", To: "up == 1"}},
+		}}, result.Queries)
+		assert.Empty(t, result.NotificationSettings)
+	})
+
+	t.Run("should return NotFound if version does not exist", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+		req.Req.Form.Set("from", "1")
+
+		response := createService(initStore(t), nil).RouteGetRuleVersionsDiff(req, rule.UID)
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return BadRequest if version to compare from is missing", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(initStore(t), nil).RouteGetRuleVersionsDiff(req, rule.UID)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestRoutePostRestoreRuleVersion(t *testing.T) {
+	orgID := rand.Int63()
+	f := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = f.UID
+	gen := models.RuleGen.With(models.RuleGen.WithGroupKey(groupKey), models.RuleGen.WithUniqueID(), models.RuleGen.WithNoNotificationSettings())
+
+	rule := gen.With(gen.WithVersion(3), gen.WithTitle("current")).GenerateRef()
+	previous := models.CopyRule(rule)
+	previous.Version = 2
+	previous.Title = "previous"
+	previous.Labels = map[string]string{"restored": "true"}
+
+	initStore := func(t *testing.T) *fakes.RuleStore {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		ruleStore.PutRule(context.Background(), rule)
+		ruleStore.History[rule.GUID] = []*models.AlertRuleVersion{{AlertRule: *previous}}
+		return ruleStore
+	}
+
+	t.Run("should update the rule with the previous version", func(t *testing.T) {
+		ruleStore := initStore(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(ruleStore, nil).RoutePostRestoreRuleVersion(req, rule.UID, strconv.FormatInt(previous.Version, 10))
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		raw := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			if u, ok := cmd.([]models.UpdateRule); ok {
+				return u, true
+			}
+			return nil, false
+		})
+		require.Len(t, raw, 1)
+		updates := raw[0].([]models.UpdateRule)
+		require.Len(t, updates, 1)
+
+		update := updates[0]
+		assert.Equal(t, rule.UID, update.New.UID)
+		assert.Equal(t, "previous", update.New.Title)
+		assert.Equal(t, previous.Labels, update.New.Labels)
+		assert.Equal(t, previous.Version, update.RestoredFrom)
+		assert.Equal(t, "Restored from version 2", update.Message)
+	})
+
+	t.Run("should return BadRequest if version is the current version", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(initStore(t), nil).RoutePostRestoreRuleVersion(req, rule.UID, strconv.FormatInt(rule.Version, 10))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return NotFound if version does not exist", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(initStore(t), nil).RoutePostRestoreRuleVersion(req, rule.UID, "1")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -39,6 +42,11 @@ type folderService interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*foldermodel.Folder, error)
 }
 
+type ruleVersionStore interface {
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
+	GetAlertRuleVersions(ctx context.Context, orgID int64, guid string) ([]*ngmodels.AlertRuleVersion, error)
+}
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	DatasourceCache datasources.CacheService
@@ -51,6 +59,7 @@ type TestingApiSrv struct {
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	ruleStore       ruleVersionStore
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -200,13 +209,22 @@ func (srv TestingApiSrv) RouteEvalQueries(c *contextmodel.ReqContext, cmd apimod
 		now = timeNow()
 	}
 
-	evalResults, err := evaluator.EvaluateRaw(c.Req.Context(), now)
+	ctx := c.Req.Context()
+	var explanation *expr.Explanation
+	if cmd.Explain {
+		ctx, explanation = expr.ContextWithExplanation(ctx)
+	}
+
+	evalResults, err := evaluator.EvaluateRaw(ctx, now)
 
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries and expressions")
 	}
 
 	addOptimizedQueryWarnings(evalResults, optimizations)
+	if explanation != nil {
+		evalResults.Responses[expr.ExplainRefID] = explanation.DataResponse()
+	}
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
 
@@ -251,13 +269,103 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		}
 	}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, folderTitle)
-	if err != nil {
-		if errors.Is(err, backtesting.ErrInvalidInputData) {
-			return ErrResp(400, err, "Failed to evaluate")
+	if cmd.CompareToVersion != nil {
+		baseline, err := srv.getAuthorizedRuleVersion(c, rule.UID, *cmd.CompareToVersion)
+		if err != nil {
+			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return errorToResponse(err)
 		}
-		return ErrResp(500, err, "Failed to evaluate")
+		compare := srv.backtesting.Compare
+		if cmd.ReplayHistory {
+			compare = srv.backtesting.CompareHistory
+		}
+		comparison, err := compare(c.Req.Context(), c.SignedInUser, rule, baseline, cmd.From, cmd.To, folderTitle)
+		if err != nil {
+			return backtestingErrorToResponse(err)
+		}
+		details := backtestDetails{
+			Notifications: comparison.Result.Notifications,
+			Baseline: &backtestBaseline{
+				Version:       baseline.Version,
+				States:        comparison.Baseline.States,
+				Notifications: comparison.Baseline.Notifications,
+				Differences:   comparison.Differences,
+			},
+		}
+		return response.JSONStreaming(http.StatusOK, details.setTo(comparison.Result.States))
+	}
+
+	test := srv.backtesting.Test
+	if cmd.ReplayHistory {
+		test = srv.backtesting.TestHistory
+	}
+	result, err := test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, folderTitle)
+	if err != nil {
+		return backtestingErrorToResponse(err)
+	}
+
+	details := backtestDetails{Notifications: result.Notifications}
+	return response.JSONStreaming(http.StatusOK, details.setTo(result.States))
+}
+
+// backtestDetails is set as the custom metadata of the frame returned by the backtesting API.
+type backtestDetails struct {
+	Notifications []backtesting.Notification `json:"notifications"`
+	Baseline      *backtestBaseline          `json:"baseline,omitempty"`
+}
+
+type backtestBaseline struct {
+	Version       int64                         `json:"version"`
+	States        *data.Frame                   `json:"states"`
+	Notifications []backtesting.Notification    `json:"notifications"`
+	Differences   []backtesting.StateDifference `json:"differences"`
+}
+
+func (d backtestDetails) setTo(frame *data.Frame) *data.Frame {
+	if frame.Meta == nil {
+		frame.SetMeta(&data.FrameMeta{})
 	}
+	frame.Meta.Custom = d
+	return frame
+}
+
+func backtestingErrorToResponse(err error) response.Response {
+	if errors.Is(err, backtesting.ErrInvalidInputData) {
+		return ErrResp(400, err, "Failed to evaluate")
+	}
+	if errors.Is(err, backtesting.ErrHistoryNotAvailable) {
+		return ErrResp(http.StatusNotImplemented, err, "Failed to replay the state history")
+	}
+	return ErrResp(500, err, "Failed to evaluate")
+}
 
-	return response.JSONStreaming(http.StatusOK, result)
+// getAuthorizedRuleVersion returns the version of the rule if the user can access the rule and query its data sources.
+func (srv TestingApiSrv) getAuthorizedRuleVersion(c *contextmodel.ReqContext, ruleUID string, version int64) (*ngmodels.AlertRule, error) {
+	ctx := c.Req.Context()
+	rule, err := srv.ruleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{UID: ruleUID, OrgID: c.GetOrgID()})
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.authz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+		return nil, err
+	}
+
+	result := rule
+	if rule.Version != version {
+		versions, err := srv.ruleStore.GetAlertRuleVersions(ctx, rule.OrgID, rule.GUID)
+		if err != nil {
+			return nil, err
+		}
+		idx := slices.IndexFunc(versions, func(v *ngmodels.AlertRuleVersion) bool { return v.Version == version })
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: version %d of rule %s", ngmodels.ErrAlertRuleNotFound, version, ruleUID)
+		}
+		result = &versions[idx].AlertRule
+	}
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(ctx, c.SignedInUser, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
@@ -41,14 +41,40 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(folder.ActionFoldersRead, folder.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))),
 		)
 	case http.MethodGet + "/api/ruler/grafana/api/v1/rules",
-		http.MethodGet + "/api/ruler/grafana/api/v1/export/rules":
+		http.MethodGet + "/api/ruler/grafana/api/v1/export/rules",
+		http.MethodGet + "/api/ruler/grafana/api/v1/export/prometheus",
+		http.MethodGet + "/api/ruler/grafana/api/v1/dependencies":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 	case http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}",
-		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions":
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/backfills":
 		eval = ac.EvalAll(
 			ac.EvalPermission(ac.ActionAlertingRuleRead),
 			ac.EvalPermission(folder.ActionFoldersRead),
 		)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore":
+		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(folder.ActionFoldersRead),
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+		)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/backfills",
+		http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/backfills/{BackfillUID}/cancel",
+		http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/backfills/{BackfillUID}/resume":
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(folder.ActionFoldersRead),
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+		)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/acknowledge",
+		http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/unacknowledge":
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(folder.ActionFoldersRead),
+			ac.EvalPermission(ac.ActionAlertingInstanceUpdate),
+		)
 	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/export":
 		scope := folder.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
 		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
@@ -293,6 +319,17 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingReceiversRead),
 			ac.EvalPermission(ac.ActionAlertingReceiversReadSecrets),
 		)
+	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/receivers/{Name}/digest":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingNotificationsRead),
+			ac.EvalPermission(ac.ActionAlertingReceiversRead), // fine-grained permission is checked later
+		)
+	case http.MethodPut + "/api/alertmanager/grafana/config/api/v1/receivers/{Name}/digest",
+		http.MethodDelete + "/api/alertmanager/grafana/config/api/v1/receivers/{Name}/digest":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingNotificationsWrite),
+			ac.EvalPermission(ac.ActionAlertingReceiversUpdate), // fine-grained permission is checked later
+		)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":
 		eval = ac.EvalAny(
 			accesscontrol.TestReceiversPreconditionEval,
@@ -328,7 +365,19 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodDelete + "/api/v1/ngalert/admin_config",
 		http.MethodGet + "/api/v1/ngalert/admin_config",
 		http.MethodPost + "/api/v1/ngalert/admin_config",
-		http.MethodGet + "/api/v1/ngalert/alertmanagers":
+		http.MethodGet + "/api/v1/ngalert/alertmanagers",
+		http.MethodDelete + "/api/v1/ngalert/maintenance_windows/{UID}",
+		http.MethodGet + "/api/v1/ngalert/maintenance_windows",
+		http.MethodGet + "/api/v1/ngalert/maintenance_windows/{UID}",
+		http.MethodPost + "/api/v1/ngalert/maintenance_windows",
+		http.MethodPut + "/api/v1/ngalert/maintenance_windows/{UID}",
+		http.MethodGet + "/api/v1/ngalert/rule_ownership",
+		http.MethodDelete + "/api/v1/ngalert/rule_policy",
+		http.MethodGet + "/api/v1/ngalert/rule_policy",
+		http.MethodPost + "/api/v1/ngalert/rule_policy",
+		http.MethodPost + "/api/v1/ngalert/rule_policy/test",
+		http.MethodGet + "/api/v1/ngalert/state/export",
+		http.MethodPost + "/api/v1/ngalert/state/import":
 		return middleware.ReqOrgAdmin
 
 	// Grafana-only Provisioning Export Paths for everything except contact points.
@@ -520,6 +569,14 @@ func (api *API) authorize(method, path string) web.Handler {
 				ac.EvalPermission(ac.ActionAlertingProvisioningSetStatus),
 			),
 		)
+	case http.MethodPost + "/api/v1/provisioning/import":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite), // organization scope
+			ac.EvalAll( // more granular permissions are enforced by the rule and contact point services
+				ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+				ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningWrite),
+			),
+		)
 	case http.MethodPost + "/api/v1/provisioning/contact-points":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),              // organization scope,
@@ -3,6 +3,8 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -290,6 +292,132 @@ func AlertQueryExportFromAlertQuery(query models.AlertQuery) (definitions.AlertQ
 	}, nil
 }
 
+// AlertRuleGroupFromAlertRuleGroupExport creates a models.AlertRuleGroup from definitions.AlertRuleGroupExport, which can be
+// decoded from any of the export formats. The folder is taken from the FolderUID field, so the caller must resolve the folder
+// of groups decoded from YAML or JSON.
+func AlertRuleGroupFromAlertRuleGroupExport(d definitions.AlertRuleGroupExport) (models.AlertRuleGroup, error) {
+	interval := d.IntervalSeconds
+	if interval == 0 {
+		interval = int64(time.Duration(d.Interval).Seconds())
+	}
+	rules := make([]models.AlertRule, 0, len(d.Rules))
+	for i := range d.Rules {
+		rule, err := AlertRuleFromAlertRuleExport(d.Rules[i])
+		if err != nil {
+			return models.AlertRuleGroup{}, fmt.Errorf("rule '%s' failed to parse: %w", d.Rules[i].Title, err)
+		}
+		rule.OrgID = d.OrgID
+		rule.NamespaceUID = d.FolderUID
+		rule.RuleGroup = d.Name
+		rule.IntervalSeconds = interval
+		rules = append(rules, rule)
+	}
+	return models.AlertRuleGroup{
+		Title:     d.Name,
+		FolderUID: d.FolderUID,
+		Interval:  interval,
+		Rules:     rules,
+	}, nil
+}
+
+// AlertRuleFromAlertRuleExport creates a models.AlertRule from definitions.AlertRuleExport.
+func AlertRuleFromAlertRuleExport(r definitions.AlertRuleExport) (models.AlertRule, error) {
+	rule := models.AlertRule{
+		UID:                         r.UID,
+		Title:                       r.Title,
+		DashboardUID:                r.DashboardUID,
+		PanelID:                     r.PanelID,
+		IsPaused:                    r.IsPaused,
+		Record:                      ModelRecordFromAlertRuleRecordExport(r.Record),
+		MissingSeriesEvalsToResolve: r.MissingSeriesEvalsToResolve,
+		NoDataState:                 models.NoData,
+		ExecErrState:                models.AlertingErrState,
+	}
+	if r.Condition != nil {
+		rule.Condition = *r.Condition
+	}
+	if r.Annotations != nil {
+		rule.Annotations = *r.Annotations
+	}
+	if r.Labels != nil {
+		rule.Labels = *r.Labels
+	}
+
+	var err error
+	if r.NoDataState != nil {
+		if rule.NoDataState, err = models.NoDataStateFromString(string(*r.NoDataState)); err != nil {
+			return models.AlertRule{}, err
+		}
+	}
+	if r.ExecErrState != nil {
+		if rule.ExecErrState, err = models.ErrStateFromString(string(*r.ExecErrState)); err != nil {
+			return models.AlertRule{}, err
+		}
+	}
+
+	forDuration, keepFiringFor := r.For, r.KeepFiringFor
+	if r.ForString != nil {
+		if forDuration, err = model.ParseDuration(*r.ForString); err != nil {
+			return models.AlertRule{}, fmt.Errorf("invalid pending period: %w", err)
+		}
+	}
+	if r.KeepFiringForString != nil {
+		if keepFiringFor, err = model.ParseDuration(*r.KeepFiringForString); err != nil {
+			return models.AlertRule{}, fmt.Errorf("invalid keep firing for period: %w", err)
+		}
+	}
+	rule.For = time.Duration(forDuration)
+	rule.KeepFiringFor = time.Duration(keepFiringFor)
+
+	if rule.NotificationSettings, err = NotificationSettingsFromAlertRuleNotificationSettingsExport(r.NotificationSettings); err != nil {
+		return models.AlertRule{}, err
+	}
+
+	rule.Data = make([]models.AlertQuery, 0, len(r.Data))
+	for i := range r.Data {
+		query, err := AlertQueryFromAlertQueryExport(r.Data[i])
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("query %s: %w", r.Data[i].RefID, err)
+		}
+		rule.Data = append(rule.Data, query)
+	}
+
+	if rule.Type() == models.RuleTypeRecording {
+		models.ClearRecordingRuleIgnoredFields(&rule)
+	}
+	return rule, nil
+}
+
+// AlertQueryFromAlertQueryExport creates a models.AlertQuery from definitions.AlertQueryExport.
+// The model is taken from ModelString if it is set, as it is in HCL, and from Model otherwise.
+func AlertQueryFromAlertQueryExport(q definitions.AlertQueryExport) (models.AlertQuery, error) {
+	var mdl json.RawMessage
+	if q.ModelString != "" {
+		if !json.Valid([]byte(q.ModelString)) {
+			return models.AlertQuery{}, errors.New("model is not valid JSON")
+		}
+		mdl = json.RawMessage(q.ModelString)
+	} else {
+		var err error
+		if mdl, err = json.Marshal(q.Model); err != nil {
+			return models.AlertQuery{}, err
+		}
+	}
+	query := models.AlertQuery{
+		RefID: q.RefID,
+		RelativeTimeRange: models.RelativeTimeRange{
+			From: models.Duration(time.Duration(q.RelativeTimeRange.FromSeconds) * time.Second),
+			To:   models.Duration(time.Duration(q.RelativeTimeRange.ToSeconds) * time.Second),
+		},
+		DatasourceUID: q.DatasourceUID,
+		Model:         mdl,
+	}
+	if q.QueryType != nil {
+		query.QueryType = *q.QueryType
+	}
+	return query, nil
+}
+
 // AlertingFileExportFromEmbeddedContactPoints creates a definitions.AlertingFileExport DTO from []definitions.EmbeddedContactPoint.
 func AlertingFileExportFromEmbeddedContactPoints(orgID int64, ecps []definitions.EmbeddedContactPoint) (definitions.AlertingFileExport, error) {
 	f := definitions.AlertingFileExport{APIVersion: 1}
@@ -545,6 +673,46 @@ func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.Alert
 	return &res
 }
 
+// NotificationSettingsFromAlertRuleNotificationSettingsExport converts definitions.AlertRuleNotificationSettingsExport to models.NotificationSettings
+func NotificationSettingsFromAlertRuleNotificationSettingsExport(ns *definitions.AlertRuleNotificationSettingsExport) (*models.NotificationSettings, error) {
+	if ns == nil {
+		return nil, nil
+	}
+
+	parseIfNotNil := func(name string, s *string) (*model.Duration, error) {
+		if s == nil {
+			return nil, nil
+		}
+		d, err := model.ParseDuration(*s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of notification settings: %w", name, err)
+		}
+		return &d, nil
+	}
+
+	cpr := models.ContactPointRouting{Receiver: ns.Receiver}
+	var err error
+	if cpr.GroupWait, err = parseIfNotNil("group_wait", ns.GroupWait); err != nil {
+		return nil, err
+	}
+	if cpr.GroupInterval, err = parseIfNotNil("group_interval", ns.GroupInterval); err != nil {
+		return nil, err
+	}
+	if cpr.RepeatInterval, err = parseIfNotNil("repeat_interval", ns.RepeatInterval); err != nil {
+		return nil, err
+	}
+	if ns.GroupBy != nil {
+		cpr.GroupBy = *ns.GroupBy
+	}
+	if ns.MuteTimeIntervals != nil {
+		cpr.MuteTimeIntervals = *ns.MuteTimeIntervals
+	}
+	if ns.ActiveTimeIntervals != nil {
+		cpr.ActiveTimeIntervals = *ns.ActiveTimeIntervals
+	}
+	return &models.NotificationSettings{ContactPointRouting: &cpr}, nil
+}
+
 func pointerOmitEmpty(s string) *string {
 	if s == "" {
 		return nil
@@ -563,6 +731,20 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	}
 }
 
+func ModelRecordFromAlertRuleRecordExport(r *definitions.AlertRuleRecordExport) *models.Record {
+	if r == nil {
+		return nil
+	}
+	record := &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+	if r.TargetDatasourceUID != nil {
+		record.TargetDatasourceUID = *r.TargetDatasourceUID
+	}
+	return record
+}
+
 func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 	if r == nil {
 		return nil
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -195,6 +196,92 @@ func TestAlertRuleExportFromAlertRule(t *testing.T) {
 	}
 }
 
+func TestAlertRuleFromAlertRuleExport(t *testing.T) {
+	rule := models.AlertRule{
+		UID:       "rule-uid",
+		Title:     "rule",
+		Condition: "A",
+		Data: []models.AlertQuery{{
+			RefID:             "A",
+			QueryType:         "range",
+			RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(10 * time.Minute), To: models.Duration(time.Minute)},
+			DatasourceUID:     "datasource-uid",
+			Model:             json.RawMessage(`{"expr":"up","refId":"A"}`),
+		}},
+		DashboardUID:  new("dashboard-uid"),
+		PanelID:       new(int64(1)),
+		NoDataState:   models.OK,
+		ExecErrState:  models.ErrorErrState,
+		For:           2 * time.Minute,
+		KeepFiringFor: 5 * time.Minute,
+		Annotations:   map[string]string{"summary": "test"},
+		Labels:        map[string]string{"team": "platform"},
+		NotificationSettings: &models.NotificationSettings{ContactPointRouting: &models.ContactPointRouting{
+			Receiver:          "receiver",
+			GroupBy:           []string{"alertname"},
+			GroupWait:         new(prommodel.Duration(30 * time.Second)),
+			MuteTimeIntervals: []string{"weekends"},
+		}},
+		MissingSeriesEvalsToResolve: new(int64(3)),
+	}
+	exported, err := AlertRuleExportFromAlertRule(rule)
+	require.NoError(t, err)
+
+	t.Run("converts rule exported to file formats", func(t *testing.T) {
+		fileExport := exported
+		fileExport.ForString = nil
+		fileExport.KeepFiringForString = nil
+		fileExport.Data = []definitions.AlertQueryExport{exported.Data[0]}
+		fileExport.Data[0].ModelString = ""
+
+		imported, err := AlertRuleFromAlertRuleExport(fileExport)
+		require.NoError(t, err)
+		require.Equal(t, rule, imported)
+	})
+
+	t.Run("converts rule exported to HCL", func(t *testing.T) {
+		hclExport := exported
+		hclExport.UID = ""
+		hclExport.DashboardUID = nil
+		hclExport.PanelID = nil
+		hclExport.For = 0
+		hclExport.KeepFiringFor = 0
+		hclExport.Data = []definitions.AlertQueryExport{exported.Data[0]}
+		hclExport.Data[0].Model = nil
+
+		imported, err := AlertRuleFromAlertRuleExport(hclExport)
+		require.NoError(t, err)
+		expected := rule
+		expected.UID = ""
+		expected.DashboardUID = nil
+		expected.PanelID = nil
+		require.Equal(t, expected, imported)
+	})
+
+	t.Run("clears fields ignored by recording rules", func(t *testing.T) {
+		recording := exported
+		recording.Record = &definitions.AlertRuleRecordExport{Metric: "metric", From: "A"}
+
+		imported, err := AlertRuleFromAlertRuleExport(recording)
+		require.NoError(t, err)
+		require.Equal(t, &models.Record{Metric: "metric", From: "A"}, imported.Record)
+		require.Empty(t, imported.Condition)
+		require.Nil(t, imported.NotificationSettings)
+	})
+
+	t.Run("fails on invalid durations and models", func(t *testing.T) {
+		invalid := exported
+		invalid.ForString = new("soon")
+		_, err := AlertRuleFromAlertRuleExport(invalid)
+		require.ErrorContains(t, err, "invalid pending period")
+
+		invalid = exported
+		invalid.Data = []definitions.AlertQueryExport{{RefID: "A", ModelString: "{"}}
+		_, err = AlertRuleFromAlertRuleExport(invalid)
+		require.ErrorContains(t, err, "query A: model is not valid JSON")
+	})
+}
+
 func TestAlertQueryExportFromAlertQuery(t *testing.T) {
 	query := models.RuleGen.GenerateQuery()
 
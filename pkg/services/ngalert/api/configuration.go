@@ -36,3 +36,51 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
+
+func (f *ConfigurationApiHandler) handleRouteGetRulePolicy(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetRulePolicy(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostRulePolicy(c *contextmodel.ReqContext, body apimodels.RulePolicy) response.Response {
+	return f.grafana.RoutePostRulePolicy(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRouteDeleteRulePolicy(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteDeleteRulePolicy(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteTestRulePolicy(c *contextmodel.ReqContext, body apimodels.RulePolicy) response.Response {
+	return f.grafana.RouteTestRulePolicy(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetMaintenanceWindows(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetMaintenanceWindows(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetMaintenanceWindow(c *contextmodel.ReqContext, uid string) response.Response {
+	return f.grafana.RouteGetMaintenanceWindow(c, uid)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostMaintenanceWindow(c *contextmodel.ReqContext, body apimodels.MaintenanceWindow) response.Response {
+	return f.grafana.RoutePostMaintenanceWindow(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePutMaintenanceWindow(c *contextmodel.ReqContext, body apimodels.MaintenanceWindow, uid string) response.Response {
+	return f.grafana.RoutePutMaintenanceWindow(c, body, uid)
+}
+
+func (f *ConfigurationApiHandler) handleRouteDeleteMaintenanceWindow(c *contextmodel.ReqContext, uid string) response.Response {
+	return f.grafana.RouteDeleteMaintenanceWindow(c, uid)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetRuleOwnership(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetRuleOwnership(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetAlertStateExport(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetAlertStateExport(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostAlertStateImport(c *contextmodel.ReqContext, body apimodels.PostableAlertStateImport) response.Response {
+	return f.grafana.RoutePostAlertStateImport(c, body)
+}
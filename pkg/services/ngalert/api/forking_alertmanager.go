@@ -173,6 +173,18 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceivers(ctx *contextmode
 	return f.GrafanaSvc.RouteGetReceivers(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceiverDigest(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.GrafanaSvc.RouteGetReceiverDigest(ctx, name)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePutGrafanaReceiverDigest(ctx *contextmodel.ReqContext, body apimodels.ReceiverDigest, name string) response.Response {
+	return f.GrafanaSvc.RoutePutReceiverDigest(ctx, body, name)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteDeleteGrafanaReceiverDigest(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.GrafanaSvc.RouteDeleteReceiverDigest(ctx, name)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx)
 }
@@ -117,6 +117,10 @@ func (f *RulerApiHandler) handleRouteGetRulesForExport(ctx *contextmodel.ReqCont
 	return f.GrafanaRuler.ExportRules(ctx)
 }
 
+func (f *RulerApiHandler) handleRouteGetRulesForPrometheusExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaRuler.ExportRulesToPrometheus(ctx)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
@@ -125,10 +129,46 @@ func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler,
 	return f.LotexRuler, nil
 }
 
+func (f *RulerApiHandler) handleRouteGetRuleDependencies(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetRuleDependencies(ctx)
+}
+
 func (f *RulerApiHandler) handleRouteGetRuleVersionsByUID(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
 	return f.GrafanaRuler.RouteGetRuleVersionsByUID(ctx, ruleUID)
 }
 
+func (f *RulerApiHandler) handleRouteGetRuleVersionsDiff(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsDiff(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostRestoreRuleVersion(ctx *contextmodel.ReqContext, ruleUID string, version string) response.Response {
+	return f.GrafanaRuler.RoutePostRestoreRuleVersion(ctx, ruleUID, version)
+}
+
+func (f *RulerApiHandler) handleRoutePostAcknowledgeAlert(ctx *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string) response.Response {
+	return f.GrafanaRuler.RoutePostAcknowledgeAlert(ctx, body, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostUnacknowledgeAlert(ctx *contextmodel.ReqContext, body apimodels.PostableAlertUnacknowledgement, ruleUID string) response.Response {
+	return f.GrafanaRuler.RoutePostUnacknowledgeAlert(ctx, body, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteGetRecordingRuleBackfills(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRecordingRuleBackfills(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostRecordingRuleBackfill(ctx *contextmodel.ReqContext, body apimodels.PostableRecordingRuleBackfill, ruleUID string) response.Response {
+	return f.GrafanaRuler.RoutePostRecordingRuleBackfill(ctx, body, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostCancelRecordingRuleBackfill(ctx *contextmodel.ReqContext, ruleUID string, backfillUID string) response.Response {
+	return f.GrafanaRuler.RoutePostCancelRecordingRuleBackfill(ctx, ruleUID, backfillUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostResumeRecordingRuleBackfill(ctx *contextmodel.ReqContext, ruleUID string, backfillUID string) response.Response {
+	return f.GrafanaRuler.RoutePostResumeRecordingRuleBackfill(ctx, ruleUID, backfillUID)
+}
+
 func (f *RulerApiHandler) handleRouteDeleteRuleFromTrashByGUID(ctx *contextmodel.ReqContext, ruleGUID string) response.Response {
 	return f.GrafanaRuler.RouteDeleteAlertRuleFromTrashByGUID(ctx, ruleGUID)
 }
@@ -20,25 +20,78 @@ import (
 )
 
 type ConfigurationApi interface {
+	RouteDeleteMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RouteDeleteNGalertConfig(*contextmodel.ReqContext) response.Response
+	RouteDeleteRulePolicy(*contextmodel.ReqContext) response.Response
+	RouteGetAlertStateExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertmanagers(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindow(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindows(*contextmodel.ReqContext) response.Response
 	RouteGetNGalertConfig(*contextmodel.ReqContext) response.Response
+	RouteGetRuleOwnership(*contextmodel.ReqContext) response.Response
+	RouteGetRulePolicy(*contextmodel.ReqContext) response.Response
 	RouteGetStatus(*contextmodel.ReqContext) response.Response
+	RoutePostAlertStateImport(*contextmodel.ReqContext) response.Response
+	RoutePostMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RoutePostNGalertConfig(*contextmodel.ReqContext) response.Response
+	RoutePostRulePolicy(*contextmodel.ReqContext) response.Response
+	RoutePutMaintenanceWindow(*contextmodel.ReqContext) response.Response
+	RouteTestRulePolicy(*contextmodel.ReqContext) response.Response
 }
 
+func (f *ConfigurationApiHandler) RouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteMaintenanceWindow(ctx, uIDParam)
+}
 func (f *ConfigurationApiHandler) RouteDeleteNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteDeleteNGalertConfig(ctx)
 }
+func (f *ConfigurationApiHandler) RouteDeleteRulePolicy(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteDeleteRulePolicy(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetAlertStateExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertStateExport(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetAlertmanagers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertmanagers(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetMaintenanceWindow(ctx, uIDParam)
+}
+func (f *ConfigurationApiHandler) RouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetMaintenanceWindows(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetNGalertConfig(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetRuleOwnership(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRuleOwnership(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetRulePolicy(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRulePolicy(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetStatus(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStatus(ctx)
 }
+func (f *ConfigurationApiHandler) RoutePostAlertStateImport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableAlertStateImport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertStateImport(ctx, conf)
+}
+func (f *ConfigurationApiHandler) RoutePostMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostMaintenanceWindow(ctx, conf)
+}
 func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.PostableNGalertConfig{}
@@ -47,9 +100,47 @@ func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqCo
 	}
 	return f.handleRoutePostNGalertConfig(ctx, conf)
 }
+func (f *ConfigurationApiHandler) RoutePostRulePolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RulePolicy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostRulePolicy(ctx, conf)
+}
+func (f *ConfigurationApiHandler) RoutePutMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutMaintenanceWindow(ctx, conf, uIDParam)
+}
+func (f *ConfigurationApiHandler) RouteTestRulePolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RulePolicy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteTestRulePolicy(ctx, conf)
+}
 
 func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/maintenance_windows/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/ngalert/maintenance_windows/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/maintenance_windows/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteMaintenanceWindow),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -62,6 +153,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/rule_policy"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/ngalert/rule_policy"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/rule_policy",
+				api.Hooks.Wrap(srv.RouteDeleteRulePolicy),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/state/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/state/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/state/export",
+				api.Hooks.Wrap(srv.RouteGetAlertStateExport),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/alertmanagers"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -74,6 +189,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/maintenance_windows/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/maintenance_windows/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/maintenance_windows/{UID}",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindow),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/maintenance_windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/maintenance_windows"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/maintenance_windows",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindows),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -86,6 +225,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rule_ownership"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/rule_ownership"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rule_ownership",
+				api.Hooks.Wrap(srv.RouteGetRuleOwnership),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rule_policy"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/rule_policy"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rule_policy",
+				api.Hooks.Wrap(srv.RouteGetRulePolicy),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -98,6 +261,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/state/import"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/state/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/state/import",
+				api.Hooks.Wrap(srv.RoutePostAlertStateImport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/maintenance_windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/maintenance_windows"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/maintenance_windows",
+				api.Hooks.Wrap(srv.RoutePostMaintenanceWindow),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -110,5 +297,41 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rule_policy"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/rule_policy"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rule_policy",
+				api.Hooks.Wrap(srv.RoutePostRulePolicy),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rule_policy/test"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/rule_policy/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rule_policy/test",
+				api.Hooks.Wrap(srv.RouteTestRulePolicy),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/maintenance_windows/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/ngalert/maintenance_windows/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/maintenance_windows/{UID}",
+				api.Hooks.Wrap(srv.RoutePutMaintenanceWindow),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -2,8 +2,12 @@ package hcl
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
+	hcl2 "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
@@ -28,3 +32,125 @@ func Encode(resources ...Resource) (data []byte, err error) {
 	}
 	return f.Bytes(), nil
 }
+
+// Decode parses resources produced by Encode. The body of every resource is decoded into the pointer to a struct
+// returned by newBody for the type of the resource.
+//
+// Unlike gohcl, all attributes are optional, because Encode omits the attributes whose values are nil pointers.
+func Decode(data []byte, filename string, newBody func(resourceType string) (interface{}, error)) ([]Resource, error) {
+	file, diags := hclsyntax.ParseConfig(data, filename, hcl2.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("%s: unexpected body type %T", filename, file.Body)
+	}
+	if len(body.Attributes) > 0 {
+		return nil, fmt.Errorf("%s: unexpected arguments, only resource blocks are supported", filename)
+	}
+
+	resources := make([]Resource, 0, len(body.Blocks))
+	for _, block := range body.Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			return nil, fmt.Errorf("%s: unexpected block %q, only resource blocks with type and name labels are supported", block.DefRange(), block.Type)
+		}
+		b, err := newBody(block.Labels[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", block.DefRange(), err)
+		}
+		v := reflect.ValueOf(b)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("body of resource type %s must be a pointer to a struct, got %T", block.Labels[0], b)
+		}
+		if err := decodeBody(block.Body, v.Elem()); err != nil {
+			return nil, err
+		}
+		resources = append(resources, Resource{
+			Type: block.Labels[0],
+			Name: block.Labels[1],
+			Body: b,
+		})
+	}
+	return resources, nil
+}
+
+type field struct {
+	index int
+	block bool
+}
+
+func fieldsByName(ty reflect.Type) map[string]field {
+	fields := make(map[string]field, ty.NumField())
+	for i := 0; i < ty.NumField(); i++ {
+		tag, ok := ty.Field(i).Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+		name, kind, _ := strings.Cut(tag, ",")
+		switch kind {
+		case "", "attr", "optional":
+			fields[name] = field{index: i}
+		case "block":
+			fields[name] = field{index: i, block: true}
+		}
+	}
+	return fields
+}
+
+func decodeBody(body *hclsyntax.Body, v reflect.Value) error {
+	fields := fieldsByName(v.Type())
+	for name, attr := range body.Attributes {
+		f, ok := fields[name]
+		if !ok || f.block {
+			return fmt.Errorf("%s: unsupported argument %q", attr.NameRange, name)
+		}
+		if diags := gohcl.DecodeExpression(attr.Expr, nil, v.Field(f.index).Addr().Interface()); diags.HasErrors() {
+			return diags
+		}
+	}
+
+	for _, block := range body.Blocks {
+		f, ok := fields[block.Type]
+		if !ok || !f.block {
+			return fmt.Errorf("%s: unsupported block %q", block.DefRange(), block.Type)
+		}
+		target := v.Field(f.index)
+		switch target.Kind() {
+		case reflect.Slice:
+			elem, err := decodeBlock(block, target.Type().Elem())
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.Append(target, elem))
+		case reflect.Ptr, reflect.Struct:
+			if !target.IsZero() {
+				return fmt.Errorf("%s: duplicate block %q", block.DefRange(), block.Type)
+			}
+			elem, err := decodeBlock(block, target.Type())
+			if err != nil {
+				return err
+			}
+			target.Set(elem)
+		default:
+			return fmt.Errorf("%s: unsupported field type %s of block %q", block.DefRange(), target.Type(), block.Type)
+		}
+	}
+	return nil
+}
+
+// decodeBlock decodes the body of the block into a new value of the given type, which is a struct or a pointer to a struct.
+func decodeBlock(block *hclsyntax.Block, ty reflect.Type) (reflect.Value, error) {
+	if ty.Kind() == reflect.Ptr {
+		elem := reflect.New(ty.Elem())
+		if err := decodeBody(block.Body, elem.Elem()); err != nil {
+			return reflect.Value{}, err
+		}
+		return elem, nil
+	}
+	elem := reflect.New(ty).Elem()
+	if err := decodeBody(block.Body, elem); err != nil {
+		return reflect.Value{}, err
+	}
+	return elem, nil
+}
@@ -1,6 +1,7 @@
 package hcl
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -73,3 +74,59 @@ func TestEncode(t *testing.T) {
 }
 `, string(encoded))
 }
+
+func TestDecode(t *testing.T) {
+	type data struct {
+		Name      string             `hcl:"name"`
+		Number    float64            `hcl:"number"`
+		NumberRef *float64           `hcl:"numberRef"`
+		BoolRef   *bool              `hcl:"bulRef"`
+		Labels    *map[string]string `hcl:"labels"`
+		Ignored   string
+		Blocks    []data `hcl:"blocks,block"`
+		SubData   *data  `hcl:"sub,block"`
+	}
+	newBody := func(resourceType string) (interface{}, error) {
+		if resourceType != "grafana_test" {
+			return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+		}
+		return &data{}, nil
+	}
+
+	t.Run("decodes resources produced by Encode", func(t *testing.T) {
+		expected := &data{
+			Name:      "test",
+			Number:    123,
+			NumberRef: func(f float64) *float64 { return &f }(1333),
+			Labels:    &map[string]string{"team": "${team}"},
+			Blocks: []data{
+				{Name: "el-0", Number: 1},
+				{Name: "el-1", Number: 2},
+			},
+			SubData: &data{Name: "sub-data"},
+		}
+		encoded, err := Encode(Resource{Type: "grafana_test", Name: "test-01", Body: expected})
+		require.NoError(t, err)
+
+		resources, err := Decode(encoded, "test.tf", newBody)
+		require.NoError(t, err)
+		require.Equal(t, []Resource{{Type: "grafana_test", Name: "test-01", Body: expected}}, resources)
+	})
+
+	t.Run("fails on unknown resources and arguments", func(t *testing.T) {
+		_, err := Decode([]byte(`resource "grafana_other" "test" {}`), "test.tf", newBody)
+		require.ErrorContains(t, err, "unsupported resource type grafana_other")
+
+		_, err = Decode([]byte(`resource "grafana_test" "test" { unknown = 1 }`), "test.tf", newBody)
+		require.ErrorContains(t, err, `unsupported argument "unknown"`)
+
+		_, err = Decode([]byte(`resource "grafana_test" "test" {
+  sub {}
+  sub {}
+}`), "test.tf", newBody)
+		require.ErrorContains(t, err, `duplicate block "sub"`)
+
+		_, err = Decode([]byte(`name = "test"`), "test.tf", newBody)
+		require.ErrorContains(t, err, "only resource blocks are supported")
+	})
+}
@@ -161,7 +161,7 @@ func PrepareAlertStatuses(ctx context.Context, manager state.AlertInstanceManage
 
 		alertResponse.Data.Alerts = append(alertResponse.Data.Alerts, &apimodels.Alert{
 			Labels:      apimodels.LabelsFromMap(alertState.GetLabels(labelOptions...)),
-			Annotations: apimodels.LabelsFromMap(alertState.Annotations),
+			Annotations: apimodels.LabelsFromMap(alertState.GetAnnotations()),
 
 			// TODO: or should we make this two fields? Using one field lets the
 			// frontend use the same logic for parsing text on annotations and this.
@@ -480,7 +480,7 @@ func computeAlertStates(states []*state.State, source *ngmodels.AlertRule, toMut
 
 			toMutate.Alerts = append(toMutate.Alerts, apimodels.Alert{
 				Labels:      apimodels.LabelsFromMap(alertState.GetLabels(labelOptions...)),
-				Annotations: apimodels.LabelsFromMap(alertState.Annotations),
+				Annotations: apimodels.LabelsFromMap(alertState.GetAnnotations()),
 
 				// TODO: or should we make this two fields? Using one field lets the
 				// frontend use the same logic for parsing text on annotations and this.
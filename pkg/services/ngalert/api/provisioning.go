@@ -1,6 +1,9 @@
 package api
 
 import (
+	"io"
+	"net/http"
+
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -157,6 +160,14 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *contextmode
 	return f.svc.RouteGetAlertRulesExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostAlertingFileImport(ctx *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(ctx.Req.Body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to read the request body")
+	}
+	return f.svc.RoutePostAlertingFileImport(ctx, body)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
 	return deprecatedRuleProvisioningResponse(f.svc.RoutePostAlertRule(ctx, ar), replacementAlertRules)
 }
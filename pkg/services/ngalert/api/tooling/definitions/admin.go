@@ -1,7 +1,11 @@
 package definitions
 
 import (
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 )
 
 // swagger:route GET /v1/ngalert configuration RouteGetStatus
@@ -58,6 +62,161 @@ import (
 //       200: Ack
 //       500: Failure
 
+// swagger:route GET /v1/ngalert/rule_ownership configuration RouteGetRuleOwnership
+//
+// Get the instance of the HA cluster that evaluates each alert rule of the user's organization.
+//
+// The owners are only set when the evaluation of alert rules is sharded between the instances of the cluster.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleOwnership
+//       403: ForbiddenError
+
+// swagger:route GET /v1/ngalert/state/export configuration RouteGetAlertStateExport
+//
+// Export the alert instances, silences and notification log of the user's organization.
+//
+// The archive can be imported into another Grafana installation so that alerts keep their state, for example during a migration.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertStateArchive
+//       403: ForbiddenError
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/state/import configuration RoutePostAlertStateImport
+//
+// Import an archive of alert instances, silences and notification log into the user's organization.
+//
+// The state of every alert rule in the archive replaces the state of the matching alert rule in the organization. Silences and the notification log are merged with the existing ones.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertStateImportResult
+//       400: ValidationError
+//       403: ForbiddenError
+//       500: Failure
+
+// swagger:route GET /v1/ngalert/rule_policy configuration RouteGetRulePolicy
+//
+//  Get the alert rule policy of the user's organization, returns 404 if no policy is present.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//		 200: RulePolicy
+//		 404: Failure
+//		 500: Failure
+
+// swagger:route POST /v1/ngalert/rule_policy configuration RoutePostRulePolicy
+//
+// Creates or replaces the alert rule policy of the user's organization.
+//
+// Alert rules that are created or updated through the ruler API, the provisioning API or file provisioning are checked against the policy.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: Ack
+//       400: ValidationError
+
+// swagger:route DELETE /v1/ngalert/rule_policy configuration RouteDeleteRulePolicy
+//
+// Deletes the alert rule policy of the user's organization.
+//
+//     Responses:
+//       200: Ack
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/rule_policy/test configuration RouteTestRulePolicy
+//
+// Checks all alert rules of the user's organization against a rule policy without saving it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RulePolicyTestResult
+//       400: ValidationError
+
+// swagger:route GET /v1/ngalert/maintenance_windows configuration RouteGetMaintenanceWindows
+//
+// Get the maintenance windows of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: MaintenanceWindows
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/maintenance_windows configuration RoutePostMaintenanceWindow
+//
+// Creates a maintenance window in the user's organization.
+//
+// A silence with the matchers of the window is created at the start of each occurrence and expires at its end.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       201: MaintenanceWindow
+//       400: ValidationError
+
+// swagger:route GET /v1/ngalert/maintenance_windows/{UID} configuration RouteGetMaintenanceWindow
+//
+// Get a maintenance window of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       404: Failure
+
+// swagger:route PUT /v1/ngalert/maintenance_windows/{UID} configuration RoutePutMaintenanceWindow
+//
+// Updates a maintenance window of the user's organization.
+//
+// If an occurrence of the window is active, its silence is expired and a new silence is created with the new definition.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       400: ValidationError
+//       404: Failure
+
+// swagger:route DELETE /v1/ngalert/maintenance_windows/{UID} configuration RouteDeleteMaintenanceWindow
+//
+// Deletes a maintenance window of the user's organization and expires the silence of its active occurrence.
+//
+//     Responses:
+//       200: Ack
+//       404: Failure
+
 // swagger:parameters RoutePostNGalertConfig
 type NGalertConfig struct {
 	// in:body
@@ -101,3 +260,204 @@ type AlertingStatus struct {
 	AlertmanagersChoice      AlertmanagersChoice `json:"alertmanagersChoice"`
 	NumExternalAlertmanagers int                 `json:"numExternalAlertmanagers"`
 }
+
+// swagger:parameters RoutePostRulePolicy RouteTestRulePolicy
+type RulePolicyParams struct {
+	// in:body
+	Body RulePolicy
+}
+
+// swagger:enum RulePolicyMode
+type RulePolicyMode string
+
+const (
+	RulePolicyModeWarn   RulePolicyMode = "warn"
+	RulePolicyModeReject RulePolicyMode = "reject"
+)
+
+// swagger:model
+type RulePolicy struct {
+	// Mode defines whether alert rules that violate the policy are saved with warnings or rejected.
+	// required: true
+	Mode RulePolicyMode `json:"mode"`
+	// RequiredLabels are the labels that every rule must have with a non-empty value.
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// RequiredAnnotations are the annotations that every alerting rule must have with a non-empty value.
+	// example: ["runbook_url"]
+	RequiredAnnotations []string `json:"requiredAnnotations,omitempty"`
+	// LabelValuePatterns maps a label name to a regular expression that the whole value of the label must match.
+	// example: {"severity": "critical|warning|info"}
+	LabelValuePatterns map[string]string `json:"labelValuePatterns,omitempty"`
+	MinInterval        model.Duration    `json:"minInterval,omitempty"`
+	MaxInterval        model.Duration    `json:"maxInterval,omitempty"`
+	// MaxFor is the longest pending period of an alerting rule.
+	MaxFor model.Duration `json:"maxFor,omitempty"`
+	// BannedDatasourceTypes are the types of the data sources that the rules must not query.
+	BannedDatasourceTypes []string `json:"bannedDatasourceTypes,omitempty"`
+}
+
+// swagger:model
+type RulePolicyTestResult struct {
+	// Rules are the alert rules that violate the policy.
+	Rules []RulePolicyRuleViolations `json:"rules"`
+}
+
+// swagger:model
+type RulePolicyRuleViolations struct {
+	UID          string   `json:"uid"`
+	Title        string   `json:"title"`
+	NamespaceUID string   `json:"namespace_uid"`
+	RuleGroup    string   `json:"rule_group"`
+	Violations   []string `json:"violations"`
+}
+
+// swagger:model
+type RuleOwnership struct {
+	// Enabled is true if the evaluation of alert rules is sharded between the instances of the cluster.
+	Enabled bool `json:"enabled"`
+	// Instance is the name of the instance that served the request.
+	Instance string `json:"instance,omitempty"`
+	// Members are the instances that the evaluation of alert rules is sharded between.
+	Members []string    `json:"members"`
+	Rules   []RuleOwner `json:"rules"`
+}
+
+// swagger:model
+type RuleOwner struct {
+	UID          string `json:"uid"`
+	Title        string `json:"title"`
+	NamespaceUID string `json:"namespace_uid"`
+	RuleGroup    string `json:"rule_group"`
+	// Owner is the instance that evaluates the rule. It is empty until the cluster settles.
+	Owner string `json:"owner"`
+}
+
+// swagger:parameters RouteGetMaintenanceWindow RoutePutMaintenanceWindow RouteDeleteMaintenanceWindow
+type MaintenanceWindowUIDParams struct {
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostMaintenanceWindow RoutePutMaintenanceWindow
+type MaintenanceWindowParams struct {
+	// in:body
+	Body MaintenanceWindow
+}
+
+// swagger:model
+type MaintenanceWindows []MaintenanceWindow
+
+// swagger:model
+type MaintenanceWindow struct {
+	// UID is generated when the window is created without one. It is ignored on update.
+	UID string `json:"uid,omitempty"`
+	// required: true
+	Title string `json:"title"`
+	// Schedule defines when the occurrences of the window start. It is either a cron expression with five fields
+	// or a recurrence rule with FREQ=DAILY, WEEKLY or MONTHLY and the BYDAY, BYMONTHDAY, BYHOUR and BYMINUTE parts.
+	// required: true
+	// example: RRULE:FREQ=WEEKLY;BYDAY=SA;BYHOUR=2
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA name of the time zone the schedule is evaluated in. Defaults to UTC.
+	// example: Europe/Berlin
+	Timezone string `json:"timezone,omitempty"`
+	// Duration is the length of each occurrence.
+	// required: true
+	Duration model.Duration `json:"duration"`
+	// Matchers select the alerts that are silenced during the window.
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	// PauseRuleEvaluation pauses the evaluation of the alert rules whose labels, title, UID and folder match the
+	// matchers during the window. Like a paused rule, the alerts of the rule are resolved.
+	PauseRuleEvaluation bool `json:"pauseRuleEvaluation,omitempty"`
+	// readOnly: true
+	CreatedBy string `json:"createdBy,omitempty"`
+	// readOnly: true
+	UpdatedBy string `json:"updatedBy,omitempty"`
+	// readOnly: true
+	Created time.Time `json:"created,omitempty"`
+	// readOnly: true
+	Updated time.Time `json:"updated,omitempty"`
+	// ActiveUntil is the end of the active occurrence of the window, if any.
+	// readOnly: true
+	ActiveUntil *time.Time `json:"activeUntil,omitempty"`
+	// NextOccurrence is the start of the next occurrence of the window.
+	// readOnly: true
+	NextOccurrence *time.Time `json:"nextOccurrence,omitempty"`
+	// SilenceID is the ID of the silence created for the most recent occurrence of the window.
+	// readOnly: true
+	SilenceID string `json:"silenceId,omitempty"`
+}
+
+// swagger:parameters RoutePostAlertStateImport
+type AlertStateImportParams struct {
+	// in:body
+	Body PostableAlertStateImport
+}
+
+// swagger:model
+type AlertStateArchive struct {
+	// Version of the archive format.
+	// required: true
+	Version int `json:"version"`
+	// OrgID is the organization the archive was exported from.
+	OrgID     int64     `json:"orgId"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Rules are the alert rules that the instances belong to.
+	Rules     []AlertStateArchiveRule     `json:"rules"`
+	Instances []AlertStateArchiveInstance `json:"instances"`
+	// Silences is the state of the silences of the Alertmanager, encoded in base64.
+	Silences []byte `json:"silences,omitempty"`
+	// NotificationLog is the state of the notification log of the Alertmanager, encoded in base64.
+	NotificationLog []byte `json:"notificationLog,omitempty"`
+	// FlushLog is the state of the flush log of the Alertmanager, encoded in base64.
+	FlushLog []byte `json:"flushLog,omitempty"`
+}
+
+// swagger:model
+type AlertStateArchiveRule struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+}
+
+// swagger:model
+type AlertStateArchiveInstance struct {
+	RuleUID           string            `json:"ruleUid"`
+	Labels            map[string]string `json:"labels"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	State             string            `json:"state"`
+	Reason            string            `json:"reason,omitempty"`
+	StateSince        time.Time         `json:"stateSince"`
+	StateEnd          time.Time         `json:"stateEnd"`
+	LastEvalTime      time.Time         `json:"lastEvalTime"`
+	LastSentAt        *time.Time        `json:"lastSentAt,omitempty"`
+	FiredAt           *time.Time        `json:"firedAt,omitempty"`
+	ResolvedAt        *time.Time        `json:"resolvedAt,omitempty"`
+	ResultFingerprint string            `json:"resultFingerprint,omitempty"`
+	LastError         string            `json:"lastError,omitempty"`
+}
+
+// swagger:model
+type PostableAlertStateImport struct {
+	// required: true
+	Archive AlertStateArchive `json:"archive"`
+	// RuleUIDs maps the UIDs of alert rules in the archive to the UIDs of alert rules in the organization.
+	RuleUIDs map[string]string `json:"ruleUids,omitempty"`
+	// FolderUIDs maps the UIDs of folders in the archive to the UIDs of folders in the organization. An alert rule
+	// that is not found by UID is looked up by its title in its folder.
+	FolderUIDs map[string]string `json:"folderUids,omitempty"`
+}
+
+// swagger:model
+type AlertStateImportResult struct {
+	// Rules is the number of alert rules whose state was imported.
+	Rules int `json:"rules"`
+	// Instances is the number of alert instances that were imported.
+	Instances int `json:"instances"`
+	// SkippedRules are the UIDs of the alert rules in the archive that were not found in the organization.
+	SkippedRules []string `json:"skippedRules"`
+	// NotificationLogSkipped is true when the notification log and the flush log in the archive were not imported
+	// because the labels of alert rules changed.
+	NotificationLogSkipped bool `json:"notificationLogSkipped"`
+}
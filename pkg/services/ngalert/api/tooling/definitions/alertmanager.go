@@ -9,6 +9,7 @@ import (
 	"github.com/go-openapi/strfmt"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"go.yaml.in/yaml/v3"
 
 	"github.com/grafana/alerting/definition"
@@ -154,6 +155,37 @@ import (
 //
 //       410: Gone
 
+// swagger:route GET /alertmanager/grafana/config/api/v1/receivers/{Name}/digest alertmanager RouteGetGrafanaReceiverDigest
+//
+// Get the digest settings of a receiver.
+//
+//     Responses:
+//       200: ReceiverDigest
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:route PUT /alertmanager/grafana/config/api/v1/receivers/{Name}/digest alertmanager RoutePutGrafanaReceiverDigest
+//
+// Enable or update the digest mode of a receiver.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ReceiverDigest
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:route DELETE /alertmanager/grafana/config/api/v1/receivers/{Name}/digest alertmanager RouteDeleteGrafanaReceiverDigest
+//
+// Disable the digest mode of a receiver.
+//
+//     Responses:
+//       204: description: The digest mode was disabled.
+//       403: PermissionDenied
+//       404: NotFound
+
 // swagger:route POST /alertmanager/grafana/config/api/v1/templates/test alertmanager RoutePostTestGrafanaTemplates
 //
 // Test Grafana managed templates without saving them.
@@ -511,6 +543,32 @@ type ReceiversResponse struct {
 	Body []alertingmodels.ReceiverStatus
 }
 
+// swagger:parameters RouteGetGrafanaReceiverDigest RoutePutGrafanaReceiverDigest RouteDeleteGrafanaReceiverDigest
+type ReceiverNameParam struct {
+	// Name of the receiver
+	// in:path
+	// required: true
+	Name string
+}
+
+// swagger:parameters RoutePutGrafanaReceiverDigest
+type ReceiverDigestParams struct {
+	// in:body
+	Body ReceiverDigest
+}
+
+// ReceiverDigest configures a receiver to accumulate its alerts and send a single summarized notification
+// per window. Alerts that match the bypass matchers are sent right away.
+//
+// swagger:model
+type ReceiverDigest struct {
+	// How long the alerts are accumulated before the digest is sent, between 1m and 24h.
+	// required: true
+	Window model.Duration `yaml:"window" json:"window"`
+	// Alerts that match these matchers are sent right away instead of being included in the digest.
+	BypassMatchers ObjectMatchers `yaml:"bypass_matchers,omitempty" json:"bypass_matchers,omitempty"`
+}
+
 // swagger:parameters RouteGetAMAlerts RouteGetAMAlertGroups RouteGetGrafanaAMAlerts RouteGetGrafanaAMAlertGroups
 type AlertsParams struct {
 
@@ -637,6 +695,7 @@ type PostableUserConfig struct {
 	ExtraConfigs           []ExtraConfiguration                      `yaml:"extra_config,omitempty" json:"extra_config,omitempty"`
 	ManagedRoutes          ManagedRoutes                             `yaml:"managed_routes,omitempty" json:"managed_routes,omitempty"`                     // TODO: Move to ConfigRevision?
 	ManagedInhibitionRules ManagedInhibitionRules                    `yaml:"managed_inhibition_rules,omitempty" json:"managed_inhibition_rules,omitempty"` // TODO: Move to ConfigRevision?
+	ReceiverDigests        map[string]ReceiverDigest                 `yaml:"receiver_digests,omitempty" json:"receiver_digests,omitempty"`
 }
 
 func (c *PostableUserConfig) UnmarshalJSON(b []byte) error {
@@ -44,6 +44,134 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions/diff ruler RouteGetRuleVersionsDiff
+//
+// Compare two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionDiff
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostRestoreRuleVersion
+//
+// Restore a previous version of a rule
+//
+// Creates a new version of the rule with the definition of the given version. The rule keeps its UID, folder and group.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: description: The rule was changed while it was being restored.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/acknowledge ruler RoutePostAcknowledgeAlert
+//
+// Acknowledge a firing alert instance of a rule
+//
+// No more notifications are sent for the alert instance until the acknowledgement expires or the alert instance stops firing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableAlertAcknowledgement
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/unacknowledge ruler RoutePostUnacknowledgeAlert
+//
+// Remove the acknowledgement of an alert instance of a rule
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       204: description: The acknowledgement was removed.
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/backfills ruler RouteGetRecordingRuleBackfills
+//
+// List the backfills of a recording rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRecordingRuleBackfills
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/backfills ruler RoutePostRecordingRuleBackfill
+//
+// Backfill a recording rule
+//
+// Evaluates the recording rule at its interval over a past time range, and writes the series with the timestamps of the evaluations. The backfill runs in the background.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: GettableRecordingRuleBackfill
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/backfills/{BackfillUID}/cancel ruler RoutePostCancelRecordingRuleBackfill
+//
+// Cancel a pending or running backfill of a recording rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRecordingRuleBackfill
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/backfills/{BackfillUID}/resume ruler RoutePostResumeRecordingRuleBackfill
+//
+// Resume a failed or canceled backfill of a recording rule
+//
+// The backfill continues from the last evaluation whose progress was saved.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRecordingRuleBackfill
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/dependencies ruler RouteGetRuleDependencies
+//
+// List the dependencies between alert rules
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleDependencyGraph
+//       403: ForbiddenError
+
 // swagger:route Get /ruler/grafana/api/v1/rules ruler RouteGetGrafanaRulesConfig
 //
 // List rule groups
@@ -72,6 +200,21 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/export/prometheus ruler RouteGetRulesForPrometheusExport
+//
+// List rules in Prometheus rule file format
+//
+// Rules that were imported from Prometheus, and rules that query a single Prometheus-compatible or Loki data source with a threshold condition, are converted to Prometheus rule groups. The rules that cannot be converted are listed with the reason.
+//
+//     Produces:
+//     - application/yaml
+//     - application/json
+//
+//     Responses:
+//       200: PrometheusRulesExport
+//       403: ForbiddenError
+//       404: description: Not found.
+
 // swagger:route Get /ruler/{DatasourceUID}/api/v1/rules ruler RouteGetRulesConfig
 //
 // List rule groups
@@ -256,18 +399,127 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
+// swagger:parameters RouteGetRulesForPrometheusExport
+type PrometheusRulesExportParameters struct {
+	// UIDs of folders from which to export rules
+	// in:query
+	// required:false
+	FolderUID []string `json:"folderUid"`
+
+	// Name of group of rules to export. Must be specified only together with a single folder UID
+	// in:query
+	// required: false
+	GroupName string `json:"group"`
+
+	// UID of alert rule to export. If specified, parameters folderUid and group must be empty.
+	// in:query
+	// required: false
+	RuleUID string `json:"ruleUid"`
+}
+
 // swagger:parameters RouteGetRuleByUID RouteGetRuleVersionsByUID
 type PathGetRuleByUIDParams struct {
 	// in: path
 	RuleUID string
 }
 
+// swagger:parameters RouteGetRuleVersionsDiff
+type RuleVersionsDiffParams struct {
+	// in: path
+	RuleUID string
+	// The version to compare from.
+	// in: query
+	// required: true
+	From int64 `json:"from"`
+	// The version to compare to. Defaults to the current version of the rule.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RoutePostRestoreRuleVersion
+type RestoreRuleVersionParams struct {
+	// in: path
+	RuleUID string
+	// The version of the rule to restore.
+	// in: path
+	Version int64
+}
+
+// swagger:parameters RoutePostAcknowledgeAlert
+type AcknowledgeAlertParams struct {
+	// in: path
+	RuleUID string
+	// in: body
+	Body PostableAlertAcknowledgement
+}
+
+// swagger:parameters RoutePostUnacknowledgeAlert
+type UnacknowledgeAlertParams struct {
+	// in: path
+	RuleUID string
+	// in: body
+	Body PostableAlertUnacknowledgement
+}
+
+// swagger:parameters RouteGetRecordingRuleBackfills
+type RecordingRuleBackfillsParams struct {
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RoutePostRecordingRuleBackfill
+type PostRecordingRuleBackfillParams struct {
+	// in: path
+	RuleUID string
+	// in: body
+	Body PostableRecordingRuleBackfill
+}
+
+// swagger:parameters RoutePostCancelRecordingRuleBackfill RoutePostResumeRecordingRuleBackfill
+type RecordingRuleBackfillParams struct {
+	// in: path
+	RuleUID string
+	// in: path
+	BackfillUID string
+}
+
 // swagger:parameters RouteDeleteRuleFromTrashByGUID
 type PathDeleteRuleFromTrashByGUIDParams struct {
 	// in: path
 	RuleGUID string
 }
 
+// swagger:model
+type PrometheusRulesExport struct {
+	// Namespaces maps the full path of each folder to its rule groups in the Prometheus rule file format.
+	Namespaces map[string][]PrometheusRuleGroup `yaml:"namespaces" json:"namespaces"`
+	// Skipped are the rules that cannot be converted to Prometheus rules and are not in the rule groups.
+	Skipped []PrometheusRuleExportFailure `yaml:"skipped,omitempty" json:"skipped,omitempty"`
+	// Warnings are the settings of the converted rules that Prometheus does not support, so the rules behave differently in Prometheus.
+	Warnings []PrometheusRuleExportWarning `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+}
+
+type PrometheusRuleExportFailure struct {
+	UID          string `yaml:"uid" json:"uid"`
+	Title        string `yaml:"title" json:"title"`
+	NamespaceUID string `yaml:"namespace_uid" json:"namespace_uid"`
+	RuleGroup    string `yaml:"rule_group" json:"rule_group"`
+	// Reason describes why the rule cannot be converted.
+	Reason string `yaml:"reason" json:"reason"`
+}
+
+type PrometheusRuleExportWarning struct {
+	UID          string `yaml:"uid" json:"uid"`
+	Title        string `yaml:"title" json:"title"`
+	NamespaceUID string `yaml:"namespace_uid" json:"namespace_uid"`
+	RuleGroup    string `yaml:"rule_group" json:"rule_group"`
+	// Field is the name of the setting of the rule that is not exported.
+	Field string `yaml:"field" json:"field"`
+	// Reason describes how the rule behaves differently in Prometheus.
+	Reason string `yaml:"reason" json:"reason"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
@@ -346,6 +598,118 @@ func (c *PostableRuleGroupConfig) validate() error {
 // swagger:model
 type GettableRuleVersions []GettableExtendedRuleNode
 
+// swagger:model
+type RuleVersionDiff struct {
+	UID         string `json:"uid"`
+	FromVersion int64  `json:"from_version"`
+	ToVersion   int64  `json:"to_version"`
+	// Fields are the changes of the rule settings that are not listed separately, such as the title, condition and pending period.
+	Fields []RuleFieldDiff `json:"fields"`
+	// Queries are the changes of the queries and expressions, matched by their reference ID.
+	Queries     []RuleQueryDiff `json:"queries"`
+	Labels      []RuleFieldDiff `json:"labels"`
+	Annotations []RuleFieldDiff `json:"annotations"`
+	// NotificationSettings are the changes of the simplified routing settings.
+	NotificationSettings []RuleFieldDiff `json:"notification_settings"`
+}
+
+// RuleFieldDiff is a changed field, label or annotation. From is null if the field was added, and To is null if it was removed.
+type RuleFieldDiff struct {
+	Path string `json:"path"`
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+type RuleQueryDiff struct {
+	RefID string `json:"refId"`
+	// enum: added,removed,changed
+	Action       string `json:"action"`
+	IsExpression bool   `json:"is_expression"`
+	// Changes are the changed fields of a changed query. The fields of the query model are prefixed with "model.".
+	Changes []RuleFieldDiff `json:"changes,omitempty"`
+}
+
+// swagger:model
+type PostableAlertAcknowledgement struct {
+	// Labels identify the alert instance. The internal labels can be omitted.
+	// required: true
+	Labels map[string]string `json:"labels"`
+	// Assignee is the login of the user who handles the alert. Defaults to the user who acknowledges it.
+	Assignee string `json:"assignee,omitempty"`
+	Note     string `json:"note,omitempty"`
+	// ExpiresAt is the time the acknowledgement ends at. By default, the acknowledgement lasts until the alert stops firing.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// swagger:model
+type PostableAlertUnacknowledgement struct {
+	// Labels identify the alert instance. The internal labels can be omitted.
+	// required: true
+	Labels map[string]string `json:"labels"`
+}
+
+// swagger:model
+type GettableAlertAcknowledgement struct {
+	Labels         map[string]string `json:"labels"`
+	AcknowledgedBy string            `json:"acknowledged_by"`
+	Assignee       string            `json:"assignee"`
+	Note           string            `json:"note,omitempty"`
+	Created        time.Time         `json:"created"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+}
+
+// swagger:model
+type PostableRecordingRuleBackfill struct {
+	// From is the start of the time range to evaluate the rule over.
+	// required: true
+	From time.Time `json:"from"`
+	// To is the end of the time range. It must not be in the future.
+	// required: true
+	To time.Time `json:"to"`
+}
+
+// swagger:model
+type GettableRecordingRuleBackfills []GettableRecordingRuleBackfill
+
+// swagger:model
+type GettableRecordingRuleBackfill struct {
+	UID     string    `json:"uid"`
+	RuleUID string    `json:"rule_uid"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	// enum: pending,running,completed,failed,canceled
+	Status string `json:"status"`
+	// Next is the time of the next evaluation.
+	Next        time.Time `json:"next"`
+	Evaluations int64     `json:"evaluations"`
+	// Completed is the number of evaluations done, including the failed ones.
+	Completed int64 `json:"completed"`
+	// Failed is the number of evaluations that failed to query or to write.
+	Failed    int64     `json:"failed"`
+	LastError string    `json:"last_error,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// swagger:model
+type RuleDependencyGraph struct {
+	Rules []RuleDependencyNode `json:"rules"`
+}
+
+// RuleDependencyNode is an alert rule that reads the state of other alert rules, or whose state
+// is read by other alert rules, with rule state expressions.
+type RuleDependencyNode struct {
+	UID          string `json:"uid"`
+	Title        string `json:"title"`
+	NamespaceUID string `json:"namespace_uid"`
+	RuleGroup    string `json:"rule_group"`
+	// DependsOn are the UIDs of the alert rules whose state the rule reads.
+	DependsOn []string `json:"depends_on"`
+	// Dependents are the UIDs of the alert rules that read the state of the rule.
+	Dependents []string `json:"dependents"`
+}
+
 // swagger:model
 type GettableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
@@ -639,6 +1003,8 @@ type GettableGrafanaRule struct {
 
 	// Field is only populated when listing alert rule versions.
 	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	// RestoredFrom is the version that was restored to create this version. Field is only populated when listing alert rule versions.
+	RestoredFrom int64 `yaml:"restored_from,omitempty" json:"restored_from,omitempty"`
 }
 
 // UserInfo represents user-related information, including a unique identifier and a name.
@@ -720,6 +1086,9 @@ type UpdateRuleGroupResponse struct {
 	Created []string `json:"created,omitempty"`
 	Updated []string `json:"updated,omitempty"`
 	Deleted []string `json:"deleted,omitempty"`
+	// Warnings describe the violations of the rule policy of the organization by the saved rules,
+	// when the policy is in the warn mode.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // swagger:parameters RouteUpdateNamespaceRules
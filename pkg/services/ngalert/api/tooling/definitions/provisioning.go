@@ -34,3 +34,85 @@ type DecryptQueryParams struct {
 	// default: false
 	Decrypt bool `json:"decrypt"`
 }
+
+// swagger:route POST /v1/provisioning/import provisioning stable RoutePostAlertingFileImport
+//
+// Import alert rule groups and contact points exported in provisioning file format or as HCL.
+//
+// The imported resources are compared with the current state of the organization. Unless it is a dry run,
+// the integrations of the contact points are created, updated or deleted to match the file in a single transaction,
+// then every rule group is replaced atomically. Rule groups and contact points that are not in the file are not changed.
+//
+//     Consumes:
+//     - application/json
+//     - application/yaml
+//     - application/terraform+hcl
+//     - text/hcl
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertingFileImportResult
+//       400: ValidationError
+//       403: PermissionDenied
+
+// swagger:parameters RoutePostAlertingFileImport
+type AlertingFileImportParams struct {
+	// Whether to only compare the file with the current state without applying any changes.
+	// in: query
+	// required: false
+	// default: false
+	DryRun bool `json:"dry_run"`
+}
+
+// ImportAction is the change that an import makes to a resource.
+// enum: create,update,delete,unchanged
+type ImportAction string
+
+const (
+	ImportActionCreate    ImportAction = "create"
+	ImportActionUpdate    ImportAction = "update"
+	ImportActionDelete    ImportAction = "delete"
+	ImportActionUnchanged ImportAction = "unchanged"
+)
+
+// AlertingFileImportResult describes the changes of an import.
+// swagger:model
+type AlertingFileImportResult struct {
+	// Applied is false if the import was a dry run.
+	Applied       bool                     `json:"applied"`
+	RuleGroups    []RuleGroupImportDiff    `json:"ruleGroups"`
+	ContactPoints []ContactPointImportDiff `json:"contactPoints"`
+	// Skipped describes the resources of the file that are not imported, such as notification policies and mute timings.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+type RuleGroupImportDiff struct {
+	FolderUID string           `json:"folderUid"`
+	Name      string           `json:"name"`
+	Action    ImportAction     `json:"action"`
+	Rules     []RuleImportDiff `json:"rules"`
+}
+
+type RuleImportDiff struct {
+	UID    string       `json:"uid,omitempty"`
+	Title  string       `json:"title"`
+	Action ImportAction `json:"action"`
+	// Paths of the changed fields of an updated rule.
+	ChangedFields []string `json:"changedFields,omitempty"`
+}
+
+type ContactPointImportDiff struct {
+	Name         string                  `json:"name"`
+	Action       ImportAction            `json:"action"`
+	Integrations []IntegrationImportDiff `json:"integrations"`
+}
+
+type IntegrationImportDiff struct {
+	UID    string       `json:"uid,omitempty"`
+	Type   string       `json:"type"`
+	Action ImportAction `json:"action"`
+	// Names of the changed settings of an updated integration.
+	ChangedFields []string `json:"changedFields,omitempty"`
+}
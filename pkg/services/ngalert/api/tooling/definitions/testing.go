@@ -131,6 +131,8 @@ type EvalQueriesPayload struct {
 	Condition string       `json:"condition"`
 	Data      []AlertQuery `json:"data"`
 	Now       time.Time    `json:"now"`
+	// Explain adds the explanation of the execution of each query and expression to the results, under the refId `__explain__`.
+	Explain bool `json:"explain,omitempty"`
 }
 
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
@@ -236,7 +238,19 @@ type BacktestConfig struct {
 	UID          string `json:"uid,omitempty"`
 	RuleGroup    string `json:"rule_group,omitempty"`
 	NamespaceUID string `json:"namespace_uid,omitempty"`
+
+	// NotificationSettings routes the notifications of the rule directly to a contact point.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty"`
+	// CompareToVersion is a stored version of the rule with the UID to test over the same time range.
+	// The differences between the states of both versions are added to the result.
+	CompareToVersion *int64 `json:"compare_to_version,omitempty"`
+	// ReplayHistory replays the state transitions recorded in the state history of the rule with the UID,
+	// instead of evaluating the queries of the rule.
+	ReplayHistory bool `json:"replay_history,omitempty"`
 }
 
+// BacktestResult is a frame with the state transitions of the rule, in the format of the state history.
+// The custom metadata of the frame contains the notifications that would have been sent and, if the
+// result is compared to another version of the rule, the result of that version and the differences.
 // swagger:model
 type BacktestResult data.Frame
@@ -412,6 +412,14 @@ func ValidateBacktestConfig(orgId int64, config apimodels.BacktestConfig, limits
 		return nil, fmt.Errorf("invalid testing range: from %s must be before to %s", config.From, config.To)
 	}
 
+	if config.CompareToVersion != nil && config.UID == "" {
+		return nil, errors.New("uid is required to compare to a version of the rule")
+	}
+
+	if config.ReplayHistory && config.UID == "" {
+		return nil, errors.New("uid is required to replay the state history of the rule")
+	}
+
 	interval, err := validateGroupInterval(config.Interval, limits)
 	if err != nil {
 		return nil, err
@@ -432,6 +440,7 @@ func ValidateBacktestConfig(orgId int64, config apimodels.BacktestConfig, limits
 			NoDataState:                 config.NoDataState,
 			ExecErrState:                config.ExecErrState,
 			MissingSeriesEvalsToResolve: config.MissingSeriesEvalsToResolve,
+			NotificationSettings:        config.NotificationSettings,
 		},
 	}, config.RuleGroup, interval, orgId, config.NamespaceUID, limits)
 }
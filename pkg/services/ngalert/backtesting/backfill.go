@@ -0,0 +1,417 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// backfillSyncInterval is how often the pending backfills are read from the database.
+	backfillSyncInterval = 10 * time.Second
+	// backfillProgressInterval is how often the progress of a running backfill is saved. The progress is saved in the
+	// background, so that the backfill is not taken over while an evaluation or a write takes long.
+	backfillProgressInterval = 10 * time.Second
+	// backfillStaleAfter is the time after which a running backfill whose progress was not saved is considered
+	// abandoned, for example because the Grafana instance running it stopped, and is taken over.
+	backfillStaleAfter = time.Minute
+	// backfillMaxConsecutiveFailures is the number of evaluations in a row that can fail before the backfill fails.
+	backfillMaxConsecutiveFailures = 10
+)
+
+var (
+	errBackfillStopped      = errors.New("backfill is not running anymore")
+	errBackfillTooManyFails = fmt.Errorf("backfill stopped after %d failed evaluations in a row", backfillMaxConsecutiveFailures)
+)
+
+type rawCallbackFunc = func(evaluationIndex int, now time.Time, resp *backend.QueryDataResponse, err error) (bool, error)
+
+// recordingEvaluator evaluates the queries of a recording rule. Unlike queryEvaluator, it returns the raw responses
+// and passes the evaluation errors to the callback, which decides whether to continue.
+type recordingEvaluator struct {
+	eval eval.ConditionEvaluator
+}
+
+func (d *recordingEvaluator) Eval(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback rawCallbackFunc) error {
+	for idx, now := 0, from; idx < evaluations; idx, now = idx+1, now.Add(interval) {
+		resp, err := d.eval.EvaluateRaw(ctx, now)
+		cont, err := callback(idx, now, resp, err)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			break
+		}
+	}
+	return nil
+}
+
+// BackfillStore is the database interface of the backfiller.
+type BackfillStore interface {
+	store.RecordingRuleBackfillStore
+	GetAlertRuleByUID(ctx context.Context, query *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error)
+}
+
+type backfillKey struct {
+	orgID int64
+	uid   string
+}
+
+// Backfiller runs the backfills of recording rules. A backfill evaluates the rule at its interval over a past time
+// range and writes the resulting series with the timestamps of the evaluations.
+//
+// The backfills are stored in the database and run one at a time. A backfill is claimed in the database before
+// it runs, so that only one Grafana instance runs it, and its progress is saved periodically, so that it resumes
+// where it stopped when it is taken over by another instance or resumed after it was canceled or failed.
+type Backfiller struct {
+	store          BackfillStore
+	evalFactory    eval.EvaluatorFactory
+	writer         schedule.RecordingWriter
+	limiter        *rate.Limiter
+	minInterval    time.Duration
+	baseInterval   time.Duration
+	maxEvaluations int
+	clock          clock.Clock
+	log            log.Logger
+
+	mtx sync.Mutex
+	// running are the cancel functions of the backfills run by this instance.
+	running map[backfillKey]context.CancelFunc
+}
+
+func NewBackfiller(store BackfillStore, evalFactory eval.EvaluatorFactory, writer schedule.RecordingWriter, cfg setting.UnifiedAlertingSettings, clock clock.Clock, log log.Logger) *Backfiller {
+	limit := rate.Inf
+	if cfg.RecordingRules.BackfillWritesPerSecond > 0 {
+		limit = rate.Limit(cfg.RecordingRules.BackfillWritesPerSecond)
+	}
+	return &Backfiller{
+		store:          store,
+		evalFactory:    evalFactory,
+		writer:         writer,
+		limiter:        rate.NewLimiter(limit, 1),
+		minInterval:    cfg.MinInterval,
+		baseInterval:   cfg.BaseInterval,
+		maxEvaluations: cfg.RecordingRules.BackfillMaxEvaluations,
+		clock:          clock,
+		log:            log,
+		running:        map[backfillKey]context.CancelFunc{},
+	}
+}
+
+// Create adds a pending backfill of the recording rule over the time range. It returns
+// ErrRecordingRuleBackfillInvalid if the rule is not a recording rule or the time range is not valid.
+func (b *Backfiller) Create(ctx context.Context, rule *models.AlertRule, from, to time.Time, createdBy string) (*models.RecordingRuleBackfill, error) {
+	if rule.Type() != models.RuleTypeRecording {
+		return nil, fmt.Errorf("%w: only recording rules can be backfilled", models.ErrRecordingRuleBackfillInvalid)
+	}
+	backfill := models.RecordingRuleBackfill{
+		OrgID:   rule.OrgID,
+		RuleUID: rule.UID,
+		// The store keeps the times with a precision of a second.
+		From:      from.UTC().Truncate(time.Second),
+		To:        to.UTC().Truncate(time.Second),
+		Status:    models.BackfillPending,
+		CreatedBy: createdBy,
+	}
+	if err := backfill.Validate(b.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	rule = b.withMinInterval(rule)
+	firstEval, err := getFirstEvaluationTime(backfill.From, rule, b.baseInterval, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrRecordingRuleBackfillInvalid, err)
+	}
+	if firstEval.After(backfill.To) {
+		return nil, fmt.Errorf("%w: time range does not contain any evaluation of the rule", models.ErrRecordingRuleBackfillInvalid)
+	}
+	evaluations := calculateNumberOfEvaluations(firstEval, backfill.To, rule.GetInterval())
+	if b.maxEvaluations > 0 && evaluations > b.maxEvaluations {
+		return nil, fmt.Errorf("%w: time range requires %d evaluations, more than the limit of %d", models.ErrRecordingRuleBackfillInvalid, evaluations, b.maxEvaluations)
+	}
+	backfill.Next = firstEval.UTC()
+	backfill.Evaluations = int64(evaluations)
+	return b.store.InsertRecordingRuleBackfill(ctx, backfill)
+}
+
+// List returns the backfills of the rule.
+func (b *Backfiller) List(ctx context.Context, orgID int64, ruleUID string) ([]*models.RecordingRuleBackfill, error) {
+	return b.store.ListRecordingRuleBackfills(ctx, orgID, ruleUID)
+}
+
+// Get returns the backfill, or ErrRecordingRuleBackfillNotFound if it does not exist.
+func (b *Backfiller) Get(ctx context.Context, orgID int64, uid string) (*models.RecordingRuleBackfill, error) {
+	return b.store.GetRecordingRuleBackfill(ctx, orgID, uid)
+}
+
+// Cancel stops the pending or running backfill. If another instance runs it, it stops the next time it saves
+// its progress.
+func (b *Backfiller) Cancel(ctx context.Context, orgID int64, uid string) (*models.RecordingRuleBackfill, error) {
+	ok, err := b.store.SetRecordingRuleBackfillStatus(ctx, orgID, uid, models.BackfillCanceled, models.BackfillPending, models.BackfillRunning)
+	if err != nil {
+		return nil, err
+	}
+	backfill, err := b.store.GetRecordingRuleBackfill(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: only pending and running backfills can be canceled, the backfill is %s", models.ErrRecordingRuleBackfillInvalid, backfill.Status)
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if cancel, ok := b.running[backfillKey{orgID: orgID, uid: uid}]; ok {
+		cancel()
+	}
+	return backfill, nil
+}
+
+// Resume sets the failed or canceled backfill to pending, so that it continues from the last saved evaluation.
+func (b *Backfiller) Resume(ctx context.Context, orgID int64, uid string) (*models.RecordingRuleBackfill, error) {
+	ok, err := b.store.SetRecordingRuleBackfillStatus(ctx, orgID, uid, models.BackfillPending, models.BackfillFailed, models.BackfillCanceled)
+	if err != nil {
+		return nil, err
+	}
+	backfill, err := b.store.GetRecordingRuleBackfill(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: only failed and canceled backfills can be resumed, the backfill is %s", models.ErrRecordingRuleBackfillInvalid, backfill.Status)
+	}
+	return backfill, nil
+}
+
+// Run runs the pending backfills until the context is cancelled.
+func (b *Backfiller) Run(ctx context.Context) error {
+	b.log.Info("Starting recording rule backfiller", "interval", backfillSyncInterval)
+	ticker := b.clock.Ticker(backfillSyncInterval)
+	defer ticker.Stop()
+	for {
+		if err := b.runPending(ctx); err != nil {
+			b.log.Error("Failed to run recording rule backfills", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPending claims and runs the pending backfills, and the running ones that were abandoned, the oldest first.
+func (b *Backfiller) runPending(ctx context.Context) error {
+	backfills, err := b.store.ListRecordingRuleBackfills(ctx, 0, "")
+	if err != nil {
+		return err
+	}
+	for _, backfill := range backfills {
+		if ctx.Err() != nil {
+			return nil
+		}
+		staleBefore := b.clock.Now().Add(-backfillStaleAfter)
+		if backfill.Status != models.BackfillPending && (backfill.Status != models.BackfillRunning || !backfill.Updated.Before(staleBefore)) {
+			continue
+		}
+		claimed, err := b.store.ClaimRecordingRuleBackfill(ctx, backfill.OrgID, backfill.UID, staleBefore)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		backfill.Status = models.BackfillRunning
+		b.run(ctx, backfill)
+	}
+	return nil
+}
+
+// backfillProgress is the progress of a running backfill, shared by the evaluations and the goroutine that saves it.
+type backfillProgress struct {
+	mtx      sync.Mutex
+	backfill *models.RecordingRuleBackfill
+}
+
+func (p *backfillProgress) update(fn func(backfill *models.RecordingRuleBackfill)) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	fn(p.backfill)
+}
+
+func (p *backfillProgress) get() models.RecordingRuleBackfill {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return *p.backfill
+}
+
+// run evaluates the rule from the next evaluation of the backfill until the end of the time range, the backfill
+// is canceled, or too many evaluations fail in a row.
+func (b *Backfiller) run(ctx context.Context, backfill *models.RecordingRuleBackfill) {
+	key := backfillKey{orgID: backfill.OrgID, uid: backfill.UID}
+	ctx, cancel := context.WithCancelCause(ctx)
+	b.mtx.Lock()
+	b.running[key] = func() { cancel(nil) }
+	b.mtx.Unlock()
+	defer func() {
+		b.mtx.Lock()
+		delete(b.running, key)
+		b.mtx.Unlock()
+		cancel(nil)
+	}()
+
+	logger := b.log.New("org_id", backfill.OrgID, "rule_uid", backfill.RuleUID, "backfill_uid", backfill.UID)
+	logger.Info("Running recording rule backfill", "from", backfill.From, "to", backfill.To, "next", backfill.Next, "completed", backfill.Completed, "evaluations", backfill.Evaluations)
+
+	progress := &backfillProgress{backfill: backfill}
+	saveCtx, stopSaving := context.WithCancel(ctx)
+	saved := make(chan struct{})
+	// The ticker is created before the goroutine starts, so that no tick is missed.
+	ticker := b.clock.Ticker(backfillProgressInterval)
+	go func() {
+		defer close(saved)
+		defer ticker.Stop()
+		b.saveProgress(saveCtx, logger, progress, ticker.C, cancel)
+	}()
+
+	err := b.evaluate(ctx, logger, progress)
+	stopSaving()
+	<-saved
+
+	switch {
+	case err == nil:
+		backfill.Status = models.BackfillCompleted
+		logger.Info("Recording rule backfill completed", "completed", backfill.Completed, "failed", backfill.Failed)
+	case ctx.Err() != nil:
+		// The backfill was canceled, it was canceled through another instance, or Grafana is shutting down.
+		// The progress is saved only if the backfill is still running, so that another instance continues it.
+		logger.Info("Recording rule backfill stopped", "completed", backfill.Completed)
+	default:
+		backfill.Status = models.BackfillFailed
+		backfill.LastError = err.Error()
+		logger.Error("Recording rule backfill failed", "error", err)
+	}
+
+	if _, err := b.store.UpdateRecordingRuleBackfillProgress(context.WithoutCancel(ctx), *backfill); err != nil {
+		logger.Error("Failed to save recording rule backfill progress", "error", err)
+	}
+}
+
+// saveProgress saves the progress of the backfill at every tick until the context is cancelled. This also shows the
+// other instances that the backfill is still running. If the backfill is not running anymore, for example because
+// it was canceled through another instance, the evaluations are stopped.
+func (b *Backfiller) saveProgress(ctx context.Context, logger log.Logger, progress *backfillProgress, ticks <-chan time.Time, stop context.CancelCauseFunc) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+		}
+		running, err := b.store.UpdateRecordingRuleBackfillProgress(ctx, progress.get())
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Failed to save recording rule backfill progress", "error", err)
+			}
+			continue
+		}
+		if !running {
+			stop(errBackfillStopped)
+			return
+		}
+	}
+}
+
+func (b *Backfiller) evaluate(ctx context.Context, logger log.Logger, progress *backfillProgress) error {
+	backfill := progress.get()
+	rule, err := b.store.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: backfill.OrgID, UID: backfill.RuleUID})
+	if err != nil {
+		return err
+	}
+	if rule.Type() != models.RuleTypeRecording {
+		return errors.New("rule is not a recording rule")
+	}
+	rule = b.withMinInterval(rule)
+
+	evaluator, err := b.evalFactory.Create(eval.NewContext(ctx, schedule.SchedulerUserFor(rule.OrgID)), rule.GetEvalCondition().WithSource("backfill"))
+	if err != nil {
+		return err
+	}
+
+	labels := models.WithoutPrivateLabels(rule.Labels)
+	failures := 0
+	remaining := int(backfill.Evaluations - backfill.Completed)
+	ev := &recordingEvaluator{eval: evaluator}
+	return ev.Eval(ctx, backfill.Next, rule.GetInterval(), remaining, func(_ int, now time.Time, resp *backend.QueryDataResponse, evalErr error) (bool, error) {
+		if now.After(backfill.To) {
+			return false, nil
+		}
+		writeErr := b.write(ctx, rule, now, resp, evalErr, labels)
+		if writeErr != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			failures++
+			logger.Warn("Failed to backfill evaluation", "time", now, "error", writeErr)
+		} else {
+			failures = 0
+		}
+		progress.update(func(backfill *models.RecordingRuleBackfill) {
+			if writeErr != nil {
+				backfill.Failed++
+				backfill.LastError = writeErr.Error()
+			}
+			backfill.Completed++
+			backfill.Next = now.Add(rule.GetInterval()).UTC()
+		})
+		if failures >= backfillMaxConsecutiveFailures {
+			return false, errBackfillTooManyFails
+		}
+		return true, nil
+	})
+}
+
+// write writes the series of the evaluation at the given time, the same way the scheduler does.
+func (b *Backfiller) write(ctx context.Context, rule *models.AlertRule, now time.Time, resp *backend.QueryDataResponse, evalErr error, labels map[string]string) error {
+	if evalErr != nil {
+		return fmt.Errorf("failed to evaluate the rule: %w", evalErr)
+	}
+	if err := eval.FindConditionError(resp, rule.Record.From); err != nil {
+		return fmt.Errorf("the query failed with an error: %w", err)
+	}
+	node, ok := resp.Responses[rule.Record.From]
+	if !ok || eval.IsNoData(node) {
+		// Nothing to write.
+		return nil
+	}
+	if err := b.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	if err := b.writer.WriteDatasource(ctx, rule.Record.TargetDatasourceUID, rule.Record.Metric, now, node.Frames, rule.OrgID, labels); err != nil {
+		return fmt.Errorf("remote write failed: %w", err)
+	}
+	return nil
+}
+
+// withMinInterval returns a copy of the rule with the minimal interval if its interval is shorter, as the scheduler
+// does not evaluate rules more often.
+func (b *Backfiller) withMinInterval(rule *models.AlertRule) *models.AlertRule {
+	if rule.GetInterval() >= b.minInterval {
+		return rule
+	}
+	rule = rule.Copy()
+	rule.IntervalSeconds = int64(b.minInterval.Seconds())
+	return rule
+}
@@ -0,0 +1,295 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeBackfillStore struct {
+	*store.FakeRecordingRuleBackfillStore
+	rules map[string]*models.AlertRule
+}
+
+func (f *fakeBackfillStore) GetAlertRuleByUID(_ context.Context, query *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error) {
+	rule, ok := f.rules[query.UID]
+	if !ok || rule.OrgID != query.OrgID {
+		return nil, models.ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+func newTestBackfiller(t *testing.T, evaluator *eval_mocks.ConditionEvaluatorMock, w writer.FakeWriter, rules ...*models.AlertRule) (*Backfiller, *fakeBackfillStore, *clock.Mock) {
+	t.Helper()
+	st := &fakeBackfillStore{FakeRecordingRuleBackfillStore: store.NewFakeRecordingRuleBackfillStore(t), rules: map[string]*models.AlertRule{}}
+	for _, rule := range rules {
+		st.rules[rule.UID] = rule
+	}
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	cfg := setting.UnifiedAlertingSettings{
+		BaseInterval: 10 * time.Second,
+		MinInterval:  10 * time.Second,
+		RecordingRules: setting.RecordingRuleSettings{
+			BackfillMaxEvaluations: 100,
+		},
+	}
+	return NewBackfiller(st, eval_mocks.NewEvaluatorFactory(evaluator), w, cfg, clk, log.NewNopLogger()), st, clk
+}
+
+func recordingResponse(refID string) *backend.QueryDataResponse {
+	frame := data.NewFrame("",
+		data.NewField("", data.Labels{"job": "test"}, []float64{1}),
+	)
+	return &backend.QueryDataResponse{
+		Responses: backend.Responses{refID: {Frames: data.Frames{frame}}},
+	}
+}
+
+func TestBackfiller_Create(t *testing.T) {
+	rule := models.RuleGen.With(
+		models.RuleMuts.WithAllRecordingRules(),
+		models.RuleMuts.WithInterval(time.Minute),
+	).GenerateRef()
+
+	t.Run("creates pending backfill with the evaluations of the time range", func(t *testing.T) {
+		b, _, clk := newTestBackfiller(t, &eval_mocks.ConditionEvaluatorMock{}, writer.FakeWriter{}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		from := to.Add(-10 * time.Minute)
+
+		backfill, err := b.Create(context.Background(), rule, from, to, "admin")
+		require.NoError(t, err)
+		require.NotEmpty(t, backfill.UID)
+		require.Equal(t, models.BackfillPending, backfill.Status)
+		require.Equal(t, rule.UID, backfill.RuleUID)
+		require.Equal(t, from.UTC(), backfill.Next)
+		require.EqualValues(t, 10, backfill.Evaluations)
+		require.Equal(t, "admin", backfill.CreatedBy)
+	})
+
+	t.Run("fails if rule is not a recording rule", func(t *testing.T) {
+		alertRule := models.RuleGen.GenerateRef()
+		b, _, clk := newTestBackfiller(t, &eval_mocks.ConditionEvaluatorMock{}, writer.FakeWriter{}, alertRule)
+
+		_, err := b.Create(context.Background(), alertRule, clk.Now().Add(-time.Hour), clk.Now(), "admin")
+		require.ErrorIs(t, err, models.ErrRecordingRuleBackfillInvalid)
+	})
+
+	t.Run("fails if time range is in the future", func(t *testing.T) {
+		b, _, clk := newTestBackfiller(t, &eval_mocks.ConditionEvaluatorMock{}, writer.FakeWriter{}, rule)
+
+		_, err := b.Create(context.Background(), rule, clk.Now().Add(-time.Hour), clk.Now().Add(time.Hour), "admin")
+		require.ErrorIs(t, err, models.ErrRecordingRuleBackfillInvalid)
+	})
+
+	t.Run("fails if time range requires too many evaluations", func(t *testing.T) {
+		b, _, clk := newTestBackfiller(t, &eval_mocks.ConditionEvaluatorMock{}, writer.FakeWriter{}, rule)
+
+		_, err := b.Create(context.Background(), rule, clk.Now().Add(-24*time.Hour), clk.Now(), "admin")
+		require.ErrorIs(t, err, models.ErrRecordingRuleBackfillInvalid)
+	})
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	rule := models.RuleGen.With(
+		models.RuleMuts.WithAllRecordingRules(),
+		models.RuleMuts.WithoutTargetDataSource(),
+		models.RuleMuts.WithInterval(time.Minute),
+	).GenerateRef()
+
+	t.Run("writes the series of every evaluation with its timestamp", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(recordingResponse(rule.Record.From), nil)
+		var written []time.Time
+		w := writer.FakeWriter{WriteFunc: func(_ context.Context, name string, ts time.Time, _ data.Frames, orgID int64, _ map[string]string) error {
+			require.Equal(t, rule.Record.Metric, name)
+			require.Equal(t, rule.OrgID, orgID)
+			written = append(written, ts)
+			return nil
+		}}
+		b, st, clk := newTestBackfiller(t, evaluator, w, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-5*time.Minute), to, "admin")
+		require.NoError(t, err)
+
+		require.NoError(t, b.runPending(context.Background()))
+
+		require.Len(t, written, 5)
+		for i, ts := range written {
+			require.Equal(t, backfill.Next.Add(time.Duration(i)*time.Minute), ts.UTC())
+		}
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCompleted, result.Status)
+		require.EqualValues(t, 5, result.Completed)
+		require.Zero(t, result.Failed)
+	})
+
+	t.Run("counts failed evaluations and continues", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(nil, errors.New("query failed")).Once()
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(recordingResponse(rule.Record.From), nil)
+		b, st, clk := newTestBackfiller(t, evaluator, writer.FakeWriter{WriteFunc: func(context.Context, string, time.Time, data.Frames, int64, map[string]string) error {
+			return nil
+		}}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-3*time.Minute), to, "admin")
+		require.NoError(t, err)
+
+		require.NoError(t, b.runPending(context.Background()))
+
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCompleted, result.Status)
+		require.EqualValues(t, 3, result.Completed)
+		require.EqualValues(t, 1, result.Failed)
+		require.Contains(t, result.LastError, "query failed")
+	})
+
+	t.Run("fails after too many failures in a row and resumes from the last evaluation", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(recordingResponse(rule.Record.From), nil)
+		failing := true
+		b, st, clk := newTestBackfiller(t, evaluator, writer.FakeWriter{WriteFunc: func(context.Context, string, time.Time, data.Frames, int64, map[string]string) error {
+			if failing {
+				return errors.New("remote write failed")
+			}
+			return nil
+		}}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-20*time.Minute), to, "admin")
+		require.NoError(t, err)
+
+		require.NoError(t, b.runPending(context.Background()))
+
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillFailed, result.Status)
+		require.EqualValues(t, backfillMaxConsecutiveFailures, result.Completed)
+		require.Equal(t, backfill.Next.Add(backfillMaxConsecutiveFailures*time.Minute), result.Next)
+
+		failing = false
+		_, err = b.Resume(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.NoError(t, b.runPending(context.Background()))
+
+		result, err = st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCompleted, result.Status)
+		require.EqualValues(t, 20, result.Completed)
+	})
+
+	t.Run("does not run canceled backfill", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		b, st, clk := newTestBackfiller(t, evaluator, writer.FakeWriter{}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-5*time.Minute), to, "admin")
+		require.NoError(t, err)
+
+		_, err = b.Cancel(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.NoError(t, b.runPending(context.Background()))
+
+		evaluator.AssertNotCalled(t, "EvaluateRaw", mock.Anything, mock.Anything)
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCanceled, result.Status)
+
+		_, err = b.Cancel(context.Background(), rule.OrgID, backfill.UID)
+		require.ErrorIs(t, err, models.ErrRecordingRuleBackfillInvalid)
+	})
+
+	t.Run("saves the progress while an evaluation takes long", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		b, st, clk := newTestBackfiller(t, evaluator, writer.FakeWriter{WriteFunc: func(context.Context, string, time.Time, data.Frames, int64, map[string]string) error {
+			return nil
+		}}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-time.Minute), to, "admin")
+		require.NoError(t, err)
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, _ time.Time) (*backend.QueryDataResponse, error) {
+			claimed, err := st.GetRecordingRuleBackfill(ctx, rule.OrgID, backfill.UID)
+			require.NoError(t, err)
+			clk.Add(backfillProgressInterval)
+			require.Eventually(t, func() bool {
+				current, err := st.GetRecordingRuleBackfill(ctx, rule.OrgID, backfill.UID)
+				return err == nil && current.Updated.After(claimed.Updated)
+			}, time.Second, 10*time.Millisecond)
+			return recordingResponse(rule.Record.From), nil
+		})
+
+		require.NoError(t, b.runPending(context.Background()))
+
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCompleted, result.Status)
+	})
+
+	t.Run("stops when the backfill is canceled through another instance", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		b, st, clk := newTestBackfiller(t, evaluator, writer.FakeWriter{WriteFunc: func(context.Context, string, time.Time, data.Frames, int64, map[string]string) error {
+			return nil
+		}}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-5*time.Minute), to, "admin")
+		require.NoError(t, err)
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, _ time.Time) (*backend.QueryDataResponse, error) {
+			canceled, err := st.SetRecordingRuleBackfillStatus(ctx, rule.OrgID, backfill.UID, models.BackfillCanceled, models.BackfillRunning)
+			require.NoError(t, err)
+			require.True(t, canceled)
+			clk.Add(backfillProgressInterval)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+				t.Error("the backfill was not stopped")
+			}
+			return nil, ctx.Err()
+		})
+
+		require.NoError(t, b.runPending(context.Background()))
+
+		evaluator.AssertNumberOfCalls(t, "EvaluateRaw", 1)
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCanceled, result.Status)
+		require.Zero(t, result.Completed)
+	})
+
+	t.Run("takes over running backfill that was abandoned", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(recordingResponse(rule.Record.From), nil)
+		b, st, clk := newTestBackfiller(t, evaluator, writer.FakeWriter{WriteFunc: func(context.Context, string, time.Time, data.Frames, int64, map[string]string) error {
+			return nil
+		}}, rule)
+		to := clk.Now().Truncate(time.Minute)
+		backfill, err := b.Create(context.Background(), rule, to.Add(-5*time.Minute), to, "admin")
+		require.NoError(t, err)
+		st.Backfills[backfill.UID].Status = models.BackfillRunning
+		st.Backfills[backfill.UID].Updated = clk.Now()
+
+		require.NoError(t, b.runPending(context.Background()))
+		evaluator.AssertNotCalled(t, "EvaluateRaw", mock.Anything, mock.Anything)
+
+		st.Backfills[backfill.UID].Updated = clk.Now().Add(-2 * backfillStaleAfter)
+		require.NoError(t, b.runPending(context.Background()))
+
+		result, err := st.GetRecordingRuleBackfill(context.Background(), rule.OrgID, backfill.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.BackfillCompleted, result.Status)
+	})
+}
@@ -0,0 +1,139 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Comparison shows how a change of a rule alters its firing behaviour over the same time range.
+type Comparison struct {
+	// Result is the result of the changed rule.
+	Result *Result
+	// Baseline is the result of the rule the change is compared to.
+	Baseline *Result
+	// Differences are the alert instances that are in a different state than in the baseline, ordered by time.
+	Differences []StateDifference
+}
+
+// StateDifference is an alert instance that is in a different state than in the baseline at the time.
+// The state is empty if the alert instance does not exist.
+type StateDifference struct {
+	At            time.Time   `json:"at"`
+	Labels        data.Labels `json:"labels"`
+	State         string      `json:"state"`
+	BaselineState string      `json:"baselineState"`
+}
+
+// evaluationStates contains the state of the alert instances after an evaluation.
+type evaluationStates struct {
+	at     time.Time
+	states map[data.Fingerprint]instanceState
+}
+
+type instanceState struct {
+	labels data.Labels
+	state  eval.State
+}
+
+func newEvaluationStates(at time.Time, transitions state.StateTransitions) evaluationStates {
+	result := evaluationStates{at: at, states: make(map[data.Fingerprint]instanceState, len(transitions))}
+	for _, t := range transitions {
+		result.states[t.Labels.Fingerprint()] = instanceState{labels: t.Labels, state: t.State.State}
+	}
+	return result
+}
+
+// Compare tests the rule and the baseline over the same time range and returns the differences of their states.
+func (e *Engine) Compare(ctx context.Context, user identity.Requester, rule, baseline *models.AlertRule, from, to time.Time, folderTitle string) (*Comparison, error) {
+	return compare(ctx, e.Test, user, rule, baseline, from, to, folderTitle)
+}
+
+// CompareHistory is like Compare, but replays the state history of the rule for both, see Engine.TestHistory.
+func (e *Engine) CompareHistory(ctx context.Context, user identity.Requester, rule, baseline *models.AlertRule, from, to time.Time, folderTitle string) (*Comparison, error) {
+	return compare(ctx, e.TestHistory, user, rule, baseline, from, to, folderTitle)
+}
+
+type testFunc func(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (*Result, error)
+
+func compare(ctx context.Context, test testFunc, user identity.Requester, rule, baseline *models.AlertRule, from, to time.Time, folderTitle string) (*Comparison, error) {
+	result, err := test(ctx, user, rule, from, to, folderTitle)
+	if err != nil {
+		return nil, err
+	}
+	baselineResult, err := test(ctx, user, baseline, from, to, folderTitle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test the baseline rule: %w", err)
+	}
+	return &Comparison{
+		Result:      result,
+		Baseline:    baselineResult,
+		Differences: compareEvaluations(result.evaluations, baselineResult.evaluations),
+	}, nil
+}
+
+// compareEvaluations returns the differences between the states of the alert instances of both evaluation
+// sequences. The sequences can have different evaluation times, e.g. if the interval of the rule was changed,
+// so the states are compared at every evaluation of either sequence, using the latest evaluation of the other.
+// An alert instance that is Normal is considered the same as one that does not exist.
+func compareEvaluations(evaluations, baseline []evaluationStates) []StateDifference {
+	times := make([]time.Time, 0, len(evaluations)+len(baseline))
+	for _, e := range evaluations {
+		times = append(times, e.at)
+	}
+	for _, e := range baseline {
+		times = append(times, e.at)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	times = slices.CompactFunc(times, func(a, b time.Time) bool { return a.Equal(b) })
+
+	var result []StateDifference
+	for _, at := range times {
+		current, base := latestEvaluation(evaluations, at), latestEvaluation(baseline, at)
+		fingerprints := make([]data.Fingerprint, 0, len(current)+len(base))
+		for fp := range current {
+			fingerprints = append(fingerprints, fp)
+		}
+		for fp := range base {
+			if _, ok := current[fp]; !ok {
+				fingerprints = append(fingerprints, fp)
+			}
+		}
+		slices.Sort(fingerprints)
+		for _, fp := range fingerprints {
+			c, cok := current[fp]
+			b, bok := base[fp]
+			if c.state == b.state || (!cok && b.state == eval.Normal) || (!bok && c.state == eval.Normal) {
+				continue
+			}
+			d := StateDifference{At: at, Labels: c.labels}
+			if cok {
+				d.State = c.state.String()
+			}
+			if bok {
+				d.Labels = b.labels
+				d.BaselineState = b.state.String()
+			}
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// latestEvaluation returns the states of the latest evaluation at or before the time.
+func latestEvaluation(evaluations []evaluationStates, at time.Time) map[data.Fingerprint]instanceState {
+	idx := sort.Search(len(evaluations), func(i int) bool { return evaluations[i].at.After(at) })
+	if idx == 0 {
+		return nil
+	}
+	return evaluations[idx-1].states
+}
@@ -0,0 +1,64 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestCompareEvaluations(t *testing.T) {
+	a, b := data.Labels{"host": "a"}, data.Labels{"host": "b"}
+	evaluation := func(at int64, states ...*state.State) evaluationStates {
+		transitions := make(state.StateTransitions, 0, len(states))
+		for _, s := range states {
+			transitions = append(transitions, state.StateTransition{State: s})
+		}
+		return newEvaluationStates(time.Unix(at, 0), transitions)
+	}
+	st := func(labels data.Labels, s eval.State) *state.State {
+		return &state.State{Labels: labels, State: s}
+	}
+
+	t.Run("no differences for the same states", func(t *testing.T) {
+		evaluations := []evaluationStates{
+			evaluation(0, st(a, eval.Pending)),
+			evaluation(10, st(a, eval.Alerting)),
+		}
+		require.Empty(t, compareEvaluations(evaluations, evaluations))
+	})
+
+	t.Run("returns the instances in different states", func(t *testing.T) {
+		current := []evaluationStates{
+			evaluation(0, st(a, eval.Alerting), st(b, eval.Normal)),
+			evaluation(10, st(a, eval.Alerting), st(b, eval.Alerting)),
+		}
+		baseline := []evaluationStates{
+			evaluation(0, st(a, eval.Pending)),
+			evaluation(10, st(a, eval.Alerting)),
+		}
+		require.Equal(t, []StateDifference{
+			{At: time.Unix(0, 0), Labels: a, State: "Alerting", BaselineState: "Pending"},
+			{At: time.Unix(10, 0), Labels: b, State: "Alerting"},
+		}, compareEvaluations(current, baseline))
+	})
+
+	t.Run("compares evaluations at different times to the latest evaluation", func(t *testing.T) {
+		current := []evaluationStates{
+			evaluation(0, st(a, eval.Pending)),
+			evaluation(20, st(a, eval.Alerting)),
+		}
+		baseline := []evaluationStates{
+			evaluation(0, st(a, eval.Pending)),
+			evaluation(10, st(a, eval.Alerting)),
+			evaluation(20, st(a, eval.Alerting)),
+		}
+		require.Equal(t, []StateDifference{
+			{At: time.Unix(10, 0), Labels: a, State: "Pending", BaselineState: "Alerting"},
+		}, compareEvaluations(current, baseline))
+	})
+}
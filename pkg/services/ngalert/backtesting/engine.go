@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/prometheus/alertmanager/dispatch"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -29,6 +30,8 @@ import (
 
 var (
 	ErrInvalidInputData = errors.New("invalid input data")
+	// ErrHistoryNotAvailable is returned if the state history is replayed but the state historian cannot be queried.
+	ErrHistoryNotAvailable = errors.New("the state history is not available")
 
 	logger                      = log.New("ngalert.backtesting.engine")
 	backtestingEvaluatorFactory = newBacktestingEvaluator
@@ -48,6 +51,9 @@ type stateManager interface {
 type Engine struct {
 	evalFactory          eval.EvaluatorFactory
 	createStateManager   func() stateManager
+	amConfigs            AlertmanagerConfigProvider
+	history              historian.Querier
+	appURL               *url.URL
 	disableGrafanaFolder bool
 	featureToggles       featuremgmt.FeatureToggles
 	minInterval          time.Duration
@@ -56,22 +62,38 @@ type Engine struct {
 	maxEvaluations       int
 }
 
-func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, cfg setting.UnifiedAlertingSettings, toggles featuremgmt.FeatureToggles) *Engine {
+// Result is the outcome of replaying the evaluations of a rule through the state machine.
+type Result struct {
+	// States contains the state transitions in the format of the state history.
+	States *data.Frame
+	// Notifications contains the notifications that would have been sent by the contact points, in the order
+	// they were sent.
+	Notifications []Notification
+
+	// evaluations contains the state of the alert instances after each evaluation.
+	evaluations []evaluationStates
+}
+
+func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, cfg setting.UnifiedAlertingSettings, toggles featuremgmt.FeatureToggles, amConfigs AlertmanagerConfigProvider, history historian.Querier) *Engine {
 	return &Engine{
 		evalFactory: evalFactory,
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
-				Metrics:       nil,
-				ExternalURL:   appUrl,
-				InstanceStore: nil,
-				Images:        &NoopImageService{},
-				Clock:         clock.New(),
-				Historian:     nil,
-				Tracer:        tracer,
-				Log:           log.New("ngalert.state.manager"),
+				Metrics:           nil,
+				ExternalURL:       appUrl,
+				InstanceStore:     nil,
+				Images:            &NoopImageService{},
+				Clock:             clock.New(),
+				Historian:         nil,
+				Tracer:            tracer,
+				Log:               log.New("ngalert.state.manager"),
+				ResolvedRetention: cfg.ResolvedAlertRetention,
 			}
 			return state.NewManager(cfg, state.NewNoopPersister())
 		},
+		amConfigs:            amConfigs,
+		history:              history,
+		appURL:               appUrl,
 		disableGrafanaFolder: false,
 		featureToggles:       toggles,
 		minInterval:          cfg.MinInterval,
@@ -81,7 +103,60 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 	}
 }
 
-func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (res *data.Frame, err error) {
+// Test evaluates the rule over the time range and replays the results through the state machine, the same way
+// the scheduler does. It returns the state transitions and the notifications that would have been sent, see
+// notificationSimulator.
+func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (*Result, error) {
+	return e.test(ctx, rule, from, to, folderTitle, func(ctx context.Context, rule *models.AlertRule, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return backtestingEvaluatorFactory(ctx, e.evalFactory, user, rule.GetEvalCondition().WithSource("backtesting"), reader)
+	})
+}
+
+// TestHistory replays the state transitions recorded by the state historian for the rule over the time range
+// through the state machine, instead of evaluating the queries of the rule, see historyEvaluator. The rule must
+// have a UID. It returns the same result as Test.
+func (e *Engine) TestHistory(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (*Result, error) {
+	if rule == nil || rule.UID == "" {
+		return nil, fmt.Errorf("%w: the rule must have a UID to replay its state history", ErrInvalidInputData)
+	}
+	if e.history == nil {
+		return nil, ErrHistoryNotAvailable
+	}
+	frame, err := e.history.Query(ctx, models.HistoryQuery{
+		RuleUID:      rule.UID,
+		OrgID:        rule.OrgID,
+		From:         from.Add(-historyLookback),
+		To:           to,
+		Limit:        historyQueryLimit,
+		SignedInUser: user,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the state history: %w", err)
+	}
+	evaluator, err := newHistoryEvaluator(frame)
+	if err != nil {
+		return nil, err
+	}
+	var warns []string
+	if len(evaluator.transitions) >= historyQueryLimit {
+		warns = append(warns, fmt.Sprintf("The state history has more than %d state transitions, only the latest are replayed", historyQueryLimit))
+	}
+	return e.test(ctx, rule, from, to, folderTitle, func(context.Context, *models.AlertRule, eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}, warns...)
+}
+
+const (
+	// historyLookback is how long before the time range the state history is queried, to know the state of
+	// the alert instances at the start of the time range.
+	historyLookback = 24 * time.Hour
+	// historyQueryLimit is the maximum number of state transitions that are replayed.
+	historyQueryLimit = 10000
+)
+
+type evaluatorFactory func(ctx context.Context, rule *models.AlertRule, reader eval.AlertingResultsReader) (backtestingEvaluator, error)
+
+func (e *Engine) test(ctx context.Context, rule *models.AlertRule, from, to time.Time, folderTitle string, newEvaluator evaluatorFactory, warns ...string) (res *Result, err error) {
 	if rule == nil {
 		return nil, fmt.Errorf("%w: rule is not defined", ErrInvalidInputData)
 	}
@@ -92,7 +167,6 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ruleCtx).New("backtesting", util.GenerateShortUID())
 
-	var warns []string
 	if rule.GetInterval() < e.minInterval {
 		logger.Warn("Interval adjusted to minimal interval", "originalInterval", rule.GetInterval(), "adjustedInterval", e.minInterval)
 		rule = rule.Copy()
@@ -131,15 +205,10 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 
 	stateMgr := e.createStateManager()
 
-	evaluator, err := backtestingEvaluatorFactory(ruleCtx,
-		e.evalFactory,
-		user,
-		rule.GetEvalCondition().WithSource("backtesting"),
-		&schedule.AlertingResultsFromRuleState{
-			Manager: stateMgr,
-			Rule:    rule,
-		},
-	)
+	evaluator, err := newEvaluator(ruleCtx, rule, &schedule.AlertingResultsFromRuleState{
+		Manager: stateMgr,
+		Rule:    rule,
+	})
 	if err != nil {
 		return nil, errors.Join(ErrInvalidInputData, err)
 	}
@@ -175,6 +244,17 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	}
 	extraLabels := state.GetRuleExtraLabels(logger, rule, folderTitle, !e.disableGrafanaFolder, e.featureToggles)
 
+	var tree *dispatch.Route
+	if e.amConfigs != nil {
+		tree, err = e.loadRoutingTree(ruleCtx, rule.OrgID)
+		if err != nil {
+			logger.Warn("Failed to load the notification policies, notifications will not be routed", "error", err)
+			warns = append(warns, fmt.Sprintf("Failed to load the notification policies, notifications will not be routed and use the default timings: %s", err))
+		}
+	}
+	notifications := newNotificationSimulator(tree, e.appURL)
+
+	result := &Result{}
 	processFn := func(idx int, currentTime time.Time, results eval.Results) (bool, error) {
 		// init the builder. Do the best guess for the size of the result
 		if builder == nil {
@@ -183,10 +263,15 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 				builder.AddWarn(warn)
 			}
 		}
-		states, err := stateMgr.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, nil)
+		notifications.flush(currentTime)
+		send := func(_ context.Context, statesToSend state.StateTransitions) {
+			notifications.add(statesToSend, currentTime)
+		}
+		states, err := stateMgr.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, send)
 		if err != nil {
 			return false, err
 		}
+		result.evaluations = append(result.evaluations, newEvaluationStates(currentTime, states))
 		for _, s := range states {
 			if !historian.ShouldRecord(s) {
 				continue
@@ -207,7 +292,10 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	if builder == nil {
 		return nil, errors.New("no results were produced")
 	}
-	return builder.ToFrame(), nil
+	notifications.flush(to)
+	result.States = builder.ToFrame()
+	result.Notifications = notifications.notifications
+	return result, nil
 }
 
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
@@ -250,7 +338,7 @@ func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFact
 // NoopImageService is a no-op image service.
 type NoopImageService struct{}
 
-func (s *NoopImageService) NewImage(_ context.Context, _ *models.AlertRule) (*models.Image, error) {
+func (s *NoopImageService) NewImage(_ context.Context, _ *models.AlertRule, _ eval.Results) (*models.Image, error) {
 	return &models.Image{}, nil
 }
 
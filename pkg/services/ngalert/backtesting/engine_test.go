@@ -8,17 +8,23 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -211,14 +217,14 @@ func TestEvaluatorTest(t *testing.T) {
 			return states
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to, "")
+		res, err := engine.Test(context.Background(), nil, rule, from, to, "")
 		require.NoError(t, err)
-		expectedLen := frame.Rows()
+		expectedLen := res.States.Rows()
 		for i := 0; i < 100; i++ {
 			jitter := time.Duration(rand.Int63n(ruleInterval.Milliseconds())) * time.Millisecond
-			frame, err = engine.Test(context.Background(), nil, rule, from, to.Add(jitter), "")
+			res, err = engine.Test(context.Background(), nil, rule, from, to.Add(jitter), "")
 			require.NoError(t, err)
-			require.Equalf(t, expectedLen, frame.Rows(), "jitter %v caused result to be different that base-line", jitter)
+			require.Equalf(t, expectedLen, res.States.Rows(), "jitter %v caused result to be different that base-line", jitter)
 		}
 	})
 
@@ -248,12 +254,160 @@ func TestEvaluatorTest(t *testing.T) {
 	})
 }
 
+func TestEvaluatorTestNotifications(t *testing.T) {
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			return eval.Results{{State: eval.Alerting}}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	manager := &fakeStateManager{
+		stateCallback: func(now time.Time) []state.StateTransition {
+			switch {
+			case now.Unix() == 2:
+				return []state.StateTransition{
+					{State: &state.State{Labels: data.Labels{"team": "a"}, State: eval.Alerting, StartsAt: now, EndsAt: now.Add(time.Minute)}},
+					{State: &state.State{Labels: data.Labels{"team": "b"}, State: eval.Normal, StartsAt: now, EndsAt: now}},
+				}
+			case now.Unix() > 2 && now.Unix() < 12:
+				// the firing alert is sent again on every evaluation
+				return []state.StateTransition{
+					{State: &state.State{Labels: data.Labels{"team": "a"}, State: eval.Alerting, StartsAt: time.Unix(2, 0), EndsAt: now.Add(time.Minute)}},
+				}
+			case now.Unix() == 12:
+				return []state.StateTransition{
+					{State: &state.State{Labels: data.Labels{"team": "a"}, State: eval.Normal, StartsAt: time.Unix(2, 0), EndsAt: now}},
+				}
+			}
+			return nil
+		},
+	}
+	duration := func(d time.Duration) *model.Duration {
+		return new(model.Duration(d))
+	}
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			return manager
+		},
+		amConfigs: fakeAlertmanagerConfigProvider{
+			Receiver:       "default",
+			GroupWait:      duration(2 * time.Second),
+			GroupInterval:  duration(3 * time.Second),
+			RepeatInterval: duration(4 * time.Second),
+			Routes: []*apimodels.Route{
+				{Receiver: "team-a", ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "team", Value: "a"}}},
+			},
+		},
+		featureToggles: featuremgmt.WithFeatures(),
+		minInterval:    1 * time.Second,
+		baseInterval:   1 * time.Second,
+		jitterStrategy: schedule.JitterNever,
+		maxEvaluations: 10000,
+	}
+	gen := models.RuleGen
+	rule := gen.With(gen.WithInterval(time.Second)).GenerateRef()
+
+	res, err := engine.Test(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(20, 0), "")
+	require.NoError(t, err)
+
+	// The group of team a is flushed after group_wait, then every group_interval. It notifies when the alert
+	// starts firing, when the repeat_interval has passed, and when the alert resolves. The group of team b
+	// does not notify as its alert never fired.
+	require.Len(t, res.Notifications, 3)
+	times := make([]time.Time, 0, len(res.Notifications))
+	for _, n := range res.Notifications {
+		times = append(times, n.Time)
+		require.Equal(t, "team-a", n.Receiver)
+		require.Len(t, n.Alerts, 1)
+		require.Equal(t, "a", n.Alerts[0].Labels["team"])
+	}
+	require.Equal(t, []time.Time{time.Unix(4, 0), time.Unix(10, 0), time.Unix(13, 0)}, times)
+	require.Equal(t, "firing", res.Notifications[0].Alerts[0].Status)
+	require.Equal(t, "firing", res.Notifications[1].Alerts[0].Status)
+	require.Equal(t, "resolved", res.Notifications[2].Alerts[0].Status)
+}
+
+func TestEvaluatorTestHistory(t *testing.T) {
+	at := func(sec int64) time.Time { return time.Unix(sec, 0) }
+	history := &fakeHistorian{frame: historyFrame(t, map[time.Time]historian.LokiEntry{
+		at(10): {Previous: "Normal", Current: "Pending", InstanceLabels: map[string]string{"instance": "1"}},
+		at(20): {Previous: "Pending", Current: "Alerting", InstanceLabels: map[string]string{"instance": "1"}},
+	}, at(10), at(20))}
+
+	engine := NewEngine(nil, nil, tracing.InitializeTracerForTest(), setting.UnifiedAlertingSettings{
+		MinInterval:  10 * time.Second,
+		BaseInterval: 10 * time.Second,
+	}, featuremgmt.WithFeatures(), nil, history)
+	engine.jitterStrategy = schedule.JitterNever
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithUID("rule"), gen.WithInterval(10*time.Second), gen.WithFor(30*time.Second), gen.WithKeepFiringFor(0), gen.WithNoNotificationSettings()).GenerateRef()
+
+	t.Run("replays the state history with the pending period of the rule", func(t *testing.T) {
+		res, err := engine.TestHistory(context.Background(), nil, rule, at(0), at(50), "")
+		require.NoError(t, err)
+		require.Equal(t, "rule", history.query.RuleUID)
+		require.Equal(t, at(0).Add(-historyLookback), history.query.From)
+
+		states := make([]eval.State, 0, len(res.evaluations))
+		for _, e := range res.evaluations {
+			s := eval.Normal
+			for _, instance := range e.states {
+				require.Equal(t, "1", instance.labels["instance"])
+				s = instance.state
+			}
+			states = append(states, s)
+		}
+		require.Equal(t, []eval.State{eval.Normal, eval.Pending, eval.Pending, eval.Pending, eval.Alerting}, states)
+	})
+
+	t.Run("fails without a rule UID", func(t *testing.T) {
+		_, err := engine.TestHistory(context.Background(), nil, gen.With(gen.WithUID("")).GenerateRef(), at(0), at(50), "")
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("fails if the state history cannot be queried", func(t *testing.T) {
+		engine := NewEngine(nil, nil, tracing.InitializeTracerForTest(), setting.UnifiedAlertingSettings{}, featuremgmt.WithFeatures(), nil, nil)
+		_, err := engine.TestHistory(context.Background(), nil, rule, at(0), at(50), "")
+		require.ErrorIs(t, err, ErrHistoryNotAvailable)
+	})
+}
+
+type fakeHistorian struct {
+	frame *data.Frame
+	query models.HistoryQuery
+}
+
+func (f *fakeHistorian) Query(_ context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	f.query = query
+	return f.frame, nil
+}
+
+type fakeAlertmanagerConfigProvider apimodels.Route
+
+func (f fakeAlertmanagerConfigProvider) GetAlertmanagerConfiguration(_ context.Context, _ int64, _ bool) (apimodels.GettableUserConfig, error) {
+	route := apimodels.Route(f)
+	cfg := apimodels.GettableUserConfig{}
+	cfg.AlertmanagerConfig.Route = &route
+	return cfg, nil
+}
+
 type fakeStateManager struct {
 	stateCallback func(now time.Time) []state.StateTransition
 }
 
-func (f *fakeStateManager) ProcessEvalResults(_ context.Context, evaluatedAt time.Time, _ *models.AlertRule, _ eval.Results, _ data.Labels, _ state.Sender) (state.StateTransitions, error) {
-	return f.stateCallback(evaluatedAt), nil
+func (f *fakeStateManager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time, _ *models.AlertRule, _ eval.Results, _ data.Labels, send state.Sender) (state.StateTransitions, error) {
+	states := f.stateCallback(evaluatedAt)
+	if send != nil {
+		send(ctx, states)
+	}
+	return states, nil
 }
 
 func (f *fakeStateManager) GetStatesForRuleUID(_ context.Context, orgID int64, alertRuleUID string) []*state.State {
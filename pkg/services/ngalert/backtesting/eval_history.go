@@ -0,0 +1,176 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prometheusModel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+)
+
+// historyEvaluator replays the state transitions recorded by the state historian as the results of evaluations.
+// The state historian records only the transitions of the alert instances, so the result of an alert instance
+// at an evaluation is derived from the latest transition at or before the time of the evaluation:
+//   - Pending and Alerting are Alerting, Normal and Recovering are Normal.
+//   - States with the reason NoData or Error are NoData or Error, whatever the state the rule configured for them.
+//   - Alert instances that were resolved as missing series, paused, updated or deleted have no result.
+//
+// The conditions of the rule are not evaluated, so the replay tests changes to the pending period, keep firing for,
+// the handling of no data, errors and missing series, and the notifications of the rule.
+type historyEvaluator struct {
+	// transitions are the recorded transitions, ordered by time.
+	transitions []historyTransition
+}
+
+type historyTransition struct {
+	at     time.Time
+	labels data.Labels
+	// state is the result of the evaluation, nil if the alert instance has no result.
+	state  *eval.State
+	err    error
+	values map[string]eval.NumberValueCapture
+}
+
+// newHistoryEvaluator returns an evaluator of the state history frame returned by historian.Querier.
+func newHistoryEvaluator(frame *data.Frame) (*historyEvaluator, error) {
+	var times, lines *data.Field
+	for _, f := range frame.Fields {
+		switch f.Name {
+		case "time":
+			times = f
+		case "line":
+			lines = f
+		}
+	}
+	if times == nil || lines == nil {
+		return nil, fmt.Errorf("the state history has no time or line field")
+	}
+
+	result := &historyEvaluator{transitions: make([]historyTransition, 0, frame.Rows())}
+	for i := 0; i < frame.Rows(); i++ {
+		at, ok := times.At(i).(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T of the time of the state history", times.At(i))
+		}
+		line, ok := lines.At(i).(json.RawMessage)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T of the line of the state history", lines.At(i))
+		}
+		var entry historian.LokiEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse the state history: %w", err)
+		}
+		t, err := newHistoryTransition(at, entry)
+		if err != nil {
+			return nil, err
+		}
+		result.transitions = append(result.transitions, t)
+	}
+	slices.SortStableFunc(result.transitions, func(a, b historyTransition) int {
+		return a.at.Compare(b.at)
+	})
+	return result, nil
+}
+
+func newHistoryTransition(at time.Time, entry historian.LokiEntry) (historyTransition, error) {
+	current, reason, err := state.ParseFormattedState(entry.Current)
+	if err != nil {
+		return historyTransition{}, fmt.Errorf("failed to parse the state history: %w", err)
+	}
+
+	// The labels that the state manager adds to the results are removed, they are added again by the replay.
+	labels := make(data.Labels, len(entry.InstanceLabels))
+	for k, v := range entry.InstanceLabels {
+		if strings.HasPrefix(k, "__") || k == prometheusModel.AlertNameLabel || k == models.FolderTitleLabel {
+			continue
+		}
+		labels[k] = v
+	}
+
+	t := historyTransition{at: at, labels: labels}
+	var s eval.State
+	switch reason {
+	case models.StateReasonNoData:
+		s = eval.NoData
+	case models.StateReasonError:
+		s = eval.Error
+	case models.StateReasonMissingSeries, models.StateReasonPaused, models.StateReasonUpdated, models.StateReasonRuleDeleted:
+		return t, nil
+	default:
+		switch current {
+		case eval.Pending, eval.Alerting:
+			s = eval.Alerting
+		case eval.Normal, eval.Recovering:
+			s = eval.Normal
+		default:
+			s = current
+		}
+	}
+	t.state = &s
+	if s == eval.Error {
+		t.err = errors.New(entry.Error)
+	}
+
+	if entry.Values != nil && s != eval.NoData && s != eval.Error {
+		values, err := entry.Values.Map()
+		if err == nil {
+			t.values = make(map[string]eval.NumberValueCapture, len(values))
+			for refID := range values {
+				value, err := entry.Values.Get(refID).Float64()
+				if err != nil {
+					continue
+				}
+				t.values[refID] = eval.NumberValueCapture{Var: refID, Labels: labels, Value: &value}
+			}
+		}
+	}
+	return t, nil
+}
+
+func (h *historyEvaluator) Eval(_ context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
+	current := make(map[data.Fingerprint]historyTransition)
+	next := 0
+	for i := 0; i < evaluations; i++ {
+		now := from.Add(time.Duration(i) * interval)
+		for ; next < len(h.transitions) && !h.transitions[next].at.After(now); next++ {
+			t := h.transitions[next]
+			if t.state == nil {
+				delete(current, t.labels.Fingerprint())
+				continue
+			}
+			current[t.labels.Fingerprint()] = t
+		}
+
+		results := make(eval.Results, 0, len(current))
+		for _, t := range current {
+			results = append(results, eval.Result{
+				Instance:    t.labels,
+				State:       *t.state,
+				Error:       t.err,
+				Values:      t.values,
+				EvaluatedAt: now,
+			})
+		}
+		slices.SortFunc(results, func(a, b eval.Result) int {
+			return strings.Compare(a.Instance.String(), b.Instance.String())
+		})
+		cont, err := callback(i, now, results)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			break
+		}
+	}
+	return nil
+}
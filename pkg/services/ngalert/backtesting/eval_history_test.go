@@ -0,0 +1,83 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+)
+
+func historyFrame(t *testing.T, entries map[time.Time]historian.LokiEntry, order ...time.Time) *data.Frame {
+	t.Helper()
+	builder := historian.NewQueryResultBuilder(len(entries))
+	for _, at := range order {
+		require.NoError(t, builder.AddRow(at, entries[at], []byte("{}")))
+	}
+	return builder.ToFrame()
+}
+
+func TestHistoryEvaluator(t *testing.T) {
+	instance := func(v string) map[string]string {
+		return map[string]string{"instance": v, "alertname": "test", "grafana_folder": "folder", "__alert_rule_uid__": "rule"}
+	}
+	at := func(sec int64) time.Time { return time.Unix(sec, 0) }
+	entries := map[time.Time]historian.LokiEntry{
+		at(10): {Previous: "Normal", Current: "Pending", InstanceLabels: instance("1"), Values: simplejson.NewFromAny(map[string]any{"B": 5.0})},
+		at(15): {Previous: "Normal", Current: "Error", Error: "timeout", InstanceLabels: instance("2")},
+		at(20): {Previous: "Pending", Current: "Alerting", InstanceLabels: instance("1")},
+		at(30): {Previous: "Alerting", Current: "Alerting (NoData)", InstanceLabels: instance("1")},
+		at(40): {Previous: "Alerting (NoData)", Current: "Normal (MissingSeries)", InstanceLabels: instance("1")},
+	}
+	// the state history is not necessarily ordered by time
+	evaluator, err := newHistoryEvaluator(historyFrame(t, entries, at(10), at(20), at(30), at(40), at(15)))
+	require.NoError(t, err)
+
+	type instanceResult struct {
+		instance string
+		state    eval.State
+	}
+	var results [][]instanceResult
+	err = evaluator.Eval(context.Background(), at(0), 10*time.Second, 5, func(_ int, now time.Time, r eval.Results) (bool, error) {
+		evaluation := []instanceResult{}
+		for _, result := range r {
+			require.Equal(t, now, result.EvaluatedAt)
+			require.Equal(t, data.Labels{"instance": result.Instance["instance"]}, result.Instance, "labels added by the state manager should be removed")
+			if result.State == eval.Error {
+				require.EqualError(t, result.Error, "timeout")
+			}
+			evaluation = append(evaluation, instanceResult{instance: result.Instance["instance"], state: result.State})
+		}
+		results = append(results, evaluation)
+		return true, nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, [][]instanceResult{
+		{},
+		{{"1", eval.Alerting}},
+		{{"1", eval.Alerting}, {"2", eval.Error}},
+		{{"1", eval.NoData}, {"2", eval.Error}},
+		{{"2", eval.Error}},
+	}, results)
+}
+
+func TestHistoryTransitionValues(t *testing.T) {
+	transition, err := newHistoryTransition(time.Unix(0, 0), historian.LokiEntry{
+		Current:        "Alerting",
+		InstanceLabels: map[string]string{"instance": "1"},
+		Values:         simplejson.NewFromAny(map[string]any{"B": 5.0, "C": 1.0}),
+	})
+	require.NoError(t, err)
+	require.Len(t, transition.values, 2)
+	require.Equal(t, "B", transition.values["B"].Var)
+	require.Equal(t, 5.0, *transition.values["B"].Value)
+
+	_, err = newHistoryTransition(time.Unix(0, 0), historian.LokiEntry{Current: "Unknown"})
+	require.Error(t, err)
+}
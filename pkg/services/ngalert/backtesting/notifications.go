@@ -0,0 +1,234 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// AlertmanagerConfigProvider provides the Alertmanager configuration of an organization.
+type AlertmanagerConfigProvider interface {
+	GetAlertmanagerConfiguration(ctx context.Context, org int64, withAutogen bool) (apimodels.GettableUserConfig, error)
+}
+
+// Notification is a notification that a contact point would have sent.
+type Notification struct {
+	// Time is when the notification would have been sent.
+	Time     time.Time `json:"time"`
+	Receiver string    `json:"receiver"`
+	// Route identifies the notification policy in the policy tree.
+	Route string `json:"route"`
+	// GroupLabels are the labels the notification policy groups the alerts by.
+	GroupLabels data.Labels `json:"groupLabels"`
+	// Alerts are the alerts of the group at the time of the notification.
+	Alerts []NotificationAlert `json:"alerts"`
+}
+
+// NotificationAlert is an alert in a notification.
+type NotificationAlert struct {
+	Labels data.Labels `json:"labels"`
+	// Status is either "firing" or "resolved".
+	Status   string    `json:"status"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+}
+
+// loadRoutingTree returns the notification policy tree of the organization, including the
+// autogenerated policies of rules with simplified routing.
+func (e *Engine) loadRoutingTree(ctx context.Context, orgID int64) (*dispatch.Route, error) {
+	cfg, err := e.amConfigs.GetAlertmanagerConfiguration(ctx, orgID, true)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AlertmanagerConfig.Route == nil {
+		return nil, fmt.Errorf("the alertmanager configuration has no root notification policy")
+	}
+	return dispatch.NewRoute(cfg.AlertmanagerConfig.Route.AsAMRoute(), nil), nil
+}
+
+// notificationSimulator replays the alerts sent to the Alertmanager through the notification policies.
+// Like the dispatcher of the Alertmanager, it groups the alerts of each matching policy by the labels of the
+// policy, and flushes each group after the group_wait of the policy and then every group_interval. A flush
+// notifies if the group has new firing or resolved alerts, or if the repeat_interval of the policy has passed
+// since the last notification. Mute and active time intervals, silences and inhibition rules are not applied.
+type notificationSimulator struct {
+	tree   *dispatch.Route
+	appURL *url.URL
+	groups map[string]*notificationGroup
+	// log contains the last notification of each group, see nflog.
+	log           map[string]notificationLogEntry
+	notifications []Notification
+}
+
+type notificationGroup struct {
+	key    string
+	route  *dispatch.Route
+	labels data.Labels
+	alerts map[data.Fingerprint]NotificationAlert
+	// next is the time of the next flush of the group.
+	next    time.Time
+	flushed bool
+}
+
+type notificationLogEntry struct {
+	time     time.Time
+	firing   map[data.Fingerprint]struct{}
+	resolved map[data.Fingerprint]struct{}
+}
+
+// newNotificationSimulator returns a simulator for the policy tree. If tree is nil, all alerts are grouped
+// together with the default timers of the root policy, and the notifications have no receiver.
+func newNotificationSimulator(tree *dispatch.Route, appURL *url.URL) *notificationSimulator {
+	if tree == nil {
+		tree = &dispatch.Route{RouteOpts: dispatch.DefaultRouteOpts}
+	}
+	return &notificationSimulator{
+		tree:   tree,
+		appURL: appURL,
+		groups: make(map[string]*notificationGroup),
+		log:    make(map[string]notificationLogEntry),
+	}
+}
+
+// add adds the alerts the state manager sent to the Alertmanager at the time to the groups of the policies they match.
+func (s *notificationSimulator) add(states state.StateTransitions, now time.Time) {
+	for _, st := range states {
+		alert := state.StateToPostableAlert(st, s.appURL)
+		a := NotificationAlert{
+			Labels:   make(data.Labels, len(alert.Labels)),
+			StartsAt: time.Time(alert.StartsAt),
+			EndsAt:   time.Time(alert.EndsAt),
+		}
+		lset := make(model.LabelSet, len(alert.Labels))
+		for k, v := range alert.Labels {
+			a.Labels[k] = v
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+		for _, r := range s.tree.Match(lset) {
+			groupLabels := make(data.Labels)
+			for k, v := range a.Labels {
+				if _, ok := r.RouteOpts.GroupBy[model.LabelName(k)]; ok || r.RouteOpts.GroupByAll {
+					groupLabels[k] = v
+				}
+			}
+			key := r.Key() + ":" + groupLabels.String()
+			g, ok := s.groups[key]
+			if !ok {
+				g = &notificationGroup{
+					key:    key,
+					route:  r,
+					labels: groupLabels,
+					alerts: make(map[data.Fingerprint]NotificationAlert),
+					next:   now.Add(r.RouteOpts.GroupWait),
+				}
+				s.groups[key] = g
+			}
+			g.alerts[a.Labels.Fingerprint()] = a
+			// the group is flushed right away if the alert has been firing for longer than group_wait
+			if !g.flushed && a.StartsAt.Add(r.RouteOpts.GroupWait).Before(now) {
+				g.next = now
+			}
+		}
+	}
+}
+
+// flush flushes the groups whose timers fire at or before the time, in the order of the timers.
+func (s *notificationSimulator) flush(until time.Time) {
+	for {
+		var next *notificationGroup
+		for _, g := range s.groups {
+			if g.next.After(until) {
+				continue
+			}
+			if next == nil || g.next.Before(next.next) || g.next.Equal(next.next) && g.key < next.key {
+				next = g
+			}
+		}
+		if next == nil {
+			return
+		}
+		s.flushGroup(next)
+	}
+}
+
+func (s *notificationSimulator) flushGroup(g *notificationGroup) {
+	now := g.next
+	g.next = now.Add(g.route.RouteOpts.GroupInterval)
+	g.flushed = true
+
+	alerts := make([]NotificationAlert, 0, len(g.alerts))
+	firing := make(map[data.Fingerprint]struct{})
+	resolved := make(map[data.Fingerprint]struct{})
+	for fp, a := range g.alerts {
+		a.Status = "firing"
+		if !a.EndsAt.IsZero() && !a.EndsAt.After(now) {
+			a.Status = "resolved"
+			resolved[fp] = struct{}{}
+			// resolved alerts are removed from the group once they are flushed
+			delete(g.alerts, fp)
+		} else {
+			firing[fp] = struct{}{}
+		}
+		alerts = append(alerts, a)
+	}
+	if len(g.alerts) == 0 {
+		delete(s.groups, g.key)
+	}
+
+	logKey := g.key + "/" + g.route.RouteOpts.Receiver
+	entry, ok := s.log[logKey]
+	if !needsNotification(entry, ok, firing, resolved, now, g.route.RouteOpts.RepeatInterval) {
+		return
+	}
+	s.log[logKey] = notificationLogEntry{time: now, firing: firing, resolved: resolved}
+
+	slices.SortFunc(alerts, func(a, b NotificationAlert) int {
+		return strings.Compare(a.Labels.String(), b.Labels.String())
+	})
+	s.notifications = append(s.notifications, Notification{
+		Time:        now,
+		Receiver:    g.route.RouteOpts.Receiver,
+		Route:       g.route.Key(),
+		GroupLabels: g.labels,
+		Alerts:      alerts,
+	})
+}
+
+// needsNotification returns true if a group with the firing and resolved alerts notifies, given the last
+// notification of the group. It is the same as the deduplication of notifications in the Alertmanager,
+// with resolved notifications enabled.
+func needsNotification(entry notificationLogEntry, notified bool, firing, resolved map[data.Fingerprint]struct{}, now time.Time, repeatInterval time.Duration) bool {
+	if !notified {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, entry.firing) {
+		return true
+	}
+	if len(firing) == 0 {
+		// the alerts that fired and resolved since the last notification were never notified
+		return len(entry.firing) > 0
+	}
+	if !isSubset(resolved, entry.resolved) {
+		return true
+	}
+	return entry.time.Before(now.Add(-repeatInterval))
+}
+
+func isSubset(subset, set map[data.Fingerprint]struct{}) bool {
+	for fp := range subset {
+		if _, ok := set[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
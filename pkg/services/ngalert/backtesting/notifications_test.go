@@ -0,0 +1,73 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestNotificationSimulator(t *testing.T) {
+	tree := &dispatch.Route{RouteOpts: dispatch.RouteOpts{
+		Receiver:       "default",
+		GroupBy:        map[model.LabelName]struct{}{"team": {}},
+		GroupWait:      30 * time.Second,
+		GroupInterval:  5 * time.Minute,
+		RepeatInterval: 4 * time.Hour,
+	}}
+	start := time.Unix(0, 0)
+	firing := func(startsAt time.Time, lbls ...string) state.StateTransition {
+		return state.StateTransition{State: &state.State{
+			Labels:   data.Labels{"team": lbls[0], "instance": lbls[1]},
+			State:    eval.Alerting,
+			StartsAt: startsAt,
+			EndsAt:   startsAt.Add(time.Hour),
+		}}
+	}
+
+	t.Run("alerts are grouped by the labels of the policy", func(t *testing.T) {
+		s := newNotificationSimulator(tree, nil)
+		s.add(state.StateTransitions{firing(start, "a", "1"), firing(start, "b", "1")}, start)
+		s.add(state.StateTransitions{firing(start.Add(10*time.Second), "a", "2")}, start.Add(10*time.Second))
+		s.flush(start.Add(time.Minute))
+
+		require.Len(t, s.notifications, 2)
+		require.Equal(t, start.Add(30*time.Second), s.notifications[0].Time)
+		require.Equal(t, data.Labels{"team": "a"}, s.notifications[0].GroupLabels)
+		require.Len(t, s.notifications[0].Alerts, 2)
+		require.Equal(t, data.Labels{"team": "b"}, s.notifications[1].GroupLabels)
+		require.Len(t, s.notifications[1].Alerts, 1)
+
+		// a new alert in the group is notified at the next group_interval
+		s.add(state.StateTransitions{firing(start.Add(time.Minute), "a", "3")}, start.Add(time.Minute))
+		s.flush(start.Add(10 * time.Minute))
+		require.Len(t, s.notifications, 3)
+		require.Equal(t, start.Add(30*time.Second+5*time.Minute), s.notifications[2].Time)
+		require.Len(t, s.notifications[2].Alerts, 3)
+	})
+
+	t.Run("alerts that fired for longer than group_wait are flushed at once", func(t *testing.T) {
+		s := newNotificationSimulator(tree, nil)
+		now := start.Add(time.Hour)
+		s.add(state.StateTransitions{firing(start, "a", "1")}, now)
+		s.flush(now)
+		require.Len(t, s.notifications, 1)
+		require.Equal(t, now, s.notifications[0].Time)
+	})
+
+	t.Run("without a policy tree the alerts are grouped together with the default timings", func(t *testing.T) {
+		s := newNotificationSimulator(nil, nil)
+		s.add(state.StateTransitions{firing(start, "a", "1"), firing(start, "b", "1")}, start)
+		s.flush(start.Add(time.Minute))
+		require.Len(t, s.notifications, 1)
+		require.Equal(t, start.Add(dispatch.DefaultRouteOpts.GroupWait), s.notifications[0].Time)
+		require.Empty(t, s.notifications[0].Receiver)
+		require.Len(t, s.notifications[0].Alerts, 2)
+	})
+}
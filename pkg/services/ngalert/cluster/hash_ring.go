@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+// tokensPerMember is the number of points every member takes on the ring. The more points, the more even the
+// distribution of keys between the members.
+const tokensPerMember = 128
+
+type ringToken struct {
+	hash   uint64
+	member string
+}
+
+// hashRing assigns keys to the members of the cluster using consistent hashing. When a member joins or leaves the
+// cluster, only the keys of that member move to other members.
+type hashRing struct {
+	tokens []ringToken
+}
+
+func newHashRing(members []string) *hashRing {
+	tokens := make([]ringToken, 0, len(members)*tokensPerMember)
+	for _, m := range members {
+		for i := range tokensPerMember {
+			tokens = append(tokens, ringToken{hash: hashKey(m + "-" + strconv.Itoa(i)), member: m})
+		}
+	}
+	slices.SortFunc(tokens, func(a, b ringToken) int {
+		// Compare the members as well so that every node of the cluster builds the same ring even if hashes collide.
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.member, b.member))
+	})
+	return &hashRing{tokens: tokens}
+}
+
+// owner returns the member that owns the key, that is the member of the first token at or after the hash of the key.
+// It returns an empty string if the ring has no members.
+func (r *hashRing) owner(key string) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := hashKey(key)
+	i, _ := slices.BinarySearchFunc(r.tokens, h, func(t ringToken, h uint64) int {
+		return cmp.Compare(t.hash, h)
+	})
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.tokens[i].member
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return mix(h.Sum64())
+}
+
+// mix spreads the bits of the FNV hash, which are poorly distributed for short keys that differ only in the last
+// characters, such as the tokens of a member.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// MembershipProvider provides the members of the cluster.
+type MembershipProvider interface {
+	// Name returns the name of this node in the cluster.
+	Name() string
+	// Members returns the names of the active members of the cluster, including this node.
+	Members() []string
+	// WaitReady blocks until the cluster has settled or ctx is done.
+	WaitReady(ctx context.Context) error
+}
+
+// RuleSharder splits the evaluation of alert rules between the members of the cluster. Every rule is owned by
+// exactly one member, which is chosen by consistent hashing of the rule UID. When a member joins or leaves the
+// cluster, only the rules owned by that member move to other members.
+type RuleSharder struct {
+	cluster MembershipProvider
+	log     log.Logger
+
+	mtx     sync.RWMutex
+	members []string
+	ring    *hashRing
+}
+
+func NewRuleSharder(cluster MembershipProvider, logger log.Logger) (*RuleSharder, error) {
+	if cluster == nil {
+		return nil, errors.New("cluster membership provider is required")
+	}
+	return &RuleSharder{cluster: cluster, log: logger}, nil
+}
+
+// Run keeps the ring up to date with the members of the cluster until ctx is done. No rule is owned until the
+// cluster settles because before that every node sees only itself and would evaluate all rules.
+func (s *RuleSharder) Run(ctx context.Context) error {
+	s.log.Info("Waiting for cluster to settle before sharding alert rules")
+	if err := s.cluster.WaitReady(ctx); err != nil {
+		return nil
+	}
+	s.sync()
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.sync()
+		}
+	}
+}
+
+func (s *RuleSharder) sync() {
+	members := slices.Clone(s.cluster.Members())
+	slices.Sort(members)
+	members = slices.Compact(members)
+	// The node must always be on its own ring. Otherwise, the rules that other nodes assign to it are not evaluated
+	// by anyone until it shows up in the membership.
+	if self := s.cluster.Name(); !slices.Contains(members, self) {
+		members = append(members, self)
+		slices.Sort(members)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.ring != nil && slices.Equal(s.members, members) {
+		return
+	}
+	s.log.Info("Cluster members changed, rebalancing alert rules", "previous", s.members, "members", members)
+	s.members = members
+	s.ring = newHashRing(members)
+}
+
+// Owns returns true if this node evaluates the rule.
+func (s *RuleSharder) Owns(key ngmodels.AlertRuleKey) bool {
+	owner := s.Owner(key)
+	return owner != "" && owner == s.cluster.Name()
+}
+
+// Owner returns the name of the node that evaluates the rule, or an empty string if the cluster has not settled yet.
+func (s *RuleSharder) Owner(key ngmodels.AlertRuleKey) string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.ring == nil {
+		return ""
+	}
+	return s.ring.owner(key.UID)
+}
+
+// Name returns the name of this node in the cluster.
+func (s *RuleSharder) Name() string {
+	return s.cluster.Name()
+}
+
+// Members returns the sorted names of the nodes that rules are split between.
+func (s *RuleSharder) Members() []string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return slices.Clone(s.members)
+}
@@ -0,0 +1,182 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type mockMembershipProvider struct {
+	name    string
+	mtx     sync.Mutex
+	members []string
+	// ready gates WaitReady. When nil, WaitReady returns immediately.
+	ready chan struct{}
+}
+
+func (m *mockMembershipProvider) Name() string {
+	return m.name
+}
+
+func (m *mockMembershipProvider) Members() []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.members
+}
+
+func (m *mockMembershipProvider) setMembers(members ...string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.members = members
+}
+
+func (m *mockMembershipProvider) WaitReady(ctx context.Context) error {
+	if m.ready == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.ready:
+		return nil
+	}
+}
+
+func ruleKeys(n int) []ngmodels.AlertRuleKey {
+	keys := make([]ngmodels.AlertRuleKey, 0, n)
+	for i := range n {
+		keys = append(keys, ngmodels.AlertRuleKey{OrgID: 1, UID: fmt.Sprintf("rule-%d", i)})
+	}
+	return keys
+}
+
+func TestHashRing(t *testing.T) {
+	t.Run("returns no owner without members", func(t *testing.T) {
+		require.Empty(t, newHashRing(nil).owner("rule"))
+	})
+
+	t.Run("spreads keys between members", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c"})
+		counts := map[string]int{}
+		for _, key := range ruleKeys(3000) {
+			counts[ring.owner(key.UID)]++
+		}
+		require.Len(t, counts, 3)
+		for member, count := range counts {
+			require.InDelta(t, 1000, count, 300, "member %s owns too few or too many keys", member)
+		}
+	})
+
+	t.Run("moves only the keys of the member that joins or leaves", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b", "c", "d"})
+		for _, key := range ruleKeys(1000) {
+			owner := after.owner(key.UID)
+			if owner != "d" {
+				require.Equal(t, before.owner(key.UID), owner)
+			}
+		}
+	})
+
+	t.Run("does not depend on the order of members", func(t *testing.T) {
+		a := newHashRing([]string{"a", "b", "c"})
+		b := newHashRing([]string{"c", "a", "b"})
+		for _, key := range ruleKeys(100) {
+			require.Equal(t, a.owner(key.UID), b.owner(key.UID))
+		}
+	})
+}
+
+func TestNewRuleSharder(t *testing.T) {
+	sharder, err := NewRuleSharder(nil, log.NewNopLogger())
+	require.ErrorContains(t, err, "cluster membership provider is required")
+	require.Nil(t, sharder)
+}
+
+func TestRuleSharder(t *testing.T) {
+	t.Run("owns no rules until the cluster settles", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			provider := &mockMembershipProvider{name: "a", members: []string{"a"}, ready: make(chan struct{})}
+			sharder, err := NewRuleSharder(provider, log.NewNopLogger())
+			require.NoError(t, err)
+
+			go func() { _ = sharder.Run(t.Context()) }()
+			synctest.Wait()
+
+			key := ngmodels.AlertRuleKey{OrgID: 1, UID: "rule"}
+			require.False(t, sharder.Owns(key))
+			require.Empty(t, sharder.Owner(key))
+
+			close(provider.ready)
+			synctest.Wait()
+			require.True(t, sharder.Owns(key))
+			require.Equal(t, []string{"a"}, sharder.Members())
+		})
+	})
+
+	t.Run("every rule is owned by exactly one member", func(t *testing.T) {
+		members := []string{"a", "b", "c"}
+		sharders := make([]*RuleSharder, 0, len(members))
+		for _, name := range members {
+			sharder, err := NewRuleSharder(&mockMembershipProvider{name: name, members: members}, log.NewNopLogger())
+			require.NoError(t, err)
+			sharder.sync()
+			sharders = append(sharders, sharder)
+		}
+		for _, key := range ruleKeys(100) {
+			owners := 0
+			for _, sharder := range sharders {
+				if sharder.Owns(key) {
+					owners++
+					require.Equal(t, sharder.Name(), sharder.Owner(key))
+				}
+			}
+			require.Equal(t, 1, owners, "rule %s", key.UID)
+		}
+	})
+
+	t.Run("rebalances rules when members change", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			provider := &mockMembershipProvider{name: "a", members: []string{"a", "b"}}
+			sharder, err := NewRuleSharder(provider, log.NewNopLogger())
+			require.NoError(t, err)
+
+			go func() { _ = sharder.Run(t.Context()) }()
+			synctest.Wait()
+
+			owned := 0
+			keys := ruleKeys(100)
+			for _, key := range keys {
+				if sharder.Owns(key) {
+					owned++
+				}
+			}
+			require.Less(t, owned, len(keys))
+
+			provider.setMembers("a")
+			time.Sleep(checkInterval)
+			synctest.Wait()
+
+			require.Equal(t, []string{"a"}, sharder.Members())
+			for _, key := range keys {
+				require.True(t, sharder.Owns(key))
+			}
+		})
+	})
+
+	t.Run("keeps itself on the ring when missing from the members", func(t *testing.T) {
+		provider := &mockMembershipProvider{name: "a", members: []string{"b"}}
+		sharder, err := NewRuleSharder(provider, log.NewNopLogger())
+		require.NoError(t, err)
+		sharder.sync()
+		require.Equal(t, []string{"a", "b"}, sharder.Members())
+	})
+}
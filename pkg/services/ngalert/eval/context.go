@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 // AlertingResultsReader provides fingerprints of results that are in alerting state.
@@ -14,6 +15,12 @@ type AlertingResultsReader interface {
 	Read(ctx context.Context) map[data.Fingerprint]struct{}
 }
 
+// SeverityResultsReader provides the severity levels of the results of a threshold expression with severity
+// levels that are in alerting state. It is optionally implemented by an AlertingResultsReader.
+type SeverityResultsReader interface {
+	ReadSeverities(ctx context.Context) expr.LoadedSeverities
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx                   context.Context
@@ -55,6 +55,9 @@ type conditionEvaluator struct {
 	condition         models.Condition
 	evalTimeout       time.Duration
 	evalResultLimit   int
+	// captureQueryResults sets the results of all queries and expressions on the evaluation results,
+	// so they can be drawn in the images of the alerts.
+	captureQueryResults bool
 }
 
 func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (resp *backend.QueryDataResponse, err error) {
@@ -101,12 +104,20 @@ func (r *conditionEvaluator) Evaluate(ctx context.Context, scheduledAt time.Time
 	if err != nil {
 		return nil, err
 	}
-	return EvaluateAlert(response, r.condition, scheduledAt, start), nil
+	execResults := queryDataResponseToExecutionResults(r.condition, response)
+	results := evaluateExecutionResult(execResults, scheduledAt, start)
+	if r.captureQueryResults {
+		for i := range results {
+			results[i].Results = execResults.Results
+		}
+	}
+	return results, nil
 }
 
 type evaluatorImpl struct {
 	evaluationTimeout     time.Duration
 	evaluationResultLimit int
+	captureQueryResults   bool
 	dataSourceCache       datasources.CacheService
 	expressionService     expressionBuilder
 }
@@ -119,6 +130,7 @@ func NewEvaluatorFactory(
 	return &evaluatorImpl{
 		evaluationTimeout:     cfg.EvaluationTimeout,
 		evaluationResultLimit: cfg.EvaluationResultLimit,
+		captureQueryResults:   cfg.Screenshots.Capture && cfg.Screenshots.Renderer == setting.ScreenshotsRendererChart,
 		dataSourceCache:       datasourceCache,
 		expressionService:     expressionService,
 	}
@@ -161,6 +173,10 @@ type ExecutionResults struct {
 	// NoData contains the DatasourceUID for RefIDs that returned no data.
 	NoData map[string]string
 
+	// Severities contains the severity levels of the results of a threshold condition with severity levels
+	// that are at a level. It is keyed by the fingerprint of the labels of the result.
+	Severities map[data.Fingerprint]expr.SeverityThresholdResult
+
 	Error error
 }
 
@@ -268,6 +284,10 @@ type Result struct {
 	// as EvalMatches (from "classic condition"), and in the future from operations
 	// like SSE "math".
 	EvaluationString string
+
+	// Severity is the severity level of the instance, if the condition is a threshold with
+	// severity levels and the instance is at a level.
+	Severity *expr.SeverityThresholdResult
 }
 
 func NewResultFromError(err error, evaluatedAt time.Time, duration time.Duration) Result {
@@ -428,6 +448,25 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 					}
 				}
 			}
+
+			isSeverityThreshold, err := q.IsSeverityThresholdExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build query '%s': %w", q.RefID, err)
+			}
+			if isSeverityThreshold {
+				// like hysteresis, severity levels depend on the state of the results of the alert condition.
+				if q.RefID != condition.Condition {
+					return nil, fmt.Errorf("severity threshold '%s' is only allowed to be the alert condition", q.RefID)
+				}
+				if severityReader, ok := reader.(SeverityResultsReader); ok {
+					loaded := severityReader.ReadSeverities(ctx.Ctx)
+					logger.FromContext(ctx.Ctx).Debug("Detected severity threshold command. Populating with the results", "items", len(loaded))
+					err = q.PatchSeverityThresholdExpression(loaded)
+					if err != nil {
+						return nil, fmt.Errorf("failed to amend severity threshold command '%s': %w", q.RefID, err)
+					}
+				}
+			}
 		}
 
 		model, err := q.GetModel()
@@ -566,12 +605,35 @@ func queryDataResponseToExecutionResults(c models.Condition, execResp *backend.Q
 		result.Results[refID] = res.Frames
 	}
 
+	// must be read before the metadata of the frames is replaced with the capture values.
+	result.Severities = severities(result.Condition)
+
 	// add capture values as data frame metadata to each result (frame) that has matching labels.
 	attachCaptureValues(result.Condition, captures)
 
 	return result
 }
 
+// severities returns the severity levels set by a threshold with severity levels
+// on the condition frames, keyed by the fingerprint of the labels of the frame.
+func severities(frames data.Frames) map[data.Fingerprint]expr.SeverityThresholdResult {
+	var result map[data.Fingerprint]expr.SeverityThresholdResult
+	for _, frame := range frames {
+		if frame.Meta == nil || len(frame.Fields) != 1 {
+			continue
+		}
+		severity, ok := frame.Meta.Custom.(expr.SeverityThresholdResult)
+		if !ok || severity.Severity == "" {
+			continue
+		}
+		if result == nil {
+			result = make(map[data.Fingerprint]expr.SeverityThresholdResult)
+		}
+		result[frame.Fields[0].Labels.Fingerprint()] = severity
+	}
+	return result
+}
+
 // FindConditionError extracts the error from a query response that caused the given condition to fail.
 // If a condition failed because a node it depends on had an error, that error is returned instead.
 // It returns nil if there are no errors related to the condition.
@@ -767,6 +829,9 @@ func evaluateExecutionResult(execResults ExecutionResults, scheduledAt time.Time
 			Values:             extractValues(f),
 			State:              stateFromVal(val),
 		}
+		if severity, ok := execResults.Severities[r.Instance.Fingerprint()]; ok {
+			r.Severity = &severity
+		}
 
 		evalResults = append(evalResults, r)
 	}
@@ -867,11 +932,12 @@ func (e *evaluatorImpl) create(ctx context.Context, condition models.Condition,
 	for _, node := range pipeline {
 		if node.RefID() == condition.Condition {
 			return &conditionEvaluator{
-				pipeline:          pipeline,
-				expressionService: e.expressionService,
-				condition:         condition,
-				evalTimeout:       e.evaluationTimeout,
-				evalResultLimit:   e.evaluationResultLimit,
+				pipeline:            pipeline,
+				expressionService:   e.expressionService,
+				condition:           condition,
+				evalTimeout:         e.evaluationTimeout,
+				evalResultLimit:     e.evaluationResultLimit,
+				captureQueryResults: e.captureQueryResults,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
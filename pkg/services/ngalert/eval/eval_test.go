@@ -603,6 +603,8 @@ func TestValidate(t *testing.T) {
 				nil,
 				tracing.InitializeTracerForTest(),
 				dsquerierclient.NewNullQSDatasourceClientBuilder(),
+				nil, // library
+				nil, // ruleStates
 			)
 			validator := NewConditionValidator(cacheService, expressions, store)
 			evalCtx := NewContext(context.Background(), u)
@@ -733,6 +735,8 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 					nil,
 					tracing.InitializeTracerForTest(),
 					dsquerierclient.NewNullQSDatasourceClientBuilder(),
+					nil, // library
+					nil, // ruleStates
 				),
 			)
 			evalCtx := NewContextWithPreviousResults(context.Background(), u, testCase.reader)
@@ -815,6 +819,49 @@ func TestQueryDataResponseToExecutionResults(t *testing.T) {
 		require.True(t, result.Values["A"].IsDatasourceNode)
 		require.False(t, result.Values["B"].IsDatasourceNode)
 	})
+
+	t.Run("should set recovery streak of severity thresholds", func(t *testing.T) {
+		c := models.Condition{
+			Condition: "B",
+			Data: []models.AlertQuery{
+				{
+					RefID:         "A",
+					DatasourceUID: "test-ds",
+				},
+				{
+					RefID:         "B",
+					DatasourceUID: expr.DatasourceUID,
+				},
+			},
+		}
+
+		labels := data.Labels{"foo": "bar"}
+		execResp := &backend.QueryDataResponse{
+			Responses: backend.Responses{
+				"A": {
+					Frames: []*data.Frame{
+						data.NewFrame("", data.NewField("Value", data.Labels{"foo": "bar"}, []*float64{new(10.0)})),
+					},
+				},
+				"B": {
+					Frames: []*data.Frame{
+						data.NewFrame("", data.NewField("Value", labels, []*float64{new(1.0)})).SetMeta(&data.FrameMeta{
+							Custom: expr.SeverityThresholdResult{Severity: "critical", RecoveryStreak: 2, Label: "severity"},
+						}),
+					},
+				},
+			},
+		}
+
+		results := queryDataResponseToExecutionResults(c, execResp)
+		severity := expr.SeverityThresholdResult{Severity: "critical", RecoveryStreak: 2, Label: "severity"}
+		require.Equal(t, map[data.Fingerprint]expr.SeverityThresholdResult{labels.Fingerprint(): severity}, results.Severities)
+
+		evaluatedResults := evaluateExecutionResult(results, time.Now(), time.Now())
+		require.Len(t, evaluatedResults, 1)
+		require.Equal(t, &severity, evaluatedResults[0].Severity)
+		require.Contains(t, evaluatedResults[0].Values, "A")
+	})
 }
 
 func TestEvaluate(t *testing.T) {
@@ -1277,6 +1324,47 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestEvaluateCaptureQueryResults(t *testing.T) {
+	query := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{time.Unix(0, 0), time.Unix(60, 0)}),
+		data.NewField("Value", data.Labels{"foo": "bar"}, []*float64{new(5.0), new(15.0)}),
+	)
+	resp := backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"A": {Frames: data.Frames{query}},
+			"B": {Frames: data.Frames{data.NewFrame("", data.NewField("Value", data.Labels{"foo": "bar"}, []*float64{new(1.0)}))}},
+		},
+	}
+	evaluate := func(capture bool) Results {
+		ev := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					return &resp, nil
+				},
+			},
+			condition: models.Condition{
+				Condition: "B",
+				Data: []models.AlertQuery{
+					{RefID: "A", DatasourceUID: "test"},
+					{RefID: "B", DatasourceUID: expr.DatasourceUID},
+				},
+			},
+			captureQueryResults: capture,
+		}
+		results, err := ev.Evaluate(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return results
+	}
+
+	require.Nil(t, evaluate(false)[0].Results)
+
+	results := evaluate(true)
+	require.Equal(t, Alerting, results[0].State)
+	require.Equal(t, data.Frames{query}, results[0].Results["A"])
+	require.Contains(t, results[0].Results, "B")
+}
+
 func TestEvaluateRaw(t *testing.T) {
 	t.Run("should timeout if request takes too long", func(t *testing.T) {
 		unexpectedResponse := &backend.QueryDataResponse{}
@@ -0,0 +1,306 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image/chart"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ChartImageService draws a chart of the queries of the alert rule from the results of
+// the evaluation, and saves the image in the store. Unlike ScreenshotImageService, it
+// does not need the image renderer or the alert rule to be associated with a dashboard
+// panel. The results must contain the results of the queries, see
+// eval.NewEvaluatorFactory.
+type ChartImageService struct {
+	cache        CacheService
+	format       string
+	imagesDir    string
+	logger       log.Logger
+	singleflight singleflight.Group
+	store        store.ImageStore
+	uploads      *UploadingService
+}
+
+// NewChartImageService returns a new ChartImageService. The format is either
+// setting.ScreenshotsChartFormatPNG or setting.ScreenshotsChartFormatSVG.
+func NewChartImageService(
+	cache CacheService,
+	format string,
+	imagesDir string,
+	logger log.Logger,
+	store store.ImageStore,
+	uploads *UploadingService) ImageService {
+	return &ChartImageService{
+		cache:     cache,
+		format:    format,
+		imagesDir: imagesDir,
+		logger:    logger,
+		store:     store,
+		uploads:   uploads,
+	}
+}
+
+// NewImage returns a chart of the queries of the alert rule or an error. No series is
+// highlighted, see NewInstanceImage.
+func (s *ChartImageService) NewImage(ctx context.Context, r *models.AlertRule, results eval.Results) (*models.Image, error) {
+	return s.NewInstanceImage(ctx, r, results, nil)
+}
+
+// NewInstanceImage returns a chart of the queries of the alert rule for the alert instance
+// with the given labels, or an error.
+//
+// The chart contains the time series returned by the queries of the alert rule, and the
+// thresholds of its threshold and classic condition expressions. The series of the alert
+// instance, whose labels contain the labels of the instance, are highlighted and always
+// drawn. If the results do not have a time series to draw then a models.ErrNoImageData
+// error is returned.
+func (s *ChartImageService) NewInstanceImage(ctx context.Context, r *models.AlertRule, results eval.Results, instance data.Labels) (*models.Image, error) {
+	logger := s.logger.FromContext(ctx).New("rule_uid", r.UID)
+
+	// All results of an evaluation share the results of the queries.
+	if len(results) == 0 || len(results[0].Results) == 0 {
+		logger.Debug("Cannot draw chart for alert rule as the results do not contain the results of its queries")
+		return nil, models.ErrNoImageData
+	}
+
+	// A chart is drawn once per alert instance and evaluation of the alert rule.
+	key := fmt.Sprintf("%d/%s/%d/%s", r.OrgID, r.UID, results[0].EvaluatedAt.UnixNano(), instance.Fingerprint())
+	if image, ok := s.cache.Get(ctx, key); ok {
+		logger.Debug("Found cached image", "token", image.Token)
+		return &image, nil
+	}
+
+	result, err, _ := s.singleflight.Do(key, func() (any, error) {
+		c := newChart(r, results[0].Results, instance)
+		if len(c.Series) == 0 {
+			return nil, models.ErrNoImageData
+		}
+
+		path, err := s.writeChart(c)
+		if err != nil {
+			if errors.Is(err, chart.ErrNoData) {
+				return nil, models.ErrNoImageData
+			}
+			return nil, fmt.Errorf("failed to draw chart: %w", err)
+		}
+
+		logger.Debug("Drew chart", "path", path, "series", len(c.Series))
+		return uploadAndSaveImage(ctx, logger, s.uploads, s.store, models.Image{Path: path})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	image := result.(models.Image)
+	if err = s.cache.Set(ctx, key, image); err != nil {
+		s.logger.Warn("Failed to cache image",
+			"token", image.Token,
+			"error", err)
+	}
+
+	return &image, nil
+}
+
+// writeChart draws the chart in a new file in the images directory, and returns the path of the file.
+func (s *ChartImageService) writeChart(c chart.Chart) (string, error) {
+	var buf bytes.Buffer
+	draw := c.PNG
+	if s.format == setting.ScreenshotsChartFormatSVG {
+		draw = c.SVG
+	}
+	if err := draw(&buf); err != nil {
+		return "", err
+	}
+
+	name, err := util.GetRandomString(20)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(s.imagesDir, 0700); err != nil {
+		return "", err
+	}
+	path, err := filepath.Abs(filepath.Join(s.imagesDir, name+"."+s.format))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// newChart returns a chart of the time series returned by the queries of the alert rule,
+// with the thresholds of its expressions. The series of the alert instance are highlighted.
+func newChart(r *models.AlertRule, results map[string]data.Frames, instance data.Labels) chart.Chart {
+	c := chart.Chart{Title: r.Title}
+
+	var queries []string
+	for _, q := range r.Data {
+		if isExpr, _ := q.IsExpression(); isExpr {
+			c.Thresholds = append(c.Thresholds, thresholds(q)...)
+			continue
+		}
+		queries = append(queries, q.RefID)
+	}
+
+	for _, refID := range queries {
+		for _, frame := range results[refID] {
+			for _, s := range frameSeries(frame, instance) {
+				// the names of the series are prefixed with the Ref ID to tell the queries apart
+				if len(queries) > 1 {
+					s.Name = refID + ": " + s.Name
+				}
+				c.Series = append(c.Series, s)
+			}
+		}
+	}
+	return c
+}
+
+// frameSeries returns a series for each numeric field of a frame with a time field.
+// Frames without a time field, such as the results of instant queries, have no series.
+func frameSeries(frame *data.Frame, instance data.Labels) []chart.Series {
+	var timeField *data.Field
+	for _, f := range frame.Fields {
+		if f.Type() == data.FieldTypeTime || f.Type() == data.FieldTypeNullableTime {
+			timeField = f
+			break
+		}
+	}
+	if timeField == nil {
+		return nil
+	}
+
+	var result []chart.Series
+	for _, f := range frame.Fields {
+		if !f.Type().Numeric() {
+			continue
+		}
+		s := chart.Series{
+			Name:        seriesName(frame, f),
+			Points:      make([]chart.Point, 0, f.Len()),
+			Highlighted: isInstanceSeries(f.Labels, instance),
+		}
+		for i := 0; i < f.Len() && i < timeField.Len(); i++ {
+			var t time.Time
+			switch v := timeField.At(i).(type) {
+			case time.Time:
+				t = v
+			case *time.Time:
+				if v == nil {
+					continue
+				}
+				t = *v
+			}
+			value := math.NaN()
+			if v, err := f.NullableFloatAt(i); err == nil && v != nil {
+				value = *v
+			}
+			s.Points = append(s.Points, chart.Point{Time: t, Value: value})
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// isInstanceSeries returns true if the labels of the series contain the labels of the alert instance.
+// The labels of the series of a query can have more labels than the alert instance, such as __name__.
+func isInstanceSeries(labels, instance data.Labels) bool {
+	if len(instance) == 0 {
+		return false
+	}
+	for k, v := range instance {
+		if lv, ok := labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func seriesName(frame *data.Frame, f *data.Field) string {
+	if f.Config != nil && f.Config.DisplayNameFromDS != "" {
+		return f.Config.DisplayNameFromDS
+	}
+	if len(f.Labels) > 0 {
+		return "{" + f.Labels.String() + "}"
+	}
+	if frame.Name != "" {
+		return frame.Name
+	}
+	return f.Name
+}
+
+// thresholdModel is the part of the model of threshold and classic condition expressions
+// that contains the thresholds.
+type thresholdModel struct {
+	Type       string `json:"type"`
+	Conditions []struct {
+		Evaluator struct {
+			Type   string    `json:"type"`
+			Params []float64 `json:"params"`
+		} `json:"evaluator"`
+		Severity string `json:"severity"`
+	} `json:"conditions"`
+}
+
+// thresholdOperators are the operators of the thresholds that compare the value with a single parameter.
+var thresholdOperators = map[string]string{
+	"gt":  ">",
+	"lt":  "<",
+	"gte": ">=",
+	"lte": "<=",
+	"eq":  "=",
+	"ne":  "!=",
+}
+
+// thresholds returns the thresholds of a threshold or classic condition expression. The lower and
+// upper bounds of ranges are returned as two thresholds.
+func thresholds(q models.AlertQuery) []chart.Threshold {
+	var model thresholdModel
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return nil
+	}
+	if model.Type != "threshold" && model.Type != "classic_conditions" {
+		return nil
+	}
+
+	var result []chart.Threshold
+	for _, condition := range model.Conditions {
+		evaluator := condition.Evaluator
+		prefix := ""
+		if condition.Severity != "" {
+			prefix = condition.Severity + " "
+		}
+		if op, ok := thresholdOperators[evaluator.Type]; ok && len(evaluator.Params) > 0 {
+			result = append(result, chart.Threshold{Value: evaluator.Params[0], Label: prefix + op + " " + formatThreshold(evaluator.Params[0])})
+			continue
+		}
+		// ranges, such as within_range and outside_range
+		for _, p := range evaluator.Params {
+			result = append(result, chart.Threshold{Value: p, Label: prefix + evaluator.Type + " " + formatThreshold(p)})
+		}
+	}
+	return result
+}
+
+func formatThreshold(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,364 @@
+// Package chart draws time series charts with threshold lines. It is used to create the images
+// of alert notifications from the results of the evaluation of the alert rules, without the
+// image renderer.
+package chart
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 400
+
+	// MaxSeries is the maximum number of series drawn in a chart. Highlighted series are kept, and
+	// the other series after the limit are ignored.
+	MaxSeries = 10
+)
+
+// ErrNoData is returned when a chart has no series with values to draw.
+var ErrNoData = errors.New("chart has no data")
+
+// Point is a value of a series at a point in time.
+type Point struct {
+	Time time.Time
+	// Value is NaN when the series has no value at the time.
+	Value float64
+}
+
+// Series is a named series of points ordered by time.
+type Series struct {
+	Name   string
+	Points []Point
+	// Highlighted series are listed first in the legend and drawn with a wider line on top of the other series.
+	Highlighted bool
+}
+
+// Threshold is drawn as a horizontal line across the chart.
+type Threshold struct {
+	Value float64
+	Label string
+}
+
+// Chart is a time series chart. The times are drawn in UTC.
+type Chart struct {
+	Title      string
+	Series     []Series
+	Thresholds []Threshold
+	// Width and Height are the size of the chart in pixels. DefaultWidth and DefaultHeight are used if zero.
+	Width  int
+	Height int
+}
+
+var (
+	backgroundColor = color.RGBA{R: 0x18, G: 0x1b, B: 0x1f, A: 0xff}
+	gridColor       = color.RGBA{R: 0x2c, G: 0x32, B: 0x35, A: 0xff}
+	textColor       = color.RGBA{R: 0xcc, G: 0xcc, B: 0xdc, A: 0xff}
+	thresholdColor  = color.RGBA{R: 0xf2, G: 0x49, B: 0x5c, A: 0xff}
+
+	// seriesColors are the colors of the classic palette of Grafana.
+	seriesColors = []color.RGBA{
+		{R: 0x7e, G: 0xb2, B: 0x6d, A: 0xff},
+		{R: 0xea, G: 0xb8, B: 0x39, A: 0xff},
+		{R: 0x6e, G: 0xd0, B: 0xe0, A: 0xff},
+		{R: 0xef, G: 0x84, B: 0x3c, A: 0xff},
+		{R: 0xe2, G: 0x4d, B: 0x42, A: 0xff},
+		{R: 0x1f, G: 0x78, B: 0xc1, A: 0xff},
+		{R: 0xba, G: 0x43, B: 0xa9, A: 0xff},
+		{R: 0x70, G: 0x5d, B: 0xa0, A: 0xff},
+		{R: 0x50, G: 0x86, B: 0x42, A: 0xff},
+		{R: 0xcc, G: 0xa3, B: 0x00, A: 0xff},
+	}
+)
+
+const (
+	// charWidth and charHeight are the size of a character in pixels, see font.go.
+	charWidth  = 8
+	charHeight = 10
+
+	margin       = 12
+	axisWidth    = 72
+	legendHeight = 16
+)
+
+type point struct {
+	X, Y float64
+}
+
+type rect struct {
+	X, Y, W, H int
+	Color      color.RGBA
+}
+
+type line struct {
+	Points []point
+	Color  color.RGBA
+	Width  int
+	Dashed bool
+}
+
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// text is a single line of text. Y is the top of the text.
+type text struct {
+	X, Y   int
+	Text   string
+	Anchor anchor
+	Color  color.RGBA
+}
+
+// canvas is the list of shapes of a chart, drawn in order: rectangles first, then lines and text.
+type canvas struct {
+	Width, Height int
+	Rects         []rect
+	Lines         []line
+	Texts         []text
+}
+
+// layout computes the shapes of the chart, so the PNG and SVG images are drawn the same.
+func (c *Chart) layout() (*canvas, error) {
+	series := slices.Clone(c.Series)
+	slices.SortStableFunc(series, func(a, b Series) int {
+		switch {
+		case a.Highlighted == b.Highlighted:
+			return 0
+		case a.Highlighted:
+			return -1
+		default:
+			return 1
+		}
+	})
+	if len(series) > MaxSeries {
+		series = series[:MaxSeries]
+	}
+
+	minT, maxT, minV, maxV, ok := bounds(series, c.Thresholds)
+	if !ok {
+		return nil, ErrNoData
+	}
+
+	cv := &canvas{Width: c.Width, Height: c.Height}
+	if cv.Width <= 0 {
+		cv.Width = DefaultWidth
+	}
+	if cv.Height <= 0 {
+		cv.Height = DefaultHeight
+	}
+	cv.Rects = append(cv.Rects, rect{W: cv.Width, H: cv.Height, Color: backgroundColor})
+
+	top := margin
+	if c.Title != "" {
+		cv.Texts = append(cv.Texts, text{X: cv.Width / 2, Y: margin, Text: truncate(c.Title, (cv.Width-2*margin)/charWidth), Anchor: anchorMiddle, Color: textColor})
+		top += charHeight + margin
+	}
+	left, right := axisWidth, cv.Width-margin
+	bottom := cv.Height - margin - len(series)*legendHeight - charHeight - margin
+	if right-left < 2*axisWidth || bottom-top < 2*charHeight {
+		return nil, errors.New("chart is too small")
+	}
+
+	// values are drawn from bottom to top and times from left to right
+	lo, hi, step := valueTicks(minV, maxV, (bottom-top)/(4*charHeight))
+	start, end := minT, maxT
+	if start.Equal(end) {
+		start, end = start.Add(-time.Minute), end.Add(time.Minute)
+	}
+	x := func(t time.Time) float64 {
+		return float64(left) + float64(t.Sub(start))/float64(end.Sub(start))*float64(right-left)
+	}
+	y := func(v float64) float64 {
+		return float64(bottom) - (v-lo)/(hi-lo)*float64(bottom-top)
+	}
+
+	for i := 0; lo+float64(i)*step <= hi+step/2; i++ {
+		v := lo + float64(i)*step
+		cv.Lines = append(cv.Lines, line{Points: []point{{float64(left), y(v)}, {float64(right), y(v)}}, Color: gridColor, Width: 1})
+		cv.Texts = append(cv.Texts, text{X: left - charWidth, Y: int(y(v)) - charHeight/2, Text: formatValue(v, step), Anchor: anchorEnd, Color: textColor})
+	}
+	timeStep, timeFormat := timeTicks(start, end, (right-left)/(12*charWidth))
+	for t := start.Truncate(timeStep); !t.After(end); t = t.Add(timeStep) {
+		if t.Before(start) {
+			continue
+		}
+		cv.Lines = append(cv.Lines, line{Points: []point{{x(t), float64(top)}, {x(t), float64(bottom)}}, Color: gridColor, Width: 1})
+		cv.Texts = append(cv.Texts, text{X: int(x(t)), Y: bottom + margin/2, Text: t.UTC().Format(timeFormat), Anchor: anchorMiddle, Color: textColor})
+	}
+
+	// highlighted series are drawn last so that they are not hidden by the other series
+	for _, highlighted := range []bool{false, true} {
+		for i, s := range series {
+			if s.Highlighted != highlighted {
+				continue
+			}
+			col := seriesColors[i%len(seriesColors)]
+			width := 2
+			if s.Highlighted {
+				width = 4
+			}
+			var segment []point
+			for _, p := range s.Points {
+				if !isFinite(p.Value) {
+					cv.addSegment(segment, col, width)
+					segment = nil
+					continue
+				}
+				segment = append(segment, point{x(p.Time), y(p.Value)})
+			}
+			cv.addSegment(segment, col, width)
+		}
+	}
+
+	for i, s := range series {
+		col := seriesColors[i%len(seriesColors)]
+
+		legendY := bottom + charHeight + margin + i*legendHeight
+		cv.Rects = append(cv.Rects, rect{X: left, Y: legendY + charHeight/2 - 1, W: 2 * charWidth, H: 3, Color: col})
+		cv.Texts = append(cv.Texts, text{X: left + 3*charWidth, Y: legendY, Text: truncate(s.Name, (right-left)/charWidth-3), Color: textColor})
+	}
+
+	for _, th := range c.Thresholds {
+		if !isFinite(th.Value) {
+			continue
+		}
+		cv.Lines = append(cv.Lines, line{Points: []point{{float64(left), y(th.Value)}, {float64(right), y(th.Value)}}, Color: thresholdColor, Width: 2, Dashed: true})
+		if th.Label != "" {
+			cv.Texts = append(cv.Texts, text{X: right - margin/2, Y: int(y(th.Value)) - charHeight - 4, Text: th.Label, Anchor: anchorEnd, Color: thresholdColor})
+		}
+	}
+	return cv, nil
+}
+
+// addSegment adds a connected segment of a series. A segment of a single point is drawn as a dot.
+func (cv *canvas) addSegment(segment []point, col color.RGBA, width int) {
+	switch len(segment) {
+	case 0:
+	case 1:
+		cv.Rects = append(cv.Rects, rect{X: int(segment[0].X) - width, Y: int(segment[0].Y) - width, W: 2 * width, H: 2 * width, Color: col})
+	default:
+		cv.Lines = append(cv.Lines, line{Points: segment, Color: col, Width: width})
+	}
+}
+
+// bounds returns the time and value range of the series. The value range includes the thresholds.
+// It returns false if the series have no values.
+func bounds(series []Series, thresholds []Threshold) (minT, maxT time.Time, minV, maxV float64, ok bool) {
+	minV, maxV = math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			if !isFinite(p.Value) {
+				continue
+			}
+			if !ok || p.Time.Before(minT) {
+				minT = p.Time
+			}
+			if !ok || p.Time.After(maxT) {
+				maxT = p.Time
+			}
+			minV, maxV = math.Min(minV, p.Value), math.Max(maxV, p.Value)
+			ok = true
+		}
+	}
+	for _, th := range thresholds {
+		if isFinite(th.Value) {
+			minV, maxV = math.Min(minV, th.Value), math.Max(maxV, th.Value)
+		}
+	}
+	return minT, maxT, minV, maxV, ok
+}
+
+// valueTicks returns a range that contains min and max, and the step between at most n ticks
+// in the range. The step is 1, 2 or 5 times a power of 10.
+func valueTicks(minV, maxV float64, n int) (lo, hi, step float64) {
+	if minV == maxV {
+		pad := math.Abs(minV) / 10
+		if pad == 0 {
+			pad = 1
+		}
+		minV, maxV = minV-pad, maxV+pad
+	}
+	n = max(n, 2)
+	raw := (maxV - minV) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step = 10 * mag
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+	return math.Floor(minV/step) * step, math.Ceil(maxV/step) * step, step
+}
+
+var timeSteps = []time.Duration{
+	10 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 2 * 24 * time.Hour, 7 * 24 * time.Hour,
+}
+
+// timeTicks returns the step between at most n ticks between start and end, and the format of the ticks.
+func timeTicks(start, end time.Time, n int) (time.Duration, string) {
+	n = max(n, 1)
+	span := end.Sub(start)
+	step := timeSteps[len(timeSteps)-1]
+	for _, s := range timeSteps {
+		if span/s <= time.Duration(n) {
+			step = s
+			break
+		}
+	}
+	switch {
+	case step >= 24*time.Hour:
+		return step, "01-02"
+	case span >= 24*time.Hour:
+		return step, "01-02 15:04"
+	case step < time.Minute:
+		return step, "15:04:05"
+	default:
+		return step, "15:04"
+	}
+}
+
+// formatValue formats the value of a tick with the precision of the step.
+func formatValue(v, step float64) string {
+	if math.Abs(v) < step/2 {
+		return "0"
+	}
+	if math.Abs(v) >= 1e6 || math.Abs(v) < 1e-3 {
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	}
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 {
+		return ""
+	}
+	if len(r) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(r[:n])
+	}
+	return string(r[:n-3]) + "..."
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
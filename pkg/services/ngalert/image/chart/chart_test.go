@@ -0,0 +1,162 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testChart() Chart {
+	start := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	var cpu, memory Series
+	cpu.Name = `{instance="server-1"}`
+	memory.Name = `{instance="server-2"}`
+	for i := 0; i < 60; i++ {
+		t := start.Add(time.Duration(i) * time.Minute)
+		cpu.Points = append(cpu.Points, Point{Time: t, Value: 50 + 40*math.Sin(float64(i)/10)})
+		value := float64(i)
+		if i == 30 {
+			value = math.NaN()
+		}
+		memory.Points = append(memory.Points, Point{Time: t, Value: value})
+	}
+	return Chart{
+		Title:      "High CPU <usage>",
+		Series:     []Series{cpu, memory},
+		Thresholds: []Threshold{{Value: 80, Label: "> 80"}},
+	}
+}
+
+func TestPNG(t *testing.T) {
+	c := testChart()
+	var buf bytes.Buffer
+	require.NoError(t, c.PNG(&buf))
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, DefaultWidth, img.Bounds().Dx())
+	require.Equal(t, DefaultHeight, img.Bounds().Dy())
+
+	// the threshold is drawn in its own color
+	found := false
+	for y := 0; y < img.Bounds().Dy() && !found; y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if uint8(r>>8) == thresholdColor.R && uint8(g>>8) == thresholdColor.G && uint8(b>>8) == thresholdColor.B {
+				found = true
+				break
+			}
+		}
+	}
+	require.True(t, found, "the threshold should be drawn")
+}
+
+func TestSVG(t *testing.T) {
+	c := testChart()
+	c.Width, c.Height = 600, 300
+	var buf bytes.Buffer
+	require.NoError(t, c.SVG(&buf))
+
+	// the SVG must be well formed, including the escaped title
+	var svg struct {
+		Width     int `xml:"width,attr"`
+		Height    int `xml:"height,attr"`
+		Polylines []struct {
+			Dashes string `xml:"stroke-dasharray,attr"`
+		} `xml:"polyline"`
+		Texts []string `xml:"text"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &svg))
+	require.Equal(t, 600, svg.Width)
+	require.Equal(t, 300, svg.Height)
+	require.Contains(t, svg.Texts, "High CPU <usage>")
+	require.Contains(t, svg.Texts, "> 80")
+	require.Contains(t, svg.Texts, `{instance="server-1"}`)
+	require.Contains(t, svg.Texts, "12:00")
+
+	dashed := 0
+	for _, p := range svg.Polylines {
+		if p.Dashes != "" {
+			dashed++
+		}
+	}
+	require.Equal(t, 1, dashed, "only the threshold should be dashed")
+}
+
+func TestHighlightedSeries(t *testing.T) {
+	start := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	var c Chart
+	for i := 0; i < MaxSeries+2; i++ {
+		c.Series = append(c.Series, Series{
+			Name:   "series-" + strconv.Itoa(i),
+			Points: []Point{{Time: start, Value: float64(i)}, {Time: start.Add(time.Minute), Value: float64(i)}},
+		})
+	}
+	c.Series[MaxSeries+1].Highlighted = true
+
+	cv, err := c.layout()
+	require.NoError(t, err)
+
+	var legend []string
+	for _, txt := range cv.Texts {
+		if strings.HasPrefix(txt.Text, "series-") {
+			legend = append(legend, txt.Text)
+		}
+	}
+	require.Len(t, legend, MaxSeries)
+	assert.Equal(t, "series-11", legend[0], "the highlighted series should be kept and listed first")
+	assert.NotContains(t, legend, "series-10")
+
+	last := cv.Lines[len(cv.Lines)-1]
+	assert.Equal(t, 4, last.Width, "the highlighted series should be drawn last with a wider line")
+	assert.False(t, c.Series[0].Highlighted, "the series of the chart should not be reordered")
+}
+
+func TestNoData(t *testing.T) {
+	c := Chart{Series: []Series{{Name: "empty", Points: []Point{{Time: time.Now(), Value: math.NaN()}}}}}
+	require.ErrorIs(t, c.PNG(&bytes.Buffer{}), ErrNoData)
+	require.ErrorIs(t, c.SVG(&bytes.Buffer{}), ErrNoData)
+}
+
+func TestValueTicks(t *testing.T) {
+	testCases := []struct {
+		name             string
+		min, max         float64
+		lo, hi, step     float64
+		expectedMinLabel string
+	}{
+		{name: "percentages", min: 3, max: 97, lo: 0, hi: 100, step: 20, expectedMinLabel: "0"},
+		{name: "small values", min: 0.012, max: 0.048, lo: 0.01, hi: 0.05, step: 0.01, expectedMinLabel: "0.01"},
+		{name: "constant value", min: 5, max: 5, lo: 4.4, hi: 5.6, step: 0.2, expectedMinLabel: "4.4"},
+		{name: "negative values", min: -35, max: 12, lo: -40, hi: 20, step: 10, expectedMinLabel: "-40"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lo, hi, step := valueTicks(tc.min, tc.max, 5)
+			assert.InDelta(t, tc.lo, lo, 1e-9)
+			assert.InDelta(t, tc.hi, hi, 1e-9)
+			assert.InDelta(t, tc.step, step, 1e-9)
+			assert.Equal(t, tc.expectedMinLabel, formatValue(lo, step))
+		})
+	}
+}
+
+func TestTimeTicks(t *testing.T) {
+	start := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	step, format := timeTicks(start, start.Add(time.Hour), 6)
+	require.Equal(t, 10*time.Minute, step)
+	require.Equal(t, "15:04", format)
+
+	step, format = timeTicks(start, start.Add(2*24*time.Hour), 6)
+	require.Equal(t, 12*time.Hour, step)
+	require.Equal(t, "01-02 15:04", format)
+}
@@ -0,0 +1,119 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+)
+
+// glyph is a character of 3x5 pixels. Each row is 3 bits, the most significant bit is the left pixel.
+type glyph [5]uint8
+
+// fontScale is the size of a pixel of a glyph. Characters are separated by two pixels, so a
+// character is charWidth pixels wide and charHeight pixels high.
+const fontScale = 2
+
+// font contains the glyphs of the printable ASCII characters. Lower case letters are drawn in upper case,
+// and other characters as a question mark.
+var font = map[rune]glyph{
+	' ':  {0b000, 0b000, 0b000, 0b000, 0b000},
+	'0':  {0b111, 0b101, 0b101, 0b101, 0b111},
+	'1':  {0b010, 0b110, 0b010, 0b010, 0b111},
+	'2':  {0b111, 0b001, 0b111, 0b100, 0b111},
+	'3':  {0b111, 0b001, 0b111, 0b001, 0b111},
+	'4':  {0b101, 0b101, 0b111, 0b001, 0b001},
+	'5':  {0b111, 0b100, 0b111, 0b001, 0b111},
+	'6':  {0b111, 0b100, 0b111, 0b101, 0b111},
+	'7':  {0b111, 0b001, 0b001, 0b001, 0b001},
+	'8':  {0b111, 0b101, 0b111, 0b101, 0b111},
+	'9':  {0b111, 0b101, 0b111, 0b001, 0b111},
+	'A':  {0b010, 0b101, 0b111, 0b101, 0b101},
+	'B':  {0b110, 0b101, 0b110, 0b101, 0b110},
+	'C':  {0b011, 0b100, 0b100, 0b100, 0b011},
+	'D':  {0b110, 0b101, 0b101, 0b101, 0b110},
+	'E':  {0b111, 0b100, 0b110, 0b100, 0b111},
+	'F':  {0b111, 0b100, 0b110, 0b100, 0b100},
+	'G':  {0b011, 0b100, 0b101, 0b101, 0b011},
+	'H':  {0b101, 0b101, 0b111, 0b101, 0b101},
+	'I':  {0b111, 0b010, 0b010, 0b010, 0b111},
+	'J':  {0b001, 0b001, 0b001, 0b101, 0b010},
+	'K':  {0b101, 0b101, 0b110, 0b101, 0b101},
+	'L':  {0b100, 0b100, 0b100, 0b100, 0b111},
+	'M':  {0b101, 0b111, 0b111, 0b101, 0b101},
+	'N':  {0b101, 0b111, 0b111, 0b111, 0b101},
+	'O':  {0b010, 0b101, 0b101, 0b101, 0b010},
+	'P':  {0b110, 0b101, 0b110, 0b100, 0b100},
+	'Q':  {0b010, 0b101, 0b101, 0b111, 0b011},
+	'R':  {0b110, 0b101, 0b111, 0b110, 0b101},
+	'S':  {0b011, 0b100, 0b010, 0b001, 0b110},
+	'T':  {0b111, 0b010, 0b010, 0b010, 0b010},
+	'U':  {0b101, 0b101, 0b101, 0b101, 0b011},
+	'V':  {0b101, 0b101, 0b101, 0b010, 0b010},
+	'W':  {0b101, 0b101, 0b111, 0b111, 0b101},
+	'X':  {0b101, 0b101, 0b010, 0b101, 0b101},
+	'Y':  {0b101, 0b101, 0b010, 0b010, 0b010},
+	'Z':  {0b111, 0b001, 0b010, 0b100, 0b111},
+	'!':  {0b010, 0b010, 0b010, 0b000, 0b010},
+	'"':  {0b101, 0b101, 0b000, 0b000, 0b000},
+	'#':  {0b101, 0b111, 0b101, 0b111, 0b101},
+	'$':  {0b011, 0b110, 0b010, 0b011, 0b110},
+	'%':  {0b101, 0b001, 0b010, 0b100, 0b101},
+	'&':  {0b010, 0b101, 0b010, 0b101, 0b011},
+	'\'': {0b010, 0b010, 0b000, 0b000, 0b000},
+	'(':  {0b001, 0b010, 0b010, 0b010, 0b001},
+	')':  {0b100, 0b010, 0b010, 0b010, 0b100},
+	'*':  {0b101, 0b010, 0b101, 0b000, 0b000},
+	'+':  {0b000, 0b010, 0b111, 0b010, 0b000},
+	',':  {0b000, 0b000, 0b000, 0b010, 0b100},
+	'-':  {0b000, 0b000, 0b111, 0b000, 0b000},
+	'.':  {0b000, 0b000, 0b000, 0b000, 0b010},
+	'/':  {0b001, 0b001, 0b010, 0b100, 0b100},
+	':':  {0b000, 0b010, 0b000, 0b010, 0b000},
+	';':  {0b000, 0b010, 0b000, 0b010, 0b100},
+	'<':  {0b001, 0b010, 0b100, 0b010, 0b001},
+	'=':  {0b000, 0b111, 0b000, 0b111, 0b000},
+	'>':  {0b100, 0b010, 0b001, 0b010, 0b100},
+	'?':  {0b111, 0b001, 0b010, 0b000, 0b010},
+	'@':  {0b010, 0b101, 0b111, 0b100, 0b011},
+	'[':  {0b110, 0b100, 0b100, 0b100, 0b110},
+	'\\': {0b100, 0b100, 0b010, 0b001, 0b001},
+	']':  {0b011, 0b001, 0b001, 0b001, 0b011},
+	'^':  {0b010, 0b101, 0b000, 0b000, 0b000},
+	'_':  {0b000, 0b000, 0b000, 0b000, 0b111},
+	'`':  {0b100, 0b010, 0b000, 0b000, 0b000},
+	'{':  {0b011, 0b010, 0b100, 0b010, 0b011},
+	'|':  {0b010, 0b010, 0b010, 0b010, 0b010},
+	'}':  {0b110, 0b010, 0b001, 0b010, 0b110},
+	'~':  {0b000, 0b011, 0b110, 0b000, 0b000},
+}
+
+// drawText draws the text with its top left corner at x, y.
+func drawText(img *image.RGBA, x, y int, s string, col color.RGBA) {
+	for _, r := range s {
+		g, ok := font[unicode.ToUpper(r)]
+		if !ok {
+			g = font['?']
+		}
+		for row, bits := range g {
+			for column := 0; column < 3; column++ {
+				if bits&(0b100>>column) == 0 {
+					continue
+				}
+				for dy := 0; dy < fontScale; dy++ {
+					for dx := 0; dx < fontScale; dx++ {
+						setPixel(img, x+column*fontScale+dx, y+row*fontScale+dy, col)
+					}
+				}
+			}
+		}
+		x += charWidth
+	}
+}
+
+func textWidth(s string) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return n*charWidth - (charWidth - 3*fontScale)
+}
@@ -0,0 +1,100 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+// dashLength and gapLength are the number of pixels of the dashes of dashed lines and the gaps between them.
+const (
+	dashLength = 8
+	gapLength  = 6
+)
+
+// PNG draws the chart as a PNG image.
+func (c *Chart) PNG(w io.Writer) error {
+	cv, err := c.layout()
+	if err != nil {
+		return err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, cv.Width, cv.Height))
+	for _, r := range cv.Rects {
+		draw.Draw(img, image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H), &image.Uniform{C: r.Color}, image.Point{}, draw.Src)
+	}
+	for _, l := range cv.Lines {
+		drawLine(img, l)
+	}
+	for _, t := range cv.Texts {
+		x := t.X
+		switch t.Anchor {
+		case anchorMiddle:
+			x -= textWidth(t.Text) / 2
+		case anchorEnd:
+			x -= textWidth(t.Text)
+		}
+		drawText(img, x, t.Y, t.Text, t.Color)
+	}
+	return png.Encode(w, img)
+}
+
+// drawLine draws the segments of the line with Bresenham's algorithm.
+func drawLine(img *image.RGBA, l line) {
+	// step counts the pixels of the whole line so the dashes continue across segments
+	step := 0
+	for i := 1; i < len(l.Points); i++ {
+		x0, y0 := int(math.Round(l.Points[i-1].X)), int(math.Round(l.Points[i-1].Y))
+		x1, y1 := int(math.Round(l.Points[i].X)), int(math.Round(l.Points[i].Y))
+		dx, dy := abs(x1-x0), -abs(y1-y0)
+		sx, sy := 1, 1
+		if x0 > x1 {
+			sx = -1
+		}
+		if y0 > y1 {
+			sy = -1
+		}
+		e := dx + dy
+		for {
+			if !l.Dashed || step%(dashLength+gapLength) < dashLength {
+				for w := 0; w < l.Width; w++ {
+					// lines are thickened across their main direction
+					if dx >= -dy {
+						setPixel(img, x0, y0+w, l.Color)
+					} else {
+						setPixel(img, x0+w, y0, l.Color)
+					}
+				}
+			}
+			step++
+			if x0 == x1 && y0 == y1 {
+				break
+			}
+			e2 := 2 * e
+			if e2 >= dy {
+				e += dy
+				x0 += sx
+			}
+			if e2 <= dx {
+				e += dx
+				y0 += sy
+			}
+		}
+	}
+}
+
+func setPixel(img *image.RGBA, x, y int, col color.RGBA) {
+	if (image.Point{X: x, Y: y}).In(img.Rect) {
+		img.SetRGBA(x, y, col)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package chart
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SVG draws the chart as an SVG image.
+func (c *Chart) SVG(w io.Writer) error {
+	cv, err := c.layout()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="%d">`,
+		cv.Width, cv.Height, cv.Width, cv.Height, charHeight+2)
+	b.WriteString("\n")
+	for _, r := range cv.Rects {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, r.X, r.Y, r.W, r.H, hex(r.Color))
+		b.WriteString("\n")
+	}
+	for _, l := range cv.Lines {
+		points := make([]string, 0, len(l.Points))
+		for _, p := range l.Points {
+			points = append(points, formatCoordinate(p.X)+","+formatCoordinate(p.Y))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%d"`, strings.Join(points, " "), hex(l.Color), l.Width)
+		if l.Dashed {
+			fmt.Fprintf(&b, ` stroke-dasharray="%d,%d"`, dashLength, gapLength)
+		}
+		b.WriteString("/>\n")
+	}
+	for _, t := range cv.Texts {
+		a := "start"
+		switch t.Anchor {
+		case anchorMiddle:
+			a = "middle"
+		case anchorEnd:
+			a = "end"
+		}
+		// the y coordinate of text in SVG is its baseline
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s" text-anchor="%s">`, t.X, t.Y+charHeight, hex(t.Color), a)
+		if err := xml.EscapeText(&b, []byte(t.Text)); err != nil {
+			return err
+		}
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
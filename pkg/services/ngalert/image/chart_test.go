@@ -0,0 +1,149 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image/chart"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func chartRule() *models.AlertRule {
+	return &models.AlertRule{
+		OrgID: 1,
+		UID:   "foo",
+		Title: "High CPU",
+		Data: []models.AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr": "cpu"}`)},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "reduce", "expression": "A"}`)},
+			{RefID: "C", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "threshold", "expression": "B", "conditions": [{"evaluator": {"type": "gt", "params": [80]}}]}`)},
+		},
+	}
+}
+
+func chartResults(evaluatedAt time.Time) eval.Results {
+	frame := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{evaluatedAt.Add(-2 * time.Minute), evaluatedAt.Add(-time.Minute), evaluatedAt}),
+		data.NewField("Value", data.Labels{"instance": "server-1"}, []*float64{new(50.0), nil, new(90.0)}),
+	)
+	return eval.Results{{
+		State:       eval.Alerting,
+		EvaluatedAt: evaluatedAt,
+		Results: map[string]data.Frames{
+			"A": {frame},
+			"B": {data.NewFrame("", data.NewField("Value", data.Labels{"instance": "server-1"}, []*float64{new(90.0)}))},
+		},
+	}}
+}
+
+func TestChartImageService(t *testing.T) {
+	ctx := context.Background()
+	evaluatedAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("image is drawn, saved and cached", func(t *testing.T) {
+		images := store.NewFakeImageStore(t)
+		dir := t.TempDir()
+		s := NewChartImageService(NewInmemCacheService(time.Minute, prometheus.NewRegistry()),
+			setting.ScreenshotsChartFormatSVG, dir, log.NewNopLogger(), images, nil)
+
+		image, err := s.NewImage(ctx, chartRule(), chartResults(evaluatedAt))
+		require.NoError(t, err)
+		assert.Equal(t, dir, filepath.Dir(image.Path))
+		assert.Equal(t, ".svg", filepath.Ext(image.Path))
+		assert.NotEmpty(t, image.Token)
+
+		b, err := os.ReadFile(image.Path)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "High CPU")
+		assert.Contains(t, string(b), "&gt; 80")
+
+		saved, err := images.GetImage(ctx, image.Token)
+		require.NoError(t, err)
+		assert.Equal(t, image, saved)
+
+		// the same evaluation returns the cached image
+		cached, err := s.NewImage(ctx, chartRule(), chartResults(evaluatedAt))
+		require.NoError(t, err)
+		assert.Equal(t, image, cached)
+
+		next, err := s.NewImage(ctx, chartRule(), chartResults(evaluatedAt.Add(time.Minute)))
+		require.NoError(t, err)
+		assert.NotEqual(t, image.Path, next.Path)
+	})
+
+	t.Run("image is drawn for each alert instance", func(t *testing.T) {
+		s := NewChartImageService(NewInmemCacheService(time.Minute, prometheus.NewRegistry()),
+			setting.ScreenshotsChartFormatSVG, t.TempDir(), log.NewNopLogger(), store.NewFakeImageStore(t), nil).(*ChartImageService)
+
+		server1, err := s.NewInstanceImage(ctx, chartRule(), chartResults(evaluatedAt), data.Labels{"instance": "server-1"})
+		require.NoError(t, err)
+		server2, err := s.NewInstanceImage(ctx, chartRule(), chartResults(evaluatedAt), data.Labels{"instance": "server-2"})
+		require.NoError(t, err)
+		assert.NotEqual(t, server1.Path, server2.Path)
+
+		b, err := os.ReadFile(server1.Path)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `stroke-width="4"`, "the series of the alert instance should be highlighted")
+		b, err = os.ReadFile(server2.Path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), `stroke-width="4"`)
+	})
+
+	t.Run("error is returned when there is no data to draw", func(t *testing.T) {
+		s := NewChartImageService(&NoOpCacheService{}, setting.ScreenshotsChartFormatPNG, t.TempDir(),
+			log.NewNopLogger(), store.NewFakeImageStore(t), nil)
+
+		_, err := s.NewImage(ctx, chartRule(), eval.Results{{State: eval.Alerting, EvaluatedAt: evaluatedAt}})
+		assert.ErrorIs(t, err, models.ErrNoImageData)
+
+		// instant queries have no time series
+		results := chartResults(evaluatedAt)
+		results[0].Results["A"] = results[0].Results["B"]
+		_, err = s.NewImage(ctx, chartRule(), results)
+		assert.ErrorIs(t, err, models.ErrNoImageData)
+	})
+}
+
+func TestNewChart(t *testing.T) {
+	rule := chartRule()
+	rule.Data = append(rule.Data,
+		models.AlertQuery{RefID: "D", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr": "memory"}`)},
+		models.AlertQuery{RefID: "E", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "classic_conditions", "conditions": [{"evaluator": {"type": "within_range", "params": [10, 20]}}]}`)},
+		models.AlertQuery{RefID: "F", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "threshold", "expression": "B", "conditions": [{"severity": "critical", "evaluator": {"type": "gte", "params": [95]}}]}`)},
+	)
+	results := chartResults(time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC))[0].Results
+
+	c := newChart(rule, results, nil)
+	require.Equal(t, "High CPU", c.Title)
+	require.Len(t, c.Series, 1)
+	assert.Equal(t, `A: {instance=server-1}`, c.Series[0].Name)
+	assert.False(t, c.Series[0].Highlighted)
+	require.Len(t, c.Series[0].Points, 3)
+	assert.Equal(t, 50.0, c.Series[0].Points[0].Value)
+	assert.True(t, math.IsNaN(c.Series[0].Points[1].Value), "null values should be NaN")
+
+	c = newChart(rule, results, data.Labels{"instance": "server-1"})
+	assert.True(t, c.Series[0].Highlighted, "the series of the alert instance should be highlighted")
+
+	assert.Equal(t, []chart.Threshold{
+		{Value: 80, Label: "> 80"},
+		{Value: 10, Label: "within_range 10"},
+		{Value: 20, Label: "within_range 20"},
+		{Value: 95, Label: "critical >= 95"},
+	}, c.Thresholds)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/imguploader"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/rendering"
@@ -38,8 +39,9 @@ func ProvideDeleteExpiredService(store *store.DBstore) *DeleteExpiredService {
 }
 
 type ImageService interface {
-	// NewImage returns a new image for the alert instance.
-	NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error)
+	// NewImage returns a new image for the alert instance. The results are the results
+	// of the evaluation of the alert rule that requested the image.
+	NewImage(ctx context.Context, r *models.AlertRule, results eval.Results) (*models.Image, error)
 }
 
 // ScreenshotImageService takes screenshots of the alert rule and saves the
@@ -78,7 +80,9 @@ func NewScreenshotImageService(
 }
 
 // NewScreenshotImageServiceFromCfg returns a new ScreenshotImageService
-// from the configuration.
+// from the configuration. If the renderer of the screenshots is
+// setting.ScreenshotsRendererChart then a ChartImageService is returned
+// instead.
 func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds dashboards.DashboardService,
 	rs rendering.Service, r prometheus.Registerer) (ImageService, error) {
 	var (
@@ -92,9 +96,6 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 	// If screenshots are enabled
 	if cfg.UnifiedAlerting.Screenshots.Capture {
 		cache = NewInmemCacheService(screenshotCacheTTL, r)
-		limiter = screenshot.NewTokenRateLimiter(cfg.UnifiedAlerting.Screenshots.MaxConcurrentScreenshots)
-		screenshots = screenshot.NewHeadlessScreenshotService(cfg, ds, rs, r)
-		screenshotTimeout = cfg.UnifiedAlerting.Screenshots.CaptureTimeout
 
 		// Image uploading is an optional feature
 		if cfg.UnifiedAlerting.Screenshots.UploadExternalImageStorage {
@@ -104,6 +105,15 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 			}
 			uploads = NewUploadingService(m, r)
 		}
+
+		if cfg.UnifiedAlerting.Screenshots.Renderer == setting.ScreenshotsRendererChart {
+			return NewChartImageService(cache, cfg.UnifiedAlerting.Screenshots.ChartFormat, cfg.ImagesDir,
+				log.New("ngalert.image"), db, uploads), nil
+		}
+
+		limiter = screenshot.NewTokenRateLimiter(cfg.UnifiedAlerting.Screenshots.MaxConcurrentScreenshots)
+		screenshots = screenshot.NewHeadlessScreenshotService(cfg, ds, rs, r)
+		screenshotTimeout = cfg.UnifiedAlerting.Screenshots.CaptureTimeout
 	}
 
 	return NewScreenshotImageService(cache, limiter, log.New("ngalert.image"),
@@ -117,7 +127,7 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 // or the dashboard does not exist, a models.ErrNoDashboard error is returned. If the
 // alert rule has a Dashboard UID and the dashboard exists, but does not have a
 // Panel ID in its annotations then a models.ErrNoPanel error is returned.
-func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error) {
+func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRule, _ eval.Results) (*models.Image, error) {
 	logger := s.logger.FromContext(ctx)
 
 	dashboardUID := r.GetDashboardUID()
@@ -172,23 +182,7 @@ func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRu
 		}
 
 		logger.Debug("Took screenshot", "path", screenshot.Path)
-		image := models.Image{Path: screenshot.Path}
-
-		// Uploading images is optional
-		if s.uploads != nil {
-			if image, err = s.uploads.Upload(ctx, image); err != nil {
-				logger.Warn("Failed to upload image", "error", err)
-			} else {
-				logger.Debug("Uploaded image", "url", image.URL)
-			}
-		}
-
-		if err := s.store.SaveImage(ctx, &image); err != nil {
-			return nil, fmt.Errorf("failed to save image: %w", err)
-		}
-		logger.Debug("Saved image", "token", image.Token)
-
-		return image, nil
+		return uploadAndSaveImage(ctx, logger, s.uploads, s.store, models.Image{Path: screenshot.Path})
 	})
 	if err != nil {
 		return nil, err
@@ -203,3 +197,25 @@ func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRu
 
 	return &image, nil
 }
+
+// uploadAndSaveImage uploads the image if uploads is not nil, and then saves it in the store.
+// The image is saved even if it could not be uploaded.
+func uploadAndSaveImage(ctx context.Context, logger log.Logger, uploads *UploadingService, images store.ImageStore, image models.Image) (models.Image, error) {
+	// Uploading images is optional
+	if uploads != nil {
+		uploaded, err := uploads.Upload(ctx, image)
+		if err != nil {
+			logger.Warn("Failed to upload image", "error", err)
+		} else {
+			logger.Debug("Uploaded image", "url", uploaded.URL)
+		}
+		image = uploaded
+	}
+
+	if err := images.SaveImage(ctx, &image); err != nil {
+		return models.Image{}, fmt.Errorf("failed to save image: %w", err)
+	}
+	logger.Debug("Saved image", "token", image.Token)
+
+	return image, nil
+}
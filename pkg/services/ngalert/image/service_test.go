@@ -68,7 +68,7 @@ func TestScreenshotImageService(t *testing.T) {
 			OrgID:        1,
 			UID:          "foo",
 			DashboardUID: new("foo"),
-			PanelID:      new(int64(1))})
+			PanelID:      new(int64(1))}, nil)
 		require.NoError(t, err)
 		assert.Equal(t, expected, *image)
 	})
@@ -105,7 +105,7 @@ func TestScreenshotImageService(t *testing.T) {
 			OrgID:        1,
 			UID:          "bar",
 			DashboardUID: new("bar"),
-			PanelID:      new(int64(1))})
+			PanelID:      new(int64(1))}, nil)
 		require.NoError(t, err)
 		assert.Equal(t, expected, *image)
 	})
@@ -120,7 +120,7 @@ func TestScreenshotImageService(t *testing.T) {
 			OrgID:        1,
 			UID:          "baz",
 			DashboardUID: new("baz"),
-			PanelID:      new(int64(1))})
+			PanelID:      new(int64(1))}, nil)
 		require.NoError(t, err)
 		assert.Equal(t, expected, *image)
 	})
@@ -141,7 +141,7 @@ func TestScreenshotImageService(t *testing.T) {
 			OrgID:        1,
 			UID:          "qux",
 			DashboardUID: new("qux"),
-			PanelID:      new(int64(1))})
+			PanelID:      new(int64(1))}, nil)
 		assert.EqualError(t, err, "context deadline exceeded")
 		assert.Nil(t, image)
 	})
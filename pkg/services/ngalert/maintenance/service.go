@@ -0,0 +1,301 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// syncInterval is how often the maintenance windows are read from the database to create the silences of the
+// occurrences that started.
+const syncInterval = 30 * time.Second
+
+// SilenceStore creates and expires the silences of the maintenance windows.
+type SilenceStore interface {
+	CreateSilence(ctx context.Context, orgID int64, ps models.Silence) (string, error)
+	DeleteSilence(ctx context.Context, orgID int64, id string) error
+}
+
+// Service manages the maintenance windows. It creates a silence when an occurrence of a maintenance window starts,
+// and tells the scheduler which alert rules must not be evaluated.
+//
+// The silences expire at the end of the occurrences. When several instances run, the occurrence is claimed in the
+// database before the silence is created, so that only one silence is created per occurrence.
+type Service struct {
+	store    store.MaintenanceWindowStore
+	silences SilenceStore
+	clock    clock.Clock
+	log      log.Logger
+
+	mtx sync.RWMutex
+	// pausing are the windows that pause rule evaluation, by organization and UID.
+	pausing map[int64]map[string]*pausingWindow
+	// pending are the occurrences claimed by this instance for which the silence could not be created yet.
+	pending map[string]time.Time
+}
+
+func NewService(store store.MaintenanceWindowStore, silences SilenceStore, clock clock.Clock, log log.Logger) *Service {
+	return &Service{
+		store:    store,
+		silences: silences,
+		clock:    clock,
+		log:      log,
+		pausing:  map[int64]map[string]*pausingWindow{},
+		pending:  map[string]time.Time{},
+	}
+}
+
+// Run creates the silences of the maintenance windows until the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	s.log.Info("Starting maintenance window service", "interval", syncInterval)
+	ticker := s.clock.Ticker(syncInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.sync(ctx); err != nil {
+			s.log.Error("Failed to sync maintenance windows", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// PausesRule returns true if an active maintenance window of the organization pauses the evaluation of the alert
+// rules with the given labels.
+func (s *Service) PausesRule(orgID int64, lbls map[string]string) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	now := s.clock.Now()
+	for _, w := range s.pausing[orgID] {
+		if !w.window.MatchesLabels(lbls) {
+			continue
+		}
+		if _, _, active := w.schedule.ActiveOccurrence(now); active {
+			return true
+		}
+	}
+	return false
+}
+
+// pausingWindow is a window that pauses rule evaluation with its parsed schedule, because PausesRule is called for
+// every rule that is evaluated.
+type pausingWindow struct {
+	window   *models.MaintenanceWindow
+	schedule *models.MaintenanceSchedule
+}
+
+// newPausingWindow returns nil if the window does not pause rule evaluation or its schedule is not valid.
+func (s *Service) newPausingWindow(w *models.MaintenanceWindow) *pausingWindow {
+	if !w.PauseRuleEvaluation {
+		return nil
+	}
+	schedule, err := w.ParseSchedule()
+	if err != nil {
+		s.log.Error("Failed to parse the schedule of a maintenance window", "org", w.OrgID, "uid", w.UID, "error", err)
+		return nil
+	}
+	return &pausingWindow{window: w, schedule: schedule}
+}
+
+// ListMaintenanceWindows returns the maintenance windows of the organization.
+func (s *Service) ListMaintenanceWindows(ctx context.Context, orgID int64) ([]*models.MaintenanceWindow, error) {
+	return s.store.ListMaintenanceWindows(ctx, orgID)
+}
+
+// GetMaintenanceWindow returns the maintenance window of the organization.
+func (s *Service) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*models.MaintenanceWindow, error) {
+	return s.store.GetMaintenanceWindow(ctx, orgID, uid)
+}
+
+// CreateMaintenanceWindow creates the maintenance window. If an occurrence of the window is active,
+// its silence is created right away.
+func (s *Service) CreateMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	created, err := s.store.InsertMaintenanceWindow(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("Maintenance window created", "org", created.OrgID, "uid", created.UID, "title", created.Title, "user", created.CreatedBy)
+	return s.apply(ctx, created), nil
+}
+
+// UpdateMaintenanceWindow updates the definition of the maintenance window. The silence of the active occurrence,
+// if any, is expired and a new one is created with the new definition.
+func (s *Service) UpdateMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.store.GetMaintenanceWindow(ctx, w.OrgID, w.UID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.store.UpdateMaintenanceWindow(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("Maintenance window updated", "org", updated.OrgID, "uid", updated.UID, "title", updated.Title, "user", updated.UpdatedBy)
+	s.expireSilence(ctx, existing)
+	return s.apply(ctx, updated), nil
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window and expires the silence of its active occurrence, if any.
+func (s *Service) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, user string) error {
+	existing, err := s.store.GetMaintenanceWindow(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteMaintenanceWindow(ctx, orgID, uid); err != nil {
+		return err
+	}
+	s.log.Info("Maintenance window deleted", "org", orgID, "uid", uid, "title", existing.Title, "user", user)
+	s.expireSilence(ctx, existing)
+
+	s.mtx.Lock()
+	delete(s.pausing[orgID], uid)
+	s.mtx.Unlock()
+	return nil
+}
+
+// sync reads all maintenance windows, creates the silences of the active occurrences and refreshes the windows
+// that pause rule evaluation.
+func (s *Service) sync(ctx context.Context) error {
+	windows, err := s.store.ListMaintenanceWindows(ctx, 0)
+	if err != nil {
+		return err
+	}
+	pausing := map[int64]map[string]*pausingWindow{}
+	for _, w := range windows {
+		if err := s.ensureSilence(ctx, w); err != nil {
+			s.log.Error("Failed to create the silence of a maintenance window", "org", w.OrgID, "uid", w.UID, "error", err)
+		}
+		if pw := s.newPausingWindow(w); pw != nil {
+			if pausing[w.OrgID] == nil {
+				pausing[w.OrgID] = map[string]*pausingWindow{}
+			}
+			pausing[w.OrgID][w.UID] = pw
+		}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.pausing = pausing
+	return nil
+}
+
+// apply creates the silence of the active occurrence of a window that was just saved,
+// and updates whether it pauses rule evaluation.
+func (s *Service) apply(ctx context.Context, w *models.MaintenanceWindow) *models.MaintenanceWindow {
+	if err := s.ensureSilence(ctx, w); err != nil {
+		s.log.Error("Failed to create the silence of a maintenance window", "org", w.OrgID, "uid", w.UID, "error", err)
+	}
+
+	pw := s.newPausingWindow(w)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	orgWindows := maps.Clone(s.pausing[w.OrgID])
+	if orgWindows == nil {
+		orgWindows = map[string]*pausingWindow{}
+	}
+	if pw != nil {
+		orgWindows[w.UID] = pw
+	} else {
+		delete(orgWindows, w.UID)
+	}
+	s.pausing[w.OrgID] = orgWindows
+	return w
+}
+
+// ensureSilence creates the silence of the active occurrence of the window if it has not been created yet.
+func (s *Service) ensureSilence(ctx context.Context, w *models.MaintenanceWindow) error {
+	start, end, active, err := w.ActiveOccurrence(s.clock.Now())
+	if err != nil {
+		return err
+	}
+	if !active {
+		return nil
+	}
+
+	key := fmt.Sprintf("%d/%s", w.OrgID, w.UID)
+	if !w.LastOccurrence.Before(start) {
+		s.mtx.RLock()
+		retry := w.SilenceID == "" && s.pending[key].Equal(start)
+		s.mtx.RUnlock()
+		if !retry {
+			return nil
+		}
+	} else {
+		claimed, err := s.store.ClaimMaintenanceWindowOccurrence(ctx, w.OrgID, w.UID, start)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+		s.mtx.Lock()
+		s.pending[key] = start
+		s.mtx.Unlock()
+		w.LastOccurrence = start
+	}
+
+	id, err := s.silences.CreateSilence(ctx, w.OrgID, silenceForOccurrence(w, start, end))
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	delete(s.pending, key)
+	s.mtx.Unlock()
+	w.SilenceID = id
+	if err := s.store.SetMaintenanceWindowSilence(ctx, w.OrgID, w.UID, id); err != nil {
+		return err
+	}
+	s.log.Info("Created silence for maintenance window", "org", w.OrgID, "uid", w.UID, "title", w.Title, "silenceID", id, "startsAt", start, "endsAt", end)
+	return nil
+}
+
+// expireSilence expires the silence of the window if its occurrence has not ended yet.
+func (s *Service) expireSilence(ctx context.Context, w *models.MaintenanceWindow) {
+	if w.SilenceID == "" || !w.LastOccurrence.Add(w.Duration).After(s.clock.Now()) {
+		return
+	}
+	if err := s.silences.DeleteSilence(ctx, w.OrgID, w.SilenceID); err != nil {
+		s.log.Warn("Failed to expire the silence of a maintenance window", "org", w.OrgID, "uid", w.UID, "silenceID", w.SilenceID, "error", err)
+		return
+	}
+	s.log.Info("Expired silence of maintenance window", "org", w.OrgID, "uid", w.UID, "silenceID", w.SilenceID)
+}
+
+func silenceForOccurrence(w *models.MaintenanceWindow, start, end time.Time) models.Silence {
+	matchers := make(amv2.Matchers, 0, len(w.Matchers))
+	for _, m := range w.Matchers {
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    new(m.Name),
+			Value:   new(m.Value),
+			IsRegex: new(m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp),
+			IsEqual: new(m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp),
+		})
+	}
+	return models.Silence{
+		Silence: amv2.Silence{
+			Comment:   new(fmt.Sprintf("Maintenance window %q (UID %s), occurrence starting at %s", w.Title, w.UID, start.Format(time.RFC3339))),
+			CreatedBy: new("Maintenance window " + w.UID),
+			StartsAt:  new(strfmt.DateTime(start)),
+			EndsAt:    new(strfmt.DateTime(end)),
+			Matchers:  matchers,
+		},
+	}
+}
@@ -0,0 +1,190 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type fakeSilenceStore struct {
+	mtx      sync.Mutex
+	created  map[string]models.Silence
+	deleted  []string
+	failNext bool
+}
+
+func newFakeSilenceStore() *fakeSilenceStore {
+	return &fakeSilenceStore{created: map[string]models.Silence{}}
+}
+
+func (f *fakeSilenceStore) CreateSilence(_ context.Context, _ int64, ps models.Silence) (string, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.failNext {
+		f.failNext = false
+		return "", errors.New("alertmanager is not ready")
+	}
+	id := fmt.Sprintf("silence-%d", len(f.created)+1)
+	f.created[id] = ps
+	return id, nil
+}
+
+func (f *fakeSilenceStore) DeleteSilence(_ context.Context, _ int64, id string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func nightlyWindow(pause bool) models.MaintenanceWindow {
+	return models.MaintenanceWindow{
+		OrgID:               1,
+		Title:               "Nightly backup",
+		Schedule:            "RRULE:FREQ=DAILY;BYHOUR=2",
+		Duration:            time.Hour,
+		Matchers:            labels.Matchers{{Type: labels.MatchEqual, Name: "team", Value: "db"}},
+		PauseRuleEvaluation: pause,
+		CreatedBy:           "admin",
+	}
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	beforeOccurrence := time.Date(2026, time.March, 4, 1, 59, 0, 0, time.UTC)
+	occurrenceStart := time.Date(2026, time.March, 4, 2, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (*Service, *store.FakeMaintenanceWindowStore, *fakeSilenceStore, *clock.Mock) {
+		clk := clock.NewMock()
+		clk.Set(beforeOccurrence)
+		st := store.NewFakeMaintenanceWindowStore(t)
+		silences := newFakeSilenceStore()
+		return NewService(st, silences, clk, log.NewNopLogger()), st, silences, clk
+	}
+
+	t.Run("should create one silence when an occurrence starts", func(t *testing.T) {
+		svc, st, silences, clk := setup(t)
+		w, err := svc.CreateMaintenanceWindow(ctx, nightlyWindow(false))
+		require.NoError(t, err)
+		require.NoError(t, svc.sync(ctx))
+		require.Empty(t, silences.created)
+
+		clk.Set(occurrenceStart.Add(time.Minute))
+		require.NoError(t, svc.sync(ctx))
+		require.NoError(t, svc.sync(ctx))
+		require.Len(t, silences.created, 1)
+
+		silence := silences.created["silence-1"]
+		assert.Equal(t, occurrenceStart, time.Time(*silence.StartsAt))
+		assert.Equal(t, occurrenceStart.Add(time.Hour), time.Time(*silence.EndsAt))
+		assert.Equal(t, "Maintenance window "+w.UID, *silence.CreatedBy)
+		require.Len(t, silence.Matchers, 1)
+		assert.Equal(t, "team", *silence.Matchers[0].Name)
+		assert.Equal(t, "db", *silence.Matchers[0].Value)
+		assert.Equal(t, "silence-1", st.Windows[w.UID].SilenceID)
+
+		clk.Set(occurrenceStart.Add(24 * time.Hour))
+		require.NoError(t, svc.sync(ctx))
+		require.Len(t, silences.created, 2)
+	})
+
+	t.Run("should create one silence per occurrence when several instances share the store", func(t *testing.T) {
+		svc, st, silences, clk := setup(t)
+		other := NewService(st, silences, clk, log.NewNopLogger())
+		_, err := svc.CreateMaintenanceWindow(ctx, nightlyWindow(false))
+		require.NoError(t, err)
+
+		clk.Set(occurrenceStart)
+		require.NoError(t, svc.sync(ctx))
+		require.NoError(t, other.sync(ctx))
+		require.Len(t, silences.created, 1)
+	})
+
+	t.Run("should create the silence right away if the window is active", func(t *testing.T) {
+		svc, _, silences, clk := setup(t)
+		clk.Set(occurrenceStart.Add(30 * time.Minute))
+		_, err := svc.CreateMaintenanceWindow(ctx, nightlyWindow(false))
+		require.NoError(t, err)
+		require.Len(t, silences.created, 1)
+	})
+
+	t.Run("should retry creating the silence of a claimed occurrence", func(t *testing.T) {
+		svc, st, silences, clk := setup(t)
+		w, err := svc.CreateMaintenanceWindow(ctx, nightlyWindow(false))
+		require.NoError(t, err)
+
+		clk.Set(occurrenceStart)
+		silences.failNext = true
+		require.NoError(t, svc.sync(ctx))
+		require.Empty(t, silences.created)
+		require.Equal(t, occurrenceStart, st.Windows[w.UID].LastOccurrence)
+
+		require.NoError(t, svc.sync(ctx))
+		require.Len(t, silences.created, 1)
+	})
+
+	t.Run("should expire the silence when the window is updated or deleted", func(t *testing.T) {
+		svc, _, silences, clk := setup(t)
+		clk.Set(occurrenceStart)
+		w, err := svc.CreateMaintenanceWindow(ctx, nightlyWindow(false))
+		require.NoError(t, err)
+		require.Len(t, silences.created, 1)
+
+		update := nightlyWindow(false)
+		update.UID = w.UID
+		update.Matchers = labels.Matchers{{Type: labels.MatchEqual, Name: "team", Value: "storage"}}
+		_, err = svc.UpdateMaintenanceWindow(ctx, update)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"silence-1"}, silences.deleted)
+		require.Len(t, silences.created, 2)
+		assert.Equal(t, "storage", *silences.created["silence-2"].Matchers[0].Value)
+
+		require.NoError(t, svc.DeleteMaintenanceWindow(ctx, 1, w.UID, "admin"))
+		assert.Equal(t, []string{"silence-1", "silence-2"}, silences.deleted)
+		require.ErrorIs(t, svc.DeleteMaintenanceWindow(ctx, 1, w.UID, "admin"), models.ErrMaintenanceWindowNotFound)
+	})
+
+	t.Run("should reject invalid windows", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+		w := nightlyWindow(false)
+		w.Schedule = "RRULE:FREQ=YEARLY"
+		_, err := svc.CreateMaintenanceWindow(ctx, w)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowInvalid)
+	})
+
+	t.Run("should pause rules that match an active window", func(t *testing.T) {
+		svc, _, _, clk := setup(t)
+		_, err := svc.CreateMaintenanceWindow(ctx, nightlyWindow(true))
+		require.NoError(t, err)
+		_, err = svc.CreateMaintenanceWindow(ctx, func() models.MaintenanceWindow {
+			w := nightlyWindow(false)
+			w.Matchers = labels.Matchers{{Type: labels.MatchEqual, Name: "team", Value: "web"}}
+			return w
+		}())
+		require.NoError(t, err)
+
+		dbRule := map[string]string{"team": "db", "alertname": "Replication lag"}
+		webRule := map[string]string{"team": "web"}
+		assert.False(t, svc.PausesRule(1, dbRule))
+
+		clk.Set(occurrenceStart)
+		require.NoError(t, svc.sync(ctx))
+		assert.True(t, svc.PausesRule(1, dbRule))
+		assert.False(t, svc.PausesRule(1, webRule))
+		assert.False(t, svc.PausesRule(2, dbRule))
+
+		clk.Set(occurrenceStart.Add(time.Hour))
+		assert.False(t, svc.PausesRule(1, dbRule))
+	})
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// AcknowledgedByAnnotation is the annotation that contains the login of the user who acknowledged the alert.
+	AcknowledgedByAnnotation = GrafanaReservedLabelPrefix + "acknowledged_by"
+	// AssigneeAnnotation is the annotation that contains the login of the user the acknowledged alert is assigned to.
+	AssigneeAnnotation = GrafanaReservedLabelPrefix + "assignee"
+	// AcknowledgementNoteAnnotation is the annotation that contains the note of the acknowledgement.
+	AcknowledgementNoteAnnotation = GrafanaReservedLabelPrefix + "acknowledgement_note"
+	// AcknowledgedUntilAnnotation is the annotation that contains the time the acknowledgement expires at, in RFC 3339 format.
+	AcknowledgedUntilAnnotation = GrafanaReservedLabelPrefix + "acknowledged_until"
+
+	// MaxAcknowledgementNoteLength is the maximum number of characters of the note of an acknowledgement.
+	MaxAcknowledgementNoteLength = 1000
+)
+
+var (
+	// ErrAlertAcknowledgementInvalid is returned when an acknowledgement cannot be saved because it is not valid.
+	ErrAlertAcknowledgementInvalid = errors.New("invalid alert acknowledgement")
+	// ErrAlertAcknowledgementNotFound is returned when an alert instance is not acknowledged.
+	ErrAlertAcknowledgementNotFound = errors.New("alert acknowledgement not found")
+)
+
+// AlertAcknowledgement records that a user is handling a firing alert instance. While the acknowledgement is active,
+// the alert instance is still visible everywhere but no more notifications are sent for it. The acknowledgement
+// ends when it expires or when the alert instance stops firing.
+type AlertAcknowledgement struct {
+	AlertInstanceKey
+
+	// AcknowledgedBy is the login of the user who acknowledged the alert instance.
+	AcknowledgedBy string
+	// Assignee is the login of the user who owns the alert instance. It defaults to the user who acknowledged it.
+	Assignee string
+	Note     string
+	Created  time.Time
+	// ExpiresAt is the time the acknowledgement ends at. Nil means the acknowledgement lasts until the alert
+	// instance stops firing.
+	ExpiresAt *time.Time
+}
+
+// Validate checks that the acknowledgement is well-formed.
+func (a AlertAcknowledgement) Validate() error {
+	if a.AcknowledgedBy == "" {
+		return fmt.Errorf("%w: the user who acknowledged the alert must not be empty", ErrAlertAcknowledgementInvalid)
+	}
+	if len([]rune(a.Note)) > MaxAcknowledgementNoteLength {
+		return fmt.Errorf("%w: note must not be longer than %d characters", ErrAlertAcknowledgementInvalid, MaxAcknowledgementNoteLength)
+	}
+	if a.ExpiresAt != nil && !a.ExpiresAt.After(a.Created) {
+		return fmt.Errorf("%w: expiration time must be in the future", ErrAlertAcknowledgementInvalid)
+	}
+	return nil
+}
+
+// IsActive returns true if the acknowledgement has not expired at the given time.
+func (a *AlertAcknowledgement) IsActive(now time.Time) bool {
+	if a == nil {
+		return false
+	}
+	return a.ExpiresAt == nil || a.ExpiresAt.After(now)
+}
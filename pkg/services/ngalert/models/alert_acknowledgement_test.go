@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertAcknowledgement_Validate(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	valid := AlertAcknowledgement{AcknowledgedBy: "admin", Created: now, ExpiresAt: &future}
+	require.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(a *AlertAcknowledgement){
+		"without user":         func(a *AlertAcknowledgement) { a.AcknowledgedBy = "" },
+		"with long note":       func(a *AlertAcknowledgement) { a.Note = strings.Repeat("a", MaxAcknowledgementNoteLength+1) },
+		"expiring in the past": func(a *AlertAcknowledgement) { a.ExpiresAt = &past },
+	} {
+		t.Run(name, func(t *testing.T) {
+			a := valid
+			modify(&a)
+			require.ErrorIs(t, a.Validate(), ErrAlertAcknowledgementInvalid)
+		})
+	}
+}
+
+func TestAlertAcknowledgement_IsActive(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	var missing *AlertAcknowledgement
+	require.False(t, missing.IsActive(now))
+	require.True(t, (&AlertAcknowledgement{}).IsActive(now))
+	require.True(t, (&AlertAcknowledgement{ExpiresAt: &expiresAt}).IsActive(now))
+	require.False(t, (&AlertAcknowledgement{ExpiresAt: &expiresAt}).IsActive(expiresAt))
+}
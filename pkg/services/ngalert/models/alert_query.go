@@ -152,6 +152,43 @@ func (aq *AlertQuery) PatchHysteresisExpression(loadedMetrics map[data.Fingerpri
 	return expr.SetLoadedDimensionsToHysteresisCommand(aq.modelProps, loadedMetrics)
 }
 
+// IsSeverityThresholdExpression returns true if the model describes a threshold command expression with severity levels. Returns error if the Model is not a valid JSON
+func (aq *AlertQuery) IsSeverityThresholdExpression() (bool, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return false, err
+		}
+	}
+	return expr.IsSeverityThresholdExpression(aq.modelProps), nil
+}
+
+// PatchSeverityThresholdExpression updates the AlertQuery to include the severity levels of the previous results into the threshold
+func (aq *AlertQuery) PatchSeverityThresholdExpression(loaded expr.LoadedSeverities) error {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return err
+		}
+	}
+	return expr.SetLoadedSeveritiesToThresholdCommand(aq.modelProps, loaded)
+}
+
+// GetRuleStateDependency returns the UID of the alert rule the model reads the state of, if it describes a rule state command. Returns error if the Model is not a valid JSON
+func (aq *AlertQuery) GetRuleStateDependency() (string, bool, error) {
+	if isExpr, _ := aq.IsExpression(); !isExpr {
+		return "", false, nil
+	}
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return "", false, err
+		}
+	}
+	uid, ok := expr.GetRuleStateDependency(aq.modelProps)
+	return uid, ok, nil
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
@@ -145,6 +145,13 @@ const (
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
 
+	// SeverityStateAnnotation, SeverityLabelStateAnnotation and RecoveryStreakStateAnnotation are the names of the annotations that keep
+	// the severity level of an alert instance of a threshold with severity levels, the name of its label, and its recovery streak, in the
+	// saved state. They are not sent to the Alertmanager.
+	SeverityStateAnnotation       = "__severity__"
+	SeverityLabelStateAnnotation  = "__severity_label__"
+	RecoveryStreakStateAnnotation = "__recovery_streak__"
+
 	// MigratedLabelPrefix is a label prefix for all labels created during legacy migration.
 	MigratedLabelPrefix = "__legacy_"
 	// MigratedUseLegacyChannelsLabel is created during legacy migration to route to separate nested policies for migrated channels.
@@ -195,6 +202,10 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	// StateReasonAcknowledged and StateReasonUnacknowledged are only used in the state history, to record the
+	// acknowledgements of the alert instances.
+	StateReasonAcknowledged   = "Acknowledged"
+	StateReasonUnacknowledged = "Unacknowledged"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -392,6 +403,8 @@ type AlertRuleVersion struct {
 
 	// Message is only stored in the alert_rule_version table.
 	Message string
+	// RestoredFrom is the version that was restored to create this version, 0 otherwise.
+	RestoredFrom int64
 }
 
 type AlertRuleMetadata struct {
@@ -1127,6 +1140,9 @@ type ListAlertRulesQuery struct {
 	// DataSourceUIDs allows searching for alert rules using data sources
 	// that match any of the given UIDs exactly (case sensitive).
 	DataSourceUIDs []string
+	// DependsOnRuleUIDs allows searching for alert rules that read the state of any of the
+	// alert rules with the given UIDs in a rule state expression.
+	DependsOnRuleUIDs []string
 	// SearchTitle allows searching for alert rules whose title contains every
 	// whitespace-separated term of the given string, in any order (case
 	// insensitive).
@@ -1195,6 +1211,8 @@ type UpdateRule struct {
 	Existing *AlertRule
 	New      AlertRule
 	Message  string
+	// RestoredFrom is the version of the rule that the update restores, 0 otherwise.
+	RestoredFrom int64
 }
 
 // Condition contains backend expressions and queries and the RefID
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ClusterPeer is a member of the Alertmanager cluster that uses the database for high availability.
+type ClusterPeer struct {
+	Name string
+	// Heartbeat is the last time the peer reported that it is alive.
+	Heartbeat time.Time
+}
+
+// ClusterMessage is a message that a peer of the Alertmanager cluster sends to all other peers through the database.
+type ClusterMessage struct {
+	// ID increases with every message, which lets peers read only the messages they have not seen yet.
+	ID      int64
+	Sender  string
+	Channel string
+	Payload []byte
+	Created time.Time
+}
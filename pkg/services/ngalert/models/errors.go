@@ -36,6 +36,8 @@ var (
 
 	ErrReceiverNotFound = errutil.NotFound("alerting.notifications.receivers.notFound", errutil.WithPublicMessage("Receiver not found"))
 
+	ErrReceiverDigestNotFound = errutil.NotFound("alerting.notifications.receivers.digest.notFound", errutil.WithPublicMessage("Receiver has no digest"))
+
 	ErrReceiverExists = errutil.Conflict("alerting.notifications.receivers.exists", errutil.WithPublicMessage("Receiver with this name already exists. Use a different name or update an existing one."))
 
 	ErrReceiverInvalidBase = errutil.BadRequest("alerting.notifications.receivers.invalid").MustTemplate(
@@ -11,6 +11,9 @@ var (
 
 	// ErrImageDataUnavailable is returned when image data is unavailable. Usually because the image is missing a path.
 	ErrImageDataUnavailable = errors.New("image data is unavailable")
+
+	// ErrNoImageData is returned when the results of the evaluation have no series to draw in an image.
+	ErrNoImageData = errors.New("no data to draw")
 )
 
 type Image struct {
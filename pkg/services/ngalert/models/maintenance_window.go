@@ -0,0 +1,273 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrMaintenanceWindowNotFound is returned when a maintenance window does not exist.
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+	// ErrMaintenanceWindowInvalid is returned when a maintenance window cannot be saved because it is not valid.
+	ErrMaintenanceWindowInvalid = errors.New("invalid maintenance window")
+)
+
+// MaintenanceWindow is a recurring period of time during which the alerts that match the matchers are silenced.
+// A silence is created at the start of each occurrence and expires at its end.
+type MaintenanceWindow struct {
+	ID    int64
+	OrgID int64
+	UID   string
+	Title string
+	// Schedule defines when the occurrences of the window start. It is either a cron expression
+	// or a recurrence rule (RRULE), see ParseMaintenanceSchedule.
+	Schedule string
+	// Timezone is the IANA name of the time zone the schedule is evaluated in. Empty means UTC.
+	Timezone string
+	// Duration is the length of each occurrence.
+	Duration time.Duration
+	// Matchers select the alerts that are silenced during the window.
+	Matchers labels.Matchers
+	// PauseRuleEvaluation pauses the evaluation of the alert rules whose labels match the matchers during the window.
+	PauseRuleEvaluation bool
+
+	CreatedBy string
+	UpdatedBy string
+	Created   time.Time
+	Updated   time.Time
+
+	// LastOccurrence is the start of the most recent occurrence a silence was created for.
+	LastOccurrence time.Time
+	// SilenceID is the ID of the silence created for the most recent occurrence.
+	SilenceID string
+}
+
+// Validate checks that the window is well-formed.
+func (w *MaintenanceWindow) Validate() error {
+	if strings.TrimSpace(w.Title) == "" {
+		return fmt.Errorf("%w: title must not be empty", ErrMaintenanceWindowInvalid)
+	}
+	if w.Duration <= 0 {
+		return fmt.Errorf("%w: duration must be greater than zero", ErrMaintenanceWindowInvalid)
+	}
+	if len(w.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrMaintenanceWindowInvalid)
+	}
+	if _, err := w.Location(); err != nil {
+		return fmt.Errorf("%w: %s", ErrMaintenanceWindowInvalid, err)
+	}
+	if _, err := ParseMaintenanceSchedule(w.Schedule); err != nil {
+		return fmt.Errorf("%w: %s", ErrMaintenanceWindowInvalid, err)
+	}
+	return nil
+}
+
+// Location returns the time zone of the schedule.
+func (w *MaintenanceWindow) Location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+	}
+	return loc, nil
+}
+
+// ActiveOccurrence returns the start and the end of the occurrence of the window that includes the given time.
+// If several occurrences overlap, the earliest one is returned. It returns false if the window is not active.
+func (w *MaintenanceWindow) ActiveOccurrence(now time.Time) (time.Time, time.Time, bool, error) {
+	schedule, err := w.ParseSchedule()
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	start, end, active := schedule.ActiveOccurrence(now)
+	return start, end, active, nil
+}
+
+// NextOccurrence returns the start of the first occurrence of the window after the given time.
+func (w *MaintenanceWindow) NextOccurrence(now time.Time) (time.Time, error) {
+	schedule, err := w.ParseSchedule()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.NextOccurrence(now), nil
+}
+
+// ParseSchedule parses the schedule and the time zone of the window, so that its occurrences can be computed
+// without parsing them every time.
+func (w *MaintenanceWindow) ParseSchedule() (*MaintenanceSchedule, error) {
+	schedule, err := ParseMaintenanceSchedule(w.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := w.Location()
+	if err != nil {
+		return nil, err
+	}
+	return &MaintenanceSchedule{schedule: schedule, location: loc, duration: w.Duration}, nil
+}
+
+// MaintenanceSchedule computes the occurrences of a maintenance window. It is created by MaintenanceWindow.ParseSchedule.
+type MaintenanceSchedule struct {
+	schedule cron.Schedule
+	location *time.Location
+	duration time.Duration
+}
+
+// ActiveOccurrence returns the start and the end of the occurrence that includes the given time.
+// If several occurrences overlap, the earliest one is returned. It returns false if there is no such occurrence.
+func (s *MaintenanceSchedule) ActiveOccurrence(now time.Time) (time.Time, time.Time, bool) {
+	// The first occurrence that starts after now-duration is the earliest one that has not ended yet.
+	start := s.schedule.Next(now.Add(-s.duration).In(s.location))
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, start.Add(s.duration), true
+}
+
+// NextOccurrence returns the start of the first occurrence after the given time.
+func (s *MaintenanceSchedule) NextOccurrence(now time.Time) time.Time {
+	return s.schedule.Next(now.In(s.location))
+}
+
+// MatchesLabels returns true if the labels match all matchers of the window.
+func (w *MaintenanceWindow) MatchesLabels(lbls map[string]string) bool {
+	for _, m := range w.Matchers {
+		if !m.Matches(lbls[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseMaintenanceSchedule parses the schedule of a maintenance window. The schedule is either a standard cron
+// expression with five fields, such as "0 2 * * 6", or a recurrence rule as defined by RFC 5545, such as
+// "RRULE:FREQ=WEEKLY;BYDAY=SA;BYHOUR=2". Recurrence rules support FREQ=DAILY, WEEKLY or MONTHLY,
+// and the BYDAY, BYMONTHDAY, BYHOUR and BYMINUTE parts. The time of the day defaults to midnight.
+func ParseMaintenanceSchedule(s string) (cron.Schedule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("schedule must not be empty")
+	}
+	if isRecurrenceRule(s) {
+		spec, err := recurrenceRuleToCron(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence rule %q: %w", s, err)
+		}
+		s = spec
+	} else if strings.HasPrefix(s, "TZ=") || strings.HasPrefix(s, "CRON_TZ=") {
+		return nil, errors.New("the time zone of the schedule must be set in the timezone field")
+	}
+	schedule, err := cron.ParseStandard(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", s, err)
+	}
+	return schedule, nil
+}
+
+func isRecurrenceRule(s string) bool {
+	return strings.HasPrefix(strings.ToUpper(s), "RRULE:") || strings.HasPrefix(strings.ToUpper(s), "FREQ=")
+}
+
+var rruleWeekdays = map[string]string{"SU": "0", "MO": "1", "TU": "2", "WE": "3", "TH": "4", "FR": "5", "SA": "6"}
+
+// recurrenceRuleToCron converts the supported subset of recurrence rules to a cron expression.
+func recurrenceRuleToCron(rule string) (string, error) {
+	rule = strings.ToUpper(rule)
+	rule = strings.TrimPrefix(rule, "RRULE:")
+	parts := map[string]string{}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return "", fmt.Errorf("invalid part %q", part)
+		}
+		parts[name] = value
+	}
+
+	minute, hour, dayOfMonth, dayOfWeek := "0", "0", "*", "*"
+	for name, value := range parts {
+		switch name {
+		case "FREQ":
+		case "INTERVAL":
+			if value != "1" {
+				return "", errors.New("only INTERVAL=1 is supported")
+			}
+		case "BYMINUTE":
+			if err := checkNumberList(value, 0, 59); err != nil {
+				return "", fmt.Errorf("BYMINUTE: %w", err)
+			}
+			minute = value
+		case "BYHOUR":
+			if err := checkNumberList(value, 0, 23); err != nil {
+				return "", fmt.Errorf("BYHOUR: %w", err)
+			}
+			hour = value
+		case "BYMONTHDAY":
+			if err := checkNumberList(value, 1, 31); err != nil {
+				return "", fmt.Errorf("BYMONTHDAY: %w", err)
+			}
+			dayOfMonth = value
+		case "BYDAY":
+			days := strings.Split(value, ",")
+			for i, d := range days {
+				n, ok := rruleWeekdays[d]
+				if !ok {
+					return "", fmt.Errorf("BYDAY: unsupported day %q", d)
+				}
+				days[i] = n
+			}
+			dayOfWeek = strings.Join(days, ",")
+		default:
+			return "", fmt.Errorf("%s is not supported", name)
+		}
+	}
+
+	switch parts["FREQ"] {
+	case "DAILY":
+		if dayOfMonth != "*" {
+			return "", errors.New("BYMONTHDAY is not supported with FREQ=DAILY")
+		}
+	case "WEEKLY":
+		if dayOfMonth != "*" {
+			return "", errors.New("BYMONTHDAY is not supported with FREQ=WEEKLY")
+		}
+		if dayOfWeek == "*" {
+			return "", errors.New("BYDAY is required with FREQ=WEEKLY")
+		}
+	case "MONTHLY":
+		if dayOfWeek != "*" {
+			return "", errors.New("BYDAY is not supported with FREQ=MONTHLY")
+		}
+		if dayOfMonth == "*" {
+			return "", errors.New("BYMONTHDAY is required with FREQ=MONTHLY")
+		}
+	case "":
+		return "", errors.New("FREQ is required")
+	default:
+		return "", fmt.Errorf("FREQ=%s is not supported", parts["FREQ"])
+	}
+	return strings.Join([]string{minute, hour, dayOfMonth, "*", dayOfWeek}, " "), nil
+}
+
+func checkNumberList(value string, minValue, maxValue int) error {
+	for _, s := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		if n < minValue || n > maxValue {
+			return fmt.Errorf("%d is out of range [%d, %d]", n, minValue, maxValue)
+		}
+	}
+	return nil
+}
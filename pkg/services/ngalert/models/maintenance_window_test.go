@@ -0,0 +1,141 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaintenanceSchedule(t *testing.T) {
+	from := time.Date(2026, time.March, 4, 10, 30, 0, 0, time.UTC) // Wednesday
+
+	testCases := []struct {
+		name     string
+		schedule string
+		next     time.Time
+	}{
+		{
+			name:     "cron expression",
+			schedule: "0 2 * * 6",
+			next:     time.Date(2026, time.March, 7, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly recurrence rule",
+			schedule: "RRULE:FREQ=WEEKLY;BYDAY=SA,SU;BYHOUR=2;BYMINUTE=30",
+			next:     time.Date(2026, time.March, 7, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily recurrence rule without prefix",
+			schedule: "FREQ=DAILY;BYHOUR=9",
+			next:     time.Date(2026, time.March, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly recurrence rule",
+			schedule: "RRULE:FREQ=MONTHLY;BYMONTHDAY=1;INTERVAL=1",
+			next:     time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseMaintenanceSchedule(tc.schedule)
+			require.NoError(t, err)
+			assert.Equal(t, tc.next, schedule.Next(from))
+		})
+	}
+
+	for _, invalid := range []string{
+		"",
+		"not a schedule",
+		"CRON_TZ=Europe/Berlin 0 2 * * 6",
+		"RRULE:FREQ=YEARLY",
+		"RRULE:FREQ=WEEKLY;BYHOUR=2",
+		"RRULE:FREQ=WEEKLY;BYDAY=XX",
+		"RRULE:FREQ=DAILY;INTERVAL=2",
+		"RRULE:FREQ=DAILY;BYHOUR=25",
+		"RRULE:FREQ=DAILY;COUNT=3",
+		"RRULE:BYHOUR=2",
+	} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := ParseMaintenanceSchedule(invalid)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMaintenanceWindowActiveOccurrence(t *testing.T) {
+	w := MaintenanceWindow{
+		Title:    "weekly",
+		Schedule: "RRULE:FREQ=WEEKLY;BYDAY=SA;BYHOUR=2",
+		Timezone: "Europe/Berlin",
+		Duration: 2 * time.Hour,
+		Matchers: labels.Matchers{{Type: labels.MatchEqual, Name: "team", Value: "db"}},
+	}
+	require.NoError(t, w.Validate())
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2026, time.March, 7, 2, 0, 0, 0, berlin)
+
+	t.Run("should be active during the occurrence in the time zone of the window", func(t *testing.T) {
+		from, end, active, err := w.ActiveOccurrence(start.Add(90 * time.Minute).UTC())
+		require.NoError(t, err)
+		require.True(t, active)
+		assert.True(t, start.Equal(from))
+		assert.True(t, start.Add(2*time.Hour).Equal(end))
+	})
+
+	t.Run("should not be active outside of the occurrence", func(t *testing.T) {
+		for _, now := range []time.Time{start.Add(-time.Minute), start.Add(2 * time.Hour), start.Add(24 * time.Hour)} {
+			_, _, active, err := w.ActiveOccurrence(now)
+			require.NoError(t, err)
+			assert.False(t, active, now)
+		}
+	})
+
+	t.Run("should return next occurrence", func(t *testing.T) {
+		next, err := w.NextOccurrence(start.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, start.AddDate(0, 0, 7).Equal(next))
+	})
+}
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	valid := func() MaintenanceWindow {
+		return MaintenanceWindow{
+			Title:    "window",
+			Schedule: "0 2 * * *",
+			Duration: time.Hour,
+			Matchers: labels.Matchers{{Type: labels.MatchEqual, Name: "team", Value: "db"}},
+		}
+	}
+	w := valid()
+	require.NoError(t, w.Validate())
+
+	testCases := map[string]func(w *MaintenanceWindow){
+		"empty title":      func(w *MaintenanceWindow) { w.Title = " " },
+		"invalid schedule": func(w *MaintenanceWindow) { w.Schedule = "0 2 * *" },
+		"no duration":      func(w *MaintenanceWindow) { w.Duration = 0 },
+		"no matchers":      func(w *MaintenanceWindow) { w.Matchers = nil },
+		"unknown timezone": func(w *MaintenanceWindow) { w.Timezone = "Mars/Olympus" },
+	}
+	for name, mutate := range testCases {
+		t.Run(name, func(t *testing.T) {
+			w := valid()
+			mutate(&w)
+			require.ErrorIs(t, w.Validate(), ErrMaintenanceWindowInvalid)
+		})
+	}
+}
+
+func TestMaintenanceWindowMatchesLabels(t *testing.T) {
+	w := MaintenanceWindow{Matchers: labels.Matchers{
+		{Type: labels.MatchEqual, Name: "team", Value: "db"},
+		labels.MustNewMatcher(labels.MatchRegexp, "env", "prod|staging"),
+	}}
+
+	assert.True(t, w.MatchesLabels(map[string]string{"team": "db", "env": "prod", "other": "value"}))
+	assert.False(t, w.MatchesLabels(map[string]string{"team": "db", "env": "dev"}))
+	assert.False(t, w.MatchesLabels(map[string]string{"env": "prod"}))
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+const (
+	// MinReceiverDigestWindow is the shortest window a receiver digest can accumulate alerts for.
+	MinReceiverDigestWindow = time.Minute
+	// MaxReceiverDigestWindow is the longest window a receiver digest can accumulate alerts for.
+	MaxReceiverDigestWindow = 24 * time.Hour
+
+	// ReceiverDigestPrefix is the prefix of the name of the receivers that are generated to send the digests.
+	ReceiverDigestPrefix = "__grafana_digest__"
+	// ReceiverDigestGroupByLabel is a label that no alert has. Grouping by it puts all alerts of a route in a single
+	// group, so that a single digest is sent per window.
+	ReceiverDigestGroupByLabel = "__grafana_digest__"
+	// ReceiverDigestTitleTemplate is the prefix of the names of the templates used for the title of the digests,
+	// followed by the window of the digest, for example __digest_title_1h.
+	ReceiverDigestTitleTemplate = "__digest_title"
+	// ReceiverDigestMessageTemplate is the prefix of the names of the templates used for the message of the digests,
+	// followed by the window of the digest, for example __digest_message_1h.
+	ReceiverDigestMessageTemplate = "__digest_message"
+)
+
+// ReceiverDigest configures a receiver to accumulate its alerts and send a single summarized notification per
+// window instead of one notification per alert group and group interval.
+type ReceiverDigest struct {
+	// Window is how long the alerts are accumulated before the digest is sent.
+	Window time.Duration
+	// BypassMatchers select the alerts that are sent right away, without waiting for the digest.
+	BypassMatchers labels.Matchers
+}
+
+// Validate checks that the digest is well-formed.
+func (d ReceiverDigest) Validate() error {
+	if d.Window < MinReceiverDigestWindow || d.Window > MaxReceiverDigestWindow {
+		return fmt.Errorf("window must be between %s and %s", MinReceiverDigestWindow, MaxReceiverDigestWindow)
+	}
+	for _, m := range d.BypassMatchers {
+		if m == nil {
+			return errors.New("bypass matchers must not be empty")
+		}
+	}
+	return nil
+}
+
+// ReceiverDigestName returns the name of the receiver generated to send the digests of the given receiver.
+func ReceiverDigestName(receiver string) string {
+	return ReceiverDigestPrefix + receiver
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRecordingRuleBackfillNotFound is returned when a backfill does not exist.
+	ErrRecordingRuleBackfillNotFound = errors.New("recording rule backfill not found")
+	// ErrRecordingRuleBackfillInvalid is returned when a backfill cannot be created or changed because it is not valid.
+	ErrRecordingRuleBackfillInvalid = errors.New("invalid recording rule backfill")
+)
+
+// RecordingRuleBackfillStatus is the status of a backfill.
+type RecordingRuleBackfillStatus string
+
+const (
+	// BackfillPending means that the backfill waits to be run by a Grafana instance.
+	BackfillPending RecordingRuleBackfillStatus = "pending"
+	// BackfillRunning means that a Grafana instance evaluates the rule.
+	BackfillRunning RecordingRuleBackfillStatus = "running"
+	// BackfillCompleted means that the rule was evaluated over the whole time range.
+	BackfillCompleted RecordingRuleBackfillStatus = "completed"
+	// BackfillFailed means that the backfill was stopped because of too many failures. It can be resumed.
+	BackfillFailed RecordingRuleBackfillStatus = "failed"
+	// BackfillCanceled means that the backfill was canceled by a user. It can be resumed.
+	BackfillCanceled RecordingRuleBackfillStatus = "canceled"
+)
+
+// RecordingRuleBackfill evaluates a recording rule over a past time range at the interval of the rule, and writes
+// the resulting series with the timestamps of the evaluations. The progress is saved, so that the backfill resumes
+// where it stopped.
+type RecordingRuleBackfill struct {
+	ID      int64
+	OrgID   int64
+	UID     string
+	RuleUID string
+	From    time.Time
+	To      time.Time
+	Status  RecordingRuleBackfillStatus
+
+	// Next is the time of the next evaluation.
+	Next time.Time
+	// Evaluations is the number of evaluations between From and To.
+	Evaluations int64
+	// Completed is the number of evaluations done, including the failed ones.
+	Completed int64
+	// Failed is the number of evaluations that failed to query or to write.
+	Failed int64
+	// LastError is the error of the last failed evaluation.
+	LastError string
+
+	CreatedBy string
+	Created   time.Time
+	// Updated is the time the backfill was last changed. While it runs, the progress is saved periodically,
+	// so that a backfill that is not updated anymore can be taken over by another Grafana instance.
+	Updated time.Time
+}
+
+// Validate checks that the time range of the backfill is in the past.
+func (b *RecordingRuleBackfill) Validate(now time.Time) error {
+	if b.RuleUID == "" {
+		return fmt.Errorf("%w: rule UID must not be empty", ErrRecordingRuleBackfillInvalid)
+	}
+	if b.From.IsZero() || b.To.IsZero() {
+		return fmt.Errorf("%w: time range must not be empty", ErrRecordingRuleBackfillInvalid)
+	}
+	if !b.From.Before(b.To) {
+		return fmt.Errorf("%w: start of the time range must be before its end", ErrRecordingRuleBackfillInvalid)
+	}
+	if b.To.After(now) {
+		return fmt.Errorf("%w: end of the time range must not be in the future", ErrRecordingRuleBackfillInvalid)
+	}
+	return nil
+}
+
+// IsDone returns true if the backfill is not pending and not running.
+func (b *RecordingRuleBackfill) IsDone() bool {
+	return b.Status != BackfillPending && b.Status != BackfillRunning
+}
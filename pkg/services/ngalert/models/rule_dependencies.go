@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// GetRuleStateDependencies returns the UIDs of the alert rules whose state is read by the
+// rule state expressions of the rule, sorted and without duplicates. Queries with a model
+// that is not valid JSON are skipped, they fail the validation of the rule.
+func (alertRule *AlertRule) GetRuleStateDependencies() []string {
+	var result []string
+	for i := range alertRule.Data {
+		uid, ok, err := alertRule.Data[i].GetRuleStateDependency()
+		if err != nil || !ok {
+			continue
+		}
+		if !slices.Contains(result, uid) {
+			result = append(result, uid)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// DependsOnAny returns true if the rule reads the state of any of the alert rules with the given UIDs.
+func (alertRule *AlertRule) DependsOnAny(uids []string) bool {
+	return slices.ContainsFunc(alertRule.GetRuleStateDependencies(), func(uid string) bool {
+		return slices.Contains(uids, uid)
+	})
+}
+
+// RuleDependencyGraph maps the UID of each alert rule to the UIDs of the alert rules
+// whose state it reads.
+type RuleDependencyGraph map[string][]string
+
+// NewRuleDependencyGraph creates the dependency graph of the rules.
+func NewRuleDependencyGraph(rules []*AlertRule) RuleDependencyGraph {
+	graph := make(RuleDependencyGraph, len(rules))
+	for _, rule := range rules {
+		if rule.UID == "" {
+			continue
+		}
+		graph[rule.UID] = rule.GetRuleStateDependencies()
+	}
+	return graph
+}
+
+// Dependents returns the UIDs of the alert rules that read the state of each alert rule.
+// Rules that no rule depends on are not in the result.
+func (g RuleDependencyGraph) Dependents() map[string][]string {
+	result := make(map[string][]string)
+	for _, uid := range slices.Sorted(maps.Keys(g)) {
+		for _, dep := range g[uid] {
+			result[dep] = append(result[dep], uid)
+		}
+	}
+	return result
+}
+
+// FindCycle returns the UIDs of the alert rules of a dependency cycle, starting and ending
+// with the same rule, or nil if the rules do not depend on each other in a cycle.
+func (g RuleDependencyGraph) FindCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	status := make(map[string]int, len(g))
+	var path []string
+
+	var visit func(uid string) []string
+	visit = func(uid string) []string {
+		switch status[uid] {
+		case visited:
+			return nil
+		case visiting:
+			start := slices.Index(path, uid)
+			return append(slices.Clone(path[start:]), uid)
+		}
+		status[uid] = visiting
+		path = append(path, uid)
+		for _, dep := range g[uid] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		status[uid] = visited
+		return nil
+	}
+
+	for _, uid := range slices.Sorted(maps.Keys(g)) {
+		if status[uid] != unvisited {
+			continue
+		}
+		if cycle := visit(uid); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// ValidateRuleDependencyChanges checks that the alert rules of an organization can read the
+// state of the rules they depend on after the changed rules are created or updated and the
+// deleted rules are deleted: the rules they depend on exist and they do not depend on each
+// other in a cycle. The rules are all the alert rules of the organization before the changes.
+func ValidateRuleDependencyChanges(rules []*AlertRule, changed []*AlertRule, deleted []string) error {
+	graph := NewRuleDependencyGraph(rules)
+	existed := make(map[string]bool, len(deleted))
+	for _, uid := range deleted {
+		_, existed[uid] = graph[uid]
+		delete(graph, uid)
+	}
+	for _, rule := range changed {
+		if rule.UID != "" {
+			graph[rule.UID] = rule.GetRuleStateDependencies()
+		}
+	}
+
+	for _, rule := range changed {
+		for _, dep := range rule.GetRuleStateDependencies() {
+			if _, ok := graph[dep]; !ok {
+				return fmt.Errorf("%w '%s': depends on the state of alert rule '%s' that does not exist", ErrAlertRuleFailedValidation, rule.Title, dep)
+			}
+		}
+	}
+	// Deleting a rule must not leave the rules that read its state without it. Rules that are
+	// deleted and created again in the same change, for example when moved, still exist.
+	dependents := graph.Dependents()
+	for _, uid := range deleted {
+		if _, ok := graph[uid]; ok || !existed[uid] {
+			continue
+		}
+		if len(dependents[uid]) > 0 {
+			return fmt.Errorf("%w: alert rule '%s' cannot be deleted, alert rules %s depend on its state", ErrAlertRuleFailedValidation, uid, strings.Join(dependents[uid], ", "))
+		}
+	}
+	if cycle := graph.FindCycle(); cycle != nil {
+		return fmt.Errorf("%w: alert rules depend on the state of each other in a cycle: %s", ErrAlertRuleFailedValidation, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+func ruleWithDependencies(uid string, deps ...string) *AlertRule {
+	rule := &AlertRule{
+		UID: uid,
+		Data: []AlertQuery{{
+			RefID:         "A",
+			DatasourceUID: "datasource",
+			Model:         json.RawMessage(`{"type": "rule_state", "ruleUid": "not-an-expression"}`),
+		}},
+	}
+	for i, dep := range deps {
+		rule.Data = append(rule.Data, AlertQuery{
+			RefID:         fmt.Sprintf("D%d", i),
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(fmt.Sprintf(`{"type": "rule_state", "ruleUid": %q}`, dep)),
+		})
+	}
+	return rule
+}
+
+func TestGetRuleStateDependencies(t *testing.T) {
+	require.Empty(t, ruleWithDependencies("a").GetRuleStateDependencies())
+	require.Equal(t, []string{"b", "c"}, ruleWithDependencies("a", "c", "b", "c").GetRuleStateDependencies())
+}
+
+func TestRuleDependencyGraph(t *testing.T) {
+	t.Run("no cycle", func(t *testing.T) {
+		g := NewRuleDependencyGraph([]*AlertRule{
+			ruleWithDependencies("a", "b", "c"),
+			ruleWithDependencies("b", "c"),
+			ruleWithDependencies("c"),
+		})
+		require.Nil(t, g.FindCycle())
+		require.Equal(t, map[string][]string{"b": {"a"}, "c": {"a", "b"}}, g.Dependents())
+	})
+
+	t.Run("rule that depends on itself", func(t *testing.T) {
+		g := NewRuleDependencyGraph([]*AlertRule{ruleWithDependencies("a", "a")})
+		require.Equal(t, []string{"a", "a"}, g.FindCycle())
+	})
+
+	t.Run("rules that depend on each other", func(t *testing.T) {
+		g := NewRuleDependencyGraph([]*AlertRule{
+			ruleWithDependencies("a", "b"),
+			ruleWithDependencies("b", "c"),
+			ruleWithDependencies("c", "d", "b"),
+			ruleWithDependencies("d"),
+		})
+		require.Equal(t, []string{"b", "c", "b"}, g.FindCycle())
+	})
+}
+
+func TestValidateRuleDependencyChanges(t *testing.T) {
+	rules := []*AlertRule{
+		ruleWithDependencies("a", "b"),
+		ruleWithDependencies("b"),
+		ruleWithDependencies("c"),
+	}
+
+	t.Run("changes without dependencies", func(t *testing.T) {
+		require.NoError(t, ValidateRuleDependencyChanges(rules, []*AlertRule{ruleWithDependencies("c")}, nil))
+		require.NoError(t, ValidateRuleDependencyChanges(rules, nil, []string{"c"}))
+	})
+
+	t.Run("rule that depends on a rule that does not exist", func(t *testing.T) {
+		err := ValidateRuleDependencyChanges(rules, []*AlertRule{ruleWithDependencies("c", "d")}, nil)
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "'d' that does not exist")
+	})
+
+	t.Run("deleted rule that another rule depends on", func(t *testing.T) {
+		err := ValidateRuleDependencyChanges(rules, nil, []string{"b"})
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "alert rules a depend on its state")
+		// The dependent rule is deleted as well.
+		require.NoError(t, ValidateRuleDependencyChanges(rules, nil, []string{"a", "b"}))
+		// The dependent rule no longer depends on the deleted rule.
+		require.NoError(t, ValidateRuleDependencyChanges(rules, []*AlertRule{ruleWithDependencies("a")}, []string{"b"}))
+	})
+
+	t.Run("unchanged rule creates a cycle with the changed rule", func(t *testing.T) {
+		err := ValidateRuleDependencyChanges(rules, []*AlertRule{ruleWithDependencies("b", "a")}, nil)
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "a -> b -> a")
+	})
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+var (
+	// ErrRulePolicyInvalid is returned when a rule policy cannot be saved because it is not valid.
+	ErrRulePolicyInvalid = errors.New("invalid rule policy")
+)
+
+// RulePolicyMode defines what happens when an alert rule that is created or updated violates the rule policy.
+type RulePolicyMode string
+
+const (
+	// RulePolicyModeWarn saves the rule and reports the violations.
+	RulePolicyModeWarn RulePolicyMode = "warn"
+	// RulePolicyModeReject refuses to save the rule.
+	RulePolicyModeReject RulePolicyMode = "reject"
+)
+
+// RulePolicy is a set of requirements that the alert rules of an organization must meet.
+// The zero value of each field means that there is no requirement.
+type RulePolicy struct {
+	Mode RulePolicyMode `json:"mode"`
+	// RequiredLabels are the labels that every rule must have with a non-empty value.
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// RequiredAnnotations are the annotations that every alerting rule must have with a non-empty value,
+	// for example runbook_url. They are not checked on recording rules.
+	RequiredAnnotations []string `json:"requiredAnnotations,omitempty"`
+	// LabelValuePatterns maps a label name to a regular expression that the whole value of the label
+	// must match if the rule has the label.
+	LabelValuePatterns map[string]string `json:"labelValuePatterns,omitempty"`
+	// MinInterval and MaxInterval limit the evaluation interval of the rule group of the rule.
+	MinInterval time.Duration `json:"minInterval,omitempty"`
+	MaxInterval time.Duration `json:"maxInterval,omitempty"`
+	// MaxFor limits the pending period of alerting rules.
+	MaxFor time.Duration `json:"maxFor,omitempty"`
+	// BannedDatasourceTypes are the types of the data sources that the rules must not query.
+	BannedDatasourceTypes []string `json:"bannedDatasourceTypes,omitempty"`
+}
+
+// Validate checks that the policy is well-formed.
+func (p *RulePolicy) Validate() error {
+	if p.Mode != RulePolicyModeWarn && p.Mode != RulePolicyModeReject {
+		return fmt.Errorf("%w: mode must be one of %s, %s", ErrRulePolicyInvalid, RulePolicyModeWarn, RulePolicyModeReject)
+	}
+	if slices.Contains(p.RequiredLabels, "") {
+		return fmt.Errorf("%w: required label names must not be empty", ErrRulePolicyInvalid)
+	}
+	if slices.Contains(p.RequiredAnnotations, "") {
+		return fmt.Errorf("%w: required annotation names must not be empty", ErrRulePolicyInvalid)
+	}
+	for name, pattern := range p.LabelValuePatterns {
+		if name == "" {
+			return fmt.Errorf("%w: label value patterns must have a label name", ErrRulePolicyInvalid)
+		}
+		if _, err := compileLabelValuePattern(pattern); err != nil {
+			return fmt.Errorf("%w: pattern of label %s: %s", ErrRulePolicyInvalid, name, err)
+		}
+	}
+	if p.MinInterval < 0 || p.MaxInterval < 0 || p.MaxFor < 0 {
+		return fmt.Errorf("%w: durations must not be negative", ErrRulePolicyInvalid)
+	}
+	if p.MaxInterval > 0 && p.MinInterval > p.MaxInterval {
+		return fmt.Errorf("%w: minimum interval %s is greater than maximum interval %s", ErrRulePolicyInvalid, p.MinInterval, p.MaxInterval)
+	}
+	return nil
+}
+
+// Check returns a description of each requirement of the policy that the rule does not meet.
+// datasourceTypes maps the UIDs of the data sources that the rule queries to their types;
+// queries to data sources missing from the map are not checked against the banned types.
+func (p *RulePolicy) Check(rule *AlertRule, datasourceTypes map[string]string) []string {
+	var violations []string
+	for _, name := range p.RequiredLabels {
+		if rule.Labels[name] == "" {
+			violations = append(violations, fmt.Sprintf("missing required label %s", name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(p.LabelValuePatterns)) {
+		value, ok := rule.Labels[name]
+		if !ok {
+			continue
+		}
+		re, err := compileLabelValuePattern(p.LabelValuePatterns[name])
+		if err != nil || !re.MatchString(value) {
+			violations = append(violations, fmt.Sprintf("value %q of label %s does not match pattern %q", value, name, p.LabelValuePatterns[name]))
+		}
+	}
+	if rule.Type() != RuleTypeRecording {
+		for _, name := range p.RequiredAnnotations {
+			if rule.Annotations[name] == "" {
+				violations = append(violations, fmt.Sprintf("missing required annotation %s", name))
+			}
+		}
+		if p.MaxFor > 0 && rule.For > p.MaxFor {
+			violations = append(violations, fmt.Sprintf("pending period %s is longer than the maximum %s", rule.For, p.MaxFor))
+		}
+	}
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	if p.MinInterval > 0 && interval < p.MinInterval {
+		violations = append(violations, fmt.Sprintf("evaluation interval %s is shorter than the minimum %s", interval, p.MinInterval))
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		violations = append(violations, fmt.Sprintf("evaluation interval %s is longer than the maximum %s", interval, p.MaxInterval))
+	}
+	if len(p.BannedDatasourceTypes) > 0 {
+		for _, uid := range rule.GetQueryDatasourceUIDs() {
+			if dsType, ok := datasourceTypes[uid]; ok && slices.Contains(p.BannedDatasourceTypes, dsType) {
+				violations = append(violations, fmt.Sprintf("queries data source %s of banned type %s", uid, dsType))
+			}
+		}
+	}
+	return violations
+}
+
+// GetQueryDatasourceUIDs returns the UIDs of the data sources that the rule queries, excluding
+// expressions, in the order of the queries and without duplicates.
+func (alertRule *AlertRule) GetQueryDatasourceUIDs() []string {
+	var result []string
+	for _, q := range alertRule.Data {
+		if expr.NodeTypeFromDatasourceUID(q.DatasourceUID) == expr.TypeCMDNode || slices.Contains(result, q.DatasourceUID) {
+			continue
+		}
+		result = append(result, q.DatasourceUID)
+	}
+	return result
+}
+
+// compileLabelValuePattern compiles the pattern anchored at both ends, so that it must match the whole value.
+func compileLabelValuePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
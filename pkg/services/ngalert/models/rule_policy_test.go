@@ -0,0 +1,123 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+func TestRulePolicyValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy RulePolicy
+		err    string
+	}{
+		{
+			name:   "valid policy",
+			policy: RulePolicy{Mode: RulePolicyModeReject, RequiredLabels: []string{"team"}, LabelValuePatterns: map[string]string{"severity": "critical|warning"}, MinInterval: time.Minute, MaxInterval: time.Hour},
+		},
+		{
+			name:   "unknown mode",
+			policy: RulePolicy{Mode: "audit"},
+			err:    "mode must be one of warn, reject",
+		},
+		{
+			name:   "empty required label",
+			policy: RulePolicy{Mode: RulePolicyModeWarn, RequiredLabels: []string{""}},
+			err:    "required label names must not be empty",
+		},
+		{
+			name:   "invalid pattern",
+			policy: RulePolicy{Mode: RulePolicyModeWarn, LabelValuePatterns: map[string]string{"team": "("}},
+			err:    "pattern of label team",
+		},
+		{
+			name:   "minimum interval greater than maximum",
+			policy: RulePolicy{Mode: RulePolicyModeWarn, MinInterval: time.Hour, MaxInterval: time.Minute},
+			err:    "minimum interval 1h0m0s is greater than maximum interval 1m0s",
+		},
+		{
+			name:   "negative duration",
+			policy: RulePolicy{Mode: RulePolicyModeWarn, MaxFor: -time.Minute},
+			err:    "durations must not be negative",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrRulePolicyInvalid)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestRulePolicyCheck(t *testing.T) {
+	policy := RulePolicy{
+		Mode:                  RulePolicyModeReject,
+		RequiredLabels:        []string{"team", "severity"},
+		RequiredAnnotations:   []string{"runbook_url"},
+		LabelValuePatterns:    map[string]string{"severity": "critical|warning"},
+		MinInterval:           time.Minute,
+		MaxInterval:           10 * time.Minute,
+		MaxFor:                time.Hour,
+		BannedDatasourceTypes: []string{"testdata"},
+	}
+	rule := func() *AlertRule {
+		return &AlertRule{
+			Title:           "compliant",
+			IntervalSeconds: 60,
+			For:             5 * time.Minute,
+			Labels:          map[string]string{"team": "platform", "severity": "critical"},
+			Annotations:     map[string]string{"runbook_url": "https://example.com/runbook"},
+			Data: []AlertQuery{
+				{RefID: "A", DatasourceUID: "prometheus"},
+				{RefID: "B", DatasourceUID: expr.DatasourceUID},
+			},
+		}
+	}
+	datasourceTypes := map[string]string{"prometheus": "prometheus", "random": "testdata"}
+
+	require.Empty(t, policy.Check(rule(), datasourceTypes))
+
+	r := rule()
+	r.Labels = map[string]string{"severity": "page"}
+	r.Annotations = nil
+	r.IntervalSeconds = 30
+	r.For = 2 * time.Hour
+	r.Data = append(r.Data, AlertQuery{RefID: "C", DatasourceUID: "random"}, AlertQuery{RefID: "D", DatasourceUID: "unknown"})
+	require.Equal(t, []string{
+		"missing required label team",
+		`value "page" of label severity does not match pattern "critical|warning"`,
+		"missing required annotation runbook_url",
+		"pending period 2h0m0s is longer than the maximum 1h0m0s",
+		"evaluation interval 30s is shorter than the minimum 1m0s",
+		"queries data source random of banned type testdata",
+	}, policy.Check(r, datasourceTypes))
+
+	t.Run("pattern must match the whole value", func(t *testing.T) {
+		r := rule()
+		r.Labels["severity"] = "critical-ish"
+		require.Len(t, policy.Check(r, datasourceTypes), 1)
+	})
+
+	t.Run("annotations and pending period are not checked on recording rules", func(t *testing.T) {
+		r := rule()
+		r.Record = &Record{Metric: "metric", From: "A"}
+		r.Annotations = nil
+		r.For = 2 * time.Hour
+		require.Empty(t, policy.Check(r, datasourceTypes))
+	})
+
+	t.Run("evaluation interval longer than the maximum", func(t *testing.T) {
+		r := rule()
+		r.IntervalSeconds = 3600
+		require.Equal(t, []string{"evaluation interval 1h0m0s is longer than the maximum 10m0s"}, policy.Check(r, datasourceTypes))
+	})
+}
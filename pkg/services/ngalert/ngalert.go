@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana-app-sdk/resource"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/lokiconfig"
+	"github.com/grafana/grafana/pkg/services/ngalert/maintenance"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/inhibition_rules"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/routes"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
@@ -40,6 +41,7 @@ import (
 	ac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
 	apiprometheus "github.com/grafana/grafana/pkg/services/ngalert/api/prometheus"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/cluster"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
@@ -50,10 +52,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/remote"
 	remoteClient "github.com/grafana/grafana/pkg/services/ngalert/remote/client"
+	"github.com/grafana/grafana/pkg/services/ngalert/rulepolicy"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
@@ -100,6 +104,7 @@ func ProvideService(
 	userService user.Service,
 	orgService org.Service,
 	clientGenerator resource.ClientGenerator,
+	ruleStateReader *state.RuleStateReader,
 ) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                      cfg,
@@ -134,6 +139,7 @@ func ProvideService(
 		RouteResourcePermissions: routeResourcePermissions,
 		userService:              userService,
 		orgService:               orgService,
+		ruleStateReader:          ruleStateReader,
 	}
 
 	if ng.IsDisabled() {
@@ -167,6 +173,7 @@ type AlertNG struct {
 	renderService         rendering.Service
 	ImageService          image.ImageService
 	RecordingWriter       schedule.RecordingWriter
+	RecordingBackfiller   *backtesting.Backfiller
 	schedule              schedule.ScheduleService
 	stateManager          *state.Manager
 	folderService         folder.Service
@@ -181,6 +188,7 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager     *notifier.MultiOrgAlertmanager
 	AlertsRouter             *sender.AlertsRouter
+	MaintenanceWindows       *maintenance.Service
 	accesscontrol            accesscontrol.AccessControl
 	AccesscontrolService     accesscontrol.Service
 	ResourcePermissions      accesscontrol.ReceiverPermissionsService
@@ -195,7 +203,11 @@ type AlertNG struct {
 	tracer          tracing.Tracer
 	clientGenerator resource.ClientGenerator
 
+	// ruleStateReader is read by the rule state expressions of the expression service.
+	ruleStateReader *state.RuleStateReader
+
 	evaluationCoordinator EvaluationCoordinator
+	ruleSharder           *cluster.RuleSharder
 	schedCfg              schedule.SchedulerCfg
 }
 
@@ -322,6 +334,7 @@ func (ng *AlertNG) init() error {
 		ng.store,
 	)
 
+	opts = append(opts, notifier.WithClusterPeerStore(ng.store))
 	moa, err := notifier.NewMultiOrgAlertmanager(
 		ng.Cfg,
 		ng.store,
@@ -365,9 +378,11 @@ func (ng *AlertNG) init() error {
 
 	clk := clock.New()
 
+	ng.MaintenanceWindows = maintenance.NewService(ng.store, ng.MultiOrgAlertmanager, clk, log.New("ngalert.maintenance"))
+
 	alertsRouter := sender.NewAlertsRouter(ng.MultiOrgAlertmanager, ng.store, clk, appUrl, ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, ng.DataSourceService, ng.SecretsService, ng.FeatureToggles,
-		ng.Cfg.UnifiedAlerting.HASingleNodeEvaluation, ng.Metrics.GetSenderMetrics())
+		ng.Cfg.UnifiedAlerting.HASingleNodeEvaluation || ng.Cfg.UnifiedAlerting.HAEvaluationSharding, ng.Metrics.GetSenderMetrics())
 
 	// Make sure we sync at least once as Grafana starts to get the router up and running before we start sending any alerts.
 	if err := alertsRouter.SyncAndApplyConfigFromDatabase(initCtx); err != nil {
@@ -384,6 +399,7 @@ func (ng *AlertNG) init() error {
 		return fmt.Errorf("failed to initialize recording writer: %w", err)
 	}
 	ng.RecordingWriter = recordingWriter
+	ng.RecordingBackfiller = backtesting.NewBackfiller(ng.store, evalFactory, ng.RecordingWriter, ng.Cfg.UnifiedAlerting, clk, log.New("ngalert.recording-backfill"))
 
 	ng.schedCfg = schedule.SchedulerCfg{
 		RetryConfig: schedule.RetryConfig{
@@ -408,6 +424,7 @@ func (ng *AlertNG) init() error {
 		Log:                  log.New("ngalert.scheduler"),
 		RecordingWriter:      ng.RecordingWriter,
 		FeatureToggles:       ng.FeatureToggles,
+		MaintenanceWindows:   ng.MaintenanceWindows,
 	}
 
 	history, err := configureHistorianBackend(
@@ -426,6 +443,7 @@ func (ng *AlertNG) init() error {
 		ng.pluginContextProvider,
 		clk,
 		ng.Metrics.GetRemoteWriterMetrics(),
+		ng.SQLStore,
 	)
 	if err != nil {
 		return err
@@ -439,6 +457,7 @@ func (ng *AlertNG) init() error {
 		ExternalURL:                    appUrl,
 		DisableExecution:               !ng.Cfg.UnifiedAlerting.ExecuteAlerts,
 		InstanceStore:                  ng.InstanceStore,
+		AcknowledgementStore:           ng.store,
 		Images:                         ng.ImageService,
 		Clock:                          clk,
 		Historian:                      history,
@@ -457,9 +476,45 @@ func (ng *AlertNG) init() error {
 	statePersister := initStatePersister(stateManagerCfg, ng.FeatureToggles)
 	ng.stateManager = state.NewManager(stateManagerCfg, statePersister)
 
+	stateArchiver := statearchive.NewService(ng.store, ng.InstanceStore, ng.store, ng.MultiOrgAlertmanager, ng.stateManager, log.New("ngalert.state-archive"))
+
 	var apiStateManager state.AlertInstanceManager
+	// ruleStates is read by the rule state expressions. It is the in-memory state unless the state of the rules
+	// is spread across the instances.
+	var ruleStates state.AlertInstanceManager = ng.stateManager
 	var ruleMutator apiprometheus.RuleMutator
-	if ng.Cfg.UnifiedAlerting.HASingleNodeEvaluation {
+	var ruleShards api.RuleShards
+	if ng.Cfg.UnifiedAlerting.HAEvaluationSharding {
+		membership := ng.MultiOrgAlertmanager.ClusterMembership()
+		if membership == nil {
+			return fmt.Errorf("evaluation sharding in HA mode requires HA clustering to be enabled")
+		}
+		//nolint:staticcheck // not yet migrated to OpenFeature
+		if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
+			// The periodic save replaces the state of all rules with the state of the rules evaluated by this instance.
+			// Sharding also relies on the state being saved after every evaluation: the instance that takes over a
+			// rule loads its state from the database, and the other instances read it there for the rule state
+			// expressions. There is no ordering between the owners of a rule, the last saved evaluation wins.
+			return fmt.Errorf("evaluation sharding in HA mode cannot be used with the periodic save of alert state")
+		}
+		var err error
+		ng.ruleSharder, err = cluster.NewRuleSharder(membership, log.New("ngalert.rule-sharder"))
+		if err != nil {
+			return fmt.Errorf("failed to create rule sharder: %w", err)
+		}
+		ruleShards = ng.ruleSharder
+		ng.evaluationCoordinator = cluster.NewNoopEvaluationCoordinator()
+		ng.schedCfg.RuleOwnership = ng.ruleSharder
+		ng.schedule = schedule.NewScheduler(ng.schedCfg, ng.stateManager)
+
+		// Use StoreStateReader to serve rule statuses / alert instances from the database,
+		// because every node has in-memory state only for the rules it evaluates
+		storeStateReader := state.NewStoreStateReader(ng.InstanceStore, ng.Log)
+		apiStateManager = storeStateReader
+		// The rules that a rule state expression reads may be evaluated by other instances.
+		ruleStates = storeStateReader
+		ruleMutator = apiprometheus.NewDBRuleMutator(storeStateReader)
+	} else if ng.Cfg.UnifiedAlerting.HASingleNodeEvaluation {
 		peer := ng.MultiOrgAlertmanager.Peer()
 		if peer == nil {
 			return fmt.Errorf("single-node evaluation in HA mode requires HA clustering to be enabled")
@@ -484,6 +539,9 @@ func (ng *AlertNG) init() error {
 		ng.schedule = schedule.NewScheduler(ng.schedCfg, ng.stateManager)
 		ruleMutator = apiprometheus.NewInMemoryRuleMutator(ng.schedule, ng.stateManager)
 	}
+	if ng.ruleStateReader != nil {
+		ng.ruleStateReader.Init(ruleStates, ng.store)
+	}
 
 	configStore := legacy_storage.NewAlertmanagerConfigStore(ng.store, notifier.NewExtraConfigsCrypto(ng.SecretsService), ng.FeatureToggles)
 
@@ -587,11 +645,12 @@ func (ng *AlertNG) init() error {
 	templateServiceWithLimits := templateService.WithLimitsProvider(limitsProvider)
 	muteTimingService := provisioning.NewMuteTimingService(configStore, ng.store, ng.store, ng.Log, ng.store, provisionRouteService, validation.NewPermissionAwareValidator(ng.accesscontrol))
 	inhibitionRuleService := inhibition_rules.NewService(configStore, ng.Log, ng.FeatureToggles, validation.NewPermissionAwareValidator(ng.accesscontrol))
+	rulePolicyEnforcer := rulepolicy.NewEnforcer(ng.store, ng.DataSourceService, log.New("ngalert.rule-policy"))
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.folderService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
 		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store),
-		ac.NewRuleService(ng.accesscontrol), ng.ruleMutationValidator)
+		ac.NewRuleService(ng.accesscontrol), rulepolicy.NewValidator(rulePolicyEnforcer, ng.ruleMutationValidator))
 
 	ng.Api = &api.API{
 		Cfg:                   ng.Cfg,
@@ -604,9 +663,16 @@ func (ng *AlertNG) init() error {
 		RuleStore:             ng.store,
 		AlertingStore:         ng.store,
 		AdminConfigStore:      ng.store,
+		RulePolicyStore:       ng.store,
+		RulePolicyEnforcer:    rulePolicyEnforcer,
+		MaintenanceWindows:    ng.MaintenanceWindows,
 		ProvenanceStore:       ng.store,
 		MultiOrgAlertmanager:  ng.MultiOrgAlertmanager,
 		StateManager:          apiStateManager,
+		AlertAcknowledger:     ng.stateManager,
+		RecordingBackfiller:   ng.RecordingBackfiller,
+		RuleShards:            ruleShards,
+		StateArchiver:         stateArchiver,
 		RuleMutator:           ruleMutator,
 		AccessControl:         ng.accesscontrol,
 		Policies:              policyService,
@@ -744,12 +810,26 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.MaintenanceWindows.Run(subCtx)
+	})
+
+	if ng.ruleSharder != nil {
+		children.Go(func() error {
+			return ng.ruleSharder.Run(subCtx)
+		})
+	}
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		children.Go(func() error {
 			runner := &evaluationRunner{ng: ng}
 			return runner.run(subCtx)
 		})
+		if ng.Cfg.UnifiedAlerting.RecordingRules.Enabled {
+			children.Go(func() error {
+				return ng.RecordingBackfiller.Run(subCtx)
+			})
+		}
 	}
 	return children.Wait()
 }
@@ -790,6 +870,38 @@ func configureHistorianBackend(
 	pluginContextProvider *plugincontext.Provider,
 	clock clock.Clock,
 	mw *metrics.RemoteWriter,
+	sqlStore db.DB,
+) (Historian, error) {
+	// Backends that cannot serve state history queries can only be secondaries of the multiple backend.
+	if cfg.Enabled {
+		primary := cfg.Backend
+		if backend, err := historian.ParseBackendType(cfg.Backend); err == nil && backend == historian.BackendTypeMultiple {
+			primary = cfg.MultiPrimary
+		}
+		if backend, err := historian.ParseBackendType(primary); err == nil && !backend.Queryable() {
+			return nil, fmt.Errorf("state history backend \"%s\" does not support querying and can only be a secondary of the multiple backend", primary)
+		}
+	}
+	return newHistorianBackend(ctx, cfg, annotationMaxTagsLength, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw, sqlStore)
+}
+
+func newHistorianBackend(
+	ctx context.Context,
+	cfg setting.UnifiedAlertingStateHistorySettings,
+	annotationMaxTagsLength int64,
+	ar annotations.Repository,
+	ds dashboards.DashboardService,
+	rs historian.RuleStore,
+	met *metrics.Historian,
+	l log.Logger,
+	tracer tracing.Tracer,
+	ac historian.AccessControl,
+	datasourceService datasources.DataSourceService,
+	httpClientProvider httpclient.Provider,
+	pluginContextProvider *plugincontext.Provider,
+	clock clock.Clock,
+	mw *metrics.RemoteWriter,
+	sqlStore db.DB,
 ) (Historian, error) {
 	if !cfg.Enabled {
 		met.Info.WithLabelValues("noop").Set(0)
@@ -805,7 +917,7 @@ func configureHistorianBackend(
 	if backend == historian.BackendTypeMultiple {
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
-		primary, err := configureHistorianBackend(ctx, primaryCfg, annotationMaxTagsLength, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
+		primary, err := newHistorianBackend(ctx, primaryCfg, annotationMaxTagsLength, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw, sqlStore)
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
@@ -814,7 +926,7 @@ func configureHistorianBackend(
 		for _, b := range cfg.MultiSecondaries {
 			secCfg := cfg
 			secCfg.Backend = b
-			sec, err := configureHistorianBackend(ctx, secCfg, annotationMaxTagsLength, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
+			sec, err := newHistorianBackend(ctx, secCfg, annotationMaxTagsLength, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw, sqlStore)
 			if err != nil {
 				return nil, fmt.Errorf("multi-backend target \"%s\" was miconfigured: %w", b, err)
 			}
@@ -869,6 +981,36 @@ func configureHistorianBackend(
 		return backend, nil
 	}
 
+	if backend == historian.BackendTypeDatabase {
+		logCtx := log.WithContextualAttributes(ctx, []any{"backend", "database"})
+		databaseBackendLogger := log.New("ngalert.state.historian").FromContext(logCtx)
+		return historian.NewDatabaseBackend(databaseBackendLogger, sqlStore, cfg.DatabaseRetention, met, rs, ac), nil
+	}
+
+	if backend == historian.BackendTypeWebhook {
+		wcfg, err := historian.NewWebhookConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook configuration: %w", err)
+		}
+		client, err := httpClientProvider.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook client: %w", err)
+		}
+		logCtx := log.WithContextualAttributes(ctx, []any{"backend", "webhook"})
+		webhookBackendLogger := log.New("ngalert.state.historian").FromContext(logCtx)
+		return historian.NewWebhookBackend(wcfg, client, cfg.ExternalLabels, webhookBackendLogger, met), nil
+	}
+
+	if backend == historian.BackendTypeFile {
+		fcfg, err := historian.NewFileConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file configuration: %w", err)
+		}
+		logCtx := log.WithContextualAttributes(ctx, []any{"backend", "file"})
+		fileBackendLogger := log.New("ngalert.state.historian").FromContext(logCtx)
+		return historian.NewFileBackend(fcfg, cfg.ExternalLabels, fileBackendLogger, met), nil
+	}
+
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
@@ -111,6 +111,10 @@ func (moa *MultiOrgAlertmanager) PrepareConfig(
 		return alertingNotify.NotificationsConfiguration{}, err
 	}
 
+	if err := AddReceiverDigests(prepared); err != nil {
+		return alertingNotify.NotificationsConfiguration{}, fmt.Errorf("failed to add receiver digests: %w", err)
+	}
+
 	return PostableAPIConfigToNotificationsConfiguration(*prepared, moa.limits)
 }
 
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"github.com/gogo/protobuf/proto"
+	alertingCluster "github.com/grafana/alerting/cluster"
+	alertingClusterPB "github.com/grafana/alerting/cluster/clusterpb"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type DBChannel struct {
+	p       *dbPeer
+	key     string
+	msgType string
+	msgc    chan []byte
+}
+
+func newDBChannel(p *dbPeer, key, msgType string, queueSize int) alertingCluster.ClusterChannel {
+	if queueSize <= 0 {
+		queueSize = setting.AlertBroadcastDefaultQueueSize
+	}
+	dbChannel := &DBChannel{
+		p:       p,
+		key:     key,
+		msgType: msgType,
+		msgc:    make(chan []byte, queueSize),
+	}
+	go dbChannel.handleMessages()
+	return dbChannel
+}
+
+func (c *DBChannel) handleMessages() {
+	for {
+		select {
+		case <-c.p.shutdownc:
+			return
+		case b := <-c.msgc:
+			// The state will eventually be propagated to other members by the full sync.
+			if err := c.p.publish(c.key, b); err != nil {
+				c.p.messagesPublishFailures.WithLabelValues(c.msgType, reasonDatabaseIssue).Inc()
+				c.p.logger.Error("Error writing a message to the database", "err", err, "key", c.key)
+				continue
+			}
+			c.p.messagesSent.WithLabelValues(c.msgType).Inc()
+			c.p.messagesSentSize.WithLabelValues(c.msgType).Add(float64(len(b)))
+		}
+	}
+}
+
+func (c *DBChannel) ReliableDelivery([]byte) bool { return true }
+
+func (c *DBChannel) Broadcast(b []byte) {
+	b, err := proto.Marshal(&alertingClusterPB.Part{Key: c.key, Data: b})
+	if err != nil {
+		c.p.logger.Error("Error marshalling broadcast into proto", "err", err, "key", c.key)
+		return
+	}
+	select {
+	case c.msgc <- b:
+	default:
+		// This is not the end of the world, we will catch up when we do a full state sync.
+		c.p.messagesPublishFailures.WithLabelValues(c.msgType, reasonBufferOverflow).Inc()
+		c.p.logger.Warn("Buffer full, dropping message", "key", c.key)
+	}
+}
@@ -0,0 +1,527 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	alertingCluster "github.com/grafana/alerting/cluster"
+	alertingClusterPB "github.com/grafana/alerting/cluster/clusterpb"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	databaseServerLabel = "database"
+	reasonDatabaseIssue = "database_issue"
+	// A peer that has not sent a heartbeat for this many heartbeat intervals is not a member of the cluster anymore.
+	dbHeartbeatTimeoutIntervals = 12
+	// Peers that have not sent a heartbeat for this long are deleted from the database.
+	dbPeerRetention = 5 * time.Minute
+	// Messages are deleted once every peer had the time to read them. A peer that falls behind catches up with the
+	// next full state sync.
+	dbMessageRetention  = 5 * time.Minute
+	dbMessagesBatchSize = 100
+	dbRequestTimeout    = 10 * time.Second
+)
+
+type dbPeerConfig struct {
+	name              string
+	pollInterval      time.Duration
+	heartbeatInterval time.Duration
+}
+
+// dbPeer is a cluster peer that uses the Grafana database to share the state of the Alertmanager between Grafana
+// instances, for setups where neither gossip nor Redis is available. Every peer writes its heartbeat to the database,
+// and the peers with a recent heartbeat are the members of the cluster. State updates are written as messages to the
+// database, and every peer polls for the messages it has not read yet.
+type dbPeer struct {
+	name   string
+	store  store.ClusterPeerStore
+	logger log.Logger
+
+	states    map[string]alertingCluster.State
+	statesMtx sync.RWMutex
+
+	readyc    chan struct{}
+	shutdownc chan struct{}
+
+	pollInterval      time.Duration
+	heartbeatInterval time.Duration
+	pushPullInterval  time.Duration
+
+	// The ID of the last message read from the database. Only accessed by the poll loop.
+	lastMessageID int64
+
+	messagesReceived        *prometheus.CounterVec
+	messagesReceivedSize    *prometheus.CounterVec
+	messagesSent            *prometheus.CounterVec
+	messagesSentSize        *prometheus.CounterVec
+	messagesPublishFailures *prometheus.CounterVec
+	nodePingDuration        *prometheus.HistogramVec
+	nodePingFailures        prometheus.Counter
+
+	// List of active members of the cluster. Should be accessed through the Members function.
+	members    []string
+	membersMtx sync.Mutex
+	// The number of peers in the database, including those that stopped sending heartbeats.
+	clusterSize int
+	// The time when we fetched the members from the database the last time successfully.
+	membersFetchedAt time.Time
+}
+
+func newDBPeer(cfg dbPeerConfig, s store.ClusterPeerStore, logger log.Logger, reg prometheus.Registerer,
+	pushPullInterval time.Duration) *dbPeer {
+	name := "peer-" + uuid.New().String()
+	if cfg.name != "" {
+		name = cfg.name
+	}
+	p := &dbPeer{
+		name:              name,
+		store:             s,
+		logger:            logger,
+		states:            map[string]alertingCluster.State{},
+		readyc:            make(chan struct{}),
+		shutdownc:         make(chan struct{}),
+		pollInterval:      cfg.pollInterval,
+		heartbeatInterval: cfg.heartbeatInterval,
+		pushPullInterval:  pushPullInterval,
+		members:           make([]string, 0),
+	}
+
+	// The metrics are the same as for the Redis peer, so that dashboards work regardless of the peer.
+	messagesReceived := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alertmanager_cluster_messages_received_total",
+		Help: "Total number of cluster messages received.",
+	}, []string{"msg_type"})
+	messagesReceivedSize := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alertmanager_cluster_messages_received_size_total",
+		Help: "Total size of cluster messages received.",
+	}, []string{"msg_type"})
+	messagesSent := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alertmanager_cluster_messages_sent_total",
+		Help: "Total number of cluster messages sent.",
+	}, []string{"msg_type"})
+	messagesSentSize := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alertmanager_cluster_messages_sent_size_total",
+		Help: "Total size of cluster messages sent.",
+	}, []string{"msg_type"})
+	messagesPublishFailures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alertmanager_cluster_messages_publish_failures_total",
+		Help: "Total number of messages that failed to be published.",
+	}, []string{"msg_type", "reason"})
+	clusterMembers := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_cluster_members",
+		Help: "Number indicating current number of members in cluster.",
+	}, func() float64 {
+		return float64(p.ClusterSize())
+	})
+	peerPosition := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_peer_position",
+		Help: "Position the Alertmanager instance believes it's in. The position determines a peer's behavior in the cluster.",
+	}, func() float64 {
+		return float64(p.Position())
+	})
+	healthScore := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_cluster_health_score",
+		Help: "Health score of the cluster. Lower values are better and zero means 'totally healthy'.",
+	}, func() float64 {
+		return float64(p.GetHealthScore())
+	})
+	nodePingDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alertmanager_cluster_pings_seconds",
+		Help:    "Histogram of latencies for ping messages.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5},
+	}, []string{"peer"},
+	)
+	nodePingFailures := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_cluster_pings_failures_total",
+		Help: "Total number of failed pings.",
+	})
+
+	messagesReceived.WithLabelValues(fullState)
+	messagesReceivedSize.WithLabelValues(fullState)
+	messagesReceived.WithLabelValues(update)
+	messagesReceivedSize.WithLabelValues(update)
+	messagesSent.WithLabelValues(fullState)
+	messagesSentSize.WithLabelValues(fullState)
+	messagesSent.WithLabelValues(update)
+	messagesSentSize.WithLabelValues(update)
+	messagesPublishFailures.WithLabelValues(fullState, reasonDatabaseIssue)
+	messagesPublishFailures.WithLabelValues(update, reasonDatabaseIssue)
+	messagesPublishFailures.WithLabelValues(update, reasonBufferOverflow)
+
+	reg.MustRegister(messagesReceived, messagesReceivedSize, messagesSent, messagesSentSize,
+		clusterMembers, peerPosition, healthScore, nodePingDuration, nodePingFailures,
+		messagesPublishFailures,
+	)
+
+	p.messagesReceived = messagesReceived
+	p.messagesReceivedSize = messagesReceivedSize
+	p.messagesSent = messagesSent
+	p.messagesSentSize = messagesSentSize
+	p.messagesPublishFailures = messagesPublishFailures
+	p.nodePingDuration = nodePingDuration
+	p.nodePingFailures = nodePingFailures
+
+	// Only the messages sent from now on are of interest, the current state of other peers is requested once the
+	// cluster settles.
+	ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+	defer cancel()
+	lastID, err := p.store.GetLastClusterMessageID(ctx)
+	if err != nil {
+		p.logger.Error("Failed to get the last cluster message, reading all messages", "err", err)
+	}
+	p.lastMessageID = lastID
+
+	p.heartbeat()
+	p.membersSync()
+
+	go p.heartbeatLoop()
+	go p.pollLoop()
+	go p.fullStateSyncPublishLoop()
+
+	return p
+}
+
+func (p *dbPeer) heartbeatLoop() {
+	ticker := time.NewTicker(p.heartbeatInterval)
+	for {
+		select {
+		case <-ticker.C:
+			p.heartbeat()
+			p.membersSync()
+			p.cleanup()
+		case <-p.shutdownc:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func (p *dbPeer) heartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+	defer cancel()
+	startTime := time.Now()
+	if err := p.store.HeartbeatClusterPeer(ctx, p.name, startTime); err != nil {
+		p.nodePingFailures.Inc()
+		p.logger.Error("Error saving the heartbeat", "err", err, "peer", p.name)
+		return
+	}
+	p.nodePingDuration.WithLabelValues(databaseServerLabel).Observe(time.Since(startTime).Seconds())
+}
+
+func (p *dbPeer) membersSync() {
+	ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+	defer cancel()
+	peers, err := p.store.ListClusterPeers(ctx)
+	if err != nil {
+		p.logger.Error("Error getting the cluster peers from the database", "err", err)
+		// To prevent a spike of duplicate messages, we return for the duration of
+		// membersValidFor the last known members and only empty the list if we do
+		// not eventually recover.
+		if p.membersFetchedAt.Before(time.Now().Add(-membersValidFor)) {
+			p.membersMtx.Lock()
+			p.members = []string{}
+			p.membersMtx.Unlock()
+		}
+		return
+	}
+	healthySince := time.Now().Add(-p.heartbeatInterval * dbHeartbeatTimeoutIntervals)
+	members := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if peer.Heartbeat.Before(healthySince) {
+			continue
+		}
+		members = append(members, peer.Name)
+	}
+	slices.Sort(members)
+
+	p.membersMtx.Lock()
+	p.members = members
+	p.clusterSize = len(peers)
+	p.membersFetchedAt = time.Now()
+	p.membersMtx.Unlock()
+}
+
+// cleanup deletes the peers that are gone and the messages that every peer had the time to read.
+func (p *dbPeer) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+	defer cancel()
+	now := time.Now()
+	if err := p.store.DeleteClusterPeers(ctx, now.Add(-dbPeerRetention)); err != nil {
+		p.logger.Warn("Error deleting stale cluster peers", "err", err)
+	}
+	if _, err := p.store.DeleteClusterMessages(ctx, now.Add(-dbMessageRetention)); err != nil {
+		p.logger.Warn("Error deleting old cluster messages", "err", err)
+	}
+}
+
+func (p *dbPeer) pollLoop() {
+	ticker := time.NewTicker(p.pollInterval)
+	for {
+		select {
+		case <-ticker.C:
+			p.poll()
+		case <-p.shutdownc:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+// poll reads the messages sent by other peers since the last poll. IDs are assigned when a message is inserted
+// but a message becomes visible only when its transaction commits, so a message that commits after a message with
+// a greater ID is missed. This is fine, the state converges with the next full state sync.
+func (p *dbPeer) poll() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+		messages, err := p.store.ListClusterMessages(ctx, p.lastMessageID, dbMessagesBatchSize)
+		cancel()
+		if err != nil {
+			p.logger.Error("Error getting cluster messages from the database", "err", err)
+			return
+		}
+		for _, msg := range messages {
+			p.lastMessageID = msg.ID
+			// We read our own messages as well, and ignore them.
+			if msg.Sender == p.name {
+				continue
+			}
+			switch msg.Channel {
+			case fullStateChannel:
+				p.mergeFullState(msg.Payload)
+			case fullStateChannelReq:
+				p.fullStateSyncPublish()
+			default:
+				p.mergePartialState(msg.Payload)
+			}
+		}
+		if len(messages) < dbMessagesBatchSize {
+			return
+		}
+	}
+}
+
+func (p *dbPeer) publish(channel string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+	defer cancel()
+	return p.store.InsertClusterMessage(ctx, models.ClusterMessage{
+		Sender:  p.name,
+		Channel: channel,
+		Payload: payload,
+		Created: time.Now(),
+	})
+}
+
+// Name returns the name of the peer as it appears in Members.
+func (p *dbPeer) Name() string {
+	return p.name
+}
+
+func (p *dbPeer) Position() int {
+	for i, peer := range p.Members() {
+		if peer == p.name {
+			p.logger.Debug("Cluster position found", "name", p.name, "position", i)
+			return i
+		}
+	}
+	p.logger.Warn("Failed to look up position, falling back to position 0")
+	return 0
+}
+
+// ClusterSize returns the known size of the cluster. This also includes dead nodes that haven't been deleted yet.
+func (p *dbPeer) ClusterSize() int {
+	p.membersMtx.Lock()
+	defer p.membersMtx.Unlock()
+	return p.clusterSize
+}
+
+// If the cluster is healthy it should return 0, otherwise the number of
+// unhealthy nodes.
+func (p *dbPeer) GetHealthScore() int {
+	size := p.ClusterSize()
+	members := len(p.Members())
+	if size > members {
+		return size - members
+	}
+	return 0
+}
+
+// Members returns a list of active cluster Members.
+func (p *dbPeer) Members() []string {
+	p.membersMtx.Lock()
+	defer p.membersMtx.Unlock()
+	return p.members
+}
+
+func (p *dbPeer) WaitReady(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.readyc:
+		return nil
+	}
+}
+
+// Settle waits until the number of members stops changing, the same way as the Redis peer.
+func (p *dbPeer) Settle(ctx context.Context, interval time.Duration) {
+	const NumOkayRequired = 3
+	p.logger.Info("Waiting for the cluster to settle...", "interval", interval)
+	start := time.Now()
+	nPeers := 0
+	nOkay := 0
+	totalPolls := 0
+	for {
+		select {
+		case <-ctx.Done():
+			elapsed := time.Since(start)
+			p.logger.Info("Cluster not settled but continuing anyway", "polls", totalPolls, "elapsed", elapsed)
+			close(p.readyc)
+			return
+		case <-time.After(interval):
+		}
+		elapsed := time.Since(start)
+		n := len(p.Members())
+		if nOkay >= NumOkayRequired {
+			p.logger.Info("Cluster settled; proceeding", "elapsed", elapsed)
+			break
+		}
+		if n == nPeers {
+			nOkay++
+			p.logger.Debug("Cluster looks settled", "elapsed", elapsed)
+		} else {
+			nOkay = 0
+			p.logger.Info("Cluster not settled", "polls", totalPolls, "before", nPeers, "now", n, "elapsed", elapsed)
+		}
+		nPeers = n
+		totalPolls++
+	}
+	p.requestFullState()
+	close(p.readyc)
+}
+
+func (p *dbPeer) AddState(key string, state alertingCluster.State, _ prometheus.Registerer, opts ...alertingCluster.ChannelOption) alertingCluster.ClusterChannel {
+	p.statesMtx.Lock()
+	defer p.statesMtx.Unlock()
+	p.states[key] = state
+	resolved := alertingCluster.ResolveOptions(opts...)
+	return newDBChannel(p, key, update, resolved.QueueSize)
+}
+
+func (p *dbPeer) mergePartialState(buf []byte) {
+	p.messagesReceived.WithLabelValues(update).Inc()
+	p.messagesReceivedSize.WithLabelValues(update).Add(float64(len(buf)))
+
+	var part alertingClusterPB.Part
+	if err := proto.Unmarshal(buf, &part); err != nil {
+		p.logger.Warn("Error decoding the received broadcast message", "err", err)
+		return
+	}
+
+	p.statesMtx.RLock()
+	s, ok := p.states[part.Key]
+	p.statesMtx.RUnlock()
+
+	if !ok {
+		return
+	}
+	if err := s.Merge(part.Data); err != nil {
+		p.logger.Warn("Error merging the received broadcast message", "err", err, "key", part.Key)
+		return
+	}
+	p.logger.Debug("Partial state was successfully merged", "key", part.Key)
+}
+
+func (p *dbPeer) mergeFullState(buf []byte) {
+	p.messagesReceived.WithLabelValues(fullState).Inc()
+	p.messagesReceivedSize.WithLabelValues(fullState).Add(float64(len(buf)))
+
+	var fs alertingClusterPB.FullState
+	if err := proto.Unmarshal(buf, &fs); err != nil {
+		p.logger.Warn("Error unmarshaling the received remote state", "err", err)
+		return
+	}
+
+	p.statesMtx.RLock()
+	defer p.statesMtx.RUnlock()
+	for _, part := range fs.Parts {
+		s, ok := p.states[part.Key]
+		if !ok {
+			p.logger.Warn("Received unknown state key", "len", len(buf), "key", part.Key)
+			continue
+		}
+		if err := s.Merge(part.Data); err != nil {
+			p.logger.Warn("Error merging the received remote state", "err", err, "key", part.Key)
+			return
+		}
+	}
+	p.logger.Debug("Full state was successfully merged")
+}
+
+func (p *dbPeer) fullStateSyncPublish() {
+	if err := p.publish(fullStateChannel, p.LocalState()); err != nil {
+		p.messagesPublishFailures.WithLabelValues(fullState, reasonDatabaseIssue).Inc()
+		p.logger.Error("Error writing the full state to the database", "err", err)
+	}
+}
+
+func (p *dbPeer) fullStateSyncPublishLoop() {
+	ticker := time.NewTicker(p.pushPullInterval)
+	for {
+		select {
+		case <-ticker.C:
+			p.fullStateSyncPublish()
+		case <-p.shutdownc:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func (p *dbPeer) requestFullState() {
+	if err := p.publish(fullStateChannelReq, []byte(p.name)); err != nil {
+		p.messagesPublishFailures.WithLabelValues(fullState, reasonDatabaseIssue).Inc()
+		p.logger.Error("Error requesting the full state from other peers", "err", err)
+	}
+}
+
+func (p *dbPeer) LocalState() []byte {
+	p.statesMtx.RLock()
+	defer p.statesMtx.RUnlock()
+	all := &alertingClusterPB.FullState{
+		Parts: make([]alertingClusterPB.Part, 0, len(p.states)),
+	}
+
+	for key, s := range p.states {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			p.logger.Warn("Error encoding the local state", "err", err, "key", key)
+		}
+		all.Parts = append(all.Parts, alertingClusterPB.Part{Key: key, Data: b})
+	}
+	b, err := proto.Marshal(all)
+	if err != nil {
+		p.logger.Warn("Error encoding the local state to proto", "err", err)
+	}
+	p.messagesSent.WithLabelValues(fullState).Inc()
+	p.messagesSentSize.WithLabelValues(fullState).Add(float64(len(b)))
+	return b
+}
+
+func (p *dbPeer) Shutdown() {
+	p.logger.Info("Stopping database peer...")
+	close(p.shutdownc)
+	p.fullStateSyncPublish()
+	ctx, cancel := context.WithTimeout(context.Background(), dbRequestTimeout)
+	defer cancel()
+	if err := p.store.DeleteClusterPeer(ctx, p.name); err != nil {
+		p.logger.Error("Error deleting the cluster peer on shutdown", "err", err, "peer", p.name)
+	}
+}
@@ -0,0 +1,196 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeClusterPeerStore struct {
+	mtx      sync.Mutex
+	peers    map[string]time.Time
+	messages []models.ClusterMessage
+}
+
+func newFakeClusterPeerStore() *fakeClusterPeerStore {
+	return &fakeClusterPeerStore{peers: map[string]time.Time{}}
+}
+
+func (f *fakeClusterPeerStore) HeartbeatClusterPeer(_ context.Context, name string, at time.Time) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.peers[name] = at
+	return nil
+}
+
+func (f *fakeClusterPeerStore) ListClusterPeers(context.Context) ([]models.ClusterPeer, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]models.ClusterPeer, 0, len(f.peers))
+	for name, heartbeat := range f.peers {
+		result = append(result, models.ClusterPeer{Name: name, Heartbeat: heartbeat})
+	}
+	return result, nil
+}
+
+func (f *fakeClusterPeerStore) DeleteClusterPeers(_ context.Context, before time.Time) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for name, heartbeat := range f.peers {
+		if heartbeat.Before(before) {
+			delete(f.peers, name)
+		}
+	}
+	return nil
+}
+
+func (f *fakeClusterPeerStore) DeleteClusterPeer(_ context.Context, name string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.peers, name)
+	return nil
+}
+
+func (f *fakeClusterPeerStore) InsertClusterMessage(_ context.Context, msg models.ClusterMessage) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	msg.ID = int64(len(f.messages) + 1)
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+func (f *fakeClusterPeerStore) ListClusterMessages(_ context.Context, afterID int64, limit int) ([]models.ClusterMessage, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result []models.ClusterMessage
+	for _, msg := range f.messages {
+		if msg.ID > afterID && len(result) < limit {
+			result = append(result, msg)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeClusterPeerStore) GetLastClusterMessageID(context.Context) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return int64(len(f.messages)), nil
+}
+
+func (f *fakeClusterPeerStore) DeleteClusterMessages(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeClusterPeerStore) channels() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]string, 0, len(f.messages))
+	for _, msg := range f.messages {
+		result = append(result, msg.Channel)
+	}
+	return result
+}
+
+// fakeState is a cluster state that merges by appending the received data.
+type fakeState struct {
+	mtx    sync.Mutex
+	local  []byte
+	merged [][]byte
+}
+
+func (s *fakeState) MarshalBinary() ([]byte, error) {
+	return s.local, nil
+}
+
+func (s *fakeState) Merge(b []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.merged = append(s.merged, b)
+	return nil
+}
+
+func (s *fakeState) received() [][]byte {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return slices.Clone(s.merged)
+}
+
+func newTestDBPeer(t *testing.T, name string, s *fakeClusterPeerStore) *dbPeer {
+	t.Helper()
+	// The loops are not supposed to run during the tests, which call the steps of the loops directly.
+	p := newDBPeer(dbPeerConfig{name: name, pollInterval: time.Hour, heartbeatInterval: time.Hour},
+		s, log.NewNopLogger(), prometheus.NewRegistry(), time.Hour)
+	t.Cleanup(p.Shutdown)
+	return p
+}
+
+func TestDBPeerMembers(t *testing.T) {
+	s := newFakeClusterPeerStore()
+	// A peer that stopped sending heartbeats is not a member anymore.
+	require.NoError(t, s.HeartbeatClusterPeer(context.Background(), "peer-gone", time.Now().Add(-24*time.Hour)))
+
+	b := newTestDBPeer(t, "peer-b", s)
+	a := newTestDBPeer(t, "peer-a", s)
+	b.membersSync()
+
+	require.Equal(t, []string{"peer-a", "peer-b"}, a.Members())
+	require.Equal(t, []string{"peer-a", "peer-b"}, b.Members())
+	require.Equal(t, 0, a.Position())
+	require.Equal(t, 1, b.Position())
+	require.Equal(t, 3, a.ClusterSize())
+	require.Equal(t, 1, a.GetHealthScore())
+
+	a.cleanup()
+	a.membersSync()
+	require.Equal(t, 2, a.ClusterSize())
+	require.Equal(t, 0, a.GetHealthScore())
+}
+
+func TestDBPeerState(t *testing.T) {
+	s := newFakeClusterPeerStore()
+	a := newTestDBPeer(t, "peer-a", s)
+	b := newTestDBPeer(t, "peer-b", s)
+
+	stateA := &fakeState{local: []byte("state-a")}
+	stateB := &fakeState{local: []byte("state-b")}
+	channelA := a.AddState("silences", stateA, nil)
+	b.AddState("silences", stateB, nil)
+
+	t.Run("broadcasts partial state to other peers", func(t *testing.T) {
+		channelA.Broadcast([]byte("silence"))
+		require.Eventually(t, func() bool {
+			return len(s.channels()) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		b.poll()
+		require.Equal(t, [][]byte{[]byte("silence")}, stateB.received())
+		// The sender ignores its own messages.
+		a.poll()
+		require.Empty(t, stateA.received())
+	})
+
+	t.Run("sends the full state when requested", func(t *testing.T) {
+		b.requestFullState()
+		a.poll()
+		require.Equal(t, []string{"silences", fullStateChannelReq, fullStateChannel}, s.channels())
+
+		b.poll()
+		require.Equal(t, [][]byte{[]byte("silence"), []byte("state-a")}, stateB.received())
+	})
+
+	t.Run("reads only the messages sent after it started", func(t *testing.T) {
+		stateC := &fakeState{}
+		c := newTestDBPeer(t, "peer-c", s)
+		c.AddState("silences", stateC, nil)
+		c.poll()
+		require.Empty(t, stateC.received())
+	})
+}
@@ -16,6 +16,46 @@ func (rev *ConfigRevision) DeleteReceiver(uid string) {
 	rev.Config.AlertmanagerConfig.Receivers = slices.DeleteFunc(rev.Config.AlertmanagerConfig.Receivers, func(r *v1.PostableApiReceiver) bool {
 		return NameToUid(r.GetName()) == uid
 	})
+	// Remove its digest settings as well.
+	for name := range rev.Config.ReceiverDigests {
+		if NameToUid(name) == uid {
+			delete(rev.Config.ReceiverDigests, name)
+		}
+	}
+}
+
+// GetReceiverDigest returns the digest settings of the receiver with the given name.
+func (rev *ConfigRevision) GetReceiverDigest(name string) (models.ReceiverDigest, bool) {
+	d, ok := rev.Config.ReceiverDigests[name]
+	return d, ok
+}
+
+// SetReceiverDigest enables the digest mode of the receiver with the given name.
+func (rev *ConfigRevision) SetReceiverDigest(name string, digest models.ReceiverDigest) {
+	if rev.Config.ReceiverDigests == nil {
+		rev.Config.ReceiverDigests = make(map[string]models.ReceiverDigest)
+	}
+	rev.Config.ReceiverDigests[name] = digest
+}
+
+// DeleteReceiverDigest disables the digest mode of the receiver with the given name.
+// It returns false if the digest mode was not enabled.
+func (rev *ConfigRevision) DeleteReceiverDigest(name string) bool {
+	if _, ok := rev.Config.ReceiverDigests[name]; !ok {
+		return false
+	}
+	delete(rev.Config.ReceiverDigests, name)
+	return true
+}
+
+// RenameReceiverDigest moves the digest settings of a receiver that was renamed.
+func (rev *ConfigRevision) RenameReceiverDigest(oldName, newName string) {
+	d, ok := rev.Config.ReceiverDigests[oldName]
+	if !ok || oldName == newName {
+		return
+	}
+	delete(rev.Config.ReceiverDigests, oldName)
+	rev.Config.ReceiverDigests[newName] = d
 }
 
 func (rev *ConfigRevision) CreateReceiver(receiver *models.Receiver) (*models.Receiver, error) {
@@ -6,12 +6,14 @@ import (
 	"hash/fnv"
 	"maps"
 	"slices"
+	"time"
 
 	"github.com/grafana/alerting/definition"
 	"github.com/grafana/alerting/definition/compat"
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -36,6 +38,7 @@ func ToModel(in *definitions.PostableUserConfig) *AMConfigV1 {
 		AlertmanagerConfig: PostableApiAlertingConfigToModel(in.AlertmanagerConfig),
 		ExtraConfigs:       ExtraConfigsToModel(in.ExtraConfigs),
 		ManagedRoutes:      ManagedRoutesToModel(in.ManagedRoutes),
+		ReceiverDigests:    ReceiverDigestsToModel(in.ReceiverDigests),
 	}
 }
 
@@ -151,6 +154,20 @@ func ObjectMatchersToModel(in definitions.ObjectMatchers) ObjectMatchers {
 	return ObjectMatchers(slices.Clone(in))
 }
 
+func ReceiverDigestsToModel(in map[string]definitions.ReceiverDigest) map[string]models.ReceiverDigest {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]models.ReceiverDigest, len(in))
+	for name, d := range in {
+		out[name] = models.ReceiverDigest{
+			Window:         time.Duration(d.Window),
+			BypassMatchers: labels.Matchers(slices.Clone(d.BypassMatchers)),
+		}
+	}
+	return out
+}
+
 func InhibitionRulesToModel(in definitions.ManagedInhibitionRules) map[ResourceUID]InhibitionRule {
 	if in == nil {
 		return nil
@@ -211,6 +228,7 @@ func ToDBModel(in *AMConfigV1) (*AMConfigDB, error) {
 		AlertmanagerConfig: PostableApiAlertingConfigToDB(in.AlertmanagerConfig),
 		ExtraConfigs:       ExtraConfigsToDB(in.ExtraConfigs),
 		ManagedRoutes:      ManagedRoutesToDB(in.ManagedRoutes),
+		ReceiverDigests:    ReceiverDigestsToDB(in.ReceiverDigests),
 	}
 
 	var errs []error
@@ -334,6 +352,20 @@ func ObjectMatchersToDB(in ObjectMatchers) definitions.ObjectMatchers {
 	return definitions.ObjectMatchers(slices.Clone(in))
 }
 
+func ReceiverDigestsToDB(in map[string]models.ReceiverDigest) map[string]definitions.ReceiverDigest {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]definitions.ReceiverDigest, len(in))
+	for name, d := range in {
+		out[name] = definitions.ReceiverDigest{
+			Window:         model.Duration(d.Window),
+			BypassMatchers: definitions.ObjectMatchers(slices.Clone(d.BypassMatchers)),
+		}
+	}
+	return out
+}
+
 func InhibitionRulesToDB(in map[ResourceUID]InhibitionRule) (definitions.ManagedInhibitionRules, error) {
 	if in == nil {
 		return nil, nil
@@ -28,6 +28,7 @@ type AMConfigV1 struct {
 	AlertmanagerConfig PostableApiAlertingConfig
 	ExtraConfigs       []ExtraConfiguration
 	ManagedRoutes      ManagedRoutes
+	ReceiverDigests    map[string]models.ReceiverDigest
 }
 
 // SortedTemplates returns templates ordered by kind and title.
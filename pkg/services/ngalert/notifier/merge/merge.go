@@ -185,6 +185,7 @@ func MergeExtraConfig(_ context.Context, cfg *v1.AMConfigV1) (v1.AMConfigV1, Mer
 			},
 			ManagedRoutes:   managedRoutes,
 			InhibitionRules: managedInhibitionRules,
+			ReceiverDigests: cfg.ReceiverDigests,
 		}, MergeResult{
 			RenameResources:      RenameResources{Receivers: renamedReceivers, TimeIntervals: renamedTimeIntervals, Templates: renamedTemplates},
 			AddedRoute:           mimirCfg.Identifier,
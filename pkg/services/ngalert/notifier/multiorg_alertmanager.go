@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/cluster"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/merge"
 
 	alertingNotify "github.com/grafana/alerting/notify"
@@ -111,6 +112,8 @@ type MultiOrgAlertmanager struct {
 	peer                   alertingNotify.ClusterPeer
 	alertsBroadcastChannel alertingCluster.ClusterChannel
 	settleCancel           context.CancelFunc
+	// clusterPeerStore is the database used by the peer when the Alertmanager is clustered through the database.
+	clusterPeerStore store.ClusterPeerStore
 
 	configStore AlertingStore
 	orgStore    store.OrgStore
@@ -141,6 +144,13 @@ func WithAlertmanagerOverride(f func(OrgAlertmanagerFactory) OrgAlertmanagerFact
 	}
 }
 
+// WithClusterPeerStore sets the database that is used when the Alertmanager is clustered through the database.
+func WithClusterPeerStore(s store.ClusterPeerStore) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.clusterPeerStore = s
+	}
+}
+
 func NewMultiOrgAlertmanager(
 	cfg *setting.Cfg,
 	configStore AlertingStore,
@@ -182,16 +192,6 @@ func NewMultiOrgAlertmanager(
 		externalAMSyncer: externalAMSyncer,
 	}
 
-	if skipClustering {
-		moa.logger.Info("Not setting up clustering for the multi-org Alertmanager")
-	} else {
-		if err := moa.setupClustering(cfg); err != nil {
-			return nil, err
-		}
-	}
-
-	moa.initAlertBroadcast()
-
 	moa.limits = alertingNotify.DynamicLimits{
 		Dispatcher: nilLimits{},
 		Templates: alertingTemplates.Limits{
@@ -213,6 +213,17 @@ func NewMultiOrgAlertmanager(
 		opt(moa)
 	}
 
+	// Clustering is set up after the options because the database peer needs the store from the options.
+	if skipClustering {
+		moa.logger.Info("Not setting up clustering for the multi-org Alertmanager")
+	} else {
+		if err := moa.setupClustering(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	moa.initAlertBroadcast()
+
 	return moa, nil
 }
 
@@ -249,6 +260,23 @@ func (moa *MultiOrgAlertmanager) setupClustering(cfg *setting.Cfg) error {
 		moa.peer = redisPeer
 		return nil
 	}
+	// Database setup.
+	if cfg.UnifiedAlerting.HADatabaseEnabled {
+		if moa.clusterPeerStore == nil {
+			return errors.New("database clustering requires a cluster peer store")
+		}
+		dbPeer := newDBPeer(dbPeerConfig{
+			name:              cfg.UnifiedAlerting.HADatabasePeerName,
+			pollInterval:      cfg.UnifiedAlerting.HADatabasePollInterval,
+			heartbeatInterval: cfg.UnifiedAlerting.HADatabaseHeartbeatInterval,
+		}, moa.clusterPeerStore, clusterLogger, moa.metrics.Registerer, cfg.UnifiedAlerting.HAPushPullInterval)
+		// The members are synced at every heartbeat, so the cluster cannot settle faster than that.
+		var ctx context.Context
+		ctx, moa.settleCancel = context.WithTimeout(context.Background(), 30*time.Second)
+		go dbPeer.Settle(ctx, max(settleTimeout, cfg.UnifiedAlerting.HADatabaseHeartbeatInterval))
+		moa.peer = dbPeer
+		return nil
+	}
 	// Memberlist setup.
 	if len(cfg.UnifiedAlerting.HAPeers) > 0 {
 		peer, err := alertingCluster.Create(
@@ -588,6 +616,11 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 		moa.settleCancel()
 		r.Shutdown()
 	}
+	d, ok := moa.peer.(*dbPeer)
+	if ok {
+		moa.settleCancel()
+		d.Shutdown()
+	}
 }
 
 // Peer returns the cluster peer for this Alertmanager.
@@ -596,6 +629,34 @@ func (moa *MultiOrgAlertmanager) Peer() alertingNotify.ClusterPeer {
 	return moa.peer
 }
 
+// ClusterMembership returns the members of the Alertmanager cluster, which the evaluation of alert rules can be
+// sharded between. Returns nil if clustering is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembership() cluster.MembershipProvider {
+	switch p := moa.peer.(type) {
+	case *redisPeer:
+		return p
+	case *dbPeer:
+		return p
+	case *alertingCluster.Peer:
+		return gossipMembership{Peer: p}
+	}
+	return nil
+}
+
+// gossipMembership provides the members of the gossip mesh.
+type gossipMembership struct {
+	*alertingCluster.Peer
+}
+
+func (m gossipMembership) Members() []string {
+	peers := m.Peers()
+	names := make([]string, 0, len(peers))
+	for _, n := range peers {
+		names = append(names, n.Name)
+	}
+	return names
+}
+
 // IsExternalAMSyncConfiguredForOrg reports whether external Alertmanager sync
 // configuration exists for the given org (operator-level ini value or per-org
 // admin_config UID). It does not consider whether the sync feature flag is on —
@@ -775,6 +836,55 @@ func (moa *MultiOrgAlertmanager) updateSilenceState(ctx context.Context, orgAM A
 	return err
 }
 
+// GetAlertmanagerState returns the silences, the notification log and the flush log of the Alertmanager of the
+// organization. The silences are read from the Alertmanager, the logs are the ones last persisted to the kvstore.
+func (moa *MultiOrgAlertmanager) GetAlertmanagerState(ctx context.Context, orgID int64) (ExternalState, error) {
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return ExternalState{}, err
+	}
+	silences, err := orgAM.SilenceState(ctx)
+	if err != nil {
+		return ExternalState{}, err
+	}
+	silencesState, err := silences.MarshalBinary()
+	if err != nil {
+		return ExternalState{}, err
+	}
+
+	fs := NewFileStore(orgID, moa.kvStore)
+	nflog, err := fs.GetNotificationLog(ctx)
+	if err != nil {
+		return ExternalState{}, err
+	}
+	flushLog, err := fs.GetFlushLog(ctx)
+	if err != nil {
+		return ExternalState{}, err
+	}
+	return ExternalState{
+		Silences: silencesState,
+		Nflog:    []byte(nflog),
+		FlushLog: []byte(flushLog),
+	}, nil
+}
+
+// MergeAlertmanagerState merges the state with the one of the Alertmanager of the organization and persists the
+// silences immediately.
+func (moa *MultiOrgAlertmanager) MergeAlertmanagerState(ctx context.Context, orgID int64, st ExternalState) error {
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return err
+	}
+	merger, ok := orgAM.(StateMerger)
+	if !ok {
+		return fmt.Errorf("the Alertmanager of organization %d does not support merging state", orgID)
+	}
+	if err := merger.MergeState(st); err != nil {
+		return err
+	}
+	return moa.updateSilenceState(ctx, orgAM, orgID)
+}
+
 // NilPeer and NilChannel implements the Alertmanager clustering interface.
 type NilPeer struct{}
 
@@ -0,0 +1,291 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
+	v1 "github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage/v1"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// receiverDigestTemplateTitle is the title of the template group that defines the digest templates.
+const receiverDigestTemplateTitle = "__digest"
+
+// receiverDigestTemplate defines the templates shared by the digests of all windows. Templates have no arithmetic,
+// so the alerts are counted with the length of a string that grows by one character per alert.
+const receiverDigestTemplate = `
+{{ define "__digest_count_by_rule" -}}
+{{ range $i, $a := . }}
+{{- $first := true }}{{ range $j, $b := $ }}{{ if and (lt $j $i) (eq $b.Labels.alertname $a.Labels.alertname) }}{{ $first = false }}{{ end }}{{ end }}
+{{- if $first }}{{ $count := "" }}{{ range $ }}{{ if eq .Labels.alertname $a.Labels.alertname }}{{ $count = print $count "." }}{{ end }}{{ end -}}
+- {{ $a.Labels.alertname }}: {{ len $count }}
+{{ end }}
+{{- end }}
+{{- end }}
+
+{{ define "__digest_count_by_severity" -}}
+{{ range $i, $a := . }}
+{{- $first := true }}{{ range $j, $b := $ }}{{ if and (lt $j $i) (eq $b.Labels.severity $a.Labels.severity) }}{{ $first = false }}{{ end }}{{ end }}
+{{- if $first }}{{ $count := "" }}{{ range $ }}{{ if eq .Labels.severity $a.Labels.severity }}{{ $count = print $count "." }}{{ end }}{{ end -}}
+- {{ or $a.Labels.severity "none" }}: {{ len $count }}
+{{ end }}
+{{- end }}
+{{- end }}
+
+{{ define "__digest_top_firing" -}}
+{{- $known := stringSlice "critical" "high" "error" "warning" "medium" "low" "info" }}
+{{- $shown := "" }}
+{{- range $severity := $known }}{{ range $ }}{{ if and (eq .Labels.severity $severity) (lt (len $shown) 10) }}{{ $shown = print $shown "." }}{{ template "__digest_alert" . }}{{ end }}{{ end }}{{ end }}
+{{- range $ }}{{ $a := . }}{{ $isKnown := false }}{{ range $known }}{{ if eq $a.Labels.severity . }}{{ $isKnown = true }}{{ end }}{{ end }}
+{{- if and (not $isKnown) (lt (len $shown) 10) }}{{ $shown = print $shown "." }}{{ template "__digest_alert" $a }}{{ end }}{{ end }}
+{{- if gt (len $) 10 }}and {{ len (slice $ 10) }} more
+{{ end }}
+{{- end }}
+
+{{ define "__digest_alert" }}- {{ .Labels.alertname }}{{ with .Labels.severity }} [{{ . }}]{{ end }} since {{ .StartsAt.Format "2006-01-02 15:04:05 MST" }}
+{{ end }}
+`
+
+// receiverDigestWindowTemplate defines the title and the message of the digests of a window. The digests of a route
+// are sent at most once per window, so the firing alerts that started during the last window are new since the last
+// digest. Resolved alerts are sent only once, so all of them are resolved since the last digest.
+const receiverDigestWindowTemplate = `
+{{ define %[1]q -}}
+{{ $new := "" }}{{ range .Alerts.Firing }}{{ if lt (since .StartsAt).Nanoseconds %[3]d }}{{ $new = print $new "." }}{{ end }}{{ end -}}
+[DIGEST] {{ len $new }} new, {{ len .Alerts.Firing }} firing, {{ len .Alerts.Resolved }} resolved
+{{- end }}
+
+{{ define %[2]q -}}
+{{ $new := "" }}{{ range .Alerts.Firing }}{{ if lt (since .StartsAt).Nanoseconds %[3]d }}{{ $new = print $new "." }}{{ end }}{{ end -}}
+{{ len $new }} new firing alerts since the last digest, {{ len .Alerts.Firing }} firing in total and {{ len .Alerts.Resolved }} resolved since the last digest.
+{{ if .Alerts.Firing }}
+Firing by rule:
+{{ template "__digest_count_by_rule" .Alerts.Firing }}
+Firing by severity:
+{{ template "__digest_count_by_severity" .Alerts.Firing }}
+Top firing:
+{{ template "__digest_top_firing" .Alerts.Firing }}
+{{- end }}
+{{- if .Alerts.Resolved }}
+Resolved by rule:
+{{ template "__digest_count_by_rule" .Alerts.Resolved }}
+{{- end }}
+{{- end }}
+`
+
+// digestTemplateName returns the name of the template used by the digests of the window.
+func digestTemplateName(name string, window time.Duration) string {
+	return name + "_" + model.Duration(window).String()
+}
+
+// digestTemplates returns the content of the template group that defines the templates of the digests of the windows.
+func digestTemplates(windows []time.Duration) string {
+	var b strings.Builder
+	b.WriteString(receiverDigestTemplate)
+	for _, window := range windows {
+		fmt.Fprintf(&b, receiverDigestWindowTemplate,
+			digestTemplateName(models.ReceiverDigestTitleTemplate, window),
+			digestTemplateName(models.ReceiverDigestMessageTemplate, window),
+			window.Nanoseconds(),
+		)
+	}
+	return b.String()
+}
+
+type digestTemplateFields struct {
+	title   string
+	message string
+}
+
+// receiverDigestFields are the settings that hold the title and the message of the notifications, by integration type.
+// The digest receivers use the digest templates for these settings unless they are customized.
+// Integrations of other types send the digest with their default templates.
+var receiverDigestFields = map[string]digestTemplateFields{
+	"discord":    {title: "title", message: "message"},
+	"email":      {title: "subject", message: "message"},
+	"googlechat": {title: "title", message: "message"},
+	"pagerduty":  {title: "summary"},
+	"pushover":   {title: "title", message: "message"},
+	"slack":      {title: "title", message: "text"},
+	"teams":      {title: "title", message: "message"},
+	"telegram":   {message: "message"},
+	"webhook":    {title: "title", message: "message"},
+}
+
+// AddReceiverDigests changes the configuration so that the receivers in digest mode send a single summarized
+// notification per window. For each of these receivers, a receiver that uses the digest templates is generated, and
+// the routes that use the receiver group all their alerts and send them to the generated receiver once per window.
+// The alerts that match the bypass matchers are routed to the original receiver with the original settings.
+func AddReceiverDigests(cfg *v1.AMConfigV1) error {
+	if len(cfg.ReceiverDigests) == 0 || cfg.AlertmanagerConfig.Route == nil {
+		return nil
+	}
+
+	digests := make(map[string]models.ReceiverDigest, len(cfg.ReceiverDigests))
+	var windows []time.Duration
+	receivers := cfg.AlertmanagerConfig.Receivers
+	for _, name := range slices.Sorted(maps.Keys(cfg.ReceiverDigests)) {
+		idx := slices.IndexFunc(receivers, func(r *v1.PostableApiReceiver) bool {
+			return r.Name == name
+		})
+		if idx < 0 {
+			continue
+		}
+		digest := cfg.ReceiverDigests[name]
+		digestReceiver, err := newDigestReceiver(receivers[idx], digest.Window)
+		if err != nil {
+			return fmt.Errorf("failed to create the digest receiver of %s: %w", name, err)
+		}
+		cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers, digestReceiver)
+		digests[name] = digest
+		if !slices.Contains(windows, digest.Window) {
+			windows = append(windows, digest.Window)
+		}
+	}
+	if len(digests) == 0 {
+		return nil
+	}
+
+	defaultOpts := dispatch.DefaultRouteOpts
+	groupWait := model.Duration(defaultOpts.GroupWait)
+	groupInterval := model.Duration(defaultOpts.GroupInterval)
+	addDigestToRoute(cfg.AlertmanagerConfig.Route, digests, digestRouteOpts{
+		groupWait:     &groupWait,
+		groupInterval: &groupInterval,
+	})
+
+	slices.Sort(windows)
+	tmpl := v1.NewTemplateGroup("", receiverDigestTemplateTitle, digestTemplates(windows), v1.TemplateKindGrafana, models.ProvenanceNone)
+	if cfg.Templates == nil {
+		cfg.Templates = make(map[v1.ResourceUID]v1.TemplateGroup, 1)
+	}
+	cfg.Templates[tmpl.UID] = tmpl
+	return nil
+}
+
+// newDigestReceiver returns a copy of the receiver whose integrations use the digest templates of the window.
+func newDigestReceiver(r *v1.PostableApiReceiver, window time.Duration) (*v1.PostableApiReceiver, error) {
+	result := &v1.PostableApiReceiver{
+		Name:                    models.ReceiverDigestName(r.Name),
+		GrafanaManagedReceivers: make([]*v1.PostableGrafanaReceiver, 0, len(r.GrafanaManagedReceivers)),
+	}
+	for _, integration := range r.GrafanaManagedReceivers {
+		cp := *integration
+		cp.UID = digestIntegrationUID(integration.UID)
+		fields, ok := receiverDigestFields[integration.Type]
+		if ok {
+			settings := map[string]any{}
+			if len(integration.Settings) > 0 {
+				if err := json.Unmarshal(integration.Settings, &settings); err != nil {
+					return nil, fmt.Errorf("failed to parse the settings of integration %s: %w", integration.UID, err)
+				}
+			}
+			setDefaultSetting(settings, fields.title, fmt.Sprintf(`{{ template %q . }}`, digestTemplateName(models.ReceiverDigestTitleTemplate, window)))
+			setDefaultSetting(settings, fields.message, fmt.Sprintf(`{{ template %q . }}`, digestTemplateName(models.ReceiverDigestMessageTemplate, window)))
+			raw, err := json.Marshal(settings)
+			if err != nil {
+				return nil, err
+			}
+			cp.Settings = raw
+		}
+		result.GrafanaManagedReceivers = append(result.GrafanaManagedReceivers, &cp)
+	}
+	return result, nil
+}
+
+// digestIntegrationUID returns the UID of the copy of the integration that sends the digests. The UID of the
+// integration is shortened and made unique with a hash if the suffix would make it longer than the limit.
+func digestIntegrationUID(uid string) string {
+	const suffix = "-digest"
+	if len(uid)+len(suffix) <= util.MaxUIDLength {
+		return uid + suffix
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(uid))
+	hash := fmt.Sprintf("-%08x", h.Sum32())
+	return uid[:util.MaxUIDLength-len(suffix)-len(hash)] + hash + suffix
+}
+
+func setDefaultSetting(settings map[string]any, key, value string) {
+	if key == "" {
+		return
+	}
+	if current, ok := settings[key].(string); ok && current != "" {
+		return
+	}
+	settings[key] = value
+}
+
+// digestRouteOpts are the settings a route passes down to its children.
+type digestRouteOpts struct {
+	groupByStr    []string
+	groupWait     *model.Duration
+	groupInterval *model.Duration
+}
+
+func addDigestToRoute(route *v1.Route, digests map[string]models.ReceiverDigest, parent digestRouteOpts) {
+	opts := parent
+	if len(route.GroupByStr) > 0 {
+		opts.groupByStr = route.GroupByStr
+	}
+	if route.GroupWait != nil {
+		opts.groupWait = route.GroupWait
+	}
+	if route.GroupInterval != nil {
+		opts.groupInterval = route.GroupInterval
+	}
+
+	digest, ok := digests[route.Receiver]
+	for _, child := range route.Routes {
+		if ok {
+			// The route is about to send digests, so pin the settings the child inherits from it.
+			pinDigestRouteOpts(child, route.Receiver, opts)
+		}
+		addDigestToRoute(child, digests, opts)
+	}
+	if !ok {
+		return
+	}
+
+	// The bypass route comes after the existing children so that they keep precedence.
+	if len(digest.BypassMatchers) > 0 {
+		bypass := &v1.Route{
+			ObjectMatchers: v1.ObjectMatchers(slices.Clone(digest.BypassMatchers)),
+		}
+		pinDigestRouteOpts(bypass, route.Receiver, opts)
+		route.Routes = append(route.Routes, bypass)
+	}
+
+	window := model.Duration(digest.Window)
+	route.Receiver = models.ReceiverDigestName(route.Receiver)
+	route.GroupByStr = []string{models.ReceiverDigestGroupByLabel}
+	route.GroupByAll, route.GroupBy = legacy_storage.ToGroupBy(route.GroupByStr...)
+	route.GroupWait = &window
+	route.GroupInterval = &window
+}
+
+func pinDigestRouteOpts(route *v1.Route, receiver string, opts digestRouteOpts) {
+	if route.Receiver == "" {
+		route.Receiver = receiver
+	}
+	if len(route.GroupByStr) == 0 && len(opts.groupByStr) > 0 {
+		route.GroupByStr = slices.Clone(opts.groupByStr)
+		route.GroupByAll, route.GroupBy = legacy_storage.ToGroupBy(route.GroupByStr...)
+	}
+	if route.GroupWait == nil {
+		route.GroupWait = opts.groupWait
+	}
+	if route.GroupInterval == nil {
+		route.GroupInterval = opts.groupInterval
+	}
+}
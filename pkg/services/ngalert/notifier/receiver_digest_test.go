@@ -0,0 +1,293 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	v1 "github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage/v1"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestAddReceiverDigests(t *testing.T) {
+	configGen := func(route *v1.Route, digests map[string]models.ReceiverDigest) *v1.AMConfigV1 {
+		return &v1.AMConfigV1{
+			AlertmanagerConfig: v1.PostableApiAlertingConfig{
+				Config: v1.Config{
+					Route: route,
+				},
+				Receivers: []*v1.PostableApiReceiver{
+					{
+						Name: "default",
+					},
+					{
+						Name: "team",
+						GrafanaManagedReceivers: []*v1.PostableGrafanaReceiver{
+							{
+								UID:      "slack-uid",
+								Name:     "team",
+								Type:     "slack",
+								Settings: definitions.RawMessage(`{"recipient":"#alerts","title":"custom title"}`),
+							},
+							{
+								UID:  "oncall-uid",
+								Name: "team",
+								Type: "oncall",
+							},
+						},
+					},
+				},
+			},
+			ReceiverDigests: digests,
+		}
+	}
+	duration := func(d time.Duration) *model.Duration {
+		md := model.Duration(d)
+		return &md
+	}
+	critical := labels.Matchers{labels.MustNewMatcher(labels.MatchEqual, "severity", "critical")}
+
+	t.Run("should not change the config without digests", func(t *testing.T) {
+		cfg := configGen(&v1.Route{Receiver: "team"}, nil)
+		require.NoError(t, AddReceiverDigests(cfg))
+		require.Equal(t, configGen(&v1.Route{Receiver: "team"}, nil), cfg)
+	})
+
+	t.Run("should ignore the digests of unknown receivers", func(t *testing.T) {
+		digests := map[string]models.ReceiverDigest{"unknown": {Window: time.Hour}}
+		cfg := configGen(&v1.Route{Receiver: "team"}, digests)
+		require.NoError(t, AddReceiverDigests(cfg))
+		require.Equal(t, configGen(&v1.Route{Receiver: "team"}, digests), cfg)
+	})
+
+	t.Run("should send the alerts of the routes of the receiver to the digest receiver", func(t *testing.T) {
+		child := &v1.Route{
+			Receiver:       "team",
+			ObjectMatchers: v1.ObjectMatchers{labels.MustNewMatcher(labels.MatchEqual, "team", "a")},
+			GroupByStr:     []string{"alertname"},
+		}
+		cfg := configGen(&v1.Route{Receiver: "default", Routes: []*v1.Route{child}}, map[string]models.ReceiverDigest{
+			"team": {Window: time.Hour, BypassMatchers: critical},
+		})
+		require.NoError(t, AddReceiverDigests(cfg))
+
+		route := cfg.AlertmanagerConfig.Route
+		require.Equal(t, "default", route.Receiver)
+		require.Len(t, route.Routes, 1)
+
+		digestRoute := route.Routes[0]
+		require.Equal(t, models.ReceiverDigestName("team"), digestRoute.Receiver)
+		require.Equal(t, []string{models.ReceiverDigestGroupByLabel}, digestRoute.GroupByStr)
+		require.Equal(t, duration(time.Hour), digestRoute.GroupWait)
+		require.Equal(t, duration(time.Hour), digestRoute.GroupInterval)
+
+		require.Len(t, digestRoute.Routes, 1)
+		bypass := digestRoute.Routes[0]
+		require.Equal(t, "team", bypass.Receiver)
+		require.Equal(t, critical.String(), labels.Matchers(bypass.ObjectMatchers).String())
+		require.Equal(t, []string{"alertname"}, bypass.GroupByStr)
+		require.Equal(t, duration(30*time.Second), bypass.GroupWait)
+		require.Equal(t, duration(5*time.Minute), bypass.GroupInterval)
+	})
+
+	t.Run("should pin the settings inherited by the child routes", func(t *testing.T) {
+		child := &v1.Route{
+			ObjectMatchers: v1.ObjectMatchers{labels.MustNewMatcher(labels.MatchEqual, "team", "a")},
+		}
+		cfg := configGen(&v1.Route{
+			Receiver:   "team",
+			GroupByStr: []string{"grafana_folder"},
+			GroupWait:  duration(time.Minute),
+			Routes:     []*v1.Route{child},
+		}, map[string]models.ReceiverDigest{
+			"team": {Window: 10 * time.Minute},
+		})
+		require.NoError(t, AddReceiverDigests(cfg))
+
+		route := cfg.AlertmanagerConfig.Route
+		require.Equal(t, models.ReceiverDigestName("team"), route.Receiver)
+		require.Len(t, route.Routes, 1)
+		require.Equal(t, "team", route.Routes[0].Receiver)
+		require.Equal(t, []string{"grafana_folder"}, route.Routes[0].GroupByStr)
+		require.Equal(t, duration(time.Minute), route.Routes[0].GroupWait)
+		require.Equal(t, duration(5*time.Minute), route.Routes[0].GroupInterval)
+	})
+
+	t.Run("should add the digest receiver and the templates", func(t *testing.T) {
+		cfg := configGen(&v1.Route{Receiver: "team"}, map[string]models.ReceiverDigest{
+			"team": {Window: time.Hour},
+		})
+		require.NoError(t, AddReceiverDigests(cfg))
+
+		receivers := cfg.AlertmanagerConfig.Receivers
+		require.Len(t, receivers, 3)
+		digestReceiver := receivers[2]
+		require.Equal(t, models.ReceiverDigestName("team"), digestReceiver.Name)
+		require.Len(t, digestReceiver.GrafanaManagedReceivers, 2)
+
+		slack := digestReceiver.GrafanaManagedReceivers[0]
+		require.Equal(t, "slack-uid-digest", slack.UID)
+		require.JSONEq(t, `{
+			"recipient": "#alerts",
+			"title": "custom title",
+			"text": "{{ template \"__digest_message_1h\" . }}"
+		}`, string(slack.Settings))
+
+		// Integrations without known title and message settings use their default templates.
+		oncall := digestReceiver.GrafanaManagedReceivers[1]
+		require.Equal(t, "oncall-uid-digest", oncall.UID)
+		require.Empty(t, oncall.Settings)
+
+		// The original receiver is not changed.
+		require.JSONEq(t, `{"recipient":"#alerts","title":"custom title"}`, string(receivers[1].GrafanaManagedReceivers[0].Settings))
+
+		tmpl, ok := cfg.Templates[v1.TemplateUID(v1.TemplateKindGrafana, receiverDigestTemplateTitle)]
+		require.True(t, ok)
+		require.Contains(t, tmpl.Content, `{{ define "__digest_title_1h" -}}`)
+		require.Contains(t, tmpl.Content, `{{ define "__digest_message_1h" -}}`)
+		require.Contains(t, tmpl.Content, "lt (since .StartsAt).Nanoseconds 3600000000000")
+	})
+
+	t.Run("should define the templates once per window", func(t *testing.T) {
+		cfg := configGen(&v1.Route{Receiver: "team"}, map[string]models.ReceiverDigest{
+			"default": {Window: 10 * time.Minute},
+			"team":    {Window: 10 * time.Minute},
+		})
+		require.NoError(t, AddReceiverDigests(cfg))
+
+		tmpl := cfg.Templates[v1.TemplateUID(v1.TemplateKindGrafana, receiverDigestTemplateTitle)]
+		require.Equal(t, 1, strings.Count(tmpl.Content, `{{ define "__digest_message_10m" -}}`))
+	})
+}
+
+func TestDigestIntegrationUID(t *testing.T) {
+	require.Equal(t, "slack-uid-digest", digestIntegrationUID("slack-uid"))
+
+	long := strings.Repeat("a", util.MaxUIDLength)
+	uid := digestIntegrationUID(long)
+	require.Len(t, uid, util.MaxUIDLength)
+	require.True(t, strings.HasSuffix(uid, "-digest"))
+	require.NotEqual(t, uid, digestIntegrationUID(strings.Repeat("a", util.MaxUIDLength-1)+"b"))
+}
+
+func TestIntegrationDigestTemplates(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	am := setupAMTest(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	alertGen := func(name, severity string, startsAt time.Time, resolved bool) *amv2.PostableAlert {
+		lbls := amv2.LabelSet{model.AlertNameLabel: name}
+		if severity != "" {
+			lbls["severity"] = severity
+		}
+		endsAt := now.Add(time.Hour)
+		if resolved {
+			endsAt = now.Add(-time.Minute)
+		}
+		return &amv2.PostableAlert{
+			Alert:    amv2.Alert{Labels: lbls},
+			StartsAt: strfmt.DateTime(startsAt),
+			EndsAt:   strfmt.DateTime(endsAt),
+		}
+	}
+	since := func(startsAt time.Time) string {
+		return startsAt.Format("2006-01-02 15:04:05 MST")
+	}
+
+	// render executes the title and the message of the digests of a window of one hour.
+	render := func(t *testing.T, alerts ...*amv2.PostableAlert) (string, string) {
+		t.Helper()
+		res, err := am.TestTemplate(context.Background(), definitions.TestTemplatesConfigBodyParams{
+			Alerts:   alerts,
+			Name:     receiverDigestTemplateTitle,
+			Template: digestTemplates([]time.Duration{time.Hour}),
+		})
+		require.NoError(t, err)
+		text := func(name string) string {
+			idx := slices.IndexFunc(res.Results, func(r alertingNotify.TestTemplatesResult) bool {
+				return r.Name == name
+			})
+			require.NotEqualf(t, -1, idx, "template %s was not rendered: %v", name, res.Errors)
+			return res.Results[idx].Text
+		}
+		return text(digestTemplateName(models.ReceiverDigestTitleTemplate, time.Hour)),
+			text(digestTemplateName(models.ReceiverDigestMessageTemplate, time.Hour))
+	}
+
+	t.Run("should summarize firing and resolved alerts", func(t *testing.T) {
+		cpuCritical := now.Add(-10 * time.Minute)
+		cpuWarning := now.Add(-2 * time.Hour)
+		disk := now.Add(-3 * time.Hour)
+		title, message := render(t,
+			alertGen("CPU", "critical", cpuCritical, false),
+			alertGen("CPU", "warning", cpuWarning, false),
+			alertGen("Disk", "critical", disk, false),
+			alertGen("Disk", "", disk, false),
+			alertGen("Memory", "info", now.Add(-time.Hour), true),
+		)
+
+		require.Equal(t, "[DIGEST] 1 new, 4 firing, 1 resolved", title)
+		require.Equal(t, "1 new firing alerts since the last digest, 4 firing in total and 1 resolved since the last digest.\n"+
+			"\n"+
+			"Firing by rule:\n"+
+			"- CPU: 2\n"+
+			"- Disk: 2\n"+
+			"\n"+
+			"Firing by severity:\n"+
+			"- critical: 2\n"+
+			"- warning: 1\n"+
+			"- none: 1\n"+
+			"\n"+
+			"Top firing:\n"+
+			"- CPU [critical] since "+since(cpuCritical)+"\n"+
+			"- Disk [critical] since "+since(disk)+"\n"+
+			"- CPU [warning] since "+since(cpuWarning)+"\n"+
+			"- Disk since "+since(disk)+"\n"+
+			"\n"+
+			"Resolved by rule:\n"+
+			"- Memory: 1\n", message)
+	})
+
+	t.Run("should show at most 10 top firing alerts", func(t *testing.T) {
+		alerts := make([]*amv2.PostableAlert, 0, 12)
+		for i := range 12 {
+			alert := alertGen("CPU", "critical", now.Add(-2*time.Hour), false)
+			alert.Labels["instance"] = fmt.Sprintf("server-%d", i)
+			alerts = append(alerts, alert)
+		}
+		title, message := render(t, alerts...)
+
+		require.Equal(t, "[DIGEST] 0 new, 12 firing, 0 resolved", title)
+		require.Equal(t, 10, strings.Count(message, "- CPU [critical] since"))
+		require.True(t, strings.HasSuffix(message, "and 2 more\n"), message)
+		require.NotContains(t, message, "Resolved by rule:")
+	})
+
+	t.Run("should only count resolved alerts if none is firing", func(t *testing.T) {
+		first := alertGen("Memory", "info", now.Add(-time.Hour), true)
+		first.Labels["instance"] = "server-1"
+		second := alertGen("Memory", "info", now.Add(-time.Hour), true)
+		second.Labels["instance"] = "server-2"
+		title, message := render(t, first, second)
+
+		require.Equal(t, "[DIGEST] 0 new, 0 firing, 2 resolved", title)
+		require.Equal(t, "0 new firing alerts since the last digest, 0 firing in total and 2 resolved since the last digest.\n"+
+			"\n"+
+			"Resolved by rule:\n"+
+			"- Memory: 2\n", message)
+	})
+}
@@ -586,6 +586,114 @@ func (rs *ReceiverService) UpdateReceiver(ctx context.Context, r *models.Receive
 	return result, nil
 }
 
+// GetReceiverDigest returns the digest settings of a receiver by its UID.
+// It returns models.ErrReceiverDigestNotFound if the digest mode of the receiver is not enabled.
+func (rs *ReceiverService) GetReceiverDigest(ctx context.Context, uid string, orgID int64, user identity.Requester) (*models.ReceiverDigest, error) {
+	ctx, span := rs.tracer.Start(ctx, "alerting.receivers.digest.get", trace.WithAttributes(
+		attribute.String("uid", uid),
+	))
+	defer span.End()
+
+	revision, err := rs.cfgStore.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	prov, err := rs.loadProvenances(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := revision.GetReceiver(uid, prov)
+	if err != nil {
+		return nil, err
+	}
+	if err := rs.authz.AuthorizeRead(ctx, user, existing); err != nil {
+		return nil, err
+	}
+
+	digest, ok := revision.GetReceiverDigest(existing.Name)
+	if !ok {
+		return nil, models.ErrReceiverDigestNotFound.Errorf("")
+	}
+	return &digest, nil
+}
+
+// SetReceiverDigest enables or updates the digest mode of a receiver by its UID. While the digest mode is enabled,
+// the alerts routed to the receiver are accumulated and sent as a single summarized notification per window.
+func (rs *ReceiverService) SetReceiverDigest(ctx context.Context, uid string, digest models.ReceiverDigest, orgID int64, user identity.Requester) (*models.ReceiverDigest, error) {
+	ctx, span := rs.tracer.Start(ctx, "alerting.receivers.digest.set", trace.WithAttributes(
+		attribute.String("uid", uid),
+		attribute.String("window", digest.Window.String()),
+	))
+	defer span.End()
+
+	if err := digest.Validate(); err != nil {
+		return nil, models.ErrReceiverInvalid(err)
+	}
+
+	revision, err := rs.cfgStore.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	prov, err := rs.loadProvenances(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := revision.GetReceiver(uid, prov)
+	if err != nil {
+		return nil, err
+	}
+	if err := rs.authz.AuthorizeUpdate(ctx, user, existing); err != nil {
+		return nil, err
+	}
+	if err := rs.provenanceValidator(ctx, existing.Provenance, models.ProvenanceNone); err != nil {
+		return nil, err
+	}
+
+	revision.SetReceiverDigest(existing.Name, digest)
+	if err := rs.cfgStore.Save(ctx, revision, orgID); err != nil {
+		return nil, err
+	}
+	rs.log.FromContext(ctx).Info("Enabled receiver digest", "receiver", existing.Name, "uid", uid, "window", digest.Window, "bypassMatchers", digest.BypassMatchers.String())
+	return &digest, nil
+}
+
+// DeleteReceiverDigest disables the digest mode of a receiver by its UID.
+// It returns models.ErrReceiverDigestNotFound if the digest mode of the receiver is not enabled.
+func (rs *ReceiverService) DeleteReceiverDigest(ctx context.Context, uid string, orgID int64, user identity.Requester) error {
+	ctx, span := rs.tracer.Start(ctx, "alerting.receivers.digest.delete", trace.WithAttributes(
+		attribute.String("uid", uid),
+	))
+	defer span.End()
+
+	revision, err := rs.cfgStore.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	prov, err := rs.loadProvenances(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	existing, err := revision.GetReceiver(uid, prov)
+	if err != nil {
+		return err
+	}
+	if err := rs.authz.AuthorizeUpdate(ctx, user, existing); err != nil {
+		return err
+	}
+	if err := rs.provenanceValidator(ctx, existing.Provenance, models.ProvenanceNone); err != nil {
+		return err
+	}
+
+	if !revision.DeleteReceiverDigest(existing.Name) {
+		return models.ErrReceiverDigestNotFound.Errorf("")
+	}
+	if err := rs.cfgStore.Save(ctx, revision, orgID); err != nil {
+		return err
+	}
+	rs.log.FromContext(ctx).Info("Disabled receiver digest", "receiver", existing.Name, "uid", uid)
+	return nil
+}
+
 func (rs *ReceiverService) UsedByRules(ctx context.Context, orgID int64, name string) ([]models.AlertRuleKey, error) {
 	keys, err := rs.ruleNotificationsStore.ListContactPointRoutings(ctx, models.ListContactPointRoutingsQuery{OrgID: orgID, ReceiverName: name})
 	if err != nil {
@@ -820,6 +928,8 @@ func (rs *ReceiverService) RenameReceiverInDependentResources(ctx context.Contex
 	))
 	defer span.End()
 
+	revision.RenameReceiverDigest(oldName, newName)
+
 	validate := validation.ValidateProvenanceOfDependentResources(receiverProvenance)
 	// if there are no references to the old time interval, exit
 	canUpdate := true
@@ -371,6 +371,11 @@ func (p *redisPeer) filterUnhealthyMembers(members []string, values []any) []str
 	return peers
 }
 
+// Name returns the name of the peer as it appears in Members.
+func (p *redisPeer) Name() string {
+	return p.withPrefix(p.name)
+}
+
 func (p *redisPeer) Position() int {
 	for i, peer := range p.Members() {
 		if peer == p.withPrefix(p.name) {
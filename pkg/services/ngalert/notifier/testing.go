@@ -472,7 +472,6 @@ type MockBroadcastChannel struct {
 func withPeer(peer alertingNotify.ClusterPeer) Option {
 	return func(moa *MultiOrgAlertmanager) {
 		moa.peer = peer
-		moa.initAlertBroadcast()
 	}
 }
 
@@ -0,0 +1,397 @@
+package prom
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleConversionFailure describes a Grafana alert rule that cannot be converted to a Prometheus rule.
+type RuleConversionFailure struct {
+	Rule   *models.AlertRule
+	Reason string
+}
+
+// RuleConversionWarning describes a setting of a Grafana alert rule that the converted Prometheus rule does not have.
+// The rule is converted, but it does not behave the same in Prometheus.
+type RuleConversionWarning struct {
+	Rule *models.AlertRule
+	// Field is the name of the setting in the API of Grafana-managed rules.
+	Field  string
+	Reason string
+}
+
+// exportQueryModel contains the fields of a Prometheus or Loki query model that are needed to
+// convert the query to a Prometheus rule.
+type exportQueryModel struct {
+	Expr      string `json:"expr"`
+	Instant   bool   `json:"instant"`
+	Range     bool   `json:"range"`
+	QueryType string `json:"queryType"`
+}
+
+func (m exportQueryModel) isInstant() bool {
+	return (m.Instant && !m.Range) || m.QueryType == "instant"
+}
+
+// GrafanaRuleGroupToPrometheus converts a Grafana rule group into a Prometheus rule group.
+// datasourceTypes maps the UIDs of the data sources that the rules query to their types.
+//
+// The rules that cannot be converted are not added to the group, they are returned with the reason instead.
+// A rule can be converted if it queries a single Prometheus-compatible or Loki data source and the condition
+// is a threshold on that query, optionally reduced with a reducer that does not change the value of an instant
+// query. The settings of the converted rules that Prometheus does not support are returned as warnings.
+func GrafanaRuleGroupToPrometheus(group string, rules []models.AlertRule, datasourceTypes map[string]string) (PrometheusRuleGroup, []RuleConversionFailure, []RuleConversionWarning) {
+	result := PrometheusRuleGroup{
+		Name:  group,
+		Rules: make([]PrometheusRule, 0, len(rules)),
+	}
+	if len(rules) == 0 {
+		return result, nil, nil
+	}
+	result.Interval = prommodel.Duration(time.Duration(rules[0].IntervalSeconds) * time.Second)
+
+	var failures []RuleConversionFailure
+	var warnings []RuleConversionWarning
+	var offset *time.Duration
+	for i := range rules {
+		rule := &rules[i]
+		promRule, ruleOffset, err := grafanaRuleToPrometheus(rule, datasourceTypes)
+		if err == nil && offset != nil && *offset != ruleOffset {
+			err = fmt.Errorf("query offset %s is different from the query offset %s of the other rules in the group", ruleOffset, *offset)
+		}
+		if err != nil {
+			failures = append(failures, RuleConversionFailure{Rule: rule, Reason: err.Error()})
+			continue
+		}
+		if offset == nil {
+			offset = &ruleOffset
+		}
+		result.Rules = append(result.Rules, promRule)
+		warnings = append(warnings, unsupportedSettings(rule)...)
+	}
+	if offset != nil && *offset > 0 {
+		result.QueryOffset = new(prommodel.Duration(*offset))
+	}
+
+	return result, failures, warnings
+}
+
+// grafanaRuleToPrometheus converts a Grafana alert rule into a Prometheus rule. It also returns the
+// evaluation offset of the rule query, which is set on the level of the group in Prometheus.
+func grafanaRuleToPrometheus(rule *models.AlertRule, datasourceTypes map[string]string) (PrometheusRule, time.Duration, error) {
+	if rule.IsPaused {
+		return PrometheusRule{}, 0, fmt.Errorf("rule is paused, Prometheus rules cannot be paused")
+	}
+
+	query, err := exportedQuery(rule, datasourceTypes)
+	if err != nil {
+		return PrometheusRule{}, 0, err
+	}
+	offset := time.Duration(query.RelativeTimeRange.To)
+
+	promRule, err := convertRule(rule, query)
+	if err != nil {
+		return PrometheusRule{}, 0, err
+	}
+
+	// The original definition of an imported rule is exported only if the rule was not changed since the
+	// import, otherwise the changes would be lost.
+	if definition, err := rule.PrometheusRuleDefinition(); err == nil {
+		var original PrometheusRule
+		if err := yaml.Unmarshal([]byte(definition), &original); err == nil && equalRules(original, promRule) {
+			return original, offset, nil
+		}
+	}
+	return promRule, offset, nil
+}
+
+// convertRule converts the current settings of a Grafana alert rule into a Prometheus rule.
+func convertRule(rule *models.AlertRule, query models.AlertQuery) (PrometheusRule, error) {
+	var model exportQueryModel
+	if err := json.Unmarshal(query.Model, &model); err != nil {
+		return PrometheusRule{}, fmt.Errorf("failed to unmarshal the model of query %s: %w", query.RefID, err)
+	}
+	if model.Expr == "" {
+		return PrometheusRule{}, fmt.Errorf("query %s has no expression", query.RefID)
+	}
+
+	labels := make(map[string]string, len(rule.Labels))
+	maps.Copy(labels, rule.Labels)
+	delete(labels, models.ConvertedPrometheusRuleLabel)
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	if rule.Type() == models.RuleTypeRecording {
+		if rule.Record.From != query.RefID {
+			return PrometheusRule{}, fmt.Errorf("recording rule records expression %s, only the result of a data source query can be recorded", rule.Record.From)
+		}
+		return PrometheusRule{
+			Record: rule.Record.Metric,
+			Expr:   model.Expr,
+			Labels: labels,
+		}, nil
+	}
+
+	promExpr, err := alertingExpression(rule, query, model)
+	if err != nil {
+		return PrometheusRule{}, err
+	}
+
+	promRule := PrometheusRule{
+		Alert:       rule.Title,
+		Expr:        promExpr,
+		Labels:      labels,
+		Annotations: rule.Annotations,
+	}
+	if rule.For > 0 {
+		promRule.For = new(prommodel.Duration(rule.For))
+	}
+	if rule.KeepFiringFor > 0 {
+		promRule.KeepFiringFor = new(prommodel.Duration(rule.KeepFiringFor))
+	}
+	return promRule, nil
+}
+
+// equalRules returns true if the rules have the same settings.
+func equalRules(a, b PrometheusRule) bool {
+	duration := func(d *prommodel.Duration) prommodel.Duration {
+		if d == nil {
+			return 0
+		}
+		return *d
+	}
+	return a.Alert == b.Alert &&
+		a.Record == b.Record &&
+		a.Expr == b.Expr &&
+		duration(a.For) == duration(b.For) &&
+		duration(a.KeepFiringFor) == duration(b.KeepFiringFor) &&
+		maps.Equal(a.Labels, b.Labels) &&
+		maps.Equal(a.Annotations, b.Annotations)
+}
+
+// unsupportedSettings returns a warning for each setting of the alert rule that makes it behave
+// differently from the converted Prometheus rule.
+func unsupportedSettings(rule *models.AlertRule) []RuleConversionWarning {
+	if rule.Type() == models.RuleTypeRecording {
+		return nil
+	}
+	var result []RuleConversionWarning
+	warn := func(field, reason string, args ...any) {
+		result = append(result, RuleConversionWarning{Rule: rule, Field: field, Reason: fmt.Sprintf(reason, args...)})
+	}
+	switch rule.NoDataState {
+	case "", models.OK:
+	default:
+		warn("no_data_state", "state %s is not supported, Prometheus rules do not fire when the query returns no data", rule.NoDataState)
+	}
+	switch rule.ExecErrState {
+	case "", models.OkErrState, models.KeepLastErrState:
+	default:
+		warn("exec_err_state", "state %s is not supported, Prometheus rules do not fire when the query fails", rule.ExecErrState)
+	}
+	if n := rule.GetMissingSeriesEvalsToResolve(); n != 1 {
+		warn("missing_series_evals_to_resolve", "alerts are resolved after %d evaluations without the series, Prometheus resolves them at the first one", n)
+	}
+	if rule.NotificationSettings != nil {
+		warn("notification_settings", "notification settings are not supported, Prometheus alerts are routed by the notification policies of the Alertmanager")
+	}
+	return result
+}
+
+// exportedQuery returns the only data source query of the rule, if it queries a data source of a convertible type.
+func exportedQuery(rule *models.AlertRule, datasourceTypes map[string]string) (models.AlertQuery, error) {
+	var queries []models.AlertQuery
+	for _, q := range rule.Data {
+		if isExpr, _ := q.IsExpression(); !isExpr {
+			queries = append(queries, q)
+		}
+	}
+	if len(queries) != 1 {
+		return models.AlertQuery{}, fmt.Errorf("rule must have exactly one data source query, found %d", len(queries))
+	}
+
+	query := queries[0]
+	dsType, ok := datasourceTypes[query.DatasourceUID]
+	if !ok {
+		return models.AlertQuery{}, fmt.Errorf("data source %s of query %s is not found", query.DatasourceUID, query.RefID)
+	}
+	if !isConvertibleDatasourceType(dsType) {
+		return models.AlertQuery{}, fmt.Errorf("query %s uses data source of type %s, must be prometheus-compatible or loki", query.RefID, dsType)
+	}
+	return query, nil
+}
+
+// alertingExpression builds the expression of a Prometheus alerting rule from the condition of the rule.
+// The condition must be a threshold expression whose input is the instant query of the rule, either
+// directly, through a reduce expression or through the math expression that the import creates.
+func alertingExpression(rule *models.AlertRule, query models.AlertQuery, model exportQueryModel) (string, error) {
+	nodes := make(map[string]models.AlertQuery, len(rule.Data))
+	for _, q := range rule.Data {
+		nodes[q.RefID] = q
+	}
+
+	condition, ok := nodes[rule.Condition]
+	if !ok {
+		return "", fmt.Errorf("condition %s is not found", rule.Condition)
+	}
+	if condition.RefID == query.RefID {
+		return "", fmt.Errorf("condition must be a threshold expression, not a data source query")
+	}
+	var threshold expr.ThresholdQuery
+	if err := unmarshalExpression(condition, expr.QueryTypeThreshold, &threshold); err != nil {
+		return "", err
+	}
+	if len(threshold.Conditions) != 1 {
+		return "", fmt.Errorf("threshold expression %s must have exactly one condition, found %d", condition.RefID, len(threshold.Conditions))
+	}
+	if threshold.Conditions[0].UnloadEvaluator != nil {
+		return "", fmt.Errorf("threshold expression %s has a recovery threshold, which Prometheus does not support", condition.RefID)
+	}
+
+	input, ok := nodes[expressionRefID(threshold.Expression)]
+	if !ok {
+		return "", fmt.Errorf("input %s of threshold expression %s is not found", threshold.Expression, condition.RefID)
+	}
+	isSeriesCheck := false
+	if input.RefID != query.RefID {
+		var err error
+		isSeriesCheck, err = checkPassThroughExpression(input, query.RefID)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !model.isInstant() {
+		return "", fmt.Errorf("query %s must be an instant query", query.RefID)
+	}
+
+	evaluator := threshold.Conditions[0].Evaluator
+	if isSeriesCheck {
+		// The math expression of imported rules is 1 for every series that the query returns,
+		// so the rule fires for every series, like in Prometheus.
+		if evaluator.Type != expr.ThresholdIsAbove || len(evaluator.Params) != 1 || evaluator.Params[0] != 0 {
+			return "", fmt.Errorf("threshold expression %s must check that the result of math expression %s is above 0", condition.RefID, input.RefID)
+		}
+		return model.Expr, nil
+	}
+	return thresholdExpression(model.Expr, evaluator)
+}
+
+// checkPassThroughExpression returns an error if the expression can change the result of an instant query.
+// Such expressions are a reduce expression that keeps the only value of each series and the math expression
+// that the import of Prometheus rules adds to keep the Prometheus behavior. It returns true for the latter,
+// since it turns every value into 1.
+func checkPassThroughExpression(node models.AlertQuery, queryRefID string) (bool, error) {
+	exprType, err := node.GetExpressionType()
+	if err != nil {
+		return false, err
+	}
+
+	switch expr.QueryType(exprType) {
+	case expr.QueryTypeReduce:
+		var reduce expr.ReduceQuery
+		if err := unmarshalExpression(node, expr.QueryTypeReduce, &reduce); err != nil {
+			return false, err
+		}
+		if expressionRefID(reduce.Expression) != queryRefID {
+			return false, fmt.Errorf("reduce expression %s must reduce the data source query %s", node.RefID, queryRefID)
+		}
+		switch reduce.Reducer {
+		case mathexp.ReducerLast, mathexp.ReducerMean, mathexp.ReducerMin, mathexp.ReducerMax, mathexp.ReducerSum, mathexp.ReducerMedian:
+		default:
+			return false, fmt.Errorf("reducer %s of expression %s is not supported", reduce.Reducer, node.RefID)
+		}
+		if reduce.Settings != nil && reduce.Settings.Mode == expr.ReduceModeReplace {
+			return false, fmt.Errorf("reduce expression %s replaces non-numeric values, which Prometheus does not support", node.RefID)
+		}
+		return false, nil
+	case expr.QueryTypeMath:
+		var math expr.MathQuery
+		if err := unmarshalExpression(node, expr.QueryTypeMath, &math); err != nil {
+			return false, err
+		}
+		if math.Expression != fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", queryRefID) {
+			return false, fmt.Errorf("math expression %s is not supported", node.RefID)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("expression %s of type %s is not supported", node.RefID, exprType)
+	}
+}
+
+func unmarshalExpression(node models.AlertQuery, queryType expr.QueryType, v any) error {
+	exprType, err := node.GetExpressionType()
+	if err != nil {
+		return err
+	}
+	if expr.QueryType(exprType) != queryType {
+		return fmt.Errorf("expression %s must be of type %s, got %s", node.RefID, queryType, exprType)
+	}
+	if err := json.Unmarshal(node.Model, v); err != nil {
+		return fmt.Errorf("failed to unmarshal the model of expression %s: %w", node.RefID, err)
+	}
+	return nil
+}
+
+// expressionRefID returns the RefID that an expression refers to, with or without the $ prefix.
+func expressionRefID(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "$")
+	return strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+}
+
+// thresholdExpression appends the comparison of the threshold to the query expression.
+// Prometheus and Loki filter out the series for which a comparison is false, which matches
+// the behavior of a threshold on an instant query.
+func thresholdExpression(query string, evaluator expr.ConditionEvalJSON) (string, error) {
+	var operators []string
+	switch evaluator.Type {
+	case expr.ThresholdIsAbove:
+		operators = []string{">"}
+	case expr.ThresholdIsBelow:
+		operators = []string{"<"}
+	case expr.ThresholdIsEqual:
+		operators = []string{"=="}
+	case expr.ThresholdIsNotEqual:
+		operators = []string{"!="}
+	case expr.ThresholdIsGreaterThanEqual:
+		operators = []string{">="}
+	case expr.ThresholdIsLessThanEqual:
+		operators = []string{"<="}
+	case expr.ThresholdIsWithinRange, expr.ThresholdIsOutsideRange:
+		operators = []string{">", "<"}
+	case expr.ThresholdIsWithinRangeIncluded, expr.ThresholdIsOutsideRangeIncluded:
+		operators = []string{">=", "<="}
+	default:
+		return "", fmt.Errorf("threshold type %s is not supported", evaluator.Type)
+	}
+	if len(evaluator.Params) != len(operators) {
+		return "", fmt.Errorf("threshold type %s requires %d parameters, got %d", evaluator.Type, len(operators), len(evaluator.Params))
+	}
+
+	params := make([]string, 0, len(evaluator.Params))
+	for _, p := range evaluator.Params {
+		params = append(params, strconv.FormatFloat(p, 'g', -1, 64))
+	}
+
+	switch evaluator.Type {
+	case expr.ThresholdIsWithinRange, expr.ThresholdIsWithinRangeIncluded:
+		return fmt.Sprintf("(%s) %s %s %s %s", query, operators[0], params[0], operators[1], params[1]), nil
+	case expr.ThresholdIsOutsideRange, expr.ThresholdIsOutsideRangeIncluded:
+		// Outside of the range the value is below the lower bound or above the upper bound.
+		return fmt.Sprintf("(%[1]s) %[2]s %[3]s or (%[1]s) %[4]s %[5]s", query, operators[1], params[0], operators[0], params[1]), nil
+	default:
+		return fmt.Sprintf("(%s) %s %s", query, operators[0], params[0]), nil
+	}
+}
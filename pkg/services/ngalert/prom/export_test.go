@@ -0,0 +1,246 @@
+package prom
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestGrafanaRuleGroupToPrometheus_RoundTrip(t *testing.T) {
+	promGroup := PrometheusRuleGroup{
+		Name:        "test-group",
+		Interval:    prommodel.Duration(30 * time.Second),
+		QueryOffset: new(prommodel.Duration(time.Minute)),
+		Rules: []PrometheusRule{
+			{
+				Alert:         "HighCPU",
+				Expr:          "cpu_usage > 80",
+				For:           new(prommodel.Duration(5 * time.Minute)),
+				KeepFiringFor: new(prommodel.Duration(time.Minute)),
+				Labels:        map[string]string{"severity": "critical"},
+				Annotations:   map[string]string{"summary": "CPU usage is critical"},
+			},
+			{
+				Record: "job:cpu_usage:sum",
+				Expr:   "sum by (job) (cpu_usage)",
+				Labels: map[string]string{"team": "platform"},
+			},
+		},
+	}
+	datasourceTypes := map[string]string{"datasource-uid": datasources.DS_PROMETHEUS}
+
+	for _, keepOriginal := range []bool{true, false} {
+		t.Run(fmt.Sprintf("keep original rule definition %t", keepOriginal), func(t *testing.T) {
+			converter, err := NewConverter(Config{
+				DatasourceUID:              "datasource-uid",
+				DatasourceType:             datasources.DS_PROMETHEUS,
+				DefaultInterval:            time.Minute,
+				KeepOriginalRuleDefinition: new(keepOriginal),
+			})
+			require.NoError(t, err)
+			grafanaGroup, err := converter.PrometheusRulesToGrafana(1, "namespace-uid", promGroup)
+			require.NoError(t, err)
+
+			exported, failures, warnings := GrafanaRuleGroupToPrometheus(grafanaGroup.Title, grafanaGroup.Rules, datasourceTypes)
+			require.Empty(t, failures)
+			require.Empty(t, warnings)
+			require.Equal(t, promGroup, exported)
+		})
+	}
+
+	t.Run("imported rule that was changed", func(t *testing.T) {
+		converter, err := NewConverter(Config{
+			DatasourceUID:   "datasource-uid",
+			DatasourceType:  datasources.DS_PROMETHEUS,
+			DefaultInterval: time.Minute,
+		})
+		require.NoError(t, err)
+		grafanaGroup, err := converter.PrometheusRulesToGrafana(1, "namespace-uid", promGroup)
+		require.NoError(t, err)
+		grafanaGroup.Rules[0].Title = "VeryHighCPU"
+		grafanaGroup.Rules[0].For = 10 * time.Minute
+
+		exported, failures, _ := GrafanaRuleGroupToPrometheus(grafanaGroup.Title, grafanaGroup.Rules, datasourceTypes)
+		require.Empty(t, failures)
+		require.Equal(t, "VeryHighCPU", exported.Rules[0].Alert)
+		require.Equal(t, new(prommodel.Duration(10*time.Minute)), exported.Rules[0].For)
+	})
+}
+
+func TestGrafanaRuleGroupToPrometheus(t *testing.T) {
+	datasourceTypes := map[string]string{
+		"prometheus": datasources.DS_PROMETHEUS,
+		"loki":       datasources.DS_LOKI,
+		"testdata":   "grafana-testdata-datasource",
+	}
+	query := func(refID, dsUID string, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: dsUID, Model: []byte(model)}
+	}
+	expression := func(refID string, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: expr.DatasourceUID, Model: []byte(model)}
+	}
+	instantQuery := query("A", "prometheus", `{"expr":"up","instant":true}`)
+	reduce := func(reducer string) models.AlertQuery {
+		return expression("B", fmt.Sprintf(`{"type":"reduce","expression":"A","reducer":%q}`, reducer))
+	}
+	threshold := func(input string, evaluator string) models.AlertQuery {
+		return expression("C", fmt.Sprintf(`{"type":"threshold","expression":%q,"conditions":[{"evaluator":%s}]}`, input, evaluator))
+	}
+	rule := func(data ...models.AlertQuery) models.AlertRule {
+		return models.AlertRule{
+			UID:             "uid",
+			Title:           "rule",
+			IntervalSeconds: 60,
+			Condition:       data[len(data)-1].RefID,
+			Data:            data,
+			For:             time.Minute,
+			Labels:          map[string]string{"severity": "warning"},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		rule   models.AlertRule
+		expr   string
+		reason string
+	}{
+		{
+			name: "threshold on an instant query",
+			rule: rule(instantQuery, threshold("A", `{"type":"gt","params":[0.5]}`)),
+			expr: "(up) > 0.5",
+		},
+		{
+			name: "threshold on a reduced instant query",
+			rule: rule(instantQuery, reduce("last"), threshold("B", `{"type":"lte","params":[1]}`)),
+			expr: "(up) <= 1",
+		},
+		{
+			name: "threshold on a Loki instant query",
+			rule: rule(query("A", "loki", `{"expr":"count_over_time({job=\"app\"}[5m])","queryType":"instant"}`), threshold("$A", `{"type":"ne","params":[2]}`)),
+			expr: `(count_over_time({job="app"}[5m])) != 2`,
+		},
+		{
+			name: "within range threshold",
+			rule: rule(instantQuery, threshold("A", `{"type":"within_range","params":[1,10]}`)),
+			expr: "(up) > 1 < 10",
+		},
+		{
+			name: "outside range threshold",
+			rule: rule(instantQuery, threshold("A", `{"type":"outside_range_included","params":[1,10]}`)),
+			expr: "(up) <= 1 or (up) >= 10",
+		},
+		{
+			name:   "range query",
+			rule:   rule(query("A", "prometheus", `{"expr":"up","range":true}`), reduce("mean"), threshold("B", `{"type":"gt","params":[0]}`)),
+			reason: "query A must be an instant query",
+		},
+		{
+			name:   "reducer that changes the value",
+			rule:   rule(instantQuery, reduce("count"), threshold("B", `{"type":"gt","params":[0]}`)),
+			reason: "reducer count of expression B is not supported",
+		},
+		{
+			name:   "unsupported expression",
+			rule:   rule(instantQuery, expression("B", `{"type":"math","expression":"$A * 2"}`), threshold("B", `{"type":"gt","params":[0]}`)),
+			reason: "math expression B is not supported",
+		},
+		{
+			name:   "recovery threshold",
+			rule:   rule(instantQuery, expression("C", `{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[5]},"unloadEvaluator":{"type":"lt","params":[3]}}]}`)),
+			reason: "threshold expression C has a recovery threshold, which Prometheus does not support",
+		},
+		{
+			name:   "several data source queries",
+			rule:   rule(instantQuery, query("B", "prometheus", `{"expr":"down","instant":true}`), threshold("A", `{"type":"gt","params":[0]}`)),
+			reason: "rule must have exactly one data source query, found 2",
+		},
+		{
+			name:   "data source of unsupported type",
+			rule:   rule(query("A", "testdata", `{}`), threshold("A", `{"type":"gt","params":[0]}`)),
+			reason: "query A uses data source of type grafana-testdata-datasource, must be prometheus-compatible or loki",
+		},
+		{
+			name:   "unknown data source",
+			rule:   rule(query("A", "missing", `{"expr":"up","instant":true}`), threshold("A", `{"type":"gt","params":[0]}`)),
+			reason: "data source missing of query A is not found",
+		},
+		{
+			name: "paused rule",
+			rule: func() models.AlertRule {
+				r := rule(instantQuery, threshold("A", `{"type":"gt","params":[0]}`))
+				r.IsPaused = true
+				return r
+			}(),
+			reason: "rule is paused, Prometheus rules cannot be paused",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group, failures, _ := GrafanaRuleGroupToPrometheus("group", []models.AlertRule{tc.rule}, datasourceTypes)
+			require.Equal(t, "group", group.Name)
+			require.Equal(t, prommodel.Duration(time.Minute), group.Interval)
+			if tc.reason != "" {
+				require.Empty(t, group.Rules)
+				require.Len(t, failures, 1)
+				require.Equal(t, tc.rule.UID, failures[0].Rule.UID)
+				require.Equal(t, tc.reason, failures[0].Reason)
+				return
+			}
+			require.Empty(t, failures)
+			require.Equal(t, []PrometheusRule{{
+				Alert:  "rule",
+				Expr:   tc.expr,
+				For:    new(prommodel.Duration(time.Minute)),
+				Labels: map[string]string{"severity": "warning"},
+			}}, group.Rules)
+		})
+	}
+
+	t.Run("rules with a different query offset are not converted", func(t *testing.T) {
+		first := rule(instantQuery, threshold("A", `{"type":"gt","params":[0]}`))
+		second := rule(instantQuery, threshold("A", `{"type":"gt","params":[1]}`))
+		second.UID = "second"
+		second.Data[0].RelativeTimeRange.To = models.Duration(time.Minute)
+
+		group, failures, _ := GrafanaRuleGroupToPrometheus("group", []models.AlertRule{first, second}, datasourceTypes)
+		require.Len(t, group.Rules, 1)
+		require.Nil(t, group.QueryOffset)
+		require.Len(t, failures, 1)
+		require.Equal(t, "second", failures[0].Rule.UID)
+		require.Equal(t, "query offset 1m0s is different from the query offset 0s of the other rules in the group", failures[0].Reason)
+	})
+
+	t.Run("settings that Prometheus does not support are reported", func(t *testing.T) {
+		r := rule(instantQuery, threshold("A", `{"type":"gt","params":[0]}`))
+		r.NoDataState = models.Alerting
+		r.ExecErrState = models.ErrorErrState
+		r.MissingSeriesEvalsToResolve = new(int64(3))
+		r.NotificationSettings = &models.NotificationSettings{Receiver: "team"}
+
+		group, failures, warnings := GrafanaRuleGroupToPrometheus("group", []models.AlertRule{r}, datasourceTypes)
+		require.Empty(t, failures)
+		require.Len(t, group.Rules, 1)
+		fields := make([]string, 0, len(warnings))
+		for _, w := range warnings {
+			require.Equal(t, "uid", w.Rule.UID)
+			require.NotEmpty(t, w.Reason)
+			fields = append(fields, w.Field)
+		}
+		require.Equal(t, []string{"no_data_state", "exec_err_state", "missing_series_evals_to_resolve", "notification_settings"}, fields)
+
+		r.NoDataState = models.OK
+		r.ExecErrState = models.KeepLastErrState
+		r.MissingSeriesEvalsToResolve = new(int64(1))
+		r.NotificationSettings = nil
+		_, _, warnings = GrafanaRuleGroupToPrometheus("group", []models.AlertRule{r}, datasourceTypes)
+		require.Empty(t, warnings)
+	})
+}
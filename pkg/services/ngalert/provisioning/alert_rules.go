@@ -518,6 +518,9 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 	if err := service.validateRuleMutation(ctx, &rule, manager); err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := service.validateRuleDependencies(ctx, rule.OrgID, []*models.AlertRule{&rule}, nil); err != nil {
+		return models.AlertRule{}, err
+	}
 	rule.Updated = time.Now()
 	if rule.NotificationSettings != nil {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
@@ -753,6 +756,11 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, user iden
 		}
 	}
 
+	changed, deleted := ruleDependencyChanges(delta)
+	if err := service.validateRuleDependencies(ctx, delta.GroupKey.OrgID, changed, deleted); err != nil {
+		return err
+	}
+
 	return service.persistDelta(ctx, user, delta, manager, versionMessage)
 }
 
@@ -787,6 +795,11 @@ func (service *AlertRuleService) DeleteRuleGroups(ctx context.Context, user iden
 	if err != nil {
 		return err
 	}
+	// The groups are validated together, rules can depend on the rules of other deleted groups.
+	_, deleted := ruleDependencyChanges(deltas...)
+	if err := service.validateRuleDependencies(ctx, user.GetOrgID(), nil, deleted); err != nil {
+		return err
+	}
 
 	// Perform all deletions in a transaction
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
@@ -1038,6 +1051,9 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 	if err := service.validateRuleMutation(ctx, &rule, manager); err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := service.validateRuleDependencies(ctx, rule.OrgID, []*models.AlertRule{&rule}, nil); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err := service.ruleStore.UpdateAlertRules(ctx, userUidOrFallback(user), []models.UpdateRule{
 			{
@@ -1097,11 +1113,40 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user ident
 	// The single delete is idempotent, and doesn't error when deleting a group that already doesn't exist.
 	// This is different from deleting groups. We delete the rules directly rather than persisting a delta here to keep the semantics the same.
 	// TODO: Either persist a delta here as a breaking change, or deprecate this endpoint in favor of the group endpoint.
+	if err := service.validateRuleDependencies(ctx, rule.OrgID, nil, []string{rule.UID}); err != nil {
+		return err
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		return service.deleteRules(ctx, user, rule)
 	})
 }
 
+// validateRuleDependencies checks that the changes do not leave alert rules of the organization depending on
+// the state of rules that do not exist, and do not make them depend on each other in a cycle.
+func (service *AlertRuleService) validateRuleDependencies(ctx context.Context, orgID int64, changed []*models.AlertRule, deleted []string) error {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to get alert rules: %w", err)
+	}
+	return models.ValidateRuleDependencyChanges(rules, changed, deleted)
+}
+
+// ruleDependencyChanges returns the created and updated rules, and the UIDs of the deleted rules of the deltas.
+func ruleDependencyChanges(deltas ...*store.GroupDelta) ([]*models.AlertRule, []string) {
+	var changed []*models.AlertRule
+	var deleted []string
+	for _, delta := range deltas {
+		changed = append(changed, delta.New...)
+		for _, upd := range delta.Update {
+			changed = append(changed, upd.New)
+		}
+		for _, rule := range delta.Delete {
+			deleted = append(deleted, rule.UID)
+		}
+	}
+	return changed, deleted
+}
+
 // checkLimitsTransactionCtx checks whether the current transaction (as identified by the ctx) breaches configured alert rule limits.
 func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, user identity.Requester) error {
 	// default to 0 if there is no user
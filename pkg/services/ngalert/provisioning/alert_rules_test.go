@@ -1686,6 +1686,29 @@ func TestDeleteAlertRule(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("should not delete rules that other rules depend on", func(t *testing.T) {
+		service, ruleStore, _, ac := initServiceWithData(t)
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+		ruleStore.PutRule(context.Background(), gen.With(gen.WithOrgID(orgID), withRuleStateDependency(rules[0].UID)).GenerateRef())
+
+		err := service.DeleteAlertRule(context.Background(), u, rules[0].UID, models.ProvenanceToManagerProperties(groupProvenance))
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.Empty(t, getDeleteQueries(ruleStore))
+	})
+}
+
+// withRuleStateDependency adds a rule state expression that reads the state of the rule to the queries of the rule.
+func withRuleStateDependency(uid string) models.AlertRuleMutator {
+	return func(rule *models.AlertRule) {
+		rule.Data = append(rule.Data, models.AlertQuery{
+			RefID:         "RULE_STATE",
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(fmt.Sprintf(`{"type": "rule_state", "ruleUid": %q}`, uid)),
+		})
+	}
 }
 
 func TestGetAlertRule(t *testing.T) {
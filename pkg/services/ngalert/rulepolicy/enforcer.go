@@ -0,0 +1,128 @@
+// Package rulepolicy enforces the alert rule policy of an organization when alert rules are
+// created or updated.
+package rulepolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// Store provides the rule policy of an organization.
+type Store interface {
+	GetRulePolicy(ctx context.Context, orgID int64) (*models.RulePolicy, error)
+}
+
+// DatasourceGetter provides the data sources that the rules query, to check their types.
+type DatasourceGetter interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error)
+}
+
+// RuleViolations are the requirements of a rule policy that an alert rule does not meet.
+type RuleViolations struct {
+	Rule       *models.AlertRule
+	Violations []string
+}
+
+// Enforcer checks alert rules against the rule policy of their organization.
+type Enforcer struct {
+	store       Store
+	datasources DatasourceGetter
+	log         log.Logger
+}
+
+func NewEnforcer(store Store, datasources DatasourceGetter, logger log.Logger) *Enforcer {
+	return &Enforcer{
+		store:       store,
+		datasources: datasources,
+		log:         logger,
+	}
+}
+
+// Enforce checks the rules against the rule policy of the organization. If the policy is in the
+// reject mode and any rule violates it, it returns an error that wraps models.ErrAlertRuleFailedValidation.
+// In the warn mode it logs and returns the violations without an error.
+//
+// If the policy cannot be read, its mode is not known and the rules could be rejected by it,
+// so the error is returned and the rules are not saved.
+func (e *Enforcer) Enforce(ctx context.Context, orgID int64, rules []*models.AlertRule) ([]RuleViolations, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	policy, err := e.store.GetRulePolicy(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, store.ErrNoRulePolicy) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the rule policy: %w", err)
+	}
+
+	violations := e.Check(ctx, orgID, policy, rules)
+	if len(violations) == 0 {
+		return nil, nil
+	}
+	if policy.Mode == models.RulePolicyModeReject {
+		return violations, fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, FormatViolations(violations))
+	}
+	for _, v := range violations {
+		e.log.Warn("Alert rule violates the rule policy", "org_id", orgID, "rule_uid", v.Rule.UID, "title", v.Rule.Title, "violations", strings.Join(v.Violations, "; "))
+	}
+	return violations, nil
+}
+
+// Check returns the rules that violate the policy with their violations, in the order of the rules.
+func (e *Enforcer) Check(ctx context.Context, orgID int64, policy *models.RulePolicy, rules []*models.AlertRule) []RuleViolations {
+	var datasourceTypes map[string]string
+	if len(policy.BannedDatasourceTypes) > 0 {
+		datasourceTypes = e.datasourceTypes(ctx, orgID, rules)
+	}
+
+	var result []RuleViolations
+	for _, rule := range rules {
+		if violations := policy.Check(rule, datasourceTypes); len(violations) > 0 {
+			result = append(result, RuleViolations{Rule: rule, Violations: violations})
+		}
+	}
+	return result
+}
+
+// datasourceTypes returns the types of the data sources that the rules query. Data sources that
+// cannot be found are left out, the validation of the rules reports them.
+func (e *Enforcer) datasourceTypes(ctx context.Context, orgID int64, rules []*models.AlertRule) map[string]string {
+	result := make(map[string]string)
+	for _, rule := range rules {
+		for _, uid := range rule.GetQueryDatasourceUIDs() {
+			if _, ok := result[uid]; ok {
+				continue
+			}
+			ds, err := e.datasources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: orgID})
+			if err != nil {
+				if !errors.Is(err, datasources.ErrDataSourceNotFound) {
+					e.log.Warn("Failed to get data source to check the rule policy", "org_id", orgID, "datasource_uid", uid, "error", err)
+				}
+				continue
+			}
+			result[uid] = ds.Type
+		}
+	}
+	return result
+}
+
+func (v RuleViolations) String() string {
+	return fmt.Sprintf("rule '%s' violates the rule policy: %s", v.Rule.Title, strings.Join(v.Violations, ", "))
+}
+
+// FormatViolations describes the violations of the rules in a single line.
+func FormatViolations(violations []RuleViolations) string {
+	parts := make([]string, 0, len(violations))
+	for _, v := range violations {
+		parts = append(parts, v.String())
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,96 @@
+package rulepolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type fakeStore struct {
+	policies map[int64]*models.RulePolicy
+	err      error
+}
+
+func (f fakeStore) GetRulePolicy(_ context.Context, orgID int64) (*models.RulePolicy, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	policy, ok := f.policies[orgID]
+	if !ok {
+		return nil, store.ErrNoRulePolicy
+	}
+	return policy, nil
+}
+
+type fakeDatasources map[string]string
+
+func (f fakeDatasources) GetDataSource(_ context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error) {
+	dsType, ok := f[query.UID]
+	if !ok {
+		return nil, datasources.ErrDataSourceNotFound
+	}
+	return &datasources.DataSource{UID: query.UID, Type: dsType}, nil
+}
+
+type rejectAll struct{}
+
+func (rejectAll) ValidateRuleMutation(context.Context, *models.AlertRule, utils.ManagerProperties) error {
+	return errors.New("rejected")
+}
+
+func TestEnforcer(t *testing.T) {
+	policy := func(mode models.RulePolicyMode) *models.RulePolicy {
+		return &models.RulePolicy{Mode: mode, RequiredLabels: []string{"team"}, BannedDatasourceTypes: []string{"testdata"}}
+	}
+	compliant := &models.AlertRule{Title: "compliant", OrgID: 1, Labels: map[string]string{"team": "platform"}, Data: []models.AlertQuery{{RefID: "A", DatasourceUID: "prometheus"}}}
+	violating := &models.AlertRule{Title: "violating", OrgID: 1, Data: []models.AlertQuery{{RefID: "A", DatasourceUID: "random"}}}
+	ds := fakeDatasources{"prometheus": "prometheus", "random": "testdata"}
+
+	t.Run("rejects rules that violate a policy in reject mode", func(t *testing.T) {
+		e := NewEnforcer(fakeStore{policies: map[int64]*models.RulePolicy{1: policy(models.RulePolicyModeReject)}}, ds, log.NewNopLogger())
+		violations, err := e.Enforce(context.Background(), 1, []*models.AlertRule{compliant, violating})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "rule 'violating' violates the rule policy: missing required label team, queries data source random of banned type testdata")
+		require.Len(t, violations, 1)
+		require.Same(t, violating, violations[0].Rule)
+	})
+
+	t.Run("returns the violations of a policy in warn mode", func(t *testing.T) {
+		e := NewEnforcer(fakeStore{policies: map[int64]*models.RulePolicy{1: policy(models.RulePolicyModeWarn)}}, ds, log.NewNopLogger())
+		violations, err := e.Enforce(context.Background(), 1, []*models.AlertRule{compliant, violating})
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		require.Equal(t, []string{"missing required label team", "queries data source random of banned type testdata"}, violations[0].Violations)
+	})
+
+	t.Run("accepts rules of an organization without a policy", func(t *testing.T) {
+		e := NewEnforcer(fakeStore{policies: map[int64]*models.RulePolicy{1: policy(models.RulePolicyModeReject)}}, ds, log.NewNopLogger())
+		violations, err := e.Enforce(context.Background(), 2, []*models.AlertRule{violating})
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+
+	t.Run("returns the error if the policy cannot be read", func(t *testing.T) {
+		storeErr := errors.New("database is down")
+		e := NewEnforcer(fakeStore{err: storeErr}, ds, log.NewNopLogger())
+		violations, err := e.Enforce(context.Background(), 1, []*models.AlertRule{violating})
+		require.ErrorIs(t, err, storeErr)
+		require.Empty(t, violations)
+		require.ErrorIs(t, NewValidator(e, nil).ValidateRuleMutation(context.Background(), compliant, utils.ManagerProperties{}), storeErr)
+	})
+
+	t.Run("validator enforces the policy after the wrapped validator", func(t *testing.T) {
+		e := NewEnforcer(fakeStore{policies: map[int64]*models.RulePolicy{1: policy(models.RulePolicyModeReject)}}, ds, log.NewNopLogger())
+		require.NoError(t, NewValidator(e, nil).ValidateRuleMutation(context.Background(), compliant, utils.ManagerProperties{}))
+		require.ErrorIs(t, NewValidator(e, nil).ValidateRuleMutation(context.Background(), violating, utils.ManagerProperties{}), models.ErrAlertRuleFailedValidation)
+		require.EqualError(t, NewValidator(e, rejectAll{}).ValidateRuleMutation(context.Background(), compliant, utils.ManagerProperties{}), "rejected")
+	})
+}
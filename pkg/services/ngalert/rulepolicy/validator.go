@@ -0,0 +1,34 @@
+package rulepolicy
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// Validator is a provisioning.RuleMutationValidator that enforces the rule policy of the
+// organization after the validator it wraps accepts the mutation.
+type Validator struct {
+	enforcer *Enforcer
+	next     provisioning.RuleMutationValidator
+}
+
+func NewValidator(enforcer *Enforcer, next provisioning.RuleMutationValidator) *Validator {
+	if next == nil {
+		next = provisioning.NoopRuleMutationValidator{}
+	}
+	return &Validator{
+		enforcer: enforcer,
+		next:     next,
+	}
+}
+
+func (v *Validator) ValidateRuleMutation(ctx context.Context, rule *models.AlertRule, manager utils.ManagerProperties) error {
+	if err := v.next.ValidateRuleMutation(ctx, rule, manager); err != nil {
+		return err
+	}
+	_, err := v.enforcer.Enforce(ctx, rule.OrgID, []*models.AlertRule{rule})
+	return err
+}
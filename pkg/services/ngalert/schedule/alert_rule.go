@@ -389,6 +389,11 @@ func (a *alertRule) Run() error {
 				a.expireAndSend(grafanaCtx, stateTransitions)
 				return nil
 			}
+			if errors.Is(reason, errRuleHandedOver) {
+				// Save the state for the instance that evaluates the rule from now on.
+				a.stateManager.HandOverStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key)
+				return nil
+			}
 			// Otherwise, just clean up the cache.
 			a.stateManager.ForgetStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key)
 			return nil
@@ -503,6 +508,9 @@ func (a *alertRule) send(ctx context.Context, logger log.Logger, states state.St
 	alerts := definitions.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(states))}
 	for _, alertState := range states {
 		alerts.PostableAlerts = append(alerts.PostableAlerts, *state.StateToPostableAlert(alertState, a.appURL))
+		if stopped := state.PreviousSeverityToStoppedAlert(alertState, a.appURL); stopped != nil {
+			alerts.PostableAlerts = append(alerts.PostableAlerts, *stopped)
+		}
 	}
 
 	if len(alerts.PostableAlerts) > 0 {
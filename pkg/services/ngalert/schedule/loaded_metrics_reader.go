@@ -5,12 +5,16 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
-var _ eval.AlertingResultsReader = AlertingResultsFromRuleState{}
+var (
+	_ eval.AlertingResultsReader = AlertingResultsFromRuleState{}
+	_ eval.SeverityResultsReader = AlertingResultsFromRuleState{}
+)
 
 func (a *alertRule) newLoadedMetricsReader(rule *ngmodels.AlertRule) eval.AlertingResultsReader {
 	return &AlertingResultsFromRuleState{
@@ -44,3 +48,20 @@ func (n AlertingResultsFromRuleState) Read(ctx context.Context) map[data.Fingerp
 	}
 	return active
 }
+
+// ReadSeverities returns the severity levels of Alerting and Pending states that have empty StateReason,
+// keyed by their results fingerprints.
+func (n AlertingResultsFromRuleState) ReadSeverities(ctx context.Context) expr.LoadedSeverities {
+	states := n.Manager.GetStatesForRuleUID(ctx, n.Rule.OrgID, n.Rule.UID)
+
+	active := expr.LoadedSeverities{}
+	for _, st := range states {
+		if st.StateReason != "" || st.Severity == "" {
+			continue
+		}
+		if st.State == eval.Alerting || st.State == eval.Pending {
+			active[st.ResultFingerprint] = expr.LoadedSeverity{Severity: st.Severity, RecoveryStreak: st.RecoveryStreak}
+		}
+	}
+	return active
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -54,6 +55,32 @@ func TestLoadedResultsFromRuleState(t *testing.T) {
 	})
 }
 
+func TestSeveritiesFromRuleState(t *testing.T) {
+	rule := ngmodels.RuleGen.GenerateRef()
+	p := &FakeRuleStateProvider{
+		map[ngmodels.AlertRuleKey][]*state.State{
+			rule.GetKey(): {
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(1), Severity: "critical", RecoveryStreak: 2},
+				{State: eval.Pending, ResultFingerprint: data.Fingerprint(2), Severity: "warning"},
+				{State: eval.Normal, ResultFingerprint: data.Fingerprint(3)},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(4), Severity: "critical", StateReason: uuid.NewString()},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(5)},
+			},
+		},
+	}
+
+	reader := AlertingResultsFromRuleState{
+		Manager: p,
+		Rule:    rule,
+	}
+
+	loaded := reader.ReadSeverities(context.Background())
+	require.Equal(t, expr.LoadedSeverities{
+		1: {Severity: "critical", RecoveryStreak: 2},
+		2: {Severity: "warning"},
+	}, loaded)
+}
+
 type FakeRuleStateProvider struct {
 	states map[ngmodels.AlertRuleKey][]*state.State
 }
@@ -16,8 +16,9 @@ import (
 )
 
 var (
-	errRuleDeleted   = errors.New("rule deleted")
-	errRuleRestarted = errors.New("rule restarted")
+	errRuleDeleted    = errors.New("rule deleted")
+	errRuleRestarted  = errors.New("rule restarted")
+	errRuleHandedOver = errors.New("rule handed over to another instance")
 )
 
 type ruleFactory interface {
@@ -3,12 +3,15 @@ package schedule
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/url"
 	"time"
 
 	"github.com/benbjohnson/clock"
+	prometheusModel "github.com/prometheus/common/model"
 	"golang.org/x/sync/errgroup"
 
+	alertingModels "github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -59,6 +62,18 @@ type AlertRuleStopReasonProvider interface {
 	FindReason(ctx context.Context, logger log.Logger, key ngmodels.AlertRuleKeyWithGroup) (error, error)
 }
 
+// MaintenanceWindows tells whether an active maintenance window pauses the evaluation of alert rules.
+type MaintenanceWindows interface {
+	// PausesRule returns true if the evaluation of the rules of the organization with the given labels is paused.
+	PausesRule(orgID int64, lbls map[string]string) bool
+}
+
+// RuleOwnership tells whether this instance evaluates the rule when the evaluation of rules is sharded between
+// the instances of a cluster.
+type RuleOwnership interface {
+	Owns(key ngmodels.AlertRuleKey) bool
+}
+
 type schedule struct {
 	// base tick rate (fastest possible configured check)
 	baseInterval time.Duration
@@ -82,6 +97,13 @@ type schedule struct {
 
 	ruleStopReasonProvider AlertRuleStopReasonProvider
 
+	maintenanceWindows MaintenanceWindows
+
+	// ruleOwnership is nil unless the evaluation of rules is sharded. notOwned contains the rules that were owned by
+	// other instances in the last tick, so the state of a rule is taken over when it moves to this instance.
+	ruleOwnership RuleOwnership
+	notOwned      map[ngmodels.AlertRuleKey]struct{}
+
 	log log.Logger
 
 	evaluatorFactory eval.EvaluatorFactory
@@ -140,6 +162,10 @@ type SchedulerCfg struct {
 	RecordingWriter        RecordingWriter
 	RuleStopReasonProvider AlertRuleStopReasonProvider
 	FeatureToggles         featuremgmt.FeatureToggles
+	MaintenanceWindows     MaintenanceWindows
+	// RuleOwnership shards the evaluation of rules between the instances of a cluster. If nil, every rule is
+	// evaluated by this instance.
+	RuleOwnership RuleOwnership
 }
 
 // NewScheduler returns a new scheduler.
@@ -175,6 +201,9 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		recordingWriter:        cfg.RecordingWriter,
 		ruleStopReasonProvider: cfg.RuleStopReasonProvider,
 		featureToggles:         cfg.FeatureToggles,
+		maintenanceWindows:     cfg.MaintenanceWindows,
+		ruleOwnership:          cfg.RuleOwnership,
+		notOwned:               make(map[ngmodels.AlertRuleKey]struct{}),
 	}
 
 	return &sch
@@ -310,6 +339,8 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
 	missingFolder := make(map[string][]string)
+	notOwned := make(map[ngmodels.AlertRuleKey]struct{})
+	handedOver := make([]ngmodels.AlertRuleKey, 0)
 
 	ruleFactory := newRuleFactory(
 		sch.appURL,
@@ -332,6 +363,20 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		key := item.GetKey()
 		logger := sch.log.FromContext(ctx).New(key.LogContext()...)
 
+		if sch.ruleOwnership != nil && !sch.ruleOwnership.Owns(key) {
+			notOwned[key] = struct{}{}
+			if _, ok := registeredDefinitions[key]; ok {
+				logger.Info("Rule is handed over to another instance")
+				handedOver = append(handedOver, key)
+				delete(registeredDefinitions, key)
+			} else if _, ok := sch.notOwned[key]; !ok {
+				// The rule was never evaluated by this instance but its state could be loaded on startup.
+				sch.stateManager.ForgetStateByRuleUID(ctx, item.GetKeyWithGroup())
+			}
+			continue
+		}
+		_, takeOver := sch.notOwned[key]
+
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
 			title, ok := folderTitles[item.GetFolderKey()]
@@ -362,7 +407,15 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		if newRoutine && !invalidInterval {
+			if takeOver {
+				logger.Info("Rule is taken over from another instance")
+			}
 			dispatcherGroup.Go(func() error {
+				if takeOver {
+					if err := sch.stateManager.TakeOverStateByRuleUID(ngmodels.WithRuleKey(ctx, key), item); err != nil {
+						logger.Error("Failed to take over rule state. Continue with empty state", "error", err)
+					}
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -378,6 +431,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
 		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
 
+		// The evaluation is skipped without changing the rule, because a change of the rule would change its
+		// fingerprint and reset its state at the start and the end of the maintenance window.
+		if isReadyToRun && !item.IsPaused && sch.pausedByMaintenanceWindow(item, folderTitle) {
+			logger.Debug("Rule evaluation is skipped because of a maintenance window", "tick", tick)
+			isReadyToRun = false
+		}
+
 		if isReadyToRun {
 			logger.Debug("Rule is ready to run on the current tick", "tick", tick, "frequency", itemFrequency, "offset", offset)
 			readyToRun = append(readyToRun, readyToRunItem{ruleRoutine: ruleRoutine, Evaluation: Evaluation{
@@ -405,6 +465,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		delete(registeredDefinitions, key)
 	}
 
+	if sch.ruleOwnership != nil {
+		sch.notOwned = notOwned
+		sch.metrics.OwnedAlertRules.Set(float64(len(alertRules) - len(notOwned)))
+	}
+
 	if len(missingFolder) > 0 { // if this happens then there can be problems with fetching folders from the database.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
@@ -423,6 +488,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
+	// Stop routines of the rules that other instances evaluate from now on, without deleting them from the scheduler.
+	for _, key := range handedOver {
+		if ruleRoutine, ok := sch.registry.del(key); ok {
+			ruleRoutine.Stop(errRuleHandedOver)
+		}
+	}
+
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
@@ -458,3 +530,20 @@ func (sch *schedule) runSequences(sequences []sequence, step int64) {
 		time.AfterFunc(time.Duration(int64(i)*step), sch.runJobFn(readyToRunItem(sequences[i])))
 	}
 }
+
+// pausedByMaintenanceWindow returns true if an active maintenance window pauses the evaluation of the rule.
+// The window matchers are matched against the labels of the rule and the labels that identify it in its alerts.
+func (sch *schedule) pausedByMaintenanceWindow(rule *ngmodels.AlertRule, folderTitle string) bool {
+	if sch.maintenanceWindows == nil {
+		return false
+	}
+	lbls := make(map[string]string, len(rule.Labels)+4)
+	maps.Copy(lbls, rule.Labels)
+	lbls[prometheusModel.AlertNameLabel] = rule.Title
+	lbls[alertingModels.RuleUIDLabel] = rule.UID
+	lbls[alertingModels.NamespaceUIDLabel] = rule.NamespaceUID
+	if folderTitle != "" {
+		lbls[ngmodels.FolderTitleLabel] = folderTitle
+	}
+	return sch.maintenanceWindows.PausesRule(rule.OrgID, lbls)
+}
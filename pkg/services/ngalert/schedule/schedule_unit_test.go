@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/url"
+	"slices"
 	"sort"
 	"sync"
 	"testing"
@@ -78,6 +79,8 @@ func TestProcessTicks(t *testing.T) {
 			nil,
 			tracing.InitializeTracerForTest(),
 			dsquerierclient.NewNullQSDatasourceClientBuilder(),
+			nil, // library
+			nil, // ruleStates
 		),
 	)
 	rrSet := setting.RecordingRuleSettings{
@@ -1195,6 +1198,129 @@ func TestSchedule_deleteAlertRule(t *testing.T) {
 	})
 }
 
+type fakeRuleOwnership struct {
+	mtx   sync.Mutex
+	owned map[models.AlertRuleKey]struct{}
+}
+
+func (f *fakeRuleOwnership) Owns(key models.AlertRuleKey) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	_, ok := f.owned[key]
+	return ok
+}
+
+func (f *fakeRuleOwnership) set(keys ...models.AlertRuleKey) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.owned = make(map[models.AlertRuleKey]struct{}, len(keys))
+	for _, key := range keys {
+		f.owned[key] = struct{}{}
+	}
+}
+
+func TestProcessTicks_RuleOwnership(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	reg := prometheus.NewPedanticRegistry()
+	sch := setupScheduler(t, ruleStore, instanceStore, reg, nil, nil, nil)
+	ownership := &fakeRuleOwnership{}
+	sch.ruleOwnership = ownership
+
+	gen := models.RuleGen
+	rule1 := gen.With(gen.WithOrgID(1), gen.WithInterval(time.Second)).GenerateRef()
+	rule2 := gen.With(gen.WithOrgID(1), gen.WithInterval(time.Second)).GenerateRef()
+	ruleStore.PutRule(ctx, rule1, rule2)
+
+	ownership.set(rule1.GetKey())
+	scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, time.Time{}.Add(time.Second))
+	require.Len(t, scheduled, 1)
+	assertScheduledContains(t, scheduled, rule1)
+	require.Empty(t, stopped)
+	require.True(t, sch.registry.exists(rule1.GetKey()))
+	require.False(t, sch.registry.exists(rule2.GetKey()))
+	require.Equal(t, 1.0, testutil.ToFloat64(sch.metrics.OwnedAlertRules))
+
+	routine1, _ := sch.registry.get(rule1.GetKey())
+
+	t.Run("hands over rules owned by another instance without deleting them", func(t *testing.T) {
+		ownership.set(rule2.GetKey())
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, time.Time{}.Add(2*time.Second))
+		require.Len(t, scheduled, 1)
+		assertScheduledContains(t, scheduled, rule2)
+		require.Empty(t, stopped)
+		require.False(t, sch.registry.exists(rule1.GetKey()))
+		require.NotNil(t, sch.schedulableAlertRules.get(rule1.GetKey()))
+		require.ErrorIs(t, routine1.(*alertRule).ctx.Err(), errRuleHandedOver)
+
+		require.Eventually(t, func() bool {
+			for _, op := range instanceStore.RecordedOps() {
+				if o, ok := op.(state.FakeInstanceStoreOp); ok && o.Name == "SaveAlertInstancesForRule" && o.Args[1] == rule1.GetKeyWithGroup() {
+					return true
+				}
+			}
+			return false
+		}, 5*time.Second, 10*time.Millisecond, "the state of the handed over rule should be saved")
+		require.Eventually(t, func() bool {
+			return slices.Contains(instanceStore.RecordedOps(), any(models.ListAlertInstancesQuery{RuleOrgID: rule2.OrgID, RuleUID: rule2.UID}))
+		}, 5*time.Second, 10*time.Millisecond, "the state of the rule owned by another instance before should be loaded")
+	})
+
+	t.Run("takes over the state of rules moved to this instance", func(t *testing.T) {
+		ownership.set(rule1.GetKey(), rule2.GetKey())
+		scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, time.Time{}.Add(3*time.Second))
+		require.Len(t, scheduled, 2)
+		require.True(t, sch.registry.exists(rule1.GetKey()))
+		require.Equal(t, 2.0, testutil.ToFloat64(sch.metrics.OwnedAlertRules))
+
+		require.Eventually(t, func() bool {
+			return slices.Contains(instanceStore.RecordedOps(), any(models.ListAlertInstancesQuery{RuleOrgID: rule1.OrgID, RuleUID: rule1.UID}))
+		}, 5*time.Second, 10*time.Millisecond, "the state of the taken over rule should be loaded")
+	})
+}
+
+type fakeMaintenanceWindows struct {
+	mtx    sync.Mutex
+	paused bool
+}
+
+func (f *fakeMaintenanceWindows) PausesRule(int64, map[string]string) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.paused
+}
+
+func (f *fakeMaintenanceWindows) set(paused bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.paused = paused
+}
+
+func TestProcessTicks_MaintenanceWindows(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil, nil)
+	windows := &fakeMaintenanceWindows{paused: true}
+	sch.maintenanceWindows = windows
+
+	rule := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithInterval(time.Second), models.RuleGen.WithIsPaused(false)).GenerateRef()
+	ruleStore.PutRule(ctx, rule)
+
+	scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, time.Time{}.Add(time.Second))
+	require.Empty(t, scheduled, "the rule should not be evaluated during the maintenance window")
+	require.Empty(t, stopped)
+	require.True(t, sch.registry.exists(rule.GetKey()))
+
+	windows.set(false)
+	scheduled, _, _ = sch.processTick(ctx, dispatcherGroup, time.Time{}.Add(2*time.Second))
+	require.Len(t, scheduled, 1)
+	require.False(t, scheduled[0].rule.IsPaused)
+	require.Equal(t, rule.GetKey(), scheduled[0].rule.GetKey())
+}
+
 type schedulerOpts struct {
 	clock           clock.Clock
 	gateUntilWarm   bool
@@ -1255,6 +1381,8 @@ func setupScheduler(
 				nil,
 				tracing.InitializeTracerForTest(),
 				dsquerierclient.NewNullQSDatasourceClientBuilder(),
+				nil, // library
+				nil, // ruleStates
 			),
 		)
 	}
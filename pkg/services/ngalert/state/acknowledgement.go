@@ -0,0 +1,275 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+// AcknowledgementSyncInterval is how often the acknowledgements are reloaded from the store, so that
+// acknowledgements made through other Grafana instances are applied.
+var AcknowledgementSyncInterval = time.Minute
+
+// acknowledgements holds the acknowledgements of the alert instances by rule and labels hash.
+type acknowledgements struct {
+	mtx    sync.RWMutex
+	byRule map[ngModels.AlertRuleKey]map[string]ngModels.AlertAcknowledgement
+}
+
+func newAcknowledgements() *acknowledgements {
+	return &acknowledgements{
+		byRule: make(map[ngModels.AlertRuleKey]map[string]ngModels.AlertAcknowledgement),
+	}
+}
+
+func (a *acknowledgements) hasRule(key ngModels.AlertRuleKey) bool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return len(a.byRule[key]) > 0
+}
+
+func (a *acknowledgements) get(key ngModels.AlertInstanceKey) (ngModels.AlertAcknowledgement, bool) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	ack, ok := a.byRule[ruleKeyOf(key)][key.LabelsHash]
+	return ack, ok
+}
+
+func (a *acknowledgements) set(ack ngModels.AlertAcknowledgement) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	ruleKey := ruleKeyOf(ack.AlertInstanceKey)
+	if _, ok := a.byRule[ruleKey]; !ok {
+		a.byRule[ruleKey] = make(map[string]ngModels.AlertAcknowledgement)
+	}
+	a.byRule[ruleKey][ack.LabelsHash] = ack
+}
+
+func (a *acknowledgements) delete(key ngModels.AlertInstanceKey) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	ruleKey := ruleKeyOf(key)
+	delete(a.byRule[ruleKey], key.LabelsHash)
+	if len(a.byRule[ruleKey]) == 0 {
+		delete(a.byRule, ruleKey)
+	}
+}
+
+func (a *acknowledgements) replace(acks []ngModels.AlertAcknowledgement) {
+	byRule := make(map[ngModels.AlertRuleKey]map[string]ngModels.AlertAcknowledgement)
+	for _, ack := range acks {
+		ruleKey := ruleKeyOf(ack.AlertInstanceKey)
+		if _, ok := byRule[ruleKey]; !ok {
+			byRule[ruleKey] = make(map[string]ngModels.AlertAcknowledgement)
+		}
+		byRule[ruleKey][ack.LabelsHash] = ack
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.byRule = byRule
+}
+
+func ruleKeyOf(key ngModels.AlertInstanceKey) ngModels.AlertRuleKey {
+	return ngModels.AlertRuleKey{OrgID: key.RuleOrgID, UID: key.RuleUID}
+}
+
+// canBeAcknowledged returns true if alerts are sent to the Alertmanager for the state.
+func canBeAcknowledged(s eval.State) bool {
+	return s == eval.Alerting || s == eval.Recovering || s == eval.NoData || s == eval.Error
+}
+
+// AcknowledgementAnnotations returns the annotations that describe the acknowledgement.
+func AcknowledgementAnnotations(ack *ngModels.AlertAcknowledgement) map[string]string {
+	annotations := map[string]string{
+		ngModels.AcknowledgedByAnnotation: ack.AcknowledgedBy,
+		ngModels.AssigneeAnnotation:       ack.Assignee,
+	}
+	if ack.Note != "" {
+		annotations[ngModels.AcknowledgementNoteAnnotation] = ack.Note
+	}
+	if ack.ExpiresAt != nil {
+		annotations[ngModels.AcknowledgedUntilAnnotation] = ack.ExpiresAt.Format(time.RFC3339)
+	}
+	return annotations
+}
+
+// GetAnnotations returns the annotations of the state, with the annotations of the acknowledgement if the alert
+// instance is acknowledged.
+func (a *State) GetAnnotations() map[string]string {
+	if !a.Acknowledgement.IsActive(a.LastEvaluationTime) {
+		return a.Annotations
+	}
+	annotations := make(map[string]string, len(a.Annotations)+4)
+	maps.Copy(annotations, a.Annotations)
+	maps.Copy(annotations, AcknowledgementAnnotations(a.Acknowledgement))
+	return annotations
+}
+
+// acknowledgementSent returns true if the alert instance is acknowledged and was sent to the Alertmanager since
+// it was acknowledged. The alert was then sent with the annotations of the acknowledgement, and it is not re-sent
+// until the acknowledgement ends, so that no repeat notifications are sent for it.
+func (a *State) acknowledgementSent(now time.Time) bool {
+	if !a.Acknowledgement.IsActive(now) || a.LastSentAt == nil {
+		return false
+	}
+	// The creation time of the acknowledgement is truncated to the second, an alert sent within that second may have
+	// been sent before the acknowledgement.
+	return !a.LastSentAt.Before(a.Acknowledgement.Created.Add(time.Second))
+}
+
+// Acknowledge records that a user is handling the firing alert instance of the rule. The alert instance is sent to
+// the Alertmanager once more with the annotations of the acknowledgement, and then it is not re-sent until the
+// acknowledgement expires or the alert instance stops firing. The acknowledgement is recorded in the state history.
+func (st *Manager) Acknowledge(ctx context.Context, rule *ngModels.AlertRule, s *State, ack ngModels.AlertAcknowledgement) (*ngModels.AlertAcknowledgement, error) {
+	if st.acknowledgementStore == nil {
+		return nil, errors.New("alert acknowledgements are not supported")
+	}
+	if !canBeAcknowledged(s.State) {
+		return nil, fmt.Errorf("%w: only firing alerts can be acknowledged, the alert is %s", ngModels.ErrAlertAcknowledgementInvalid, s.State)
+	}
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return nil, err
+	}
+	ack.AlertInstanceKey = key
+	if ack.Assignee == "" {
+		ack.Assignee = ack.AcknowledgedBy
+	}
+	// The store keeps the times with a precision of a second.
+	ack.Created = st.clock.Now().UTC().Truncate(time.Second)
+	if ack.ExpiresAt != nil {
+		expiresAt := ack.ExpiresAt.UTC().Truncate(time.Second)
+		ack.ExpiresAt = &expiresAt
+	}
+	if err := ack.Validate(); err != nil {
+		return nil, err
+	}
+	if err := st.acknowledgementStore.SaveAlertAcknowledgement(ctx, ack); err != nil {
+		return nil, err
+	}
+	st.acknowledgements.set(ack)
+	st.setAcknowledgement(s, &ack)
+	st.recordAcknowledgement(ctx, rule, s, fmt.Sprintf("%s by %s", ngModels.StateReasonAcknowledged, ack.AcknowledgedBy))
+	return &ack, nil
+}
+
+// Unacknowledge removes the acknowledgement of the alert instance of the rule, so that notifications are sent for
+// it again. It returns ErrAlertAcknowledgementNotFound if the alert instance is not acknowledged.
+func (st *Manager) Unacknowledge(ctx context.Context, rule *ngModels.AlertRule, s *State, user string) error {
+	if st.acknowledgementStore == nil {
+		return errors.New("alert acknowledgements are not supported")
+	}
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return err
+	}
+	if err := st.acknowledgementStore.DeleteAlertAcknowledgement(ctx, key); err != nil {
+		return err
+	}
+	st.acknowledgements.delete(key)
+	st.setAcknowledgement(s, nil)
+	st.recordAcknowledgement(ctx, rule, s, fmt.Sprintf("%s by %s", ngModels.StateReasonUnacknowledged, user))
+	return nil
+}
+
+// setAcknowledgement replaces the cached state with a copy that has the given acknowledgement.
+func (st *Manager) setAcknowledgement(s *State, ack *ngModels.AlertAcknowledgement) {
+	current := st.cache.get(s.OrgID, s.AlertRuleUID, s.CacheID)
+	if current == nil {
+		// The state is evaluated by another Grafana instance.
+		return
+	}
+	updated := current.Copy()
+	updated.Acknowledgement = ack
+	st.cache.set(updated)
+}
+
+// recordAcknowledgement writes an entry to the state history that does not change the state but has the given reason.
+func (st *Manager) recordAcknowledgement(ctx context.Context, rule *ngModels.AlertRule, s *State, reason string) {
+	if st.historian == nil {
+		return
+	}
+	entry := s.Copy()
+	entry.StateReason = reason
+	if s.StateReason != "" {
+		entry.StateReason = ngModels.ConcatReasons(s.StateReason, reason)
+	}
+	entry.LastEvaluationTime = st.clock.Now()
+	transition := StateTransition{
+		State:               entry,
+		PreviousState:       s.State,
+		PreviousStateReason: s.StateReason,
+	}
+	logger := st.log.FromContext(ctx)
+	errCh := st.historian.Record(ctx, history_model.NewRuleMeta(rule, logger), []StateTransition{transition})
+	go func() {
+		err := <-errCh
+		if err != nil {
+			logger.Error("Error updating historian with acknowledgement", append(rule.GetKey().LogContext(), "reason", reason, "error", err)...)
+		}
+	}()
+}
+
+// applyAcknowledgements sets the acknowledgements of the rule on the firing states. The acknowledgements of
+// the states that stopped firing, and the expired ones, are deleted.
+func (st *Manager) applyAcknowledgements(ctx context.Context, logger log.Logger, ruleKey ngModels.AlertRuleKey, transitions StateTransitions, now time.Time) {
+	// Avoid hashing the labels of every state when no alert instance of the rule is acknowledged.
+	if !st.acknowledgements.hasRule(ruleKey) {
+		return
+	}
+	for _, t := range transitions {
+		key, err := t.GetAlertInstanceKey()
+		if err != nil {
+			continue
+		}
+		ack, ok := st.acknowledgements.get(key)
+		if !ok {
+			continue
+		}
+		if canBeAcknowledged(t.State.State) && ack.IsActive(now) {
+			t.Acknowledgement = &ack
+			continue
+		}
+		st.acknowledgements.delete(key)
+		if st.acknowledgementStore == nil {
+			continue
+		}
+		if err := st.acknowledgementStore.DeleteAlertAcknowledgement(ctx, key); err != nil && !errors.Is(err, ngModels.ErrAlertAcknowledgementNotFound) {
+			logger.Warn("Failed to delete ended alert acknowledgement", "labelsHash", key.LabelsHash, "error", err)
+		}
+	}
+}
+
+// loadAcknowledgements replaces the acknowledgements with the ones in the store.
+func (st *Manager) loadAcknowledgements(ctx context.Context) {
+	if st.acknowledgementStore == nil {
+		return
+	}
+	acks, err := st.acknowledgementStore.ListAlertAcknowledgements(ctx, 0)
+	if err != nil {
+		st.log.FromContext(ctx).Error("Unable to load alert acknowledgements", "error", err)
+		return
+	}
+	st.acknowledgements.replace(acks)
+}
+
+func (st *Manager) syncAcknowledgements(ctx context.Context) {
+	ticker := st.clock.Ticker(AcknowledgementSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			st.loadAcknowledgements(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeAcknowledgementStore struct {
+	acks map[ngmodels.AlertInstanceKey]ngmodels.AlertAcknowledgement
+}
+
+func (f *fakeAcknowledgementStore) ListAlertAcknowledgements(_ context.Context, _ int64) ([]ngmodels.AlertAcknowledgement, error) {
+	result := make([]ngmodels.AlertAcknowledgement, 0, len(f.acks))
+	for _, ack := range f.acks {
+		result = append(result, ack)
+	}
+	return result, nil
+}
+
+func (f *fakeAcknowledgementStore) SaveAlertAcknowledgement(_ context.Context, a ngmodels.AlertAcknowledgement) error {
+	f.acks[a.AlertInstanceKey] = a
+	return nil
+}
+
+func (f *fakeAcknowledgementStore) DeleteAlertAcknowledgement(_ context.Context, key ngmodels.AlertInstanceKey) error {
+	if _, ok := f.acks[key]; !ok {
+		return ngmodels.ErrAlertAcknowledgementNotFound
+	}
+	delete(f.acks, key)
+	return nil
+}
+
+func TestAcknowledgements(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	rule := &ngmodels.AlertRule{OrgID: 1, UID: "rule", Title: "test"}
+
+	setup := func(t *testing.T, s eval.State) (*Manager, *fakeAcknowledgementStore, *FakeHistorian, *State) {
+		clk := clock.NewMock()
+		clk.Set(now)
+		store := &fakeAcknowledgementStore{acks: map[ngmodels.AlertInstanceKey]ngmodels.AlertAcknowledgement{}}
+		historian := &FakeHistorian{}
+		mgr := NewManager(ManagerCfg{
+			Clock:                clk,
+			Log:                  log.NewNopLogger(),
+			Historian:            historian,
+			AcknowledgementStore: store,
+		}, NewNoopPersister())
+
+		lbls := data.Labels{"alertname": "test", "instance": "a"}
+		state := &State{
+			OrgID:              rule.OrgID,
+			AlertRuleUID:       rule.UID,
+			CacheID:            lbls.Fingerprint(),
+			State:              s,
+			Labels:             lbls,
+			Annotations:        map[string]string{"summary": "test"},
+			StartsAt:           now,
+			LastEvaluationTime: now,
+		}
+		mgr.cache.set(state)
+		return mgr, store, historian, state
+	}
+
+	t.Run("should acknowledge a firing alert instance", func(t *testing.T) {
+		mgr, store, historian, s := setup(t, eval.Alerting)
+		expiresAt := now.Add(time.Hour)
+
+		ack, err := mgr.Acknowledge(ctx, rule, s, ngmodels.AlertAcknowledgement{
+			AcknowledgedBy: "admin",
+			Note:           "Looking into it",
+			ExpiresAt:      &expiresAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "admin", ack.Assignee)
+		require.Equal(t, now, ack.Created)
+
+		key, err := s.GetAlertInstanceKey()
+		require.NoError(t, err)
+		require.Equal(t, *ack, store.acks[key])
+
+		cached := mgr.cache.get(s.OrgID, s.AlertRuleUID, s.CacheID)
+		require.Equal(t, ack, cached.Acknowledgement)
+		require.Nil(t, s.Acknowledgement, "the state must not be changed in place")
+		require.Equal(t, map[string]string{
+			"summary":                              "test",
+			ngmodels.AcknowledgedByAnnotation:      "admin",
+			ngmodels.AssigneeAnnotation:            "admin",
+			ngmodels.AcknowledgementNoteAnnotation: "Looking into it",
+			ngmodels.AcknowledgedUntilAnnotation:   "2024-05-01T11:00:00Z",
+		}, cached.GetAnnotations())
+
+		alert := StateToPostableAlert(StateTransition{State: cached, PreviousState: eval.Alerting}, nil)
+		require.Equal(t, models.LabelSet(s.Labels), alert.Labels, "the labels must not change so that the alert keeps its fingerprint")
+		require.Equal(t, "admin", alert.Annotations[ngmodels.AcknowledgedByAnnotation])
+
+		require.Len(t, historian.StateTransitions, 1)
+		require.Equal(t, eval.Alerting, historian.StateTransitions[0].State.State)
+		require.Equal(t, eval.Alerting, historian.StateTransitions[0].PreviousState)
+		require.Equal(t, "Acknowledged by admin", historian.StateTransitions[0].StateReason)
+	})
+
+	t.Run("should not acknowledge an alert instance that is not firing", func(t *testing.T) {
+		mgr, store, _, s := setup(t, eval.Pending)
+		_, err := mgr.Acknowledge(ctx, rule, s, ngmodels.AlertAcknowledgement{AcknowledgedBy: "admin"})
+		require.ErrorIs(t, err, ngmodels.ErrAlertAcknowledgementInvalid)
+		require.Empty(t, store.acks)
+	})
+
+	t.Run("should send an acknowledged alert instance once", func(t *testing.T) {
+		mgr, _, _, s := setup(t, eval.Alerting)
+		lastSentAt := now.Add(-time.Minute)
+		s.LastSentAt = &lastSentAt
+		_, err := mgr.Acknowledge(ctx, rule, s, ngmodels.AlertAcknowledgement{AcknowledgedBy: "admin"})
+		require.NoError(t, err)
+
+		// The alert is sent once with the annotations of the acknowledgement.
+		evaluatedAt := now.Add(time.Minute)
+		firing := mgr.cache.get(s.OrgID, s.AlertRuleUID, s.CacheID).Copy()
+		transitions := StateTransitions{{State: firing, PreviousState: eval.Alerting}}
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), transitions, evaluatedAt)
+		toSend := mgr.updateLastSentAt(transitions, evaluatedAt)
+		require.Len(t, toSend, 1)
+		require.Equal(t, "admin", StateToPostableAlert(toSend[0], nil).Annotations[ngmodels.AcknowledgedByAnnotation])
+
+		// It is not re-sent while it is acknowledged.
+		evaluatedAt = evaluatedAt.Add(time.Hour)
+		firing = firing.Copy()
+		transitions = StateTransitions{{State: firing, PreviousState: eval.Alerting}}
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), transitions, evaluatedAt)
+		require.Empty(t, mgr.updateLastSentAt(transitions, evaluatedAt))
+
+		// It is sent again when the acknowledgement ends.
+		require.NoError(t, mgr.Unacknowledge(ctx, rule, s, "admin"))
+		firing = firing.Copy()
+		transitions = StateTransitions{{State: firing, PreviousState: eval.Alerting}}
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), transitions, evaluatedAt)
+		require.Len(t, mgr.updateLastSentAt(transitions, evaluatedAt), 1)
+	})
+
+	t.Run("should apply the acknowledgement to the next evaluations while the alert is firing", func(t *testing.T) {
+		mgr, store, _, s := setup(t, eval.Alerting)
+		_, err := mgr.Acknowledge(ctx, rule, s, ngmodels.AlertAcknowledgement{AcknowledgedBy: "admin"})
+		require.NoError(t, err)
+
+		firing := s.Copy()
+		transitions := StateTransitions{{State: firing, PreviousState: eval.Alerting}}
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), transitions, now.Add(time.Minute))
+		require.NotNil(t, firing.Acknowledgement)
+
+		resolved := s.Copy()
+		resolved.State = eval.Normal
+		transitions = StateTransitions{{State: resolved, PreviousState: eval.Alerting}}
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), transitions, now.Add(2*time.Minute))
+		require.Nil(t, resolved.Acknowledgement)
+		require.Empty(t, store.acks)
+		require.False(t, mgr.acknowledgements.hasRule(rule.GetKey()))
+	})
+
+	t.Run("should delete expired acknowledgements", func(t *testing.T) {
+		mgr, store, _, s := setup(t, eval.Alerting)
+		expiresAt := now.Add(time.Hour)
+		_, err := mgr.Acknowledge(ctx, rule, s, ngmodels.AlertAcknowledgement{AcknowledgedBy: "admin", ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+
+		firing := s.Copy()
+		transitions := StateTransitions{{State: firing, PreviousState: eval.Alerting}}
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), transitions, expiresAt)
+		require.Nil(t, firing.Acknowledgement)
+		require.Empty(t, store.acks)
+	})
+
+	t.Run("should unacknowledge an acknowledged alert instance", func(t *testing.T) {
+		mgr, store, historian, s := setup(t, eval.Alerting)
+		require.ErrorIs(t, mgr.Unacknowledge(ctx, rule, s, "admin"), ngmodels.ErrAlertAcknowledgementNotFound)
+
+		_, err := mgr.Acknowledge(ctx, rule, s, ngmodels.AlertAcknowledgement{AcknowledgedBy: "admin"})
+		require.NoError(t, err)
+		require.NoError(t, mgr.Unacknowledge(ctx, rule, s, "editor"))
+
+		require.Empty(t, store.acks)
+		require.Nil(t, mgr.cache.get(s.OrgID, s.AlertRuleUID, s.CacheID).Acknowledgement)
+		require.Len(t, historian.StateTransitions, 2)
+		require.Equal(t, "Unacknowledged by editor", historian.StateTransitions[1].StateReason)
+	})
+
+	t.Run("should load the acknowledgements of the store", func(t *testing.T) {
+		mgr, store, _, s := setup(t, eval.Alerting)
+		key, err := s.GetAlertInstanceKey()
+		require.NoError(t, err)
+		store.acks[key] = ngmodels.AlertAcknowledgement{AlertInstanceKey: key, AcknowledgedBy: "admin", Assignee: "admin", Created: now}
+
+		mgr.loadAcknowledgements(ctx)
+
+		firing := s.Copy()
+		mgr.applyAcknowledgements(ctx, mgr.log, rule.GetKey(), StateTransitions{{State: firing, PreviousState: eval.Alerting}}, now)
+		require.Equal(t, "admin", firing.Acknowledgement.AcknowledgedBy)
+	})
+}
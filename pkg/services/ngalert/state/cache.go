@@ -352,44 +352,52 @@ func (c *cache) GetAlertInstances() []ngModels.AlertInstance {
 	for _, orgStates := range c.states {
 		for _, v1 := range orgStates {
 			for _, v2 := range v1.states {
-				key, err := v2.GetAlertInstanceKey()
+				instance, err := stateToAlertInstance(v2)
 				if err != nil {
 					continue
 				}
-				var lastError string
-				if v2.Error != nil {
-					lastError = v2.Error.Error()
-				}
-				var lastResult ngModels.LastResult
-				if v2.LatestResult != nil {
-					lastResult = ngModels.LastResult{
-						Values:    v2.LatestResult.Values,
-						Condition: v2.LatestResult.Condition,
-					}
-				}
-				states = append(states, ngModels.AlertInstance{
-					AlertInstanceKey:   key,
-					Labels:             ngModels.InstanceLabels(v2.Labels),
-					Annotations:        v2.Annotations,
-					CurrentState:       ngModels.InstanceStateType(v2.State.String()),
-					CurrentReason:      v2.StateReason,
-					LastEvalTime:       v2.LastEvaluationTime,
-					CurrentStateSince:  v2.StartsAt,
-					CurrentStateEnd:    v2.EndsAt,
-					FiredAt:            v2.FiredAt,
-					ResolvedAt:         v2.ResolvedAt,
-					LastSentAt:         v2.LastSentAt,
-					ResultFingerprint:  v2.ResultFingerprint.String(),
-					EvaluationDuration: v2.EvaluationDuration,
-					LastError:          lastError,
-					LastResult:         lastResult,
-				})
+				states = append(states, instance)
 			}
 		}
 	}
 	return states
 }
 
+func stateToAlertInstance(s *State) (ngModels.AlertInstance, error) {
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return ngModels.AlertInstance{}, err
+	}
+	var lastError string
+	if s.Error != nil {
+		lastError = s.Error.Error()
+	}
+	var lastResult ngModels.LastResult
+	if s.LatestResult != nil {
+		lastResult = ngModels.LastResult{
+			Values:    s.LatestResult.Values,
+			Condition: s.LatestResult.Condition,
+		}
+	}
+	return ngModels.AlertInstance{
+		AlertInstanceKey:   key,
+		Labels:             ngModels.InstanceLabels(s.Labels),
+		Annotations:        s.Annotations,
+		CurrentState:       ngModels.InstanceStateType(s.State.String()),
+		CurrentReason:      s.StateReason,
+		LastEvalTime:       s.LastEvaluationTime,
+		CurrentStateSince:  s.StartsAt,
+		CurrentStateEnd:    s.EndsAt,
+		FiredAt:            s.FiredAt,
+		ResolvedAt:         s.ResolvedAt,
+		LastSentAt:         s.LastSentAt,
+		ResultFingerprint:  s.ResultFingerprint.String(),
+		EvaluationDuration: s.EvaluationDuration,
+		LastError:          lastError,
+		LastResult:         lastResult,
+	}, nil
+}
+
 // if duplicate labels exist, keep the value from the first set
 func mergeLabels(a, b data.Labels) data.Labels {
 	newLbs := make(data.Labels, len(a)+len(b))
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"path"
 	"strconv"
@@ -54,6 +55,21 @@ func StateToPostableAlert(transition StateTransition, appURL *url.URL) *models.P
 			delete(nA, k)
 		}
 	}
+	// the severity level is saved in internal annotations, see State.setSeverity
+	delete(nA, ngModels.SeverityStateAnnotation)
+	delete(nA, ngModels.SeverityLabelStateAnnotation)
+	delete(nA, ngModels.RecoveryStreakStateAnnotation)
+
+	// The severity level is a label so that notification policies can route by it. It is not a label of the state,
+	// so that an alert instance that changes level keeps its state. A resolved alert has the level it had while firing,
+	// so that it resolves the alert that was sent.
+	severity := alertState.Severity
+	if alertState.ResolvedAt != nil {
+		severity = transition.PreviousSeverity
+	}
+	if severity != "" && alertState.SeverityLabel != "" {
+		nL[alertState.SeverityLabel] = severity
+	}
 
 	// encode the values as JSON where it will be expanded later
 	if len(alertState.Values) > 0 {
@@ -74,6 +90,11 @@ func StateToPostableAlert(transition StateTransition, appURL *url.URL) *models.P
 		nA[alertingModels.StateReasonAnnotation] = alertState.StateReason
 	}
 
+	// The labels are not changed so that the alert keeps its fingerprint.
+	if alertState.Acknowledgement.IsActive(alertState.LastEvaluationTime) {
+		maps.Copy(nA, AcknowledgementAnnotations(alertState.Acknowledgement))
+	}
+
 	if alertState.OrgID != 0 {
 		nA[alertingModels.OrgIDAnnotation] = strconv.FormatInt(alertState.OrgID, 10)
 	}
@@ -167,6 +188,23 @@ func errorAlert(labels, annotations data.Labels, alertState *State, urlStr strin
 	}
 }
 
+// PreviousSeverityToStoppedAlert returns an alert that resolves the alert sent with the previous severity level
+// of an alert instance whose level changed while it kept firing, or nil if the level did not change.
+func PreviousSeverityToStoppedAlert(transition StateTransition, appURL *url.URL) *models.PostableAlert {
+	if !transition.SeverityChanged() {
+		return nil
+	}
+	previous := transition.Copy()
+	previous.Severity = transition.PreviousSeverity
+	previous.EndsAt = transition.LastEvaluationTime
+	return StateToPostableAlert(StateTransition{
+		State:               previous,
+		PreviousState:       transition.PreviousState,
+		PreviousStateReason: transition.PreviousStateReason,
+		PreviousSeverity:    transition.PreviousSeverity,
+	}, appURL)
+}
+
 // FromAlertsStateToStoppedAlert selects only transitions from firing states (states eval.Alerting, eval.NoData, eval.Error)
 // and converts them to models.PostableAlert with EndsAt set to time.Now
 func FromAlertsStateToStoppedAlert(firingStates []StateTransition, appURL *url.URL, clock clock.Clock) apimodels.PostableAlerts {
@@ -223,6 +261,11 @@ func AlertInstanceToState(entry *ngModels.AlertInstance, logger log.Logger) *Sta
 		}
 	}
 
+	// the severity level is restored so that the instance does not start over from no level
+	severity := entry.Annotations[ngModels.SeverityStateAnnotation]
+	severityLabel := entry.Annotations[ngModels.SeverityLabelStateAnnotation]
+	recoveryStreak, _ := strconv.Atoi(entry.Annotations[ngModels.RecoveryStreakStateAnnotation])
+
 	return &State{
 		AlertRuleUID:         entry.RuleUID,
 		OrgID:                entry.RuleOrgID,
@@ -243,6 +286,9 @@ func AlertInstanceToState(entry *ngModels.AlertInstance, logger log.Logger) *Sta
 		Error:                stateError,
 		Values:               values,
 		LatestResult:         latestResult,
+		Severity:             severity,
+		SeverityLabel:        severityLabel,
+		RecoveryStreak:       recoveryStreak,
 	}
 }
 
@@ -447,3 +447,74 @@ func TestAlertInstanceToState(t *testing.T) {
 	require.Empty(t, cmp.Diff(expected, state, cmpopts.IgnoreFields(State{}, "Error")))
 	require.EqualError(t, state.Error, "some error")
 }
+
+func TestSeverityIsRestoredAndSentAsLabel(t *testing.T) {
+	instance := ngModels.AlertInstanceGen(ngModels.InstanceMuts.WithState(ngModels.InstanceStateFiring))
+	instance.Annotations = map[string]string{
+		ngModels.SeverityStateAnnotation:       "warning",
+		ngModels.SeverityLabelStateAnnotation:  "level",
+		ngModels.RecoveryStreakStateAnnotation: "3",
+	}
+
+	state := AlertInstanceToState(instance, log.NewNopLogger())
+	require.Equal(t, "warning", state.Severity)
+	require.Equal(t, "level", state.SeverityLabel)
+	require.Equal(t, 3, state.RecoveryStreak)
+	require.NotContains(t, state.Labels, "level")
+
+	alert := StateToPostableAlert(StateTransition{State: state}, nil)
+	require.Equal(t, "warning", alert.Labels["level"])
+	require.NotContains(t, alert.Annotations, ngModels.SeverityStateAnnotation)
+	require.NotContains(t, alert.Annotations, ngModels.SeverityLabelStateAnnotation)
+	require.NotContains(t, alert.Annotations, ngModels.RecoveryStreakStateAnnotation)
+}
+
+func TestPreviousSeverityToStoppedAlert(t *testing.T) {
+	evaluatedAt := time.Now()
+	firing := func() *State {
+		return &State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"host": "a"},
+			Severity:           "warning",
+			SeverityLabel:      "severity",
+			LastEvaluationTime: evaluatedAt,
+			EndsAt:             evaluatedAt.Add(4 * time.Minute),
+		}
+	}
+
+	t.Run("resolves the alert of the previous level", func(t *testing.T) {
+		transition := StateTransition{State: firing(), PreviousState: eval.Alerting, PreviousSeverity: "critical"}
+		require.True(t, transition.SeverityChanged())
+
+		alert := StateToPostableAlert(transition, nil)
+		require.Equal(t, models.LabelSet{"host": "a", "severity": "warning"}, alert.Labels)
+
+		stopped := PreviousSeverityToStoppedAlert(transition, nil)
+		require.NotNil(t, stopped)
+		require.Equal(t, models.LabelSet{"host": "a", "severity": "critical"}, stopped.Labels)
+		require.Equal(t, strfmt.DateTime(evaluatedAt), stopped.EndsAt)
+		require.Equal(t, "warning", transition.Severity)
+	})
+
+	t.Run("returns nil if the level did not change", func(t *testing.T) {
+		transition := StateTransition{State: firing(), PreviousState: eval.Alerting, PreviousSeverity: "warning"}
+		require.False(t, transition.SeverityChanged())
+		require.Nil(t, PreviousSeverityToStoppedAlert(transition, nil))
+	})
+
+	t.Run("returns nil if the alert was not firing", func(t *testing.T) {
+		transition := StateTransition{State: firing(), PreviousState: eval.Pending, PreviousSeverity: "critical"}
+		require.False(t, transition.SeverityChanged())
+		require.Nil(t, PreviousSeverityToStoppedAlert(transition, nil))
+	})
+
+	t.Run("a resolved alert has the previous level", func(t *testing.T) {
+		state := firing()
+		state.State, state.Severity, state.ResolvedAt = eval.Normal, "", &evaluatedAt
+		transition := StateTransition{State: state, PreviousState: eval.Alerting, PreviousSeverity: "critical"}
+		require.False(t, transition.SeverityChanged())
+
+		alert := StateToPostableAlert(transition, nil)
+		require.Equal(t, models.LabelSet{"host": "a", "severity": "critical"}, alert.Labels)
+	})
+}
@@ -19,8 +19,17 @@ const (
 	BackendTypeMultiple    BackendType = "multiple"
 	BackendTypePrometheus  BackendType = "prometheus"
 	BackendTypeNoop        BackendType = "noop"
+	BackendTypeDatabase    BackendType = "database"
+	BackendTypeWebhook     BackendType = "webhook"
+	BackendTypeFile        BackendType = "file"
 )
 
+// Queryable returns false if the backend cannot serve state history queries. Such backends can only be
+// secondaries of the multiple backend.
+func (bt BackendType) Queryable() bool {
+	return bt != BackendTypeWebhook && bt != BackendTypeFile
+}
+
 func ParseBackendType(s string) (BackendType, error) {
 	norm := strings.ToLower(strings.TrimSpace(s))
 
@@ -30,6 +39,9 @@ func ParseBackendType(s string) (BackendType, error) {
 		BackendTypeMultiple:    {},
 		BackendTypePrometheus:  {},
 		BackendTypeNoop:        {},
+		BackendTypeDatabase:    {},
+		BackendTypeWebhook:     {},
+		BackendTypeFile:        {},
 	}
 	p := BackendType(norm)
 	if _, ok := types[p]; !ok {
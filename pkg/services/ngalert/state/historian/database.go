@@ -0,0 +1,285 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const (
+	// defaultDatabaseQueryLimit is the maximum number of transitions returned by a query without a limit.
+	defaultDatabaseQueryLimit = 1000
+	// databaseQueryBatchSize is the number of rows read at once while filtering transitions by labels and state.
+	databaseQueryBatchSize = 1000
+	// databaseCleanupInterval is how often transitions older than the retention are deleted.
+	databaseCleanupInterval = 10 * time.Minute
+)
+
+// stateHistoryEntry is a row of the alert_state_history table.
+type stateHistoryEntry struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	OrgID          int64  `xorm:"org_id"`
+	RuleUID        string `xorm:"rule_uid"`
+	RuleVersion    int64  `xorm:"rule_version"`
+	FolderUID      string `xorm:"folder_uid"`
+	RuleGroup      string `xorm:"rule_group"`
+	DashboardUID   string `xorm:"dashboard_uid"`
+	PanelID        int64  `xorm:"panel_id"`
+	PreviousState  string `xorm:"previous_state"`
+	CurrentState   string `xorm:"current_state"`
+	Entry          string `xorm:"entry"`
+	TransitionedAt int64  `xorm:"transitioned_at"`
+}
+
+func (e stateHistoryEntry) TableName() string {
+	return "alert_state_history"
+}
+
+// DatabaseBackend is a state.Historian that records state history to a table in the Grafana database.
+// Transitions are stored in the same format as the Loki backend, so that it can serve the same queries.
+type DatabaseBackend struct {
+	db        db.DB
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	ac        AccessControl
+	ruleStore RuleStore
+	// retention is how long transitions are kept. Transitions are kept forever if it is zero.
+	retention time.Duration
+	// lastCleanup is the time of the last deletion of expired transitions, in Unix nanoseconds.
+	lastCleanup atomic.Int64
+}
+
+func NewDatabaseBackend(logger log.Logger, store db.DB, retention time.Duration, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *DatabaseBackend {
+	return &DatabaseBackend{
+		db:        store,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+		ac:        ac,
+		ruleStore: ruleStore,
+		retention: retention,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *DatabaseBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build the rows before starting the goroutine, to make sure all data is copied and won't mutate underneath us.
+	entries := h.buildEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	// This also prevents timeouts or other lingering objects (like transactions) from being
+	// incorrectly propagated here from other areas.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(entries))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "database").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.BulkInsert(stateHistoryEntry{}, entries, sqlstore.NativeSettingsForDialect(h.db.GetDialect()))
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "database").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(entries))
+
+		if err := h.cleanup(ctx); err != nil {
+			logger.Warn("Failed to delete expired alert state history", "error", err)
+		}
+	}(writeCtx)
+	return errCh
+}
+
+func (h *DatabaseBackend) buildEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []stateHistoryEntry {
+	entries := make([]stateHistoryEntry, 0, len(states))
+	for _, state := range states {
+		if !ShouldRecord(state) {
+			continue
+		}
+
+		entry := StateTransitionToLokiEntry(rule, state)
+		jsn, err := json.Marshal(entry)
+		if err != nil {
+			logger.Error("Failed to construct history record for state, skipping", "error", err)
+			continue
+		}
+
+		entries = append(entries, stateHistoryEntry{
+			OrgID:          rule.OrgID,
+			RuleUID:        rule.UID,
+			RuleVersion:    rule.Version,
+			FolderUID:      rule.NamespaceUID,
+			RuleGroup:      rule.Group,
+			DashboardUID:   rule.DashboardUID,
+			PanelID:        rule.PanelID,
+			PreviousState:  entry.Previous,
+			CurrentState:   entry.Current,
+			Entry:          string(jsn),
+			TransitionedAt: state.LastEvaluationTime.UnixNano(),
+		})
+	}
+	return entries
+}
+
+// cleanup deletes the transitions that are older than the retention. It does nothing if the
+// retention is not set or if the last cleanup happened less than databaseCleanupInterval ago.
+func (h *DatabaseBackend) cleanup(ctx context.Context) error {
+	if h.retention <= 0 {
+		return nil
+	}
+	now := h.clock.Now()
+	last := h.lastCleanup.Load()
+	if now.Sub(time.Unix(0, last)) < databaseCleanupInterval || !h.lastCleanup.CompareAndSwap(last, now.UnixNano()) {
+		return nil
+	}
+	return h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := sess.Where("transitioned_at < ?", now.Add(-h.retention).UnixNano()).Delete(&stateHistoryEntry{})
+		if err != nil {
+			return err
+		}
+		h.log.FromContext(ctx).Debug("Deleted expired alert state history", "count", deleted)
+		return nil
+	})
+}
+
+// Query retrieves state history entries from the database and formats the results into the same dataframe as the Loki backend.
+func (h *DatabaseBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore, h.log)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultDatabaseQueryLimit
+	}
+
+	// The latest transitions are returned, like Loki does, but ordered by time.
+	var result []stateHistoryEntry
+	err = h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		for offset := 0; len(result) < limit; offset += databaseQueryBatchSize {
+			var batch []stateHistoryEntry
+			q := sess.Where("org_id = ?", query.OrgID).
+				And("transitioned_at >= ?", query.From.UnixNano()).
+				And("transitioned_at <= ?", query.To.UnixNano())
+			if query.RuleUID != "" {
+				q = q.And("rule_uid = ?", query.RuleUID)
+			}
+			if query.DashboardUID != "" {
+				q = q.And("dashboard_uid = ?", query.DashboardUID)
+			}
+			if query.PanelID != 0 {
+				q = q.And("panel_id = ?", query.PanelID)
+			}
+			if len(uids) > 0 {
+				q = q.In("folder_uid", uids)
+			}
+			if err := q.Desc("transitioned_at", "id").Limit(databaseQueryBatchSize, offset).Find(&batch); err != nil {
+				return err
+			}
+			for _, e := range batch {
+				if len(result) == limit {
+					break
+				}
+				if h.matches(e, query) {
+					result = append(result, e)
+				}
+			}
+			if len(batch) < databaseQueryBatchSize {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert state history: %w", err)
+	}
+	slices.Reverse(result)
+
+	queryResult := NewQueryResultBuilder(len(result))
+	for _, e := range result {
+		lbls, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.FolderUID,
+		})
+		if err != nil {
+			// This should in theory never happen, as we're marshalling a map[string]string.
+			h.log.Warn("failed to serialize stream labels, continuing", "err", err)
+			continue
+		}
+		queryResult.AddRowRaw(time.Unix(0, e.TransitionedAt), json.RawMessage(e.Entry), lbls)
+	}
+	return queryResult.ToFrame(), nil
+}
+
+// matches returns true if the entry matches the filters of the query that cannot be applied in the database.
+func (h *DatabaseBackend) matches(e stateHistoryEntry, query models.HistoryQuery) bool {
+	if query.Previous != "" && !strings.HasPrefix(e.PreviousState, query.Previous) {
+		return false
+	}
+	if query.Current != "" && !strings.HasPrefix(e.CurrentState, query.Current) {
+		return false
+	}
+	if len(query.Labels) == 0 {
+		return true
+	}
+	var entry LokiEntry
+	if err := json.Unmarshal([]byte(e.Entry), &entry); err != nil {
+		h.log.Warn("failed to unmarshal entry, continuing", "err", err, "id", e.ID)
+		return false
+	}
+	for _, m := range query.Labels {
+		if !m.Matches(entry.InstanceLabels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
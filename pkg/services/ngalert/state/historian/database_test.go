@@ -0,0 +1,188 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/folder"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationDatabaseBackend(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	usr := accesscontrol.BackgroundUser("test", 1, org.RoleNone, nil)
+	transition := func(at int64, previous, current eval.State, lbls data.Labels) state.StateTransition {
+		return state.StateTransition{
+			PreviousState: previous,
+			State: &state.State{
+				State:              current,
+				Labels:             lbls,
+				Values:             map[string]float64{"A": float64(at)},
+				LastEvaluationTime: time.Unix(at, 0),
+			},
+		}
+	}
+	record := func(t *testing.T, h *DatabaseBackend, rule history_model.RuleMeta, states ...state.StateTransition) {
+		t.Helper()
+		for err := range h.Record(context.Background(), rule, states) {
+			require.NoError(t, err)
+		}
+	}
+	entries := func(t *testing.T, frame *data.Frame) []LokiEntry {
+		t.Helper()
+		result := make([]LokiEntry, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	ruleA := createTestRule()
+	ruleA.UID = "rule-a"
+	ruleA.Version = 3
+	ruleA.NamespaceUID = "folder-a"
+	ruleB := createTestRule()
+	ruleB.UID = "rule-b"
+	ruleB.NamespaceUID = "folder-b"
+
+	createBackend := func(t *testing.T, ac AccessControl) *DatabaseBackend {
+		rules := fakes.NewRuleStore(t)
+		rules.Folders = map[int64][]*folder.Folder{
+			1: {{UID: "folder-a", OrgID: 1}, {UID: "folder-b", OrgID: 1}},
+		}
+		rules.Rules = map[int64][]*models.AlertRule{
+			1: {
+				{OrgID: 1, UID: ruleA.UID, NamespaceUID: ruleA.NamespaceUID},
+				{OrgID: 1, UID: ruleB.UID, NamespaceUID: ruleB.NamespaceUID},
+			},
+		}
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+		h := NewDatabaseBackend(log.NewNopLogger(), db.InitTestDB(t), 0, met, rules, ac)
+
+		record(t, h, ruleA,
+			transition(10, eval.Normal, eval.Pending, data.Labels{"host": "a"}),
+			transition(10, eval.Normal, eval.Alerting, data.Labels{"host": "b"}),
+			transition(10, eval.Normal, eval.Normal, data.Labels{"host": "c"}),
+		)
+		record(t, h, ruleA, transition(20, eval.Pending, eval.Alerting, data.Labels{"host": "a"}))
+		record(t, h, ruleB, transition(30, eval.Normal, eval.Alerting, data.Labels{"host": "a"}))
+		return h
+	}
+	canReadAll := &acfakes.FakeRuleService{
+		CanReadAllRulesFunc: func(context.Context, identity.Requester) (bool, error) {
+			return true, nil
+		},
+	}
+	query := func(q models.HistoryQuery) models.HistoryQuery {
+		q.OrgID = 1
+		q.SignedInUser = usr
+		q.From = time.Unix(0, 0)
+		q.To = time.Unix(100, 0)
+		return q
+	}
+
+	t.Run("returns the transitions of the rule in the format of loki", func(t *testing.T) {
+		h := createBackend(t, canReadAll)
+
+		frame, err := h.Query(context.Background(), query(models.HistoryQuery{RuleUID: ruleA.UID}))
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, []string{dfTime, dfLine, dfLabels}, []string{frame.Fields[0].Name, frame.Fields[1].Name, frame.Fields[2].Name})
+		require.Equal(t, time.Unix(10, 0), frame.Fields[0].At(0))
+		require.Equal(t, time.Unix(20, 0), frame.Fields[0].At(2))
+
+		result := entries(t, frame)
+		require.Equal(t, "Normal", result[0].Previous)
+		require.Equal(t, "Alerting", result[2].Current)
+		require.Equal(t, map[string]string{"host": "a"}, result[2].InstanceLabels)
+		require.Equal(t, int64(3), result[2].RuleVersion)
+		require.Equal(t, 20.0, result[2].Values.Get("A").MustFloat64())
+
+		var lbls map[string]string
+		require.NoError(t, json.Unmarshal(frame.Fields[2].At(0).(json.RawMessage), &lbls))
+		require.Equal(t, map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           "1",
+			GroupLabel:           ruleA.Group,
+			FolderUIDLabel:       ruleA.NamespaceUID,
+		}, lbls)
+	})
+
+	t.Run("filters by labels and states", func(t *testing.T) {
+		h := createBackend(t, canReadAll)
+
+		frame, err := h.Query(context.Background(), query(models.HistoryQuery{
+			Labels:  labels.Matchers{{Type: labels.MatchEqual, Name: "host", Value: "a"}},
+			Current: "Alerting",
+		}))
+		require.NoError(t, err)
+		result := entries(t, frame)
+		require.Len(t, result, 2)
+		require.Equal(t, ruleA.UID, result[0].RuleUID)
+		require.Equal(t, ruleB.UID, result[1].RuleUID)
+	})
+
+	t.Run("returns the latest transitions up to the limit", func(t *testing.T) {
+		h := createBackend(t, canReadAll)
+
+		frame, err := h.Query(context.Background(), query(models.HistoryQuery{Limit: 2}))
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(20, 0), frame.Fields[0].At(0))
+		require.Equal(t, time.Unix(30, 0), frame.Fields[0].At(1))
+	})
+
+	t.Run("returns only the transitions in folders the user can read", func(t *testing.T) {
+		ac := &acfakes.FakeRuleService{
+			HasAccessInFolderFunc: func(_ context.Context, _ identity.Requester, n models.Namespaced) (bool, error) {
+				return n.GetNamespaceUID() == "folder-b", nil
+			},
+		}
+		h := createBackend(t, ac)
+
+		frame, err := h.Query(context.Background(), query(models.HistoryQuery{}))
+		require.NoError(t, err)
+		result := entries(t, frame)
+		require.Len(t, result, 1)
+		require.Equal(t, ruleB.UID, result[0].RuleUID)
+	})
+
+	t.Run("deletes transitions older than the retention", func(t *testing.T) {
+		h := createBackend(t, canReadAll)
+		clk := clock.NewMock()
+		clk.Set(time.Unix(0, 0).Add(databaseCleanupInterval + 25*time.Second))
+		h.clock = clk
+		h.retention = databaseCleanupInterval
+
+		record(t, h, ruleB, transition(40, eval.Alerting, eval.Normal, data.Labels{"host": "a"}))
+
+		frame, err := h.Query(context.Background(), query(models.HistoryQuery{}))
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(30, 0), frame.Fields[0].At(0))
+		require.Equal(t, time.Unix(40, 0), frame.Fields[0].At(1))
+	})
+}
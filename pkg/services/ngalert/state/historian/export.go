@@ -0,0 +1,32 @@
+package historian
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+// TransitionRecord is a state transition as exported by the webhook and file backends.
+// Entry has the same format as the lines written to Loki, and Labels are the labels of the Loki stream.
+type TransitionRecord struct {
+	// Timestamp is the time of the transition in Unix nanoseconds.
+	Timestamp int64             `json:"timestamp"`
+	Labels    map[string]string `json:"labels"`
+	Entry     json.RawMessage   `json:"entry"`
+}
+
+// StatesToRecords converts the state transitions that should be recorded to records.
+func StatesToRecords(rule history_model.RuleMeta, states []state.StateTransition, externalLabels map[string]string, logger log.Logger) []TransitionRecord {
+	stream := StatesToStream(rule, states, externalLabels, logger)
+	records := make([]TransitionRecord, 0, len(stream.Values))
+	for _, sample := range stream.Values {
+		records = append(records, TransitionRecord{
+			Timestamp: sample.T.UnixNano(),
+			Labels:    stream.Stream,
+			Entry:     json.RawMessage(sample.V),
+		})
+	}
+	return records
+}
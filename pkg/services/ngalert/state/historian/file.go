@@ -0,0 +1,171 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type FileConfig struct {
+	Path string
+	// MaxSize is the size in bytes after which the file is rotated.
+	MaxSize int64
+	// MaxFiles is the number of rotated files that are kept, in addition to the current file.
+	MaxFiles int
+}
+
+func NewFileConfig(cfg setting.UnifiedAlertingStateHistorySettings) (FileConfig, error) {
+	if cfg.FilePath == "" {
+		return FileConfig{}, errors.New("file path must not be empty")
+	}
+	if cfg.FileMaxSize <= 0 {
+		return FileConfig{}, errors.New("file max size must be positive")
+	}
+	if cfg.FileMaxFiles < 0 {
+		return FileConfig{}, errors.New("file max files must not be negative")
+	}
+
+	return FileConfig{
+		Path:     cfg.FilePath,
+		MaxSize:  cfg.FileMaxSize,
+		MaxFiles: cfg.FileMaxFiles,
+	}, nil
+}
+
+// FileBackend is a state.Historian that writes state history to a local file as newline delimited JSON.
+// Each line is a TransitionRecord. When the file reaches the maximum size, it is rotated to <path>.1,
+// the previous <path>.1 to <path>.2 and so on, and the oldest file is deleted.
+type FileBackend struct {
+	cfg            FileConfig
+	externalLabels map[string]string
+	metrics        *metrics.Historian
+	log            log.Logger
+
+	mtx  sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewFileBackend(cfg FileConfig, externalLabels map[string]string, logger log.Logger, metrics *metrics.Historian) *FileBackend {
+	return &FileBackend{
+		cfg:            cfg,
+		externalLabels: externalLabels,
+		metrics:        metrics,
+		log:            logger,
+	}
+}
+
+func (h *FileBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	return nil, fmt.Errorf("file historian backend does not support querying")
+}
+
+// Record appends a number of state transitions for a given rule to the file.
+func (h *FileBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	records := StatesToRecords(rule, states, h.externalLabels, logger)
+
+	errCh := make(chan error, 1)
+	if len(records) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	go func() {
+		defer close(errCh)
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "file").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(records)))
+		if err := h.write(records); err != nil {
+			logger.Error("Failed to write alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "file").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(records)))
+			errCh <- fmt.Errorf("failed to write alert state history batch: %w", err)
+		}
+	}()
+	return errCh
+}
+
+func (h *FileBackend) write(records []TransitionRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.file == nil {
+		if err := h.open(); err != nil {
+			return err
+		}
+	}
+	if h.size > 0 && h.size+int64(buf.Len()) > h.cfg.MaxSize {
+		if err := h.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate file: %w", err)
+		}
+		if err := h.open(); err != nil {
+			return err
+		}
+	}
+	n, err := h.file.Write(buf.Bytes())
+	h.size += int64(n)
+	return err
+}
+
+func (h *FileBackend) open() error {
+	if err := os.MkdirAll(filepath.Dir(h.cfg.Path), 0o750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	h.file, h.size = f, info.Size()
+	return nil
+}
+
+// rotate closes the current file and shifts the rotated files by one, deleting the oldest.
+func (h *FileBackend) rotate() error {
+	if err := h.file.Close(); err != nil {
+		return err
+	}
+	h.file, h.size = nil, 0
+
+	if h.cfg.MaxFiles == 0 {
+		return os.Remove(h.cfg.Path)
+	}
+	if err := os.Remove(rotatedFileName(h.cfg.Path, h.cfg.MaxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := h.cfg.MaxFiles - 1; i > 0; i-- {
+		if err := os.Rename(rotatedFileName(h.cfg.Path, i), rotatedFileName(h.cfg.Path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(h.cfg.Path, rotatedFileName(h.cfg.Path, 1))
+}
+
+func rotatedFileName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
@@ -0,0 +1,81 @@
+package historian
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestFileBackend(t *testing.T) {
+	transitions := func(at int64) []state.StateTransition {
+		return singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"host": "a"},
+			LastEvaluationTime: time.Unix(at, 0),
+		})
+	}
+	readRecords := func(t *testing.T, path string) []TransitionRecord {
+		t.Helper()
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		var result []TransitionRecord
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var r TransitionRecord
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+			result = append(result, r)
+		}
+		require.NoError(t, scanner.Err())
+		return result
+	}
+	createBackend := func(cfg FileConfig) *FileBackend {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+		return NewFileBackend(cfg, nil, log.NewNopLogger(), met)
+	}
+
+	t.Run("appends transitions as newline delimited json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "history", "state.ndjson")
+		h := createBackend(FileConfig{Path: path, MaxSize: 1024 * 1024, MaxFiles: 1})
+
+		require.NoError(t, <-h.Record(context.Background(), createTestRule(), transitions(1)))
+		require.NoError(t, <-h.Record(context.Background(), createTestRule(), transitions(2)))
+
+		records := readRecords(t, path)
+		require.Len(t, records, 2)
+		require.Equal(t, time.Unix(2, 0).UnixNano(), records[1].Timestamp)
+		var entry LokiEntry
+		require.NoError(t, json.Unmarshal(records[1].Entry, &entry))
+		require.Equal(t, "Normal", entry.Previous)
+		require.Equal(t, "Alerting", entry.Current)
+		require.Equal(t, createTestRule().UID, entry.RuleUID)
+	})
+
+	t.Run("rotates the file when it reaches the maximum size", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.ndjson")
+		// Every record is larger than the maximum size, so every write rotates the file.
+		h := createBackend(FileConfig{Path: path, MaxSize: 10, MaxFiles: 2})
+
+		for i := range 4 {
+			require.NoError(t, <-h.Record(context.Background(), createTestRule(), transitions(int64(i))))
+		}
+
+		require.Equal(t, time.Unix(3, 0).UnixNano(), readRecords(t, path)[0].Timestamp)
+		require.Equal(t, time.Unix(2, 0).UnixNano(), readRecords(t, path+".1")[0].Timestamp)
+		require.Equal(t, time.Unix(1, 0).UnixNano(), readRecords(t, path+".2")[0].Timestamp)
+		require.NoFileExists(t, path+".3")
+	})
+}
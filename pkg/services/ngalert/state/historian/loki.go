@@ -365,6 +365,7 @@ func StateTransitionToLokiEntry(rule history_model.RuleMeta, state state.StateTr
 		RuleTitle:      rule.Title,
 		RuleID:         rule.ID,
 		RuleUID:        rule.UID,
+		RuleVersion:    rule.Version,
 		InstanceLabels: sanitizedLabels,
 	}
 	if state.Error != nil {
@@ -395,6 +396,7 @@ type LokiEntry struct {
 	RuleTitle     string           `json:"ruleTitle"`
 	RuleID        int64            `json:"ruleID"`
 	RuleUID       string           `json:"ruleUID"`
+	RuleVersion   int64            `json:"ruleVersion,omitempty"`
 	// InstanceLabels is exactly the set of labels associated with the alert instance in Alertmanager.
 	// These should not be conflated with labels associated with log streams.
 	InstanceLabels map[string]string `json:"labels"`
@@ -566,13 +568,19 @@ func queryHasLogFilters(query models.HistoryQuery) bool {
 }
 
 func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery) ([]string, error) {
-	bypass, err := h.ac.CanReadAllRules(ctx, query.SignedInUser)
+	return getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore, h.log)
+}
+
+// getFolderUIDsForFilter returns the UIDs of the folders whose history the user can read.
+// It returns nil if the history does not need to be filtered by folder.
+func getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery, ac AccessControl, ruleStore RuleStore, logger log.Logger) ([]string, error) {
+	bypass, err := ac.CanReadAllRules(ctx, query.SignedInUser)
 	if err != nil {
 		return nil, err
 	}
 
 	if query.RuleUID != "" {
-		return h.getFolderUIDsForRuleFilter(ctx, query, bypass)
+		return getFolderUIDsForRuleFilter(ctx, query, bypass, ac, ruleStore, logger)
 	}
 
 	// If the query has no rule filter, we need to return all folder UIDs the user has access to.
@@ -583,14 +591,14 @@ func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query mo
 	}
 
 	// All folders the user has access to.
-	folders, err := h.ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
+	folders, err := ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch folders that user can access: %w", err)
 	}
 	uids := make([]string, 0, len(folders))
 	// Keep only UIDs of folder in which user can read rules.
 	for _, f := range folders {
-		hasAccess, err := h.ac.HasAccessInFolder(ctx, query.SignedInUser, models.NewNamespace(f))
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.NewNamespace(f))
 		if err != nil {
 			return nil, err
 		}
@@ -606,15 +614,15 @@ func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query mo
 	return uids, nil
 }
 
-func (h *RemoteLokiBackend) getFolderUIDsForRuleFilter(ctx context.Context, query models.HistoryQuery, canReadAll bool) ([]string, error) {
-	rule, err := h.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
+func getFolderUIDsForRuleFilter(ctx context.Context, query models.HistoryQuery, canReadAll bool, ac AccessControl, ruleStore RuleStore, logger log.Logger) ([]string, error) {
+	rule, err := ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
 		UID:   query.RuleUID,
 		OrgID: query.OrgID,
 	})
 	if err != nil {
 		if canReadAll {
 			// When the user can read all rules, filtering by folder UID is purely an optimization, so we can ignore errors here.
-			h.log.FromContext(ctx).Debug("failed to fetch alert rule by UID", "err", err)
+			logger.FromContext(ctx).Debug("failed to fetch alert rule by UID", "err", err)
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to fetch alert rule by UID: %w", err)
@@ -624,7 +632,7 @@ func (h *RemoteLokiBackend) getFolderUIDsForRuleFilter(ctx context.Context, quer
 	// Whether we should check historical folders they might still have access to is not 100% clear, but it seems more
 	// intuitive to deny access in this case.
 	if !canReadAll {
-		if err := h.ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule); err != nil {
+		if err := ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule); err != nil {
 			return nil, err
 		}
 	}
@@ -632,11 +640,11 @@ func (h *RemoteLokiBackend) getFolderUIDsForRuleFilter(ctx context.Context, quer
 	// We want to return folder UIDs when possible, as it's indexed in Loki and will help with query performance.
 	// However, by just returning the current folder UID the user can lose history when a rule is moved between folders.
 	// So, we attempt to get historical folder UIDs from the rule's history.
-	historicalFolders, err := h.ruleStore.GetAlertRuleVersionFolders(ctx, rule.OrgID, rule.GUID)
+	historicalFolders, err := ruleStore.GetAlertRuleVersionFolders(ctx, rule.OrgID, rule.GUID)
 	if err != nil {
 		// Including historical folders is an edge case enhancement, better to just log the error and continue
 		// with the current folder UID.
-		h.log.FromContext(ctx).Debug("failed to include historical folder UIDs for rule", "err", err)
+		logger.FromContext(ctx).Debug("failed to include historical folder UIDs for rule", "err", err)
 	}
 
 	accessibleFolders := make([]string, 0, len(historicalFolders)+1)
@@ -656,11 +664,11 @@ func (h *RemoteLokiBackend) getFolderUIDsForRuleFilter(ctx context.Context, quer
 			continue
 		}
 
-		hasAccess, err := h.ac.HasAccessInFolder(ctx, query.SignedInUser, models.NewNamespaceUID(folderUID))
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.NewNamespaceUID(folderUID))
 		if err != nil {
 			// Including historical folders is an edge case enhancement, better to just log the error and continue
 			// with the current folder UID.
-			h.log.FromContext(ctx).Debug("failed to check access to folder", "err", err, "folderUID", folderUID)
+			logger.FromContext(ctx).Debug("failed to check access to folder", "err", err, "folderUID", folderUID)
 			continue
 		}
 		if !hasAccess {
@@ -13,6 +13,7 @@ type RuleMeta struct {
 	ID           int64
 	OrgID        int64
 	UID          string
+	Version      int64
 	Title        string
 	Group        string
 	NamespaceUID string
@@ -38,6 +39,7 @@ func NewRuleMeta(r *models.AlertRule, logger log.Logger) RuleMeta {
 		ID:           r.ID,
 		OrgID:        r.OrgID,
 		UID:          r.UID,
+		Version:      r.Version,
 		Title:        r.Title,
 		Group:        r.RuleGroup,
 		NamespaceUID: r.NamespaceUID,
@@ -0,0 +1,286 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// WebhookSignatureHeader is the header with the HMAC-SHA256 signature of the timestamp and the body of the request.
+	WebhookSignatureHeader = "X-Grafana-Alerting-Signature"
+	// WebhookTimestampHeader is the header with the Unix timestamp of the request, in seconds.
+	WebhookTimestampHeader = "X-Grafana-Alerting-Timestamp"
+
+	webhookTimeout = 10 * time.Second
+)
+
+type WebhookConfig struct {
+	URL *url.URL
+	// Secret is used to sign the requests. Requests are not signed if it is empty.
+	Secret  string
+	Backoff backoff.Config
+	// BatchSize is the maximum number of transitions sent in a single request.
+	BatchSize int
+	// FlushInterval is how long transitions are buffered before they are sent, unless there are
+	// BatchSize transitions before. Transitions are sent right away if it is zero.
+	FlushInterval time.Duration
+}
+
+func NewWebhookConfig(cfg setting.UnifiedAlertingStateHistorySettings) (WebhookConfig, error) {
+	if cfg.WebhookURL == "" {
+		return WebhookConfig{}, errors.New("webhook URL must not be empty")
+	}
+	u, err := url.Parse(cfg.WebhookURL)
+	if err != nil {
+		return WebhookConfig{}, fmt.Errorf("failed to parse webhook URL: %w", err)
+	}
+	if cfg.WebhookMaxRetries < 0 {
+		return WebhookConfig{}, errors.New("webhook max retries must not be negative")
+	}
+	if cfg.WebhookBatchSize <= 0 {
+		return WebhookConfig{}, errors.New("webhook batch size must be positive")
+	}
+	if cfg.WebhookFlushInterval < 0 {
+		return WebhookConfig{}, errors.New("webhook flush interval must not be negative")
+	}
+
+	return WebhookConfig{
+		URL:    u,
+		Secret: cfg.WebhookSecret,
+		Backoff: backoff.Config{
+			MinBackoff: time.Second,
+			MaxBackoff: 10 * time.Second,
+			// The first attempt counts as a retry.
+			MaxRetries: cfg.WebhookMaxRetries + 1,
+		},
+		BatchSize:     cfg.WebhookBatchSize,
+		FlushInterval: cfg.WebhookFlushInterval,
+	}, nil
+}
+
+type webhookClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookPayload is the body of the requests sent by the webhook backend.
+type WebhookPayload struct {
+	Transitions []TransitionRecord `json:"transitions"`
+}
+
+// WebhookBackend is a state.Historian that sends state history as JSON to an HTTP endpoint.
+// The transitions of all rules are buffered for the flush interval and sent in batches, so that
+// the number of requests does not grow with the number of evaluations.
+type WebhookBackend struct {
+	cfg            WebhookConfig
+	client         webhookClient
+	externalLabels map[string]string
+	metrics        *metrics.Historian
+	log            log.Logger
+
+	mtx sync.Mutex
+	// pending are the transitions that are not sent yet.
+	pending []webhookRecord
+	// waiting are the channels returned by Record for the pending transitions.
+	waiting []chan error
+	// flushTimer sends the pending transitions after the flush interval.
+	flushTimer *time.Timer
+}
+
+type webhookRecord struct {
+	org    string
+	record TransitionRecord
+}
+
+func NewWebhookBackend(cfg WebhookConfig, client webhookClient, externalLabels map[string]string, logger log.Logger, metrics *metrics.Historian) *WebhookBackend {
+	return &WebhookBackend{
+		cfg:            cfg,
+		client:         client,
+		externalLabels: externalLabels,
+		metrics:        metrics,
+		log:            logger,
+	}
+}
+
+func (h *WebhookBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	return nil, fmt.Errorf("webhook historian backend does not support querying")
+}
+
+// Record adds a number of state transitions for a given rule to the pending transitions. The returned
+// channel receives the error of sending the batches that contain them.
+func (h *WebhookBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	records := StatesToRecords(rule, states, h.externalLabels, logger)
+
+	errCh := make(chan error, 1)
+	if len(records) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	org := fmt.Sprint(rule.OrgID)
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for _, r := range records {
+		h.pending = append(h.pending, webhookRecord{org: org, record: r})
+	}
+	h.waiting = append(h.waiting, errCh)
+	if len(h.pending) >= h.cfg.BatchSize || h.cfg.FlushInterval <= 0 {
+		h.flushLocked()
+	} else if h.flushTimer == nil {
+		h.flushTimer = time.AfterFunc(h.cfg.FlushInterval, h.flush)
+	}
+	return errCh
+}
+
+func (h *WebhookBackend) flush() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.flushLocked()
+}
+
+// flushLocked sends the pending transitions in the background. The caller must hold the lock.
+func (h *WebhookBackend) flushLocked() {
+	if h.flushTimer != nil {
+		h.flushTimer.Stop()
+		h.flushTimer = nil
+	}
+	pending, waiting := h.pending, h.waiting
+	h.pending, h.waiting = nil, nil
+	if len(pending) == 0 {
+		return
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	ctx, cancel := context.WithTimeout(context.Background(), StateHistoryWriteTimeout)
+	go func() {
+		defer cancel()
+		var errs []error
+		for len(pending) > 0 {
+			batch := pending[:min(len(pending), h.cfg.BatchSize)]
+			pending = pending[len(batch):]
+			if err := h.sendBatch(ctx, batch); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		var err error
+		if len(errs) > 0 {
+			err = fmt.Errorf("failed to send alert state history batch: %w", errors.Join(errs...))
+		}
+		for _, ch := range waiting {
+			if err != nil {
+				ch <- err
+			}
+			close(ch)
+		}
+	}()
+}
+
+// sendBatch sends a batch of transitions, and updates the metrics of the organizations of the transitions.
+func (h *WebhookBackend) sendBatch(ctx context.Context, batch []webhookRecord) error {
+	records := make([]TransitionRecord, 0, len(batch))
+	orgs := make(map[string]int)
+	for _, r := range batch {
+		records = append(records, r.record)
+		orgs[r.org]++
+	}
+
+	h.log.Debug("Sending state history batch", "samples", len(records))
+	for org, n := range orgs {
+		h.metrics.WritesTotal.WithLabelValues(org, "webhook").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(n))
+	}
+	err := h.send(ctx, records, h.log)
+	if err != nil {
+		h.log.Error("Failed to send alert state history batch", "error", err)
+		for org, n := range orgs {
+			h.metrics.WritesFailed.WithLabelValues(org, "webhook").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(n))
+		}
+	}
+	return err
+}
+
+// send sends the records and retries with backoff on connection errors, 429 and 5xx responses.
+func (h *WebhookBackend) send(ctx context.Context, records []TransitionRecord, logger log.Logger) error {
+	body, err := json.Marshal(WebhookPayload{Transitions: records})
+	if err != nil {
+		return err
+	}
+
+	retries := backoff.New(ctx, h.cfg.Backoff)
+	for {
+		status, err := h.sendOnce(ctx, body)
+		if err == nil {
+			return nil
+		}
+		// Do not retry requests that will fail again.
+		if status > 0 && status != http.StatusTooManyRequests && status/100 != 5 {
+			return err
+		}
+
+		logger.Warn("Error sending state history batch, will retry", "status", status, "error", err)
+		retries.Wait()
+		if !retries.Ongoing() {
+			return err
+		}
+	}
+}
+
+func (h *WebhookBackend) sendOnce(ctx context.Context, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.cfg.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.cfg.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(h.cfg.Secret, ts, body))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of the timestamp and the body, separated by a colon.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte(":"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,161 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestWebhookBackend(t *testing.T) {
+	transitions := singleFromNormal(&state.State{
+		State:              eval.Alerting,
+		Labels:             data.Labels{"host": "a"},
+		LastEvaluationTime: time.Unix(10, 0),
+	})
+	createBackendWithConfig := func(t *testing.T, handler http.HandlerFunc, cfg WebhookConfig) *WebhookBackend {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		u, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		cfg.URL = u
+		cfg.Backoff = backoff.Config{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxRetries: 3}
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+		return NewWebhookBackend(cfg, http.DefaultClient, map[string]string{"cluster": "prod"}, log.NewNopLogger(), met)
+	}
+	createBackend := func(t *testing.T, handler http.HandlerFunc, secret string) *WebhookBackend {
+		return createBackendWithConfig(t, handler, WebhookConfig{Secret: secret, BatchSize: 1000})
+	}
+
+	t.Run("sends signed transitions in the loki entry format", func(t *testing.T) {
+		var payload WebhookPayload
+		h := createBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, SignWebhookPayload("secret", r.Header.Get(WebhookTimestampHeader), body), r.Header.Get(WebhookSignatureHeader))
+			require.NoError(t, json.Unmarshal(body, &payload))
+			w.WriteHeader(http.StatusNoContent)
+		}, "secret")
+
+		err := <-h.Record(context.Background(), createTestRule(), transitions)
+		require.NoError(t, err)
+
+		require.Len(t, payload.Transitions, 1)
+		record := payload.Transitions[0]
+		require.Equal(t, time.Unix(10, 0).UnixNano(), record.Timestamp)
+		require.Equal(t, "prod", record.Labels["cluster"])
+		require.Equal(t, StateHistoryLabelValue, record.Labels[StateHistoryLabelKey])
+		var entry LokiEntry
+		require.NoError(t, json.Unmarshal(record.Entry, &entry))
+		require.Equal(t, "Alerting", entry.Current)
+		require.Equal(t, map[string]string{"host": "a"}, entry.InstanceLabels)
+	})
+
+	t.Run("buffers transitions across calls until the flush interval", func(t *testing.T) {
+		var requests atomic.Int32
+		var sent atomic.Int32
+		h := createBackendWithConfig(t, func(w http.ResponseWriter, r *http.Request) {
+			var payload WebhookPayload
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			requests.Add(1)
+			sent.Add(int32(len(payload.Transitions)))
+			w.WriteHeader(http.StatusNoContent)
+		}, WebhookConfig{BatchSize: 1000, FlushInterval: 50 * time.Millisecond})
+
+		first := h.Record(context.Background(), createTestRule(), transitions)
+		second := h.Record(context.Background(), createTestRule(), transitions)
+		require.NoError(t, <-first)
+		require.NoError(t, <-second)
+		require.Equal(t, int32(1), requests.Load())
+		require.Equal(t, int32(2), sent.Load())
+	})
+
+	t.Run("sends a full batch before the flush interval", func(t *testing.T) {
+		var requests atomic.Int32
+		h := createBackendWithConfig(t, func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}, WebhookConfig{BatchSize: 2, FlushInterval: time.Hour})
+
+		first := h.Record(context.Background(), createTestRule(), transitions)
+		second := h.Record(context.Background(), createTestRule(), transitions)
+		require.NoError(t, <-first)
+		require.NoError(t, <-second)
+		require.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		h := createBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}, "")
+
+		err := <-h.Record(context.Background(), createTestRule(), transitions)
+		require.NoError(t, err)
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("fails after the maximum number of retries", func(t *testing.T) {
+		var calls atomic.Int32
+		h := createBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}, "")
+
+		err := <-h.Record(context.Background(), createTestRule(), transitions)
+		require.ErrorContains(t, err, "500")
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		h := createBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Empty(t, r.Header.Get(WebhookSignatureHeader))
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}, "")
+
+		err := <-h.Record(context.Background(), createTestRule(), transitions)
+		require.ErrorContains(t, err, "400")
+		require.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestNewWebhookConfig(t *testing.T) {
+	_, err := NewWebhookConfig(setting.UnifiedAlertingStateHistorySettings{})
+	require.ErrorContains(t, err, "URL must not be empty")
+
+	_, err = NewWebhookConfig(setting.UnifiedAlertingStateHistorySettings{WebhookURL: "http://localhost/history"})
+	require.ErrorContains(t, err, "batch size must be positive")
+
+	cfg, err := NewWebhookConfig(setting.UnifiedAlertingStateHistorySettings{
+		WebhookURL:           "http://localhost/history",
+		WebhookMaxRetries:    2,
+		WebhookBatchSize:     100,
+		WebhookFlushInterval: time.Second,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, cfg.Backoff.MaxRetries)
+	require.Equal(t, 100, cfg.BatchSize)
+	require.Equal(t, time.Second, cfg.FlushInterval)
+}
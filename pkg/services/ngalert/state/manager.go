@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strings"
 	"time"
@@ -23,7 +24,9 @@ var (
 	ResendDelay = 30 * time.Second
 )
 
-type takeImageFn func(reason string) *ngModels.Image
+// takeImageFn takes an image for the alert instance with the given labels. The labels are nil if the alert
+// instance has no result in the evaluation.
+type takeImageFn func(reason string, instance data.Labels) *ngModels.Image
 
 // AlertInstanceManager defines the interface for querying the current alert instances.
 type AlertInstanceManager interface {
@@ -62,15 +65,20 @@ type Manager struct {
 
 	// readiness gates whether evaluation results may be applied before the cache is warmed.
 	readiness ReadinessProbe
+
+	acknowledgementStore AcknowledgementStore
+	acknowledgements     *acknowledgements
 }
 
 type ManagerCfg struct {
 	Metrics       *metrics.State
 	ExternalURL   *url.URL
 	InstanceStore InstanceStore
-	Images        ImageCapturer
-	Clock         clock.Clock
-	Historian     Historian
+	// AcknowledgementStore is optional. Without it, alert instances cannot be acknowledged.
+	AcknowledgementStore AcknowledgementStore
+	Images               ImageCapturer
+	Clock                clock.Clock
+	Historian            Historian
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
 	// StatePeriodicSaveBatchSize controls the size of the alert instance batch that is saved periodically when the
@@ -133,6 +141,9 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		ignorePendingForNoDataAndError: cfg.IgnorePendingForNoDataAndError,
 
 		readiness: readiness,
+
+		acknowledgementStore: cfg.AcknowledgementStore,
+		acknowledgements:     newAcknowledgements(),
 	}
 
 	return m
@@ -149,6 +160,9 @@ func (st *Manager) ClearCache() {
 }
 
 func (st *Manager) Run(ctx context.Context) error {
+	if st.acknowledgementStore != nil {
+		go st.syncAcknowledgements(ctx)
+	}
 	st.persister.Async(ctx, st.cache)
 	return nil
 }
@@ -250,6 +264,8 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 			statesCount++
 		}
 	}
+
+	st.loadAcknowledgements(ctx)
 }
 
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
@@ -336,6 +352,61 @@ func (st *Manager) ResetStateByRuleUID(ctx context.Context, rule *ngModels.Alert
 	return transitions
 }
 
+// HandOverStateByRuleUID removes the rule instances from cache and saves them to instanceStore, so that another
+// Grafana instance that takes over the evaluation of the rule continues from the same state.
+func (st *Manager) HandOverStateByRuleUID(ctx context.Context, ruleKey ngModels.AlertRuleKeyWithGroup) []*State {
+	logger := st.log.FromContext(ctx)
+	logger.Debug("Handing over rule state")
+
+	states := st.ForgetStateByRuleUID(ctx, ruleKey)
+	if st.instanceStore == nil {
+		return states
+	}
+	instances := make([]ngModels.AlertInstance, 0, len(states))
+	for _, s := range states {
+		instance, err := stateToAlertInstance(s)
+		if err != nil {
+			logger.Error("Failed to create a key for alert state to save it. The state will be ignored", "cacheID", s.CacheID, "error", err)
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	if err := st.instanceStore.SaveAlertInstancesForRule(ctx, ruleKey, instances); err != nil {
+		logger.Error("Failed to save rule state to hand it over", "error", err)
+	}
+	return states
+}
+
+// TakeOverStateByRuleUID replaces the rule instances in cache with the ones in instanceStore, which were saved by
+// the Grafana instance that evaluated the rule before.
+func (st *Manager) TakeOverStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) error {
+	logger := st.log.FromContext(ctx)
+	if st.instanceStore == nil {
+		return nil
+	}
+	instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch rule state: %w", err)
+	}
+
+	st.cache.removeByRuleUID(rule.OrgID, rule.UID)
+	for _, entry := range instances {
+		state := AlertInstanceToState(entry, logger)
+		if len(state.Annotations) == 0 {
+			state.Annotations = rule.Annotations
+		}
+		if state.Annotations == nil {
+			state.Annotations = make(map[string]string)
+		}
+		st.cache.set(state)
+	}
+	logger.Debug("Took over rule state", "states", len(instances))
+	return nil
+}
+
 // ProcessEvalResults updates the current states that belong to a rule with the evaluation results.
 // if extraLabels is not empty, those labels will be added to every state. The extraLabels take precedence over rule labels and result labels
 // This will update the states in cache/store and return the state transitions that need to be sent to the alertmanager.
@@ -369,27 +440,31 @@ func (st *Manager) ProcessEvalResults(
 		attribute.Int("results", len(results))))
 	defer span.End()
 
-	// lazy evaluation of takeImage only once and only if it is requested.
+	// lazy evaluation of takeImage only if it is requested, once per evaluation or once per alert instance
+	// if the image capturer takes a different image for each alert instance.
 	var fn takeImageFn
 	{
-		var image *ngModels.Image
-		var imageTaken bool
-		fn = func(reason string) *ngModels.Image {
-			if imageTaken {
+		_, perInstance := st.images.(InstanceImageCapturer)
+		images := make(map[data.Fingerprint]*ngModels.Image)
+		fn = func(reason string, instance data.Labels) *ngModels.Image {
+			if !perInstance {
+				instance = nil
+			}
+			key := instance.Fingerprint()
+			if image, ok := images[key]; ok {
 				return image
 			}
 			logger.Debug("Taking image", "dashboard", alertRule.GetDashboardUID(), "panel", alertRule.GetPanelID(), "reason", reason)
-			img, err := takeImage(ctx, st.images, alertRule)
-			imageTaken = true
+			img, err := takeImage(ctx, st.images, alertRule, results, instance)
 			if err != nil {
 				logger.Warn("Failed to take an image",
 					"dashboard", alertRule.GetDashboardUID(),
 					"panel", alertRule.GetPanelID(), "reason", reason,
 					"error", err)
-				return nil
+				img = nil
 			}
-			image = img
-			return image
+			images[key] = img
+			return img
 		}
 	}
 
@@ -403,6 +478,7 @@ func (st *Manager) ProcessEvalResults(
 	))
 
 	allChanges := StateTransitions(append(states, missingSeriesStates...))
+	st.applyAcknowledgements(ctx, logger, alertRule.GetKey(), allChanges, evaluatedAt)
 
 	// It's important that this is done *before* we sync the states to the persister. Otherwise, we will not persist
 	// the LastSentAt field to the store.
@@ -430,7 +506,12 @@ func (st *Manager) ProcessEvalResults(
 func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Time) StateTransitions {
 	var result StateTransitions
 	for _, t := range states {
-		if t.NeedsSending(evaluatedAt, st.ResendDelay, st.ResolvedRetention) {
+		// an acknowledged alert instance is sent once with the annotations of the acknowledgement, and not re-sent
+		if t.acknowledgementSent(evaluatedAt) {
+			continue
+		}
+		// an alert instance whose severity level changed is sent right away, so that the alert of the previous level is resolved
+		if t.NeedsSending(evaluatedAt, st.ResendDelay, st.ResolvedRetention) || t.SeverityChanged() {
 			t.LastSentAt = &evaluatedAt
 			result = append(result, t)
 		}
@@ -579,7 +660,7 @@ func (st *Manager) processMissingSeriesStates(logger log.Logger, evaluatedAt tim
 			// By setting 'ResolvedAt' we trigger the scheduler to send a 'resolved' alert to the Alertmanager.
 			if s.ShouldBeResolved(oldState) {
 				s.ResolvedAt = &evaluatedAt
-				s.Image = takeImageFn("stale state") // Potentially nil
+				s.Image = takeImageFn("stale state", nil) // Potentially nil
 			}
 
 			staleStates[s.CacheID] = struct{}{}
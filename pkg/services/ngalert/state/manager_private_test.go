@@ -5587,6 +5587,46 @@ func TestProcessEvalResults_Screenshots(t *testing.T) {
 	}
 }
 
+// instanceImageService returns an image for each alert instance, with the fingerprint of the labels of the
+// alert instance as token.
+type instanceImageService struct {
+	NoopImageService
+	called int
+}
+
+func (s *instanceImageService) NewInstanceImage(_ context.Context, _ *ngmodels.AlertRule, _ eval.Results, instance data.Labels) (*ngmodels.Image, error) {
+	s.called++
+	return &ngmodels.Image{Token: instance.Fingerprint().String()}, nil
+}
+
+func TestProcessEvalResults_InstanceImages(t *testing.T) {
+	rule := ngmodels.RuleGen.With(ngmodels.RuleGen.WithLabels(nil), ngmodels.RuleGen.WithFor(0)).GenerateRef()
+	evaluatedAt := time.Now()
+	images := &instanceImageService{}
+	mgr := NewManager(ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &FakeInstanceStore{},
+		Images:        images,
+		Clock:         clock.NewMock(),
+		Historian:     &FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           &logtest.Fake{},
+	}, NewNoopPersister())
+
+	results := eval.Results{
+		{State: eval.Alerting, Instance: data.Labels{"instance_label": "test-1"}, EvaluatedAt: evaluatedAt},
+		{State: eval.Alerting, Instance: data.Labels{"instance_label": "test-2"}, EvaluatedAt: evaluatedAt},
+	}
+	transitions, _ := mgr.ProcessEvalResults(context.Background(), evaluatedAt, rule, results, nil, nil)
+
+	require.Len(t, transitions, 2)
+	assert.Equal(t, 2, images.called)
+	for _, transition := range transitions {
+		require.NotNil(t, transition.Image)
+		assert.Equal(t, transition.ResultFingerprint.String(), transition.Image.Token, "each alert instance should have its own image")
+	}
+}
+
 func setCacheID(s *State) *State {
 	if s.CacheID != 0 {
 		return s
@@ -4,6 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
 )
@@ -36,6 +39,13 @@ type InstanceWriter interface {
 	FullSync(ctx context.Context, instances []models.AlertInstance, batchSize int, jitterFunc func(int) time.Duration) error
 }
 
+// AcknowledgementStore represents the ability to fetch and write the acknowledgements of alert instances.
+type AcknowledgementStore interface {
+	ListAlertAcknowledgements(ctx context.Context, orgID int64) ([]models.AlertAcknowledgement, error)
+	SaveAlertAcknowledgement(ctx context.Context, a models.AlertAcknowledgement) error
+	DeleteAlertAcknowledgement(ctx context.Context, key models.AlertInstanceKey) error
+}
+
 type OrgReader interface {
 	FetchOrgIds(ctx context.Context) ([]int64, error)
 }
@@ -57,5 +67,12 @@ type Historian interface {
 //
 //go:generate mockgen -destination=image_mock.go -package=state github.com/grafana/grafana/pkg/services/ngalert/state ImageCapturer
 type ImageCapturer interface {
-	NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error)
+	NewImage(ctx context.Context, r *models.AlertRule, results eval.Results) (*models.Image, error)
+}
+
+// InstanceImageCapturer is an ImageCapturer that captures a different image for each alert instance. The state
+// manager requests an image for each alert instance from it, instead of one image per evaluation of the alert rule.
+type InstanceImageCapturer interface {
+	ImageCapturer
+	NewInstanceImage(ctx context.Context, r *models.AlertRule, results eval.Results, instance data.Labels) (*models.Image, error)
 }
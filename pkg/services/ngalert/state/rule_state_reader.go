@@ -0,0 +1,69 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// errRuleStatesNotReady is returned when the state of rules is read before the
+// alerting service has started.
+var errRuleStatesNotReady = errors.New("the state of alert rules is not available yet")
+
+// RuleStateReader reads the state of the alert instances of alert rules for the rule state expressions.
+type RuleStateReader struct {
+	source atomic.Pointer[ruleStateSource]
+}
+
+type ruleStateSource struct {
+	states AlertInstanceManager
+	rules  RuleReader
+}
+
+func NewRuleStateReader(states AlertInstanceManager, rules RuleReader) *RuleStateReader {
+	r := &RuleStateReader{}
+	r.Init(states, rules)
+	return r
+}
+
+// ProvideRuleStateReader creates a reader that is passed to the expression service
+// before the state manager exists. It returns an error until Init is called.
+func ProvideRuleStateReader() *RuleStateReader {
+	return &RuleStateReader{}
+}
+
+// Init sets where the states and the rules are read from. It is safe to call while
+// the reader is in use.
+func (r *RuleStateReader) Init(states AlertInstanceManager, rules RuleReader) {
+	r.source.Store(&ruleStateSource{states: states, rules: rules})
+}
+
+func (r *RuleStateReader) GetRuleInstanceStates(ctx context.Context, orgID int64, ruleUID string) ([]expr.RuleInstanceState, error) {
+	src := r.source.Load()
+	if src == nil {
+		return nil, errRuleStatesNotReady
+	}
+	states := src.states.GetStatesForRuleUID(ctx, orgID, ruleUID)
+	if len(states) == 0 {
+		// A rule without states has either not been evaluated yet or does not exist.
+		rules, err := src.rules.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, RuleUIDs: []string{ruleUID}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alert rule: %w", err)
+		}
+		if len(rules) == 0 {
+			return nil, expr.ErrRuleStateRuleNotFound
+		}
+	}
+	result := make([]expr.RuleInstanceState, 0, len(states))
+	for _, s := range states {
+		result = append(result, expr.RuleInstanceState{
+			Labels: s.Labels,
+			State:  s.State.String(),
+		})
+	}
+	return result, nil
+}
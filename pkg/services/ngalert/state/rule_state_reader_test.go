@@ -0,0 +1,73 @@
+package state
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeAlertInstanceManager map[string][]*State
+
+func (f fakeAlertInstanceManager) GetAll(_ context.Context, _ int64) []*State {
+	return nil
+}
+
+func (f fakeAlertInstanceManager) GetStatesForRuleUID(_ context.Context, _ int64, alertRuleUID string) []*State {
+	return f[alertRuleUID]
+}
+
+type fakeRuleReader []string
+
+func (f fakeRuleReader) ListAlertRules(_ context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error) {
+	var result models.RulesGroup
+	for _, uid := range query.RuleUIDs {
+		if slices.Contains(f, uid) {
+			result = append(result, &models.AlertRule{OrgID: query.OrgID, UID: uid})
+		}
+	}
+	return result, nil
+}
+
+func TestRuleStateReader(t *testing.T) {
+	reader := NewRuleStateReader(fakeAlertInstanceManager{
+		"evaluated": {{Labels: data.Labels{"db": "a"}, State: eval.Alerting}},
+	}, fakeRuleReader{"evaluated", "new"})
+
+	t.Run("returns the states of the instances", func(t *testing.T) {
+		states, err := reader.GetRuleInstanceStates(context.Background(), 1, "evaluated")
+		require.NoError(t, err)
+		require.Equal(t, []expr.RuleInstanceState{{Labels: data.Labels{"db": "a"}, State: "Alerting"}}, states)
+	})
+
+	t.Run("returns no states for a rule that has not been evaluated", func(t *testing.T) {
+		states, err := reader.GetRuleInstanceStates(context.Background(), 1, "new")
+		require.NoError(t, err)
+		require.Empty(t, states)
+	})
+
+	t.Run("fails for a rule that does not exist", func(t *testing.T) {
+		_, err := reader.GetRuleInstanceStates(context.Background(), 1, "deleted")
+		require.ErrorIs(t, err, expr.ErrRuleStateRuleNotFound)
+	})
+}
+
+func TestProvideRuleStateReader(t *testing.T) {
+	reader := ProvideRuleStateReader()
+
+	_, err := reader.GetRuleInstanceStates(context.Background(), 1, "evaluated")
+	require.ErrorIs(t, err, errRuleStatesNotReady)
+
+	reader.Init(fakeAlertInstanceManager{
+		"evaluated": {{Labels: data.Labels{"db": "a"}, State: eval.Alerting}},
+	}, fakeRuleReader{"evaluated"})
+	states, err := reader.GetRuleInstanceStates(context.Background(), 1, "evaluated")
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+}
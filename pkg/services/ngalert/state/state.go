@@ -7,6 +7,7 @@ import (
 	"maps"
 	"math"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -78,6 +79,18 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// Severity is the severity level of the state, SeverityLabel the name of the label it is sent
+	// with, and RecoveryStreak the number of consecutive evaluations the recovery threshold of the
+	// level has been met, if the condition is a threshold with severity levels. They are saved in
+	// the annotations, see setSeverity.
+	Severity       string
+	SeverityLabel  string
+	RecoveryStreak int
+
+	// Acknowledgement is the active acknowledgement of the alert instance, if any. It is not copied by Copy,
+	// it is set again by the state manager on every evaluation.
+	Acknowledgement *models.AlertAcknowledgement
 }
 
 func newState(ctx context.Context, log log.Logger, alertRule *models.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
@@ -138,6 +151,9 @@ func (a *State) Copy() *State {
 		LastEvaluationString: a.LastEvaluationString,
 		LastEvaluationTime:   a.LastEvaluationTime,
 		EvaluationDuration:   a.EvaluationDuration,
+		Severity:             a.Severity,
+		SeverityLabel:        a.SeverityLabel,
+		RecoveryStreak:       a.RecoveryStreak,
 	}
 }
 
@@ -289,6 +305,8 @@ type StateTransition struct {
 	*State
 	PreviousState       eval.State
 	PreviousStateReason string
+	// PreviousSeverity is the severity level of the state before the transition, see State.Severity.
+	PreviousSeverity string
 }
 
 func (c StateTransition) Formatted() string {
@@ -299,6 +317,17 @@ func (c StateTransition) PreviousFormatted() string {
 	return FormatStateAndReason(c.PreviousState, c.PreviousStateReason)
 }
 
+// SeverityChanged returns true if the severity level of an alert instance changed while it kept firing.
+// The alert sent with the previous level has different labels, so it must be resolved, see
+// PreviousSeverityToStoppedAlert.
+func (c StateTransition) SeverityChanged() bool {
+	return c.PreviousSeverity != c.Severity && isFiring(c.PreviousState) && isFiring(c.State.State)
+}
+
+func isFiring(s eval.State) bool {
+	return s == eval.Alerting || s == eval.Recovering
+}
+
 func (c StateTransition) Changed() bool {
 	return c.PreviousState != c.State.State || c.PreviousStateReason != c.StateReason
 }
@@ -725,14 +754,22 @@ func shouldTakeImage(state, previousState eval.State, previousImage *models.Imag
 	return ""
 }
 
-// takeImage takes an image for the alert rule. It returns nil if screenshots are disabled or
-// the rule is not associated with a dashboard panel.
-func takeImage(ctx context.Context, s ImageCapturer, r *models.AlertRule) (*models.Image, error) {
-	img, err := s.NewImage(ctx, r)
+// takeImage takes an image for the alert rule, or for the alert instance with the given labels if the image
+// capturer takes a different image for each alert instance. It returns nil if screenshots are disabled,
+// the rule is not associated with a dashboard panel, or the results have no data to draw.
+func takeImage(ctx context.Context, s ImageCapturer, r *models.AlertRule, results eval.Results, instance data.Labels) (*models.Image, error) {
+	var img *models.Image
+	var err error
+	if ic, ok := s.(InstanceImageCapturer); ok {
+		img, err = ic.NewInstanceImage(ctx, r, results, instance)
+	} else {
+		img, err = s.NewImage(ctx, r, results)
+	}
 	if err != nil {
 		if errors.Is(err, screenshot.ErrScreenshotsUnavailable) ||
 			errors.Is(err, models.ErrNoDashboard) ||
-			errors.Is(err, models.ErrNoPanel) {
+			errors.Is(err, models.ErrNoPanel) ||
+			errors.Is(err, models.ErrNoImageData) {
 			return nil, nil
 		}
 		return nil, err
@@ -826,6 +863,27 @@ func patch(newState, existingState *State, result eval.Result) {
 	}
 }
 
+// setSeverity sets the severity level of the state from the result. The level is sent to the Alertmanager
+// as a label, see StateToPostableAlert, and the level, the name of the label and the recovery streak are kept
+// in internal annotations so that they are restored with the state. Results without severity, such as errors,
+// clear the severity level. The name of the label is kept so that the alert of the previous level can be resolved.
+func (a *State) setSeverity(severity *expr.SeverityThresholdResult) {
+	a.Severity, a.RecoveryStreak = "", 0
+	delete(a.Annotations, models.SeverityStateAnnotation)
+	delete(a.Annotations, models.SeverityLabelStateAnnotation)
+	delete(a.Annotations, models.RecoveryStreakStateAnnotation)
+	if severity == nil || severity.Severity == "" {
+		return
+	}
+	a.Severity, a.SeverityLabel, a.RecoveryStreak = severity.Severity, severity.Label, severity.RecoveryStreak
+	if a.Annotations == nil {
+		a.Annotations = make(map[string]string, 3)
+	}
+	a.Annotations[models.SeverityStateAnnotation] = severity.Severity
+	a.Annotations[models.SeverityLabelStateAnnotation] = severity.Label
+	a.Annotations[models.RecoveryStreakStateAnnotation] = strconv.Itoa(severity.RecoveryStreak)
+}
+
 func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extraAnnotations data.Labels, logger log.Logger, takeImageFn takeImageFn, ignorePendingForNoDataAndError bool) StateTransition {
 	a.LastEvaluationTime = result.EvaluatedAt
 	a.EvaluationDuration = result.EvaluationDuration
@@ -837,6 +895,8 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 		Condition:       alertRule.Condition,
 	}
 	a.LastEvaluationString = result.EvaluationString
+	oldSeverity := a.Severity
+	a.setSeverity(result.Severity)
 	oldState := a.State
 	oldReason := a.StateReason
 
@@ -881,7 +941,7 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 	}
 
 	if reason := shouldTakeImage(a.State, oldState, a.Image, newlyResolved); reason != "" {
-		image := takeImageFn(reason)
+		image := takeImageFn(reason, result.Instance)
 		if image != nil {
 			a.Image = image
 		}
@@ -895,6 +955,7 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 		State:               a,
 		PreviousState:       oldState,
 		PreviousStateReason: oldReason,
+		PreviousSeverity:    oldSeverity,
 	}
 	return nextState
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/grafana/alerting/models"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -522,7 +523,7 @@ func TestNeedsSending(t *testing.T) {
 func TestTransitionSetsResolvedAt(t *testing.T) {
 	evaluatedAt := time.Now()
 	logger := log.NewNopLogger()
-	noImage := func(string) *ngmodels.Image { return nil }
+	noImage := func(string, data.Labels) *ngmodels.Image { return nil }
 
 	baseRule := &ngmodels.AlertRule{
 		IntervalSeconds: 60,
@@ -601,6 +602,52 @@ func TestTransitionSetsResolvedAt(t *testing.T) {
 	}
 }
 
+func TestTransitionSetsSeverity(t *testing.T) {
+	evaluatedAt := time.Now()
+	rule := &ngmodels.AlertRule{IntervalSeconds: 60, ExecErrState: ngmodels.ErrorErrState, NoDataState: ngmodels.NoData}
+	noImage := func(string, data.Labels) *ngmodels.Image { return nil }
+
+	state := &State{State: eval.Alerting, Annotations: map[string]string{"summary": "high"}}
+	state.transition(rule, eval.Result{
+		State:       eval.Alerting,
+		EvaluatedAt: evaluatedAt,
+		Severity:    &expr.SeverityThresholdResult{Severity: "critical", RecoveryStreak: 2, Label: "level"},
+	}, nil, log.NewNopLogger(), noImage, false)
+
+	require.Equal(t, "critical", state.Severity)
+	require.Equal(t, "level", state.SeverityLabel)
+	require.Equal(t, 2, state.RecoveryStreak)
+	require.Empty(t, state.Labels)
+	require.Equal(t, map[string]string{
+		"summary":                              "high",
+		ngmodels.SeverityStateAnnotation:       "critical",
+		ngmodels.SeverityLabelStateAnnotation:  "level",
+		ngmodels.RecoveryStreakStateAnnotation: "2",
+	}, state.Annotations)
+
+	// a change of level while firing is reported with the previous level
+	transition := state.transition(rule, eval.Result{
+		State:       eval.Alerting,
+		EvaluatedAt: evaluatedAt.Add(time.Minute),
+		Severity:    &expr.SeverityThresholdResult{Severity: "warning", Label: "level"},
+	}, nil, log.NewNopLogger(), noImage, false)
+	require.Equal(t, "critical", transition.PreviousSeverity)
+	require.True(t, transition.SeverityChanged())
+
+	// results without a severity level clear it
+	transition = state.transition(rule, eval.Result{State: eval.Normal, EvaluatedAt: evaluatedAt.Add(2 * time.Minute)}, nil, log.NewNopLogger(), noImage, false)
+	require.Equal(t, "warning", transition.PreviousSeverity)
+	require.Empty(t, state.Severity)
+	require.Zero(t, state.RecoveryStreak)
+	require.NotContains(t, state.Annotations, ngmodels.SeverityStateAnnotation)
+	require.NotContains(t, state.Annotations, ngmodels.SeverityLabelStateAnnotation)
+	require.NotContains(t, state.Annotations, ngmodels.RecoveryStreakStateAnnotation)
+
+	// the label of the cleared level is resolved
+	alert := StateToPostableAlert(transition, nil)
+	require.Equal(t, "warning", alert.Labels["level"])
+}
+
 func TestGetLastEvaluationValuesForCondition(t *testing.T) {
 	genState := func(latestResult *Evaluation) *State {
 		return &State{
@@ -719,8 +766,8 @@ func TestTakeImage(t *testing.T) {
 		r := ngmodels.AlertRule{}
 		s := NewMockImageCapturer(ctrl)
 
-		s.EXPECT().NewImage(ctx, &r).Return(nil, ngmodels.ErrNoDashboard)
-		image, err := takeImage(ctx, s, &r)
+		s.EXPECT().NewImage(ctx, &r, nil).Return(nil, ngmodels.ErrNoDashboard)
+		image, err := takeImage(ctx, s, &r, nil, nil)
 		assert.NoError(t, err)
 		assert.Nil(t, image)
 	})
@@ -733,8 +780,8 @@ func TestTakeImage(t *testing.T) {
 		r := ngmodels.AlertRule{DashboardUID: new("foo")}
 		s := NewMockImageCapturer(ctrl)
 
-		s.EXPECT().NewImage(ctx, &r).Return(nil, ngmodels.ErrNoPanel)
-		image, err := takeImage(ctx, s, &r)
+		s.EXPECT().NewImage(ctx, &r, nil).Return(nil, ngmodels.ErrNoPanel)
+		image, err := takeImage(ctx, s, &r, nil, nil)
 		assert.NoError(t, err)
 		assert.Nil(t, image)
 	})
@@ -747,8 +794,23 @@ func TestTakeImage(t *testing.T) {
 		r := ngmodels.AlertRule{DashboardUID: new("foo"), PanelID: new(int64(1))}
 		s := NewMockImageCapturer(ctrl)
 
-		s.EXPECT().NewImage(ctx, &r).Return(nil, screenshot.ErrScreenshotsUnavailable)
-		image, err := takeImage(ctx, s, &r)
+		s.EXPECT().NewImage(ctx, &r, nil).Return(nil, screenshot.ErrScreenshotsUnavailable)
+		image, err := takeImage(ctx, s, &r, nil, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, image)
+	})
+
+	t.Run("ErrNoImageData should return nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		r := ngmodels.AlertRule{}
+		results := eval.Results{{State: eval.Alerting}}
+		s := NewMockImageCapturer(ctrl)
+
+		s.EXPECT().NewImage(ctx, &r, results).Return(nil, ngmodels.ErrNoImageData)
+		image, err := takeImage(ctx, s, &r, results, nil)
 		assert.NoError(t, err)
 		assert.Nil(t, image)
 	})
@@ -761,8 +823,8 @@ func TestTakeImage(t *testing.T) {
 		r := ngmodels.AlertRule{DashboardUID: new("foo"), PanelID: new(int64(1))}
 		s := NewMockImageCapturer(ctrl)
 
-		s.EXPECT().NewImage(ctx, &r).Return(nil, errors.New("unknown error"))
-		image, err := takeImage(ctx, s, &r)
+		s.EXPECT().NewImage(ctx, &r, nil).Return(nil, errors.New("unknown error"))
+		image, err := takeImage(ctx, s, &r, nil, nil)
 		assert.EqualError(t, err, "unknown error")
 		assert.Nil(t, image)
 	})
@@ -775,8 +837,8 @@ func TestTakeImage(t *testing.T) {
 		r := ngmodels.AlertRule{DashboardUID: new("foo"), PanelID: new(int64(1))}
 		s := NewMockImageCapturer(ctrl)
 
-		s.EXPECT().NewImage(ctx, &r).Return(&ngmodels.Image{Path: "foo.png"}, nil)
-		image, err := takeImage(ctx, s, &r)
+		s.EXPECT().NewImage(ctx, &r, nil).Return(&ngmodels.Image{Path: "foo.png"}, nil)
+		image, err := takeImage(ctx, s, &r, nil, nil)
 		assert.NoError(t, err)
 		require.NotNil(t, image)
 		assert.Equal(t, ngmodels.Image{Path: "foo.png"}, *image)
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
 	"github.com/grafana/grafana/pkg/services/screenshot"
@@ -106,14 +107,14 @@ func (f *FakeHistorian) Record(ctx context.Context, rule history_model.RuleMeta,
 // NotAvailableImageService is a service that returns ErrScreenshotsUnavailable.
 type NotAvailableImageService struct{}
 
-func (s *NotAvailableImageService) NewImage(_ context.Context, _ *models.AlertRule) (*models.Image, error) {
+func (s *NotAvailableImageService) NewImage(_ context.Context, _ *models.AlertRule, _ eval.Results) (*models.Image, error) {
 	return nil, screenshot.ErrScreenshotsUnavailable
 }
 
 // NoopImageService is a no-op image service.
 type NoopImageService struct{}
 
-func (s *NoopImageService) NewImage(_ context.Context, _ *models.AlertRule) (*models.Image, error) {
+func (s *NoopImageService) NewImage(_ context.Context, _ *models.AlertRule, _ eval.Results) (*models.Image, error) {
 	return &models.Image{}, nil
 }
 
@@ -127,7 +128,7 @@ type CountingImageService struct {
 	Err    error
 }
 
-func (c *CountingImageService) NewImage(_ context.Context, _ *models.AlertRule) (*models.Image, error) {
+func (c *CountingImageService) NewImage(_ context.Context, _ *models.AlertRule, _ eval.Results) (*models.Image, error) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 	c.Called += 1
@@ -0,0 +1,170 @@
+package statearchive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/flushlog/flushlogpb"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+// KVAlertmanagerState reads and writes the state of the Alertmanager in the kvstore, where Grafana persists it. It
+// is meant to be used while Grafana is not running, otherwise the Alertmanager overwrites the merged state the next
+// time it persists its own.
+//
+// The merged entries are deduplicated with the persisted entries by key, and the newest entry of a key is kept, as
+// when the Alertmanager merges the state of its peers.
+type KVAlertmanagerState struct {
+	kv kvstore.KVStore
+}
+
+func NewKVAlertmanagerState(kv kvstore.KVStore) *KVAlertmanagerState {
+	return &KVAlertmanagerState{kv: kv}
+}
+
+func (s *KVAlertmanagerState) GetAlertmanagerState(ctx context.Context, orgID int64) (notifier.ExternalState, error) {
+	fs := notifier.NewFileStore(orgID, s.kv)
+	silences, err := fs.GetSilences(ctx)
+	if err != nil {
+		return notifier.ExternalState{}, err
+	}
+	nflog, err := fs.GetNotificationLog(ctx)
+	if err != nil {
+		return notifier.ExternalState{}, err
+	}
+	flushLog, err := fs.GetFlushLog(ctx)
+	if err != nil {
+		return notifier.ExternalState{}, err
+	}
+	return notifier.ExternalState{
+		Silences: []byte(silences),
+		Nflog:    []byte(nflog),
+		FlushLog: []byte(flushLog),
+	}, nil
+}
+
+func (s *KVAlertmanagerState) MergeAlertmanagerState(ctx context.Context, orgID int64, st notifier.ExternalState) error {
+	current, err := s.GetAlertmanagerState(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	fs := notifier.NewFileStore(orgID, s.kv)
+	if len(st.Silences) > 0 {
+		silences, err := mergeState(current.Silences, st.Silences, silenceKey)
+		if err != nil {
+			return fmt.Errorf("failed to merge silences: %w", err)
+		}
+		if _, err := fs.SaveSilences(ctx, rawState(silences)); err != nil {
+			return err
+		}
+	}
+	if len(st.Nflog) > 0 {
+		nflog, err := mergeState(current.Nflog, st.Nflog, nflogKey)
+		if err != nil {
+			return fmt.Errorf("failed to merge the notification log: %w", err)
+		}
+		if _, err := fs.SaveNotificationLog(ctx, rawState(nflog)); err != nil {
+			return err
+		}
+	}
+	if len(st.FlushLog) > 0 {
+		flushLog, err := mergeState(current.FlushLog, st.FlushLog, flushLogKey)
+		if err != nil {
+			return fmt.Errorf("failed to merge the flush log: %w", err)
+		}
+		if _, err := fs.SaveFlushLog(ctx, rawState(flushLog)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errInvalidEntry = errors.New("invalid entry")
+
+// protoMessage is a pointer to a protobuf message of the state of the Alertmanager.
+type protoMessage[E any] interface {
+	*E
+	Reset()
+	String() string
+	ProtoMessage()
+}
+
+// mergeState merges two states of the Alertmanager, encoded as sequences of length-delimited protobuf messages. key
+// returns the key of an entry and the time it was last updated at. Of the entries with the same key, the one that
+// was updated last is kept, and the one of current when they were updated at the same time.
+func mergeState[E any, PE protoMessage[E]](current, merged []byte, key func(PE) (string, time.Time, error)) ([]byte, error) {
+	entries := make(map[string]PE)
+	updated := make(map[string]time.Time)
+	for _, state := range [][]byte{current, merged} {
+		r := bytes.NewReader(state)
+		for {
+			e := PE(new(E))
+			if _, err := pbutil.ReadDelimited(r, e); err != nil {
+				//nolint:errorlint
+				if err == io.EOF {
+					break
+				}
+				return nil, err
+			}
+			k, ts, err := key(e)
+			if err != nil {
+				return nil, err
+			}
+			if prev, ok := updated[k]; ok && !prev.Before(ts) {
+				continue
+			}
+			entries[k] = e
+			updated[k] = ts
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, k := range slices.Sorted(maps.Keys(entries)) {
+		if _, err := pbutil.WriteDelimited(&buf, entries[k]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func silenceKey(s *silencepb.MeshSilence) (string, time.Time, error) {
+	if s.Silence == nil {
+		return "", time.Time{}, errInvalidEntry
+	}
+	return s.Silence.Id, s.Silence.UpdatedAt, nil
+}
+
+// nflogKey returns the same key as the notification log of the Alertmanager.
+func nflogKey(e *nflogpb.MeshEntry) (string, time.Time, error) {
+	if e.Entry == nil || e.Entry.Receiver == nil {
+		return "", time.Time{}, errInvalidEntry
+	}
+	r := e.Entry.Receiver
+	return fmt.Sprintf("%s:%s/%s/%d", e.Entry.GroupKey, r.GroupName, r.Integration, r.Idx), e.Entry.Timestamp, nil
+}
+
+func flushLogKey(e *flushlogpb.MeshFlushLog) (string, time.Time, error) {
+	if e.FlushLog == nil {
+		return "", time.Time{}, errInvalidEntry
+	}
+	return strconv.FormatUint(e.FlushLog.GroupFingerprint, 10), e.FlushLog.Timestamp, nil
+}
+
+// rawState is a state of the Alertmanager that is already encoded.
+type rawState []byte
+
+func (s rawState) MarshalBinary() ([]byte, error) {
+	return s, nil
+}
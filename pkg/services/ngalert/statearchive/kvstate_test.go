@@ -0,0 +1,95 @@
+package statearchive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+func TestKVAlertmanagerState_MergeAlertmanagerState(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 4, 2, 0, 0, 0, time.UTC)
+	silence := func(id, comment string, updatedAt time.Time) *silencepb.MeshSilence {
+		return &silencepb.MeshSilence{Silence: &silencepb.Silence{Id: id, Comment: comment, UpdatedAt: updatedAt}}
+	}
+	entry := func(groupKey string, sentAt time.Time) *nflogpb.MeshEntry {
+		return &nflogpb.MeshEntry{Entry: &nflogpb.Entry{
+			GroupKey:  []byte(groupKey),
+			Receiver:  &nflogpb.Receiver{GroupName: "receiver", Integration: "email", Idx: 0},
+			Timestamp: sentAt,
+		}}
+	}
+
+	st := NewKVAlertmanagerState(kvstore.NewFakeKVStore())
+	require.NoError(t, st.MergeAlertmanagerState(ctx, 1, notifier.ExternalState{
+		Silences: encodeSilences(t, silence("a", "current", now), silence("b", "current", now)),
+		Nflog:    encodeNflog(t, entry("group-1", now), entry("group-2", now)),
+	}))
+
+	require.NoError(t, st.MergeAlertmanagerState(ctx, 1, notifier.ExternalState{
+		Silences: encodeSilences(t, silence("a", "newer", now.Add(time.Minute)), silence("b", "older", now.Add(-time.Minute)), silence("c", "new", now)),
+		Nflog:    encodeNflog(t, entry("group-1", now.Add(time.Minute)), entry("group-3", now)),
+	}))
+
+	merged, err := st.GetAlertmanagerState(ctx, 1)
+	require.NoError(t, err)
+
+	silences := decodeSilences(t, merged.Silences)
+	require.Len(t, silences, 3, "silences with the same ID should not be duplicated")
+	comments := make(map[string]string, len(silences))
+	for _, s := range silences {
+		comments[s.Silence.Id] = s.Silence.Comment
+	}
+	require.Equal(t, map[string]string{"a": "newer", "b": "current", "c": "new"}, comments)
+
+	entries := decodeNflog(t, merged.Nflog)
+	require.Len(t, entries, 3, "entries with the same key should not be duplicated")
+	sentAt := make(map[string]time.Time, len(entries))
+	for _, e := range entries {
+		sentAt[string(e.Entry.GroupKey)] = e.Entry.Timestamp
+	}
+	require.Equal(t, map[string]time.Time{"group-1": now.Add(time.Minute), "group-2": now, "group-3": now}, sentAt)
+
+	t.Run("should reject invalid entries", func(t *testing.T) {
+		err := st.MergeAlertmanagerState(ctx, 1, notifier.ExternalState{
+			Nflog: encodeNflog(t, &nflogpb.MeshEntry{Entry: &nflogpb.Entry{GroupKey: []byte("group-4")}}),
+		})
+		require.ErrorIs(t, err, errInvalidEntry)
+	})
+}
+
+func encodeNflog(t *testing.T, entries ...*nflogpb.MeshEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, e := range entries {
+		_, err := pbutil.WriteDelimited(&buf, e)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func decodeNflog(t *testing.T, b []byte) []*nflogpb.MeshEntry {
+	t.Helper()
+	var result []*nflogpb.MeshEntry
+	r := bytes.NewReader(b)
+	for {
+		var e nflogpb.MeshEntry
+		_, err := pbutil.ReadDelimited(r, &e)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		require.NoError(t, err)
+		result = append(result, &e)
+	}
+}
@@ -0,0 +1,369 @@
+// Package statearchive exports the state of the alerts of an organization into a versioned archive
+// and imports it into another Grafana installation.
+package statearchive
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Version is the version of the archive format written by Export. Import rejects archives of other versions.
+const Version = 1
+
+var ErrInvalidArchive = errors.New("invalid alert state archive")
+
+// RuleStore provides the alert rules of an organization.
+type RuleStore interface {
+	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
+}
+
+// InstanceStore reads and overwrites the alert instances of the alert rules.
+type InstanceStore interface {
+	state.InstanceReader
+	SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKeyWithGroup, instances []models.AlertInstance) error
+}
+
+// TransactionManager runs the writes of an import in a single transaction.
+type TransactionManager interface {
+	InTransaction(ctx context.Context, work func(ctx context.Context) error) error
+}
+
+// AlertmanagerState reads and merges the silences and the notification log of the Alertmanager of an organization.
+type AlertmanagerState interface {
+	GetAlertmanagerState(ctx context.Context, orgID int64) (notifier.ExternalState, error)
+	MergeAlertmanagerState(ctx context.Context, orgID int64, st notifier.ExternalState) error
+}
+
+// StateLoader reloads the state of an alert rule after it was written to the instance store.
+type StateLoader interface {
+	TakeOverStateByRuleUID(ctx context.Context, rule *models.AlertRule) error
+}
+
+// ImportOptions control how the alert rules in an archive are matched with the alert rules of the organization.
+type ImportOptions struct {
+	// RuleUIDs maps the UIDs of alert rules in the archive to the UIDs of alert rules in the organization.
+	RuleUIDs map[string]string
+	// FolderUIDs maps the UIDs of folders in the archive to the UIDs of folders in the organization.
+	FolderUIDs map[string]string
+}
+
+type Service struct {
+	rules        RuleStore
+	instances    InstanceStore
+	xact         TransactionManager
+	alertmanager AlertmanagerState
+	loader       StateLoader
+	log          log.Logger
+}
+
+// NewService creates the service. The loader is optional, it is nil when the state is imported while Grafana is
+// not running.
+func NewService(rules RuleStore, instances InstanceStore, xact TransactionManager, alertmanager AlertmanagerState, loader StateLoader, logger log.Logger) *Service {
+	return &Service{
+		rules:        rules,
+		instances:    instances,
+		xact:         xact,
+		alertmanager: alertmanager,
+		loader:       loader,
+		log:          logger,
+	}
+}
+
+// Export returns the archive of the alert instances of the organization, and of the silences and the notification
+// log of its Alertmanager. Instances of alert rules that do not exist anymore are not exported.
+func (s *Service) Export(ctx context.Context, orgID int64) (*definitions.AlertStateArchive, error) {
+	instances, err := s.instances.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert instances: %w", err)
+	}
+
+	archive := &definitions.AlertStateArchive{
+		Version:   Version,
+		OrgID:     orgID,
+		CreatedAt: time.Now().UTC(),
+		Rules:     []definitions.AlertStateArchiveRule{},
+		Instances: make([]definitions.AlertStateArchiveInstance, 0, len(instances)),
+	}
+
+	ruleUIDs := make(map[string]struct{})
+	for _, instance := range instances {
+		ruleUIDs[instance.RuleUID] = struct{}{}
+	}
+	if len(ruleUIDs) > 0 {
+		rules, err := s.rules.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:    orgID,
+			RuleUIDs: slices.Sorted(maps.Keys(ruleUIDs)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		clear(ruleUIDs)
+		for _, rule := range rules {
+			ruleUIDs[rule.UID] = struct{}{}
+			archive.Rules = append(archive.Rules, definitions.AlertStateArchiveRule{
+				UID:       rule.UID,
+				Title:     rule.Title,
+				FolderUID: rule.NamespaceUID,
+			})
+		}
+	}
+	slices.SortFunc(archive.Rules, func(a, b definitions.AlertStateArchiveRule) int {
+		return strings.Compare(a.UID, b.UID)
+	})
+
+	slices.SortFunc(instances, func(a, b *models.AlertInstance) int {
+		return cmp.Or(strings.Compare(a.RuleUID, b.RuleUID), strings.Compare(a.LabelsHash, b.LabelsHash))
+	})
+	for _, instance := range instances {
+		if _, ok := ruleUIDs[instance.RuleUID]; !ok {
+			continue
+		}
+		archive.Instances = append(archive.Instances, instanceToArchive(instance))
+	}
+
+	amState, err := s.alertmanager.GetAlertmanagerState(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the state of the Alertmanager: %w", err)
+	}
+	archive.Silences = amState.Silences
+	archive.NotificationLog = amState.Nflog
+	archive.FlushLog = amState.FlushLog
+
+	return archive, nil
+}
+
+// Import replaces the state of the alert rules of the organization with the state in the archive, and merges the
+// silences and the notification log in the archive with the ones of the Alertmanager.
+//
+// An alert rule in the archive is matched, in order, with the alert rule of the UID it is mapped to in the options,
+// the alert rule with the same UID, and the alert rule with the same title in the same folder. The folder is
+// mapped with the options when it is different in the organization. Alert rules that cannot be matched are skipped.
+// The rule and folder UIDs in the labels of the instances and in the matchers of the silences are replaced with the
+// ones of the matched alert rule. The instances and the state of the Alertmanager are written in a single
+// transaction, the state of the alert rules is loaded once it is committed.
+//
+// The notification log and the flush log identify the alerts by hashes of their labels, which cannot be remapped.
+// They are not imported when the labels of an alert rule change, so notifications of the imported alerts are sent
+// again as if they were new.
+func (s *Service) Import(ctx context.Context, orgID int64, archive definitions.AlertStateArchive, opts ImportOptions) (definitions.AlertStateImportResult, error) {
+	result := definitions.AlertStateImportResult{SkippedRules: []string{}}
+	if archive.Version != Version {
+		return result, fmt.Errorf("%w: unsupported version %d, expected %d", ErrInvalidArchive, archive.Version, Version)
+	}
+
+	targets, err := s.matchRules(ctx, orgID, archive.Rules, opts)
+	if err != nil {
+		return result, err
+	}
+
+	// Convert all instances before writing anything so that an invalid archive is not partially imported.
+	byRule := make(map[string][]models.AlertInstance, len(targets))
+	for _, instance := range archive.Instances {
+		target, ok := targets[instance.RuleUID]
+		if !ok {
+			continue
+		}
+		converted, err := instanceFromArchive(instance, target)
+		if err != nil {
+			return result, fmt.Errorf("%w: instance of alert rule %s: %s", ErrInvalidArchive, instance.RuleUID, err)
+		}
+		byRule[instance.RuleUID] = append(byRule[instance.RuleUID], converted)
+	}
+
+	ruleUIDs := make(map[string]string, len(targets))
+	folderUIDs := maps.Clone(opts.FolderUIDs)
+	if folderUIDs == nil {
+		folderUIDs = make(map[string]string, len(targets))
+	}
+	remapped := false
+	for _, rule := range archive.Rules {
+		target, ok := targets[rule.UID]
+		if !ok {
+			result.SkippedRules = append(result.SkippedRules, rule.UID)
+			continue
+		}
+		ruleUIDs[rule.UID] = target.UID
+		folderUIDs[rule.FolderUID] = target.NamespaceUID
+		if target.UID != rule.UID || target.NamespaceUID != rule.FolderUID || target.Title != rule.Title {
+			remapped = true
+		}
+	}
+	silences, err := remapSilences(archive.Silences, ruleUIDs, folderUIDs)
+	if err != nil {
+		return result, fmt.Errorf("%w: silences: %s", ErrInvalidArchive, err)
+	}
+
+	amState := notifier.ExternalState{
+		Silences: silences,
+		Nflog:    archive.NotificationLog,
+		FlushLog: archive.FlushLog,
+	}
+	if remapped && (len(amState.Nflog) > 0 || len(amState.FlushLog) > 0) {
+		amState.Nflog = nil
+		amState.FlushLog = nil
+		result.NotificationLogSkipped = true
+	}
+
+	imported := make([]*models.AlertRule, 0, len(targets))
+	err = s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, rule := range archive.Rules {
+			target, ok := targets[rule.UID]
+			if !ok {
+				continue
+			}
+			instances := byRule[rule.UID]
+			if err := s.instances.SaveAlertInstancesForRule(ctx, target.GetKeyWithGroup(), instances); err != nil {
+				return fmt.Errorf("failed to save the alert instances of rule %s: %w", target.UID, err)
+			}
+			imported = append(imported, target)
+			result.Instances += len(instances)
+		}
+		if len(amState.Silences) > 0 || len(amState.Nflog) > 0 || len(amState.FlushLog) > 0 {
+			if err := s.alertmanager.MergeAlertmanagerState(ctx, orgID, amState); err != nil {
+				return fmt.Errorf("failed to merge the state of the Alertmanager: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Rules = len(imported)
+
+	if s.loader != nil {
+		for _, target := range imported {
+			if err := s.loader.TakeOverStateByRuleUID(ctx, target); err != nil {
+				return result, fmt.Errorf("failed to load the state of rule %s: %w", target.UID, err)
+			}
+		}
+	}
+
+	s.log.Info("Imported alert state", "org_id", orgID, "source_org_id", archive.OrgID, "rules", result.Rules, "instances", result.Instances, "skipped_rules", len(result.SkippedRules), "notification_log_skipped", result.NotificationLogSkipped)
+	return result, nil
+}
+
+type folderTitle struct {
+	folderUID string
+	title     string
+}
+
+// matchRules returns the alert rules of the organization by the UID of the alert rule in the archive they match.
+func (s *Service) matchRules(ctx context.Context, orgID int64, archived []definitions.AlertStateArchiveRule, opts ImportOptions) (map[string]*models.AlertRule, error) {
+	result := make(map[string]*models.AlertRule, len(archived))
+	if len(archived) == 0 {
+		return result, nil
+	}
+	rules, err := s.rules.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	byUID := make(map[string]*models.AlertRule, len(rules))
+	byTitle := make(map[folderTitle]*models.AlertRule, len(rules))
+	for _, rule := range rules {
+		byUID[rule.UID] = rule
+		byTitle[folderTitle{folderUID: rule.NamespaceUID, title: rule.Title}] = rule
+	}
+
+	for _, r := range archived {
+		if uid, ok := opts.RuleUIDs[r.UID]; ok {
+			if rule, ok := byUID[uid]; ok {
+				result[r.UID] = rule
+			}
+			continue
+		}
+		if rule, ok := byUID[r.UID]; ok {
+			result[r.UID] = rule
+			continue
+		}
+		folderUID := r.FolderUID
+		if uid, ok := opts.FolderUIDs[folderUID]; ok {
+			folderUID = uid
+		}
+		if rule, ok := byTitle[folderTitle{folderUID: folderUID, title: r.Title}]; ok {
+			result[r.UID] = rule
+		}
+	}
+	return result, nil
+}
+
+func instanceToArchive(instance *models.AlertInstance) definitions.AlertStateArchiveInstance {
+	return definitions.AlertStateArchiveInstance{
+		RuleUID:           instance.RuleUID,
+		Labels:            instance.Labels,
+		Annotations:       instance.Annotations,
+		State:             string(instance.CurrentState),
+		Reason:            instance.CurrentReason,
+		StateSince:        instance.CurrentStateSince,
+		StateEnd:          instance.CurrentStateEnd,
+		LastEvalTime:      instance.LastEvalTime,
+		LastSentAt:        instance.LastSentAt,
+		FiredAt:           instance.FiredAt,
+		ResolvedAt:        instance.ResolvedAt,
+		ResultFingerprint: instance.ResultFingerprint,
+		LastError:         instance.LastError,
+	}
+}
+
+// instanceFromArchive converts the instance in the archive to an instance of the alert rule it was matched with. The
+// labels that identify the alert rule are replaced so that the instance matches the results of the next evaluation.
+func instanceFromArchive(instance definitions.AlertStateArchiveInstance, rule *models.AlertRule) (models.AlertInstance, error) {
+	labels := make(models.InstanceLabels, len(instance.Labels))
+	for k, v := range instance.Labels {
+		labels[k] = v
+	}
+	if _, ok := labels[alertingModels.RuleUIDLabel]; ok {
+		labels[alertingModels.RuleUIDLabel] = rule.UID
+	}
+	if _, ok := labels[alertingModels.NamespaceUIDLabel]; ok {
+		labels[alertingModels.NamespaceUIDLabel] = rule.NamespaceUID
+	}
+	if _, ok := labels[model.AlertNameLabel]; ok {
+		labels[model.AlertNameLabel] = rule.Title
+	}
+	if _, ok := labels[models.FolderTitleLabel]; ok && rule.FolderFullpath != "" {
+		labels[models.FolderTitleLabel] = rule.FolderFullpath
+	}
+	_, hash, err := labels.StringAndHash()
+	if err != nil {
+		return models.AlertInstance{}, err
+	}
+
+	result := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		Labels:            labels,
+		Annotations:       instance.Annotations,
+		CurrentState:      models.InstanceStateType(instance.State),
+		CurrentReason:     instance.Reason,
+		CurrentStateSince: instance.StateSince,
+		CurrentStateEnd:   instance.StateEnd,
+		LastEvalTime:      instance.LastEvalTime,
+		LastSentAt:        instance.LastSentAt,
+		FiredAt:           instance.FiredAt,
+		ResolvedAt:        instance.ResolvedAt,
+		ResultFingerprint: instance.ResultFingerprint,
+		LastError:         instance.LastError,
+	}
+	if err := models.ValidateAlertInstance(result); err != nil {
+		return models.AlertInstance{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,299 @@
+package statearchive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+type fakeRuleStore struct {
+	rules []*models.AlertRule
+}
+
+func (f *fakeRuleStore) ListAlertRules(_ context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error) {
+	var result models.RulesGroup
+	for _, rule := range f.rules {
+		if rule.OrgID != query.OrgID {
+			continue
+		}
+		if len(query.RuleUIDs) > 0 && !slices.Contains(query.RuleUIDs, rule.UID) {
+			continue
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+type fakeInstanceStore struct {
+	instances map[models.AlertRuleKey][]models.AlertInstance
+	// failFor is the UID of the alert rule whose instances cannot be saved.
+	failFor string
+}
+
+func (f *fakeInstanceStore) ListAlertInstances(_ context.Context, query *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var result []*models.AlertInstance
+	for key, instances := range f.instances {
+		if key.OrgID != query.RuleOrgID {
+			continue
+		}
+		for i := range instances {
+			result = append(result, &instances[i])
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeInstanceStore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKeyWithGroup, instances []models.AlertInstance) error {
+	tx, ok := ctx.Value(fakeTransactionKey{}).(*fakeTransaction)
+	if !ok {
+		return errors.New("alert instances must be saved in a transaction")
+	}
+	if key.UID == f.failFor {
+		return errors.New("failed to save")
+	}
+	tx.writes = append(tx.writes, func() { f.instances[key.AlertRuleKey] = instances })
+	return nil
+}
+
+type fakeTransactionKey struct{}
+
+type fakeTransaction struct {
+	writes []func()
+}
+
+// fakeTransactionManager applies the writes of the fake instance store when the transaction is committed.
+type fakeTransactionManager struct {
+	transactions int
+}
+
+func (f *fakeTransactionManager) InTransaction(ctx context.Context, work func(ctx context.Context) error) error {
+	f.transactions++
+	tx := &fakeTransaction{}
+	if err := work(context.WithValue(ctx, fakeTransactionKey{}, tx)); err != nil {
+		return err
+	}
+	for _, write := range tx.writes {
+		write()
+	}
+	return nil
+}
+
+type fakeAlertmanagerState struct {
+	state  notifier.ExternalState
+	merged []notifier.ExternalState
+}
+
+func (f *fakeAlertmanagerState) GetAlertmanagerState(context.Context, int64) (notifier.ExternalState, error) {
+	return f.state, nil
+}
+
+func (f *fakeAlertmanagerState) MergeAlertmanagerState(_ context.Context, _ int64, st notifier.ExternalState) error {
+	f.merged = append(f.merged, st)
+	return nil
+}
+
+type fakeLoader struct {
+	loaded []string
+}
+
+func (f *fakeLoader) TakeOverStateByRuleUID(_ context.Context, rule *models.AlertRule) error {
+	f.loaded = append(f.loaded, rule.UID)
+	return nil
+}
+
+func newInstance(t *testing.T, rule *models.AlertRule, state models.InstanceStateType) models.AlertInstance {
+	t.Helper()
+	labels := models.InstanceLabels{
+		model.AlertNameLabel:             rule.Title,
+		alertingModels.RuleUIDLabel:      rule.UID,
+		alertingModels.NamespaceUIDLabel: rule.NamespaceUID,
+		models.FolderTitleLabel:          rule.FolderFullpath,
+		"instance":                       "server-1",
+	}
+	_, hash, err := labels.StringAndHash()
+	require.NoError(t, err)
+	since := time.Date(2026, time.March, 4, 2, 0, 0, 0, time.UTC)
+	return models.AlertInstance{
+		AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash},
+		Labels:            labels,
+		CurrentState:      state,
+		CurrentStateSince: since,
+		CurrentStateEnd:   since.Add(4 * time.Minute),
+		LastEvalTime:      since,
+		FiredAt:           &since,
+	}
+}
+
+func encodeSilences(t *testing.T, silences ...*silencepb.MeshSilence) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, s := range silences {
+		_, err := pbutil.WriteDelimited(&buf, s)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func decodeSilences(t *testing.T, b []byte) []*silencepb.MeshSilence {
+	t.Helper()
+	var result []*silencepb.MeshSilence
+	r := bytes.NewReader(b)
+	for {
+		var s silencepb.MeshSilence
+		_, err := pbutil.ReadDelimited(r, &s)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		require.NoError(t, err)
+		result = append(result, &s)
+	}
+}
+
+func silenceForRule(id, ruleUID string) *silencepb.MeshSilence {
+	return &silencepb.MeshSilence{
+		Silence: &silencepb.Silence{
+			Id: id,
+			Matchers: []*silencepb.Matcher{
+				{Type: silencepb.Matcher_EQUAL, Name: alertingModels.RuleUIDLabel, Pattern: ruleUID},
+				{Type: silencepb.Matcher_REGEXP, Name: alertingModels.NamespaceUIDLabel, Pattern: "source-folder"},
+			},
+		},
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	cpu := &models.AlertRule{OrgID: 1, UID: "cpu", Title: "High CPU", NamespaceUID: "source-folder", FolderFullpath: "Source folder", RuleGroup: "group"}
+	disk := &models.AlertRule{OrgID: 1, UID: "disk", Title: "Disk full", NamespaceUID: "source-folder", FolderFullpath: "Source folder", RuleGroup: "group"}
+	deleted := &models.AlertRule{OrgID: 1, UID: "deleted", Title: "Deleted", NamespaceUID: "source-folder", FolderFullpath: "Source folder", RuleGroup: "group"}
+
+	sourceInstances := &fakeInstanceStore{instances: map[models.AlertRuleKey][]models.AlertInstance{
+		cpu.GetKey():     {newInstance(t, cpu, models.InstanceStateFiring)},
+		disk.GetKey():    {newInstance(t, disk, models.InstanceStatePending)},
+		deleted.GetKey(): {newInstance(t, deleted, models.InstanceStateFiring)},
+	}}
+	sourceAM := &fakeAlertmanagerState{state: notifier.ExternalState{
+		Silences: encodeSilences(t, silenceForRule("silence-1", "cpu")),
+		Nflog:    []byte("nflog"),
+	}}
+	source := NewService(&fakeRuleStore{rules: []*models.AlertRule{cpu, disk}}, sourceInstances, &fakeTransactionManager{}, sourceAM, nil, log.NewNopLogger())
+
+	archive, err := source.Export(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, Version, archive.Version)
+	require.Len(t, archive.Rules, 2)
+	require.Equal(t, "cpu", archive.Rules[0].UID)
+	require.Len(t, archive.Instances, 2, "instances of deleted rules should not be exported")
+	require.Equal(t, "cpu", archive.Instances[0].RuleUID)
+	require.Equal(t, []byte("nflog"), archive.NotificationLog)
+
+	t.Run("should remap rules by title in the mapped folder and by explicit UID", func(t *testing.T) {
+		targetCPU := &models.AlertRule{OrgID: 2, UID: "cpu-2", Title: "High CPU", NamespaceUID: "target-folder", FolderFullpath: "Target folder", RuleGroup: "group"}
+		targetDisk := &models.AlertRule{OrgID: 2, UID: "disk-2", Title: "Renamed disk rule", NamespaceUID: "target-folder", FolderFullpath: "Target folder", RuleGroup: "group"}
+		instances := &fakeInstanceStore{instances: map[models.AlertRuleKey][]models.AlertInstance{}}
+		am := &fakeAlertmanagerState{}
+		loader := &fakeLoader{}
+		target := NewService(&fakeRuleStore{rules: []*models.AlertRule{targetCPU, targetDisk}}, instances, &fakeTransactionManager{}, am, loader, log.NewNopLogger())
+
+		result, err := target.Import(ctx, 2, *archive, ImportOptions{
+			RuleUIDs:   map[string]string{"disk": "disk-2"},
+			FolderUIDs: map[string]string{"source-folder": "target-folder"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Rules)
+		require.Equal(t, 2, result.Instances)
+		require.Empty(t, result.SkippedRules)
+		require.Equal(t, []string{"cpu-2", "disk-2"}, loader.loaded)
+
+		imported := instances.instances[targetCPU.GetKey()]
+		require.Len(t, imported, 1)
+		require.Equal(t, models.InstanceStateFiring, imported[0].CurrentState)
+		require.Equal(t, "cpu-2", imported[0].Labels[alertingModels.RuleUIDLabel])
+		require.Equal(t, "target-folder", imported[0].Labels[alertingModels.NamespaceUIDLabel])
+		require.Equal(t, "Target folder", imported[0].Labels[models.FolderTitleLabel])
+		require.Equal(t, newInstance(t, targetCPU, models.InstanceStateFiring).LabelsHash, imported[0].LabelsHash)
+
+		imported = instances.instances[targetDisk.GetKey()]
+		require.Len(t, imported, 1)
+		require.Equal(t, "Renamed disk rule", imported[0].Labels[model.AlertNameLabel])
+
+		require.True(t, result.NotificationLogSkipped, "the notification log cannot be remapped")
+		require.Len(t, am.merged, 1)
+		require.Nil(t, am.merged[0].Nflog)
+		silences := decodeSilences(t, am.merged[0].Silences)
+		require.Len(t, silences, 1)
+		require.Equal(t, "cpu-2", silences[0].Silence.Matchers[0].Pattern)
+		require.Equal(t, "source-folder", silences[0].Silence.Matchers[1].Pattern, "only equality matchers should be remapped")
+	})
+
+	t.Run("should import the notification log when the rules are unchanged", func(t *testing.T) {
+		instances := &fakeInstanceStore{instances: map[models.AlertRuleKey][]models.AlertInstance{}}
+		am := &fakeAlertmanagerState{}
+		target := NewService(&fakeRuleStore{rules: []*models.AlertRule{cpu, disk}}, instances, &fakeTransactionManager{}, am, nil, log.NewNopLogger())
+
+		result, err := target.Import(ctx, 1, *archive, ImportOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Rules)
+		require.False(t, result.NotificationLogSkipped)
+		require.Len(t, am.merged, 1)
+		require.Equal(t, []byte("nflog"), am.merged[0].Nflog)
+	})
+
+	t.Run("should skip rules that are not found", func(t *testing.T) {
+		instances := &fakeInstanceStore{instances: map[models.AlertRuleKey][]models.AlertInstance{}}
+		sameCPU := &models.AlertRule{OrgID: 2, UID: "cpu", Title: "Other title", NamespaceUID: "other-folder", FolderFullpath: "Other folder", RuleGroup: "group"}
+		target := NewService(&fakeRuleStore{rules: []*models.AlertRule{sameCPU}}, instances, &fakeTransactionManager{}, &fakeAlertmanagerState{}, nil, log.NewNopLogger())
+
+		result, err := target.Import(ctx, 2, *archive, ImportOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Rules)
+		require.Equal(t, []string{"disk"}, result.SkippedRules)
+		require.Len(t, instances.instances[sameCPU.GetKey()], 1)
+	})
+
+	t.Run("should import nothing if the instances of a rule cannot be saved", func(t *testing.T) {
+		instances := &fakeInstanceStore{instances: map[models.AlertRuleKey][]models.AlertInstance{}, failFor: "disk"}
+		am := &fakeAlertmanagerState{}
+		loader := &fakeLoader{}
+		xact := &fakeTransactionManager{}
+		target := NewService(&fakeRuleStore{rules: []*models.AlertRule{cpu, disk}}, instances, xact, am, loader, log.NewNopLogger())
+
+		_, err := target.Import(ctx, 1, *archive, ImportOptions{})
+		require.Error(t, err)
+		require.Equal(t, 1, xact.transactions)
+		require.Empty(t, instances.instances)
+		require.Empty(t, am.merged)
+		require.Empty(t, loader.loaded)
+	})
+
+	t.Run("should reject invalid archives", func(t *testing.T) {
+		instances := &fakeInstanceStore{instances: map[models.AlertRuleKey][]models.AlertInstance{}}
+		target := NewService(&fakeRuleStore{rules: []*models.AlertRule{cpu, disk}}, instances, &fakeTransactionManager{}, &fakeAlertmanagerState{}, nil, log.NewNopLogger())
+
+		unsupported := *archive
+		unsupported.Version = Version + 1
+		_, err := target.Import(ctx, 1, unsupported, ImportOptions{})
+		require.ErrorIs(t, err, ErrInvalidArchive)
+
+		invalidState := *archive
+		invalidState.Instances = []definitions.AlertStateArchiveInstance{{RuleUID: "cpu", State: "Unknown"}}
+		_, err = target.Import(ctx, 1, invalidState, ImportOptions{})
+		require.ErrorIs(t, err, ErrInvalidArchive)
+		require.Empty(t, instances.instances, "nothing should be saved from an invalid archive")
+	})
+}
@@ -0,0 +1,52 @@
+package statearchive
+
+import (
+	"bytes"
+	"io"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+// remapSilences replaces the rule and folder UIDs in the equality matchers of the silences. The silences are
+// encoded in the format of the silences state of the Alertmanager, a sequence of length-delimited protobuf messages.
+func remapSilences(state []byte, ruleUIDs, folderUIDs map[string]string) ([]byte, error) {
+	if len(state) == 0 {
+		return state, nil
+	}
+
+	var buf bytes.Buffer
+	r := bytes.NewReader(state)
+	for {
+		var s silencepb.MeshSilence
+		if _, err := pbutil.ReadDelimited(r, &s); err != nil {
+			//nolint:errorlint
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if s.Silence != nil {
+			for _, m := range s.Silence.Matchers {
+				if m.Type != silencepb.Matcher_EQUAL {
+					continue
+				}
+				switch m.Name {
+				case alertingModels.RuleUIDLabel:
+					if uid, ok := ruleUIDs[m.Pattern]; ok {
+						m.Pattern = uid
+					}
+				case alertingModels.NamespaceUIDLabel:
+					if uid, ok := folderUIDs[m.Pattern]; ok {
+						m.Pattern = uid
+					}
+				}
+			}
+		}
+		if _, err := pbutil.WriteDelimited(&buf, &s); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
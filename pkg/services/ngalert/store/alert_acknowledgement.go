@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type alertAcknowledgement struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	RuleOrgID      int64  `xorm:"rule_org_id"`
+	RuleUID        string `xorm:"rule_uid"`
+	LabelsHash     string `xorm:"labels_hash"`
+	AcknowledgedBy string `xorm:"acknowledged_by"`
+	Assignee       string `xorm:"assignee"`
+	Note           string `xorm:"note"`
+	Created        int64  `xorm:"created"`
+	ExpiresAt      int64  `xorm:"expires_at"`
+}
+
+func alertAcknowledgementFromModel(a ngmodels.AlertAcknowledgement) alertAcknowledgement {
+	var expiresAt int64
+	if a.ExpiresAt != nil {
+		expiresAt = a.ExpiresAt.Unix()
+	}
+	return alertAcknowledgement{
+		RuleOrgID:      a.RuleOrgID,
+		RuleUID:        a.RuleUID,
+		LabelsHash:     a.LabelsHash,
+		AcknowledgedBy: a.AcknowledgedBy,
+		Assignee:       a.Assignee,
+		Note:           a.Note,
+		Created:        a.Created.Unix(),
+		ExpiresAt:      expiresAt,
+	}
+}
+
+func (a alertAcknowledgement) toModel() ngmodels.AlertAcknowledgement {
+	var expiresAt *time.Time
+	if a.ExpiresAt > 0 {
+		t := time.Unix(a.ExpiresAt, 0).UTC()
+		expiresAt = &t
+	}
+	return ngmodels.AlertAcknowledgement{
+		AlertInstanceKey: ngmodels.AlertInstanceKey{
+			RuleOrgID:  a.RuleOrgID,
+			RuleUID:    a.RuleUID,
+			LabelsHash: a.LabelsHash,
+		},
+		AcknowledgedBy: a.AcknowledgedBy,
+		Assignee:       a.Assignee,
+		Note:           a.Note,
+		Created:        time.Unix(a.Created, 0).UTC(),
+		ExpiresAt:      expiresAt,
+	}
+}
+
+// ListAlertAcknowledgements returns the acknowledgements of the alert instances of the organization,
+// or of all organizations if orgID is 0.
+func (st DBstore) ListAlertAcknowledgements(ctx context.Context, orgID int64) ([]ngmodels.AlertAcknowledgement, error) {
+	var rows []alertAcknowledgement
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table("alert_acknowledgement")
+		if orgID > 0 {
+			q = q.Where("rule_org_id = ?", orgID)
+		}
+		return q.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ngmodels.AlertAcknowledgement, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.toModel())
+	}
+	return result, nil
+}
+
+// SaveAlertAcknowledgement creates the acknowledgement of the alert instance, or replaces the existing one.
+func (st DBstore) SaveAlertAcknowledgement(ctx context.Context, a ngmodels.AlertAcknowledgement) error {
+	row := alertAcknowledgementFromModel(a)
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_acknowledgement WHERE rule_org_id = ? AND rule_uid = ? AND labels_hash = ?", a.RuleOrgID, a.RuleUID, a.LabelsHash)
+		if err != nil {
+			return err
+		}
+		_, err = sess.Table("alert_acknowledgement").Insert(&row)
+		return err
+	})
+}
+
+// DeleteAlertAcknowledgement deletes the acknowledgement of the alert instance, or returns
+// ErrAlertAcknowledgementNotFound if the alert instance is not acknowledged.
+func (st DBstore) DeleteAlertAcknowledgement(ctx context.Context, key ngmodels.AlertInstanceKey) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_acknowledgement WHERE rule_org_id = ? AND rule_uid = ? AND labels_hash = ?", key.RuleOrgID, key.RuleUID, key.LabelsHash)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ngmodels.ErrAlertAcknowledgementNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationAlertAcknowledgements(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := created.Add(time.Hour)
+	ack := ngmodels.AlertAcknowledgement{
+		AlertInstanceKey: ngmodels.AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule", LabelsHash: "hash"},
+		AcknowledgedBy:   "admin",
+		Assignee:         "oncall",
+		Note:             "Looking into it",
+		Created:          created,
+		ExpiresAt:        &expiresAt,
+	}
+	require.NoError(t, store.SaveAlertAcknowledgement(ctx, ack))
+	other := ngmodels.AlertAcknowledgement{
+		AlertInstanceKey: ngmodels.AlertInstanceKey{RuleOrgID: 2, RuleUID: "rule", LabelsHash: "hash"},
+		AcknowledgedBy:   "admin",
+		Assignee:         "admin",
+		Created:          created,
+	}
+	require.NoError(t, store.SaveAlertAcknowledgement(ctx, other))
+
+	acks, err := store.ListAlertAcknowledgements(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []ngmodels.AlertAcknowledgement{ack}, acks)
+
+	acks, err = store.ListAlertAcknowledgements(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, acks, 2)
+
+	t.Run("saving the acknowledgement of an acknowledged alert instance replaces it", func(t *testing.T) {
+		updated := ack
+		updated.Assignee = "admin"
+		updated.ExpiresAt = nil
+		require.NoError(t, store.SaveAlertAcknowledgement(ctx, updated))
+
+		acks, err := store.ListAlertAcknowledgements(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []ngmodels.AlertAcknowledgement{updated}, acks)
+	})
+
+	t.Run("delete removes the acknowledgement", func(t *testing.T) {
+		require.NoError(t, store.DeleteAlertAcknowledgement(ctx, ack.AlertInstanceKey))
+		err := store.DeleteAlertAcknowledgement(ctx, ack.AlertInstanceKey)
+		require.ErrorIs(t, err, ngmodels.ErrAlertAcknowledgementNotFound)
+
+		acks, err := store.ListAlertAcknowledgements(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, acks)
+
+		acks, err = store.ListAlertAcknowledgements(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, acks, 1)
+	})
+}
@@ -616,6 +616,7 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, user *ngmodels.UserUID,
 			v := alertRuleToAlertRuleVersion(converted)
 			v.Version++
 			v.ParentVersion = r.Existing.Version
+			v.RestoredFrom = r.RestoredFrom
 			v.Message = r.Message
 
 			// check if there is diff between existing and new, and if no, skip saving version.
@@ -808,6 +809,11 @@ func (st DBstore) ListAlertRulesByGroup(ctx context.Context, query *ngmodels.Lis
 				// Need Metadata for this filter
 				opts.ExcludeMetadata = false
 			}
+
+			if len(query.DependsOnRuleUIDs) > 0 {
+				// Need the queries for this filter
+				opts.ExcludeAlertQueries = false
+			}
 		}
 
 		// Process rules and implement per-group pagination
@@ -951,6 +957,10 @@ func shouldIncludeRule(rule *ngmodels.AlertRule, query *ngmodels.ListAlertRulesE
 		}
 	}
 
+	if len(query.DependsOnRuleUIDs) > 0 && !rule.DependsOnAny(query.DependsOnRuleUIDs) {
+		return false
+	}
+
 	if groupsMap != nil {
 		if _, ok := groupsMap[rule.RuleGroup]; !ok {
 			return false
@@ -1308,6 +1318,21 @@ func (st DBstore) buildListAlertRulesQuery(sess *db.Session, query *ngmodels.Lis
 		q = q.And("("+strings.Join(orConditions, " OR ")+")", orParams...)
 	}
 
+	if len(query.DependsOnRuleUIDs) > 0 {
+		orConditions := make([]string, 0, len(query.DependsOnRuleUIDs))
+		orParams := make([]interface{}, 0, len(query.DependsOnRuleUIDs))
+		for _, ruleUID := range query.DependsOnRuleUIDs {
+			// Like for data sources, the UID of the rule is searched in the normalized JSON of the 'data' column.
+			// Queries of other types with the same field are removed from the result.
+			pattern := fmt.Sprintf(`"ruleUid":"%s"`, ruleUID)
+			sql, param := st.SQLStore.GetDialect().LikeOperator("data", true, pattern, true)
+			orConditions = append(orConditions, sql)
+			orParams = append(orParams, param)
+		}
+
+		q = q.And("("+strings.Join(orConditions, " OR ")+")", orParams...)
+	}
+
 	if query.SearchTitle != "" {
 		for _, term := range searchTitleTerms(query.SearchTitle) {
 			sql, param := st.SQLStore.GetDialect().LikeOperator("title", true, term, true)
@@ -1433,6 +1458,9 @@ func (st DBstore) handleRuleRow(rows *xorm.Rows, query *ngmodels.ListAlertRulesE
 			return nil, false
 		}
 	}
+	if len(query.DependsOnRuleUIDs) > 0 && !converted.DependsOnAny(query.DependsOnRuleUIDs) { // remove false-positive hits from the result
+		return nil, false
+	}
 	if len(query.LabelMatchers) > 0 { // remove false-positive hits from the result
 		if !matchersMatchLabels(query.LabelMatchers, converted.Labels) {
 			return nil, false
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
@@ -18,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -2703,6 +2705,72 @@ func TestIntegration_ListAlertRules(t *testing.T) {
 		}
 	})
 
+	t.Run("filter by DependsOnRuleUIDs", func(t *testing.T) {
+		sqlStore := db.InitTestDB(t)
+		folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+		store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, b)
+
+		withRuleStateQueries := func(uids ...string) models.AlertRuleMutator {
+			queries := []models.AlertQuery{models.GenerateAlertQuery()}
+			for i, uid := range uids {
+				queries = append(queries, models.AlertQuery{
+					RefID:         fmt.Sprintf("S%d", i),
+					DatasourceUID: expr.DatasourceUID,
+					Model:         json.RawMessage(fmt.Sprintf(`{"type": "rule_state", "ruleUid": %q}`, uid)),
+				})
+			}
+			return models.RuleGen.WithQuery(queries...)
+		}
+
+		createRule(t, store, ruleGen.With(models.RuleGen.WithUID("parent-1")))
+		createRule(t, store, ruleGen.With(models.RuleGen.WithUID("parent-2")))
+		createRule(t, store, ruleGen.With(models.RuleGen.WithUID("child-1"), withRuleStateQueries("parent-1")))
+		createRule(t, store, ruleGen.With(models.RuleGen.WithUID("child-2"), withRuleStateQueries("parent-1", "parent-2")))
+		// The UID is in a query of a data source, not in a rule state expression.
+		createRule(t, store, ruleGen.With(models.RuleGen.WithUID("other"), models.RuleGen.WithQuery(models.AlertQuery{
+			RefID:         "A",
+			DatasourceUID: "datasource",
+			Model:         json.RawMessage(`{"ruleUid": "parent-1"}`),
+		})))
+
+		tc := []struct {
+			name         string
+			ruleUIDs     []string
+			expectedUIDs []string
+		}{
+			{
+				name:         "searching for parent-1 returns the rules that depend on it",
+				ruleUIDs:     []string{"parent-1"},
+				expectedUIDs: []string{"child-1", "child-2"},
+			},
+			{
+				name:         "searching for parent-2 returns the rules that depend on it",
+				ruleUIDs:     []string{"parent-2"},
+				expectedUIDs: []string{"child-2"},
+			},
+			{
+				name:     "searching for a rule without dependents returns no rules",
+				ruleUIDs: []string{"child-1"},
+			},
+		}
+
+		for _, tt := range tc {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := store.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{
+					OrgID:             orgID,
+					DependsOnRuleUIDs: tt.ruleUIDs,
+				})
+				require.NoError(t, err)
+
+				got := make([]string, 0, len(result))
+				for _, r := range result {
+					got = append(got, r.UID)
+				}
+				require.ElementsMatch(t, tt.expectedUIDs, got)
+			})
+		}
+	})
+
 	t.Run("filter by SearchTitle", func(t *testing.T) {
 		sqlStore := db.InitTestDB(t)
 		folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ClusterPeerStore is the database interface of the Alertmanager cluster that uses the database for high availability.
+type ClusterPeerStore interface {
+	// HeartbeatClusterPeer creates the peer or updates the time of its last heartbeat.
+	HeartbeatClusterPeer(ctx context.Context, name string, at time.Time) error
+	ListClusterPeers(ctx context.Context) ([]ngmodels.ClusterPeer, error)
+	// DeleteClusterPeers deletes the peers whose last heartbeat is before the given time.
+	DeleteClusterPeers(ctx context.Context, before time.Time) error
+	DeleteClusterPeer(ctx context.Context, name string) error
+	InsertClusterMessage(ctx context.Context, msg ngmodels.ClusterMessage) error
+	// ListClusterMessages returns at most limit messages with an ID greater than afterID, ordered by ID.
+	ListClusterMessages(ctx context.Context, afterID int64, limit int) ([]ngmodels.ClusterMessage, error)
+	// GetLastClusterMessageID returns the ID of the most recent message, or 0 if there are no messages.
+	GetLastClusterMessageID(ctx context.Context) (int64, error)
+	// DeleteClusterMessages deletes the messages created before the given time.
+	DeleteClusterMessages(ctx context.Context, before time.Time) (int64, error)
+}
+
+type clusterPeer struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	Name      string `xorm:"name"`
+	Heartbeat int64  `xorm:"heartbeat"`
+}
+
+type clusterMessage struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	Sender  string `xorm:"sender"`
+	Channel string `xorm:"channel"`
+	Payload []byte `xorm:"payload"`
+	Created int64  `xorm:"created"`
+}
+
+func (st DBstore) HeartbeatClusterPeer(ctx context.Context, name string, at time.Time) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
+			"alert_cluster_peer",
+			[]string{"name"},
+			[]string{"name", "heartbeat"},
+		)
+		_, err := sess.SQL(upsertSQL, name, at.Unix()).Query()
+		return err
+	})
+}
+
+// ListClusterPeers returns all peers, including those that stopped sending heartbeats, ordered by name.
+func (st DBstore) ListClusterPeers(ctx context.Context) ([]ngmodels.ClusterPeer, error) {
+	var rows []clusterPeer
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_cluster_peer").Asc("name").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ngmodels.ClusterPeer, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ngmodels.ClusterPeer{Name: row.Name, Heartbeat: time.Unix(row.Heartbeat, 0).UTC()})
+	}
+	return result, nil
+}
+
+func (st DBstore) DeleteClusterPeers(ctx context.Context, before time.Time) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_cluster_peer WHERE heartbeat < ?", before.Unix())
+		return err
+	})
+}
+
+func (st DBstore) DeleteClusterPeer(ctx context.Context, name string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_cluster_peer WHERE name = ?", name)
+		return err
+	})
+}
+
+func (st DBstore) InsertClusterMessage(ctx context.Context, msg ngmodels.ClusterMessage) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Table("alert_cluster_message").Insert(&clusterMessage{
+			Sender:  msg.Sender,
+			Channel: msg.Channel,
+			Payload: msg.Payload,
+			Created: msg.Created.Unix(),
+		})
+		return err
+	})
+}
+
+func (st DBstore) ListClusterMessages(ctx context.Context, afterID int64, limit int) ([]ngmodels.ClusterMessage, error) {
+	var rows []clusterMessage
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_cluster_message").Where("id > ?", afterID).Asc("id").Limit(limit).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ngmodels.ClusterMessage, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ngmodels.ClusterMessage{
+			ID:      row.ID,
+			Sender:  row.Sender,
+			Channel: row.Channel,
+			Payload: row.Payload,
+			Created: time.Unix(row.Created, 0).UTC(),
+		})
+	}
+	return result, nil
+}
+
+func (st DBstore) GetLastClusterMessageID(ctx context.Context) (int64, error) {
+	var id int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.SQL("SELECT COALESCE(MAX(id), 0) FROM alert_cluster_message").Get(&id)
+		return err
+	})
+	return id, err
+}
+
+func (st DBstore) DeleteClusterMessages(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_cluster_message WHERE created < ?", before.Unix())
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
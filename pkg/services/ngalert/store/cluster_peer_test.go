@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationClusterPeers(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("heartbeat creates and updates peer", func(t *testing.T) {
+		require.NoError(t, store.HeartbeatClusterPeer(ctx, "peer-b", now.Add(-time.Hour)))
+		require.NoError(t, store.HeartbeatClusterPeer(ctx, "peer-a", now.Add(-time.Minute)))
+		require.NoError(t, store.HeartbeatClusterPeer(ctx, "peer-a", now))
+
+		peers, err := store.ListClusterPeers(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []ngmodels.ClusterPeer{
+			{Name: "peer-a", Heartbeat: now},
+			{Name: "peer-b", Heartbeat: now.Add(-time.Hour)},
+		}, peers)
+	})
+
+	t.Run("deletes stale peers", func(t *testing.T) {
+		require.NoError(t, store.DeleteClusterPeers(ctx, now.Add(-time.Minute)))
+		peers, err := store.ListClusterPeers(ctx)
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+
+		require.NoError(t, store.DeleteClusterPeer(ctx, "peer-a"))
+		peers, err = store.ListClusterPeers(ctx)
+		require.NoError(t, err)
+		require.Empty(t, peers)
+	})
+
+	t.Run("lists messages after the given one", func(t *testing.T) {
+		lastID, err := store.GetLastClusterMessageID(ctx)
+		require.NoError(t, err)
+		require.Zero(t, lastID)
+
+		for _, channel := range []string{"silences", "nflog", "full_state"} {
+			require.NoError(t, store.InsertClusterMessage(ctx, ngmodels.ClusterMessage{
+				Sender:  "peer-a",
+				Channel: channel,
+				Payload: []byte(channel),
+				Created: now.Add(-time.Hour),
+			}))
+		}
+		require.NoError(t, store.InsertClusterMessage(ctx, ngmodels.ClusterMessage{
+			Sender:  "peer-b",
+			Channel: "silences",
+			Payload: []byte{0, 1, 2},
+			Created: now,
+		}))
+
+		messages, err := store.ListClusterMessages(ctx, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		require.Equal(t, "silences", messages[0].Channel)
+		require.Equal(t, "nflog", messages[1].Channel)
+
+		messages, err = store.ListClusterMessages(ctx, messages[1].ID, 100)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		require.Equal(t, "peer-b", messages[1].Sender)
+		require.Equal(t, []byte{0, 1, 2}, messages[1].Payload)
+		require.Equal(t, now, messages[1].Created)
+
+		lastID, err = store.GetLastClusterMessageID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, messages[1].ID, lastID)
+	})
+
+	t.Run("deletes old messages", func(t *testing.T) {
+		deleted, err := store.DeleteClusterMessages(ctx, now.Add(-time.Minute))
+		require.NoError(t, err)
+		require.EqualValues(t, 3, deleted)
+
+		messages, err := store.ListClusterMessages(ctx, 0, 100)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+	})
+}
@@ -325,7 +325,8 @@ func alertRuleVersionToModelsAlertRuleVersion(version alertRuleVersion, l log.Lo
 	}
 
 	return models.AlertRuleVersion{
-		AlertRule: result,
-		Message:   version.Message,
+		AlertRule:    result,
+		Message:      version.Message,
+		RestoredFrom: version.RestoredFrom,
 	}, nil
 }
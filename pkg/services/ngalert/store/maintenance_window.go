@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MaintenanceWindowStore is the database interface of the maintenance windows.
+type MaintenanceWindowStore interface {
+	// ListMaintenanceWindows returns the maintenance windows of the organization, or of all organizations if orgID is 0.
+	ListMaintenanceWindows(ctx context.Context, orgID int64) ([]*ngmodels.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*ngmodels.MaintenanceWindow, error)
+	InsertMaintenanceWindow(ctx context.Context, w ngmodels.MaintenanceWindow) (*ngmodels.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, w ngmodels.MaintenanceWindow) (*ngmodels.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error
+	// ClaimMaintenanceWindowOccurrence records that a silence is being created for the occurrence that starts at
+	// the given time. It returns false if the occurrence was already claimed, for example by another instance.
+	ClaimMaintenanceWindowOccurrence(ctx context.Context, orgID int64, uid string, occurrence time.Time) (bool, error)
+	// SetMaintenanceWindowSilence records the silence created for the last claimed occurrence.
+	SetMaintenanceWindowSilence(ctx context.Context, orgID int64, uid string, silenceID string) error
+}
+
+type maintenanceWindow struct {
+	ID                  int64  `xorm:"pk autoincr 'id'"`
+	OrgID               int64  `xorm:"org_id"`
+	UID                 string `xorm:"uid"`
+	Title               string `xorm:"title"`
+	Schedule            string `xorm:"schedule"`
+	Timezone            string `xorm:"timezone"`
+	Duration            int64  `xorm:"duration"`
+	Matchers            string `xorm:"matchers"`
+	PauseRuleEvaluation bool   `xorm:"pause_rule_evaluation"`
+	CreatedBy           string `xorm:"created_by"`
+	UpdatedBy           string `xorm:"updated_by"`
+	Created             int64  `xorm:"created"`
+	Updated             int64  `xorm:"updated"`
+	LastOccurrence      int64  `xorm:"last_occurrence"`
+	SilenceID           string `xorm:"silence_id"`
+}
+
+func maintenanceWindowFromModel(w ngmodels.MaintenanceWindow) (maintenanceWindow, error) {
+	matchers := make([]string, 0, len(w.Matchers))
+	for _, m := range w.Matchers {
+		matchers = append(matchers, m.String())
+	}
+	raw, err := json.Marshal(matchers)
+	if err != nil {
+		return maintenanceWindow{}, fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+	var lastOccurrence int64
+	if !w.LastOccurrence.IsZero() {
+		lastOccurrence = w.LastOccurrence.Unix()
+	}
+	return maintenanceWindow{
+		ID:                  w.ID,
+		OrgID:               w.OrgID,
+		UID:                 w.UID,
+		Title:               w.Title,
+		Schedule:            w.Schedule,
+		Timezone:            w.Timezone,
+		Duration:            int64(w.Duration.Seconds()),
+		Matchers:            string(raw),
+		PauseRuleEvaluation: w.PauseRuleEvaluation,
+		CreatedBy:           w.CreatedBy,
+		UpdatedBy:           w.UpdatedBy,
+		Created:             w.Created.Unix(),
+		Updated:             w.Updated.Unix(),
+		LastOccurrence:      lastOccurrence,
+		SilenceID:           w.SilenceID,
+	}, nil
+}
+
+func (w maintenanceWindow) toModel() (*ngmodels.MaintenanceWindow, error) {
+	var raw []string
+	if err := json.Unmarshal([]byte(w.Matchers), &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal matchers of maintenance window %s: %w", w.UID, err)
+	}
+	matchers := make(labels.Matchers, 0, len(raw))
+	for _, s := range raw {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse matcher of maintenance window %s: %w", w.UID, err)
+		}
+		matchers = append(matchers, m)
+	}
+	var lastOccurrence time.Time
+	if w.LastOccurrence > 0 {
+		lastOccurrence = time.Unix(w.LastOccurrence, 0).UTC()
+	}
+	return &ngmodels.MaintenanceWindow{
+		ID:                  w.ID,
+		OrgID:               w.OrgID,
+		UID:                 w.UID,
+		Title:               w.Title,
+		Schedule:            w.Schedule,
+		Timezone:            w.Timezone,
+		Duration:            time.Duration(w.Duration) * time.Second,
+		Matchers:            matchers,
+		PauseRuleEvaluation: w.PauseRuleEvaluation,
+		CreatedBy:           w.CreatedBy,
+		UpdatedBy:           w.UpdatedBy,
+		Created:             time.Unix(w.Created, 0).UTC(),
+		Updated:             time.Unix(w.Updated, 0).UTC(),
+		LastOccurrence:      lastOccurrence,
+		SilenceID:           w.SilenceID,
+	}, nil
+}
+
+// ListMaintenanceWindows returns the maintenance windows of the organization ordered by title,
+// or of all organizations if orgID is 0.
+func (st DBstore) ListMaintenanceWindows(ctx context.Context, orgID int64) ([]*ngmodels.MaintenanceWindow, error) {
+	var rows []maintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table("alert_maintenance_window")
+		if orgID > 0 {
+			q = q.Where("org_id = ?", orgID)
+		}
+		return q.Asc("org_id", "title").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ngmodels.MaintenanceWindow, 0, len(rows))
+	for _, row := range rows {
+		w, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, nil
+}
+
+// GetMaintenanceWindow returns the maintenance window, or ErrMaintenanceWindowNotFound if it does not exist.
+func (st DBstore) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*ngmodels.MaintenanceWindow, error) {
+	row := maintenanceWindow{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ok, err := sess.Table("alert_maintenance_window").Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ngmodels.ErrMaintenanceWindowNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel()
+}
+
+// InsertMaintenanceWindow creates the maintenance window. A UID is generated if it is empty.
+func (st DBstore) InsertMaintenanceWindow(ctx context.Context, w ngmodels.MaintenanceWindow) (*ngmodels.MaintenanceWindow, error) {
+	if w.UID == "" {
+		w.UID = util.GenerateShortUID()
+	}
+	now := TimeNow().UTC().Truncate(time.Second)
+	w.Created = now
+	w.Updated = now
+	w.UpdatedBy = w.CreatedBy
+	w.LastOccurrence = time.Time{}
+	w.SilenceID = ""
+	row, err := maintenanceWindowFromModel(w)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table("alert_maintenance_window").Where("org_id = ? AND uid = ?", w.OrgID, w.UID).Exist()
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: a maintenance window with UID %s already exists", ngmodels.ErrMaintenanceWindowInvalid, w.UID)
+		}
+		if _, err := sess.Table("alert_maintenance_window").Insert(&row); err != nil {
+			return err
+		}
+		w.ID = row.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// UpdateMaintenanceWindow updates the definition of the maintenance window. The creation details are kept.
+// The last occurrence and its silence are reset, so that the silence of the active occurrence is created again.
+func (st DBstore) UpdateMaintenanceWindow(ctx context.Context, w ngmodels.MaintenanceWindow) (*ngmodels.MaintenanceWindow, error) {
+	w.Updated = TimeNow().UTC().Truncate(time.Second)
+	w.LastOccurrence = time.Time{}
+	w.SilenceID = ""
+	row, err := maintenanceWindowFromModel(w)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Table("alert_maintenance_window").
+			Where("org_id = ? AND uid = ?", w.OrgID, w.UID).
+			Cols("title", "schedule", "timezone", "duration", "matchers", "pause_rule_evaluation", "updated_by", "updated", "last_occurrence", "silence_id").
+			Update(&row)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ngmodels.ErrMaintenanceWindowNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return st.GetMaintenanceWindow(ctx, w.OrgID, w.UID)
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window, or returns ErrMaintenanceWindowNotFound if it does not exist.
+func (st DBstore) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_maintenance_window WHERE org_id = ? AND uid = ?", orgID, uid)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ngmodels.ErrMaintenanceWindowNotFound
+		}
+		return nil
+	})
+}
+
+// ClaimMaintenanceWindowOccurrence sets the last occurrence of the maintenance window if it is before the given one.
+// The update is conditional so that only one instance creates the silence of an occurrence.
+func (st DBstore) ClaimMaintenanceWindowOccurrence(ctx context.Context, orgID int64, uid string, occurrence time.Time) (bool, error) {
+	var claimed bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE alert_maintenance_window SET last_occurrence = ?, silence_id = '' WHERE org_id = ? AND uid = ? AND last_occurrence < ?",
+			occurrence.Unix(), orgID, uid, occurrence.Unix())
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = affected > 0
+		return nil
+	})
+	return claimed, err
+}
+
+// SetMaintenanceWindowSilence sets the ID of the silence created for the last occurrence of the maintenance window.
+func (st DBstore) SetMaintenanceWindowSilence(ctx context.Context, orgID int64, uid string, silenceID string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE alert_maintenance_window SET silence_id = ? WHERE org_id = ? AND uid = ?", silenceID, orgID, uid)
+		return err
+	})
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationMaintenanceWindows(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	window := ngmodels.MaintenanceWindow{
+		OrgID:    1,
+		Title:    "Nightly backup",
+		Schedule: "RRULE:FREQ=DAILY;BYHOUR=2",
+		Timezone: "Europe/Berlin",
+		Duration: time.Hour,
+		Matchers: labels.Matchers{
+			{Type: labels.MatchEqual, Name: "team", Value: "db"},
+			labels.MustNewMatcher(labels.MatchRegexp, "env", "prod|staging"),
+		},
+		PauseRuleEvaluation: true,
+		CreatedBy:           "admin",
+	}
+	created, err := store.InsertMaintenanceWindow(ctx, window)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+	other := window
+	other.OrgID = 2
+	_, err = store.InsertMaintenanceWindow(ctx, other)
+	require.NoError(t, err)
+
+	_, err = store.InsertMaintenanceWindow(ctx, *created)
+	require.ErrorIs(t, err, ngmodels.ErrMaintenanceWindowInvalid)
+
+	got, err := store.GetMaintenanceWindow(ctx, 1, created.UID)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, got.ID)
+	require.Equal(t, window.Title, got.Title)
+	require.Equal(t, window.Schedule, got.Schedule)
+	require.Equal(t, window.Timezone, got.Timezone)
+	require.Equal(t, window.Duration, got.Duration)
+	require.Equal(t, window.Matchers.String(), got.Matchers.String())
+	require.True(t, got.PauseRuleEvaluation)
+	require.Equal(t, "admin", got.CreatedBy)
+	require.True(t, created.Created.Equal(got.Created))
+
+	list, err := store.ListMaintenanceWindows(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	list, err = store.ListMaintenanceWindows(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	t.Run("should claim an occurrence only once", func(t *testing.T) {
+		occurrence := time.Date(2026, time.March, 4, 1, 0, 0, 0, time.UTC)
+		claimed, err := store.ClaimMaintenanceWindowOccurrence(ctx, 1, created.UID, occurrence)
+		require.NoError(t, err)
+		require.True(t, claimed)
+		claimed, err = store.ClaimMaintenanceWindowOccurrence(ctx, 1, created.UID, occurrence)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		require.NoError(t, store.SetMaintenanceWindowSilence(ctx, 1, created.UID, "silence-id"))
+		got, err := store.GetMaintenanceWindow(ctx, 1, created.UID)
+		require.NoError(t, err)
+		require.True(t, occurrence.Equal(got.LastOccurrence))
+		require.Equal(t, "silence-id", got.SilenceID)
+	})
+
+	t.Run("should update the window and reset the last occurrence", func(t *testing.T) {
+		update := *created
+		update.Title = "Weekly backup"
+		update.Schedule = "0 2 * * 6"
+		update.PauseRuleEvaluation = false
+		update.UpdatedBy = "editor"
+		updated, err := store.UpdateMaintenanceWindow(ctx, update)
+		require.NoError(t, err)
+		require.Equal(t, "Weekly backup", updated.Title)
+		require.Equal(t, "0 2 * * 6", updated.Schedule)
+		require.False(t, updated.PauseRuleEvaluation)
+		require.Equal(t, "admin", updated.CreatedBy)
+		require.Equal(t, "editor", updated.UpdatedBy)
+		require.True(t, updated.LastOccurrence.IsZero())
+		require.Empty(t, updated.SilenceID)
+
+		update.OrgID = 3
+		_, err = store.UpdateMaintenanceWindow(ctx, update)
+		require.ErrorIs(t, err, ngmodels.ErrMaintenanceWindowNotFound)
+	})
+
+	t.Run("should delete the window", func(t *testing.T) {
+		require.NoError(t, store.DeleteMaintenanceWindow(ctx, 1, created.UID))
+		_, err := store.GetMaintenanceWindow(ctx, 1, created.UID)
+		require.ErrorIs(t, err, ngmodels.ErrMaintenanceWindowNotFound)
+		require.ErrorIs(t, store.DeleteMaintenanceWindow(ctx, 1, created.UID), ngmodels.ErrMaintenanceWindowNotFound)
+
+		list, err := store.ListMaintenanceWindows(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+	})
+}
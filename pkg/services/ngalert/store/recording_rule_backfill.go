@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RecordingRuleBackfillStore is the database interface of the backfills of recording rules.
+type RecordingRuleBackfillStore interface {
+	// ListRecordingRuleBackfills returns the backfills of the rule, of all rules if ruleUID is empty,
+	// and of all organizations if orgID is 0.
+	ListRecordingRuleBackfills(ctx context.Context, orgID int64, ruleUID string) ([]*ngmodels.RecordingRuleBackfill, error)
+	GetRecordingRuleBackfill(ctx context.Context, orgID int64, uid string) (*ngmodels.RecordingRuleBackfill, error)
+	InsertRecordingRuleBackfill(ctx context.Context, b ngmodels.RecordingRuleBackfill) (*ngmodels.RecordingRuleBackfill, error)
+	// ClaimRecordingRuleBackfill sets the status of the backfill to running if it is pending, or if it is running but
+	// was not updated since staleBefore. It returns false if the backfill was not claimed, for example because another
+	// instance claimed it first.
+	ClaimRecordingRuleBackfill(ctx context.Context, orgID int64, uid string, staleBefore time.Time) (bool, error)
+	// UpdateRecordingRuleBackfillProgress saves the progress and the status of the backfill if it is still running.
+	// It returns false if the backfill is not running anymore, for example because it was canceled.
+	UpdateRecordingRuleBackfillProgress(ctx context.Context, b ngmodels.RecordingRuleBackfill) (bool, error)
+	// SetRecordingRuleBackfillStatus sets the status of the backfill if its current status is one of the given ones.
+	// It returns false if the status was not changed.
+	SetRecordingRuleBackfillStatus(ctx context.Context, orgID int64, uid string, status ngmodels.RecordingRuleBackfillStatus, current ...ngmodels.RecordingRuleBackfillStatus) (bool, error)
+}
+
+type recordingRuleBackfill struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	OrgID       int64  `xorm:"org_id"`
+	UID         string `xorm:"uid"`
+	RuleUID     string `xorm:"rule_uid"`
+	From        int64  `xorm:"from_time"`
+	To          int64  `xorm:"to_time"`
+	Status      string `xorm:"status"`
+	Next        int64  `xorm:"next_time"`
+	Evaluations int64  `xorm:"evaluations"`
+	Completed   int64  `xorm:"completed"`
+	Failed      int64  `xorm:"failed"`
+	LastError   string `xorm:"last_error"`
+	CreatedBy   string `xorm:"created_by"`
+	Created     int64  `xorm:"created"`
+	Updated     int64  `xorm:"updated"`
+}
+
+func recordingRuleBackfillFromModel(b ngmodels.RecordingRuleBackfill) recordingRuleBackfill {
+	return recordingRuleBackfill{
+		ID:          b.ID,
+		OrgID:       b.OrgID,
+		UID:         b.UID,
+		RuleUID:     b.RuleUID,
+		From:        b.From.Unix(),
+		To:          b.To.Unix(),
+		Status:      string(b.Status),
+		Next:        b.Next.Unix(),
+		Evaluations: b.Evaluations,
+		Completed:   b.Completed,
+		Failed:      b.Failed,
+		LastError:   b.LastError,
+		CreatedBy:   b.CreatedBy,
+		Created:     b.Created.Unix(),
+		Updated:     b.Updated.Unix(),
+	}
+}
+
+func (b recordingRuleBackfill) toModel() *ngmodels.RecordingRuleBackfill {
+	return &ngmodels.RecordingRuleBackfill{
+		ID:          b.ID,
+		OrgID:       b.OrgID,
+		UID:         b.UID,
+		RuleUID:     b.RuleUID,
+		From:        time.Unix(b.From, 0).UTC(),
+		To:          time.Unix(b.To, 0).UTC(),
+		Status:      ngmodels.RecordingRuleBackfillStatus(b.Status),
+		Next:        time.Unix(b.Next, 0).UTC(),
+		Evaluations: b.Evaluations,
+		Completed:   b.Completed,
+		Failed:      b.Failed,
+		LastError:   b.LastError,
+		CreatedBy:   b.CreatedBy,
+		Created:     time.Unix(b.Created, 0).UTC(),
+		Updated:     time.Unix(b.Updated, 0).UTC(),
+	}
+}
+
+// ListRecordingRuleBackfills returns the backfills ordered by creation, the oldest first.
+func (st DBstore) ListRecordingRuleBackfills(ctx context.Context, orgID int64, ruleUID string) ([]*ngmodels.RecordingRuleBackfill, error) {
+	var rows []recordingRuleBackfill
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table("alert_recording_rule_backfill")
+		if orgID > 0 {
+			q = q.Where("org_id = ?", orgID)
+		}
+		if ruleUID != "" {
+			q = q.Where("rule_uid = ?", ruleUID)
+		}
+		return q.Asc("id").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ngmodels.RecordingRuleBackfill, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.toModel())
+	}
+	return result, nil
+}
+
+// GetRecordingRuleBackfill returns the backfill, or ErrRecordingRuleBackfillNotFound if it does not exist.
+func (st DBstore) GetRecordingRuleBackfill(ctx context.Context, orgID int64, uid string) (*ngmodels.RecordingRuleBackfill, error) {
+	row := recordingRuleBackfill{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ok, err := sess.Table("alert_recording_rule_backfill").Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ngmodels.ErrRecordingRuleBackfillNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// InsertRecordingRuleBackfill creates the backfill with a new UID.
+func (st DBstore) InsertRecordingRuleBackfill(ctx context.Context, b ngmodels.RecordingRuleBackfill) (*ngmodels.RecordingRuleBackfill, error) {
+	b.UID = util.GenerateShortUID()
+	now := TimeNow().UTC().Truncate(time.Second)
+	b.Created = now
+	b.Updated = now
+	row := recordingRuleBackfillFromModel(b)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Table("alert_recording_rule_backfill").Insert(&row); err != nil {
+			return err
+		}
+		b.ID = row.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ClaimRecordingRuleBackfill sets the status of the backfill to running. The update is conditional so that only one
+// instance runs a backfill.
+func (st DBstore) ClaimRecordingRuleBackfill(ctx context.Context, orgID int64, uid string, staleBefore time.Time) (bool, error) {
+	var claimed bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE alert_recording_rule_backfill SET status = ?, updated = ? WHERE org_id = ? AND uid = ? AND (status = ? OR (status = ? AND updated < ?))",
+			string(ngmodels.BackfillRunning), TimeNow().Unix(), orgID, uid, string(ngmodels.BackfillPending), string(ngmodels.BackfillRunning), staleBefore.Unix())
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = affected > 0
+		return nil
+	})
+	return claimed, err
+}
+
+// UpdateRecordingRuleBackfillProgress saves the progress of the running backfill.
+func (st DBstore) UpdateRecordingRuleBackfillProgress(ctx context.Context, b ngmodels.RecordingRuleBackfill) (bool, error) {
+	var updated bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE alert_recording_rule_backfill SET status = ?, next_time = ?, evaluations = ?, completed = ?, failed = ?, last_error = ?, updated = ? WHERE org_id = ? AND uid = ? AND status = ?",
+			string(b.Status), b.Next.Unix(), b.Evaluations, b.Completed, b.Failed, b.LastError, TimeNow().Unix(), b.OrgID, b.UID, string(ngmodels.BackfillRunning))
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		updated = affected > 0
+		return nil
+	})
+	return updated, err
+}
+
+// SetRecordingRuleBackfillStatus changes the status of the backfill.
+func (st DBstore) SetRecordingRuleBackfillStatus(ctx context.Context, orgID int64, uid string, status ngmodels.RecordingRuleBackfillStatus, current ...ngmodels.RecordingRuleBackfillStatus) (bool, error) {
+	if len(current) == 0 {
+		return false, nil
+	}
+	var updated bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		statuses := make([]string, 0, len(current))
+		for _, s := range current {
+			statuses = append(statuses, string(s))
+		}
+		affected, err := sess.Table("alert_recording_rule_backfill").
+			Where("org_id = ? AND uid = ?", orgID, uid).
+			In("status", statuses).
+			Cols("status", "updated").
+			Update(&recordingRuleBackfill{Status: string(status), Updated: TimeNow().Unix()})
+		if err != nil {
+			return err
+		}
+		updated = affected > 0
+		return nil
+	})
+	return updated, err
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationRecordingRuleBackfills(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	to := time.Now().UTC().Truncate(time.Minute)
+	backfill := ngmodels.RecordingRuleBackfill{
+		OrgID:       1,
+		RuleUID:     "rule",
+		From:        to.Add(-time.Hour),
+		To:          to,
+		Status:      ngmodels.BackfillPending,
+		Next:        to.Add(-time.Hour),
+		Evaluations: 60,
+		CreatedBy:   "admin",
+	}
+	created, err := store.InsertRecordingRuleBackfill(ctx, backfill)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+	other := backfill
+	other.RuleUID = "other-rule"
+	_, err = store.InsertRecordingRuleBackfill(ctx, other)
+	require.NoError(t, err)
+	other.OrgID = 2
+	_, err = store.InsertRecordingRuleBackfill(ctx, other)
+	require.NoError(t, err)
+
+	got, err := store.GetRecordingRuleBackfill(ctx, 1, created.UID)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, got.ID)
+	require.Equal(t, "rule", got.RuleUID)
+	require.Equal(t, backfill.From, got.From)
+	require.Equal(t, backfill.To, got.To)
+	require.Equal(t, backfill.Next, got.Next)
+	require.Equal(t, ngmodels.BackfillPending, got.Status)
+	require.EqualValues(t, 60, got.Evaluations)
+	require.Equal(t, "admin", got.CreatedBy)
+
+	_, err = store.GetRecordingRuleBackfill(ctx, 2, created.UID)
+	require.ErrorIs(t, err, ngmodels.ErrRecordingRuleBackfillNotFound)
+
+	t.Run("lists backfills by organization and rule", func(t *testing.T) {
+		list, err := store.ListRecordingRuleBackfills(ctx, 1, "rule")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		list, err = store.ListRecordingRuleBackfills(ctx, 1, "")
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		list, err = store.ListRecordingRuleBackfills(ctx, 0, "")
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+	})
+
+	t.Run("claims pending backfill only once", func(t *testing.T) {
+		claimed, err := store.ClaimRecordingRuleBackfill(ctx, 1, created.UID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.True(t, claimed)
+		claimed, err = store.ClaimRecordingRuleBackfill(ctx, 1, created.UID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		// The running backfill is taken over when it was not updated since the given time.
+		claimed, err = store.ClaimRecordingRuleBackfill(ctx, 1, created.UID, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, claimed)
+	})
+
+	t.Run("updates progress of running backfill", func(t *testing.T) {
+		progress := *created
+		progress.Status = ngmodels.BackfillRunning
+		progress.Next = to.Add(-30 * time.Minute)
+		progress.Completed = 30
+		progress.Failed = 2
+		progress.LastError = "remote write failed"
+		updated, err := store.UpdateRecordingRuleBackfillProgress(ctx, progress)
+		require.NoError(t, err)
+		require.True(t, updated)
+
+		got, err := store.GetRecordingRuleBackfill(ctx, 1, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, ngmodels.BackfillRunning, got.Status)
+		require.Equal(t, progress.Next, got.Next)
+		require.EqualValues(t, 30, got.Completed)
+		require.EqualValues(t, 2, got.Failed)
+		require.Equal(t, "remote write failed", got.LastError)
+	})
+
+	t.Run("does not update progress of canceled backfill", func(t *testing.T) {
+		changed, err := store.SetRecordingRuleBackfillStatus(ctx, 1, created.UID, ngmodels.BackfillCanceled, ngmodels.BackfillPending, ngmodels.BackfillRunning)
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		progress := *created
+		progress.Status = ngmodels.BackfillCompleted
+		updated, err := store.UpdateRecordingRuleBackfillProgress(ctx, progress)
+		require.NoError(t, err)
+		require.False(t, updated)
+
+		got, err := store.GetRecordingRuleBackfill(ctx, 1, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, ngmodels.BackfillCanceled, got.Status)
+	})
+
+	t.Run("changes status only from the given ones", func(t *testing.T) {
+		changed, err := store.SetRecordingRuleBackfillStatus(ctx, 1, created.UID, ngmodels.BackfillCanceled, ngmodels.BackfillPending, ngmodels.BackfillRunning)
+		require.NoError(t, err)
+		require.False(t, changed)
+
+		changed, err = store.SetRecordingRuleBackfillStatus(ctx, 1, created.UID, ngmodels.BackfillPending, ngmodels.BackfillFailed, ngmodels.BackfillCanceled)
+		require.NoError(t, err)
+		require.True(t, changed)
+	})
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var (
+	// ErrNoRulePolicy is an error for when an organization has no rule policy.
+	ErrNoRulePolicy = errors.New("no rule policy available")
+)
+
+// RulePolicyStore is the database interface of the alert rule policies of the organizations.
+type RulePolicyStore interface {
+	GetRulePolicy(ctx context.Context, orgID int64) (*ngmodels.RulePolicy, error)
+	SaveRulePolicy(ctx context.Context, orgID int64, policy ngmodels.RulePolicy) error
+	DeleteRulePolicy(ctx context.Context, orgID int64) error
+}
+
+type rulePolicy struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	Policy    string `xorm:"policy"`
+	CreatedAt int64  `xorm:"created_at"`
+	UpdatedAt int64  `xorm:"updated_at"`
+}
+
+// GetRulePolicy returns the rule policy of the organization, or ErrNoRulePolicy if it has none.
+func (st DBstore) GetRulePolicy(ctx context.Context, orgID int64) (*ngmodels.RulePolicy, error) {
+	row := rulePolicy{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ok, err := sess.Table("alert_rule_policy").Where("org_id = ?", orgID).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNoRulePolicy
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &ngmodels.RulePolicy{}
+	if err := json.Unmarshal([]byte(row.Policy), policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule policy: %w", err)
+	}
+	return policy, nil
+}
+
+// SaveRulePolicy creates or replaces the rule policy of the organization.
+func (st DBstore) SaveRulePolicy(ctx context.Context, orgID int64, policy ngmodels.RulePolicy) error {
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule policy: %w", err)
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := rulePolicy{}
+		has, err := sess.Table("alert_rule_policy").Where("org_id = ?", orgID).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		now := TimeNow().Unix()
+		if !has {
+			_, err := sess.Table("alert_rule_policy").Insert(&rulePolicy{
+				OrgID:     orgID,
+				Policy:    string(raw),
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			return err
+		}
+		existing.Policy = string(raw)
+		existing.UpdatedAt = now
+		_, err = sess.Table("alert_rule_policy").ID(existing.ID).Cols("policy", "updated_at").Update(&existing)
+		return err
+	})
+}
+
+// DeleteRulePolicy deletes the rule policy of the organization, if any.
+func (st DBstore) DeleteRulePolicy(ctx context.Context, orgID int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_rule_policy WHERE org_id = ?", orgID)
+		return err
+	})
+}
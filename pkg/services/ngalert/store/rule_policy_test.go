@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationRulePolicy(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	_, err := store.GetRulePolicy(ctx, 1)
+	require.ErrorIs(t, err, ErrNoRulePolicy)
+
+	policy := ngmodels.RulePolicy{
+		Mode:               ngmodels.RulePolicyModeReject,
+		RequiredLabels:     []string{"team", "severity"},
+		LabelValuePatterns: map[string]string{"severity": "critical|warning"},
+		MaxFor:             time.Hour,
+	}
+	require.NoError(t, store.SaveRulePolicy(ctx, 1, policy))
+	require.NoError(t, store.SaveRulePolicy(ctx, 2, ngmodels.RulePolicy{Mode: ngmodels.RulePolicyModeWarn}))
+
+	got, err := store.GetRulePolicy(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, policy, *got)
+
+	policy.Mode = ngmodels.RulePolicyModeWarn
+	require.NoError(t, store.SaveRulePolicy(ctx, 1, policy))
+	got, err = store.GetRulePolicy(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, policy, *got)
+
+	require.NoError(t, store.DeleteRulePolicy(ctx, 1))
+	_, err = store.GetRulePolicy(ctx, 1)
+	require.ErrorIs(t, err, ErrNoRulePolicy)
+
+	got, err = store.GetRulePolicy(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, ngmodels.RulePolicyModeWarn, got.Mode)
+}
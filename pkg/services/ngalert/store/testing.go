@@ -1,7 +1,9 @@
 package store
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -14,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func NewFakeImageStore(t *testing.T, images ...*models.Image) *FakeImageStore {
@@ -136,6 +139,234 @@ func (f *FakeAdminConfigStore) UpdateAdminConfiguration(cmd UpdateAdminConfigura
 	return nil
 }
 
+func NewFakeRulePolicyStore(t *testing.T) *FakeRulePolicyStore {
+	t.Helper()
+	return &FakeRulePolicyStore{Policies: map[int64]models.RulePolicy{}}
+}
+
+type FakeRulePolicyStore struct {
+	mtx      sync.Mutex
+	Policies map[int64]models.RulePolicy
+}
+
+func (f *FakeRulePolicyStore) GetRulePolicy(_ context.Context, orgID int64) (*models.RulePolicy, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	policy, ok := f.Policies[orgID]
+	if !ok {
+		return nil, ErrNoRulePolicy
+	}
+	return &policy, nil
+}
+
+func (f *FakeRulePolicyStore) SaveRulePolicy(_ context.Context, orgID int64, policy models.RulePolicy) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Policies[orgID] = policy
+	return nil
+}
+
+func (f *FakeRulePolicyStore) DeleteRulePolicy(_ context.Context, orgID int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.Policies, orgID)
+	return nil
+}
+
+func NewFakeMaintenanceWindowStore(t *testing.T) *FakeMaintenanceWindowStore {
+	t.Helper()
+	return &FakeMaintenanceWindowStore{Windows: map[string]*models.MaintenanceWindow{}}
+}
+
+// FakeMaintenanceWindowStore stores maintenance windows in memory. Windows are keyed by UID for all organizations.
+type FakeMaintenanceWindowStore struct {
+	mtx     sync.Mutex
+	Windows map[string]*models.MaintenanceWindow
+}
+
+func (f *FakeMaintenanceWindowStore) ListMaintenanceWindows(_ context.Context, orgID int64) ([]*models.MaintenanceWindow, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]*models.MaintenanceWindow, 0, len(f.Windows))
+	for _, w := range f.Windows {
+		if orgID == 0 || w.OrgID == orgID {
+			cp := *w
+			result = append(result, &cp)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeMaintenanceWindowStore) GetMaintenanceWindow(_ context.Context, orgID int64, uid string) (*models.MaintenanceWindow, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	w, ok := f.Windows[uid]
+	if !ok || w.OrgID != orgID {
+		return nil, models.ErrMaintenanceWindowNotFound
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (f *FakeMaintenanceWindowStore) InsertMaintenanceWindow(_ context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if w.UID == "" {
+		w.UID = util.GenerateShortUID()
+	}
+	w.ID = int64(len(f.Windows) + 1)
+	w.Created = TimeNow()
+	w.Updated = w.Created
+	w.UpdatedBy = w.CreatedBy
+	f.Windows[w.UID] = &w
+	cp := w
+	return &cp, nil
+}
+
+func (f *FakeMaintenanceWindowStore) UpdateMaintenanceWindow(_ context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	existing, ok := f.Windows[w.UID]
+	if !ok || existing.OrgID != w.OrgID {
+		return nil, models.ErrMaintenanceWindowNotFound
+	}
+	w.ID = existing.ID
+	w.CreatedBy = existing.CreatedBy
+	w.Created = existing.Created
+	w.Updated = TimeNow()
+	w.LastOccurrence = time.Time{}
+	w.SilenceID = ""
+	f.Windows[w.UID] = &w
+	cp := w
+	return &cp, nil
+}
+
+func (f *FakeMaintenanceWindowStore) DeleteMaintenanceWindow(_ context.Context, orgID int64, uid string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	w, ok := f.Windows[uid]
+	if !ok || w.OrgID != orgID {
+		return models.ErrMaintenanceWindowNotFound
+	}
+	delete(f.Windows, uid)
+	return nil
+}
+
+func (f *FakeMaintenanceWindowStore) ClaimMaintenanceWindowOccurrence(_ context.Context, orgID int64, uid string, occurrence time.Time) (bool, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	w, ok := f.Windows[uid]
+	if !ok || w.OrgID != orgID || !w.LastOccurrence.Before(occurrence) {
+		return false, nil
+	}
+	w.LastOccurrence = occurrence
+	w.SilenceID = ""
+	return true, nil
+}
+
+func (f *FakeMaintenanceWindowStore) SetMaintenanceWindowSilence(_ context.Context, orgID int64, uid string, silenceID string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if w, ok := f.Windows[uid]; ok && w.OrgID == orgID {
+		w.SilenceID = silenceID
+	}
+	return nil
+}
+
+func NewFakeRecordingRuleBackfillStore(t *testing.T) *FakeRecordingRuleBackfillStore {
+	t.Helper()
+	return &FakeRecordingRuleBackfillStore{Backfills: map[string]*models.RecordingRuleBackfill{}}
+}
+
+// FakeRecordingRuleBackfillStore stores backfills in memory. Backfills are keyed by UID for all organizations.
+type FakeRecordingRuleBackfillStore struct {
+	mtx       sync.Mutex
+	Backfills map[string]*models.RecordingRuleBackfill
+}
+
+func (f *FakeRecordingRuleBackfillStore) ListRecordingRuleBackfills(_ context.Context, orgID int64, ruleUID string) ([]*models.RecordingRuleBackfill, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]*models.RecordingRuleBackfill, 0, len(f.Backfills))
+	for _, b := range f.Backfills {
+		if (orgID == 0 || b.OrgID == orgID) && (ruleUID == "" || b.RuleUID == ruleUID) {
+			cp := *b
+			result = append(result, &cp)
+		}
+	}
+	slices.SortFunc(result, func(a, b *models.RecordingRuleBackfill) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return result, nil
+}
+
+func (f *FakeRecordingRuleBackfillStore) GetRecordingRuleBackfill(_ context.Context, orgID int64, uid string) (*models.RecordingRuleBackfill, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	b, ok := f.Backfills[uid]
+	if !ok || b.OrgID != orgID {
+		return nil, models.ErrRecordingRuleBackfillNotFound
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (f *FakeRecordingRuleBackfillStore) InsertRecordingRuleBackfill(_ context.Context, b models.RecordingRuleBackfill) (*models.RecordingRuleBackfill, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	b.UID = util.GenerateShortUID()
+	b.ID = int64(len(f.Backfills) + 1)
+	b.Created = TimeNow()
+	b.Updated = b.Created
+	f.Backfills[b.UID] = &b
+	cp := b
+	return &cp, nil
+}
+
+func (f *FakeRecordingRuleBackfillStore) ClaimRecordingRuleBackfill(_ context.Context, orgID int64, uid string, staleBefore time.Time) (bool, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	b, ok := f.Backfills[uid]
+	if !ok || b.OrgID != orgID {
+		return false, nil
+	}
+	if b.Status != models.BackfillPending && (b.Status != models.BackfillRunning || !b.Updated.Before(staleBefore)) {
+		return false, nil
+	}
+	b.Status = models.BackfillRunning
+	b.Updated = TimeNow()
+	return true, nil
+}
+
+func (f *FakeRecordingRuleBackfillStore) UpdateRecordingRuleBackfillProgress(_ context.Context, b models.RecordingRuleBackfill) (bool, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	existing, ok := f.Backfills[b.UID]
+	if !ok || existing.OrgID != b.OrgID || existing.Status != models.BackfillRunning {
+		return false, nil
+	}
+	existing.Status = b.Status
+	existing.Next = b.Next
+	existing.Evaluations = b.Evaluations
+	existing.Completed = b.Completed
+	existing.Failed = b.Failed
+	existing.LastError = b.LastError
+	existing.Updated = TimeNow()
+	return true, nil
+}
+
+func (f *FakeRecordingRuleBackfillStore) SetRecordingRuleBackfillStatus(_ context.Context, orgID int64, uid string, status models.RecordingRuleBackfillStatus, current ...models.RecordingRuleBackfillStatus) (bool, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	b, ok := f.Backfills[uid]
+	if !ok || b.OrgID != orgID || !slices.Contains(current, b.Status) {
+		return false, nil
+	}
+	b.Status = status
+	b.Updated = TimeNow()
+	return true, nil
+}
+
 func SetupStoreForTesting(t *testing.T, db db.DB) *DBstore {
 	t.Helper()
 	cfg := setting.NewCfg()
@@ -368,6 +368,9 @@ func (f *RuleStore) listAlertRules(q *models.ListAlertRulesQuery) (models.RulesG
 		if len(q.RuleUIDs) > 0 && !slices.Contains(q.RuleUIDs, r.UID) {
 			continue
 		}
+		if len(q.DependsOnRuleUIDs) > 0 && !r.DependsOnAny(q.DependsOnRuleUIDs) {
+			continue
+		}
 		if q.HasPrometheusRuleDefinition != nil {
 			if *q.HasPrometheusRuleDefinition != r.HasPrometheusRuleDefinition() {
 				continue
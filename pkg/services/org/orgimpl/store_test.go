@@ -123,8 +123,31 @@ func TestIntegrationOrgDataAccess(t *testing.T) {
 		require.NoError(t, err)
 		t.Logf("remove: %d\n", orgId)
 
+		// the state history of alert rules is kept in the database by the database history backend
+		insertStateHistory := "INSERT INTO alert_state_history (org_id, rule_uid, rule_version, folder_uid, rule_group, previous_state, current_state, entry, transitioned_at) VALUES (?, 'rule', 1, 'folder', 'group', 'Normal', 'Alerting', '{}', 1)"
+		countStateHistory := func(orgID int64) int64 {
+			var count int64
+			err := ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+				_, err := sess.SQL("SELECT COUNT(*) FROM alert_state_history WHERE org_id = ?", orgID).Get(&count)
+				return err
+			})
+			require.NoError(t, err)
+			return count
+		}
+		err = ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+			for _, orgID := range []int64{ac2.ID, ac2.ID + 1} {
+				if _, err := sess.Exec(insertStateHistory, orgID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
 		err = orgStore.Delete(context.Background(), &org.DeleteOrgCommand{ID: ac2.ID})
 		require.NoError(t, err)
+		require.Zero(t, countStateHistory(ac2.ID))
+		require.EqualValues(t, 1, countStateHistory(ac2.ID+1))
 
 		// TODO: this part of the test will be added when we move RemoveOrgUser to org store
 		// "Removing user from org should delete user completely if in no other org"
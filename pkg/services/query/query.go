@@ -251,6 +251,7 @@ func QueryData(ctx context.Context, log log.Logger, dscache datasources.CacheSer
 func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Requester, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	exprReq := expr.Request{
 		Queries: []expr.Query{},
+		Explain: parsedReq.explain,
 	}
 
 	if user != nil { // for passthrough authentication, SSE does not authenticate
@@ -393,6 +394,7 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user identity.Requ
 
 	req := &parsedRequest{
 		hasExpression: false,
+		explain:       reqDTO.Explain,
 		parsedQueries: make(map[string][]parsedQuery),
 		dsTypes:       make(map[string]bool),
 	}
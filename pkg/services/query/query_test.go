@@ -877,6 +877,8 @@ func setup(t *testing.T, isMultiTenant bool, mockClient clientapi.QueryDataClien
 		nil,
 		tracing.InitializeTracerForTest(),
 		qsdsClientBuilder,
+		nil, // library
+		nil, // ruleStates
 	)
 
 	queryService := ProvideService(
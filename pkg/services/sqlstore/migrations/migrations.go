@@ -187,5 +187,17 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 
 	ualert.AddAlertRuleStateBigIntMigration(mg)
 
+	ualert.AddAlertStateHistoryTable(mg)
+
+	ualert.AddAlertRulePolicyTable(mg)
+
+	ualert.AddAlertMaintenanceWindowTable(mg)
+
+	ualert.AddAlertAcknowledgementTable(mg)
+
+	ualert.AddAlertRecordingRuleBackfillTable(mg)
+
+	ualert.AddAlertClusterPeerTables(mg)
+
 	mg.AddObsoleteMigration(obsolete.PlaylistMigrations())
 }
@@ -0,0 +1,30 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertAcknowledgementTable adds a table to store the acknowledgements of firing alert instances.
+func AddAlertAcknowledgementTable(mg *migrator.Migrator) {
+	acknowledgementTable := migrator.Table{
+		Name: "alert_acknowledgement",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "rule_org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			// The hash of the labels of the alert instance, as in the alert_instance table.
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "acknowledged_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "assignee", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "note", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+			// The expiration time in Unix seconds, or 0 if the acknowledgement lasts until the alert stops firing.
+			{Name: "expires_at", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"rule_org_id", "rule_uid", "labels_hash"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add alert_acknowledgement table", migrator.NewAddTableMigration(acknowledgementTable))
+	mg.AddMigration("add unique index to alert_acknowledgement on rule_org_id, rule_uid and labels_hash columns",
+		migrator.NewAddIndexMigration(acknowledgementTable, acknowledgementTable.Indices[0]))
+}
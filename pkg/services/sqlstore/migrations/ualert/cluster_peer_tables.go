@@ -0,0 +1,42 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertClusterPeerTables adds the tables that the Alertmanager uses for high availability when it is clustered
+// through the database: the heartbeats of the members of the cluster and the state messages they exchange.
+func AddAlertClusterPeerTables(mg *migrator.Migrator) {
+	peerTable := migrator.Table{
+		Name: "alert_cluster_peer",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			// The time of the last heartbeat, in Unix seconds.
+			{Name: "heartbeat", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"name"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add alert_cluster_peer table", migrator.NewAddTableMigration(peerTable))
+	mg.AddMigration("add unique index to alert_cluster_peer on name column",
+		migrator.NewAddIndexMigration(peerTable, peerTable.Indices[0]))
+
+	messageTable := migrator.Table{
+		Name: "alert_cluster_message",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "sender", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "channel", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "payload", Type: migrator.DB_LongBlob, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("add alert_cluster_message table", migrator.NewAddTableMigration(messageTable))
+	mg.AddMigration("add index to alert_cluster_message on created column",
+		migrator.NewAddIndexMigration(messageTable, messageTable.Indices[0]))
+}
@@ -0,0 +1,36 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertMaintenanceWindowTable adds a table to store the recurring maintenance windows that create silences.
+func AddAlertMaintenanceWindowTable(mg *migrator.Migrator) {
+	maintenanceWindowTable := migrator.Table{
+		Name: "alert_maintenance_window",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "schedule", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "timezone", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false}, // Seconds.
+			// The matchers as a JSON array of strings.
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false},
+			{Name: "pause_rule_evaluation", Type: migrator.DB_Bool, Nullable: false, Default: "0"},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "updated_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_BigInt, Nullable: false},
+			// The start of the last occurrence a silence was created for, in Unix seconds.
+			{Name: "last_occurrence", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "silence_id", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false, Default: "''"},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add alert_maintenance_window table", migrator.NewAddTableMigration(maintenanceWindowTable))
+	mg.AddMigration("add unique index to alert_maintenance_window on org_id and uid columns",
+		migrator.NewAddIndexMigration(maintenanceWindowTable, maintenanceWindowTable.Indices[0]))
+}
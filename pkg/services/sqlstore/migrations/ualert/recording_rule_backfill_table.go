@@ -0,0 +1,35 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertRecordingRuleBackfillTable adds a table to store the backfills of recording rules and their progress.
+func AddAlertRecordingRuleBackfillTable(mg *migrator.Migrator) {
+	backfillTable := migrator.Table{
+		Name: "alert_recording_rule_backfill",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			// The time range and the time of the next evaluation, in Unix seconds.
+			{Name: "from_time", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "to_time", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "next_time", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "evaluations", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "completed", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "failed", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "last_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add alert_recording_rule_backfill table", migrator.NewAddTableMigration(backfillTable))
+	mg.AddMigration("add unique index to alert_recording_rule_backfill on org_id and uid columns",
+		migrator.NewAddIndexMigration(backfillTable, backfillTable.Indices[0]))
+}
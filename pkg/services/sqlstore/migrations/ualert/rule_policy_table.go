@@ -0,0 +1,25 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertRulePolicyTable adds a table to store the alert rule policy of each organization.
+func AddAlertRulePolicyTable(mg *migrator.Migrator) {
+	rulePolicyTable := migrator.Table{
+		Name: "alert_rule_policy",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			// The policy as JSON.
+			{Name: "policy", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add alert_rule_policy table", migrator.NewAddTableMigration(rulePolicyTable))
+	mg.AddMigration("add unique index to alert_rule_policy on org_id column",
+		migrator.NewAddIndexMigration(rulePolicyTable, rulePolicyTable.Indices[0]))
+}
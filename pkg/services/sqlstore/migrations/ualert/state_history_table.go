@@ -0,0 +1,35 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertStateHistoryTable adds a table to store alert state transitions for the database state history backend.
+func AddAlertStateHistoryTable(mg *migrator.Migrator) {
+	stateHistoryTable := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: true},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			// The transition in the same JSON format as the entries written to Loki, including labels and values.
+			{Name: "entry", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "transitioned_at", Type: migrator.DB_BigInt, Nullable: false}, // Unix nanoseconds.
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "transitioned_at"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "rule_uid", "transitioned_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("add alert_state_history table", migrator.NewAddTableMigration(stateHistoryTable))
+	mg.AddMigration("add index to alert_state_history on org_id and transitioned_at columns",
+		migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[0]))
+	mg.AddMigration("add index to alert_state_history on org_id, rule_uid and transitioned_at columns",
+		migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[1]))
+}
@@ -27,6 +27,8 @@ const (
 	alertmanagerDefaultConfigPollInterval = time.Minute
 	AlertBroadcastDefaultQueueSize        = 200
 	alertmanagerRedisDefaultMaxConns      = 5
+	alertmanagerDatabaseDefaultPoll       = time.Second
+	alertmanagerDatabaseDefaultHeartbeat  = 5 * time.Second
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -56,6 +58,8 @@ const (
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultRenderer              = ScreenshotsRendererDashboard
+	screenshotsDefaultChartFormat           = ScreenshotsChartFormatPNG
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -69,11 +73,32 @@ const (
 	lokiDefaultMaxQuerySize                = 65536 // 64kb
 	defaultHistorianPrometheusWriteTimeout = 10 * time.Second
 	defaultHistorianPrometheusMetricName   = "GRAFANA_ALERTS"
+	defaultHistorianDatabaseRetention      = 30 * 24 * time.Hour
+	defaultHistorianWebhookMaxRetries      = 3
+	defaultHistorianWebhookBatchSize       = 1000
+	defaultHistorianWebhookFlushInterval   = 5 * time.Second
+	defaultHistorianFileMaxSize            = 100 * 1024 * 1024 // 100MB
+	defaultHistorianFileMaxFiles           = 5
 )
 
 var (
 	errHARedisBothClusterAndSentinel     = fmt.Errorf("'ha_redis_cluster_mode_enabled' and 'ha_redis_sentinel_mode_enabled' are mutually exclusive")
 	errHARedisSentinelMasterNameRequired = fmt.Errorf("'ha_redis_sentinel_master_name' is required when 'ha_redis_sentinel_mode_enabled' is true")
+	errHABothSingleNodeAndSharding       = fmt.Errorf("'ha_single_node_evaluation' and 'ha_evaluation_sharding' are mutually exclusive")
+	errHADatabaseWithOtherBackend        = fmt.Errorf("'ha_database_enabled' cannot be used together with 'ha_redis_address' or 'ha_peers'")
+	errHADatabaseInvalidInterval         = fmt.Errorf("'ha_database_poll_interval' and 'ha_database_heartbeat_interval' must be greater than zero")
+	errScreenshotsInvalidRenderer        = fmt.Errorf("'renderer' must be either %q or %q", ScreenshotsRendererDashboard, ScreenshotsRendererChart)
+	errScreenshotsInvalidChartFormat     = fmt.Errorf("'chart_format' must be either %q or %q", ScreenshotsChartFormatPNG, ScreenshotsChartFormatSVG)
+)
+
+const (
+	// ScreenshotsRendererDashboard takes screenshots of the dashboard panel of the alert rule with the image renderer.
+	ScreenshotsRendererDashboard = "dashboard"
+	// ScreenshotsRendererChart draws a chart of the queries of the alert rule from the results of the evaluation.
+	ScreenshotsRendererChart = "chart"
+
+	ScreenshotsChartFormatPNG = "png"
+	ScreenshotsChartFormatSVG = "svg"
 )
 
 type UnifiedAlertingSettings struct {
@@ -103,8 +128,13 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                           int
 	HARedisTLSEnabled                         bool
 	HARedisTLSConfig                          dstls.ClientConfig
+	HADatabaseEnabled                         bool
+	HADatabasePeerName                        string
+	HADatabasePollInterval                    time.Duration
+	HADatabaseHeartbeatInterval               time.Duration
 	HASingleNodeEvaluation                    bool
 	HASingleEvaluationAlertBroadcastQueueSize int
+	HAEvaluationSharding                      bool
 	InitializationTimeout                     time.Duration
 	MaxAttempts                               int64
 	InitialRetryDelay                         time.Duration
@@ -177,6 +207,10 @@ type RecordingRuleSettings struct {
 	CustomHeaders        map[string]string
 	Timeout              time.Duration
 	DefaultDatasourceUID string
+	// BackfillWritesPerSecond limits the writes of the backfills of recording rules of each Grafana instance.
+	BackfillWritesPerSecond float64
+	// BackfillMaxEvaluations is the maximum number of evaluations of a backfill.
+	BackfillMaxEvaluations int
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -194,6 +228,10 @@ type UnifiedAlertingScreenshotSettings struct {
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	// Renderer is either ScreenshotsRendererDashboard or ScreenshotsRendererChart.
+	Renderer string
+	// ChartFormat is the image format of the charts, either ScreenshotsChartFormatPNG or ScreenshotsChartFormatSVG.
+	ChartFormat string
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
@@ -229,6 +267,15 @@ type UnifiedAlertingStateHistorySettings struct {
 	PrometheusMetricName          string
 	PrometheusTargetDatasourceUID string
 	PrometheusWriteTimeout        time.Duration
+	DatabaseRetention             time.Duration
+	WebhookURL                    string
+	WebhookSecret                 string
+	WebhookMaxRetries             int
+	WebhookBatchSize              int
+	WebhookFlushInterval          time.Duration
+	FilePath                      string
+	FileMaxSize                   int64
+	FileMaxFiles                  int
 	MultiPrimary                  string
 	MultiSecondaries              []string
 	ExternalLabels                map[string]string
@@ -372,8 +419,30 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisTLSConfig.InsecureSkipVerify = ua.Key("ha_redis_tls_insecure_skip_verify").MustBool(false)
 	uaCfg.HARedisTLSConfig.CipherSuites = ua.Key("ha_redis_tls_cipher_suites").MustString("")
 	uaCfg.HARedisTLSConfig.MinVersion = ua.Key("ha_redis_tls_min_version").MustString("")
+	uaCfg.HADatabaseEnabled = ua.Key("ha_database_enabled").MustBool(false)
+	uaCfg.HADatabasePeerName = ua.Key("ha_database_peer_name").MustString("")
+	uaCfg.HADatabasePollInterval, err = gtime.ParseDuration(valueAsString(ua, "ha_database_poll_interval", (alertmanagerDatabaseDefaultPoll).String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.HADatabaseHeartbeatInterval, err = gtime.ParseDuration(valueAsString(ua, "ha_database_heartbeat_interval", (alertmanagerDatabaseDefaultHeartbeat).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.HADatabaseEnabled {
+		if uaCfg.HARedisAddr != "" || len(uaCfg.HAPeers) > 0 {
+			return errHADatabaseWithOtherBackend
+		}
+		if uaCfg.HADatabasePollInterval <= 0 || uaCfg.HADatabaseHeartbeatInterval <= 0 {
+			return errHADatabaseInvalidInterval
+		}
+	}
 	uaCfg.HASingleNodeEvaluation = ua.Key("ha_single_node_evaluation").MustBool(false)
 	uaCfg.HASingleEvaluationAlertBroadcastQueueSize = ua.Key("ha_single_evaluation_alert_broadcast_queue_size").MustInt(AlertBroadcastDefaultQueueSize)
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
+	if uaCfg.HASingleNodeEvaluation && uaCfg.HAEvaluationSharding {
+		return errHABothSingleNodeAndSharding
+	}
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -511,6 +580,15 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+
+	uaCfgScreenshots.Renderer = valueAsString(screenshots, "renderer", screenshotsDefaultRenderer)
+	if uaCfgScreenshots.Renderer != ScreenshotsRendererDashboard && uaCfgScreenshots.Renderer != ScreenshotsRendererChart {
+		return errScreenshotsInvalidRenderer
+	}
+	uaCfgScreenshots.ChartFormat = valueAsString(screenshots, "chart_format", screenshotsDefaultChartFormat)
+	if uaCfgScreenshots.ChartFormat != ScreenshotsChartFormatPNG && uaCfgScreenshots.ChartFormat != ScreenshotsChartFormatSVG {
+		return errScreenshotsInvalidChartFormat
+	}
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -542,6 +620,15 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		PrometheusMetricName:          stateHistory.Key("prometheus_metric_name").MustString(defaultHistorianPrometheusMetricName),
 		PrometheusTargetDatasourceUID: stateHistory.Key("prometheus_target_datasource_uid").MustString(""),
 		PrometheusWriteTimeout:        stateHistory.Key("prometheus_write_timeout").MustDuration(defaultHistorianPrometheusWriteTimeout),
+		DatabaseRetention:             stateHistory.Key("database_retention").MustDuration(defaultHistorianDatabaseRetention),
+		WebhookURL:                    stateHistory.Key("webhook_url").MustString(""),
+		WebhookSecret:                 stateHistory.Key("webhook_secret").MustString(""),
+		WebhookMaxRetries:             stateHistory.Key("webhook_max_retries").MustInt(defaultHistorianWebhookMaxRetries),
+		WebhookBatchSize:              stateHistory.Key("webhook_batch_size").MustInt(defaultHistorianWebhookBatchSize),
+		WebhookFlushInterval:          stateHistory.Key("webhook_flush_interval").MustDuration(defaultHistorianWebhookFlushInterval),
+		FilePath:                      stateHistory.Key("file_path").MustString(""),
+		FileMaxSize:                   stateHistory.Key("file_max_size").MustInt64(defaultHistorianFileMaxSize),
+		FileMaxFiles:                  stateHistory.Key("file_max_files").MustInt(defaultHistorianFileMaxFiles),
 		ExternalLabels:                stateHistoryLabels.KeysHash(),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
@@ -575,6 +662,9 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		Enabled:              rr.Key("enabled").MustBool(true),
 		Timeout:              rr.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
 		DefaultDatasourceUID: rr.Key("default_datasource_uid").MustString(""),
+
+		BackfillWritesPerSecond: rr.Key("backfill_writes_per_second").MustFloat64(10),
+		BackfillMaxEvaluations:  rr.Key("backfill_max_evaluations").MustInt(100000),
 	}
 
 	rrHeaders := iniFile.Section("recording_rules.custom_headers")
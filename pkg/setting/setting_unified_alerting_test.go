@@ -421,6 +421,78 @@ func TestHARedisSentinelModeSettings(t *testing.T) {
 	}
 }
 
+func TestHAEvaluationShardingSettings(t *testing.T) {
+	f := ini.Empty()
+	section, err := f.NewSection("unified_alerting")
+	require.NoError(t, err)
+	_, err = section.NewKey("ha_evaluation_sharding", "true")
+	require.NoError(t, err)
+
+	cfg := NewCfg()
+	require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+	require.True(t, cfg.UnifiedAlerting.HAEvaluationSharding)
+
+	_, err = section.NewKey("ha_single_node_evaluation", "true")
+	require.NoError(t, err)
+	cfg = NewCfg()
+	require.ErrorIs(t, cfg.ReadUnifiedAlertingSettings(f), errHABothSingleNodeAndSharding)
+}
+
+func TestHADatabaseSettings(t *testing.T) {
+	f := ini.Empty()
+	section, err := f.NewSection("unified_alerting")
+	require.NoError(t, err)
+	_, err = section.NewKey("ha_database_enabled", "true")
+	require.NoError(t, err)
+
+	cfg := NewCfg()
+	require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+	require.True(t, cfg.UnifiedAlerting.HADatabaseEnabled)
+	require.Equal(t, alertmanagerDatabaseDefaultPoll, cfg.UnifiedAlerting.HADatabasePollInterval)
+	require.Equal(t, alertmanagerDatabaseDefaultHeartbeat, cfg.UnifiedAlerting.HADatabaseHeartbeatInterval)
+
+	_, err = section.NewKey("ha_database_poll_interval", "0s")
+	require.NoError(t, err)
+	cfg = NewCfg()
+	require.ErrorIs(t, cfg.ReadUnifiedAlertingSettings(f), errHADatabaseInvalidInterval)
+
+	_, err = section.NewKey("ha_database_poll_interval", "2s")
+	require.NoError(t, err)
+	_, err = section.NewKey("ha_redis_address", "localhost:6379")
+	require.NoError(t, err)
+	cfg = NewCfg()
+	require.ErrorIs(t, cfg.ReadUnifiedAlertingSettings(f), errHADatabaseWithOtherBackend)
+}
+
+func TestScreenshotsRendererSettings(t *testing.T) {
+	f := ini.Empty()
+	cfg := NewCfg()
+	require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+	require.Equal(t, ScreenshotsRendererDashboard, cfg.UnifiedAlerting.Screenshots.Renderer)
+	require.Equal(t, ScreenshotsChartFormatPNG, cfg.UnifiedAlerting.Screenshots.ChartFormat)
+
+	section, err := f.NewSection("unified_alerting.screenshots")
+	require.NoError(t, err)
+	_, err = section.NewKey("renderer", "chart")
+	require.NoError(t, err)
+	_, err = section.NewKey("chart_format", "svg")
+	require.NoError(t, err)
+	cfg = NewCfg()
+	require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+	require.Equal(t, ScreenshotsRendererChart, cfg.UnifiedAlerting.Screenshots.Renderer)
+	require.Equal(t, ScreenshotsChartFormatSVG, cfg.UnifiedAlerting.Screenshots.ChartFormat)
+
+	_, err = section.NewKey("chart_format", "jpeg")
+	require.NoError(t, err)
+	cfg = NewCfg()
+	require.ErrorIs(t, cfg.ReadUnifiedAlertingSettings(f), errScreenshotsInvalidChartFormat)
+
+	_, err = section.NewKey("renderer", "browser")
+	require.NoError(t, err)
+	cfg = NewCfg()
+	require.ErrorIs(t, cfg.ReadUnifiedAlertingSettings(f), errScreenshotsInvalidRenderer)
+}
+
 func TestReadAllowedIntegrations(t *testing.T) {
 	testCases := []struct {
 		name    string
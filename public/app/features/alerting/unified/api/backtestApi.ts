@@ -1,5 +1,10 @@
 import { type DataFrameJSON } from '@grafana/data';
-import { type AlertQuery, type GrafanaAlertStateDecision, type Labels } from 'app/types/unified-alerting-dto';
+import {
+  type AlertQuery,
+  type GrafanaAlertStateDecision,
+  type GrafanaNotificationSettings,
+  type Labels,
+} from 'app/types/unified-alerting-dto';
 
 import { alertingApi } from './alertingApi';
 
@@ -31,6 +36,15 @@ interface BacktestRequest {
   uid?: string;
   rule_group?: string;
   namespace_uid?: string;
+
+  // Optional simplified routing of the notifications
+  notification_settings?: GrafanaNotificationSettings;
+
+  // Optional stored version of the rule with the uid to compare the result to
+  compare_to_version?: number;
+
+  // Optional replay of the state history of the rule with the uid instead of evaluating the queries
+  replay_history?: boolean;
 }
 
 const BACKTEST_URL = '/api/v1/rule/backtest';
@@ -13,14 +13,16 @@ interface HistoryProps {
 }
 
 const History = ({ rule }: HistoryProps) => {
-  // can be "loki", "multiple" or "annotations"
+  // can be "loki", "database", "multiple" or "annotations"
   const stateHistoryBackend = config.unifiedAlerting.stateHistory?.backend;
-  // can be "loki" or "annotations"
+  // can be "loki", "database" or "annotations"
   const stateHistoryPrimary = config.unifiedAlerting.stateHistory?.primary;
 
-  // if "loki" is either the backend or the primary, show the new state history implementation
+  // if "loki" or "database" is either the backend or the primary, show the new state history implementation,
+  // the database backend returns the same history as Loki
   const usingNewAlertStateHistory = [stateHistoryBackend, stateHistoryPrimary].some(
-    (implementation) => implementation === StateHistoryImplementation.Loki
+    (implementation) =>
+      implementation === StateHistoryImplementation.Loki || implementation === StateHistoryImplementation.Database
   );
   const implementation = usingNewAlertStateHistory
     ? StateHistoryImplementation.Loki
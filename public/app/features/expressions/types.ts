@@ -83,6 +83,20 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'stddev', label: 'StdDev', description: 'Get the standard deviation of all values' },
+  { value: ReducerID.variance, label: 'Variance', description: 'Get the variance of all values' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and minimum values' },
+  { value: ReducerID.diff, label: 'Difference', description: 'Get the difference between the last and first values' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of values that are not null or NaN' },
+  {
+    value: 'rate',
+    label: 'Rate per second',
+    description: 'Get the difference between the last and first values divided by the seconds between them',
+  },
+  { value: ReducerID.p90, label: '90th percentile', description: 'Get the 90th percentile value' },
+  { value: ReducerID.p95, label: '95th percentile', description: 'Get the 95th percentile value' },
+  { value: ReducerID.p99, label: '99th percentile', description: 'Get the 99th percentile value' },
 ];
 
 export enum ReducerMode {
@@ -121,6 +135,12 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'Interpolate linearly between the surrounding values' },
+  {
+    value: 'time_weighted',
+    label: 'time_weighted',
+    description: 'Fill with the time weighted average of the interpolated values over the window',
+  },
 ];
 
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
@@ -158,6 +178,10 @@ export interface SqlExpressionQuery extends ExpressionQuery {
 
 export interface ThresholdExpressionQuery extends ExpressionQuery {
   conditions: ClassicCondition[];
+  /** The label that holds the severity level of the alert instance, defaults to `severity` */
+  severityLabel?: string;
+  /** The number of consecutive evaluations the recovery threshold of a severity level must be met */
+  recoveryEvaluations?: number;
 }
 export interface ExpressionQuerySettings {
   mode?: ReducerMode;
@@ -173,6 +197,8 @@ export interface ClassicCondition {
     params: number[];
     type: EvalFunction;
   };
+  /** The severity level of a threshold condition, conditions are ordered from the most to the least severe */
+  severity?: string;
   operator?: {
     type: string;
   };